@@ -20,6 +20,7 @@ import (
 	"go.opentelemetry.io/collector/service/defaultcomponents"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/alibabacloudlogserviceexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/amqpexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/azuremonitorexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/carbonexporter"
@@ -28,6 +29,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/jaegerthrifthttpexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kinesisexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/newrelicexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sapmexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/sentryexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter"
@@ -35,24 +37,51 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/stackdriverexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/hostobserver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer/k8sobserver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/zpagescontribextension"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/cardinalityguardprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/countprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/dedupprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/geoipprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/intervalprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/logstransformprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/memorylimitercontribprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/metricstransformprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/ratelimitprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/resourcedetectionprocessor"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/routingprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/websocketprocessor"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/aerospikereceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/chronyreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/collectdreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/couchbasereceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/envoyalsreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/expvarreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filestatsreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/flinkmetricsreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/httpcheckreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/iisreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/pulsarreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/receivercreator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sapmreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/signalfxreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/simpleprometheusreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/skywalkingreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sparkreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/splunkhecreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sqlqueryreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sshcheckreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/tcpcheckreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/wavefrontreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/windowsperfcountersreceiver"
 )
 
 func components() (component.Factories, error) {
@@ -65,6 +94,7 @@ func components() (component.Factories, error) {
 	extensions := []component.ExtensionFactory{
 		k8sobserver.NewFactory(),
 		hostobserver.NewFactory(),
+		zpagescontribextension.NewFactory(),
 	}
 
 	for _, ext := range factories.Extensions {
@@ -91,6 +121,23 @@ func components() (component.Factories, error) {
 		statsdreceiver.NewFactory(),
 		awsxrayreceiver.NewFactory(),
 		splunkhecreceiver.NewFactory(),
+		windowsperfcountersreceiver.NewFactory(),
+		iisreceiver.NewFactory(),
+		expvarreceiver.NewFactory(),
+		chronyreceiver.NewFactory(),
+		filestatsreceiver.NewFactory(),
+		httpcheckreceiver.NewFactory(),
+		sshcheckreceiver.NewFactory(),
+		tcpcheckreceiver.NewFactory(),
+		sqlqueryreceiver.NewFactory(),
+		aerospikereceiver.NewFactory(),
+		couchbasereceiver.NewFactory(),
+		flinkmetricsreceiver.NewFactory(),
+		sparkreceiver.NewFactory(),
+		envoyalsreceiver.NewFactory(),
+		skywalkingreceiver.NewFactory(),
+		pulsarreceiver.NewFactory(),
+		awsecscontainermetricsreceiver.NewFactory(),
 	}
 	for _, rcv := range factories.Receivers {
 		receivers = append(receivers, rcv)
@@ -115,6 +162,9 @@ func components() (component.Factories, error) {
 		elasticexporter.NewFactory(),
 		alibabacloudlogserviceexporter.NewFactory(),
 		sentryexporter.NewFactory(),
+		pulsarexporter.NewFactory(),
+		amqpexporter.NewFactory(),
+		statsdreceiver.NewTraceContextExporterFactory(),
 	}
 	for _, exp := range factories.Exporters {
 		exporters = append(exporters, exp)
@@ -129,6 +179,15 @@ func components() (component.Factories, error) {
 		resourcedetectionprocessor.NewFactory(),
 		metricstransformprocessor.NewFactory(),
 		routingprocessor.NewFactory(),
+		websocketprocessor.NewFactory(),
+		countprocessor.NewFactory(),
+		logstransformprocessor.NewFactory(),
+		memorylimitercontribprocessor.NewFactory(),
+		ratelimitprocessor.NewFactory(),
+		dedupprocessor.NewFactory(),
+		geoipprocessor.NewFactory(),
+		intervalprocessor.NewFactory(),
+		cardinalityguardprocessor.NewFactory(),
 	}
 	for _, pr := range factories.Processors {
 		processors = append(processors, pr)
@@ -0,0 +1,244 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqpexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/Azure/go-amqp"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// The content type of the messages, OTLP export requests serialized with
+// protobuf.
+const contentType = "application/x-protobuf"
+
+// link is the sender link to the node of a signal, it is replaced by a fake
+// in the tests.
+type link interface {
+	Send(ctx context.Context, msg *amqp.Message) error
+	Close(ctx context.Context) error
+}
+
+// amqpLink is a sender link with its own connection to the broker.
+type amqpLink struct {
+	client *amqp.Client
+	sender *amqp.Sender
+}
+
+func (l *amqpLink) Send(ctx context.Context, msg *amqp.Message) error {
+	return l.sender.Send(ctx, msg)
+}
+
+func (l *amqpLink) Close(ctx context.Context) error {
+	_ = l.sender.Close(ctx)
+	return l.client.Close()
+}
+
+// amqpSender sends the messages of a signal to its AMQP node.
+type amqpSender struct {
+	endpoint string
+	opts     []amqp.ConnOption
+	address  string
+	logger   *zap.Logger
+	// dial opens the link to the node, it is replaced in the tests.
+	dial func() (link, error)
+
+	mu   sync.Mutex
+	link link
+}
+
+// newAMQPSender validates the connection options, the link being only
+// opened on start as it connects to the broker.
+func newAMQPSender(config *Config, address string, logger *zap.Logger) (*amqpSender, error) {
+	if address == "" {
+		return nil, errors.New("address not specified")
+	}
+
+	opts, err := config.connOptions()
+	if err != nil {
+		return nil, err
+	}
+	e := &amqpSender{
+		endpoint: config.Endpoint,
+		opts:     opts,
+		address:  address,
+		logger:   logger,
+	}
+	e.dial = e.dialLink
+	return e, nil
+}
+
+// connOptions returns the options of the AMQP connection for the config.
+func (c *Config) connOptions() ([]amqp.ConnOption, error) {
+	if c.Endpoint == "" {
+		return nil, errors.New("`endpoint` not specified")
+	}
+	u, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid `endpoint`: %w", err)
+	}
+
+	var opts []amqp.ConnOption
+	switch u.Scheme {
+	case "amqp":
+	case "amqps":
+		tlsCfg, err := c.TLSSetting.LoadTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		if tlsCfg != nil {
+			opts = append(opts, amqp.ConnTLSConfig(tlsCfg))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported `endpoint` scheme %q, must be amqp or amqps", u.Scheme)
+	}
+
+	switch {
+	case c.Username != "":
+		opts = append(opts, amqp.ConnSASLPlain(c.Username, c.Password))
+	case u.User == nil:
+		// The credentials of the URL are used by the client otherwise.
+		opts = append(opts, amqp.ConnSASLAnonymous())
+	}
+	return opts, nil
+}
+
+func (e *amqpSender) dialLink() (link, error) {
+	client, err := amqp.Dial(e.endpoint, e.opts...)
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	sender, err := session.NewSender(amqp.LinkTargetAddress(e.address))
+	if err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+	return &amqpLink{client: client, sender: sender}, nil
+}
+
+func (e *amqpSender) start(context.Context, component.Host) error {
+	_, err := e.getLink()
+	return err
+}
+
+func (e *amqpSender) shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.link == nil {
+		return nil
+	}
+	err := e.link.Close(ctx)
+	e.link = nil
+	return err
+}
+
+// getLink returns the link to the node, opening it if needed.
+func (e *amqpSender) getLink() (link, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.link == nil {
+		l, err := e.dial()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the link to %q: %w", e.address, err)
+		}
+		e.link = l
+	}
+	return e.link, nil
+}
+
+// resetLink closes a failed link, so that the next send opens a new one.
+func (e *amqpSender) resetLink(ctx context.Context, l link) {
+	e.mu.Lock()
+	if e.link != l {
+		// Already replaced after another send failed.
+		e.mu.Unlock()
+		return
+	}
+	e.link = nil
+	e.mu.Unlock()
+
+	if err := l.Close(ctx); err != nil {
+		e.logger.Debug("Failed to close the AMQP link", zap.String("address", e.address), zap.Error(err))
+	}
+}
+
+// send sends a message and waits for its acceptance by the broker.
+func (e *amqpSender) send(ctx context.Context, payload []byte) error {
+	l, err := e.getLink()
+	if err != nil {
+		return err
+	}
+
+	msg := amqp.NewMessage(payload)
+	msg.Properties = &amqp.MessageProperties{
+		ContentType: contentType,
+	}
+	err = l.Send(ctx, msg)
+	// The rejections of the message by the broker are returned as an
+	// *amqp.Error, any other error but the expiry of the context means the
+	// link or the connection failed.
+	var rejection *amqp.Error
+	if err != nil && !errors.As(err, &rejection) && ctx.Err() == nil {
+		e.resetLink(ctx, l)
+	}
+	return err
+}
+
+func (e *amqpSender) pushTraces(ctx context.Context, td pdata.Traces) (int, error) {
+	payload, err := marshalTraces(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
+}
+
+func (e *amqpSender) pushMetrics(ctx context.Context, md pdata.Metrics) (int, error) {
+	numReceivedTimeSeries, _ := md.MetricAndDataPointCount()
+	payload, err := marshalMetrics(md)
+	if err != nil {
+		return numReceivedTimeSeries, consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return numReceivedTimeSeries, err
+	}
+	return 0, nil
+}
+
+func (e *amqpSender) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	payload, err := marshalLogs(ld)
+	if err != nil {
+		return ld.LogRecordCount(), consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
@@ -0,0 +1,166 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqpexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Azure/go-amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+// fakeLink records the payloads of the messages sent to it.
+type fakeLink struct {
+	payloads [][]byte
+	err      error
+	closed   bool
+}
+
+func (l *fakeLink) Send(_ context.Context, msg *amqp.Message) error {
+	if l.err != nil {
+		return l.err
+	}
+	if msg.Properties.ContentType != contentType {
+		return errors.New("unexpected content type")
+	}
+	l.payloads = append(l.payloads, msg.GetData())
+	return nil
+}
+
+func (l *fakeLink) Close(context.Context) error {
+	l.closed = true
+	return nil
+}
+
+func newTestSender(t *testing.T, links ...*fakeLink) *amqpSender {
+	cfg := createDefaultConfig().(*Config)
+	e, err := newAMQPSender(cfg, cfg.TracesAddress, zap.NewNop())
+	require.NoError(t, err)
+	e.dial = func() (link, error) {
+		if len(links) == 0 {
+			return nil, errors.New("connection refused")
+		}
+		l := links[0]
+		links = links[1:]
+		return l, nil
+	}
+	return e
+}
+
+func TestPushTraces(t *testing.T) {
+	l := &fakeLink{}
+	e := newTestSender(t, l)
+	require.NoError(t, e.start(context.Background(), componenttest.NewNopHost()))
+	td := testTraces()
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalTraces(td)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, l.payloads)
+
+	l.err = &amqp.Error{Condition: amqp.ErrorNotAllowed}
+	dropped, err = e.pushTraces(context.Background(), td)
+	assert.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, dropped)
+	// The link is kept after a rejection.
+	assert.False(t, l.closed)
+
+	assert.NoError(t, e.shutdown(context.Background()))
+	assert.True(t, l.closed)
+}
+
+func TestPushMetrics(t *testing.T) {
+	l := &fakeLink{}
+	e := newTestSender(t, l)
+	md := testMetrics()
+
+	dropped, err := e.pushMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalMetrics(md)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, l.payloads)
+
+	l.err = &amqp.Error{Condition: amqp.ErrorNotAllowed}
+	dropped, err = e.pushMetrics(context.Background(), md)
+	assert.Error(t, err)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestPushLogs(t *testing.T) {
+	l := &fakeLink{}
+	e := newTestSender(t, l)
+	ld := testLogs()
+
+	dropped, err := e.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalLogs(ld)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, l.payloads)
+
+	l.err = &amqp.Error{Condition: amqp.ErrorNotAllowed}
+	dropped, err = e.pushLogs(context.Background(), ld)
+	assert.Error(t, err)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestSendReopensFailedLink(t *testing.T) {
+	failed := &fakeLink{err: amqp.ErrLinkClosed}
+	l := &fakeLink{}
+	e := newTestSender(t, failed, l)
+	td := testTraces()
+
+	_, err := e.pushTraces(context.Background(), td)
+	assert.Equal(t, amqp.ErrLinkClosed, err)
+	assert.True(t, failed.closed)
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	assert.Len(t, l.payloads, 1)
+
+	// The next link cannot be opened.
+	l.err = amqp.ErrConnClosed
+	_, err = e.pushTraces(context.Background(), td)
+	assert.Error(t, err)
+	_, err = e.pushTraces(context.Background(), td)
+	assert.EqualError(t, err, `failed to open the link to "otlp_spans": connection refused`)
+}
+
+func TestStartError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "amqp://" + testutil.GetAvailableLocalAddress(t)
+	e, err := newAMQPSender(cfg, cfg.TracesAddress, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.Error(t, e.start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, e.shutdown(context.Background()))
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	e := newTestSender(t)
+	assert.NoError(t, e.shutdown(context.Background()))
+}
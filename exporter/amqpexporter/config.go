@@ -0,0 +1,48 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqpexporter
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// Config defines configuration for the AMQP exporter.
+type Config struct {
+	configmodels.ExporterSettings  `mapstructure:",squash"`
+	exporterhelper.TimeoutSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct.
+	exporterhelper.QueueSettings   `mapstructure:"sending_queue"`
+	exporterhelper.RetrySettings   `mapstructure:"retry_on_failure"`
+
+	// The URL of the AMQP 1.0 broker, amqps:// URLs enabling TLS
+	// (default "amqp://localhost:5672")
+	Endpoint string `mapstructure:"endpoint"`
+
+	// The credentials of the client, for SASL PLAIN authentication. The
+	// client authenticates with SASL ANONYMOUS if not set.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// TLS configures the connections to amqps:// URLs.
+	TLSSetting configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// The address of the node to export the traces to (default "otlp_spans")
+	TracesAddress string `mapstructure:"traces_address"`
+	// The address of the node to export the metrics to (default "otlp_metrics")
+	MetricsAddress string `mapstructure:"metrics_address"`
+	// The address of the node to export the logs to (default "otlp_logs")
+	LogsAddress string `mapstructure:"logs_address"`
+}
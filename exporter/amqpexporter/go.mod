@@ -0,0 +1,11 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/amqpexporter
+
+go 1.14
+
+require (
+	github.com/Azure/go-amqp v0.12.7
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
+	go.uber.org/zap v1.16.0
+	google.golang.org/protobuf v1.25.0
+)
@@ -0,0 +1,83 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqpexporter
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The number of the repeated resource spans, metrics and logs field in the
+// OTLP export requests, their only field.
+const resourceFieldNumber = 1
+
+// resourceMarshaller is implemented by the OTLP resource spans, metrics and
+// logs, whose types are internal to the Collector.
+type resourceMarshaller interface {
+	Marshal() ([]byte, error)
+}
+
+// appendResource appends a resource to an OTLP export request.
+func appendResource(request []byte, resource resourceMarshaller) ([]byte, error) {
+	bts, err := resource.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	request = protowire.AppendTag(request, resourceFieldNumber, protowire.BytesType)
+	return protowire.AppendBytes(request, bts), nil
+}
+
+// marshalTraces serializes the traces as an OTLP ExportTraceServiceRequest.
+func marshalTraces(td pdata.Traces) ([]byte, error) {
+	var (
+		request []byte
+		err     error
+	)
+	for _, rs := range pdata.TracesToOtlp(td) {
+		if request, err = appendResource(request, rs); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// marshalMetrics serializes the metrics as an OTLP
+// ExportMetricsServiceRequest.
+func marshalMetrics(md pdata.Metrics) ([]byte, error) {
+	var (
+		request []byte
+		err     error
+	)
+	for _, rm := range pdata.MetricsToOtlp(md) {
+		if request, err = appendResource(request, rm); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
+
+// marshalLogs serializes the logs as an OTLP ExportLogsServiceRequest.
+func marshalLogs(ld pdata.Logs) ([]byte, error) {
+	var (
+		request []byte
+		err     error
+	)
+	for _, rl := range *ld.InternalRep().Orig {
+		if request, err = appendResource(request, rl); err != nil {
+			return nil, err
+		}
+	}
+	return request, nil
+}
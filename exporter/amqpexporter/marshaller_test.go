@@ -0,0 +1,137 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqpexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	for i, service := range []string{"frontend", "backend"} {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().InitEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(1)
+		spans.At(0).SetName("GET /api/" + service)
+		spans.At(0).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		spans.At(0).SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+	return td
+}
+
+func testMetrics() pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "frontend")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName("requests")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().InitEmpty()
+	metrics.At(0).IntSum().DataPoints().Resize(1)
+	metrics.At(0).IntSum().DataPoints().At(0).SetValue(42)
+	return md
+}
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "frontend")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	logs.At(0).SetName("access")
+	logs.At(0).Body().SetStringVal("GET /api/orders 200")
+	return ld
+}
+
+// splitRequest returns the serialized resources of an OTLP export request.
+func splitRequest(t *testing.T, request []byte) [][]byte {
+	var resources [][]byte
+	for len(request) > 0 {
+		num, typ, n := protowire.ConsumeTag(request)
+		require.True(t, n > 0)
+		require.EqualValues(t, resourceFieldNumber, num)
+		require.Equal(t, protowire.BytesType, typ)
+		request = request[n:]
+		resource, n := protowire.ConsumeBytes(request)
+		require.True(t, n > 0)
+		resources = append(resources, resource)
+		request = request[n:]
+	}
+	return resources
+}
+
+func TestMarshalTraces(t *testing.T) {
+	td := testTraces()
+	request, err := marshalTraces(td)
+	require.NoError(t, err)
+
+	resources := splitRequest(t, request)
+	got := pdata.NewTraces()
+	got.ResourceSpans().Resize(len(resources))
+	for i, rs := range pdata.TracesToOtlp(got) {
+		require.NoError(t, rs.Unmarshal(resources[i]))
+	}
+	assert.Equal(t, td, got)
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	md := testMetrics()
+	request, err := marshalMetrics(md)
+	require.NoError(t, err)
+
+	resources := splitRequest(t, request)
+	got := pdata.NewMetrics()
+	got.ResourceMetrics().Resize(len(resources))
+	for i, rm := range pdata.MetricsToOtlp(got) {
+		require.NoError(t, rm.Unmarshal(resources[i]))
+	}
+	assert.Equal(t, md, got)
+}
+
+func TestMarshalLogs(t *testing.T) {
+	ld := testLogs()
+	request, err := marshalLogs(ld)
+	require.NoError(t, err)
+
+	resources := splitRequest(t, request)
+	got := pdata.NewLogs()
+	got.ResourceLogs().Resize(len(resources))
+	for i, rl := range *got.InternalRep().Orig {
+		require.NoError(t, rl.Unmarshal(resources[i]))
+	}
+	assert.Equal(t, ld, got)
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	request, err := marshalTraces(pdata.NewTraces())
+	require.NoError(t, err)
+	assert.Empty(t, request)
+}
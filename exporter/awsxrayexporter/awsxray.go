@@ -16,6 +16,7 @@ package awsxrayexporter
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/xray"
@@ -36,13 +37,46 @@ const (
 // NewTraceExporter creates an component.TraceExporterOld that converts to an X-Ray PutTraceSegments
 // request and then posts the request to the configured region's X-Ray endpoint.
 func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn connAttr) (component.TraceExporter, error) {
+	return newTraceExporter(config, logger, component.ApplicationStartInfo{}, cn)
+}
+
+func newTraceExporter(config configmodels.Exporter, logger *zap.Logger, startInfo component.ApplicationStartInfo, cn connAttr) (component.TraceExporter, error) {
 	typeLog := zap.String("type", string(config.Type()))
 	nameLog := zap.String("name", config.Name())
+	rewriteTraceIDs, err := deterministicTraceIDs(config.(*Config))
+	if err != nil {
+		return nil, err
+	}
 	awsConfig, session, err := GetAWSConfigSession(logger, cn, config.(*Config))
 	if err != nil {
 		return nil, err
 	}
 	xrayClient := NewXRay(logger, awsConfig, session)
+	provenance := newProvenanceMetadata(config.(*Config), startInfo)
+	telemetry := newTelemetryRecorder(logger, xrayClient, config.(*Config), session)
+	spool, err := newSegmentSpool(logger, config.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	// put sends a batch of documents to X-Ray, returning the number of documents it did not process.
+	put := func(documents []*string) (int, error) {
+		input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents}
+		logger.Debug("request: " + input.String())
+		output, err := xrayClient.PutTraceSegments(&input)
+		if err != nil {
+			telemetry.recordConnectionError(err)
+		}
+		if output == nil {
+			return 0, err
+		}
+		logger.Debug("response: " + output.String())
+		unprocessed := len(output.UnprocessedTraceSegments)
+		if err == nil {
+			telemetry.recordSegmentsSent(len(documents) - unprocessed)
+			telemetry.recordSegmentsRejected(unprocessed)
+		}
+		return unprocessed, err
+	}
 	return exporterhelper.NewTraceExporter(
 		config,
 		func(ctx context.Context, td pdata.Traces) (totalDroppedSpans int, err error) {
@@ -69,45 +103,85 @@ func NewTraceExporter(config configmodels.Exporter, logger *zap.Logger, cn connA
 							continue
 						}
 
-						document, localErr := translator.MakeSegmentDocumentString(span, resource,
+						segment := translator.MakeSegment(span, resource,
 							config.(*Config).IndexedAttributes, config.(*Config).IndexAllAttributes)
+						if rewriteTraceIDs {
+							translator.RewriteTraceID(&segment, span)
+						}
+						addProvenance(&segment, provenance)
+						segmentDocuments, localErr := translator.SerializeSegmentDocuments(segment, translator.MaxSegmentDocumentSize)
 						if localErr != nil {
+							logger.Debug("Failed to serialize segment", zap.Error(localErr))
+							telemetry.recordSegmentsReceived(1)
+							telemetry.recordSegmentsRejected(1)
 							totalDroppedSpans++
 							continue
 						}
-						documents = append(documents, &document)
+						telemetry.recordSegmentsReceived(len(segmentDocuments))
+						for d := range segmentDocuments {
+							documents = append(documents, &segmentDocuments[d])
+						}
 					}
 				}
 			}
+			// spoolErr is the error of the batch spooled, if any. X-Ray being unreachable, the
+			// following batches are spooled without being sent.
+			var spoolErr error
 			for offset := 0; offset < len(documents); offset += maxSegmentsPerPut {
 				nextOffset := offset + maxSegmentsPerPut
-				if nextOffset > td.SpanCount() {
-					nextOffset = td.SpanCount()
+				if nextOffset > len(documents) {
+					nextOffset = len(documents)
 				}
-				input := xray.PutTraceSegmentsInput{TraceSegmentDocuments: documents[offset:nextOffset]}
-				logger.Debug("request: " + input.String())
-				output, localErr := xrayClient.PutTraceSegments(&input)
-				if localErr != nil {
-					err = wrapErrorIfBadRequest(&localErr) // record error
+				batch := documents[offset:nextOffset]
+				if spoolErr != nil && spool.save(batch, spoolErr) {
+					continue
 				}
-				if output != nil {
-					logger.Debug("response: " + output.String())
-					if output.UnprocessedTraceSegments != nil {
-						totalDroppedSpans += len(output.UnprocessedTraceSegments)
+				unprocessed, localErr := put(batch)
+				totalDroppedSpans += unprocessed
+				if localErr != nil {
+					if spool.save(batch, localErr) {
+						logger.Debug("Spooled the segments which could not be sent", zap.Error(localErr))
+						spoolErr = localErr
+						continue
 					}
-				}
-				if err != nil {
+					err = wrapErrorIfBadRequest(&localErr) // record error
 					break
 				}
 			}
 			return totalDroppedSpans, err
 		},
+		exporterhelper.WithStart(func(context.Context, component.Host) error {
+			telemetry.start(telemetryPeriod)
+			spool.start(config.(*Config).Spool.RetryInterval, func(documents []*string) error {
+				if unprocessed, err := put(documents); err != nil || unprocessed > 0 {
+					logger.Debug("Spooled segments not sent", zap.Int("unprocessed", unprocessed), zap.Error(err))
+					return err
+				}
+				return nil
+			})
+			return nil
+		}),
 		exporterhelper.WithShutdown(func(context.Context) error {
+			spool.stop()
+			telemetry.stop()
 			return logger.Sync()
 		}),
 	)
 }
 
+// deterministicTraceIDs returns whether the trace IDs not generated by X-Ray are rewritten by translator.RewriteTraceID.
+func deterministicTraceIDs(config *Config) (bool, error) {
+	switch config.TraceIDRewrite {
+	case "", TraceIDRewriteCurrentTime:
+		return false, nil
+	case TraceIDRewriteDeterministic:
+		return true, nil
+	default:
+		return false, fmt.Errorf("trace_id_rewrite must be either %q or %q, got %q",
+			TraceIDRewriteCurrentTime, TraceIDRewriteDeterministic, config.TraceIDRewrite)
+	}
+}
+
 func wrapErrorIfBadRequest(err *error) error {
 	_, ok := (*err).(awserr.RequestFailure)
 	if ok && (*err).(awserr.RequestFailure).StatusCode() < 500 {
@@ -18,14 +18,17 @@ import (
 	"context"
 	"encoding/binary"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/testutil"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 	"go.uber.org/zap"
 )
@@ -38,6 +41,35 @@ func TestTraceExport(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestTraceExportSpooled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "awsxray-spool")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Region = "us-east-1"
+	config.LocalMode = true
+	// nothing listens on the endpoint
+	config.Endpoint = "http://" + testutil.GetAvailableLocalAddress(t)
+	config.Spool.Directory = dir
+	mconn := new(mockConn)
+	mconn.sn, _ = getDefaultSession(zap.NewNop())
+	traceExporter, err := NewTraceExporter(config, zap.NewNop(), mconn)
+	require.NoError(t, err)
+
+	assert.NoError(t, traceExporter.ConsumeTraces(context.Background(), constructSpanData()))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestInvalidTraceIDRewrite(t *testing.T) {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.TraceIDRewrite = "random"
+	_, err := NewTraceExporter(config, zap.NewNop(), new(mockConn))
+	assert.EqualError(t, err, `trace_id_rewrite must be either "current_time" or "deterministic", got "random"`)
+}
+
 func BenchmarkForTraceExporter(b *testing.B) {
 	traceExporter := initializeTraceExporter()
 	for i := 0; i < b.N; i++ {
@@ -14,7 +14,11 @@
 
 package awsxrayexporter
 
-import "go.opentelemetry.io/collector/config/configmodels"
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
 
 // Config defines configuration for AWS X-Ray exporter.
 type Config struct {
@@ -29,6 +33,11 @@ type Config struct {
 	MaxRetries int `mapstructure:"max_retries"`
 	// Enable or disable TLS certificate verification.
 	NoVerifySSL bool `mapstructure:"no_verify_ssl"`
+	// Use the FIPS 140-2 validated endpoint of AWS X-Ray in the region, cannot be used with Endpoint.
+	UseFIPSEndpoint bool `mapstructure:"use_fips_endpoint"`
+	// Path of a PEM file of the CA certificates trusted in addition to the system ones, for proxies intercepting
+	// the TLS connections.
+	CABundle string `mapstructure:"ca_bundle"`
 	// Upload segments to AWS X-Ray through a proxy.
 	ProxyAddress string `mapstructure:"proxy_address"`
 	// Send segments to AWS X-Ray service in a specific region.
@@ -39,11 +48,68 @@ type Config struct {
 	ResourceARN string `mapstructure:"resource_arn"`
 	// IAM role to upload segments to a different account.
 	RoleARN string `mapstructure:"role_arn"`
+	// External ID passed when assuming RoleARN, if required by the trust policy of the role.
+	ExternalID string `mapstructure:"external_id"`
+	// STS endpoint used to assume RoleARN instead of the regional endpoint, e.g. a VPC endpoint.
+	STSEndpoint string `mapstructure:"sts_endpoint"`
 	// By default, OpenTelemetry attributes are converted to X-Ray metadata, which are not indexed.
 	// Specify a list of attribute names to be converted to X-Ray annotations instead, which will be indexed.
+	// The resource attributes of the listed names are converted to annotations too.
 	// See annotation vs. metadata: https://docs.aws.amazon.com/xray/latest/devguide/xray-concepts.html#xray-concepts-annotations
 	IndexedAttributes []string `mapstructure:"indexed_attributes"`
 	// Set to true to convert all OpenTelemetry attributes to X-Ray annotation (indexed) ignoring the IndexedAttributes option.
 	// Default value: false
 	IndexAllAttributes bool `mapstructure:"index_all_attributes"`
+	// How the trace IDs not generated by X-Ray, whose epoch is older than 28 days, are replaced: with the current
+	// time as epoch, TraceIDRewriteCurrentTime, or with an epoch derived from the span, TraceIDRewriteDeterministic.
+	// Default value: TraceIDRewriteCurrentTime
+	TraceIDRewrite string `mapstructure:"trace_id_rewrite"`
+	// Metadata identifying the collector which emitted the segments, added to each segment when enabled.
+	Provenance ProvenanceConfig `mapstructure:"provenance"`
+	// Telemetry records reported to the X-Ray telemetry API, as the X-Ray daemon does.
+	Telemetry TelemetryConfig `mapstructure:"telemetry"`
+	// Segments which could not be sent because of a credential or network failure, kept on disk and retried.
+	Spool SpoolConfig `mapstructure:"spool"`
+}
+
+const (
+	// TraceIDRewriteCurrentTime replaces the epoch of the trace IDs by the current time, the spans of a trace
+	// exported at different times may get different trace IDs.
+	TraceIDRewriteCurrentTime = "current_time"
+	// TraceIDRewriteDeterministic replaces the epoch of the trace IDs by the day the span started, so that the
+	// spans of a trace get the same trace ID, and keeps the original trace ID in the otel_trace_id annotation.
+	TraceIDRewriteDeterministic = "deterministic"
+)
+
+// ProvenanceConfig defines the "collector" metadata added to each segment, so that the segments can be traced
+// back to the collector instance and pipeline which emitted them.
+type ProvenanceConfig struct {
+	// Set to true to add the "collector" metadata to each segment.
+	// Default value: false
+	Enabled bool `mapstructure:"enabled"`
+	// Identifier of the collector instance, the host name by default.
+	CollectorID string `mapstructure:"collector_id"`
+	// Name of the pipeline the exporter is used in, not reported if empty.
+	Pipeline string `mapstructure:"pipeline"`
+}
+
+// TelemetryConfig defines the reports of the counts of the segments sent and of the connection errors to the
+// X-Ray telemetry API, shown in the X-Ray console for the X-Ray daemons.
+type TelemetryConfig struct {
+	// Set to true to report the telemetry records every minute.
+	// Default value: false
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// SpoolConfig defines the disk buffer of the segments which could not be sent to X-Ray because the credentials
+// were being rotated or the X-Ray endpoint was unreachable, so that they are retried instead of being dropped.
+type SpoolConfig struct {
+	// Directory of the spooled segments, created if needed. The segments are not spooled if not set.
+	Directory string `mapstructure:"directory"`
+	// Maximum size in MiB of the spooled segments, the segments failing to be sent beyond it being dropped.
+	// Default value: 100
+	MaxSizeMiB int `mapstructure:"max_size_mib"`
+	// Interval at which the spooled segments are retried.
+	// Default value: 30s
+	RetryInterval time.Duration `mapstructure:"retry_interval"`
 }
@@ -17,6 +17,7 @@ package awsxrayexporter
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,12 +53,28 @@ func TestLoadConfig(t *testing.T) {
 			RequestTimeoutSeconds: 30,
 			MaxRetries:            2,
 			NoVerifySSL:           false,
+			UseFIPSEndpoint:       true,
+			CABundle:              "testdata/ca.pem",
 			ProxyAddress:          "",
-			Region:                "eu-west-1",
+			Region:                "us-west-2",
 			LocalMode:             false,
 			ResourceARN:           "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u",
 			RoleARN:               "arn:aws:iam::123456789:role/monitoring-EKS-NodeInstanceRole",
+			ExternalID:            "monitoring-42",
+			STSEndpoint:           "https://sts.us-west-2.amazonaws.com",
 			IndexedAttributes:     []string{"indexed_attr_0", "indexed_attr_1"},
 			IndexAllAttributes:    false,
+			TraceIDRewrite:        TraceIDRewriteDeterministic,
+			Provenance: ProvenanceConfig{
+				Enabled:     true,
+				CollectorID: "collector-42",
+				Pipeline:    "traces",
+			},
+			Telemetry: TelemetryConfig{Enabled: true},
+			Spool: SpoolConfig{
+				Directory:     "/var/lib/otelcol/xray-spool",
+				MaxSizeMiB:    50,
+				RetryInterval: 30 * time.Second,
+			},
 		})
 }
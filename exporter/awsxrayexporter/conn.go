@@ -17,6 +17,7 @@ package awsxrayexporter
 
 import (
 	"crypto/tls"
+	"errors"
 	"net/http"
 	"net/url"
 	"os"
@@ -32,13 +33,27 @@ import (
 	"github.com/aws/aws-sdk-go/service/sts"
 	"go.uber.org/zap"
 	"golang.org/x/net/http2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsutil"
 )
 
 type connAttr interface {
-	newAWSSession(logger *zap.Logger, roleArn string, region string) (*session.Session, error)
+	newAWSSession(logger *zap.Logger, role assumeRole, region string) (*session.Session, error)
 	getEC2Region(s *session.Session) (string, error)
 }
 
+// assumeRole defines the IAM role assumed to upload the segments, for instance
+// to a central monitoring account.
+type assumeRole struct {
+	roleARN string
+	// externalID is required by the trust policies of the roles assumed by
+	// third parties.
+	externalID string
+	// stsEndpoint overrides the regional STS endpoint, e.g. with a VPC endpoint.
+	stsEndpoint string
+}
+
 // Conn implements connAttr interface.
 type Conn struct{}
 
@@ -55,12 +70,14 @@ const (
 
 // newHTTPClient returns new HTTP client instance with provided configuration.
 func newHTTPClient(logger *zap.Logger, maxIdle int, requestTimeout int, noVerify bool,
-	proxyAddress string) (*http.Client, error) {
+	proxyAddress string, caBundle string) (*http.Client, error) {
 	logger.Debug("Using proxy address: ",
 		zap.String("proxyAddr", proxyAddress),
 	)
-	tls := &tls.Config{
-		InsecureSkipVerify: false,
+	tls, err := awsutil.NewTLSConfig(caBundle)
+	if err != nil {
+		logger.Error("unable to load the CA bundle", zap.Error(err))
+		return nil, err
 	}
 
 	finalProxyAddress := getProxyAddress(proxyAddress)
@@ -114,9 +131,9 @@ func GetAWSConfigSession(logger *zap.Logger, cn connAttr, cfg *Config) (*aws.Con
 	var s *session.Session
 	var err error
 	var awsRegion string
-	http, err := newHTTPClient(logger, cfg.NumberOfWorkers, cfg.RequestTimeoutSeconds, cfg.NoVerifySSL, cfg.ProxyAddress)
+	http, err := newHTTPClient(logger, cfg.NumberOfWorkers, cfg.RequestTimeoutSeconds, cfg.NoVerifySSL, cfg.ProxyAddress,
+		cfg.CABundle)
 	if err != nil {
-		logger.Error("unable to obtain proxy URL", zap.Error(err))
 		return nil, nil, err
 	}
 	regionEnv := os.Getenv("AWS_REGION")
@@ -145,7 +162,17 @@ func GetAWSConfigSession(logger *zap.Logger, cn connAttr, cfg *Config) (*aws.Con
 		logger.Error(msg)
 		return nil, nil, awserr.New("NoAwsRegion", msg, nil)
 	}
-	s, err = cn.newAWSSession(logger, cfg.RoleARN, awsRegion)
+	endpoint, err := awsutil.ResolveEndpoint("xray", awsRegion, cfg.Endpoint, cfg.UseFIPSEndpoint)
+	if err != nil {
+		logger.Error("Invalid X-Ray endpoint", zap.Error(err))
+		return nil, nil, err
+	}
+	role, err := newAssumeRole(cfg, awsRegion)
+	if err != nil {
+		logger.Error("Invalid role configuration", zap.Error(err))
+		return nil, nil, err
+	}
+	s, err = cn.newAWSSession(logger, role, awsRegion)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -154,7 +181,7 @@ func GetAWSConfigSession(logger *zap.Logger, cn connAttr, cfg *Config) (*aws.Con
 		Region:                 aws.String(awsRegion),
 		DisableParamValidation: aws.Bool(true),
 		MaxRetries:             aws.Int(cfg.MaxRetries),
-		Endpoint:               aws.String(cfg.Endpoint),
+		Endpoint:               aws.String(endpoint),
 		HTTPClient:             http,
 	}
 	return config, s, nil
@@ -193,16 +220,30 @@ func ProxyServerTransport(logger *zap.Logger, config *Config) (*http.Transport,
 	return transport, nil
 }
 
-func (c *Conn) newAWSSession(logger *zap.Logger, roleArn string, region string) (*session.Session, error) {
+func newAssumeRole(cfg *Config, region string) (assumeRole, error) {
+	if cfg.RoleARN == "" {
+		if cfg.ExternalID != "" || cfg.STSEndpoint != "" {
+			return assumeRole{}, errors.New("external_id and sts_endpoint require role_arn")
+		}
+		return assumeRole{}, nil
+	}
+	stsEndpoint, err := awsutil.ResolveEndpoint("sts", region, cfg.STSEndpoint, false)
+	if err != nil {
+		return assumeRole{}, err
+	}
+	return assumeRole{roleARN: cfg.RoleARN, externalID: cfg.ExternalID, stsEndpoint: stsEndpoint}, nil
+}
+
+func (c *Conn) newAWSSession(logger *zap.Logger, role assumeRole, region string) (*session.Session, error) {
 	var s *session.Session
 	var err error
-	if roleArn == "" {
+	if role.roleARN == "" {
 		s, err = getDefaultSession(logger)
 		if err != nil {
 			return s, err
 		}
 	} else {
-		stsCreds, _ := getSTSCreds(logger, region, roleArn)
+		stsCreds, _ := getSTSCreds(logger, region, role)
 
 		s, err = session.NewSession(&aws.Config{
 			Credentials: stsCreds,
@@ -219,13 +260,13 @@ func (c *Conn) newAWSSession(logger *zap.Logger, roleArn string, region string)
 // getSTSCreds gets STS credentials from regional endpoint. ErrCodeRegionDisabledException is received if the
 // STS regional endpoint is disabled. In this case STS credentials are fetched from STS primary regional endpoint
 // in the respective AWS partition.
-func getSTSCreds(logger *zap.Logger, region string, roleArn string) (*credentials.Credentials, error) {
+func getSTSCreds(logger *zap.Logger, region string, role assumeRole) (*credentials.Credentials, error) {
 	t, err := getDefaultSession(logger)
 	if err != nil {
 		return nil, err
 	}
 
-	stsCred := getSTSCredsFromRegionEndpoint(logger, t, region, roleArn)
+	stsCred := getSTSCredsFromRegionEndpoint(logger, t, region, role)
 	// Make explicit call to fetch credentials.
 	_, err = stsCred.Get()
 	if err != nil {
@@ -234,7 +275,11 @@ func getSTSCreds(logger *zap.Logger, region string, roleArn string) (*credential
 			switch aerr.Code() {
 			case sts.ErrCodeRegionDisabledException:
 				logger.Error("Region ", zap.String("region", region), zap.String("error", aerr.Error()))
-				stsCred = getSTSCredsFromPrimaryRegionEndpoint(logger, t, roleArn, region)
+				// a custom STS endpoint is not replaced by the public endpoint
+				// of the primary region, which may not be reachable.
+				if role.stsEndpoint == "" {
+					stsCred = getSTSCredsFromPrimaryRegionEndpoint(logger, t, role, region)
+				}
 			}
 		}
 	}
@@ -245,29 +290,44 @@ func getSTSCreds(logger *zap.Logger, region string, roleArn string) (*credential
 // AWS STS recommends that you provide both the Region and endpoint when you make calls to a Regional endpoint.
 // Reference: https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_temp_enable-regions.html#id_credentials_temp_enable-regions_writing_code
 func getSTSCredsFromRegionEndpoint(logger *zap.Logger, sess *session.Session, region string,
-	roleArn string) *credentials.Credentials {
-	regionalEndpoint := getSTSRegionalEndpoint(region)
-	// if regionalEndpoint is "", the STS endpoint is Global endpoint for classic regions except ap-east-1 - (HKG)
-	// for other opt-in regions, region value will create STS regional endpoint.
-	// This will be only in the case, if provided region is not present in aws_regions.go
-	c := &aws.Config{Region: aws.String(region), Endpoint: &regionalEndpoint}
+	role assumeRole) *credentials.Credentials {
+	return credentials.NewCredentials(newAssumeRoleProvider(logger, sess, region, role))
+}
+
+// newAssumeRoleProvider returns the provider of the credentials of the role, assumed with the STS endpoint of the
+// role if set, the regional endpoint otherwise.
+func newAssumeRoleProvider(logger *zap.Logger, sess *session.Session, region string,
+	role assumeRole) *stscreds.AssumeRoleProvider {
+	stsEndpoint := role.stsEndpoint
+	if stsEndpoint == "" {
+		// if the regional endpoint is "", the STS endpoint is Global endpoint for classic regions except
+		// ap-east-1 - (HKG) for other opt-in regions, region value will create STS regional endpoint.
+		// This will be only in the case, if provided region is not present in aws_regions.go
+		stsEndpoint = getSTSRegionalEndpoint(region)
+	}
+	c := &aws.Config{Region: aws.String(region), Endpoint: &stsEndpoint}
 	st := sts.New(sess, c)
 	logger.Info("STS Endpoint ", zap.String("endpoint", st.Endpoint))
-	return stscreds.NewCredentialsWithClient(st, roleArn)
+	return &stscreds.AssumeRoleProvider{
+		Client:     st,
+		RoleARN:    role.roleARN,
+		ExternalID: awsxray.String(role.externalID),
+		Duration:   stscreds.DefaultDuration,
+	}
 }
 
 // getSTSCredsFromPrimaryRegionEndpoint fetches STS credentials for provided roleARN from primary region endpoint in
 // the respective partition.
-func getSTSCredsFromPrimaryRegionEndpoint(logger *zap.Logger, t *session.Session, roleArn string,
+func getSTSCredsFromPrimaryRegionEndpoint(logger *zap.Logger, t *session.Session, role assumeRole,
 	region string) *credentials.Credentials {
 	logger.Info("Credentials for provided RoleARN being fetched from STS primary region endpoint.")
 	partitionID := getPartition(region)
 	if partitionID == endpoints.AwsPartitionID {
-		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.UsEast1RegionID, roleArn)
+		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.UsEast1RegionID, role)
 	} else if partitionID == endpoints.AwsCnPartitionID {
-		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.CnNorth1RegionID, roleArn)
+		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.CnNorth1RegionID, role)
 	} else if partitionID == endpoints.AwsUsGovPartitionID {
-		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.UsGovWest1RegionID, roleArn)
+		return getSTSCredsFromRegionEndpoint(logger, t, endpoints.UsGovWest1RegionID, role)
 	}
 
 	return nil
@@ -16,12 +16,14 @@ package awsxrayexporter
 
 import (
 	"errors"
+	"net/http"
 	"os"
 	"path"
 	"strings"
 	"testing"
 
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -47,7 +49,7 @@ func (c *mockConn) getEC2Region(s *session.Session) (string, error) {
 	return ec2Region, nil
 }
 
-func (c *mockConn) newAWSSession(logger *zap.Logger, roleArn string, region string) (*session.Session, error) {
+func (c *mockConn) newAWSSession(logger *zap.Logger, role assumeRole, region string) (*session.Session, error) {
 	return c.sn, nil
 }
 
@@ -85,6 +87,86 @@ func TestRegionEnv(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+// use the FIPS endpoint of the region
+func TestFIPSEndpoint(t *testing.T) {
+	logger := zap.NewNop()
+	xrayExporterCfg := loadExporterConfig(t)
+	xrayExporterCfg.Region = "us-east-1"
+	xrayExporterCfg.UseFIPSEndpoint = true
+
+	m := &mockConn{}
+	m.sn, _ = session.NewSession()
+	cfg, _, err := GetAWSConfigSession(logger, m, xrayExporterCfg)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://xray-fips.us-east-1.amazonaws.com", *cfg.Endpoint)
+
+	xrayExporterCfg.Endpoint = "https://xray.internal"
+	_, _, err = GetAWSConfigSession(logger, m, xrayExporterCfg)
+	assert.Error(t, err, "a custom endpoint cannot be used with the FIPS endpoint")
+
+	xrayExporterCfg.Endpoint = ""
+	xrayExporterCfg.Region = "eu-west-1"
+	_, _, err = GetAWSConfigSession(logger, m, xrayExporterCfg)
+	assert.EqualError(t, err, `no FIPS endpoint of xray in region "eu-west-1"`)
+}
+
+func TestCABundle(t *testing.T) {
+	logger := zap.NewNop()
+	xrayExporterCfg := loadExporterConfig(t)
+	xrayExporterCfg.Region = "us-east-1"
+	xrayExporterCfg.CABundle = path.Join(".", "testdata", "ca.pem")
+
+	m := &mockConn{}
+	m.sn, _ = session.NewSession()
+	cfg, _, err := GetAWSConfigSession(logger, m, xrayExporterCfg)
+	assert.Nil(t, err)
+	transport := cfg.HTTPClient.Transport.(*http.Transport)
+	assert.NotNil(t, transport.TLSClientConfig.RootCAs)
+
+	xrayExporterCfg.CABundle = path.Join(".", "testdata", "missing.pem")
+	_, _, err = GetAWSConfigSession(logger, m, xrayExporterCfg)
+	assert.Error(t, err)
+}
+
+func TestNewAssumeRole(t *testing.T) {
+	cfg := &Config{}
+	role, err := newAssumeRole(cfg, "us-east-1")
+	assert.Nil(t, err)
+	assert.Equal(t, assumeRole{}, role)
+
+	cfg.ExternalID = "monitoring"
+	_, err = newAssumeRole(cfg, "us-east-1")
+	assert.Error(t, err, "an external ID requires a role")
+
+	cfg.RoleARN = "arn:aws:iam::123456789:role/monitoring"
+	cfg.STSEndpoint = "vpce-0123-abcd.sts.us-east-1.vpce.amazonaws.com"
+	role, err = newAssumeRole(cfg, "us-east-1")
+	assert.Nil(t, err)
+	assert.Equal(t, assumeRole{
+		roleARN:     "arn:aws:iam::123456789:role/monitoring",
+		externalID:  "monitoring",
+		stsEndpoint: "https://vpce-0123-abcd.sts.us-east-1.vpce.amazonaws.com",
+	}, role)
+}
+
+func TestAssumeRoleProvider(t *testing.T) {
+	logger := zap.NewNop()
+	sess, _ := session.NewSession()
+
+	p := newAssumeRoleProvider(logger, sess, "us-west-2", assumeRole{roleARN: "arn:aws:iam::123456789:role/monitoring"})
+	assert.Equal(t, "arn:aws:iam::123456789:role/monitoring", p.RoleARN)
+	assert.Nil(t, p.ExternalID)
+	assert.Equal(t, "https://sts.us-west-2.amazonaws.com", p.Client.(*sts.STS).Endpoint)
+
+	p = newAssumeRoleProvider(logger, sess, "us-west-2", assumeRole{
+		roleARN:     "arn:aws:iam::123456789:role/monitoring",
+		externalID:  "monitoring",
+		stsEndpoint: "https://sts.internal",
+	})
+	assert.Equal(t, "monitoring", *p.ExternalID)
+	assert.Equal(t, "https://sts.internal", p.Client.(*sts.STS).Endpoint)
+}
+
 func loadExporterConfig(t *testing.T) *Config {
 	factories, err := componenttest.ExampleComponents()
 	assert.Nil(t, err)
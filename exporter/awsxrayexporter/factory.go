@@ -16,6 +16,7 @@ package awsxrayexporter
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -51,6 +52,11 @@ func createDefaultConfig() configmodels.Exporter {
 		LocalMode:             false,
 		ResourceARN:           "",
 		RoleARN:               "",
+		TraceIDRewrite:        TraceIDRewriteCurrentTime,
+		Spool: SpoolConfig{
+			MaxSizeMiB:    100,
+			RetryInterval: 30 * time.Second,
+		},
 	}
 }
 
@@ -60,5 +66,5 @@ func createTraceExporter(
 	cfg configmodels.Exporter,
 ) (component.TraceExporter, error) {
 	eCfg := cfg.(*Config)
-	return NewTraceExporter(eCfg, params.Logger, &Conn{})
+	return newTraceExporter(eCfg, params.Logger, params.ApplicationStartInfo, &Conn{})
 }
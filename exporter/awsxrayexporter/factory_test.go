@@ -18,6 +18,7 @@ import (
 	"context"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -47,6 +48,11 @@ func TestCreateDefaultConfig(t *testing.T) {
 		LocalMode:             false,
 		ResourceARN:           "",
 		RoleARN:               "",
+		TraceIDRewrite:        TraceIDRewriteCurrentTime,
+		Spool: SpoolConfig{
+			MaxSizeMiB:    100,
+			RetryInterval: 30 * time.Second,
+		},
 	}, "failed to create default config")
 	assert.NoError(t, configcheck.ValidateConfig(cfg))
 }
@@ -5,11 +5,14 @@ go 1.14
 require (
 	github.com/aws/aws-sdk-go v1.34.22
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray v0.0.0-00010101000000-000000000000
+	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
 	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
 	go.uber.org/zap v1.16.0
-	golang.org/x/net v0.0.0-20200625001655-4c5254603344
+	golang.org/x/net v0.0.0-20200707034311-ab3426394381
 	google.golang.org/grpc/examples v0.0.0-20200728194956-1c32b02682df // indirect
 )
 
 replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray => ./../../internal/awsxray
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/internal/common => ./../../internal/common
@@ -0,0 +1,66 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"os"
+
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// provenanceNamespace is the namespace of the segment metadata identifying the collector which emitted them.
+const provenanceNamespace = "collector"
+
+// newProvenanceMetadata returns the metadata identifying the collector instance, exporter and pipeline, or
+// nil if the provenance metadata is not enabled.
+func newProvenanceMetadata(config *Config, startInfo component.ApplicationStartInfo) map[string]interface{} {
+	if !config.Provenance.Enabled {
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"exporter":           config.Name(),
+		"translator_version": translator.Version,
+	}
+	id := config.Provenance.CollectorID
+	if id == "" {
+		// The collector is identified by its host if the host name can be determined
+		id, _ = os.Hostname()
+	}
+	if id != "" {
+		metadata["id"] = id
+	}
+	if config.Provenance.Pipeline != "" {
+		metadata["pipeline"] = config.Provenance.Pipeline
+	}
+	if startInfo.Version != "" {
+		metadata["version"] = startInfo.Version
+	}
+	return metadata
+}
+
+// addProvenance adds the provenance metadata to the segment, replacing any metadata of the same namespace.
+func addProvenance(segment *awsxray.Segment, provenance map[string]interface{}) {
+	if provenance == nil {
+		return
+	}
+	if segment.Metadata == nil {
+		segment.Metadata = map[string]map[string]interface{}{}
+	}
+	segment.Metadata[provenanceNamespace] = provenance
+}
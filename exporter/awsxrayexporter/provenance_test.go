@@ -0,0 +1,84 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/awsxrayexporter/translator"
+)
+
+func TestProvenanceDisabled(t *testing.T) {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	provenance := newProvenanceMetadata(config, component.ApplicationStartInfo{Version: "0.11.0"})
+	assert.Nil(t, provenance)
+
+	segment := translator.MakeSegment(constructHTTPServerSpan(), constructResource(), nil, false)
+	addProvenance(&segment, provenance)
+	assert.NotContains(t, segment.Metadata, provenanceNamespace)
+}
+
+func TestProvenance(t *testing.T) {
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.NameVal = "awsxray/fleet"
+	config.Provenance = ProvenanceConfig{
+		Enabled:     true,
+		CollectorID: "collector-42",
+		Pipeline:    "traces/frontend",
+	}
+	provenance := newProvenanceMetadata(config, component.ApplicationStartInfo{Version: "0.11.0"})
+
+	span := constructHTTPServerSpan()
+	span.Attributes().InsertString("user_tier", "gold")
+	segment := translator.MakeSegment(span, constructResource(), nil, false)
+	addProvenance(&segment, provenance)
+	document, err := translator.SerializeSegment(segment)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Metadata map[string]map[string]interface{} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(document), &decoded))
+	assert.Equal(t, map[string]interface{}{
+		"id":                 "collector-42",
+		"exporter":           "awsxray/fleet",
+		"pipeline":           "traces/frontend",
+		"version":            "0.11.0",
+		"translator_version": translator.Version,
+	}, decoded.Metadata[provenanceNamespace])
+	// The metadata converted from the span attributes are kept
+	assert.Contains(t, decoded.Metadata, "default")
+}
+
+func TestProvenanceDefaultCollectorID(t *testing.T) {
+	hostname, err := os.Hostname()
+	require.NoError(t, err)
+
+	config := NewFactory().CreateDefaultConfig().(*Config)
+	config.Provenance.Enabled = true
+	provenance := newProvenanceMetadata(config, component.ApplicationStartInfo{})
+
+	assert.Equal(t, map[string]interface{}{
+		"id":                 hostname,
+		"exporter":           "awsxray",
+		"translator_version": translator.Version,
+	}, provenance)
+}
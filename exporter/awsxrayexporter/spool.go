@@ -0,0 +1,228 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"go.uber.org/zap"
+)
+
+const (
+	spoolFilePrefix = "segments-"
+	spoolFileSuffix = ".json"
+)
+
+// segmentSpool keeps on disk the segment documents which could not be sent to X-Ray because of a
+// credential or network failure, and retries them periodically so that they are not lost while the
+// credentials are rotated or while the X-Ray endpoint is unreachable, as the X-Ray daemon does. Each
+// batch of documents is a file of the spool directory, holding a JSON array of the documents, the
+// files being sent in the order they were spooled.
+type segmentSpool struct {
+	logger   *zap.Logger
+	dir      string
+	maxBytes int64
+
+	mu   sync.Mutex
+	size int64
+	seq  uint64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// newSegmentSpool returns a segmentSpool in the configured directory, or nil if the segments are
+// not spooled. The files left by a previous run are kept and sent once the retries start.
+func newSegmentSpool(logger *zap.Logger, config *Config) (*segmentSpool, error) {
+	if config.Spool.Directory == "" {
+		return nil, nil
+	}
+	if config.Spool.MaxSizeMiB <= 0 {
+		return nil, errors.New("spool.max_size_mib must be positive")
+	}
+	if config.Spool.RetryInterval <= 0 {
+		return nil, errors.New("spool.retry_interval must be positive")
+	}
+	if err := os.MkdirAll(config.Spool.Directory, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create the spool directory: %w", err)
+	}
+	s := &segmentSpool{
+		logger:   logger,
+		dir:      config.Spool.Directory,
+		maxBytes: int64(config.Spool.MaxSizeMiB) << 20,
+	}
+	files, err := s.files()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the spool directory: %w", err)
+	}
+	for _, file := range files {
+		s.size += file.Size()
+	}
+	return s, nil
+}
+
+// isSpoolable returns whether the documents of a failed call to X-Ray should be spooled, the
+// failure being transient.
+func isSpoolable(err error) bool {
+	if failure, ok := err.(awserr.RequestFailure); ok {
+		switch failure.Code() {
+		case "ExpiredTokenException", "ExpiredToken", "InvalidSignatureException", "UnrecognizedClientException":
+			// the credentials are being rotated
+			return true
+		}
+		return failure.StatusCode() >= 500 || failure.StatusCode() == 429
+	}
+	// the X-Ray endpoint is unreachable or the credentials cannot be retrieved
+	return true
+}
+
+// save spools the documents of a call to X-Ray which failed with err, returning whether they were
+// spooled. The documents are not spooled if the failure is not transient or when the spool is full.
+func (s *segmentSpool) save(documents []*string, err error) bool {
+	if s == nil || !isSpoolable(err) {
+		return false
+	}
+	content, err := json.Marshal(documents)
+	if err != nil {
+		s.logger.Warn("Failed to serialize the segments to spool", zap.Error(err))
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.size+int64(len(content)) > s.maxBytes {
+		s.logger.Warn("The spool is full, dropping the segments", zap.Int("segments", len(documents)))
+		return false
+	}
+	s.seq++
+	name := fmt.Sprintf("%s%020d-%06d%s", spoolFilePrefix, time.Now().UnixNano(), s.seq%1000000, spoolFileSuffix)
+	if err := writeSpoolFile(filepath.Join(s.dir, name), content); err != nil {
+		s.logger.Warn("Failed to spool the segments", zap.Int("segments", len(documents)), zap.Error(err))
+		return false
+	}
+	s.size += int64(len(content))
+	return true
+}
+
+// writeSpoolFile writes a temporary file renamed once complete, so that a partial file is never sent.
+func writeSpoolFile(path string, content []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-"+filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// files returns the spooled files, the oldest first.
+func (s *segmentSpool) files() ([]os.FileInfo, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	files := infos[:0]
+	for _, info := range infos {
+		if info.Mode().IsRegular() && strings.HasPrefix(info.Name(), spoolFilePrefix) && strings.HasSuffix(info.Name(), spoolFileSuffix) {
+			files = append(files, info)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name() < files[j].Name() })
+	return files, nil
+}
+
+// start retries the spooled documents every interval, with put, until stop is called.
+func (s *segmentSpool) start(interval time.Duration, put func(documents []*string) error) {
+	if s == nil {
+		return
+	}
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+	go func() {
+		defer close(s.doneCh)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.retry(put)
+			case <-s.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the retries, the documents still spooled being sent once the exporter starts again.
+func (s *segmentSpool) stop() {
+	if s == nil || s.stopCh == nil {
+		return
+	}
+	close(s.stopCh)
+	<-s.doneCh
+	s.stopCh = nil
+}
+
+// retry sends the spooled files in order until a call fails with a transient error, the files
+// being removed once sent or rejected by X-Ray.
+func (s *segmentSpool) retry(put func(documents []*string) error) {
+	files, err := s.files()
+	if err != nil {
+		s.logger.Warn("Failed to read the spool directory", zap.Error(err))
+		return
+	}
+	for _, file := range files {
+		path := filepath.Join(s.dir, file.Name())
+		var documents []*string
+		content, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(content, &documents)
+		}
+		if err != nil {
+			s.logger.Warn("Dropping an invalid spool file", zap.String("path", path), zap.Error(err))
+		} else if err = put(documents); err != nil {
+			if isSpoolable(err) {
+				s.logger.Debug("Failed to send the spooled segments", zap.Error(err))
+				return
+			}
+			s.logger.Warn("Dropping the spooled segments rejected by X-Ray", zap.Int("segments", len(documents)), zap.Error(err))
+		}
+		if err := os.Remove(path); err != nil {
+			s.logger.Warn("Failed to remove a spool file", zap.String("path", path), zap.Error(err))
+			return
+		}
+		s.mu.Lock()
+		s.size -= file.Size()
+		s.mu.Unlock()
+	}
+}
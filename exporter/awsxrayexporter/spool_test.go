@@ -0,0 +1,155 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var errUnreachable = awserr.New("RequestError", "send request failed", errors.New("connection refused"))
+
+func newTestSpool(t *testing.T, maxSizeMiB int) *segmentSpool {
+	dir, err := ioutil.TempDir("", "awsxray-spool")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	config := &Config{Spool: SpoolConfig{Directory: dir, MaxSizeMiB: maxSizeMiB, RetryInterval: time.Second}}
+	s, err := newSegmentSpool(zap.NewNop(), config)
+	require.NoError(t, err)
+	return s
+}
+
+func spooledFiles(t *testing.T, s *segmentSpool) []string {
+	files, err := s.files()
+	require.NoError(t, err)
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func TestSpoolDisabled(t *testing.T) {
+	s, err := newSegmentSpool(zap.NewNop(), &Config{})
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	assert.False(t, s.save(aws.StringSlice([]string{"{}"}), errUnreachable))
+	s.start(time.Millisecond, func([]*string) error { return nil })
+	s.stop()
+}
+
+func TestSpoolInvalidConfig(t *testing.T) {
+	_, err := newSegmentSpool(zap.NewNop(), &Config{Spool: SpoolConfig{Directory: "spool", RetryInterval: time.Second}})
+	assert.EqualError(t, err, "spool.max_size_mib must be positive")
+	_, err = newSegmentSpool(zap.NewNop(), &Config{Spool: SpoolConfig{Directory: "spool", MaxSizeMiB: 1}})
+	assert.EqualError(t, err, "spool.retry_interval must be positive")
+}
+
+func TestIsSpoolable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unreachable", errUnreachable, true},
+		{"no credentials", awserr.New("NoCredentialProviders", "no valid providers in chain", nil), true},
+		{"expired token", awserr.NewRequestFailure(awserr.New("ExpiredTokenException", "expired", nil), 400, "id"), true},
+		{"throttled", awserr.NewRequestFailure(awserr.New("ThrottlingException", "slow down", nil), 429, "id"), true},
+		{"unavailable", awserr.NewRequestFailure(awserr.New("ServiceUnavailable", "unavailable", nil), 503, "id"), true},
+		{"invalid request", awserr.NewRequestFailure(awserr.New("InvalidRequestException", "invalid", nil), 400, "id"), false},
+		{"access denied", awserr.NewRequestFailure(awserr.New("AccessDeniedException", "denied", nil), 403, "id"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isSpoolable(tt.err))
+		})
+	}
+}
+
+func TestSpoolSaveRetry(t *testing.T) {
+	s := newTestSpool(t, 1)
+	require.True(t, s.save(aws.StringSlice([]string{`{"id":"1"}`, `{"id":"2"}`}), errUnreachable))
+	require.True(t, s.save(aws.StringSlice([]string{`{"id":"3"}`}), errUnreachable))
+	require.Len(t, spooledFiles(t, s), 2)
+
+	var sent [][]string
+	s.retry(func(documents []*string) error {
+		sent = append(sent, aws.StringValueSlice(documents))
+		return errUnreachable
+	})
+	// the retries stop at the first transient failure
+	assert.Equal(t, [][]string{{`{"id":"1"}`, `{"id":"2"}`}}, sent)
+	assert.Len(t, spooledFiles(t, s), 2)
+
+	sent = nil
+	s.retry(func(documents []*string) error {
+		sent = append(sent, aws.StringValueSlice(documents))
+		return nil
+	})
+	assert.Equal(t, [][]string{{`{"id":"1"}`, `{"id":"2"}`}, {`{"id":"3"}`}}, sent)
+	assert.Empty(t, spooledFiles(t, s))
+	assert.Zero(t, s.size)
+}
+
+func TestSpoolNotTransient(t *testing.T) {
+	s := newTestSpool(t, 1)
+	rejected := awserr.NewRequestFailure(awserr.New("InvalidRequestException", "invalid", nil), 400, "id")
+	assert.False(t, s.save(aws.StringSlice([]string{"{}"}), rejected))
+	assert.Empty(t, spooledFiles(t, s))
+
+	// the spooled segments rejected by X-Ray are dropped
+	require.True(t, s.save(aws.StringSlice([]string{"{}"}), errUnreachable))
+	s.retry(func([]*string) error { return rejected })
+	assert.Empty(t, spooledFiles(t, s))
+}
+
+func TestSpoolFull(t *testing.T) {
+	s := newTestSpool(t, 1)
+	large := strings.Repeat("x", 600<<10)
+	require.True(t, s.save(aws.StringSlice([]string{large}), errUnreachable))
+	assert.False(t, s.save(aws.StringSlice([]string{large}), errUnreachable))
+	assert.Len(t, spooledFiles(t, s), 1)
+}
+
+func TestSpoolKeptAcrossRestarts(t *testing.T) {
+	s := newTestSpool(t, 1)
+	require.True(t, s.save(aws.StringSlice([]string{"{}"}), errUnreachable))
+	// a temporary file left by an interrupted write is ignored
+	require.NoError(t, ioutil.WriteFile(filepath.Join(s.dir, ".tmp-"+spoolFilePrefix+"1"+spoolFileSuffix), []byte("["), 0600))
+
+	restarted, err := newSegmentSpool(zap.NewNop(), &Config{Spool: SpoolConfig{Directory: s.dir, MaxSizeMiB: 1, RetryInterval: time.Second}})
+	require.NoError(t, err)
+	assert.Equal(t, s.size, restarted.size)
+
+	sent := make(chan []string, 1)
+	restarted.start(10*time.Millisecond, func(documents []*string) error {
+		sent <- aws.StringValueSlice(documents)
+		return nil
+	})
+	assert.Equal(t, []string{"{}"}, <-sent)
+	restarted.stop()
+	assert.Empty(t, spooledFiles(t, restarted))
+}
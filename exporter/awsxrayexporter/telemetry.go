@@ -0,0 +1,231 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/xray"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+const (
+	// telemetryPeriod is the period of the telemetry records, as for the X-Ray daemon.
+	telemetryPeriod = time.Minute
+	// maxTelemetryRecords is the maximum number of records kept while they cannot be sent, the older
+	// records being dropped first.
+	maxTelemetryRecords = 30
+)
+
+// telemetryRecorder counts the segments sent to X-Ray and the connection errors, and reports them
+// every period to the X-Ray telemetry API, so that the AWS-side monitoring of the X-Ray daemons
+// keeps working with the collector.
+type telemetryRecorder struct {
+	logger      *zap.Logger
+	client      XRay
+	hostname    string
+	instanceID  string
+	resourceARN string
+	// session is used to retrieve the ID of the EC2 instance, nil in local mode.
+	session *session.Session
+
+	mu      sync.Mutex
+	current *xray.TelemetryRecord
+	queue   []*xray.TelemetryRecord
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// newTelemetryRecorder returns a telemetryRecorder reporting to the client, or nil if the telemetry
+// is not enabled. The ID of the EC2 instance is reported unless in local mode, once retrieved when
+// the reports start.
+func newTelemetryRecorder(logger *zap.Logger, client XRay, config *Config, s *session.Session) *telemetryRecorder {
+	if !config.Telemetry.Enabled {
+		return nil
+	}
+	hostname, _ := os.Hostname()
+	r := &telemetryRecorder{
+		logger:      logger,
+		client:      client,
+		hostname:    hostname,
+		resourceARN: config.ResourceARN,
+		current:     newTelemetryRecord(),
+	}
+	if !config.LocalMode {
+		r.session = s
+	}
+	return r
+}
+
+func newTelemetryRecord() *xray.TelemetryRecord {
+	return &xray.TelemetryRecord{
+		SegmentsReceivedCount:  aws.Int64(0),
+		SegmentsSentCount:      aws.Int64(0),
+		SegmentsSpilloverCount: aws.Int64(0),
+		SegmentsRejectedCount:  aws.Int64(0),
+		BackendConnectionErrors: &xray.BackendConnectionErrors{
+			HTTPCode4XXCount:       aws.Int64(0),
+			HTTPCode5XXCount:       aws.Int64(0),
+			ConnectionRefusedCount: aws.Int64(0),
+			TimeoutCount:           aws.Int64(0),
+			UnknownHostCount:       aws.Int64(0),
+			OtherCount:             aws.Int64(0),
+		},
+	}
+}
+
+// start reports the telemetry records every period until stop is called.
+func (r *telemetryRecorder) start(period time.Duration) {
+	if r == nil {
+		return
+	}
+	r.stopCh = make(chan struct{})
+	r.doneCh = make(chan struct{})
+	go func() {
+		defer close(r.doneCh)
+		if r.session != nil {
+			instanceID, err := ec2metadata.New(r.session).GetMetadata("instance-id")
+			if err != nil {
+				r.logger.Debug("Unable to retrieve the EC2 instance ID for the telemetry records", zap.Error(err))
+			}
+			r.instanceID = instanceID
+		}
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				r.flush()
+			case <-r.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the reports and sends the records not sent yet.
+func (r *telemetryRecorder) stop() {
+	if r == nil {
+		return
+	}
+	if r.stopCh != nil {
+		close(r.stopCh)
+		<-r.doneCh
+		r.stopCh = nil
+	}
+	r.flush()
+}
+
+func (r *telemetryRecorder) add(count func(*xray.TelemetryRecord) *int64, n int) {
+	if r == nil || n == 0 {
+		return
+	}
+	r.mu.Lock()
+	*count(r.current) += int64(n)
+	r.mu.Unlock()
+}
+
+// recordSegmentsReceived counts the segments converted from the spans.
+func (r *telemetryRecorder) recordSegmentsReceived(n int) {
+	r.add(func(record *xray.TelemetryRecord) *int64 { return record.SegmentsReceivedCount }, n)
+}
+
+// recordSegmentsSent counts the segments accepted by X-Ray.
+func (r *telemetryRecorder) recordSegmentsSent(n int) {
+	r.add(func(record *xray.TelemetryRecord) *int64 { return record.SegmentsSentCount }, n)
+}
+
+// recordSegmentsRejected counts the segments which could not be serialized or were not processed
+// by X-Ray.
+func (r *telemetryRecorder) recordSegmentsRejected(n int) {
+	r.add(func(record *xray.TelemetryRecord) *int64 { return record.SegmentsRejectedCount }, n)
+}
+
+// recordConnectionError counts the error of a call to X-Ray by kind, as the X-Ray daemon does.
+func (r *telemetryRecorder) recordConnectionError(err error) {
+	if r == nil || err == nil {
+		return
+	}
+	r.add(func(record *xray.TelemetryRecord) *int64 {
+		counts := record.BackendConnectionErrors
+		if failure, ok := err.(awserr.RequestFailure); ok {
+			switch {
+			case failure.StatusCode() >= 500 && failure.StatusCode() < 600:
+				return counts.HTTPCode5XXCount
+			case failure.StatusCode() >= 400 && failure.StatusCode() < 500:
+				return counts.HTTPCode4XXCount
+			}
+			return counts.OtherCount
+		}
+		if IsTimeoutError(err) {
+			return counts.TimeoutCount
+		}
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == request.ErrCodeRequestError {
+			var (
+				cause  = awsErr.OrigErr()
+				dnsErr *net.DNSError
+				netErr net.Error
+			)
+			switch {
+			case errors.As(cause, &dnsErr):
+				return counts.UnknownHostCount
+			case cause != nil && strings.Contains(cause.Error(), "connection refused"):
+				return counts.ConnectionRefusedCount
+			case errors.As(cause, &netErr) && netErr.Timeout():
+				return counts.TimeoutCount
+			}
+		}
+		return counts.OtherCount
+	}, 1)
+}
+
+// flush queues the current record and sends the queued records, keeping them if they cannot be sent.
+func (r *telemetryRecorder) flush() {
+	r.mu.Lock()
+	record := r.current
+	r.current = newTelemetryRecord()
+	r.mu.Unlock()
+
+	record.Timestamp = aws.Time(time.Now())
+	r.queue = append(r.queue, record)
+	if len(r.queue) > maxTelemetryRecords {
+		r.queue = r.queue[len(r.queue)-maxTelemetryRecords:]
+	}
+
+	input := &xray.PutTelemetryRecordsInput{
+		TelemetryRecords: r.queue,
+		Hostname:         awsxray.String(r.hostname),
+		ResourceARN:      awsxray.String(r.resourceARN),
+		EC2InstanceId:    awsxray.String(r.instanceID),
+	}
+	if _, err := r.client.PutTelemetryRecords(input); err != nil {
+		r.logger.Debug("Failed to send the telemetry records", zap.Int("records", len(r.queue)), zap.Error(err))
+		return
+	}
+	r.queue = nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayexporter
+
+import (
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/xray"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockXRay struct {
+	mu           sync.Mutex
+	telemetryErr error
+	telemetry    []*xray.PutTelemetryRecordsInput
+}
+
+func (m *mockXRay) PutTraceSegments(*xray.PutTraceSegmentsInput) (*xray.PutTraceSegmentsOutput, error) {
+	return &xray.PutTraceSegmentsOutput{}, nil
+}
+
+func (m *mockXRay) PutTelemetryRecords(input *xray.PutTelemetryRecordsInput) (*xray.PutTelemetryRecordsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.telemetry = append(m.telemetry, input)
+	return &xray.PutTelemetryRecordsOutput{}, m.telemetryErr
+}
+
+func (m *mockXRay) inputs() []*xray.PutTelemetryRecordsInput {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*xray.PutTelemetryRecordsInput(nil), m.telemetry...)
+}
+
+func newTestTelemetryRecorder(client XRay) *telemetryRecorder {
+	config := &Config{ResourceARN: "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u", LocalMode: true}
+	config.Telemetry.Enabled = true
+	return newTelemetryRecorder(zap.NewNop(), client, config, nil)
+}
+
+func TestTelemetryDisabled(t *testing.T) {
+	r := newTelemetryRecorder(zap.NewNop(), &mockXRay{}, &Config{}, nil)
+	assert.Nil(t, r)
+	// a disabled recorder can be used
+	r.recordSegmentsReceived(1)
+	r.recordConnectionError(errors.New("error"))
+	r.start(time.Millisecond)
+	r.stop()
+}
+
+func TestTelemetryRecords(t *testing.T) {
+	client := &mockXRay{}
+	r := newTestTelemetryRecorder(client)
+	r.recordSegmentsReceived(10)
+	r.recordSegmentsSent(7)
+	r.recordSegmentsRejected(2)
+	r.recordSegmentsRejected(1)
+	r.recordConnectionError(awserr.NewRequestFailure(awserr.New("ThrottlingException", "rate exceeded", nil), 429, "id"))
+	r.flush()
+
+	inputs := client.inputs()
+	require.Len(t, inputs, 1)
+	assert.Equal(t, "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u", *inputs[0].ResourceARN)
+	assert.Nil(t, inputs[0].EC2InstanceId)
+	require.Len(t, inputs[0].TelemetryRecords, 1)
+	record := inputs[0].TelemetryRecords[0]
+	assert.NotNil(t, record.Timestamp)
+	assert.Equal(t, int64(10), *record.SegmentsReceivedCount)
+	assert.Equal(t, int64(7), *record.SegmentsSentCount)
+	assert.Equal(t, int64(3), *record.SegmentsRejectedCount)
+	assert.Equal(t, int64(0), *record.SegmentsSpilloverCount)
+	assert.Equal(t, int64(1), *record.BackendConnectionErrors.HTTPCode4XXCount)
+
+	// the counts restart from zero
+	r.flush()
+	inputs = client.inputs()
+	require.Len(t, inputs, 2)
+	assert.Equal(t, int64(0), *inputs[1].TelemetryRecords[0].SegmentsReceivedCount)
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestTelemetryConnectionErrors(t *testing.T) {
+	requestError := func(err error) error {
+		return awserr.New(request.ErrCodeRequestError, "send request failed", err)
+	}
+	tests := []struct {
+		name  string
+		err   error
+		count func(*xray.BackendConnectionErrors) *int64
+	}{
+		{"5xx", awserr.NewRequestFailure(awserr.New("ServiceUnavailable", "", nil), 503, "id"), func(e *xray.BackendConnectionErrors) *int64 { return e.HTTPCode5XXCount }},
+		{"4xx", awserr.NewRequestFailure(awserr.New("AccessDenied", "", nil), 403, "id"), func(e *xray.BackendConnectionErrors) *int64 { return e.HTTPCode4XXCount }},
+		{"unknown host", requestError(&net.DNSError{Err: "no such host", Name: "xray.invalid", IsNotFound: true}), func(e *xray.BackendConnectionErrors) *int64 { return e.UnknownHostCount }},
+		{"connection refused", requestError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}), func(e *xray.BackendConnectionErrors) *int64 { return e.ConnectionRefusedCount }},
+		{"request canceled", requestError(errors.New("net/http: request canceled")), func(e *xray.BackendConnectionErrors) *int64 { return e.TimeoutCount }},
+		{"dial timeout", requestError(&net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}), func(e *xray.BackendConnectionErrors) *int64 { return e.TimeoutCount }},
+		{"other", errors.New("unexpected"), func(e *xray.BackendConnectionErrors) *int64 { return e.OtherCount }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mockXRay{}
+			r := newTestTelemetryRecorder(client)
+			r.recordConnectionError(tt.err)
+			r.flush()
+			errs := client.inputs()[0].TelemetryRecords[0].BackendConnectionErrors
+			assert.Equal(t, int64(1), *tt.count(errs))
+			total := *errs.HTTPCode4XXCount + *errs.HTTPCode5XXCount + *errs.ConnectionRefusedCount +
+				*errs.TimeoutCount + *errs.UnknownHostCount + *errs.OtherCount
+			assert.Equal(t, int64(1), total)
+		})
+	}
+}
+
+func TestTelemetryRecordsKeptOnError(t *testing.T) {
+	client := &mockXRay{telemetryErr: errors.New("unavailable")}
+	r := newTestTelemetryRecorder(client)
+	for i := 0; i < maxTelemetryRecords+5; i++ {
+		r.recordSegmentsReceived(i + 1)
+		r.flush()
+	}
+	inputs := client.inputs()
+	require.Len(t, inputs, maxTelemetryRecords+5)
+	last := inputs[len(inputs)-1].TelemetryRecords
+	require.Len(t, last, maxTelemetryRecords)
+	// the oldest records are dropped first
+	assert.Equal(t, int64(6), *last[0].SegmentsReceivedCount)
+	assert.Equal(t, int64(maxTelemetryRecords+5), *last[maxTelemetryRecords-1].SegmentsReceivedCount)
+
+	client.mu.Lock()
+	client.telemetryErr = nil
+	client.mu.Unlock()
+	r.flush()
+	r.flush()
+	inputs = client.inputs()
+	assert.Len(t, inputs[len(inputs)-2].TelemetryRecords, maxTelemetryRecords)
+	assert.Len(t, inputs[len(inputs)-1].TelemetryRecords, 1)
+}
+
+func TestTelemetryStartStop(t *testing.T) {
+	client := &mockXRay{}
+	r := newTestTelemetryRecorder(client)
+	r.start(10 * time.Millisecond)
+	assert.Eventually(t, func() bool { return len(client.inputs()) > 0 }, time.Second, 10*time.Millisecond)
+
+	r.recordSegmentsSent(3)
+	r.stop()
+	inputs := client.inputs()
+	// the records are sent when stopped
+	assert.Equal(t, int64(3), *inputs[len(inputs)-1].TelemetryRecords[0].SegmentsSentCount)
+	assert.Equal(t, aws.StringValue(inputs[0].ResourceARN), "arn:aws:ec2:us-east1:123456789:instance/i-293hiuhe0u")
+}
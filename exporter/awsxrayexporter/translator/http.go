@@ -93,7 +93,7 @@ func makeHTTP(span pdata.Span) (map[string]string, *awsxray.HTTPData) {
 			}
 			urlParts[key] = value.StringVal()
 		default:
-			filtered[key] = value.StringVal()
+			filtered[key] = tracetranslator.AttributeValueToString(value, false)
 		}
 	})
 
@@ -21,7 +21,6 @@ import (
 	"math/rand"
 	"net/url"
 	"regexp"
-	"strings"
 	"time"
 
 	awsP "github.com/aws/aws-sdk-go/aws"
@@ -29,6 +28,7 @@ import (
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	awsxraytranslator "github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray/translator"
 )
 
 // AWS X-Ray acceptable values for origin field.
@@ -55,6 +55,13 @@ const (
 	maxSegmentNameLength = 200
 )
 
+// Version identifies the conversion of the spans to segments, it is increased whenever the mapping changes.
+const Version = "4"
+
+// OriginalTraceIDAnnotation is the annotation set by RewriteTraceID to the trace ID of the span, in 32 hexadecimal
+// digits, so that the segments can be searched by their OpenTelemetry trace ID.
+const OriginalTraceIDAnnotation = "otel_trace_id"
+
 const (
 	traceIDLength    = 35 // fixed length of aws trace id
 	identifierOffset = 11 // offset of identifier within traceID
@@ -66,7 +73,11 @@ var (
 
 // MakeSegmentDocumentString converts an OpenTelemetry Span to an X-Ray Segment and then serialzies to JSON
 func MakeSegmentDocumentString(span pdata.Span, resource pdata.Resource, indexedAttrs []string, indexAllAttrs bool) (string, error) {
-	segment := MakeSegment(span, resource, indexedAttrs, indexAllAttrs)
+	return SerializeSegment(MakeSegment(span, resource, indexedAttrs, indexAllAttrs))
+}
+
+// SerializeSegment serializes an X-Ray Segment to JSON
+func SerializeSegment(segment awsxray.Segment) (string, error) {
 	w := writers.borrow()
 	if err := w.Encode(segment); err != nil {
 		return "", err
@@ -86,10 +97,10 @@ func MakeSegment(span pdata.Span, resource pdata.Resource, indexedAttrs []string
 		isError, isFault, causefiltered, cause = makeCause(span, httpfiltered, resource)
 		isThrottled                            = !span.Status().IsNil() && span.Status().Code() == pdata.StatusCodeResourceExhausted
 		origin                                 = determineAwsOrigin(resource)
-		awsfiltered, aws                       = makeAws(causefiltered, resource)
+		awsfiltered, aws                       = awsxraytranslator.MakeAWS(causefiltered, resource)
 		service                                = makeService(resource)
-		sqlfiltered, sql                       = makeSQL(awsfiltered)
-		user, annotations, metadata            = makeXRayAttributes(sqlfiltered, indexedAttrs, indexAllAttrs)
+		sqlfiltered, sql                       = awsxraytranslator.MakeSQL(awsfiltered)
+		user, annotations, metadata            = makeXRayAttributes(sqlfiltered, span.Attributes(), resource, indexedAttrs, indexAllAttrs)
 		name                                   string
 		namespace                              string
 		segmentType                            string
@@ -239,20 +250,9 @@ func determineAwsOrigin(resource pdata.Resource) string {
 //    or 58406520 in hexadecimal.
 //  * A 96-bit identifier for the trace, globally unique, in 24 hexadecimal digits.
 func convertToAmazonTraceID(traceID pdata.TraceID) string {
-	const (
-		// maxAge of 28 days.  AWS has a 30 day limit, let's be conservative rather than
-		// hit the limit
-		maxAge = 60 * 60 * 24 * 28
-
-		// maxSkew allows for 5m of clock skew
-		maxSkew = 60 * 5
-	)
-
 	var (
-		content  = [traceIDLength]byte{}
 		epochNow = time.Now().Unix()
 		epoch    = int64(binary.BigEndian.Uint32(traceID[0:4]))
-		b        = [4]byte{}
 	)
 
 	// If AWS traceID originally came from AWS, no problem.  However, if oc generated
@@ -261,9 +261,32 @@ func convertToAmazonTraceID(traceID pdata.TraceID) string {
 	//
 	// In that case, we use the current time as the epoch and accept that a new span
 	// may be created
-	if delta := epochNow - epoch; delta > maxAge || delta < -maxSkew {
+	if !isValidEpoch(epoch, epochNow) {
 		epoch = epochNow
 	}
+	return formatAmazonTraceID(epoch, traceID)
+}
+
+// isValidEpoch returns whether the epoch of a trace ID is in the range accepted by X-Ray.
+func isValidEpoch(epoch, epochNow int64) bool {
+	const (
+		// maxAge of 28 days.  AWS has a 30 day limit, let's be conservative rather than
+		// hit the limit
+		maxAge = 60 * 60 * 24 * 28
+
+		// maxSkew allows for 5m of clock skew
+		maxSkew = 60 * 5
+	)
+	delta := epochNow - epoch
+	return delta <= maxAge && delta >= -maxSkew
+}
+
+// formatAmazonTraceID formats a trace ID of the given epoch and of the 96-bit identifier of traceID.
+func formatAmazonTraceID(epoch int64, traceID pdata.TraceID) string {
+	var (
+		content = [traceIDLength]byte{}
+		b       = [4]byte{}
+	)
 
 	binary.BigEndian.PutUint32(b[0:4], uint32(epoch))
 
@@ -276,6 +299,34 @@ func convertToAmazonTraceID(traceID pdata.TraceID) string {
 	return string(content[0:traceIDLength])
 }
 
+// RewriteTraceID replaces the trace ID of a segment made from a span whose trace ID was not generated by X-Ray,
+// its epoch being outside the accepted range, by a trace ID derived from the span only. The epoch is the start
+// of the day the span started, or of the current day if the span is too old, so that all the spans of a trace
+// get the same trace ID whenever they are exported, unlike with the current time used by MakeSegment. The
+// original trace ID is kept in the OriginalTraceIDAnnotation annotation.
+func RewriteTraceID(segment *awsxray.Segment, span pdata.Span) {
+	const day = 60 * 60 * 24
+
+	var (
+		traceID  = span.TraceID()
+		epochNow = time.Now().Unix()
+	)
+	if len(traceID) != 16 || isValidEpoch(int64(binary.BigEndian.Uint32(traceID[0:4])), epochNow) {
+		return
+	}
+
+	epoch := int64(span.StartTime()) / int64(time.Second)
+	epoch -= epoch % day
+	if !isValidEpoch(epoch, epochNow) {
+		epoch = epochNow - epochNow%day
+	}
+	segment.TraceID = awsxray.String(formatAmazonTraceID(epoch, traceID))
+	if segment.Annotations == nil {
+		segment.Annotations = map[string]interface{}{}
+	}
+	segment.Annotations[OriginalTraceIDAnnotation] = hex.EncodeToString(traceID)
+}
+
 // convertToAmazonSpanID generates an Amazon spanID from a trace.SpanID - a 64-bit identifier
 // for the Segment, unique among segments in the same trace, in 16 hexadecimal digits.
 func convertToAmazonSpanID(v []byte) string {
@@ -289,7 +340,13 @@ func timestampToFloatSeconds(ts pdata.TimestampUnixNano) float64 {
 	return float64(ts) / float64(time.Second)
 }
 
-func makeXRayAttributes(attributes map[string]string, indexedAttrs []string, indexAllAttrs bool) (
+// makeXRayAttributes converts the span attributes left by the other conversions
+// to the user, annotations and metadata of the segment. The values are taken
+// from spanAttrs so that the annotations keep the type of the attributes. The
+// resource attributes listed in indexedAttrs are added to the annotations too,
+// unless the span has an attribute of the same name.
+func makeXRayAttributes(attributes map[string]string, spanAttrs pdata.AttributeMap, resource pdata.Resource,
+	indexedAttrs []string, indexAllAttrs bool) (
 	string, map[string]interface{}, map[string]map[string]interface{}) {
 	var (
 		annotations = map[string]interface{}{}
@@ -303,14 +360,43 @@ func makeXRayAttributes(attributes map[string]string, indexedAttrs []string, ind
 		delete(attributes, semconventions.AttributeEnduserID)
 	}
 
+	for _, name := range indexedAttrs {
+		if _, ok := spanAttrs.Get(name); ok || resource.IsNil() {
+			continue
+		}
+		if v, ok := resource.Attributes().Get(name); ok {
+			annotations[awsxraytranslator.FixAnnotationKey(name)] = awsxraytranslator.AnnotationValue(v)
+		}
+	}
+
 	if len(attributes) == 0 {
-		return user, nil, nil
+		if len(annotations) == 0 {
+			return user, nil, nil
+		}
+		return user, annotations, nil
+	}
+
+	value := func(key string) interface{} {
+		if v, ok := spanAttrs.Get(key); ok {
+			return awsxraytranslator.AnnotationValue(v)
+		}
+		return attributes[key]
+	}
+
+	// the metadata namespaces of segments received by the awsxray receiver
+	// are restored as they were.
+	for key := range attributes {
+		if v, ok := spanAttrs.Get(key); ok {
+			if namespace, meta, ok := awsxraytranslator.MakeMetadata(key, v); ok {
+				metadata[namespace] = meta
+				delete(attributes, key)
+			}
+		}
 	}
 
 	if indexAllAttrs {
-		for key, value := range attributes {
-			key = fixAnnotationKey(key)
-			annotations[key] = value
+		for key := range attributes {
+			annotations[awsxraytranslator.FixAnnotationKey(key)] = value(key)
 		}
 	} else {
 		defaultMetadata := map[string]interface{}{}
@@ -319,12 +405,11 @@ func makeXRayAttributes(attributes map[string]string, indexedAttrs []string, ind
 			indexedKeys[name] = true
 		}
 
-		for key, value := range attributes {
+		for key := range attributes {
 			if _, ok := indexedKeys[key]; ok {
-				key = fixAnnotationKey(key)
-				annotations[key] = value
+				annotations[awsxraytranslator.FixAnnotationKey(key)] = value(key)
 			} else {
-				defaultMetadata[key] = value
+				defaultMetadata[key] = value(key)
 			}
 		}
 		if len(defaultMetadata) > 0 {
@@ -352,21 +437,3 @@ func fixSegmentName(name string) string {
 
 	return name
 }
-
-// fixAnnotationKey removes any invalid characters from the annotaiton key.  AWS X-Ray defines
-// the list of valid characters here:
-// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
-func fixAnnotationKey(key string) string {
-	return strings.Map(func(r rune) rune {
-		switch {
-		case '0' <= r && r <= '9':
-			fallthrough
-		case 'A' <= r && r <= 'Z':
-			fallthrough
-		case 'a' <= r && r <= 'z':
-			return r
-		default:
-			return '_'
-		}
-	}, key)
-}
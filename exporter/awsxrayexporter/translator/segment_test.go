@@ -299,6 +299,58 @@ func TestSpanWithExpiredTraceId(t *testing.T) {
 	assert.GreaterOrEqual(t, CurEpoch, PrevEpoch)
 }
 
+func TestRewriteTraceID(t *testing.T) {
+	span := constructServerSpan(nil, "GET /widgets", 0, "OK", nil)
+	traceID := []byte(span.TraceID())
+	traceID[0] = 0x11
+	span.SetTraceID(traceID)
+	segment := MakeSegment(span, constructDefaultResource(), nil, false)
+
+	RewriteTraceID(&segment, span)
+
+	startEpoch := span.StartTime() / pdata.TimestampUnixNano(time.Second)
+	dayEpoch := uint32(startEpoch - startEpoch%(60*60*24))
+	binaryEpoch := make([]byte, 4)
+	binary.BigEndian.PutUint32(binaryEpoch, dayEpoch)
+	assert.Equal(t, "1-"+hex.EncodeToString(binaryEpoch)+"-"+hex.EncodeToString(traceID[4:]), *segment.TraceID)
+	assert.Equal(t, hex.EncodeToString(traceID), segment.Annotations[OriginalTraceIDAnnotation])
+
+	// the other spans of the trace get the same trace ID
+	other := constructClientSpan(span.SpanID(), "GET", 0, "OK", nil)
+	other.SetTraceID(traceID)
+	other.SetStartTime(span.StartTime() + pdata.TimestampUnixNano(time.Millisecond))
+	otherSegment := MakeSegment(other, constructDefaultResource(), nil, false)
+	RewriteTraceID(&otherSegment, other)
+	assert.Equal(t, *segment.TraceID, *otherSegment.TraceID)
+}
+
+func TestRewriteTraceIDOldSpan(t *testing.T) {
+	span := constructServerSpan(nil, "GET /widgets", 0, "OK", nil)
+	traceID := []byte(span.TraceID())
+	traceID[0] = 0x11
+	span.SetTraceID(traceID)
+	span.SetStartTime(pdata.TimestampUnixNano(time.Now().Add(-60 * 24 * time.Hour).UnixNano()))
+	segment := MakeSegment(span, constructDefaultResource(), nil, false)
+
+	RewriteTraceID(&segment, span)
+
+	binaryEpoch, err := hex.DecodeString((*segment.TraceID)[2:10])
+	assert.NoError(t, err)
+	now := time.Now().Unix()
+	assert.Equal(t, now-now%(60*60*24), int64(binary.BigEndian.Uint32(binaryEpoch)))
+}
+
+func TestRewriteTraceIDKeepsXRayTraceID(t *testing.T) {
+	span := constructServerSpan(nil, "GET /widgets", 0, "OK", nil)
+	segment := MakeSegment(span, constructDefaultResource(), nil, false)
+	traceID := *segment.TraceID
+
+	RewriteTraceID(&segment, span)
+
+	assert.Equal(t, traceID, *segment.TraceID)
+	assert.NotContains(t, segment.Annotations, OriginalTraceIDAnnotation)
+}
+
 func TestFixSegmentName(t *testing.T) {
 	validName := "EP @ test_15.testing-d\u00F6main.org#GO"
 	fixedName := fixSegmentName(validName)
@@ -311,15 +363,6 @@ func TestFixSegmentName(t *testing.T) {
 	assert.Equal(t, defaultSegmentName, fixedName)
 }
 
-func TestFixAnnotationKey(t *testing.T) {
-	validKey := "Key_1"
-	fixedKey := fixAnnotationKey(validKey)
-	assert.Equal(t, validKey, fixedKey)
-	invalidKey := "Key@1"
-	fixedKey = fixAnnotationKey(invalidKey)
-	assert.Equal(t, "Key_1", fixedKey)
-}
-
 func TestServerSpanWithNilAttributes(t *testing.T) {
 	spanName := "/api/locations"
 	parentSpanID := newSegmentID()
@@ -392,6 +435,56 @@ func TestSpanWithAttributesAllIndexed(t *testing.T) {
 	assert.Equal(t, 0, len(segment.Metadata["default"]))
 }
 
+func TestSpanWithTypedAttributes(t *testing.T) {
+	spanName := "/api/locations"
+	parentSpanID := newSegmentID()
+	attributes := make(map[string]interface{})
+	attributes["attr1@1"] = int64(42)
+	attributes["attr2@2"] = "val2"
+	attributes["attr3"] = int64(7)
+	resource := constructDefaultResource()
+	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
+
+	segment := MakeSegment(span, resource, []string{"attr1@1", "attr2@2"}, false)
+
+	assert.Equal(t, int64(42), segment.Annotations["attr1_1"])
+	assert.Equal(t, "val2", segment.Annotations["attr2_2"])
+	assert.Equal(t, int64(7), segment.Metadata["default"]["attr3"])
+}
+
+func TestSpanWithMetadataNamespaceAttributes(t *testing.T) {
+	spanName := "/api/locations"
+	parentSpanID := newSegmentID()
+	attributes := make(map[string]interface{})
+	attributes[awsxray.AWSXraySegmentMetadataAttributePrefix+"orders"] = `{"id":"o-1","items":2}`
+	attributes["attr1"] = "val1"
+	resource := constructDefaultResource()
+	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
+
+	segment := MakeSegment(span, resource, nil, true)
+
+	assert.Equal(t, map[string]interface{}{"id": "o-1", "items": float64(2)}, segment.Metadata["orders"])
+	assert.Equal(t, 1, len(segment.Annotations))
+	assert.Equal(t, "val1", segment.Annotations["attr1"])
+}
+
+func TestSpanWithIndexedResourceAttributes(t *testing.T) {
+	spanName := "/api/locations"
+	parentSpanID := newSegmentID()
+	attributes := make(map[string]interface{})
+	attributes["attr1@1"] = "val1"
+	attributes[semconventions.AttributeK8sNamespace] = "staging"
+	resource := constructDefaultResource()
+	span := constructServerSpan(parentSpanID, spanName, tracetranslator.OCInternal, "OK", attributes)
+
+	segment := MakeSegment(span, resource, []string{semconventions.AttributeK8sCluster, semconventions.AttributeK8sNamespace, "not_exist"}, false)
+
+	assert.Equal(t, 2, len(segment.Annotations))
+	assert.Equal(t, "production", segment.Annotations["k8s_cluster_name"])
+	assert.Equal(t, "staging", segment.Annotations["k8s_namespace_name"])
+	assert.Equal(t, "val1", segment.Metadata["default"]["attr1@1"])
+}
+
 func constructClientSpan(parentSpanID []byte, name string, code int32, message string, attributes map[string]interface{}) pdata.Span {
 	var (
 		traceID        = newTraceID()
@@ -0,0 +1,319 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+const (
+	// MaxSegmentDocumentSize is the maximum size in bytes of the segment
+	// documents accepted by X-Ray, larger documents are rejected.
+	MaxSegmentDocumentSize = 64 * 1024
+
+	// TruncatedAnnotation is the annotation set to true on the segments whose
+	// fields were truncated to fit in a document.
+	TruncatedAnnotation = "otel_truncated"
+
+	// maxTruncatedStringLength is the length in bytes the long strings of
+	// the oversized segments are truncated to.
+	maxTruncatedStringLength = 1024
+
+	// metadataFieldSize is the size of `,"metadata":{}`.
+	metadataFieldSize = 14
+)
+
+// SerializeSegmentDocuments serializes an X-Ray Segment to one or more JSON
+// documents of at most maxSize bytes, the segment first. The metadata of an
+// oversized segment are moved to subsegments of the segment, each holding as
+// much of the metadata as fits in a document. If the segment is still too large
+// without metadata, the stacks of its exceptions are dropped, then its long
+// strings truncated and its exceptions but the first dropped. The
+// TruncatedAnnotation annotation is set on the segments truncated or whose
+// metadata values too large for a document are dropped.
+func SerializeSegmentDocuments(segment awsxray.Segment, maxSize int) ([]string, error) {
+	document, err := SerializeSegment(segment)
+	if err != nil {
+		return nil, err
+	}
+	if len(document) <= maxSize {
+		return []string{document}, nil
+	}
+
+	metadata := segment.Metadata
+	segment.Metadata = nil
+	var subsegments []string
+	if len(metadata) > 0 {
+		var truncated bool
+		subsegments, truncated, err = serializeMetadataSubsegments(&segment, metadata, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		if truncated {
+			markTruncated(&segment)
+		}
+	}
+
+	document, err = serializeTruncatedSegment(segment, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{document}, subsegments...), nil
+}
+
+type metadataEntry struct {
+	namespace string
+	key       string
+	value     json.RawMessage
+	// size is the size of the entry in a metadata namespace, `"key":value,`.
+	size int
+}
+
+// serializeMetadataSubsegments packs the metadata in as few subsegments of the
+// segment as possible, in the order of their namespaces and keys. It reports
+// whether the values too large for a subsegment were dropped.
+func serializeMetadataSubsegments(segment *awsxray.Segment, metadata map[string]map[string]interface{}, maxSize int) ([]string, bool, error) {
+	entries := make([]metadataEntry, 0, len(metadata))
+	for namespace, values := range metadata {
+		for key, value := range values {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, false, err
+			}
+			quotedKey, _ := json.Marshal(key)
+			entries = append(entries, metadataEntry{
+				namespace: namespace,
+				key:       key,
+				value:     raw,
+				size:      len(quotedKey) + 1 + len(raw) + 1,
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].namespace != entries[j].namespace {
+			return entries[i].namespace < entries[j].namespace
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	var (
+		documents []string
+		truncated bool
+		chunk     map[string]map[string]interface{}
+		free      int
+	)
+	empty, err := SerializeSegment(newMetadataSubsegment(segment, nil))
+	if err != nil {
+		return nil, false, err
+	}
+	capacity := maxSize - len(empty) - metadataFieldSize
+	flush := func() error {
+		if chunk == nil {
+			return nil
+		}
+		document, err := SerializeSegment(newMetadataSubsegment(segment, chunk))
+		if err != nil {
+			return err
+		}
+		documents = append(documents, document)
+		chunk = nil
+		return nil
+	}
+	for _, e := range entries {
+		// the size of the namespace is counted with its first entry
+		// of each subsegment, `"namespace":{},`.
+		quotedNamespace, _ := json.Marshal(e.namespace)
+		namespaceSize := len(quotedNamespace) + 4
+		if e.size+namespaceSize > capacity {
+			truncated = true
+			continue
+		}
+		size := e.size
+		if _, ok := chunk[e.namespace]; !ok {
+			size += namespaceSize
+		}
+		if chunk != nil && size > free {
+			if err := flush(); err != nil {
+				return nil, false, err
+			}
+			size = e.size + namespaceSize
+		}
+		if chunk == nil {
+			chunk = map[string]map[string]interface{}{}
+			free = capacity
+		}
+		if chunk[e.namespace] == nil {
+			chunk[e.namespace] = map[string]interface{}{}
+		}
+		chunk[e.namespace][e.key] = e.value
+		free -= size
+	}
+	if err := flush(); err != nil {
+		return nil, false, err
+	}
+	return documents, truncated, nil
+}
+
+// newMetadataSubsegment returns a subsegment of the segment, over the same
+// period, holding the metadata.
+func newMetadataSubsegment(segment *awsxray.Segment, metadata map[string]map[string]interface{}) awsxray.Segment {
+	return awsxray.Segment{
+		Name:      segment.Name,
+		ID:        awsxray.String(convertToAmazonSpanID(newSegmentID())),
+		TraceID:   segment.TraceID,
+		StartTime: segment.StartTime,
+		EndTime:   segment.EndTime,
+		ParentID:  segment.ID,
+		Type:      awsxray.String("subsegment"),
+		Metadata:  metadata,
+	}
+}
+
+// serializeTruncatedSegment truncates the fields of the segment until it fits
+// in a document of maxSize bytes.
+func serializeTruncatedSegment(segment awsxray.Segment, maxSize int) (string, error) {
+	truncations := []func(*awsxray.Segment){
+		dropExceptionStacks,
+		truncateStrings,
+		dropExceptions,
+	}
+	for i := 0; ; i++ {
+		document, err := SerializeSegment(segment)
+		if err != nil {
+			return "", err
+		}
+		if len(document) <= maxSize {
+			return document, nil
+		}
+		if i == len(truncations) {
+			return "", fmt.Errorf("segment document of %d bytes exceeds %d bytes once truncated", len(document), maxSize)
+		}
+		copyTruncatedFields(&segment)
+		truncations[i](&segment)
+		markTruncated(&segment)
+	}
+}
+
+// copyTruncatedFields copies the fields of the segment modified by the
+// truncations, which are shared with the segment of the caller.
+func copyTruncatedFields(segment *awsxray.Segment) {
+	if segment.Cause != nil {
+		cause := *segment.Cause
+		cause.Exceptions = append([]awsxray.Exception(nil), cause.Exceptions...)
+		segment.Cause = &cause
+	}
+	if segment.SQL != nil {
+		sql := *segment.SQL
+		segment.SQL = &sql
+	}
+	if segment.HTTP != nil && segment.HTTP.Request != nil {
+		http := *segment.HTTP
+		request := *http.Request
+		http.Request = &request
+		segment.HTTP = &http
+	}
+	annotations := make(map[string]interface{}, len(segment.Annotations)+1)
+	for k, v := range segment.Annotations {
+		annotations[k] = v
+	}
+	segment.Annotations = annotations
+}
+
+func markTruncated(segment *awsxray.Segment) {
+	if _, ok := segment.Annotations[TruncatedAnnotation]; ok {
+		return
+	}
+	annotations := make(map[string]interface{}, len(segment.Annotations)+1)
+	for k, v := range segment.Annotations {
+		annotations[k] = v
+	}
+	annotations[TruncatedAnnotation] = true
+	segment.Annotations = annotations
+}
+
+// dropExceptionStacks drops the stack frames of the exceptions, counting them
+// as truncated.
+func dropExceptionStacks(segment *awsxray.Segment) {
+	if segment.Cause == nil {
+		return
+	}
+	for i := range segment.Cause.Exceptions {
+		exception := &segment.Cause.Exceptions[i]
+		if len(exception.Stack) == 0 {
+			continue
+		}
+		truncated := int64(len(exception.Stack))
+		if exception.Truncated != nil {
+			truncated += *exception.Truncated
+		}
+		exception.Truncated = aws.Int64(truncated)
+		exception.Stack = nil
+	}
+}
+
+// truncateStrings truncates the exception messages, the SQL query, the URL
+// and the string annotations to maxTruncatedStringLength bytes.
+func truncateStrings(segment *awsxray.Segment) {
+	if segment.Cause != nil {
+		for i := range segment.Cause.Exceptions {
+			segment.Cause.Exceptions[i].Message = truncateStringPtr(segment.Cause.Exceptions[i].Message)
+		}
+	}
+	if segment.SQL != nil {
+		segment.SQL.SanitizedQuery = truncateStringPtr(segment.SQL.SanitizedQuery)
+	}
+	if segment.HTTP != nil && segment.HTTP.Request != nil {
+		segment.HTTP.Request.URL = truncateStringPtr(segment.HTTP.Request.URL)
+	}
+	for k, v := range segment.Annotations {
+		if s, ok := v.(string); ok {
+			segment.Annotations[k] = truncateString(s)
+		}
+	}
+}
+
+// dropExceptions drops the exceptions but the first, which is the one thrown.
+func dropExceptions(segment *awsxray.Segment) {
+	if segment.Cause != nil && len(segment.Cause.Exceptions) > 1 {
+		segment.Cause.Exceptions = segment.Cause.Exceptions[:1]
+	}
+}
+
+func truncateStringPtr(s *string) *string {
+	if s == nil || len(*s) <= maxTruncatedStringLength {
+		return s
+	}
+	return aws.String(truncateString(*s))
+}
+
+// truncateString truncates the string to maxTruncatedStringLength bytes,
+// without splitting a multi-byte character.
+func truncateString(s string) string {
+	if len(s) <= maxTruncatedStringLength {
+		return s
+	}
+	end := maxTruncatedStringLength
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+	return s[:end]
+}
@@ -0,0 +1,190 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+func newTestSegment() awsxray.Segment {
+	span := constructServerSpan(newSegmentID(), "/api/locations", 0, "OK", map[string]interface{}{"user": "testingT"})
+	return MakeSegment(span, constructDefaultResource(), nil, false)
+}
+
+func unmarshalSegments(t *testing.T, documents []string, maxSize int) []awsxray.Segment {
+	segments := make([]awsxray.Segment, len(documents))
+	for i, document := range documents {
+		assert.LessOrEqual(t, len(document), maxSize)
+		require.NoError(t, json.Unmarshal([]byte(document), &segments[i]))
+	}
+	return segments
+}
+
+func TestSerializeSegmentDocumentsSmallSegment(t *testing.T) {
+	segment := newTestSegment()
+	expected, err := SerializeSegment(segment)
+	require.NoError(t, err)
+
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	assert.Equal(t, []string{expected}, documents)
+}
+
+func TestSerializeSegmentDocumentsSplitsMetadata(t *testing.T) {
+	segment := newTestSegment()
+	segment.Metadata = map[string]map[string]interface{}{"default": {}, "app": {}}
+	for i := 0; i < 200; i++ {
+		segment.Metadata["default"][fmt.Sprintf("attr%03d", i)] = strings.Repeat("v", 1000)
+	}
+	segment.Metadata["app"]["config"] = map[string]interface{}{"retries": 3.0, "hosts": []interface{}{"a", "b"}}
+
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 5)
+	segments := unmarshalSegments(t, documents, MaxSegmentDocumentSize)
+
+	assert.Equal(t, segment.ID, segments[0].ID)
+	assert.Nil(t, segments[0].Metadata)
+	assert.NotContains(t, segments[0].Annotations, TruncatedAnnotation)
+	metadata := map[string]map[string]interface{}{}
+	for _, subsegment := range segments[1:] {
+		assert.Equal(t, "subsegment", *subsegment.Type)
+		assert.Equal(t, segment.ID, subsegment.ParentID)
+		assert.Equal(t, segment.TraceID, subsegment.TraceID)
+		assert.Equal(t, segment.Name, subsegment.Name)
+		assert.Equal(t, segment.StartTime, subsegment.StartTime)
+		assert.Equal(t, segment.EndTime, subsegment.EndTime)
+		assert.NotEqual(t, segment.ID, subsegment.ID)
+		for namespace, values := range subsegment.Metadata {
+			if metadata[namespace] == nil {
+				metadata[namespace] = map[string]interface{}{}
+			}
+			for k, v := range values {
+				metadata[namespace][k] = v
+			}
+		}
+	}
+	assert.Equal(t, segment.Metadata, metadata)
+}
+
+func TestSerializeSegmentDocumentsDropsOversizedMetadata(t *testing.T) {
+	segment := newTestSegment()
+	segment.Metadata = map[string]map[string]interface{}{"default": {
+		"small": "value",
+		"large": strings.Repeat("v", MaxSegmentDocumentSize),
+	}}
+
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	segments := unmarshalSegments(t, documents, MaxSegmentDocumentSize)
+	assert.Equal(t, true, segments[0].Annotations[TruncatedAnnotation])
+	assert.Equal(t, map[string]map[string]interface{}{"default": {"small": "value"}}, segments[1].Metadata)
+}
+
+func TestSerializeSegmentDocumentsTruncatesExceptions(t *testing.T) {
+	segment := newTestSegment()
+	stack := make([]awsxray.StackFrame, 2000)
+	for i := range stack {
+		stack[i] = awsxray.StackFrame{Path: aws.String("com/example/Handler.java"), Line: aws.Int(i), Label: aws.String("handle")}
+	}
+	segment.Cause = &awsxray.CauseData{
+		Type: awsxray.CauseTypeObject,
+		CauseObject: awsxray.CauseObject{Exceptions: []awsxray.Exception{
+			{Type: aws.String("java.lang.IllegalStateException"), Message: aws.String("failed"), Stack: stack, Truncated: aws.Int64(5)},
+			{Type: aws.String("java.io.IOException"), Message: aws.String("closed"), Stack: stack[:10]},
+		}},
+	}
+
+	// the metadata are moved to a subsegment before the segment is truncated
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	segments := unmarshalSegments(t, documents, MaxSegmentDocumentSize)
+	assert.Equal(t, true, segments[0].Annotations[TruncatedAnnotation])
+	exceptions := segments[0].Cause.Exceptions
+	require.Len(t, exceptions, 2)
+	assert.Nil(t, exceptions[0].Stack)
+	assert.Equal(t, int64(2005), *exceptions[0].Truncated)
+	assert.Equal(t, int64(10), *exceptions[1].Truncated)
+	assert.Equal(t, "failed", *exceptions[0].Message)
+
+	// the segment of the caller is not modified
+	assert.Len(t, segment.Cause.Exceptions[0].Stack, 2000)
+	assert.NotContains(t, segment.Annotations, TruncatedAnnotation)
+}
+
+func TestSerializeSegmentDocumentsTruncatesStrings(t *testing.T) {
+	segment := newTestSegment()
+	query := strings.Repeat("SELECT * FROM customers WHERE id = ? OR ", 2000)
+	segment.SQL = &awsxray.SQLData{SanitizedQuery: aws.String(query)}
+	segment.Annotations["query"] = query
+
+	// the metadata are moved to a subsegment before the segment is truncated
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	require.Len(t, documents, 2)
+	segments := unmarshalSegments(t, documents, MaxSegmentDocumentSize)
+	assert.Equal(t, true, segments[0].Annotations[TruncatedAnnotation])
+	assert.Equal(t, query[:maxTruncatedStringLength], *segments[0].SQL.SanitizedQuery)
+	assert.Equal(t, query[:maxTruncatedStringLength], segments[0].Annotations["query"])
+	assert.Equal(t, query, *segment.SQL.SanitizedQuery)
+}
+
+func TestSerializeSegmentDocumentsDropsExceptions(t *testing.T) {
+	segment := newTestSegment()
+	exceptions := make([]awsxray.Exception, 500)
+	for i := range exceptions {
+		exceptions[i] = awsxray.Exception{Type: aws.String("java.lang.IllegalStateException"), Message: aws.String(strings.Repeat("m", 1000))}
+	}
+	segment.Cause = &awsxray.CauseData{Type: awsxray.CauseTypeObject, CauseObject: awsxray.CauseObject{Exceptions: exceptions}}
+
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	require.NoError(t, err)
+	segments := unmarshalSegments(t, documents, MaxSegmentDocumentSize)
+	assert.Len(t, segments[0].Cause.Exceptions, 1)
+	assert.Equal(t, true, segments[0].Annotations[TruncatedAnnotation])
+}
+
+func TestSerializeSegmentDocumentsTooLarge(t *testing.T) {
+	segment := newTestSegment()
+	segment.User = aws.String(strings.Repeat("u", MaxSegmentDocumentSize))
+
+	documents, err := SerializeSegmentDocuments(segment, MaxSegmentDocumentSize)
+	assert.Error(t, err)
+	assert.Nil(t, documents)
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "short", truncateString("short"))
+	long := strings.Repeat("é", maxTruncatedStringLength)
+	truncated := truncateString(long)
+	assert.Equal(t, strings.Repeat("é", maxTruncatedStringLength/2), truncated)
+
+	// a multi-byte character is not split
+	long = "a" + long
+	truncated = truncateString(long)
+	assert.Len(t, truncated, maxTruncatedStringLength-1)
+	assert.True(t, strings.HasSuffix(truncated, "é"))
+}
@@ -24,6 +24,9 @@ type AWSConfig struct {
 	KinesisEndpoint string `mapstructure:"kinesis_endpoint"`
 	Region          string `mapstructure:"region"`
 	Role            string `mapstructure:"role"`
+	// UseFIPSEndpoint sends the records to the FIPS 140-2 endpoint of Kinesis
+	// in the region, it cannot be used with KinesisEndpoint.
+	UseFIPSEndpoint bool `mapstructure:"use_fips_endpoint"`
 }
 
 // KPLConfig contains kinesis producer library related config to controls things
@@ -21,6 +21,8 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/awsutil"
 )
 
 const (
@@ -68,12 +70,16 @@ func createTraceExporter(
 	config configmodels.Exporter,
 ) (component.TraceExporter, error) {
 	c := config.(*Config)
+	endpoint, err := awsutil.ResolveEndpoint("kinesis", c.AWS.Region, c.AWS.KinesisEndpoint, c.AWS.UseFIPSEndpoint)
+	if err != nil {
+		return nil, err
+	}
 	k, err := kinesis.NewExporter(&kinesis.Options{
 		Name:               c.Name(),
 		StreamName:         c.AWS.StreamName,
 		AWSRegion:          c.AWS.Region,
 		AWSRole:            c.AWS.Role,
-		AWSKinesisEndpoint: c.AWS.KinesisEndpoint,
+		AWSKinesisEndpoint: endpoint,
 
 		KPLAggregateBatchSize:   c.KPL.AggregateBatchSize,
 		KPLAggregateBatchCount:  c.KPL.AggregateBatchCount,
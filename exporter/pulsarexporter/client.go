@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"errors"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+)
+
+// ClientConfig defines the connection to a Pulsar cluster, it is shared by
+// the Pulsar exporter and receiver.
+type ClientConfig struct {
+	// The URL of the Pulsar service, pulsar+ssl:// URLs enabling TLS
+	// (default "pulsar://localhost:6650")
+	URL string `mapstructure:"url"`
+
+	// Authentication of the client, the client is not authenticated if not
+	// set.
+	Authentication Authentication `mapstructure:"auth"`
+
+	// TLS configures the connections to pulsar+ssl:// URLs.
+	TLS TLSSettings `mapstructure:"tls"`
+}
+
+// Authentication defines how the client authenticates with the brokers,
+// at most one method can be set.
+type Authentication struct {
+	// Token is the JSON Web Token of the client, for token authentication.
+	Token string `mapstructure:"token"`
+
+	// TLS authenticates the client with a certificate.
+	TLS *TLSAuthentication `mapstructure:"tls"`
+}
+
+// TLSAuthentication defines the certificate of the client for TLS
+// authentication.
+type TLSAuthentication struct {
+	// Path of the certificate of the client.
+	CertFile string `mapstructure:"cert_file"`
+	// Path of the private key of the client.
+	KeyFile string `mapstructure:"key_file"`
+}
+
+// TLSSettings defines the verification of the certificates of the brokers.
+type TLSSettings struct {
+	// Path of the certificates of the trusted authorities, the certificates
+	// of the system are used if not set.
+	CAFile string `mapstructure:"ca_file"`
+	// InsecureSkipVerify disables the verification of the certificates of
+	// the brokers and of their hostname.
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// ClientOptions returns the options of the Pulsar client for the config.
+func (c *ClientConfig) ClientOptions() (pulsar.ClientOptions, error) {
+	if c.URL == "" {
+		return pulsar.ClientOptions{}, errors.New("`url` not specified")
+	}
+
+	opts := pulsar.ClientOptions{
+		URL:                        c.URL,
+		TLSTrustCertsFilePath:      c.TLS.CAFile,
+		TLSAllowInsecureConnection: c.TLS.InsecureSkipVerify,
+		TLSValidateHostname:        !c.TLS.InsecureSkipVerify,
+	}
+
+	auth := c.Authentication
+	switch {
+	case auth.Token != "" && auth.TLS != nil:
+		return pulsar.ClientOptions{}, errors.New("only one of `auth.token` and `auth.tls` can be specified")
+	case auth.Token != "":
+		opts.Authentication = pulsar.NewAuthenticationToken(auth.Token)
+	case auth.TLS != nil:
+		if auth.TLS.CertFile == "" || auth.TLS.KeyFile == "" {
+			return pulsar.ClientOptions{}, errors.New("`auth.tls` requires both `cert_file` and `key_file`")
+		}
+		opts.Authentication = pulsar.NewAuthenticationTLS(auth.TLS.CertFile, auth.TLS.KeyFile)
+	}
+	return opts, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientOptions(t *testing.T) {
+	cfg := ClientConfig{
+		URL: "pulsar+ssl://localhost:6651",
+		TLS: TLSSettings{
+			CAFile: "ca.pem",
+		},
+	}
+	opts, err := cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.Equal(t, pulsar.ClientOptions{
+		URL:                   "pulsar+ssl://localhost:6651",
+		TLSTrustCertsFilePath: "ca.pem",
+		TLSValidateHostname:   true,
+	}, opts)
+
+	cfg.TLS.InsecureSkipVerify = true
+	opts, err = cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.True(t, opts.TLSAllowInsecureConnection)
+	assert.False(t, opts.TLSValidateHostname)
+}
+
+func TestClientOptionsAuthentication(t *testing.T) {
+	cfg := ClientConfig{
+		URL:            "pulsar://localhost:6650",
+		Authentication: Authentication{Token: "token"},
+	}
+	opts, err := cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.NotNil(t, opts.Authentication)
+
+	cfg.Authentication = Authentication{TLS: &TLSAuthentication{CertFile: "cert.pem", KeyFile: "key.pem"}}
+	opts, err = cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.NotNil(t, opts.Authentication)
+}
+
+func TestClientOptionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  ClientConfig
+		err  string
+	}{
+		{
+			name: "no url",
+			err:  "`url` not specified",
+		},
+		{
+			name: "both authentications",
+			cfg: ClientConfig{
+				URL: "pulsar://localhost:6650",
+				Authentication: Authentication{
+					Token: "token",
+					TLS:   &TLSAuthentication{CertFile: "cert.pem", KeyFile: "key.pem"},
+				},
+			},
+			err: "only one of `auth.token` and `auth.tls` can be specified",
+		},
+		{
+			name: "tls authentication without key",
+			cfg: ClientConfig{
+				URL: "pulsar://localhost:6650",
+				Authentication: Authentication{
+					TLS: &TLSAuthentication{CertFile: "cert.pem"},
+				},
+			},
+			err: "`auth.tls` requires both `cert_file` and `key_file`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cfg.ClientOptions()
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// pulsarProducer produces the messages of a signal to its Pulsar topic.
+type pulsarProducer struct {
+	client   pulsar.Client
+	producer pulsar.Producer
+	topic    string
+	logger   *zap.Logger
+}
+
+// newPulsarProducer creates the Pulsar client, the producer being only
+// created on start as it connects to the brokers.
+func newPulsarProducer(config *Config, topic string, logger *zap.Logger) (*pulsarProducer, error) {
+	if topic == "" {
+		return nil, errors.New("topic not specified")
+	}
+
+	opts, err := config.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	client, err := pulsar.NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pulsarProducer{
+		client: client,
+		topic:  topic,
+		logger: logger,
+	}, nil
+}
+
+func (e *pulsarProducer) start(context.Context, component.Host) error {
+	producer, err := e.client.CreateProducer(pulsar.ProducerOptions{
+		Topic: e.topic,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the producer of topic %q: %w", e.topic, err)
+	}
+	e.producer = producer
+	return nil
+}
+
+func (e *pulsarProducer) shutdown(context.Context) error {
+	if e.producer != nil {
+		e.producer.Close()
+	}
+	e.client.Close()
+	return nil
+}
+
+// send produces a message and waits for its acknowledgement by the broker.
+func (e *pulsarProducer) send(ctx context.Context, payload []byte) error {
+	_, err := e.producer.Send(ctx, &pulsar.ProducerMessage{
+		Payload: payload,
+	})
+	return err
+}
+
+func (e *pulsarProducer) pushTraces(ctx context.Context, td pdata.Traces) (int, error) {
+	payload, err := marshalTraces(td)
+	if err != nil {
+		return td.SpanCount(), consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return td.SpanCount(), err
+	}
+	return 0, nil
+}
+
+func (e *pulsarProducer) pushMetrics(ctx context.Context, md pdata.Metrics) (int, error) {
+	numReceivedTimeSeries, _ := md.MetricAndDataPointCount()
+	payload, err := marshalMetrics(md)
+	if err != nil {
+		return numReceivedTimeSeries, consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return numReceivedTimeSeries, err
+	}
+	return 0, nil
+}
+
+func (e *pulsarProducer) pushLogs(ctx context.Context, ld pdata.Logs) (int, error) {
+	payload, err := marshalLogs(ld)
+	if err != nil {
+		return ld.LogRecordCount(), consumererror.Permanent(err)
+	}
+	if err = e.send(ctx, payload); err != nil {
+		return ld.LogRecordCount(), err
+	}
+	return 0, nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarexporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+)
+
+// fakeProducer records the payloads of the messages sent to it.
+type fakeProducer struct {
+	pulsar.Producer
+	payloads [][]byte
+	err      error
+	closed   bool
+}
+
+func (p *fakeProducer) Send(_ context.Context, msg *pulsar.ProducerMessage) (pulsar.MessageID, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.payloads = append(p.payloads, msg.Payload)
+	return nil, nil
+}
+
+func (p *fakeProducer) Close() {
+	p.closed = true
+}
+
+func newTestProducer(t *testing.T, producer pulsar.Producer) *pulsarProducer {
+	cfg := createDefaultConfig().(*Config)
+	e, err := newPulsarProducer(cfg, cfg.TracesTopic, zap.NewNop())
+	require.NoError(t, err)
+	e.producer = producer
+	return e
+}
+
+func TestPushTraces(t *testing.T) {
+	producer := &fakeProducer{}
+	e := newTestProducer(t, producer)
+	td := testTraces()
+
+	dropped, err := e.pushTraces(context.Background(), td)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalTraces(td)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, producer.payloads)
+
+	producer.err = errors.New("broker unavailable")
+	dropped, err = e.pushTraces(context.Background(), td)
+	assert.EqualError(t, err, "broker unavailable")
+	assert.False(t, consumererror.IsPermanent(err))
+	assert.Equal(t, 2, dropped)
+
+	assert.NoError(t, e.shutdown(context.Background()))
+	assert.True(t, producer.closed)
+}
+
+func TestPushMetrics(t *testing.T) {
+	producer := &fakeProducer{}
+	e := newTestProducer(t, producer)
+	md := testMetrics()
+
+	dropped, err := e.pushMetrics(context.Background(), md)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalMetrics(md)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, producer.payloads)
+
+	producer.err = errors.New("broker unavailable")
+	dropped, err = e.pushMetrics(context.Background(), md)
+	assert.Error(t, err)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestPushLogs(t *testing.T) {
+	producer := &fakeProducer{}
+	e := newTestProducer(t, producer)
+	ld := testLogs()
+
+	dropped, err := e.pushLogs(context.Background(), ld)
+	require.NoError(t, err)
+	assert.Equal(t, 0, dropped)
+	want, err := marshalLogs(ld)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{want}, producer.payloads)
+
+	producer.err = errors.New("broker unavailable")
+	dropped, err = e.pushLogs(context.Background(), ld)
+	assert.Error(t, err)
+	assert.Equal(t, 1, dropped)
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	e := newTestProducer(t, nil)
+	assert.NoError(t, e.shutdown(context.Background()))
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -42,6 +43,8 @@ type Config struct {
 	// URL is specified. If a path is not included the exporter will
 	// automatically append the appropriate path, eg.: "v2/datapoint".
 	// If a path is specified it will use the one set by the config.
+	// The events are sent to the "v2/event" path of the URL, replacing
+	// "v2/datapoint" if included.
 	IngestURL string `mapstructure:"ingest_url"`
 
 	// APIURL is the destination to where SignalFx metadata will be sent. This
@@ -61,6 +64,10 @@ type Config struct {
 	// Whether to log dimension updates being sent to SignalFx.
 	LogDimensionUpdates bool `mapstructure:"log_dimension_updates"`
 
+	// DimensionClient defines how the properties and tags of the dimensions,
+	// e.g. the labels of the Kubernetes pods and deployments, are sent.
+	DimensionClient DimensionClientConfig `mapstructure:"dimension_client"`
+
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
 
 	// SendCompatibleMetrics specifies if metrics must be sent in a format backward-compatible with
@@ -76,6 +83,19 @@ type Config struct {
 	DeltaTranslationTTL int64 `mapstructure:"delta_translation_ttl"`
 }
 
+// DimensionClientConfig defines the configuration of the client sending the
+// properties and tags of the dimensions to SignalFx.
+type DimensionClientConfig struct {
+	// SendDelay is the duration the updates of a dimension are held before
+	// being sent, the updates of the same dimension within it being merged.
+	// The default value is 10 seconds.
+	SendDelay time.Duration `mapstructure:"send_delay"`
+
+	// MaxBuffered is the maximum number of dimension updates waiting to be
+	// sent, the updates beyond it being dropped. The default value is 10000.
+	MaxBuffered int `mapstructure:"max_buffered"`
+}
+
 func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 	if err := cfg.validateConfig(); err != nil {
 		return nil, err
@@ -86,6 +106,8 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 		return nil, fmt.Errorf("invalid \"ingest_url\": %v", err)
 	}
 
+	eventsURL := getEventsURL(ingestURL)
+
 	apiURL, err := cfg.getAPIURL()
 	if err != nil {
 		return nil, fmt.Errorf("invalid \"api_url\": %v", err)
@@ -95,6 +117,10 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 		cfg.Timeout = 5 * time.Second
 	}
 
+	if cfg.DimensionClient.MaxBuffered == 0 {
+		cfg.DimensionClient.MaxBuffered = defaultDimensionMaxBuffered
+	}
+
 	var metricTranslator *translation.MetricTranslator
 	if cfg.SendCompatibleMetrics {
 		metricTranslator, err = translation.NewMetricTranslator(cfg.TranslationRules, cfg.DeltaTranslationTTL)
@@ -105,10 +131,13 @@ func (cfg *Config) getOptionsFromConfig() (*exporterOptions, error) {
 
 	return &exporterOptions{
 		ingestURL:        ingestURL,
+		eventsURL:        eventsURL,
 		apiURL:           apiURL,
 		httpTimeout:      cfg.Timeout,
 		token:            cfg.AccessToken,
 		logDimUpdate:     cfg.LogDimensionUpdates,
+		dimSendDelay:     cfg.DimensionClient.SendDelay,
+		dimMaxBuffered:   cfg.DimensionClient.MaxBuffered,
 		metricTranslator: metricTranslator,
 	}, nil
 }
@@ -127,6 +156,10 @@ func (cfg *Config) validateConfig() error {
 		return errors.New("cannot have a negative \"timeout\"")
 	}
 
+	if cfg.DimensionClient.SendDelay < 0 || cfg.DimensionClient.MaxBuffered < 0 {
+		return errors.New("cannot have a negative \"send_delay\" or \"max_buffered\" in \"dimension_client\"")
+	}
+
 	return nil
 }
 
@@ -150,6 +183,17 @@ func (cfg *Config) getIngestURL() (out *url.URL, err error) {
 	return out, err
 }
 
+// getEventsURL returns the URL of the events API of the ingest URL.
+func getEventsURL(ingestURL *url.URL) *url.URL {
+	eventsURL := *ingestURL
+	if strings.HasSuffix(eventsURL.Path, "v2/datapoint") {
+		eventsURL.Path = strings.TrimSuffix(eventsURL.Path, "v2/datapoint") + "v2/event"
+	} else {
+		eventsURL.Path = path.Join(eventsURL.Path, "v2/event")
+	}
+	return &eventsURL
+}
+
 func (cfg *Config) getAPIURL() (*url.URL, error) {
 	if cfg.APIURL == "" {
 		return url.Parse(fmt.Sprintf("https://api.%s.signalfx.com", cfg.Realm))
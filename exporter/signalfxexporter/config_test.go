@@ -66,6 +66,10 @@ func TestLoadConfig(t *testing.T) {
 			"dot.test":    "test",
 		},
 		Timeout: 2 * time.Second,
+		DimensionClient: DimensionClientConfig{
+			SendDelay:   5 * time.Second,
+			MaxBuffered: 2000,
+		},
 		AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 			AccessTokenPassthrough: false,
 		},
@@ -98,6 +102,7 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 		Headers               map[string]string
 		SendCompatibleMetrics bool
 		TranslationRules      []translation.Rule
+		DimensionClient       DimensionClientConfig
 	}
 	tests := []struct {
 		name    string
@@ -119,13 +124,47 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 					Host:   "ingest.us1.signalfx.com",
 					Path:   "/v2/datapoint",
 				},
+				eventsURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us1.signalfx.com",
+					Path:   "/v2/event",
+				},
 				apiURL: &url.URL{
 					Scheme: "https",
 					Host:   "api.us1.signalfx.com",
 					Path:   "/",
 				},
-				httpTimeout: 5 * time.Second,
-				token:       "access_token",
+				httpTimeout:    5 * time.Second,
+				token:          "access_token",
+				dimMaxBuffered: 10000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test ingest URL with a path",
+			fields: fields{
+				AccessToken: "access_token",
+				IngestURL:   "https://proxy.example.com/signalfx",
+				APIURL:      "https://api.us1.signalfx.com",
+			},
+			want: &exporterOptions{
+				ingestURL: &url.URL{
+					Scheme: "https",
+					Host:   "proxy.example.com",
+					Path:   "/signalfx",
+				},
+				eventsURL: &url.URL{
+					Scheme: "https",
+					Host:   "proxy.example.com",
+					Path:   "/signalfx/v2/event",
+				},
+				apiURL: &url.URL{
+					Scheme: "https",
+					Host:   "api.us1.signalfx.com",
+				},
+				httpTimeout:    5 * time.Second,
+				token:          "access_token",
+				dimMaxBuffered: 10000,
 			},
 			wantErr: false,
 		},
@@ -142,12 +181,18 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 					Host:   "ingest.us0.signalfx.com",
 					Path:   "/v2/datapoint",
 				},
+				eventsURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us0.signalfx.com",
+					Path:   "/v2/event",
+				},
 				apiURL: &url.URL{
 					Scheme: "https",
 					Host:   "api.us0.signalfx.com",
 				},
-				httpTimeout: 10 * time.Second,
-				token:       "access_token",
+				httpTimeout:    10 * time.Second,
+				token:          "access_token",
+				dimMaxBuffered: 10000,
 			},
 			wantErr: false,
 		},
@@ -182,6 +227,50 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Test dimension client settings",
+			fields: fields{
+				Realm:       "us0",
+				AccessToken: "access_token",
+				DimensionClient: DimensionClientConfig{
+					SendDelay:   time.Second,
+					MaxBuffered: 100,
+				},
+			},
+			want: &exporterOptions{
+				ingestURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us0.signalfx.com",
+					Path:   "/v2/datapoint",
+				},
+				eventsURL: &url.URL{
+					Scheme: "https",
+					Host:   "ingest.us0.signalfx.com",
+					Path:   "/v2/event",
+				},
+				apiURL: &url.URL{
+					Scheme: "https",
+					Host:   "api.us0.signalfx.com",
+				},
+				httpTimeout:    5 * time.Second,
+				token:          "access_token",
+				dimSendDelay:   time.Second,
+				dimMaxBuffered: 100,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Test negative dimension client settings",
+			fields: fields{
+				Realm:       "us0",
+				AccessToken: "access_token",
+				DimensionClient: DimensionClientConfig{
+					SendDelay: -time.Second,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name:    "Test empty config",
 			want:    nil,
@@ -215,6 +304,7 @@ func TestConfig_getOptionsFromConfig(t *testing.T) {
 				Headers:               tt.fields.Headers,
 				SendCompatibleMetrics: tt.fields.SendCompatibleMetrics,
 				TranslationRules:      tt.fields.TranslationRules,
+				DimensionClient:       tt.fields.DimensionClient,
 			}
 			got, err := cfg.getOptionsFromConfig()
 			if (err != nil) != tt.wantErr {
@@ -85,7 +85,7 @@ type DimensionClientOptions struct {
 	APIURL                *url.URL
 	LogUpdates            bool
 	Logger                *zap.Logger
-	SendDelay             int
+	SendDelay             time.Duration
 	PropertiesMaxBuffered int
 	MetricTranslator      *translation.MetricTranslator
 }
@@ -114,7 +114,7 @@ func NewDimensionClient(ctx context.Context, options DimensionClientOptions) *Di
 		ctx:              ctx,
 		Token:            options.Token,
 		APIURL:           options.APIURL,
-		sendDelay:        time.Duration(options.SendDelay) * time.Second,
+		sendDelay:        options.SendDelay,
 		delayedSet:       make(map[DimensionKey]*DimensionUpdate),
 		delayedQueue:     make(chan *queuedDimension, options.PropertiesMaxBuffered),
 		requestSender:    sender,
@@ -117,7 +117,7 @@ func setup(t *testing.T) (*DimensionClient, chan dim, *atomic.Value, context.Can
 		APIURL:                serverURL,
 		LogUpdates:            true,
 		Logger:                zap.NewNop(),
-		SendDelay:             1,
+		SendDelay:             time.Second,
 		PropertiesMaxBuffered: 10,
 	})
 	client.Start()
@@ -182,7 +182,7 @@ func (s *sfxDPClient) encodeBody(dps []*sfxpb.DataPoint) (bodyReader io.Reader,
 	if err != nil {
 		return nil, false, err
 	}
-	return s.getReader(body)
+	return getReader(&s.zippers, body)
 }
 
 func (s *sfxDPClient) retrieveAccessToken(md consumerdata.MetricsData) string {
@@ -201,12 +201,12 @@ func (s *sfxDPClient) retrieveAccessToken(md consumerdata.MetricsData) string {
 }
 
 // avoid attempting to compress things that fit into a single ethernet frame
-func (s *sfxDPClient) getReader(b []byte) (io.Reader, bool, error) {
+func getReader(zippers *sync.Pool, b []byte) (io.Reader, bool, error) {
 	var err error
 	if len(b) > 1500 {
 		buf := new(bytes.Buffer)
-		w := s.zippers.Get().(*gzip.Writer)
-		defer s.zippers.Put(w)
+		w := zippers.Get().(*gzip.Writer)
+		defer zippers.Put(w)
 		w.Reset(buf)
 		_, err = w.Write(b)
 		if err == nil {
@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxexporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/translation"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+// sfxEventClient sends the log records flagged as events to the SignalFx
+// events API.
+type sfxEventClient struct {
+	eventsURL              *url.URL
+	headers                map[string]string
+	client                 *http.Client
+	logger                 *zap.Logger
+	zippers                sync.Pool
+	accessTokenPassthrough bool
+}
+
+func (s *sfxEventClient) pushLogsData(ctx context.Context, ld pdata.Logs) (droppedLogRecords int, err error) {
+	var numDroppedLogRecords int
+	var errs []error
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		resourceAttrs := pdata.NewAttributeMap()
+		if resource := rl.Resource(); !resource.IsNil() {
+			resourceAttrs = resource.Attributes()
+		}
+
+		var events []*sfxpb.Event
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			illEvents, dropped := translation.LogSliceToSignalFxV2(s.logger, ill.Logs(), resourceAttrs)
+			events = append(events, illEvents...)
+			numDroppedLogRecords += dropped
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		if err := s.pushEventsForToken(events, s.retrieveAccessToken(resourceAttrs)); err != nil {
+			numDroppedLogRecords += len(events)
+			errs = append(errs, err)
+		}
+	}
+
+	return numDroppedLogRecords, componenterror.CombineErrors(errs)
+}
+
+func (s *sfxEventClient) pushEventsForToken(events []*sfxpb.Event, accessToken string) error {
+	msg := sfxpb.EventUploadMessage{
+		Events: events,
+	}
+	b, err := msg.Marshal()
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+	body, compressed, err := getReader(&s.zippers, b)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	req, err := http.NewRequest("POST", s.eventsURL.String(), body)
+	if err != nil {
+		return consumererror.Permanent(err)
+	}
+
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+
+	// Override access token in headers map if it's non empty.
+	if accessToken != "" {
+		req.Header.Set(splunk.SFxAccessTokenHeader, accessToken)
+	}
+
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+
+	// SignalFx accepts all 2XX codes.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf(
+			"HTTP %d %q",
+			resp.StatusCode,
+			http.StatusText(resp.StatusCode))
+	}
+
+	return nil
+}
+
+func (s *sfxEventClient) retrieveAccessToken(resourceAttrs pdata.AttributeMap) string {
+	if !s.accessTokenPassthrough {
+		return ""
+	}
+	if accessToken, ok := resourceAttrs.Get(splunk.SFxAccessTokenLabel); ok && accessToken.Type() == pdata.AttributeValueSTRING {
+		return accessToken.StringVal()
+	}
+	return ""
+}
@@ -38,14 +38,20 @@ type signalfxExporter struct {
 	logger          *zap.Logger
 	pushMetricsData func(ctx context.Context, md pdata.Metrics) (droppedTimeSeries int, err error)
 	pushMetadata    func(metadata []*collection.MetadataUpdate) error
+	pushLogsData    func(ctx context.Context, ld pdata.Logs) (droppedLogRecords int, err error)
+	// cancel stops the dimension client, nil if there is none.
+	cancel context.CancelFunc
 }
 
 type exporterOptions struct {
 	ingestURL        *url.URL
+	eventsURL        *url.URL
 	apiURL           *url.URL
 	httpTimeout      time.Duration
 	token            string
 	logDimUpdate     bool
+	dimSendDelay     time.Duration
+	dimMaxBuffered   int
 	metricTranslator *translation.MetricTranslator
 }
 
@@ -83,20 +89,16 @@ func newSignalFxExporter(
 		converter:              translation.NewMetricsConverter(logger, options.metricTranslator),
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
 	dimClient := dimensions.NewDimensionClient(
-		context.Background(),
+		ctx,
 		dimensions.DimensionClientOptions{
-			Token:      options.token,
-			APIURL:     options.apiURL,
-			LogUpdates: options.logDimUpdate,
-			Logger:     logger,
-			// Duration to wait between property updates. This might be worth
-			// being made configurable.
-			SendDelay: 10,
-			// In case of having issues sending dimension updates to SignalFx,
-			// buffer a fixed number of updates. Might also be a good candidate
-			// to make configurable.
-			PropertiesMaxBuffered: 10000,
+			Token:                 options.token,
+			APIURL:                options.apiURL,
+			LogUpdates:            options.logDimUpdate,
+			Logger:                logger,
+			SendDelay:             options.dimSendDelay,
+			PropertiesMaxBuffered: options.dimMaxBuffered,
 			MetricTranslator:      options.metricTranslator,
 		})
 	dimClient.Start()
@@ -105,6 +107,43 @@ func newSignalFxExporter(
 		logger:          logger,
 		pushMetricsData: dpClient.pushMetricsData,
 		pushMetadata:    dimClient.PushMetadata,
+		cancel:          cancel,
+	}, nil
+}
+
+// newEventExporter returns a new SignalFx exporter sending the log records
+// flagged as events to the SignalFx events API.
+func newEventExporter(
+	config *Config,
+	logger *zap.Logger,
+) (component.LogsExporter, error) {
+
+	if config == nil {
+		return nil, errors.New("nil config")
+	}
+
+	options, err := config.getOptionsFromConfig()
+	if err != nil {
+		return nil,
+			fmt.Errorf("failed to process %q config: %v", config.Name(), err)
+	}
+
+	eventClient := &sfxEventClient{
+		eventsURL: options.eventsURL,
+		headers:   buildHeaders(config),
+		client: &http.Client{
+			Timeout: config.Timeout,
+		},
+		logger: logger,
+		zippers: sync.Pool{New: func() interface{} {
+			return gzip.NewWriter(nil)
+		}},
+		accessTokenPassthrough: config.AccessTokenPassthrough,
+	}
+
+	return signalfxExporter{
+		logger:       logger,
+		pushLogsData: eventClient.pushLogsData,
 	}, nil
 }
 
@@ -112,7 +151,12 @@ func (se signalfxExporter) Start(context.Context, component.Host) error {
 	return nil
 }
 
+// Shutdown stops the dimension client, the dimension updates not sent yet
+// being dropped.
 func (se signalfxExporter) Shutdown(context.Context) error {
+	if se.cancel != nil {
+		se.cancel()
+	}
 	return nil
 }
 
@@ -125,6 +169,13 @@ func (se signalfxExporter) ConsumeMetrics(ctx context.Context, md pdata.Metrics)
 	return err
 }
 
+func (se signalfxExporter) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	ctx = obsreport.StartLogsExportOp(ctx, typeStr)
+	numDroppedLogRecords, err := se.pushLogsData(ctx, ld)
+	obsreport.EndLogsExportOp(ctx, ld.LogRecordCount(), numDroppedLogRecords, err)
+	return err
+}
+
 func (se signalfxExporter) ConsumeMetadata(metadata []*collection.MetadataUpdate) error {
 	return se.pushMetadata(metadata)
 }
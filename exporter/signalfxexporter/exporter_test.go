@@ -30,6 +30,7 @@ import (
 	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -41,6 +42,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/dimensions"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter/translation"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
@@ -461,6 +463,129 @@ func generateLargeBatch() pdata.Metrics {
 	return internaldata.OCSliceToMetrics(mds)
 }
 
+func TestNewEventExporter(t *testing.T) {
+	got, err := newEventExporter(nil, zap.NewNop())
+	require.EqualError(t, err, "nil config")
+	require.Nil(t, got)
+
+	got, err = newEventExporter(&Config{APIURL: "abc"}, zap.NewNop())
+	require.Error(t, err)
+	require.Nil(t, got)
+
+	got, err = newEventExporter(&Config{AccessToken: "someToken", Realm: "xyz", Timeout: time.Second}, zap.NewNop())
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	require.NoError(t, got.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, got.ConsumeLogs(context.Background(), pdata.NewLogs()))
+	require.NoError(t, got.Shutdown(context.Background()))
+}
+
+func makeSampleEventLogs(token string) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("k8s.cluster.name", "test")
+	if token != "" {
+		rl.Resource().Attributes().InsertString(splunk.SFxAccessTokenLabel, token)
+	}
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+
+	event := logs.At(0)
+	event.SetName("deployment")
+	event.SetTimestamp(pdata.TimestampUnixNano(1000000000))
+	event.Attributes().InsertInt(splunk.SFxEventCategoryKey, int64(sfxpb.EventCategory_USER_DEFINED))
+	event.Attributes().InsertString("service", "checkout")
+
+	// Not an event.
+	logs.At(1).SetName("access log")
+	return ld
+}
+
+func TestConsumeLogs(t *testing.T) {
+	tests := []struct {
+		name                 string
+		ld                   pdata.Logs
+		accessToken          string
+		httpResponseCode     int
+		numDroppedLogRecords int
+		wantErr              bool
+	}{
+		{
+			name:                 "happy_path",
+			ld:                   makeSampleEventLogs(""),
+			accessToken:          "DefaultToken",
+			httpResponseCode:     http.StatusAccepted,
+			numDroppedLogRecords: 1,
+		},
+		{
+			name:                 "access_token_passthrough",
+			ld:                   makeSampleEventLogs("PassthroughToken"),
+			accessToken:          "PassthroughToken",
+			httpResponseCode:     http.StatusAccepted,
+			numDroppedLogRecords: 1,
+		},
+		{
+			name:                 "response_forbidden",
+			ld:                   makeSampleEventLogs(""),
+			accessToken:          "DefaultToken",
+			httpResponseCode:     http.StatusForbidden,
+			numDroppedLogRecords: 2,
+			wantErr:              true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received sfxpb.EventUploadMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/v2/event", r.URL.Path)
+				assert.Equal(t, tt.accessToken, r.Header.Get("x-sf-token"))
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NoError(t, received.Unmarshal(body))
+				w.WriteHeader(tt.httpResponseCode)
+			}))
+			defer server.Close()
+
+			serverURL, err := url.Parse(server.URL)
+			assert.NoError(t, err)
+
+			eventClient := &sfxEventClient{
+				eventsURL: getEventsURL(serverURL),
+				headers:   map[string]string{"x-sf-token": "DefaultToken"},
+				client: &http.Client{
+					Timeout: 1 * time.Second,
+				},
+				logger: zap.NewNop(),
+				zippers: sync.Pool{New: func() interface{} {
+					return gzip.NewWriter(nil)
+				}},
+				accessTokenPassthrough: true,
+			}
+
+			numDroppedLogRecords, err := eventClient.pushLogsData(context.Background(), tt.ld)
+			assert.Equal(t, tt.numDroppedLogRecords, numDroppedLogRecords)
+
+			require.Len(t, received.Events, 1)
+			assert.Equal(t, "deployment", received.Events[0].EventType)
+			assert.Equal(t, int64(1000), received.Events[0].Timestamp)
+			assert.Equal(t, []*sfxpb.Dimension{
+				{Key: "k8s.cluster.name", Value: "test"},
+				{Key: "service", Value: "checkout"},
+			}, received.Events[0].Dimensions)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func TestConsumeMetadata(t *testing.T) {
 	type args struct {
 		metadata []*collection.MetadataUpdate
@@ -644,7 +769,7 @@ func TestConsumeMetadata(t *testing.T) {
 					APIURL:                serverURL,
 					LogUpdates:            true,
 					Logger:                logger,
-					SendDelay:             1,
+					SendDelay:             time.Second,
 					PropertiesMaxBuffered: 10,
 				})
 			dimClient.Start()
@@ -664,6 +789,46 @@ func TestConsumeMetadata(t *testing.T) {
 	}
 }
 
+func TestConsumeMetadataDimensionClientConfig(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests <- r
+	}))
+	defer server.Close()
+
+	config := &Config{
+		AccessToken: "test_token",
+		IngestURL:   server.URL,
+		APIURL:      server.URL,
+		DimensionClient: DimensionClientConfig{
+			SendDelay:   10 * time.Millisecond,
+			MaxBuffered: 10,
+		},
+	}
+	exp, err := newSignalFxExporter(config, zap.NewNop())
+	require.NoError(t, err)
+	se := exp.(signalfxExporter)
+
+	require.NoError(t, se.ConsumeMetadata([]*collection.MetadataUpdate{{
+		ResourceIDKey: "k8s.pod.uid",
+		ResourceID:    "pod-1",
+		MetadataDelta: collection.MetadataDelta{
+			MetadataToAdd: map[string]string{"app.kubernetes.io/name": "checkout"},
+		},
+	}}))
+
+	select {
+	case r := <-requests:
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/v2/dimension/k8s_pod_uid/pod-1/_/sfxagent", r.URL.Path)
+		assert.Equal(t, "test_token", r.Header.Get("X-SF-TOKEN"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dimension update should have been sent")
+	}
+
+	require.NoError(t, se.Shutdown(context.Background()))
+}
+
 func BenchmarkExporterConsumeData(b *testing.B) {
 	batchSize := 1000
 	mds := make([]consumerdata.MetricsData, 0, batchSize)
@@ -34,6 +34,9 @@ const (
 	typeStr = "signalfx"
 
 	defaultHTTPTimeout = time.Second * 5
+
+	defaultDimensionSendDelay   = time.Second * 10
+	defaultDimensionMaxBuffered = 10000
 )
 
 // NewFactory creates a factory for SignalFx exporter.
@@ -41,7 +44,8 @@ func NewFactory() component.ExporterFactory {
 	return exporterhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		exporterhelper.WithMetrics(createMetricsExporter))
+		exporterhelper.WithMetrics(createMetricsExporter),
+		exporterhelper.WithLogs(createLogsExporter))
 }
 
 func createDefaultConfig() configmodels.Exporter {
@@ -51,6 +55,10 @@ func createDefaultConfig() configmodels.Exporter {
 			NameVal: typeStr,
 		},
 		Timeout: defaultHTTPTimeout,
+		DimensionClient: DimensionClientConfig{
+			SendDelay:   defaultDimensionSendDelay,
+			MaxBuffered: defaultDimensionMaxBuffered,
+		},
 		AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 			AccessTokenPassthrough: true,
 		},
@@ -83,6 +91,14 @@ func createMetricsExporter(
 	return exp, nil
 }
 
+func createLogsExporter(
+	_ context.Context,
+	params component.ExporterCreateParams,
+	config configmodels.Exporter,
+) (component.LogsExporter, error) {
+	return newEventExporter(config.(*Config), params.Logger)
+}
+
 func loadDefaultTranslationRules() ([]translation.Rule, error) {
 	config := Config{}
 
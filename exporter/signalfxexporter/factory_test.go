@@ -52,6 +52,16 @@ func TestCreateMetricsExporter(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateLogsExporter(t *testing.T) {
+	cfg := createDefaultConfig()
+	c := cfg.(*Config)
+	c.AccessToken = "access_token"
+	c.Realm = "us0"
+
+	_, err := NewFactory().CreateLogsExporter(context.Background(), component.ExporterCreateParams{Logger: zap.NewNop()}, cfg)
+	assert.NoError(t, err)
+}
+
 func TestCreateInstanceViaFactory(t *testing.T) {
 	factory := NewFactory()
 
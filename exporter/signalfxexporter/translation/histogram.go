@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+const histogramBucketSuffix = "_bucket"
+
+// histogramBuckets holds the buckets of one histogram datapoint, as converted to
+// "<name>_bucket" datapoints, each counting the values of its bucket only.
+type histogramBuckets struct {
+	baseName string
+	// first bucket datapoint, providing the timestamp and the dimensions.
+	first  *sfxpb.DataPoint
+	bounds []float64
+	counts []int64
+}
+
+func (hb *histogramBuckets) Len() int           { return len(hb.bounds) }
+func (hb *histogramBuckets) Less(i, j int) bool { return hb.bounds[i] < hb.bounds[j] }
+func (hb *histogramBuckets) Swap(i, j int) {
+	hb.bounds[i], hb.bounds[j] = hb.bounds[j], hb.bounds[i]
+	hb.counts[i], hb.counts[j] = hb.counts[j], hb.counts[i]
+}
+
+// histogramBucketBaseName returns the name of the histogram of a bucket datapoint,
+// ok being false if dp is not a bucket datapoint of a histogram in metricNames,
+// or of any histogram if metricNames is empty.
+func histogramBucketBaseName(dp *sfxpb.DataPoint, metricNames map[string]bool) (string, bool) {
+	if !strings.HasSuffix(dp.Metric, histogramBucketSuffix) || upperBound(dp) == nil {
+		return "", false
+	}
+	baseName := strings.TrimSuffix(dp.Metric, histogramBucketSuffix)
+	if len(metricNames) > 0 && !metricNames[baseName] {
+		return "", false
+	}
+	return baseName, true
+}
+
+func upperBound(dp *sfxpb.DataPoint) *sfxpb.Dimension {
+	for _, d := range dp.Dimensions {
+		if d.Key == upperBoundDimensionKey {
+			return d
+		}
+	}
+	return nil
+}
+
+// calculateHistogramQuantiles returns the "<name>_quantile" gauges of the quantiles
+// in tr.Quantiles estimated from the bucket datapoints in dps. The buckets are
+// grouped by timestamp and dimensions other than "upper_bound" and the
+// histograms without values are skipped.
+func calculateHistogramQuantiles(dps []*sfxpb.DataPoint, tr Rule) []*sfxpb.DataPoint {
+	var keys []string
+	histograms := map[string]*histogramBuckets{}
+	for _, dp := range dps {
+		baseName, ok := histogramBucketBaseName(dp, tr.MetricNames)
+		if !ok || dp.Value.IntValue == nil {
+			continue
+		}
+		bound, err := strconv.ParseFloat(upperBound(dp).Value, 64)
+		if err != nil {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d/%s", baseName, dp.Timestamp,
+			stringifyDimensions(dp.Dimensions, []string{upperBoundDimensionKey}))
+		hb, ok := histograms[key]
+		if !ok {
+			hb = &histogramBuckets{baseName: baseName, first: dp}
+			histograms[key] = hb
+			keys = append(keys, key)
+		}
+		hb.bounds = append(hb.bounds, bound)
+		hb.counts = append(hb.counts, *dp.Value.IntValue)
+	}
+
+	var quantileDps []*sfxpb.DataPoint
+	for _, key := range keys {
+		hb := histograms[key]
+		sort.Sort(hb)
+		dimensions := filterDimensions(hb.first.Dimensions, []string{upperBoundDimensionKey})
+		for _, q := range tr.Quantiles {
+			value, ok := hb.quantile(q)
+			if !ok {
+				continue
+			}
+			quantileDimensions := make([]*sfxpb.Dimension, len(dimensions)+1)
+			copy(quantileDimensions, dimensions)
+			quantileDimensions[len(quantileDimensions)-1] = &sfxpb.Dimension{
+				Key:   quantileDimensionKey,
+				Value: float64ToDimValue(q),
+			}
+			quantileDps = append(quantileDps, &sfxpb.DataPoint{
+				Metric:     hb.baseName + "_quantile",
+				Timestamp:  hb.first.Timestamp,
+				MetricType: quantileMetricType,
+				Dimensions: quantileDimensions,
+				Value:      sfxpb.Datum{DoubleValue: &value},
+			})
+		}
+	}
+	return quantileDps
+}
+
+// quantile estimates the q quantile the way Prometheus histogram_quantile does:
+// the values of the bucket the quantile falls in are assumed to be evenly spread
+// between its lower and upper bounds, the lower bound of the first bucket being 0
+// if positive. A quantile falling in the +Inf bucket is estimated to be the
+// largest finite bound. ok is false if the histogram has no values or no finite
+// bound to estimate the quantile from.
+func (hb *histogramBuckets) quantile(q float64) (value float64, ok bool) {
+	var total int64
+	for _, c := range hb.counts {
+		total += c
+	}
+	if total == 0 {
+		return 0, false
+	}
+
+	rank := q * float64(total)
+	var cumulative int64
+	for i, c := range hb.counts {
+		if c == 0 || float64(cumulative+c) < rank {
+			cumulative += c
+			continue
+		}
+		upper := hb.bounds[i]
+		if math.IsInf(upper, 1) {
+			if i == 0 {
+				return 0, false
+			}
+			return hb.bounds[i-1], true
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = hb.bounds[i-1]
+		} else if upper <= 0 {
+			return upper, true
+		}
+		return lower + (upper-lower)*(rank-float64(cumulative))/float64(c), true
+	}
+	return 0, false
+}
@@ -0,0 +1,142 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+// LogSliceToSignalFxV2 converts the log records flagged as SignalFx events,
+// i.e. with a "com.splunk.signalfx.event_category" attribute, to SignalFx
+// events and returns the number of log records dropped. The name of a log
+// record is the type of its event, the attributes of its resource and its
+// other attributes its dimensions, and its
+// "com.splunk.signalfx.event_properties" map attribute its properties.
+func LogSliceToSignalFxV2(
+	logger *zap.Logger,
+	logs pdata.LogSlice,
+	resourceAttrs pdata.AttributeMap,
+) (events []*sfxpb.Event, numDroppedLogRecords int) {
+	events = make([]*sfxpb.Event, 0, logs.Len())
+	for i := 0; i < logs.Len(); i++ {
+		lr := logs.At(i)
+		if lr.IsNil() {
+			continue
+		}
+		event, ok := logRecordToEvent(logger, lr, resourceAttrs)
+		if !ok {
+			numDroppedLogRecords++
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, numDroppedLogRecords
+}
+
+func logRecordToEvent(logger *zap.Logger, lr pdata.LogRecord, resourceAttrs pdata.AttributeMap) (*sfxpb.Event, bool) {
+	attrs := lr.Attributes()
+	categoryVal, ok := attrs.Get(splunk.SFxEventCategoryKey)
+	if !ok {
+		return nil, false
+	}
+	category, ok := eventCategory(categoryVal)
+	if !ok {
+		logger.Debug("Dropping the event of an invalid category",
+			zap.String("event_type", lr.Name()),
+			zap.String("category", tracetranslator.AttributeValueToString(categoryVal, false)))
+		return nil, false
+	}
+	if lr.Name() == "" {
+		logger.Debug("Dropping an event without type, the log record having no name")
+		return nil, false
+	}
+
+	event := &sfxpb.Event{
+		EventType: lr.Name(),
+		Category:  &category,
+		// SignalFx timestamps are in milliseconds.
+		Timestamp:  int64(lr.Timestamp()) / 1e6,
+		Dimensions: make([]*sfxpb.Dimension, 0, resourceAttrs.Len()+attrs.Len()),
+	}
+
+	appendDimensions := func(k string, v pdata.AttributeValue) {
+		switch k {
+		case splunk.SFxEventCategoryKey, splunk.SFxEventPropertiesKey, splunk.SFxAccessTokenLabel:
+			return
+		}
+		event.Dimensions = append(event.Dimensions, &sfxpb.Dimension{
+			Key:   k,
+			Value: tracetranslator.AttributeValueToString(v, false),
+		})
+	}
+	resourceAttrs.ForEach(appendDimensions)
+	attrs.ForEach(appendDimensions)
+
+	if propertiesVal, ok := attrs.Get(splunk.SFxEventPropertiesKey); ok && propertiesVal.Type() == pdata.AttributeValueMAP {
+		properties := propertiesVal.MapVal()
+		event.Properties = make([]*sfxpb.Property, 0, properties.Len())
+		properties.ForEach(func(k string, v pdata.AttributeValue) {
+			event.Properties = append(event.Properties, &sfxpb.Property{
+				Key:   k,
+				Value: propertyValue(v),
+			})
+		})
+	}
+
+	return event, true
+}
+
+// eventCategory returns the category of an event from its value, either
+// the number or the name of a SignalFx event category.
+func eventCategory(v pdata.AttributeValue) (sfxpb.EventCategory, bool) {
+	var category int32
+	switch v.Type() {
+	case pdata.AttributeValueINT:
+		category = int32(v.IntVal())
+	case pdata.AttributeValueSTRING:
+		var ok bool
+		if category, ok = sfxpb.EventCategory_value[v.StringVal()]; !ok {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+	if _, ok := sfxpb.EventCategory_name[category]; !ok {
+		return 0, false
+	}
+	return sfxpb.EventCategory(category), true
+}
+
+func propertyValue(v pdata.AttributeValue) *sfxpb.PropertyValue {
+	switch v.Type() {
+	case pdata.AttributeValueINT:
+		i := v.IntVal()
+		return &sfxpb.PropertyValue{IntValue: &i}
+	case pdata.AttributeValueDOUBLE:
+		d := v.DoubleVal()
+		return &sfxpb.PropertyValue{DoubleValue: &d}
+	case pdata.AttributeValueBOOL:
+		b := v.BoolVal()
+		return &sfxpb.PropertyValue{BoolValue: &b}
+	default:
+		s := tracetranslator.AttributeValueToString(v, false)
+		return &sfxpb.PropertyValue{StrValue: &s}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+func TestLogSliceToSignalFxV2(t *testing.T) {
+	userDefined := sfxpb.EventCategory_USER_DEFINED
+	alert := sfxpb.EventCategory_ALERT
+	strPtr := func(s string) *string { return &s }
+	boolPtr := func(b bool) *bool { return &b }
+
+	tests := []struct {
+		name        string
+		logRecord   func(lr pdata.LogRecord)
+		wantEvents  []*sfxpb.Event
+		wantDropped int
+	}{
+		{
+			name: "event",
+			logRecord: func(lr pdata.LogRecord) {
+				lr.SetName("deployment")
+				lr.SetTimestamp(pdata.TimestampUnixNano(1500000000))
+				lr.Attributes().InsertInt(splunk.SFxEventCategoryKey, int64(userDefined))
+				lr.Attributes().InsertString("service", "checkout")
+				properties := pdata.NewAttributeMap()
+				properties.InsertString("version", "1.2.3")
+				properties.InsertInt("replicas", 3)
+				properties.InsertDouble("ratio", 0.5)
+				properties.InsertBool("canary", true)
+				propertiesVal := pdata.NewAttributeValueNull()
+				propertiesVal.SetMapVal(properties)
+				lr.Attributes().Insert(splunk.SFxEventPropertiesKey, propertiesVal)
+			},
+			wantEvents: []*sfxpb.Event{
+				{
+					EventType: "deployment",
+					Category:  &userDefined,
+					Timestamp: 1500,
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "k8s.cluster.name", Value: "test"},
+						{Key: "service", Value: "checkout"},
+					},
+					Properties: []*sfxpb.Property{
+						{Key: "version", Value: &sfxpb.PropertyValue{StrValue: strPtr("1.2.3")}},
+						{Key: "replicas", Value: &sfxpb.PropertyValue{IntValue: generateIntPtr(3)}},
+						{Key: "ratio", Value: &sfxpb.PropertyValue{DoubleValue: generateFloatPtr(0.5)}},
+						{Key: "canary", Value: &sfxpb.PropertyValue{BoolValue: boolPtr(true)}},
+					},
+				},
+			},
+		},
+		{
+			name: "category_name",
+			logRecord: func(lr pdata.LogRecord) {
+				lr.SetName("cpu high")
+				lr.Attributes().InsertString(splunk.SFxEventCategoryKey, "ALERT")
+			},
+			wantEvents: []*sfxpb.Event{
+				{
+					EventType: "cpu high",
+					Category:  &alert,
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "k8s.cluster.name", Value: "test"},
+					},
+				},
+			},
+		},
+		{
+			name: "not_an_event",
+			logRecord: func(lr pdata.LogRecord) {
+				lr.SetName("access log")
+			},
+			wantEvents:  []*sfxpb.Event{},
+			wantDropped: 1,
+		},
+		{
+			name: "invalid_category",
+			logRecord: func(lr pdata.LogRecord) {
+				lr.SetName("deployment")
+				lr.Attributes().InsertInt(splunk.SFxEventCategoryKey, 42)
+			},
+			wantEvents:  []*sfxpb.Event{},
+			wantDropped: 1,
+		},
+		{
+			name: "no_event_type",
+			logRecord: func(lr pdata.LogRecord) {
+				lr.Attributes().InsertInt(splunk.SFxEventCategoryKey, int64(userDefined))
+			},
+			wantEvents:  []*sfxpb.Event{},
+			wantDropped: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourceAttrs := pdata.NewAttributeMap()
+			resourceAttrs.InsertString("k8s.cluster.name", "test")
+			resourceAttrs.InsertString(splunk.SFxAccessTokenLabel, "secret")
+
+			logs := pdata.NewLogSlice()
+			logs.Resize(1)
+			tt.logRecord(logs.At(0))
+
+			events, dropped := LogSliceToSignalFxV2(zap.NewNop(), logs, resourceAttrs)
+			assert.Equal(t, tt.wantDropped, dropped)
+			assert.Equal(t, tt.wantEvents, events)
+		})
+	}
+}
@@ -117,6 +117,22 @@ const (
 	// metric. It takes mappings of names of the existing metrics to the names of the new, delta metrics to be
 	// created. All dimensions will be preserved.
 	ActionDeltaMetric Action = "delta_metric"
+
+	// ActionCalculateHistogramQuantiles estimates the quantiles listed in "quantiles" of the histograms
+	// named in "metric_names", or of all the histograms if not set, from their "<name>_bucket" datapoints.
+	// Each quantile is emitted as a "<name>_quantile" gauge with a "quantile" dimension, as the quantiles
+	// of summaries are, interpolating linearly within the bucket the quantile falls in. It must be applied
+	// before the buckets are dropped or renamed.
+	ActionCalculateHistogramQuantiles Action = "calculate_histogram_quantiles"
+
+	// ActionDropHistogramBuckets drops the "<name>_bucket" datapoints of the histograms named in
+	// "metric_names", or of all the histograms if not set. Their "<name>" and "<name>_count" datapoints
+	// are kept.
+	ActionDropHistogramBuckets Action = "drop_histogram_buckets"
+
+	// ActionRenameHistogramBuckets replaces the "_bucket" suffix of the bucket datapoints of the histograms
+	// named in "metric_names", or of all the histograms if not set, by "bucket_suffix".
+	ActionRenameHistogramBuckets Action = "rename_histogram_buckets"
 )
 
 type MetricOperator string
@@ -185,9 +201,18 @@ type Rule struct {
 	// excluded by aggregation.
 	WithoutDimensions []string `mapstructure:"without_dimensions"`
 
-	// MetricNames is used by "rename_dimension_keys" and "drop_metrics" translation rules.
+	// MetricNames is used by "rename_dimension_keys", "drop_metrics" and the histogram translation rules.
+	// The histograms are named without the suffixes of their datapoints.
 	MetricNames map[string]bool `mapstructure:"metric_names"`
 
+	// Quantiles is used by "calculate_histogram_quantiles" translation rule to specify the quantiles,
+	// between 0 and 1, to be estimated.
+	Quantiles []float64 `mapstructure:"quantiles"`
+
+	// BucketSuffix is used by "rename_histogram_buckets" translation rule to specify the suffix
+	// replacing "_bucket".
+	BucketSuffix string `mapstructure:"bucket_suffix"`
+
 	Operand1Metric string         `mapstructure:"operand1_metric"`
 	Operand2Metric string         `mapstructure:"operand2_metric"`
 	Operator       MetricOperator `mapstructure:"operator"`
@@ -299,6 +324,20 @@ func validateTranslationRules(rules []Rule) error {
 			if len(tr.Mapping) == 0 {
 				return fmt.Errorf(`field "mapping" is required for %q translation rule`, tr.Action)
 			}
+		case ActionCalculateHistogramQuantiles:
+			if len(tr.Quantiles) == 0 {
+				return fmt.Errorf(`field "quantiles" is required for %q translation rule`, tr.Action)
+			}
+			for _, q := range tr.Quantiles {
+				if q < 0 || q > 1 {
+					return fmt.Errorf(`"quantiles" for %q translation rule has %v value not between 0 and 1`, tr.Action, q)
+				}
+			}
+		case ActionDropHistogramBuckets:
+		case ActionRenameHistogramBuckets:
+			if tr.BucketSuffix == "" {
+				return fmt.Errorf(`field "bucket_suffix" is required for %q translation rule`, tr.Action)
+			}
 		default:
 			return fmt.Errorf("unknown \"action\" value: %q", tr.Action)
 		}
@@ -437,6 +476,28 @@ func (mp *MetricTranslator) TranslateDataPoints(logger *zap.Logger, sfxDataPoint
 
 		case ActionDeltaMetric:
 			processedDataPoints = mp.deltaTranslator.translate(processedDataPoints, tr)
+
+		case ActionCalculateHistogramQuantiles:
+			processedDataPoints = append(processedDataPoints, calculateHistogramQuantiles(processedDataPoints, tr)...)
+
+		case ActionDropHistogramBuckets:
+			resultSliceLen := 0
+			for i, dp := range processedDataPoints {
+				if _, ok := histogramBucketBaseName(dp, tr.MetricNames); !ok {
+					if resultSliceLen < i {
+						processedDataPoints[resultSliceLen] = dp
+					}
+					resultSliceLen++
+				}
+			}
+			processedDataPoints = processedDataPoints[:resultSliceLen]
+
+		case ActionRenameHistogramBuckets:
+			for _, dp := range processedDataPoints {
+				if baseName, ok := histogramBucketBaseName(dp, tr.MetricNames); ok {
+					dp.Metric = baseName + tr.BucketSuffix
+				}
+			}
 		}
 	}
 
@@ -15,6 +15,7 @@
 package translation
 
 import (
+	"math"
 	"sort"
 	"testing"
 	"time"
@@ -472,6 +473,60 @@ func TestNewMetricTranslator(t *testing.T) {
 			},
 			wantError: `field "mapping" is required for "delta_metric" translation rule`,
 		},
+		{
+			name: "calculate_histogram_quantiles_valid",
+			trs: []Rule{
+				{
+					Action:    ActionCalculateHistogramQuantiles,
+					Quantiles: []float64{0, 0.5, 0.99, 1},
+				},
+			},
+		},
+		{
+			name: "calculate_histogram_quantiles_no_quantiles",
+			trs: []Rule{
+				{
+					Action: ActionCalculateHistogramQuantiles,
+				},
+			},
+			wantError: `field "quantiles" is required for "calculate_histogram_quantiles" translation rule`,
+		},
+		{
+			name: "calculate_histogram_quantiles_invalid_quantile",
+			trs: []Rule{
+				{
+					Action:    ActionCalculateHistogramQuantiles,
+					Quantiles: []float64{0.5, 99},
+				},
+			},
+			wantError: `"quantiles" for "calculate_histogram_quantiles" translation rule has 99 value not between 0 and 1`,
+		},
+		{
+			name: "drop_histogram_buckets_valid",
+			trs: []Rule{
+				{
+					Action: ActionDropHistogramBuckets,
+				},
+			},
+		},
+		{
+			name: "rename_histogram_buckets_valid",
+			trs: []Rule{
+				{
+					Action:       ActionRenameHistogramBuckets,
+					BucketSuffix: ".bucket",
+				},
+			},
+		},
+		{
+			name: "rename_histogram_buckets_no_suffix",
+			trs: []Rule{
+				{
+					Action: ActionRenameHistogramBuckets,
+				},
+			},
+			wantError: `field "bucket_suffix" is required for "rename_histogram_buckets" translation rule`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1555,6 +1610,96 @@ func TestTranslateDataPoints(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "calculate_histogram_quantiles",
+			trs: []Rule{
+				{
+					Action:    ActionCalculateHistogramQuantiles,
+					Quantiles: []float64{0.5, 0.9, 1},
+				},
+			},
+			dps: histogramDataPoints("hist", "host", "a", []string{"1", "5", "+Inf"}, []int64{2, 2, 0}),
+			want: append(
+				histogramDataPoints("hist", "host", "a", []string{"1", "5", "+Inf"}, []int64{2, 2, 0}),
+				quantileDataPoint("hist_quantile", "host", "a", "0.5", 1),
+				quantileDataPoint("hist_quantile", "host", "a", "0.9", 4.2),
+				quantileDataPoint("hist_quantile", "host", "a", "1", 5),
+			),
+		},
+		{
+			name: "drop_histogram_buckets",
+			trs: []Rule{
+				{
+					Action:      ActionDropHistogramBuckets,
+					MetricNames: map[string]bool{"hist": true},
+				},
+			},
+			dps: append(
+				histogramDataPoints("hist", "host", "a", []string{"1", "+Inf"}, []int64{2, 1}),
+				histogramDataPoints("other", "host", "a", []string{"+Inf"}, []int64{1})...,
+			),
+			want: append(
+				histogramDataPoints("hist", "host", "a", []string{"1", "+Inf"}, []int64{2, 1})[:1],
+				histogramDataPoints("other", "host", "a", []string{"+Inf"}, []int64{1})...,
+			),
+		},
+		{
+			name: "rename_histogram_buckets",
+			trs: []Rule{
+				{
+					Action:       ActionRenameHistogramBuckets,
+					BucketSuffix: ".bucket",
+				},
+			},
+			dps: []*sfxpb.DataPoint{
+				{
+					Metric:     "hist_bucket",
+					Timestamp:  msec,
+					MetricType: &cumulativeType,
+					Dimensions: []*sfxpb.Dimension{
+						{
+							Key:   "upper_bound",
+							Value: "+Inf",
+						},
+					},
+					Value: sfxpb.Datum{
+						IntValue: generateIntPtr(1),
+					},
+				},
+				{
+					Metric:     "not_a_histogram_bucket",
+					Timestamp:  msec,
+					MetricType: &gaugeType,
+					Value: sfxpb.Datum{
+						IntValue: generateIntPtr(1),
+					},
+				},
+			},
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "hist.bucket",
+					Timestamp:  msec,
+					MetricType: &cumulativeType,
+					Dimensions: []*sfxpb.Dimension{
+						{
+							Key:   "upper_bound",
+							Value: "+Inf",
+						},
+					},
+					Value: sfxpb.Datum{
+						IntValue: generateIntPtr(1),
+					},
+				},
+				{
+					Metric:     "not_a_histogram_bucket",
+					Timestamp:  msec,
+					MetricType: &gaugeType,
+					Value: sfxpb.Datum{
+						IntValue: generateIntPtr(1),
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1567,6 +1712,116 @@ func TestTranslateDataPoints(t *testing.T) {
 	}
 }
 
+var cumulativeType = sfxpb.MetricType_CUMULATIVE_COUNTER
+
+// histogramDataPoints returns the datapoints of a histogram as converted by the
+// exporter, with a dimension and the given buckets.
+func histogramDataPoints(name, dimKey, dimValue string, bounds []string, counts []int64) []*sfxpb.DataPoint {
+	var total int64
+	for _, c := range counts {
+		total += c
+	}
+	dps := []*sfxpb.DataPoint{
+		{
+			Metric:     name + "_count",
+			Timestamp:  msec,
+			MetricType: &cumulativeType,
+			Dimensions: []*sfxpb.Dimension{{Key: dimKey, Value: dimValue}},
+			Value:      sfxpb.Datum{IntValue: generateIntPtr(int(total))},
+		},
+	}
+	for i, bound := range bounds {
+		dps = append(dps, &sfxpb.DataPoint{
+			Metric:     name + "_bucket",
+			Timestamp:  msec,
+			MetricType: &cumulativeType,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: dimKey, Value: dimValue},
+				{Key: "upper_bound", Value: bound},
+			},
+			Value: sfxpb.Datum{IntValue: generateIntPtr(int(counts[i]))},
+		})
+	}
+	return dps
+}
+
+func quantileDataPoint(name, dimKey, dimValue, quantile string, value float64) *sfxpb.DataPoint {
+	return &sfxpb.DataPoint{
+		Metric:     name,
+		Timestamp:  msec,
+		MetricType: &gaugeType,
+		Dimensions: []*sfxpb.Dimension{
+			{Key: dimKey, Value: dimValue},
+			{Key: "quantile", Value: quantile},
+		},
+		Value: sfxpb.Datum{DoubleValue: generateFloatPtr(value)},
+	}
+}
+
+func TestHistogramBucketsQuantile(t *testing.T) {
+	tests := []struct {
+		name      string
+		bounds    []float64
+		counts    []int64
+		q         float64
+		want      float64
+		wantFound bool
+	}{
+		{
+			name:      "first_bucket",
+			bounds:    []float64{10, 20, math.Inf(1)},
+			counts:    []int64{4, 4, 0},
+			q:         0.25,
+			want:      5,
+			wantFound: true,
+		},
+		{
+			name:      "lower_bound_of_first_non_empty_bucket",
+			bounds:    []float64{10, 20, math.Inf(1)},
+			counts:    []int64{0, 4, 0},
+			q:         0,
+			want:      10,
+			wantFound: true,
+		},
+		{
+			name:      "negative_first_bound",
+			bounds:    []float64{-1, 20, math.Inf(1)},
+			counts:    []int64{4, 4, 0},
+			q:         0.25,
+			want:      -1,
+			wantFound: true,
+		},
+		{
+			name:      "infinity_bucket",
+			bounds:    []float64{10, 20, math.Inf(1)},
+			counts:    []int64{1, 1, 2},
+			q:         0.9,
+			want:      20,
+			wantFound: true,
+		},
+		{
+			name:   "only_infinity_bucket",
+			bounds: []float64{math.Inf(1)},
+			counts: []int64{2},
+			q:      0.5,
+		},
+		{
+			name:   "no_values",
+			bounds: []float64{10, math.Inf(1)},
+			counts: []int64{0, 0},
+			q:      0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hb := &histogramBuckets{bounds: tt.bounds, counts: tt.counts}
+			got, found := hb.quantile(tt.q)
+			assert.Equal(t, tt.wantFound, found)
+			assert.InDelta(t, tt.want, got, 0.00000001)
+		})
+	}
+}
+
 func assertEqualPoints(t *testing.T, got []*sfxpb.DataPoint, want []*sfxpb.DataPoint, action Action) {
 	// Handle float values separately
 	for i, dp := range got {
@@ -0,0 +1,94 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+)
+
+const (
+	componentzPath = "/debug/componentz"
+	k8szPath       = "/debug/k8sz"
+	statuszPath    = "/debug/statusz"
+)
+
+type zpagesContribExtension struct {
+	config    Config
+	logger    *zap.Logger
+	collector *statsCollector
+	server    http.Server
+	done      chan struct{}
+}
+
+func (zce *zpagesContribExtension) Start(_ context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(componentzPath, zce.handleComponentz)
+	mux.HandleFunc(k8szPath, zce.handleK8sz)
+	mux.HandleFunc(statuszPath, zce.handleStatusz)
+
+	// Start the listener here so we can have earlier failure if port is
+	// already in use.
+	ln, err := net.Listen("tcp", zce.config.Endpoint)
+	if err != nil {
+		return err
+	}
+
+	zce.collector.sample(time.Now())
+	go zce.sampleLoop()
+
+	zce.logger.Info("Starting contrib zPages extension", zap.Any("config", zce.config))
+	zce.server = http.Server{Handler: mux}
+	go func() {
+		if err := zce.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			host.ReportFatalError(err)
+		}
+	}()
+
+	return nil
+}
+
+// sampleLoop samples the statistics every rate interval until the extension is shut down.
+func (zce *zpagesContribExtension) sampleLoop() {
+	ticker := time.NewTicker(zce.config.RateInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			zce.collector.sample(now)
+		case <-zce.done:
+			return
+		}
+	}
+}
+
+func (zce *zpagesContribExtension) Shutdown(context.Context) error {
+	close(zce.done)
+	return zce.server.Close()
+}
+
+func newServer(config Config, logger *zap.Logger) *zpagesContribExtension {
+	return &zpagesContribExtension{
+		config:    config,
+		logger:    logger,
+		collector: newStatsCollector(),
+		done:      make(chan struct{}),
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func TestZPagesContribExtension(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.RateInterval = time.Second
+
+	zce := newServer(*cfg, zap.NewNop())
+	zce.collector.retrieveData = fakeViews{
+		"receiver/accepted_metric_points": {sumRow(42, "receiver", "statsd")},
+		k8sPodsAddedView:                  {sumRow(3)},
+	}.retrieveData
+
+	require.NoError(t, zce.Start(context.Background(), componenttest.NewNopHost()))
+	defer zce.Shutdown(context.Background())
+
+	body := get(t, "http://"+cfg.Endpoint+componentzPath)
+	assert.Contains(t, body, "<td>receiver</td><td>statsd</td><td>metric points</td>")
+	assert.Contains(t, body, "<td>42</td>")
+	assert.Contains(t, body, "No queue.")
+
+	body = get(t, "http://"+cfg.Endpoint+k8szPath)
+	assert.Contains(t, body, "<tr><td>Cached pods</td><td>3</td></tr>")
+
+	body = get(t, "http://"+cfg.Endpoint+statuszPath)
+	assert.Contains(t, body, "No component reports its status.")
+
+	unregister := RegisterStatus("receiver", "prometheus_exec/mysql", func() []StatusEntry {
+		return []StatusEntry{{Name: "Command", Value: "./mysqld_exporter <9104>"}}
+	})
+	defer unregister()
+	body = get(t, "http://"+cfg.Endpoint+statuszPath)
+	assert.Contains(t, body, "<h2>receiver prometheus_exec/mysql</h2>")
+	assert.Contains(t, body, "<tr><td>Command</td><td><pre>./mysqld_exporter &lt;9104&gt;</pre></td></tr>")
+}
+
+func TestZPagesContribExtensionPortInUse(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+
+	zce := newServer(*cfg, zap.NewNop())
+	require.NoError(t, zce.Start(context.Background(), componenttest.NewNopHost()))
+	defer zce.Shutdown(context.Background())
+
+	other := newServer(*cfg, zap.NewNop())
+	assert.Error(t, other.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func get(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/extension/extensionhelper"
+)
+
+const (
+	// The value of extension "type" in configuration.
+	typeStr configmodels.Type = "zpages_contrib"
+
+	// Default endpoint to bind to.
+	defaultEndpoint = "localhost:55670"
+
+	defaultRateInterval = 10 * time.Second
+)
+
+// NewFactory creates a factory for the contrib zPages extension.
+func NewFactory() component.ExtensionFactory {
+	return extensionhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		createExtension)
+}
+
+func createDefaultConfig() configmodels.Extension {
+	return &Config{
+		ExtensionSettings: configmodels.ExtensionSettings{
+			TypeVal: typeStr,
+			NameVal: string(typeStr),
+		},
+		Endpoint:     defaultEndpoint,
+		RateInterval: defaultRateInterval,
+	}
+}
+
+func createExtension(
+	_ context.Context,
+	params component.ExtensionCreateParams,
+	cfg configmodels.Extension,
+) (component.ServiceExtension, error) {
+	config := cfg.(*Config)
+	if config.Endpoint == "" {
+		return nil, errors.New("\"endpoint\" is required when using the \"zpages_contrib\" extension")
+	}
+	if config.RateInterval <= 0 {
+		return nil, errors.New("\"rate_interval\" must be positive")
+	}
+
+	return newServer(*config, params.Logger), nil
+}
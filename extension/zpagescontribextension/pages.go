@@ -0,0 +1,122 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var templateFuncs = template.FuncMap{
+	"percent": func(ratio float64) float64 { return ratio * 100 },
+}
+
+var componentzTemplate = template.Must(template.New("componentz").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head><title>Pipeline components</title></head>
+<body>
+<h1>Pipeline components</h1>
+<p>Throughputs over the last {{.RateInterval}}, totals since the collector started.</p>
+{{if .Components}}
+<table border="1" cellpadding="4">
+<tr><th>Kind</th><th>Name</th><th>Signal</th><th>OK/s</th><th>Failed/s</th><th>OK</th><th>Failed</th><th>Error rate</th></tr>
+{{range .Components}}
+<tr><td>{{.Kind}}</td><td>{{.Name}}</td><td>{{.Signal}}</td><td>{{printf "%.2f" .OKRate}}</td><td>{{printf "%.2f" .FailedRate}}</td><td>{{printf "%.0f" .OK}}</td><td>{{printf "%.0f" .Failed}}</td><td>{{printf "%.2f" (percent .ErrorRatio)}}%</td></tr>
+{{end}}
+</table>
+{{else}}
+<p>No statistics available, the collector's metrics level may be set to none.</p>
+{{end}}
+<h2>Queues</h2>
+{{if .Queues}}
+<table border="1" cellpadding="4">
+<tr><th>Processor</th><th>Batches in queue</th></tr>
+{{range .Queues}}
+<tr><td>{{.Processor}}</td><td>{{printf "%.0f" .Length}}</td></tr>
+{{end}}
+</table>
+{{else}}
+<p>No queue.</p>
+{{end}}
+</body>
+</html>
+`))
+
+var k8szTemplate = template.Must(template.New("k8sz").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head><title>k8s_tagger cache</title></head>
+<body>
+<h1>k8s_tagger cache</h1>
+{{if .Available}}
+<table border="1" cellpadding="4">
+<tr><td>Cached pods</td><td>{{printf "%.0f" .CachedPods}}</td></tr>
+<tr><td>Pod add events</td><td>{{printf "%.0f" .PodsAdded}}</td></tr>
+<tr><td>Pod update events</td><td>{{printf "%.0f" .PodsUpdated}}</td></tr>
+<tr><td>Pod delete events</td><td>{{printf "%.0f" .PodsDeleted}}</td></tr>
+<tr><td>Pod lookup by IP misses</td><td>{{printf "%.0f" .IPLookupMiss}}</td></tr>
+</table>
+{{else}}
+<p>The k8s_tagger processor is not available in this collector.</p>
+{{end}}
+</body>
+</html>
+`))
+
+var statuszTemplate = template.Must(template.New("statusz").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html>
+<head><title>Component status</title></head>
+<body>
+<h1>Component status</h1>
+{{range .}}
+<h2>{{.Kind}} {{.Name}}</h2>
+<table border="1" cellpadding="4">
+{{range .Entries}}
+<tr><td>{{.Name}}</td><td><pre>{{.Value}}</pre></td></tr>
+{{end}}
+</table>
+{{else}}
+<p>No component reports its status.</p>
+{{end}}
+</body>
+</html>
+`))
+
+func (zce *zpagesContribExtension) handleComponentz(w http.ResponseWriter, _ *http.Request) {
+	data := struct {
+		RateInterval string
+		Components   []componentStats
+		Queues       []queueStats
+	}{
+		RateInterval: zce.config.RateInterval.String(),
+		Components:   zce.collector.componentStats(),
+		Queues:       zce.collector.queueStats(),
+	}
+	zce.render(w, componentzTemplate, data)
+}
+
+func (zce *zpagesContribExtension) handleK8sz(w http.ResponseWriter, _ *http.Request) {
+	zce.render(w, k8szTemplate, zce.collector.k8sStats())
+}
+
+func (zce *zpagesContribExtension) handleStatusz(w http.ResponseWriter, _ *http.Request) {
+	zce.render(w, statuszTemplate, statuses.componentStatuses())
+}
+
+func (zce *zpagesContribExtension) render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/obsreport"
+)
+
+const (
+	signalSpans        = "spans"
+	signalMetricPoints = "metric points"
+	signalLogRecords   = "log records"
+
+	// The k8s_tagger processor views, see processor/k8sprocessor/observability.
+	k8sPodsAddedView    = "otelsvc/k8s/pod_added"
+	k8sPodsUpdatedView  = "otelsvc/k8s/pod_updated"
+	k8sPodsDeletedView  = "otelsvc/k8s/pod_deleted"
+	k8sIPLookupMissView = "otelsvc/k8s/ip_lookup_miss"
+)
+
+// queueLengthViews are the names of the view of the queued_retry processors' queue
+// length, with and without the processor prefix.
+var queueLengthViews = []string{"processor/queued_retry/queue_length", "queue_length"}
+
+// pipelineView pairs the obsreport views of the items successfully handled by a kind of
+// component and of the items it failed to handle.
+type pipelineView struct {
+	kind        string
+	signal      string
+	okView      string
+	failedViews []string
+}
+
+func newPipelineView(kind, signal, okKey string, failedKeys ...string) pipelineView {
+	pv := pipelineView{kind: kind, signal: signal, okView: kind + "/" + okKey}
+	for _, key := range failedKeys {
+		pv.failedViews = append(pv.failedViews, kind+"/"+key)
+	}
+	return pv
+}
+
+var pipelineViews = []pipelineView{
+	newPipelineView(obsreport.ReceiverKey, signalSpans, obsreport.AcceptedSpansKey, obsreport.RefusedSpansKey),
+	newPipelineView(obsreport.ReceiverKey, signalMetricPoints, obsreport.AcceptedMetricPointsKey, obsreport.RefusedMetricPointsKey),
+	newPipelineView(obsreport.ReceiverKey, signalLogRecords, obsreport.AcceptedLogRecordsKey, obsreport.RefusedLogRecordsKey),
+	newPipelineView(obsreport.ProcessorKey, signalSpans, obsreport.AcceptedSpansKey, obsreport.RefusedSpansKey, obsreport.DroppedSpansKey),
+	newPipelineView(obsreport.ProcessorKey, signalMetricPoints, obsreport.AcceptedMetricPointsKey, obsreport.RefusedMetricPointsKey, obsreport.DroppedMetricPointsKey),
+	newPipelineView(obsreport.ProcessorKey, signalLogRecords, obsreport.AcceptedLogRecordsKey, obsreport.RefusedLogRecordsKey, obsreport.DroppedLogRecordsKey),
+	newPipelineView(obsreport.ExporterKey, signalSpans, obsreport.SentSpansKey, obsreport.FailedToSendSpansKey),
+	newPipelineView(obsreport.ExporterKey, signalMetricPoints, obsreport.SentMetricPointsKey, obsreport.FailedToSendMetricPointsKey),
+	newPipelineView(obsreport.ExporterKey, signalLogRecords, obsreport.SentLogRecordsKey, obsreport.FailedToSendLogRecordsKey),
+}
+
+// componentKey identifies the items of a signal handled by a component.
+type componentKey struct {
+	kind   string
+	name   string
+	signal string
+}
+
+// counts are the items successfully handled, and failed to be handled, by a component.
+type counts struct {
+	ok     float64
+	failed float64
+}
+
+// sample holds the cumulative counts of all the components at a point in time.
+type sample struct {
+	time       time.Time
+	components map[componentKey]counts
+}
+
+// componentStats are the statistics of the items of a signal handled by a component.
+type componentStats struct {
+	Kind   string
+	Name   string
+	Signal string
+	// OK and Failed are the items successfully handled and failed to be handled since
+	// the collector started.
+	OK     float64
+	Failed float64
+	// OKRate and FailedRate are the per second rates over the last interval.
+	OKRate     float64
+	FailedRate float64
+}
+
+// ErrorRatio is the share of the items which failed to be handled since the collector started.
+func (cs componentStats) ErrorRatio() float64 {
+	if cs.OK+cs.Failed == 0 {
+		return 0
+	}
+	return cs.Failed / (cs.OK + cs.Failed)
+}
+
+// queueStats is the current length of the queue of a processor.
+type queueStats struct {
+	Processor string
+	Length    float64
+}
+
+// k8sStats summarizes the pod cache of the k8s_tagger processors.
+type k8sStats struct {
+	Available    bool
+	PodsAdded    float64
+	PodsUpdated  float64
+	PodsDeleted  float64
+	IPLookupMiss float64
+}
+
+// CachedPods is the approximate number of pods held by the caches.
+func (ks k8sStats) CachedPods() float64 {
+	return ks.PodsAdded - ks.PodsDeleted
+}
+
+// statsCollector samples the views registered by the collector's components, keeping the
+// last two samples to compute the throughputs.
+type statsCollector struct {
+	retrieveData func(viewName string) ([]*view.Row, error)
+
+	mu       sync.Mutex
+	previous *sample
+	last     *sample
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{retrieveData: view.RetrieveData}
+}
+
+// sample takes a new sample of the counts of all the components.
+func (sc *statsCollector) sample(now time.Time) {
+	s := &sample{time: now, components: map[componentKey]counts{}}
+	for _, pv := range pipelineViews {
+		for _, row := range sc.rows(pv.okView) {
+			key := componentKey{kind: pv.kind, name: tagValue(row, pv.kind), signal: pv.signal}
+			c := s.components[key]
+			c.ok += rowValue(row)
+			s.components[key] = c
+		}
+		for _, failedView := range pv.failedViews {
+			for _, row := range sc.rows(failedView) {
+				key := componentKey{kind: pv.kind, name: tagValue(row, pv.kind), signal: pv.signal}
+				c := s.components[key]
+				c.failed += rowValue(row)
+				s.components[key] = c
+			}
+		}
+	}
+
+	sc.mu.Lock()
+	sc.previous, sc.last = sc.last, s
+	sc.mu.Unlock()
+}
+
+// componentStats returns the statistics of the last sample, sorted by kind, name and signal.
+func (sc *statsCollector) componentStats() []componentStats {
+	sc.mu.Lock()
+	previous, last := sc.previous, sc.last
+	sc.mu.Unlock()
+	if last == nil {
+		return nil
+	}
+
+	var elapsed float64
+	if previous != nil {
+		elapsed = last.time.Sub(previous.time).Seconds()
+	}
+
+	stats := make([]componentStats, 0, len(last.components))
+	for key, c := range last.components {
+		cs := componentStats{Kind: key.kind, Name: key.name, Signal: key.signal, OK: c.ok, Failed: c.failed}
+		if elapsed > 0 {
+			p := previous.components[key]
+			cs.OKRate = (c.ok - p.ok) / elapsed
+			cs.FailedRate = (c.failed - p.failed) / elapsed
+		}
+		stats = append(stats, cs)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Kind != stats[j].Kind {
+			return kindOrder(stats[i].Kind) < kindOrder(stats[j].Kind)
+		}
+		if stats[i].Name != stats[j].Name {
+			return stats[i].Name < stats[j].Name
+		}
+		return stats[i].Signal < stats[j].Signal
+	})
+	return stats
+}
+
+// queueStats returns the current queue lengths of the processors, sorted by processor.
+func (sc *statsCollector) queueStats() []queueStats {
+	var stats []queueStats
+	for _, name := range queueLengthViews {
+		for _, row := range sc.rows(name) {
+			stats = append(stats, queueStats{Processor: tagValue(row, obsreport.ProcessorKey), Length: rowValue(row)})
+		}
+		if len(stats) > 0 {
+			break
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Processor < stats[j].Processor })
+	return stats
+}
+
+// k8sStats returns the summary of the k8s_tagger pod caches, not available if no
+// k8s_tagger processor is built in the collector.
+func (sc *statsCollector) k8sStats() k8sStats {
+	var ks k8sStats
+	for _, v := range []struct {
+		name  string
+		value *float64
+	}{
+		{k8sPodsAddedView, &ks.PodsAdded},
+		{k8sPodsUpdatedView, &ks.PodsUpdated},
+		{k8sPodsDeletedView, &ks.PodsDeleted},
+		{k8sIPLookupMissView, &ks.IPLookupMiss},
+	} {
+		rows, err := sc.retrieveData(v.name)
+		if err != nil {
+			continue
+		}
+		ks.Available = true
+		for _, row := range rows {
+			*v.value += rowValue(row)
+		}
+	}
+	return ks
+}
+
+// rows returns the rows of a view, none if the view isn't registered.
+func (sc *statsCollector) rows(viewName string) []*view.Row {
+	rows, err := sc.retrieveData(viewName)
+	if err != nil {
+		return nil
+	}
+	return rows
+}
+
+func tagValue(row *view.Row, key string) string {
+	for _, t := range row.Tags {
+		if t.Key.Name() == key {
+			return t.Value
+		}
+	}
+	return ""
+}
+
+func rowValue(row *view.Row) float64 {
+	switch data := row.Data.(type) {
+	case *view.SumData:
+		return data.Value
+	case *view.CountData:
+		return float64(data.Value)
+	case *view.LastValueData:
+		return data.Value
+	}
+	return 0
+}
+
+func kindOrder(kind string) int {
+	switch kind {
+	case obsreport.ReceiverKey:
+		return 0
+	case obsreport.ProcessorKey:
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// fakeViews are the rows of the registered views, by view name.
+type fakeViews map[string][]*view.Row
+
+func (fv fakeViews) retrieveData(viewName string) ([]*view.Row, error) {
+	rows, ok := fv[viewName]
+	if !ok {
+		return nil, errors.New("cannot retrieve data; view is not registered")
+	}
+	return rows, nil
+}
+
+func sumRow(value float64, tags ...string) *view.Row {
+	row := &view.Row{Data: &view.SumData{Value: value}}
+	for i := 0; i+1 < len(tags); i += 2 {
+		row.Tags = append(row.Tags, tag.Tag{Key: tag.MustNewKey(tags[i]), Value: tags[i+1]})
+	}
+	return row
+}
+
+func TestComponentStats(t *testing.T) {
+	views := fakeViews{
+		"receiver/accepted_spans": {
+			sumRow(100, "receiver", "otlp", "transport", "grpc"),
+			sumRow(50, "receiver", "otlp", "transport", "http"),
+		},
+		"receiver/refused_spans":   {sumRow(10, "receiver", "otlp", "transport", "grpc")},
+		"exporter/sent_spans":      {sumRow(140, "exporter", "jaeger")},
+		"processor/dropped_spans":  {sumRow(5, "processor", "batch")},
+		"processor/accepted_spans": {sumRow(145, "processor", "batch")},
+	}
+	sc := &statsCollector{retrieveData: views.retrieveData}
+	assert.Nil(t, sc.componentStats())
+
+	start := time.Now()
+	sc.sample(start)
+	assert.Equal(t, []componentStats{
+		{Kind: "receiver", Name: "otlp", Signal: signalSpans, OK: 150, Failed: 10},
+		{Kind: "processor", Name: "batch", Signal: signalSpans, OK: 145, Failed: 5},
+		{Kind: "exporter", Name: "jaeger", Signal: signalSpans, OK: 140},
+	}, sc.componentStats())
+
+	views["receiver/accepted_spans"][0] = sumRow(200, "receiver", "otlp", "transport", "grpc")
+	views["exporter/send_failed_spans"] = []*view.Row{sumRow(20, "exporter", "jaeger")}
+	sc.sample(start.Add(10 * time.Second))
+
+	stats := sc.componentStats()
+	assert.Equal(t, []componentStats{
+		{Kind: "receiver", Name: "otlp", Signal: signalSpans, OK: 250, Failed: 10, OKRate: 10},
+		{Kind: "processor", Name: "batch", Signal: signalSpans, OK: 145, Failed: 5},
+		{Kind: "exporter", Name: "jaeger", Signal: signalSpans, OK: 140, Failed: 20, FailedRate: 2},
+	}, stats)
+	assert.Equal(t, 0.125, stats[2].ErrorRatio())
+	assert.Equal(t, float64(0), componentStats{}.ErrorRatio())
+}
+
+func TestQueueStats(t *testing.T) {
+	views := fakeViews{
+		"queue_length": {
+			{Tags: []tag.Tag{{Key: tag.MustNewKey("processor"), Value: "queued_retry/2"}}, Data: &view.LastValueData{Value: 3}},
+			{Tags: []tag.Tag{{Key: tag.MustNewKey("processor"), Value: "queued_retry"}}, Data: &view.LastValueData{Value: 7}},
+		},
+	}
+	sc := &statsCollector{retrieveData: views.retrieveData}
+	assert.Equal(t, []queueStats{
+		{Processor: "queued_retry", Length: 7},
+		{Processor: "queued_retry/2", Length: 3},
+	}, sc.queueStats())
+
+	sc = &statsCollector{retrieveData: fakeViews{}.retrieveData}
+	assert.Nil(t, sc.queueStats())
+}
+
+func TestK8sStats(t *testing.T) {
+	sc := &statsCollector{retrieveData: fakeViews{}.retrieveData}
+	assert.Equal(t, k8sStats{}, sc.k8sStats())
+
+	views := fakeViews{
+		k8sPodsAddedView:    {sumRow(12)},
+		k8sPodsUpdatedView:  {sumRow(30)},
+		k8sPodsDeletedView:  {sumRow(2)},
+		k8sIPLookupMissView: {},
+	}
+	sc = &statsCollector{retrieveData: views.retrieveData}
+	ks := sc.k8sStats()
+	assert.Equal(t, k8sStats{Available: true, PodsAdded: 12, PodsUpdated: 30, PodsDeleted: 2}, ks)
+	assert.Equal(t, float64(10), ks.CachedPods())
+}
@@ -0,0 +1,101 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"sort"
+	"sync"
+)
+
+// StatusEntry is a named value of the status of a component.
+type StatusEntry struct {
+	Name  string
+	Value string
+}
+
+// StatusFunc returns the current status of a component. It is called each
+// time the page is served, and must be safe for concurrent use.
+type StatusFunc func() []StatusEntry
+
+// componentStatus is the status of a component, as served on the page.
+type componentStatus struct {
+	Kind    string
+	Name    string
+	Entries []StatusEntry
+}
+
+type registeredStatus struct {
+	kind   string
+	name   string
+	status StatusFunc
+}
+
+// statusRegistry holds the status functions registered by the components.
+type statusRegistry struct {
+	mu       sync.Mutex
+	nextID   int
+	statuses map[int]registeredStatus
+}
+
+var statuses = &statusRegistry{statuses: make(map[int]registeredStatus)}
+
+// RegisterStatus adds the status of the component of the given kind (e.g.
+// "receiver") and name to the /debug/statusz page, until the returned
+// function is called. The status is shown by all the zpages_contrib
+// extensions of the collector.
+func RegisterStatus(kind, name string, status StatusFunc) (unregister func()) {
+	return statuses.register(kind, name, status)
+}
+
+func (r *statusRegistry) register(kind, name string, status StatusFunc) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextID
+	r.nextID++
+	r.statuses[id] = registeredStatus{kind: kind, name: name, status: status}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			delete(r.statuses, id)
+		})
+	}
+}
+
+// componentStatuses returns the current status of the registered components,
+// sorted by kind and name.
+func (r *statusRegistry) componentStatuses() []componentStatus {
+	r.mu.Lock()
+	registered := make([]registeredStatus, 0, len(r.statuses))
+	for _, rs := range r.statuses {
+		registered = append(registered, rs)
+	}
+	r.mu.Unlock()
+
+	result := make([]componentStatus, 0, len(registered))
+	for _, rs := range registered {
+		result = append(result, componentStatus{Kind: rs.kind, Name: rs.name, Entries: rs.status()})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
@@ -0,0 +1,46 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zpagescontribextension
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusRegistry(t *testing.T) {
+	r := &statusRegistry{statuses: make(map[int]registeredStatus)}
+	assert.Empty(t, r.componentStatuses())
+
+	status := func(value string) StatusFunc {
+		return func() []StatusEntry { return []StatusEntry{{Name: "Port", Value: value}} }
+	}
+	unregisterB := r.register("receiver", "prometheus_exec/b", status("9104"))
+	unregisterA := r.register("receiver", "prometheus_exec/a", status("9100"))
+	r.register("exporter", "prometheus", status("9090"))
+
+	assert.Equal(t, []componentStatus{
+		{Kind: "exporter", Name: "prometheus", Entries: []StatusEntry{{Name: "Port", Value: "9090"}}},
+		{Kind: "receiver", Name: "prometheus_exec/a", Entries: []StatusEntry{{Name: "Port", Value: "9100"}}},
+		{Kind: "receiver", Name: "prometheus_exec/b", Entries: []StatusEntry{{Name: "Port", Value: "9104"}}},
+	}, r.componentStatuses())
+
+	unregisterB()
+	unregisterB()
+	unregisterA()
+	assert.Equal(t, []componentStatus{
+		{Kind: "exporter", Name: "prometheus", Entries: []StatusEntry{{Name: "Port", Value: "9090"}}},
+	}, r.componentStatuses())
+}
@@ -46,6 +46,10 @@ const (
 	// will be AWSXraySegmentMetadataAttributePrefix + <metadata_key>.
 	AWSXraySegmentMetadataAttributePrefix = "aws.xray.metadata."
 
+	// AWSXRayRawAttribute is the JSON object of the fields of an X-Ray
+	// (sub)segment which are not part of the segment document schema.
+	AWSXRayRawAttribute = "aws.xray.raw"
+
 	// AWSXrayRetriesAttribute is the `retries` field in an X-Ray (sub)segment.
 	AWSXrayRetriesAttribute = "aws.xray.retries"
 
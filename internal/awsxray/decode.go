@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxPooledSubsegments is the capacity of the embedded subsegments beyond
+// which the subsegments of a released segment are not kept for reuse, so
+// that a few huge segments don't pin their memory in the pool.
+const maxPooledSubsegments = 64
+
+var segmentPool = sync.Pool{
+	New: func() interface{} {
+		return new(Segment)
+	},
+}
+
+// UnmarshalSegment unmarshals the segment document into a segment of a pool,
+// reusing the memory of the segments released with ReleaseSegment to reduce
+// the allocations at high ingest rates. The segment must not be used once
+// released, the strings it holds stay valid.
+func UnmarshalSegment(document []byte) (*Segment, error) {
+	seg := segmentPool.Get().(*Segment)
+	if err := json.Unmarshal(document, seg); err != nil {
+		ReleaseSegment(seg)
+		return nil, err
+	}
+	return seg, nil
+}
+
+// ReleaseSegment returns the segment to the pool of UnmarshalSegment.
+func ReleaseSegment(seg *Segment) {
+	seg.reset()
+	segmentPool.Put(seg)
+}
+
+// reset zeroes the segment, keeping the memory of its subsegments.
+func (s *Segment) reset() {
+	// encoding/json decodes into the existing elements of the slices and
+	// into the existing maps and pointed values, everything but the
+	// zeroed subsegments is dropped so that nothing leaks into the next
+	// segment.
+	subsegments := s.Subsegments[:cap(s.Subsegments)]
+	if cap(subsegments) > maxPooledSubsegments {
+		subsegments = nil
+	}
+	for i := range subsegments {
+		subsegments[i] = Segment{}
+	}
+	*s = Segment{Subsegments: subsegments[:0]}
+}
+
+// DocumentsDecoder reads the segment documents of a JSON array one at a time,
+// without holding the whole array in memory. Each document is either a
+// document serialized as a string, as in the TraceSegmentDocuments of the
+// PutTraceSegments API, or a document object.
+type DocumentsDecoder struct {
+	decoder *json.Decoder
+	started bool
+}
+
+// NewDocumentsDecoder returns a decoder reading a JSON array of segment
+// documents from r.
+func NewDocumentsDecoder(r io.Reader) *DocumentsDecoder {
+	return &DocumentsDecoder{decoder: json.NewDecoder(r)}
+}
+
+// Next returns the next document of the array, or io.EOF once the whole
+// array was read. The documents are not validated, only their type.
+func (d *DocumentsDecoder) Next() ([]byte, error) {
+	if !d.started {
+		token, err := d.decoder.Token()
+		if err != nil {
+			return nil, arrayError(err)
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return nil, arrayError(fmt.Errorf("unexpected %v", token))
+		}
+		d.started = true
+	}
+
+	if !d.decoder.More() {
+		if _, err := d.decoder.Token(); err != nil {
+			return nil, arrayError(err)
+		}
+		if _, err := d.decoder.Token(); err != io.EOF {
+			return nil, arrayError(errors.New("unexpected data after the array"))
+		}
+		return nil, io.EOF
+	}
+
+	var element json.RawMessage
+	if err := d.decoder.Decode(&element); err != nil {
+		return nil, arrayError(err)
+	}
+	element = bytes.TrimSpace(element)
+	if len(element) > 0 && element[0] == '"' {
+		var document string
+		if err := json.Unmarshal(element, &document); err != nil {
+			return nil, err
+		}
+		return []byte(document), nil
+	}
+	if len(element) == 0 || element[0] != '{' {
+		return nil, errors.New("each segment document must be a JSON object or a string")
+	}
+	return element, nil
+}
+
+func arrayError(err error) error {
+	return fmt.Errorf("the documents must be a JSON array: %w", err)
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxray
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestSegment(t testing.TB, name string) []byte {
+	content, err := ioutil.ReadFile(path.Join("testdata", name))
+	require.NoError(t, err)
+	return content
+}
+
+func TestUnmarshalSegment(t *testing.T) {
+	for _, name := range []string{"ddbSample.txt", "serverSample.txt", "minCauseIsExceptionId.txt"} {
+		content := readTestSegment(t, name)
+		var expected Segment
+		require.NoError(t, json.Unmarshal(content, &expected))
+
+		seg, err := UnmarshalSegment(content)
+		require.NoError(t, err)
+		if len(seg.Subsegments) == 0 {
+			// an empty slice may be reused from the pool
+			seg.Subsegments = nil
+		}
+		assert.Equal(t, &expected, seg, name)
+		ReleaseSegment(seg)
+	}
+
+	_, err := UnmarshalSegment([]byte(`{"name":`))
+	assert.Error(t, err)
+}
+
+func TestResetSegment(t *testing.T) {
+	seg := &Segment{}
+	require.NoError(t, json.Unmarshal(readTestSegment(t, "ddbSample.txt"), seg))
+	subsegments := cap(seg.Subsegments)
+	require.NotZero(t, subsegments)
+
+	seg.reset()
+	assert.Equal(t, Segment{Subsegments: []Segment{}}, *seg)
+	assert.Equal(t, subsegments, cap(seg.Subsegments), "the subsegments should be kept for reuse")
+
+	// nothing of the previous segment leaks into the next one
+	content := readTestSegment(t, "serverSample.txt")
+	var expected Segment
+	require.NoError(t, json.Unmarshal(content, &expected))
+	require.NoError(t, json.Unmarshal(content, seg))
+	seg.Subsegments = nil
+	assert.Equal(t, expected, *seg)
+
+	seg = &Segment{Subsegments: make([]Segment, maxPooledSubsegments+1)}
+	seg.reset()
+	assert.Zero(t, cap(seg.Subsegments), "too many subsegments should not be kept")
+}
+
+func TestDocumentsDecoder(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "strings",
+			body: `["{\"id\":\"a\"}", "{\"id\":\"b\"}"]`,
+			want: []string{`{"id":"a"}`, `{"id":"b"}`},
+		},
+		{
+			name: "objects",
+			body: ` [ {"id":"a"}, {"id": "b"} ] `,
+			want: []string{`{"id":"a"}`, `{"id": "b"}`},
+		},
+		{
+			name: "empty",
+			body: `[]`,
+		},
+		{
+			name:    "not an array",
+			body:    `{"id":"a"}`,
+			wantErr: true,
+		},
+		{
+			name:    "number",
+			body:    `[{"id":"a"}, 42]`,
+			want:    []string{`{"id":"a"}`},
+			wantErr: true,
+		},
+		{
+			name:    "truncated",
+			body:    `[{"id":"a"}, {"id":`,
+			want:    []string{`{"id":"a"}`},
+			wantErr: true,
+		},
+		{
+			name:    "unterminated",
+			body:    `[{"id":"a"}`,
+			want:    []string{`{"id":"a"}`},
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			body:    `[{"id":"a"}] []`,
+			want:    []string{`{"id":"a"}`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := NewDocumentsDecoder(strings.NewReader(tt.body))
+			var got []string
+			var err error
+			for {
+				var document []byte
+				document, err = decoder.Next()
+				if err != nil {
+					break
+				}
+				got = append(got, string(document))
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.NotEqual(t, io.EOF, err)
+			} else {
+				assert.Equal(t, io.EOF, err)
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshalSegment(b *testing.B) {
+	content := readTestSegment(b, "ddbSample.txt")
+	b.Run("json", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var seg Segment
+			if err := json.Unmarshal(content, &seg); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			seg, err := UnmarshalSegment(content)
+			if err != nil {
+				b.Fatal(err)
+			}
+			ReleaseSegment(seg)
+		}
+	})
+}
+
+func BenchmarkDocumentsDecoder(b *testing.B) {
+	document := string(readTestSegment(b, "ddbSample.txt"))
+	body := "[" + strings.TrimSuffix(strings.Repeat(document+",", 50), ",") + "]"
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		decoder := NewDocumentsDecoder(strings.NewReader(body))
+		for {
+			if _, err := decoder.Next(); err == io.EOF {
+				break
+			} else if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
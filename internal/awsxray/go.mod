@@ -5,4 +5,5 @@ go 1.14
 require (
 	github.com/aws/aws-sdk-go v1.34.22
 	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
 )
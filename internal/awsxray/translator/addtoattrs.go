@@ -15,21 +15,17 @@
 package translator
 
 import (
-	"encoding/json"
-
 	"go.opentelemetry.io/collector/consumer/pdata"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
 
-func addMetadata(meta map[string]map[string]interface{}, attrs *pdata.AttributeMap) error {
-	for k, v := range meta {
-		val, err := json.Marshal(v)
-		if err != nil {
-			return err
-		}
-		attrs.UpsertString(
-			awsxray.AWSXraySegmentMetadataAttributePrefix+k, string(val))
+func addString(val *string, attrKey string, attrs *pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertString(attrKey, *val)
+	}
+}
+
+func addInt64(val *int64, attrKey string, attrs *pdata.AttributeMap) {
+	if val != nil {
+		attrs.UpsertInt(attrKey, *val)
 	}
-	return nil
 }
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// AddAnnotations converts the annotations of an X-Ray segment to the span
+// attributes, keeping the type of their values.
+func AddAnnotations(annos map[string]interface{}, attrs *pdata.AttributeMap) {
+	for k, v := range annos {
+		switch t := v.(type) {
+		case int:
+			attrs.UpsertInt(k, int64(t))
+		case int32:
+			attrs.UpsertInt(k, int64(t))
+		case int64:
+			attrs.UpsertInt(k, t)
+		case string:
+			attrs.UpsertString(k, t)
+		case bool:
+			attrs.UpsertBool(k, t)
+		case float32:
+			attrs.UpsertDouble(k, float64(t))
+		case float64:
+			attrs.UpsertDouble(k, t)
+		default:
+		}
+	}
+}
+
+// AnnotationValue returns the value of the X-Ray annotation made from the span
+// attribute value. X-Ray annotations are strings, numbers or booleans, the other
+// attribute values are converted to strings.
+func AnnotationValue(value pdata.AttributeValue) interface{} {
+	switch value.Type() {
+	case pdata.AttributeValueSTRING:
+		return value.StringVal()
+	case pdata.AttributeValueINT:
+		return value.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return value.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return value.BoolVal()
+	default:
+		return tracetranslator.AttributeValueToString(value, false)
+	}
+}
+
+// FixAnnotationKey removes any invalid characters from the annotaiton key.  AWS X-Ray defines
+// the list of valid characters here:
+// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
+func FixAnnotationKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case '0' <= r && r <= '9':
+			fallthrough
+		case 'A' <= r && r <= 'Z':
+			fallthrough
+		case 'a' <= r && r <= 'z':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+}
@@ -31,8 +31,8 @@ func TestAddAnnotations(t *testing.T) {
 	input["float64"] = float64(5.5)
 
 	attrMap := pdata.NewAttributeMap()
-	attrMap.InitEmptyWithCapacity(initAttrCapacity)
-	addAnnotations(input, &attrMap)
+	attrMap.InitEmptyWithCapacity(len(input))
+	AddAnnotations(input, &attrMap)
 
 	expectedAttrMap := pdata.NewAttributeMap()
 	expectedAttrMap.InitFromMap(
@@ -47,3 +47,12 @@ func TestAddAnnotations(t *testing.T) {
 	)
 	assert.Equal(t, expectedAttrMap.Sort(), attrMap.Sort(), "attribute maps differ")
 }
+
+func TestFixAnnotationKey(t *testing.T) {
+	validKey := "Key_1"
+	fixedKey := FixAnnotationKey(validKey)
+	assert.Equal(t, validKey, fixedKey)
+	invalidKey := "Key@1"
+	fixedKey = FixAnnotationKey(invalidKey)
+	assert.Equal(t, "Key_1", fixedKey)
+}
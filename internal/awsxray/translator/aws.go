@@ -24,7 +24,10 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
 
-func makeAws(attributes map[string]string, resource pdata.Resource) (map[string]string, *awsxray.AWSData) {
+// MakeAWS converts the AWS resource and span attributes to the aws field of
+// an X-Ray segment, returning the span attributes left to convert. It returns
+// a nil AWSData if the resource is not from an AWS cloud provider.
+func MakeAWS(attributes map[string]string, resource pdata.Resource) (map[string]string, *awsxray.AWSData) {
 	var (
 		cloud        string
 		account      string
@@ -41,6 +44,7 @@ func makeAws(attributes map[string]string, resource pdata.Resource) (map[string]
 		requestID    string
 		queueURL     string
 		tableName    string
+		retries      *int64
 		sdk          string
 		sdkName      string
 		sdkLanguage  string
@@ -113,6 +117,10 @@ func makeAws(attributes map[string]string, resource pdata.Resource) (map[string]
 			fallthrough
 		case awsxray.AWSTableNameAttribute2:
 			tableName = value
+		case awsxray.AWSXrayRetriesAttribute:
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				retries = aws.Int64(n)
+			}
 		default:
 			filtered[key] = value
 		}
@@ -172,6 +180,56 @@ func makeAws(attributes map[string]string, resource pdata.Resource) (map[string]
 		RequestID:    awsxray.String(requestID),
 		QueueURL:     awsxray.String(queueURL),
 		TableName:    awsxray.String(tableName),
+		Retries:      retries,
 	}
 	return filtered, awsData
 }
+
+// AddAWSToResource converts the aws field of an X-Ray segment to the resource
+// attributes, the cloud provider being unknown if the field is not set.
+func AddAWSToResource(awsData *awsxray.AWSData, attrs *pdata.AttributeMap) {
+	if awsData == nil {
+		// this implies that the current segment being processed is not generated
+		// by an AWS entity, see MakeAWS.
+		attrs.UpsertString(semconventions.AttributeCloudProvider, "unknown")
+		return
+	}
+
+	attrs.UpsertString(semconventions.AttributeCloudProvider, "aws")
+	addString(awsData.AccountID, semconventions.AttributeCloudAccount, attrs)
+
+	// based on https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html#api-segmentdocuments-aws
+	// it's possible to have all ec2, ecs and beanstalk fields at the same time.
+	if ec2 := awsData.EC2; ec2 != nil {
+		addString(ec2.AvailabilityZone, semconventions.AttributeCloudZone, attrs)
+		addString(ec2.InstanceID, semconventions.AttributeHostID, attrs)
+		addString(ec2.InstanceSize, semconventions.AttributeHostType, attrs)
+		addString(ec2.AmiID, semconventions.AttributeHostImageID, attrs)
+	}
+
+	if ecs := awsData.ECS; ecs != nil {
+		addString(ecs.ContainerName, semconventions.AttributeContainerName, attrs)
+	}
+
+	if bs := awsData.Beanstalk; bs != nil {
+		addString(bs.Environment, semconventions.AttributeServiceNamespace, attrs)
+		if bs.DeploymentID != nil {
+			attrs.UpsertString(semconventions.AttributeServiceInstance, strconv.FormatInt(*bs.DeploymentID, 10))
+		}
+		addString(bs.VersionLabel, semconventions.AttributeServiceVersion, attrs)
+	}
+}
+
+// AddAWSToSpan converts the aws field of an X-Ray segment that describes the
+// call, such as the operation or the DynamoDB table, to the span attributes.
+func AddAWSToSpan(awsData *awsxray.AWSData, attrs *pdata.AttributeMap) {
+	if awsData != nil {
+		addString(awsData.AccountID, awsxray.AWSAccountAttribute, attrs)
+		addString(awsData.Operation, awsxray.AWSOperationAttribute, attrs)
+		addString(awsData.RemoteRegion, awsxray.AWSRegionAttribute, attrs)
+		addString(awsData.RequestID, awsxray.AWSRequestIDAttribute, attrs)
+		addString(awsData.QueueURL, awsxray.AWSQueueURLAttribute, attrs)
+		addString(awsData.TableName, awsxray.AWSTableNameAttribute, attrs)
+		addInt64(awsData.Retries, awsxray.AWSXrayRetriesAttribute, attrs)
+	}
+}
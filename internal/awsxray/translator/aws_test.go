@@ -42,7 +42,7 @@ func TestAwsFromEc2Resource(t *testing.T) {
 
 	attributes := make(map[string]string)
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -80,7 +80,7 @@ func TestAwsFromEcsResource(t *testing.T) {
 
 	attributes := make(map[string]string)
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -108,7 +108,7 @@ func TestAwsFromBeanstalkResource(t *testing.T) {
 
 	attributes := make(map[string]string)
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -149,7 +149,7 @@ func TestAwsWithAwsSqsResources(t *testing.T) {
 	attributes[awsxray.AWSQueueURLAttribute] = queueURL
 	attributes["employee.id"] = "XB477"
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -162,7 +162,7 @@ func TestAwsWithSqsAlternateAttribute(t *testing.T) {
 	attributes := make(map[string]string)
 	attributes[awsxray.AWSQueueURLAttribute2] = queueURL
 
-	filtered, awsData := makeAws(attributes, pdata.NewResource())
+	filtered, awsData := MakeAWS(attributes, pdata.NewResource())
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -194,7 +194,7 @@ func TestAwsWithAwsDynamoDbResources(t *testing.T) {
 	attributes[awsxray.AWSRequestIDAttribute] = "75107C82-EC8A-4F75-883F-4440B491B0AB"
 	attributes[awsxray.AWSTableNameAttribute] = tableName
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -208,7 +208,7 @@ func TestAwsWithDynamoDbAlternateAttribute(t *testing.T) {
 	attributes := make(map[string]string)
 	attributes[awsxray.AWSTableNameAttribute2] = tableName
 
-	filtered, awsData := makeAws(attributes, pdata.NewResource())
+	filtered, awsData := MakeAWS(attributes, pdata.NewResource())
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -220,7 +220,7 @@ func TestAwsWithRequestIdAlternateAttribute(t *testing.T) {
 	attributes := make(map[string]string)
 	attributes[awsxray.AWSRequestIDAttribute2] = requestid
 
-	filtered, awsData := makeAws(attributes, pdata.NewResource())
+	filtered, awsData := MakeAWS(attributes, pdata.NewResource())
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -235,7 +235,7 @@ func TestJavaSDK(t *testing.T) {
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKLanguage, "java")
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKVersion, "1.2.3")
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -252,7 +252,7 @@ func TestJavaAutoInstrumentation(t *testing.T) {
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKVersion, "1.2.3")
 	resource.Attributes().InsertString(semconventions.AttributeTelemetryAutoVersion, "3.4.5")
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -269,7 +269,7 @@ func TestGoSDK(t *testing.T) {
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKLanguage, "go")
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKVersion, "2.0.3")
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -285,7 +285,7 @@ func TestCustomSDK(t *testing.T) {
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKLanguage, "java")
 	resource.Attributes().InsertString(semconventions.AttributeTelemetrySDKVersion, "2.0.3")
 
-	filtered, awsData := makeAws(attributes, resource)
+	filtered, awsData := MakeAWS(attributes, resource)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, awsData)
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// AddMetadata converts the metadata of an X-Ray segment to span attributes, one
+// per namespace, whose key is the namespace prefixed by
+// awsxray.AWSXraySegmentMetadataAttributePrefix and value the JSON encoded
+// metadata of the namespace.
+func AddMetadata(meta map[string]map[string]interface{}, attrs *pdata.AttributeMap) error {
+	for k, v := range meta {
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		attrs.UpsertString(
+			awsxray.AWSXraySegmentMetadataAttributePrefix+k, string(val))
+	}
+	return nil
+}
+
+// MakeMetadata converts a span attribute made by AddMetadata back to the
+// namespace and the metadata of an X-Ray segment. It returns false if the
+// attribute is not a metadata attribute or its value not a JSON object.
+func MakeMetadata(key string, value pdata.AttributeValue) (string, map[string]interface{}, bool) {
+	if !strings.HasPrefix(key, awsxray.AWSXraySegmentMetadataAttributePrefix) ||
+		value.Type() != pdata.AttributeValueSTRING {
+		return "", nil, false
+	}
+	namespace := strings.TrimPrefix(key, awsxray.AWSXraySegmentMetadataAttributePrefix)
+	if namespace == "" {
+		return "", nil, false
+	}
+	var meta map[string]interface{}
+	if err := json.Unmarshal([]byte(value.StringVal()), &meta); err != nil || meta == nil {
+		return "", nil, false
+	}
+	return namespace, meta, true
+}
@@ -0,0 +1,267 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+	"testing/quick"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	semconventions "go.opentelemetry.io/collector/translator/conventions"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// The round-trip tests convert randomly generated segment fields to pdata the
+// way the receiver does, then back the way the exporter does, and check that
+// nothing is lost or changed along the way.
+
+var quickConfig = &quick.Config{MaxCount: 500}
+
+const idChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+
+// randID returns a non-empty string made of the characters valid in an
+// annotation key.
+func randID(r *rand.Rand) string {
+	b := make([]byte, 1+r.Intn(12))
+	for i := range b {
+		b[i] = idChars[r.Intn(len(idChars))]
+	}
+	return string(b)
+}
+
+// randString returns nil or a non-empty string, MakeAWS and MakeSQL dropping
+// the empty strings.
+func randString(r *rand.Rand) *string {
+	if r.Intn(4) == 0 {
+		return nil
+	}
+	return aws.String(randID(r))
+}
+
+// randScalar returns a value as decoded from a JSON segment document.
+func randScalar(r *rand.Rand) interface{} {
+	switch r.Intn(3) {
+	case 0:
+		return randID(r)
+	case 1:
+		return float64(r.Int63n(1<<53)) / 8
+	default:
+		return r.Intn(2) == 0
+	}
+}
+
+// attributesToMap flattens the span attributes as the exporter does before
+// MakeAWS and MakeSQL.
+func attributesToMap(attrs pdata.AttributeMap) map[string]string {
+	m := make(map[string]string, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		m[k] = tracetranslator.AttributeValueToString(v, false)
+	})
+	return m
+}
+
+type annotationsCase map[string]interface{}
+
+func (annotationsCase) Generate(r *rand.Rand, size int) reflect.Value {
+	annos := annotationsCase{}
+	for i := r.Intn(size + 1); i > 0; i-- {
+		annos[randID(r)] = randScalar(r)
+	}
+	return reflect.ValueOf(annos)
+}
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	roundTrip := func(annos annotationsCase) bool {
+		attrs := pdata.NewAttributeMap()
+		attrs.InitEmptyWithCapacity(len(annos))
+		AddAnnotations(annos, &attrs)
+
+		actual := annotationsCase{}
+		attrs.ForEach(func(k string, v pdata.AttributeValue) {
+			actual[FixAnnotationKey(k)] = AnnotationValue(v)
+		})
+		return reflect.DeepEqual(annos, actual)
+	}
+	assert.NoError(t, quick.Check(roundTrip, quickConfig))
+}
+
+type metadataCase map[string]map[string]interface{}
+
+func randMetadata(r *rand.Rand, size, depth int) map[string]interface{} {
+	meta := map[string]interface{}{}
+	for i := r.Intn(size + 1); i > 0; i-- {
+		switch {
+		case depth > 0 && r.Intn(4) == 0:
+			meta[randID(r)] = randMetadata(r, size/2, depth-1)
+		case r.Intn(8) == 0:
+			meta[randID(r)] = []interface{}{randScalar(r), randScalar(r)}
+		default:
+			meta[randID(r)] = randScalar(r)
+		}
+	}
+	return meta
+}
+
+func (metadataCase) Generate(r *rand.Rand, size int) reflect.Value {
+	meta := metadataCase{}
+	for i := r.Intn(4); i > 0; i-- {
+		meta[randID(r)] = randMetadata(r, size, 2)
+	}
+	return reflect.ValueOf(meta)
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	roundTrip := func(meta metadataCase) bool {
+		attrs := pdata.NewAttributeMap()
+		attrs.InitEmptyWithCapacity(len(meta))
+		if err := AddMetadata(meta, &attrs); err != nil {
+			return false
+		}
+
+		actual := metadataCase{}
+		ok := true
+		attrs.ForEach(func(k string, v pdata.AttributeValue) {
+			namespace, m, isMetadata := MakeMetadata(k, v)
+			ok = ok && isMetadata
+			actual[namespace] = m
+		})
+		return ok && reflect.DeepEqual(meta, actual)
+	}
+	assert.NoError(t, quick.Check(roundTrip, quickConfig))
+}
+
+func TestMakeMetadataIgnoresOtherAttributes(t *testing.T) {
+	_, _, ok := MakeMetadata("http.method", pdata.NewAttributeValueString(`{"a":1}`))
+	assert.False(t, ok)
+	_, _, ok = MakeMetadata(awsxray.AWSXraySegmentMetadataAttributePrefix+"default", pdata.NewAttributeValueString("GET"))
+	assert.False(t, ok)
+	_, _, ok = MakeMetadata(awsxray.AWSXraySegmentMetadataAttributePrefix+"default", pdata.NewAttributeValueInt(1))
+	assert.False(t, ok)
+	_, _, ok = MakeMetadata(awsxray.AWSXraySegmentMetadataAttributePrefix, pdata.NewAttributeValueString(`{"a":1}`))
+	assert.False(t, ok)
+}
+
+type sqlCase awsxray.SQLData
+
+var sqlSystems = []string{"db2", "derby", "hive", "mariadb", "mssql", "mysql", "oracle", "postgresql", "sqlite", "teradata", "other_sql"}
+
+func (sqlCase) Generate(r *rand.Rand, _ int) reflect.Value {
+	// MakeSQL only converts the SQL databases and always sets the url, the
+	// database name not being optional in it.
+	system := sqlSystems[r.Intn(len(sqlSystems))]
+	return reflect.ValueOf(sqlCase{
+		URL:            aws.String(system + "://" + randID(r) + ":" + strconv.Itoa(r.Intn(65536)) + "/" + randID(r)),
+		DatabaseType:   aws.String(system),
+		User:           randString(r),
+		SanitizedQuery: randString(r),
+	})
+}
+
+func TestSQLRoundTrip(t *testing.T) {
+	roundTrip := func(sql sqlCase) bool {
+		expected := awsxray.SQLData(sql)
+		attrs := pdata.NewAttributeMap()
+		attrs.InitEmptyWithCapacity(0)
+		if err := AddSQLToSpan(&expected, &attrs); err != nil {
+			return false
+		}
+
+		filtered, actual := MakeSQL(attributesToMap(attrs))
+		return len(filtered) == 0 && reflect.DeepEqual(&expected, actual)
+	}
+	assert.NoError(t, quick.Check(roundTrip, quickConfig))
+}
+
+type awsCase awsxray.AWSData
+
+func (awsCase) Generate(r *rand.Rand, _ int) reflect.Value {
+	data := awsCase{
+		AccountID:    randString(r),
+		Operation:    randString(r),
+		RemoteRegion: randString(r),
+		RequestID:    randString(r),
+		QueueURL:     randString(r),
+		TableName:    randString(r),
+	}
+	if r.Intn(2) == 0 {
+		data.Retries = aws.Int64(r.Int63n(10))
+	}
+	// MakeAWS identifies the EC2 instances by their ID and the Elastic
+	// Beanstalk environments by their deployment ID.
+	if r.Intn(2) == 0 {
+		data.EC2 = &awsxray.EC2Metadata{
+			InstanceID:       aws.String("i-" + randID(r)),
+			AvailabilityZone: randString(r),
+			InstanceSize:     randString(r),
+			AmiID:            randString(r),
+		}
+	}
+	if r.Intn(2) == 0 {
+		data.ECS = &awsxray.ECSMetadata{ContainerName: aws.String(randID(r))}
+	}
+	if r.Intn(2) == 0 {
+		data.Beanstalk = &awsxray.BeanstalkMetadata{
+			Environment:  randString(r),
+			VersionLabel: randString(r),
+			DeploymentID: aws.Int64(r.Int63()),
+		}
+	}
+	return reflect.ValueOf(data)
+}
+
+func TestAWSRoundTrip(t *testing.T) {
+	roundTrip := func(data awsCase) bool {
+		expected := awsxray.AWSData(data)
+		resource := pdata.NewResource()
+		resource.InitEmpty()
+		resourceAttrs := resource.Attributes()
+		AddAWSToResource(&expected, &resourceAttrs)
+		attrs := pdata.NewAttributeMap()
+		attrs.InitEmptyWithCapacity(0)
+		AddAWSToSpan(&expected, &attrs)
+
+		filtered, actual := MakeAWS(attributesToMap(attrs), resource)
+		if len(filtered) != 0 || actual == nil {
+			return false
+		}
+		// the X-Ray SDK is described by the telemetry resource attributes,
+		// which are not part of the aws field.
+		expected.XRay = &awsxray.XRayMetaData{AutoInstrumentation: aws.Bool(false)}
+		return reflect.DeepEqual(&expected, actual)
+	}
+	assert.NoError(t, quick.Check(roundTrip, quickConfig))
+}
+
+func TestAWSRoundTripNotAWS(t *testing.T) {
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	resourceAttrs := resource.Attributes()
+	AddAWSToResource(nil, &resourceAttrs)
+	attrs := pdata.NewAttributeMap()
+	attrs.InitEmptyWithCapacity(0)
+	AddAWSToSpan(nil, &attrs)
+
+	provider, _ := resourceAttrs.Get(semconventions.AttributeCloudProvider)
+	assert.Equal(t, "unknown", provider.StringVal())
+	_, awsData := MakeAWS(attributesToMap(attrs), resource)
+	assert.Nil(t, awsData)
+}
@@ -15,12 +15,19 @@
 package translator
 
 import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
 	semconventions "go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
 )
 
-func makeSQL(attributes map[string]string) (map[string]string, *awsxray.SQLData) {
+// MakeSQL converts the database span attributes of an SQL database to the sql
+// field of an X-Ray segment, returning the span attributes left to convert. It
+// returns a nil SQLData if the database is not an SQL database.
+func MakeSQL(attributes map[string]string) (map[string]string, *awsxray.SQLData) {
 	var (
 		filtered    = make(map[string]string)
 		sqlData     awsxray.SQLData
@@ -94,3 +101,44 @@ func isSQL(system string) bool {
 	}
 	return false
 }
+
+// AddSQLToSpan converts the sql field of an X-Ray segment to the database span
+// attributes, the database name being split from the url as MakeSQL joins it.
+func AddSQLToSpan(sql *awsxray.SQLData, attrs *pdata.AttributeMap) error {
+	if sql == nil {
+		return nil
+	}
+
+	if sql.URL != nil {
+		dbURL, dbName, err := splitSQLURL(*sql.URL)
+		if err != nil {
+			return err
+		}
+		attrs.UpsertString(semconventions.AttributeDBConnectionString, dbURL)
+		attrs.UpsertString(semconventions.AttributeDBName, dbName)
+	}
+	// not handling sql.ConnectionString for now because MakeSQL does not
+	// support it
+	addString(sql.DatabaseType, semconventions.AttributeDBSystem, attrs)
+	addString(sql.SanitizedQuery, semconventions.AttributeDBStatement, attrs)
+	addString(sql.User, semconventions.AttributeDBUser, attrs)
+	return nil
+}
+
+var re = regexp.MustCompile(`^(.+\/\/.+)\/([^\?]+)\??.*$`)
+
+const (
+	dbURLI  = 1
+	dbNameI = 2
+)
+
+func splitSQLURL(rawURL string) (string, string, error) {
+	m := re.FindStringSubmatch(rawURL)
+	if len(m) == 0 {
+		return "", "", fmt.Errorf(
+			"failed to parse out the database name in the \"sql.url\" field, rawUrl: %s",
+			rawURL,
+		)
+	}
+	return m[dbURLI], m[dbNameI], nil
+}
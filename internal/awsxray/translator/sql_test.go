@@ -15,6 +15,7 @@
 package translator
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
@@ -33,18 +34,14 @@ func TestClientSpanWithStatementAttribute(t *testing.T) {
 	attributes[semconventions.AttributeNetPeerName] = "db.example.com"
 	attributes[semconventions.AttributeNetPeerPort] = "3306"
 
-	filtered, sqlData := makeSQL(attributes)
+	filtered, sqlData := MakeSQL(attributes)
 
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, sqlData)
 
-	w := testWriters.borrow()
-	if err := w.Encode(sqlData); err != nil {
-		assert.Fail(t, "invalid json")
-	}
-	jsonStr := w.String()
-	testWriters.release(w)
-	assert.True(t, strings.Contains(jsonStr, "mysql://db.example.com:3306/customers"))
+	jsonStr, err := json.Marshal(sqlData)
+	assert.NoError(t, err, "invalid json")
+	assert.True(t, strings.Contains(string(jsonStr), "mysql://db.example.com:3306/customers"))
 }
 
 func TestClientSpanWithNonSQLDatabase(t *testing.T) {
@@ -58,7 +55,7 @@ func TestClientSpanWithNonSQLDatabase(t *testing.T) {
 	attributes[semconventions.AttributeNetPeerName] = "db.example.com"
 	attributes[semconventions.AttributeNetPeerPort] = "3306"
 
-	filtered, sqlData := makeSQL(attributes)
+	filtered, sqlData := MakeSQL(attributes)
 	assert.Nil(t, sqlData)
 	assert.NotNil(t, filtered)
 }
@@ -73,9 +70,34 @@ func TestClientSpanWithoutDBurlAttribute(t *testing.T) {
 	attributes[semconventions.AttributeDBConnectionString] = ""
 	attributes[semconventions.AttributeNetPeerName] = "db.example.com"
 	attributes[semconventions.AttributeNetPeerPort] = "3306"
-	filtered, sqlData := makeSQL(attributes)
+	filtered, sqlData := MakeSQL(attributes)
 	assert.NotNil(t, filtered)
 	assert.NotNil(t, sqlData)
 
 	assert.Equal(t, "localhost/customers", *sqlData.URL)
 }
+
+func TestSQLURL(t *testing.T) {
+	raw := "jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432/ebdb"
+	url, dbName, err := splitSQLURL(raw)
+	assert.NoError(t, err, "should succeed")
+	assert.Equal(t,
+		"jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432",
+		url, "expected url to be the same")
+
+	assert.Equal(t,
+		"ebdb",
+		dbName, "expected db name to be the same")
+}
+func TestSQLURLQueryParameter(t *testing.T) {
+	raw := "jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432/ebdb?myInterceptor=foo"
+	url, dbName, err := splitSQLURL(raw)
+	assert.NoError(t, err, "should succeed")
+	assert.Equal(t,
+		"jdbc:postgresql://aawijb5u25wdoy.cpamxznpdoq8.us-west-2.rds.amazonaws.com:5432",
+		url, "expected url to be the same")
+
+	assert.Equal(t,
+		"ebdb",
+		dbName, "expected db name to be the same")
+}
@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package awsutil contains the endpoint and TLS settings shared by the AWS
+// exporters.
+package awsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// ResolveEndpoint returns the endpoint of the AWS service in the region. A
+// custom endpoint is returned after checking that it is an http or https URL,
+// the https scheme being added to the host names. If useFIPS is set, the FIPS
+// 140-2 endpoint of the service is returned, an error being returned outside
+// of the US and GovCloud regions, otherwise an empty endpoint is returned to
+// let the AWS SDK resolve it.
+func ResolveEndpoint(service, region, endpoint string, useFIPS bool) (string, error) {
+	if endpoint != "" {
+		if useFIPS {
+			return "", errors.New("a custom endpoint cannot be used with the FIPS endpoint")
+		}
+		if !strings.Contains(endpoint, "://") {
+			// as the AWS SDK, default to https for the endpoints given as a
+			// host name.
+			endpoint = "https://" + endpoint
+		}
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return "", fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+		}
+		if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			return "", fmt.Errorf("invalid endpoint %q: must be an http or https URL", endpoint)
+		}
+		return endpoint, nil
+	}
+	if !useFIPS {
+		return "", nil
+	}
+
+	if !fipsRegions[region] {
+		return "", fmt.Errorf("no FIPS endpoint of %s in region %q", service, region)
+	}
+	return "https://" + service + "-fips." + region + ".amazonaws.com", nil
+}
+
+// fipsRegions are the regions where the AWS exporters' services, X-Ray and
+// Kinesis, have a FIPS 140-2 endpoint. They are not listed by the endpoints
+// resolver of the AWS SDK for all the services.
+var fipsRegions = map[string]bool{
+	endpoints.UsEast1RegionID:    true,
+	endpoints.UsEast2RegionID:    true,
+	endpoints.UsWest1RegionID:    true,
+	endpoints.UsWest2RegionID:    true,
+	endpoints.UsGovEast1RegionID: true,
+	endpoints.UsGovWest1RegionID: true,
+}
+
+// NewTLSConfig returns the TLS configuration verifying the servers with the
+// system CA certificates and the certificates of the PEM encoded caBundle
+// file, for environments where the TLS connections are intercepted by a proxy.
+// The system configuration is used if caBundle is empty.
+func NewTLSConfig(caBundle string) (*tls.Config, error) {
+	if caBundle == "" {
+		return &tls.Config{}, nil
+	}
+	pem, err := ioutil.ReadFile(caBundle)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CA bundle: %v", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificate found in the CA bundle %q", caBundle)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsutil
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		region   string
+		endpoint string
+		useFIPS  bool
+		want     string
+		wantErr  bool
+	}{
+		{name: "default", region: "us-east-1"},
+		{name: "custom", region: "us-east-1", endpoint: "https://xray.internal:8443", want: "https://xray.internal:8443"},
+		{name: "fips", region: "us-east-1", useFIPS: true, want: "https://xray-fips.us-east-1.amazonaws.com"},
+		{name: "fips_govcloud", region: "us-gov-west-1", useFIPS: true, want: "https://xray-fips.us-gov-west-1.amazonaws.com"},
+		{name: "fips_china", region: "cn-north-1", useFIPS: true, wantErr: true},
+		{name: "fips_europe", region: "eu-west-1", useFIPS: true, wantErr: true},
+		{name: "fips_unknown_region", region: "mars-1", useFIPS: true, wantErr: true},
+		{name: "fips_and_custom", region: "us-east-1", endpoint: "https://xray.internal", useFIPS: true, wantErr: true},
+		{name: "host_name", region: "us-east-1", endpoint: "xray.internal:8443", want: "https://xray.internal:8443"},
+		{name: "no_host", region: "us-east-1", endpoint: "https://", wantErr: true},
+		{name: "invalid_scheme", region: "us-east-1", endpoint: "ftp://xray.internal", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveEndpoint("xray", tt.region, tt.endpoint, tt.useFIPS)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg, err := NewTLSConfig("")
+	require.NoError(t, err)
+	assert.Nil(t, cfg.RootCAs)
+
+	cfg, err = NewTLSConfig(path.Join(".", "testdata", "ca.pem"))
+	require.NoError(t, err)
+	assert.NotNil(t, cfg.RootCAs)
+
+	_, err = NewTLSConfig(path.Join(".", "testdata", "invalid.pem"))
+	assert.Error(t, err)
+
+	_, err = NewTLSConfig(path.Join(".", "testdata", "missing.pem"))
+	assert.Error(t, err)
+}
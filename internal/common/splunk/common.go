@@ -19,6 +19,15 @@ import "strings"
 const (
 	SFxAccessTokenHeader = "X-Sf-Token"
 	SFxAccessTokenLabel  = "com.splunk.signalfx.access_token"
+	HECTokenHeader       = "Splunk"
+	HECTokenLabel        = "com.splunk.hec.access_token"
+
+	// SFxEventCategoryKey is the attribute flagging the log records which are
+	// SignalFx events, holding the category of the event.
+	SFxEventCategoryKey = "com.splunk.signalfx.event_category"
+	// SFxEventPropertiesKey is the map attribute holding the properties of a
+	// SignalFx event.
+	SFxEventPropertiesKey = "com.splunk.signalfx.event_properties"
 )
 
 type AccessTokenPassthroughConfig struct {
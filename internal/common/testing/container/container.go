@@ -16,6 +16,7 @@ package container
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
 	"net"
@@ -27,6 +28,7 @@ import (
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/go-connections/nat"
 	"github.com/stretchr/testify/require"
 )
@@ -45,6 +47,9 @@ type Containers struct {
 type Container struct {
 	ID           ID
 	waitForPorts []uint16
+	env          []string
+	cmd          []string
+	hostNetwork  bool
 	ports        nat.PortMap
 	t            *testing.T
 }
@@ -72,6 +77,28 @@ func WithPortReady(port uint16) Option {
 	}
 }
 
+// WithEnv sets environment variables of the container, as KEY=value.
+func WithEnv(env ...string) Option {
+	return func(c *Container) {
+		c.env = append(c.env, env...)
+	}
+}
+
+// WithCmd overrides the command of the image.
+func WithCmd(cmd ...string) Option {
+	return func(c *Container) {
+		c.cmd = cmd
+	}
+}
+
+// WithHostNetwork runs the container in the network namespace of the host, so that it can connect
+// to the servers listening on the loopback interface of the host. Only supported on Linux.
+func WithHostNetwork() Option {
+	return func(c *Container) {
+		c.hostNetwork = true
+	}
+}
+
 // New wraps the provided testing.T instance so that any containers started will be cleaned
 // up when the test completes.
 func New(t *testing.T) *Containers {
@@ -117,40 +144,43 @@ func (c *Containers) pullImage(image string) {
 	}
 }
 
-func (c *Containers) createContainer(image string) container.ContainerCreateCreatedBody {
+func (c *Containers) createContainer(image string, con Container) container.ContainerCreateCreatedBody {
+	hostConfig := &container.HostConfig{
+		PublishAllPorts: true,
+	}
+	if con.hostNetwork {
+		hostConfig = &container.HostConfig{NetworkMode: "host"}
+	}
 	created, err := c.cli.ContainerCreate(context.Background(), &container.Config{
 		Image:  image,
+		Env:    con.env,
+		Cmd:    con.cmd,
 		Labels: map[string]string{"started-by": "opentelemetry-testing"},
-	}, &container.HostConfig{
-		PublishAllPorts: true,
-	}, &network.NetworkingConfig{}, "")
+	}, hostConfig, &network.NetworkingConfig{}, "")
 	if err != nil {
 		c.t.Fatalf("failed creating container with image %v: %v", image, err)
 	}
 	return created
 }
 
-func (c *Containers) startContainer(created container.ContainerCreateCreatedBody) Container {
+func (c *Containers) startContainer(created container.ContainerCreateCreatedBody, con Container) Container {
 	if err := c.cli.ContainerStart(context.Background(), created.ID, types.ContainerStartOptions{}); err != nil {
 		c.t.Fatalf("failed starting container %v: %v", created.ID, err)
 	}
 
-	con, err := c.cli.ContainerInspect(context.Background(), created.ID)
+	inspected, err := c.cli.ContainerInspect(context.Background(), created.ID)
 	if err != nil {
 		c.t.Fatalf("failed inspecting container %v: %v", created.ID, err)
 	}
 
-	if con.NetworkSettings.IPAddress == "" {
+	if !con.hostNetwork && inspected.NetworkSettings.IPAddress == "" {
 		c.t.Fatalf("failed to acquire IP address")
 	}
 
-	ports := con.NetworkSettings.Ports
-
-	return Container{
-		ID:    ID(created.ID),
-		ports: ports,
-		t:     c.t,
-	}
+	con.ID = ID(created.ID)
+	con.ports = inspected.NetworkSettings.Ports
+	con.t = c.t
+	return con
 }
 
 func (c *Containers) waitForPorts(con Container) {
@@ -180,19 +210,51 @@ func (c *Containers) waitForPorts(con Container) {
 // StartImage starts a container with the given image and zero or more ContainerOptions.
 func (c *Containers) StartImage(image string, opts ...Option) Container {
 	c.pullImage(image)
-	created := c.createContainer(image)
-	con := c.startContainer(created)
-	c.runningContainers[con.ID] = con
 
+	var con Container
 	for _, opt := range opts {
 		opt(&con)
 	}
 
+	created := c.createContainer(image, con)
+	con = c.startContainer(created, con)
+	c.runningContainers[con.ID] = con
+
 	c.waitForPorts(con)
 
 	return con
 }
 
+// Wait waits for the container to exit and returns its exit code and its output, stdout and stderr
+// interleaved. The test fails if the container does not exit before the timeout.
+func (c *Containers) Wait(con Container, timeout time.Duration) (int64, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var exitCode int64
+	statusCh, errCh := c.cli.ContainerWait(ctx, string(con.ID), container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	case err := <-errCh:
+		c.t.Fatalf("failed waiting for container %v: %v", con.ID, err)
+	}
+
+	reader, err := c.cli.ContainerLogs(context.Background(), string(con.ID), types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		c.t.Fatalf("failed reading the logs of container %v: %v", con.ID, err)
+	}
+	defer reader.Close()
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, reader); err != nil {
+		c.t.Fatalf("failed reading the logs of container %v: %v", con.ID, err)
+	}
+	return exitCode, output.String()
+}
+
 func (c *Containers) RemoveContainer(container Container) error {
 	err := c.cli.ContainerRemove(context.Background(), string(container.ID), types.ContainerRemoveOptions{
 		RemoveVolumes: true,
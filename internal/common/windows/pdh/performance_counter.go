@@ -0,0 +1,49 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pdh provides access to the Windows performance counters through
+// the Performance Data Helper (PDH) library.
+package pdh
+
+import "errors"
+
+// ErrUnsupported is returned when creating performance counters on other
+// operating systems than Windows.
+var ErrUnsupported = errors.New("performance counters are only supported on Windows")
+
+// CounterValue is the value of a performance counter for one of its
+// instances. InstanceName is empty for counters without instances.
+type CounterValue struct {
+	InstanceName string
+	Value        float64
+}
+
+// PerfCounterScraper collects the values of a performance counter.
+type PerfCounterScraper interface {
+	// Path returns the path of the counter.
+	Path() string
+	// ScrapeData collects a measurement and returns the value(s).
+	ScrapeData() ([]CounterValue, error)
+	// Close all counters/handles related to the query and free all associated memory.
+	Close() error
+}
+
+// CounterPath returns the path of the counter of the object, for the given
+// instance if it is not empty, e.g. `\Processor(_Total)\% Processor Time`.
+func CounterPath(object, instance, counter string) string {
+	if instance == "" {
+		return `\` + object + `\` + counter
+	}
+	return `\` + object + `(` + instance + `)\` + counter
+}
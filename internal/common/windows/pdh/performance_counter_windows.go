@@ -0,0 +1,159 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+// +build windows
+
+package pdh
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	errorSuccess  = 0
+	pdhMoreData   = 0x800007D2
+	pdhNoData     = 0x800007D5
+	pdhFmtDouble  = 0x00000200
+	pdhFmtNoCap   = 0x00008000
+	pdhValidData  = 0x00000000
+	pdhNewData    = 0x00000001
+	instanceTotal = "_Total"
+)
+
+var (
+	libPDH = windows.NewLazySystemDLL("pdh.dll")
+
+	procPdhOpenQuery                = libPDH.NewProc("PdhOpenQueryW")
+	procPdhAddEnglishCounter        = libPDH.NewProc("PdhAddEnglishCounterW")
+	procPdhCollectQueryData         = libPDH.NewProc("PdhCollectQueryData")
+	procPdhGetFormattedCounterArray = libPDH.NewProc("PdhGetFormattedCounterArrayW")
+	procPdhCloseQuery               = libPDH.NewProc("PdhCloseQuery")
+)
+
+// fmtCounterValueItemDouble mirrors PDH_FMT_COUNTERVALUE_ITEM_DOUBLE.
+type fmtCounterValueItemDouble struct {
+	name        *uint16
+	cStatus     uint32
+	_           [4]byte
+	doubleValue float64
+}
+
+// PerfCounter is a performance counter backed by its own PDH query.
+type PerfCounter struct {
+	path    string
+	query   uintptr
+	counter uintptr
+}
+
+var _ PerfCounterScraper = (*PerfCounter)(nil)
+
+// NewPerfCounter returns a new performance counter for the specified path.
+// The English names of the objects and counters are expected, regardless of
+// the language of the system.
+func NewPerfCounter(counterPath string) (PerfCounterScraper, error) {
+	pc := &PerfCounter{path: counterPath}
+	if ret, _, _ := procPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&pc.query))); ret != errorSuccess {
+		return nil, pdhError("PdhOpenQuery", ret)
+	}
+
+	path, err := windows.UTF16PtrFromString(counterPath)
+	if err != nil {
+		pc.Close()
+		return nil, err
+	}
+	if ret, _, _ := procPdhAddEnglishCounter.Call(pc.query, uintptr(unsafe.Pointer(path)), 0, uintptr(unsafe.Pointer(&pc.counter))); ret != errorSuccess {
+		pc.Close()
+		return nil, fmt.Errorf("counter %q: %w", counterPath, pdhError("PdhAddEnglishCounter", ret))
+	}
+
+	// Rate counters need two samples to be computed, collect the first one
+	// now so the first scrape reports a valid value.
+	if ret, _, _ := procPdhCollectQueryData.Call(pc.query); ret != errorSuccess && ret != pdhNoData {
+		pc.Close()
+		return nil, fmt.Errorf("counter %q: %w", counterPath, pdhError("PdhCollectQueryData", ret))
+	}
+	return pc, nil
+}
+
+// Path returns the path of the counter.
+func (pc *PerfCounter) Path() string {
+	return pc.path
+}
+
+// Close releases the query of the counter.
+func (pc *PerfCounter) Close() error {
+	if ret, _, _ := procPdhCloseQuery.Call(pc.query); ret != errorSuccess {
+		return pdhError("PdhCloseQuery", ret)
+	}
+	return nil
+}
+
+// ScrapeData collects the current values of the counter, for each of its
+// instances. The _Total instance is dropped when other instances are
+// reported.
+func (pc *PerfCounter) ScrapeData() ([]CounterValue, error) {
+	if ret, _, _ := procPdhCollectQueryData.Call(pc.query); ret != errorSuccess {
+		return nil, fmt.Errorf("counter %q: %w", pc.path, pdhError("PdhCollectQueryData", ret))
+	}
+
+	var size, count uint32
+	ret, _, _ := procPdhGetFormattedCounterArray.Call(pc.counter, pdhFmtDouble|pdhFmtNoCap,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), 0)
+	if ret != pdhMoreData {
+		return nil, fmt.Errorf("counter %q: %w", pc.path, pdhError("PdhGetFormattedCounterArray", ret))
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	buf := make([]byte, size)
+	ret, _, _ = procPdhGetFormattedCounterArray.Call(pc.counter, pdhFmtDouble|pdhFmtNoCap,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&buf[0])))
+	if ret != errorSuccess {
+		return nil, fmt.Errorf("counter %q: %w", pc.path, pdhError("PdhGetFormattedCounterArray", ret))
+	}
+
+	items := (*[1 << 20]fmtCounterValueItemDouble)(unsafe.Pointer(&buf[0]))[:count:count]
+	values := make([]CounterValue, 0, count)
+	for _, item := range items {
+		if item.cStatus != pdhValidData && item.cStatus != pdhNewData {
+			continue
+		}
+		values = append(values, CounterValue{
+			InstanceName: windows.UTF16PtrToString(item.name),
+			Value:        item.doubleValue,
+		})
+	}
+	return removeTotalIfMultipleValues(values), nil
+}
+
+func removeTotalIfMultipleValues(values []CounterValue) []CounterValue {
+	if len(values) <= 1 {
+		return values
+	}
+	for i, value := range values {
+		if value.InstanceName == instanceTotal {
+			return append(values[:i], values[i+1:]...)
+		}
+	}
+	return values
+}
+
+func pdhError(function string, status uintptr) error {
+	return fmt.Errorf("%s failed with status 0x%X", function, status)
+}
@@ -0,0 +1,49 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package pdh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfCounter(t *testing.T) {
+	pc, err := NewPerfCounter(`\Memory\Committed Bytes`)
+	require.NoError(t, err)
+	assert.Equal(t, `\Memory\Committed Bytes`, pc.Path())
+
+	values, err := pc.ScrapeData()
+	require.NoError(t, err)
+	require.Len(t, values, 1)
+	assert.Greater(t, values[0].Value, float64(0))
+
+	assert.NoError(t, pc.Close())
+}
+
+func TestNewPerfCounterInvalidPath(t *testing.T) {
+	_, err := NewPerfCounter(`\Invalid Object\Invalid Counter`)
+	assert.Error(t, err)
+}
+
+func TestRemoveTotalIfMultipleValues(t *testing.T) {
+	assert.Equal(t, []CounterValue{{InstanceName: "_Total", Value: 1}},
+		removeTotalIfMultipleValues([]CounterValue{{InstanceName: "_Total", Value: 1}}))
+	assert.Equal(t, []CounterValue{{InstanceName: "0", Value: 1}, {InstanceName: "1", Value: 3}},
+		removeTotalIfMultipleValues([]CounterValue{{InstanceName: "0", Value: 1}, {InstanceName: "_Total", Value: 2}, {InstanceName: "1", Value: 3}}))
+}
@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalityguardprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the cardinality guard processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MaxValues is the maximum number of distinct values of each key, the
+	// further values being replaced with the overflow value (default 1000).
+	MaxValues int `mapstructure:"max_values"`
+
+	// Keys are the keys of the attributes and labels guarded, all the keys
+	// being guarded if empty.
+	Keys []string `mapstructure:"keys"`
+
+	// Overrides are the maximum numbers of values of specific keys.
+	Overrides []OverrideConfig `mapstructure:"overrides"`
+
+	// OverflowValue is the value replacing the values past the maximum
+	// (default "overflow").
+	OverflowValue string `mapstructure:"overflow_value"`
+
+	// ResetInterval is how often the distinct values are forgotten, letting
+	// new values through again. 0 disables the resets (the default).
+	ResetInterval time.Duration `mapstructure:"reset_interval"`
+}
+
+// OverrideConfig defines the maximum number of values of a key.
+type OverrideConfig struct {
+	// Key is the key of the attributes and labels.
+	Key string `mapstructure:"key"`
+
+	// MaxValues is the maximum number of distinct values of the key.
+	MaxValues int `mapstructure:"max_values"`
+}
@@ -0,0 +1,49 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalityguardprocessor
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(viewValuesReplaced)
+}
+
+var (
+	tagProcessorKey, _ = tag.NewKey("processor")
+	tagKeyKey, _       = tag.NewKey("key")
+
+	mValuesReplaced = stats.Int64("otelcol/cardinality_guard/values_replaced", "Number of attribute and label values replaced with the overflow value", "1")
+)
+
+var viewValuesReplaced = &view.View{
+	Name:        mValuesReplaced.Name(),
+	Description: mValuesReplaced.Description(),
+	Measure:     mValuesReplaced,
+	TagKeys:     []tag.Key{tagProcessorKey, tagKeyKey},
+	Aggregation: view.Sum(),
+}
+
+func recordValuesReplaced(processor, key string) {
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(tagProcessorKey, processor), tag.Upsert(tagKeyKey, key)},
+		mValuesReplaced.M(1))
+}
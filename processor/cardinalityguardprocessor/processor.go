@@ -0,0 +1,266 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalityguardprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// cardinalityGuardProcessor replaces the values of the attributes and metric
+// labels past the maximum number of distinct values of their key with the
+// overflow value.
+type cardinalityGuardProcessor struct {
+	name          string
+	logger        *zap.Logger
+	tracker       *tracker
+	overflowValue string
+	resetInterval time.Duration
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCardinalityGuardProcessor(logger *zap.Logger, cfg *Config) (*cardinalityGuardProcessor, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return &cardinalityGuardProcessor{
+		name:          cfg.Name(),
+		logger:        logger,
+		tracker:       newTracker(cfg),
+		overflowValue: cfg.OverflowValue,
+		resetInterval: cfg.ResetInterval,
+		done:          make(chan struct{}),
+	}, nil
+}
+
+func (p *cardinalityGuardProcessor) start(context.Context, component.Host) error {
+	if p.resetInterval <= 0 {
+		return nil
+	}
+	p.wg.Add(1)
+	go p.resetPeriodically()
+	return nil
+}
+
+func (p *cardinalityGuardProcessor) resetPeriodically() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.resetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.tracker.reset()
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *cardinalityGuardProcessor) shutdown(context.Context) error {
+	close(p.done)
+	p.wg.Wait()
+	return nil
+}
+
+func (p *cardinalityGuardProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				p.guardAttributes(span.Attributes())
+			}
+		}
+	}
+	return td, nil
+}
+
+func (p *cardinalityGuardProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() {
+					continue
+				}
+				forEachLabelsMap(metric, p.guardLabels)
+			}
+		}
+	}
+	return md, nil
+}
+
+func (p *cardinalityGuardProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				p.guardAttributes(lr.Attributes())
+			}
+		}
+	}
+	return ld, nil
+}
+
+// guardAttributes replaces the values of the attributes which aren't
+// allowed, the non-string values being tracked by their string
+// representation.
+func (p *cardinalityGuardProcessor) guardAttributes(attrs pdata.AttributeMap) {
+	var replaced []string
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		if !p.allow(k, tracetranslator.AttributeValueToString(v, false)) {
+			replaced = append(replaced, k)
+		}
+	})
+	for _, k := range replaced {
+		attrs.Upsert(k, pdata.NewAttributeValueString(p.overflowValue))
+	}
+}
+
+func (p *cardinalityGuardProcessor) guardLabels(labels pdata.StringMap) {
+	var replaced []string
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		if !p.allow(k, v.Value()) {
+			replaced = append(replaced, k)
+		}
+	})
+	for _, k := range replaced {
+		labels.Update(k, p.overflowValue)
+	}
+}
+
+// allow returns whether the value of the key is allowed, warning the first
+// time the key overflows and recording the replaced values.
+func (p *cardinalityGuardProcessor) allow(key, value string) bool {
+	allowed, firstOverflow := p.tracker.allow(key, value)
+	if firstOverflow {
+		p.logger.Warn("Key reached its maximum number of distinct values, replacing its new values",
+			zap.String("key", key),
+			zap.String("overflow_value", p.overflowValue))
+	}
+	if !allowed {
+		recordValuesReplaced(p.name, key)
+	}
+	return allowed
+}
+
+// forEachLabelsMap calls the function with the labels of each data point of
+// the metric.
+func forEachLabelsMap(metric pdata.Metric, f func(pdata.StringMap)) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if !metric.IntGauge().IsNil() {
+			forEachIntDataPoint(metric.IntGauge().DataPoints(), f)
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if !metric.DoubleGauge().IsNil() {
+			forEachDoubleDataPoint(metric.DoubleGauge().DataPoints(), f)
+		}
+	case pdata.MetricDataTypeIntSum:
+		if !metric.IntSum().IsNil() {
+			forEachIntDataPoint(metric.IntSum().DataPoints(), f)
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if !metric.DoubleSum().IsNil() {
+			forEachDoubleDataPoint(metric.DoubleSum().DataPoints(), f)
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if !metric.IntHistogram().IsNil() {
+			forEachIntHistogramDataPoint(metric.IntHistogram().DataPoints(), f)
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if !metric.DoubleHistogram().IsNil() {
+			forEachDoubleHistogramDataPoint(metric.DoubleHistogram().DataPoints(), f)
+		}
+	}
+}
+
+func forEachIntDataPoint(points pdata.IntDataPointSlice, f func(pdata.StringMap)) {
+	for i := 0; i < points.Len(); i++ {
+		if !points.At(i).IsNil() {
+			f(points.At(i).LabelsMap())
+		}
+	}
+}
+
+func forEachDoubleDataPoint(points pdata.DoubleDataPointSlice, f func(pdata.StringMap)) {
+	for i := 0; i < points.Len(); i++ {
+		if !points.At(i).IsNil() {
+			f(points.At(i).LabelsMap())
+		}
+	}
+}
+
+func forEachIntHistogramDataPoint(points pdata.IntHistogramDataPointSlice, f func(pdata.StringMap)) {
+	for i := 0; i < points.Len(); i++ {
+		if !points.At(i).IsNil() {
+			f(points.At(i).LabelsMap())
+		}
+	}
+}
+
+func forEachDoubleHistogramDataPoint(points pdata.DoubleHistogramDataPointSlice, f func(pdata.StringMap)) {
+	for i := 0; i < points.Len(); i++ {
+		if !points.At(i).IsNil() {
+			f(points.At(i).LabelsMap())
+		}
+	}
+}
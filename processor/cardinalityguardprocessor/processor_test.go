@@ -0,0 +1,183 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalityguardprocessor
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func newTestProcessor(t *testing.T, logger *zap.Logger, modify func(*Config)) *cardinalityGuardProcessor {
+	cfg := createDefaultConfig().(*Config)
+	modify(cfg)
+	p, err := newCardinalityGuardProcessor(logger, cfg)
+	require.NoError(t, err)
+	return p
+}
+
+func TestTracker(t *testing.T) {
+	tr := newTracker(&Config{
+		MaxValues: 2,
+		Keys:      []string{"http.url"},
+		Overrides: []OverrideConfig{{Key: "user.id", MaxValues: 1}},
+	})
+
+	for _, tt := range []struct {
+		key, value    string
+		allowed       bool
+		firstOverflow bool
+	}{
+		{key: "http.url", value: "/a", allowed: true},
+		{key: "http.url", value: "/b", allowed: true},
+		{key: "http.url", value: "/a", allowed: true},
+		{key: "http.url", value: "/c", allowed: false, firstOverflow: true},
+		{key: "http.url", value: "/d", allowed: false},
+		{key: "user.id", value: "1", allowed: true},
+		{key: "user.id", value: "2", allowed: false, firstOverflow: true},
+		// The keys neither listed nor overridden aren't guarded.
+		{key: "http.method", value: "GET", allowed: true},
+		{key: "http.method", value: "POST", allowed: true},
+		{key: "http.method", value: "PUT", allowed: true},
+	} {
+		allowed, firstOverflow := tr.allow(tt.key, tt.value)
+		assert.Equal(t, tt.allowed, allowed, "%s=%s", tt.key, tt.value)
+		assert.Equal(t, tt.firstOverflow, firstOverflow, "%s=%s", tt.key, tt.value)
+	}
+
+	tr.reset()
+	allowed, _ := tr.allow("http.url", "/c")
+	assert.True(t, allowed)
+}
+
+func TestProcessMetrics(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	p := newTestProcessor(t, zap.New(core), func(cfg *Config) { cfg.MaxValues = 2 })
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(2)
+	metrics.At(0).SetName("requests")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().InitEmpty()
+	points := metrics.At(0).IntSum().DataPoints()
+	points.Resize(4)
+	for i := 0; i < points.Len(); i++ {
+		points.At(i).LabelsMap().InitFromMap(map[string]string{"user": strconv.Itoa(i), "method": "GET"})
+	}
+	metrics.At(1).SetName("latency")
+	metrics.At(1).SetDataType(pdata.MetricDataTypeDoubleHistogram)
+	metrics.At(1).DoubleHistogram().InitEmpty()
+	metrics.At(1).DoubleHistogram().DataPoints().Resize(1)
+	metrics.At(1).DoubleHistogram().DataPoints().At(0).LabelsMap().InitFromMap(map[string]string{"user": "4"})
+
+	replacedBefore := valuesReplaced(t, "user")
+	md, err := p.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	var users []string
+	for i := 0; i < points.Len(); i++ {
+		user, _ := points.At(i).LabelsMap().Get("user")
+		users = append(users, user.Value())
+		method, _ := points.At(i).LabelsMap().Get("method")
+		assert.Equal(t, "GET", method.Value())
+	}
+	assert.Equal(t, []string{"0", "1", "overflow", "overflow"}, users)
+	user, _ := metrics.At(1).DoubleHistogram().DataPoints().At(0).LabelsMap().Get("user")
+	assert.Equal(t, "overflow", user.Value())
+
+	assert.Equal(t, float64(3), valuesReplaced(t, "user")-replacedBefore)
+	// The overflow is only warned about once.
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "user", logs.All()[0].ContextMap()["key"])
+}
+
+func TestProcessTraces(t *testing.T) {
+	p := newTestProcessor(t, zap.NewNop(), func(cfg *Config) {
+		cfg.MaxValues = 1
+		cfg.OverflowValue = "other"
+	})
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+	spans.At(0).Attributes().InsertInt("http.status_code", 200)
+	spans.At(1).Attributes().InsertInt("http.status_code", 500)
+
+	td, err := p.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	code, _ := spans.At(0).Attributes().Get("http.status_code")
+	assert.Equal(t, int64(200), code.IntVal())
+	code, _ = spans.At(1).Attributes().Get("http.status_code")
+	assert.Equal(t, "other", code.StringVal())
+}
+
+func TestProcessLogs(t *testing.T) {
+	p := newTestProcessor(t, zap.NewNop(), func(cfg *Config) {
+		cfg.MaxValues = 1
+		cfg.Keys = []string{"request.id"}
+	})
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(2)
+	for i := 0; i < logs.Len(); i++ {
+		logs.At(i).Attributes().InsertString("request.id", strconv.Itoa(i))
+		logs.At(i).Attributes().InsertString("host", strconv.Itoa(i))
+	}
+
+	ld, err := p.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	id, _ := logs.At(1).Attributes().Get("request.id")
+	assert.Equal(t, "overflow", id.StringVal())
+	host, _ := logs.At(1).Attributes().Get("host")
+	assert.Equal(t, "1", host.StringVal())
+}
+
+// valuesReplaced returns the number of values of the key replaced so far by
+// the processors named after the type.
+func valuesReplaced(t *testing.T, key string) float64 {
+	rows, err := view.RetrieveData(viewValuesReplaced.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		tags := map[string]string{}
+		for _, tag := range row.Tags {
+			tags[tag.Key.Name()] = tag.Value
+		}
+		if tags["processor"] == typeStr && tags["key"] == key {
+			return row.Data.(*view.SumData).Value
+		}
+	}
+	return 0
+}
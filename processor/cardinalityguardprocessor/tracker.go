@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cardinalityguardprocessor
+
+import (
+	"sync"
+)
+
+// tracker tracks the distinct values of each key, up to its maximum number
+// of values.
+type tracker struct {
+	maxValues int
+	overrides map[string]int
+	// guarded are the keys tracked, all the keys being tracked if nil.
+	guarded map[string]bool
+
+	mu     sync.Mutex
+	values map[string]map[string]struct{}
+	// overflowed are the keys whose values were replaced since the last
+	// reset.
+	overflowed map[string]bool
+}
+
+func newTracker(cfg *Config) *tracker {
+	t := &tracker{
+		maxValues:  cfg.MaxValues,
+		overrides:  map[string]int{},
+		values:     map[string]map[string]struct{}{},
+		overflowed: map[string]bool{},
+	}
+	for _, override := range cfg.Overrides {
+		t.overrides[override.Key] = override.MaxValues
+	}
+	if len(cfg.Keys) > 0 {
+		t.guarded = map[string]bool{}
+		for _, key := range cfg.Keys {
+			t.guarded[key] = true
+		}
+		// The overridden keys are guarded even if not listed.
+		for key := range t.overrides {
+			t.guarded[key] = true
+		}
+	}
+	return t
+}
+
+// allow returns whether the value of the key is allowed, being one of the
+// first distinct values of the key, and whether the key overflowed for the
+// first time since the last reset.
+func (t *tracker) allow(key, value string) (allowed bool, firstOverflow bool) {
+	if t.guarded != nil && !t.guarded[key] {
+		return true, false
+	}
+	maxValues, ok := t.overrides[key]
+	if !ok {
+		maxValues = t.maxValues
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	values, ok := t.values[key]
+	if !ok {
+		values = map[string]struct{}{}
+		t.values[key] = values
+	}
+	if _, ok := values[value]; ok {
+		return true, false
+	}
+	if len(values) < maxValues {
+		values[value] = struct{}{}
+		return true, false
+	}
+	if t.overflowed[key] {
+		return false, false
+	}
+	t.overflowed[key] = true
+	return false, true
+}
+
+// reset forgets the distinct values of all the keys.
+func (t *tracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.values = map[string]map[string]struct{}{}
+	t.overflowed = map[string]bool{}
+}
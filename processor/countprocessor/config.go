@@ -0,0 +1,69 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the count processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// MetricsExporter is the name of the exporter the counters are sent to,
+	// it must be part of a metrics pipeline.
+	MetricsExporter string `mapstructure:"metrics_exporter"`
+
+	// FlushInterval is the interval at which the counters are sent to the
+	// exporter (default 60s).
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+
+	// Counters are the counters incremented by the spans and log records.
+	Counters []CounterConfig `mapstructure:"counters"`
+}
+
+// CounterConfig defines a counter and the spans and log records it counts.
+type CounterConfig struct {
+	// Name is the name of the metric reporting the counter.
+	Name string `mapstructure:"name"`
+
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+
+	// Errors restricts the counter to the spans with an error status and the
+	// log records with a severity of at least ERROR.
+	Errors bool `mapstructure:"errors"`
+
+	// Attributes are the conditions the spans and log records must all
+	// match to be counted, all of them being counted if empty.
+	Attributes []AttributeCondition `mapstructure:"attributes"`
+
+	// Labels are the keys of the attributes whose values label the counts.
+	Labels []string `mapstructure:"labels"`
+}
+
+// AttributeCondition matches the spans and log records having an attribute,
+// looked up in the attributes of the span or log record then in the
+// attributes of its resource.
+type AttributeCondition struct {
+	// Key is the key of the attribute.
+	Key string `mapstructure:"key"`
+
+	// Value is the expected value of the attribute, converted to a string,
+	// any value matching if empty.
+	Value string `mapstructure:"value"`
+}
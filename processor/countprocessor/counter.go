@@ -0,0 +1,337 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countprocessor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+	"go.uber.org/zap"
+)
+
+// counters holds the counts of the processors sharing a config, the
+// processors of the traces and logs pipelines adding up to the same metrics.
+type counters struct {
+	logger *zap.Logger
+	config *Config
+
+	mu        sync.Mutex
+	refCount  int
+	startTime pdata.TimestampUnixNano
+	exporter  component.MetricsExporter
+	done      chan struct{}
+	flushDone chan struct{}
+	// counts holds the counts of each counter of the config by label values.
+	counts []map[string]*count
+}
+
+type count struct {
+	labelValues []string
+	value       int64
+}
+
+func newCounters(cfg *Config, logger *zap.Logger) *counters {
+	c := &counters{
+		logger: logger,
+		config: cfg,
+		counts: make([]map[string]*count, len(cfg.Counters)),
+	}
+	for i := range c.counts {
+		c.counts[i] = map[string]*count{}
+	}
+	return c
+}
+
+func validateConfig(cfg *Config) error {
+	if cfg.MetricsExporter == "" {
+		return errors.New("`metrics_exporter` not specified")
+	}
+	if cfg.FlushInterval <= 0 {
+		return errors.New("`flush_interval` must be positive")
+	}
+	if len(cfg.Counters) == 0 {
+		return errors.New("`counters` not specified")
+	}
+	names := map[string]bool{}
+	for i, counter := range cfg.Counters {
+		if counter.Name == "" {
+			return fmt.Errorf("counter %d: name must be set", i)
+		}
+		if names[counter.Name] {
+			return fmt.Errorf("counter %q defined more than once", counter.Name)
+		}
+		names[counter.Name] = true
+		for _, condition := range counter.Attributes {
+			if condition.Key == "" {
+				return fmt.Errorf("counter %q: attribute key must be set", counter.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// start looks up the metrics exporter and starts flushing the counters to
+// it, once for all the processors sharing the counters.
+func (c *counters) start(_ context.Context, host component.Host) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.refCount++
+	if c.refCount > 1 {
+		return nil
+	}
+
+	exporter, err := findExporter(host, c.config.MetricsExporter)
+	if err != nil {
+		c.refCount--
+		return err
+	}
+	c.exporter = exporter
+	c.startTime = pdata.TimestampUnixNano(uint64(time.Now().UnixNano()))
+	c.done = make(chan struct{})
+	c.flushDone = make(chan struct{})
+	go c.flushPeriodically()
+	return nil
+}
+
+func findExporter(host component.Host, name string) (component.MetricsExporter, error) {
+	for k, exp := range host.GetExporters()[configmodels.MetricsDataType] {
+		if k.Name() != name {
+			continue
+		}
+		metricsExp, ok := exp.(component.MetricsExporter)
+		if !ok {
+			return nil, fmt.Errorf("the exporter %q isn't a metrics exporter", k.Name())
+		}
+		return metricsExp, nil
+	}
+	return nil, fmt.Errorf("the metrics exporter %q isn't part of a metrics pipeline", name)
+}
+
+// shutdown stops flushing the counters once all the processors sharing them
+// are shut down, sending the last counts to the exporter.
+func (c *counters) shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	c.refCount--
+	if c.refCount > 0 || c.done == nil {
+		c.mu.Unlock()
+		return nil
+	}
+	close(c.done)
+	c.mu.Unlock()
+
+	<-c.flushDone
+	return c.flush(ctx)
+}
+
+func (c *counters) flushPeriodically() {
+	defer close(c.flushDone)
+	ticker := time.NewTicker(c.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.flush(context.Background()); err != nil {
+				c.logger.Warn("Failed to send the counters", zap.Error(err))
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *counters) flush(ctx context.Context) error {
+	md := c.metrics()
+	if md.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	return c.exporter.ConsumeMetrics(ctx, md)
+}
+
+func (c *counters) countSpans(td pdata.Traces) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		resource := resourceAttributes(rs.Resource())
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				isError := !span.Status().IsNil() && span.Status().Code() != pdata.StatusCodeOk
+				c.add(isError, span.Attributes(), resource)
+			}
+		}
+	}
+}
+
+func (c *counters) countLogs(ld pdata.Logs) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		resource := resourceAttributes(rl.Resource())
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				c.add(lr.SeverityNumber() >= pdata.SeverityNumberERROR, lr.Attributes(), resource)
+			}
+		}
+	}
+}
+
+func resourceAttributes(resource pdata.Resource) pdata.AttributeMap {
+	if resource.IsNil() {
+		return pdata.NewAttributeMap()
+	}
+	return resource.Attributes()
+}
+
+// add increments the counters matching a span or log record.
+func (c *counters) add(isError bool, attrs, resource pdata.AttributeMap) {
+	for i, counter := range c.config.Counters {
+		if counter.Errors && !isError {
+			continue
+		}
+		if !matches(counter.Attributes, attrs, resource) {
+			continue
+		}
+
+		labelValues := make([]string, len(counter.Labels))
+		for j, key := range counter.Labels {
+			labelValues[j], _ = lookup(key, attrs, resource)
+		}
+		key := strings.Join(labelValues, "\x00")
+		cnt, ok := c.counts[i][key]
+		if !ok {
+			cnt = &count{labelValues: labelValues}
+			c.counts[i][key] = cnt
+		}
+		cnt.value++
+	}
+}
+
+func matches(conditions []AttributeCondition, attrs, resource pdata.AttributeMap) bool {
+	for _, condition := range conditions {
+		value, ok := lookup(condition.Key, attrs, resource)
+		if !ok || (condition.Value != "" && value != condition.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// lookup returns the value of an attribute of a span or log record, falling
+// back to the attributes of its resource.
+func lookup(key string, attrs, resource pdata.AttributeMap) (string, bool) {
+	if v, ok := attrs.Get(key); ok {
+		return tracetranslator.AttributeValueToString(v, false), true
+	}
+	if v, ok := resource.Get(key); ok {
+		return tracetranslator.AttributeValueToString(v, false), true
+	}
+	return "", false
+}
+
+// metrics returns the cumulative counts as metrics, one per counter which
+// counted at least a span or log record.
+func (c *counters) metrics() pdata.Metrics {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	md := pdata.NewMetrics()
+	now := pdata.TimestampUnixNano(uint64(time.Now().UnixNano()))
+	var metrics pdata.MetricSlice
+	for i, counter := range c.config.Counters {
+		counts := c.counts[i]
+		if len(counts) == 0 {
+			continue
+		}
+		if md.ResourceMetrics().Len() == 0 {
+			md.ResourceMetrics().Resize(1)
+			rm := md.ResourceMetrics().At(0)
+			rm.InstrumentationLibraryMetrics().Resize(1)
+			metrics = rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		}
+
+		metrics.Resize(metrics.Len() + 1)
+		metric := metrics.At(metrics.Len() - 1)
+		metric.SetName(counter.Name)
+		metric.SetDescription(counter.Description)
+		metric.SetDataType(pdata.MetricDataTypeIntSum)
+		sum := metric.IntSum()
+		sum.InitEmpty()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pdata.AggregationTemporalityCumulative)
+
+		keys := make([]string, 0, len(counts))
+		for key := range counts {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dps := sum.DataPoints()
+		dps.Resize(len(keys))
+		for j, key := range keys {
+			cnt := counts[key]
+			dp := dps.At(j)
+			for k, label := range counter.Labels {
+				if cnt.labelValues[k] != "" {
+					dp.LabelsMap().Insert(label, cnt.labelValues[k])
+				}
+			}
+			dp.SetStartTime(c.startTime)
+			dp.SetTimestamp(now)
+			dp.SetValue(cnt.value)
+		}
+	}
+	return md
+}
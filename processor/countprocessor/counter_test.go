@@ -0,0 +1,253 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+type mockHost struct {
+	componenttest.NopHost
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (m *mockHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return m.exporters
+}
+
+func newMockHost(name string, exp component.Exporter) *mockHost {
+	return &mockHost{
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{
+			configmodels.MetricsDataType: {
+				&configmodels.ExporterSettings{NameVal: name}: exp,
+			},
+		},
+	}
+}
+
+type testSpan struct {
+	route  string
+	status int32
+	err    bool
+}
+
+func testTraces(service string, spans ...testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString("service.name", service)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ss := rs.InstrumentationLibrarySpans().At(0).Spans()
+	ss.Resize(len(spans))
+	for i, s := range spans {
+		span := ss.At(i)
+		span.SetName("GET " + s.route)
+		span.Attributes().InsertString("http.route", s.route)
+		span.Attributes().InsertInt("http.status_code", int64(s.status))
+		if s.err {
+			span.Status().InitEmpty()
+			span.Status().SetCode(pdata.StatusCode(2))
+		}
+	}
+	return td
+}
+
+func testLogs(service string, severities ...pdata.SeverityNumber) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", service)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(severities))
+	for i, severity := range severities {
+		logs.At(i).SetSeverityNumber(severity)
+	}
+	return ld
+}
+
+type point struct {
+	labels map[string]string
+	value  int64
+}
+
+// points returns the points of the metrics sent to the exporter by name.
+func points(t *testing.T, md pdata.Metrics) map[string][]point {
+	got := map[string][]point{}
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	for i := 0; i < metrics.Len(); i++ {
+		metric := metrics.At(i)
+		require.Equal(t, pdata.MetricDataTypeIntSum, metric.DataType())
+		assert.True(t, metric.IntSum().IsMonotonic())
+		assert.Equal(t, pdata.AggregationTemporalityCumulative, metric.IntSum().AggregationTemporality())
+		dps := metric.IntSum().DataPoints()
+		for j := 0; j < dps.Len(); j++ {
+			dp := dps.At(j)
+			assert.NotZero(t, dp.StartTime())
+			assert.True(t, dp.Timestamp() >= dp.StartTime())
+			labels := map[string]string{}
+			dp.LabelsMap().ForEach(func(k string, v pdata.StringValue) {
+				labels[k] = v.Value()
+			})
+			got[metric.Name()] = append(got[metric.Name()], point{labels: labels, value: dp.Value()})
+		}
+	}
+	return got
+}
+
+func TestCountSpans(t *testing.T) {
+	cfg := testConfig()
+	cfg.Counters = []CounterConfig{
+		{
+			Name:   "errors",
+			Errors: true,
+			Labels: []string{"service.name"},
+		},
+		{
+			Name: "checkout_requests",
+			Attributes: []AttributeCondition{
+				{Key: "http.route", Value: "/checkout"},
+				{Key: "http.status_code"},
+			},
+			Labels: []string{"http.status_code", "missing"},
+		},
+		{
+			Name:       "unmatched",
+			Attributes: []AttributeCondition{{Key: "missing"}},
+		},
+	}
+	sink := &exportertest.SinkMetricsExporter{}
+	next := &exportertest.SinkTraceExporter{}
+	tp, err := NewFactory().CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), newMockHost("metrics", sink)))
+
+	td := testTraces("frontend",
+		testSpan{route: "/checkout", status: 200},
+		testSpan{route: "/checkout", status: 500, err: true},
+		testSpan{route: "/cart", status: 200},
+		testSpan{route: "/checkout", status: 200},
+	)
+	require.NoError(t, tp.ConsumeTraces(context.Background(), td))
+	require.NoError(t, tp.ConsumeTraces(context.Background(), testTraces("backend", testSpan{route: "/orders", err: true})))
+	assert.Equal(t, 5, next.SpansCount())
+	assert.Equal(t, 0, sink.MetricsCount())
+
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, map[string][]point{
+		"errors": {
+			{labels: map[string]string{"service.name": "backend"}, value: 1},
+			{labels: map[string]string{"service.name": "frontend"}, value: 1},
+		},
+		"checkout_requests": {
+			{labels: map[string]string{"http.status_code": "200"}, value: 2},
+			{labels: map[string]string{"http.status_code": "500"}, value: 1},
+		},
+	}, points(t, sink.AllMetrics()[0]))
+}
+
+func TestCountLogs(t *testing.T) {
+	cfg := testConfig()
+	cfg.Counters = []CounterConfig{
+		{
+			Name:   "error_logs",
+			Errors: true,
+			Labels: []string{"service.name"},
+		},
+	}
+	sink := &exportertest.SinkMetricsExporter{}
+	next := &exportertest.SinkLogsExporter{}
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, next)
+	require.NoError(t, err)
+	require.NoError(t, lp.Start(context.Background(), newMockHost("metrics", sink)))
+
+	ld := testLogs("frontend", pdata.SeverityNumberINFO, pdata.SeverityNumberERROR, pdata.SeverityNumberFATAL, pdata.SeverityNumberWARN)
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+	assert.Equal(t, 4, next.LogRecordsCount())
+
+	require.NoError(t, lp.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, map[string][]point{
+		"error_logs": {{labels: map[string]string{"service.name": "frontend"}, value: 2}},
+	}, points(t, sink.AllMetrics()[0]))
+}
+
+func TestSharedCounters(t *testing.T) {
+	cfg := testConfig()
+	sink := &exportertest.SinkMetricsExporter{}
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	host := newMockHost("metrics", sink)
+
+	tp, err := NewFactory().CreateTraceProcessor(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), host))
+	require.NoError(t, lp.Start(context.Background(), host))
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), testTraces("frontend", testSpan{route: "/"})))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), testLogs("frontend", pdata.SeverityNumberINFO)))
+
+	// The counters are flushed once the last processor is shut down.
+	require.NoError(t, tp.Shutdown(context.Background()))
+	assert.Equal(t, 0, sink.MetricsCount())
+	require.NoError(t, lp.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, map[string][]point{
+		"spans": {{labels: map[string]string{}, value: 2}},
+	}, points(t, sink.AllMetrics()[0]))
+}
+
+func TestFlushPeriodically(t *testing.T) {
+	cfg := testConfig()
+	cfg.FlushInterval = 10 * time.Millisecond
+	sink := &exportertest.SinkMetricsExporter{}
+	tp, err := NewFactory().CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), newMockHost("metrics", sink)))
+	defer tp.Shutdown(context.Background())
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), testTraces("frontend", testSpan{route: "/"})))
+	assert.Eventually(t, func() bool {
+		return sink.MetricsCount() > 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestStartExporterNotFound(t *testing.T) {
+	cfg := testConfig()
+	tp, err := NewFactory().CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+
+	err = tp.Start(context.Background(), newMockHost("other", &exportertest.SinkMetricsExporter{}))
+	assert.EqualError(t, err, `the metrics exporter "metrics" isn't part of a metrics pipeline`)
+
+	err = tp.Start(context.Background(), newMockHost("metrics", &exportertest.SinkTraceExporter{}))
+	assert.EqualError(t, err, `the exporter "metrics" isn't a metrics exporter`)
+}
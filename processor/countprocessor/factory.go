@@ -0,0 +1,115 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+const (
+	// The value of "type" key in configuration.
+	typeStr = "count"
+
+	defaultFlushInterval = 60 * time.Second
+)
+
+var processorCapabilities = component.ProcessorCapabilities{MutatesConsumedData: false}
+
+// NewFactory creates a factory for the count processor.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		typeStr,
+		createDefaultConfig,
+		processorhelper.WithTraces(createTraceProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
+}
+
+func createDefaultConfig() configmodels.Processor {
+	return &Config{
+		ProcessorSettings: configmodels.ProcessorSettings{
+			TypeVal: typeStr,
+			NameVal: typeStr,
+		},
+		FlushInterval: defaultFlushInterval,
+	}
+}
+
+func createTraceProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.TraceConsumer,
+) (component.TraceProcessor, error) {
+	c, err := getCounters(cfg.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewTraceProcessor(
+		cfg,
+		nextConsumer,
+		&countProcessor{counters: c},
+		processorhelper.WithStart(c.start),
+		processorhelper.WithShutdown(c.shutdown),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	c, err := getCounters(cfg.(*Config), params.Logger)
+	if err != nil {
+		return nil, err
+	}
+	return processorhelper.NewLogsProcessor(
+		cfg,
+		nextConsumer,
+		&countProcessor{counters: c},
+		processorhelper.WithStart(c.start),
+		processorhelper.WithShutdown(c.shutdown),
+		processorhelper.WithCapabilities(processorCapabilities))
+}
+
+// The processors of the different pipelines sharing a config share its
+// counters, hence its metrics.
+var (
+	countersMu  sync.Mutex
+	allCounters = map[*Config]*counters{}
+)
+
+func getCounters(cfg *Config, logger *zap.Logger) (*counters, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	countersMu.Lock()
+	defer countersMu.Unlock()
+	c, ok := allCounters[cfg]
+	if !ok {
+		c = newCounters(cfg, logger)
+		allCounters[cfg] = c
+	}
+	return c, nil
+}
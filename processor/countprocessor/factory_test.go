@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func testConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.MetricsExporter = "metrics"
+	cfg.Counters = []CounterConfig{{Name: "spans"}}
+	return cfg
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := testConfig()
+
+	tp, err := factory.CreateTraceProcessor(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+	assert.False(t, tp.GetCapabilities().MutatesConsumedData)
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	require.NoError(t, err)
+	assert.NotNil(t, lp)
+
+	// The processors of a config share its counters.
+	c, err := getCounters(cfg, zap.NewNop())
+	require.NoError(t, err)
+	assert.Same(t, allCounters[cfg], c)
+}
+
+func TestCreateProcessorInvalidConfig(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "no exporter",
+			modify: func(cfg *Config) { cfg.MetricsExporter = "" },
+			err:    "`metrics_exporter` not specified",
+		},
+		{
+			name:   "no flush interval",
+			modify: func(cfg *Config) { cfg.FlushInterval = 0 },
+			err:    "`flush_interval` must be positive",
+		},
+		{
+			name:   "no counters",
+			modify: func(cfg *Config) { cfg.Counters = nil },
+			err:    "`counters` not specified",
+		},
+		{
+			name:   "no counter name",
+			modify: func(cfg *Config) { cfg.Counters = []CounterConfig{{}} },
+			err:    "counter 0: name must be set",
+		},
+		{
+			name:   "duplicate counter",
+			modify: func(cfg *Config) { cfg.Counters = append(cfg.Counters, cfg.Counters[0]) },
+			err:    `counter "spans" defined more than once`,
+		},
+		{
+			name: "no attribute key",
+			modify: func(cfg *Config) {
+				cfg.Counters[0].Attributes = []AttributeCondition{{Value: "GET"}}
+			},
+			err: `counter "spans": attribute key must be set`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.modify(cfg)
+			tp, err := factory.CreateTraceProcessor(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+			assert.EqualError(t, err, tt.err)
+			assert.Nil(t, tp)
+		})
+	}
+}
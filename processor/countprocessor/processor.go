@@ -1,4 +1,4 @@
-// Copyright The OpenTelemetry Authors
+// Copyright 2020, OpenTelemetry Authors
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
 // you may not use this file except in compliance with the License.
@@ -12,30 +12,26 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package translator
+package countprocessor
 
 import (
+	"context"
+
 	"go.opentelemetry.io/collector/consumer/pdata"
 )
 
-func addAnnotations(annos map[string]interface{}, attrs *pdata.AttributeMap) {
-	for k, v := range annos {
-		switch t := v.(type) {
-		case int:
-			attrs.UpsertInt(k, int64(t))
-		case int32:
-			attrs.UpsertInt(k, int64(t))
-		case int64:
-			attrs.UpsertInt(k, t)
-		case string:
-			attrs.UpsertString(k, t)
-		case bool:
-			attrs.UpsertBool(k, t)
-		case float32:
-			attrs.UpsertDouble(k, float64(t))
-		case float64:
-			attrs.UpsertDouble(k, t)
-		default:
-		}
-	}
+// countProcessor counts the spans and log records passing it, leaving the
+// data unchanged.
+type countProcessor struct {
+	counters *counters
+}
+
+func (p *countProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	p.counters.countSpans(td)
+	return td, nil
+}
+
+func (p *countProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	p.counters.countLogs(ld)
+	return ld, nil
 }
@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupprocessor
+
+import (
+	"sync"
+	"time"
+)
+
+type cacheEntry struct {
+	hash    uint64
+	expires time.Time
+}
+
+// cache remembers the hashes of the spans and log records received within
+// the window.
+type cache struct {
+	window     time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	expires map[uint64]time.Time
+	// queue holds the entries in the order they were added, hence of their
+	// expiration.
+	queue []cacheEntry
+}
+
+func newCache(window time.Duration, maxEntries int) *cache {
+	return &cache{
+		window:     window,
+		maxEntries: maxEntries,
+		expires:    map[uint64]time.Time{},
+	}
+}
+
+// seen remembers the hash and returns whether it was already seen within the
+// window. The window starts when the hash is first seen, the duplicates not
+// extending it.
+func (c *cache) seen(hash uint64, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.queue) > 0 && !c.queue[0].expires.After(now) {
+		c.removeOldest()
+	}
+	if _, ok := c.expires[hash]; ok {
+		return true
+	}
+
+	if len(c.queue) >= c.maxEntries {
+		c.removeOldest()
+	}
+	expires := now.Add(c.window)
+	c.expires[hash] = expires
+	c.queue = append(c.queue, cacheEntry{hash: hash, expires: expires})
+	return false
+}
+
+func (c *cache) removeOldest() {
+	delete(c.expires, c.queue[0].hash)
+	c.queue = c.queue[1:]
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.queue)
+}
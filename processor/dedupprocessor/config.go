@@ -0,0 +1,38 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the dedup processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Window is how long a span or log record is remembered to detect its
+	// duplicates (default 1m).
+	Window time.Duration `mapstructure:"window"`
+
+	// MaxEntries is the maximum number of spans and log records remembered,
+	// the oldest being forgotten first (default 100000).
+	MaxEntries int `mapstructure:"max_entries"`
+
+	// IgnoredAttributes are the keys of the attributes left out when
+	// comparing log records, e.g. the ingestion time added by an agent.
+	IgnoredAttributes []string `mapstructure:"ignored_attributes"`
+}
@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupprocessor
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewSpansDropped,
+		viewLogRecordsDropped,
+	)
+}
+
+var (
+	tagProcessorKey, _ = tag.NewKey("processor")
+
+	mSpansDropped      = stats.Int64("otelcol/dedup/spans_dropped", "Number of duplicate spans dropped", "1")
+	mLogRecordsDropped = stats.Int64("otelcol/dedup/log_records_dropped", "Number of duplicate log records dropped", "1")
+)
+
+var viewSpansDropped = &view.View{
+	Name:        mSpansDropped.Name(),
+	Description: mSpansDropped.Description(),
+	Measure:     mSpansDropped,
+	TagKeys:     []tag.Key{tagProcessorKey},
+	Aggregation: view.Sum(),
+}
+
+var viewLogRecordsDropped = &view.View{
+	Name:        mLogRecordsDropped.Name(),
+	Description: mLogRecordsDropped.Description(),
+	Measure:     mLogRecordsDropped,
+	TagKeys:     []tag.Key{tagProcessorKey},
+	Aggregation: view.Sum(),
+}
+
+func recordSpansDropped(processor string, n int) {
+	record(processor, mSpansDropped.M(int64(n)))
+}
+
+func recordLogRecordsDropped(processor string, n int) {
+	record(processor, mLogRecordsDropped.M(int64(n)))
+}
+
+func record(processor string, m stats.Measurement) {
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagProcessorKey, processor)}, m)
+}
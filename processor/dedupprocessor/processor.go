@@ -0,0 +1,198 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupprocessor
+
+import (
+	"context"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// dedupProcessor drops the spans and log records already received within the
+// window. The spans are identified by their trace and span IDs, the log
+// records by a hash of their content and of their resource.
+type dedupProcessor struct {
+	name    string
+	ignored map[string]bool
+	cache   *cache
+}
+
+func newDedupProcessor(cfg *Config) (*dedupProcessor, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	p := &dedupProcessor{
+		name:    cfg.Name(),
+		ignored: map[string]bool{},
+		cache:   newCache(cfg.Window, cfg.MaxEntries),
+	}
+	for _, key := range cfg.IgnoredAttributes {
+		p.ignored[key] = true
+	}
+	return p, nil
+}
+
+func (p *dedupProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	now := time.Now()
+	dropped := 0
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			dropped += p.dedupSpans(ils.Spans(), now)
+		}
+	}
+	if dropped > 0 {
+		recordSpansDropped(p.name, dropped)
+	}
+	return td, nil
+}
+
+// dedupSpans removes the duplicate spans from the slice, returning their
+// number. The spans without IDs are kept.
+func (p *dedupProcessor) dedupSpans(spans pdata.SpanSlice, now time.Time) int {
+	kept := pdata.NewSpanSlice()
+	dropped := 0
+	for i := 0; i < spans.Len(); i++ {
+		span := spans.At(i)
+		if span.IsNil() {
+			continue
+		}
+		if len(span.TraceID().Bytes()) > 0 || len(span.SpanID().Bytes()) > 0 {
+			h := fnv.New64a()
+			h.Write(span.TraceID().Bytes())
+			h.Write(span.SpanID().Bytes())
+			if p.cache.seen(h.Sum64(), now) {
+				dropped++
+				continue
+			}
+		}
+		kept.Append(span)
+	}
+	if dropped > 0 {
+		spans.Resize(0)
+		kept.MoveAndAppendTo(spans)
+	}
+	return dropped
+}
+
+func (p *dedupProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	now := time.Now()
+	dropped := 0
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		// The hash of the resource starts the hash of each of its log records.
+		resourceHash := fnv.New64a()
+		if !rl.Resource().IsNil() {
+			p.writeAttributes(resourceHash, rl.Resource().Attributes())
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			dropped += p.dedupLogs(ill.Logs(), resourceHash.Sum64(), now)
+		}
+	}
+	if dropped > 0 {
+		recordLogRecordsDropped(p.name, dropped)
+	}
+	return ld, nil
+}
+
+// dedupLogs removes the duplicate log records from the slice, returning their
+// number.
+func (p *dedupProcessor) dedupLogs(logs pdata.LogSlice, resourceHash uint64, now time.Time) int {
+	kept := pdata.NewLogSlice()
+	dropped := 0
+	for i := 0; i < logs.Len(); i++ {
+		lr := logs.At(i)
+		if lr.IsNil() {
+			continue
+		}
+		if p.cache.seen(p.logHash(lr, resourceHash), now) {
+			dropped++
+			continue
+		}
+		kept.Append(lr)
+	}
+	if dropped > 0 {
+		logs.Resize(0)
+		kept.MoveAndAppendTo(logs)
+	}
+	return dropped
+}
+
+func (p *dedupProcessor) logHash(lr pdata.LogRecord, resourceHash uint64) uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], resourceHash)
+	h.Write(b[:])
+	binary.LittleEndian.PutUint64(b[:], uint64(lr.Timestamp()))
+	h.Write(b[:])
+	binary.LittleEndian.PutUint64(b[:], uint64(lr.SeverityNumber()))
+	h.Write(b[:])
+	writeString(h, lr.SeverityText())
+	writeString(h, lr.Name())
+	h.Write(lr.TraceID().Bytes())
+	h.Write(lr.SpanID().Bytes())
+	if !lr.Body().IsNil() {
+		writeString(h, tracetranslator.AttributeValueToString(lr.Body(), true))
+	}
+	p.writeAttributes(h, lr.Attributes())
+	return h.Sum64()
+}
+
+// writeAttributes writes the attributes not ignored, sorted by key.
+func (p *dedupProcessor) writeAttributes(h hash.Hash64, attrs pdata.AttributeMap) {
+	keys := make([]string, 0, attrs.Len())
+	attrs.ForEach(func(k string, _ pdata.AttributeValue) {
+		if !p.ignored[k] {
+			keys = append(keys, k)
+		}
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		writeString(h, k)
+		writeString(h, tracetranslator.AttributeValueToString(v, true))
+	}
+}
+
+// writeString writes the string followed by a separator, so that consecutive
+// strings can't be confused.
+func writeString(h hash.Hash64, s string) {
+	h.Write([]byte(s))
+	h.Write([]byte{0})
+}
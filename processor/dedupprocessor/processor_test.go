@@ -0,0 +1,156 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func generateTraces(spanIDs ...byte) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(len(spanIDs))
+	for i, id := range spanIDs {
+		spans.At(i).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		spans.At(i).SetSpanID(pdata.NewSpanID([]byte{0, 0, 0, 0, 0, 0, 0, id}))
+		spans.At(i).SetName("span")
+	}
+	return td
+}
+
+func spanIDs(td pdata.Traces) []byte {
+	var ids []byte
+	spans := td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		ids = append(ids, spans.At(i).SpanID().Bytes()[7])
+	}
+	return ids
+}
+
+func TestProcessTraces(t *testing.T) {
+	p, err := newDedupProcessor(createDefaultConfig().(*Config))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	td, err := p.ProcessTraces(ctx, generateTraces(1, 2, 2, 3))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, spanIDs(td))
+
+	td, err = p.ProcessTraces(ctx, generateTraces(3, 4, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4}, spanIDs(td))
+
+	// The spans without IDs are kept.
+	td = pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().Resize(1)
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().Resize(2)
+	td, err = p.ProcessTraces(ctx, td)
+	require.NoError(t, err)
+	assert.Equal(t, 2, td.SpanCount())
+}
+
+type logRecord struct {
+	body       string
+	attributes map[string]string
+}
+
+func generateLogs(host string, records ...logRecord) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("host.name", host)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(records))
+	for i, r := range records {
+		lr := logs.At(i)
+		lr.SetTimestamp(pdata.TimestampUnixNano(1600000000000000000))
+		lr.Body().SetStringVal(r.body)
+		for k, v := range r.attributes {
+			lr.Attributes().InsertString(k, v)
+		}
+	}
+	return ld
+}
+
+func bodies(ld pdata.Logs) []string {
+	var bodies []string
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	for i := 0; i < logs.Len(); i++ {
+		bodies = append(bodies, logs.At(i).Body().StringVal())
+	}
+	return bodies
+}
+
+func TestProcessLogs(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.IgnoredAttributes = []string{"fluent.tag"}
+	p, err := newDedupProcessor(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	ld, err := p.ProcessLogs(ctx, generateLogs("a",
+		logRecord{body: "first", attributes: map[string]string{"level": "info", "fluent.tag": "app"}},
+		logRecord{body: "second"},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, bodies(ld))
+
+	// The retried records are dropped, the ignored attributes not being compared.
+	ld, err = p.ProcessLogs(ctx, generateLogs("a",
+		logRecord{body: "first", attributes: map[string]string{"level": "info", "fluent.tag": "retry"}},
+		logRecord{body: "first", attributes: map[string]string{"level": "warn"}},
+		logRecord{body: "second"},
+		logRecord{body: "third"},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "third"}, bodies(ld))
+
+	// The records of another resource are not duplicates.
+	ld, err = p.ProcessLogs(ctx, generateLogs("b", logRecord{body: "second"}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"second"}, bodies(ld))
+}
+
+func TestCache(t *testing.T) {
+	c := newCache(time.Minute, 3)
+	now := time.Unix(1600000000, 0)
+
+	assert.False(t, c.seen(1, now))
+	assert.True(t, c.seen(1, now.Add(30*time.Second)))
+	assert.False(t, c.seen(2, now.Add(30*time.Second)))
+
+	// The window starts when the hash is first seen.
+	assert.False(t, c.seen(1, now.Add(time.Minute)))
+	assert.Equal(t, 2, c.len())
+
+	// The oldest hashes are forgotten first beyond the max entries.
+	assert.False(t, c.seen(3, now.Add(time.Minute)))
+	assert.False(t, c.seen(4, now.Add(time.Minute)))
+	assert.Equal(t, 3, c.len())
+	assert.False(t, c.seen(2, now.Add(time.Minute)))
+	assert.True(t, c.seen(4, now.Add(time.Minute)))
+}
@@ -0,0 +1,43 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoipprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the geoip processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// CityDatabase is the path of a GeoIP2 or GeoLite2 City database, from
+	// which the country, city and location are looked up.
+	CityDatabase string `mapstructure:"city_database"`
+
+	// ASNDatabase is the path of a GeoLite2 ASN database, from which the
+	// autonomous system is looked up.
+	ASNDatabase string `mapstructure:"asn_database"`
+
+	// Attributes are the keys of the attributes holding the IP address to
+	// look up, the first one holding a valid address being used (default
+	// client.ip and net.peer.ip).
+	Attributes []string `mapstructure:"attributes"`
+
+	// ReloadInterval is how often the databases are checked for changes,
+	// being reopened when modified. 0 disables the reloads (default 1m).
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
@@ -0,0 +1,199 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoipprocessor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+var errNotLoaded = errors.New("the databases are not loaded")
+
+// location is the information known about an IP address, the zero values
+// standing for the information missing from the databases.
+type location struct {
+	countryISOCode string
+	countryName    string
+	continentCode  string
+	cityName       string
+	hasCoordinates bool
+	latitude       float64
+	longitude      float64
+	asNumber       uint
+	asOrganization string
+}
+
+// locator looks up the location of IP addresses.
+type locator interface {
+	lookup(ip net.IP) (location, error)
+	close() error
+}
+
+// mmdbLocator looks up the IP addresses in MaxMind databases, either of them
+// being optional.
+type mmdbLocator struct {
+	city *geoip2.Reader
+	asn  *geoip2.Reader
+}
+
+var _ locator = (*mmdbLocator)(nil)
+
+func openMMDBLocator(cityDatabase, asnDatabase string) (locator, error) {
+	l := &mmdbLocator{}
+	var err error
+	if cityDatabase != "" {
+		if l.city, err = geoip2.Open(cityDatabase); err != nil {
+			return nil, fmt.Errorf("failed to open the city database: %w", err)
+		}
+	}
+	if asnDatabase != "" {
+		if l.asn, err = geoip2.Open(asnDatabase); err != nil {
+			l.close()
+			return nil, fmt.Errorf("failed to open the ASN database: %w", err)
+		}
+	}
+	return l, nil
+}
+
+func (l *mmdbLocator) lookup(ip net.IP) (location, error) {
+	var loc location
+	if l.city != nil {
+		city, err := l.city.City(ip)
+		if err != nil {
+			return location{}, err
+		}
+		loc.countryISOCode = city.Country.IsoCode
+		loc.countryName = city.Country.Names["en"]
+		loc.continentCode = city.Continent.Code
+		loc.cityName = city.City.Names["en"]
+		// The addresses without location have null coordinates.
+		if city.Location.Latitude != 0 || city.Location.Longitude != 0 {
+			loc.hasCoordinates = true
+			loc.latitude = city.Location.Latitude
+			loc.longitude = city.Location.Longitude
+		}
+	}
+	if l.asn != nil {
+		asn, err := l.asn.ASN(ip)
+		if err != nil {
+			return location{}, err
+		}
+		loc.asNumber = asn.AutonomousSystemNumber
+		loc.asOrganization = asn.AutonomousSystemOrganization
+	}
+	return loc, nil
+}
+
+func (l *mmdbLocator) close() error {
+	var err error
+	for _, r := range []*geoip2.Reader{l.city, l.asn} {
+		if r == nil {
+			continue
+		}
+		if closeErr := r.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// databases holds the locator of the current version of the database files,
+// reopening them when they are modified.
+type databases struct {
+	paths []string
+	open  func() (locator, error)
+
+	mu       sync.RWMutex
+	current  locator
+	modTimes []time.Time
+}
+
+func newDatabases(paths []string, open func() (locator, error)) *databases {
+	return &databases{paths: paths, open: open}
+}
+
+// load opens the databases if they were modified since they were last
+// opened, returning whether they were reopened. The previous locator is kept
+// if they fail to open, e.g. while a file is being replaced.
+func (d *databases) load() (bool, error) {
+	modTimes := make([]time.Time, len(d.paths))
+	for i, path := range d.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, err
+		}
+		modTimes[i] = info.ModTime()
+	}
+
+	d.mu.RLock()
+	unchanged := d.current != nil && equalTimes(d.modTimes, modTimes)
+	d.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	l, err := d.open()
+	if err != nil {
+		return false, err
+	}
+	d.mu.Lock()
+	previous := d.current
+	d.current = l
+	d.modTimes = modTimes
+	d.mu.Unlock()
+	// No lookup can still use the previous locator once the lock is released.
+	if previous != nil {
+		previous.close()
+	}
+	return true, nil
+}
+
+func (d *databases) lookup(ip net.IP) (location, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.current == nil {
+		return location{}, errNotLoaded
+	}
+	return d.current.lookup(ip)
+}
+
+func (d *databases) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.current == nil {
+		return nil
+	}
+	err := d.current.close()
+	d.current = nil
+	return err
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
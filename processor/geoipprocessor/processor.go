@@ -0,0 +1,233 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoipprocessor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// The attributes added from the location of the IP addresses.
+const (
+	countryISOCodeAttribute = "geo.country.iso_code"
+	countryNameAttribute    = "geo.country.name"
+	continentCodeAttribute  = "geo.continent.code"
+	cityNameAttribute       = "geo.city.name"
+	latitudeAttribute       = "geo.location.lat"
+	longitudeAttribute      = "geo.location.lon"
+	asNumberAttribute       = "as.number"
+	asOrganizationAttribute = "as.organization"
+)
+
+// geoIPProcessor adds the location of the IP address held by the resources,
+// spans and log records to their attributes.
+type geoIPProcessor struct {
+	logger         *zap.Logger
+	attributes     []string
+	databases      *databases
+	reloadInterval time.Duration
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newGeoIPProcessor(logger *zap.Logger, cfg *Config) (*geoIPProcessor, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, path := range []string{cfg.CityDatabase, cfg.ASNDatabase} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	cityDatabase, asnDatabase := cfg.CityDatabase, cfg.ASNDatabase
+	open := func() (locator, error) {
+		return openMMDBLocator(cityDatabase, asnDatabase)
+	}
+	return &geoIPProcessor{
+		logger:         logger,
+		attributes:     cfg.Attributes,
+		databases:      newDatabases(paths, open),
+		reloadInterval: cfg.ReloadInterval,
+		done:           make(chan struct{}),
+	}, nil
+}
+
+// start opens the databases, then checks them for changes every reload
+// interval.
+func (p *geoIPProcessor) start(context.Context, component.Host) error {
+	if _, err := p.databases.load(); err != nil {
+		return fmt.Errorf("failed to load the geoip databases: %w", err)
+	}
+	if p.reloadInterval <= 0 {
+		return nil
+	}
+	p.wg.Add(1)
+	go p.reloadDatabases()
+	return nil
+}
+
+func (p *geoIPProcessor) reloadDatabases() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			reloaded, err := p.databases.load()
+			if err != nil {
+				p.logger.Warn("Failed to reload the geoip databases, keeping the previous ones", zap.Error(err))
+			} else if reloaded {
+				p.logger.Info("Reloaded the geoip databases")
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *geoIPProcessor) shutdown(context.Context) error {
+	close(p.done)
+	p.wg.Wait()
+	return p.databases.close()
+}
+
+func (p *geoIPProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		if !rs.Resource().IsNil() {
+			p.enrich(rs.Resource().Attributes())
+		}
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			if ils.IsNil() {
+				continue
+			}
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if span.IsNil() {
+					continue
+				}
+				p.enrich(span.Attributes())
+			}
+		}
+	}
+	return td, nil
+}
+
+func (p *geoIPProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() || rm.Resource().IsNil() {
+			continue
+		}
+		p.enrich(rm.Resource().Attributes())
+	}
+	return md, nil
+}
+
+func (p *geoIPProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		if !rl.Resource().IsNil() {
+			p.enrich(rl.Resource().Attributes())
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			logs := ill.Logs()
+			for k := 0; k < logs.Len(); k++ {
+				lr := logs.At(k)
+				if lr.IsNil() {
+					continue
+				}
+				p.enrich(lr.Attributes())
+			}
+		}
+	}
+	return ld, nil
+}
+
+// enrich adds the location of the first valid IP address held by the
+// configured attributes to the attributes. The attributes are left unchanged
+// if none holds an IP address, or if it isn't found in the databases.
+func (p *geoIPProcessor) enrich(attrs pdata.AttributeMap) {
+	ip := p.findIP(attrs)
+	if ip == nil {
+		return
+	}
+	loc, err := p.databases.lookup(ip)
+	if err != nil {
+		p.logger.Debug("Failed to look up IP address", zap.Stringer("ip", ip), zap.Error(err))
+		return
+	}
+	if loc.countryISOCode != "" {
+		attrs.UpsertString(countryISOCodeAttribute, loc.countryISOCode)
+	}
+	if loc.countryName != "" {
+		attrs.UpsertString(countryNameAttribute, loc.countryName)
+	}
+	if loc.continentCode != "" {
+		attrs.UpsertString(continentCodeAttribute, loc.continentCode)
+	}
+	if loc.cityName != "" {
+		attrs.UpsertString(cityNameAttribute, loc.cityName)
+	}
+	if loc.hasCoordinates {
+		attrs.UpsertDouble(latitudeAttribute, loc.latitude)
+		attrs.UpsertDouble(longitudeAttribute, loc.longitude)
+	}
+	if loc.asNumber != 0 {
+		attrs.UpsertInt(asNumberAttribute, int64(loc.asNumber))
+	}
+	if loc.asOrganization != "" {
+		attrs.UpsertString(asOrganizationAttribute, loc.asOrganization)
+	}
+}
+
+func (p *geoIPProcessor) findIP(attrs pdata.AttributeMap) net.IP {
+	for _, key := range p.attributes {
+		v, ok := attrs.Get(key)
+		if !ok || v.Type() != pdata.AttributeValueSTRING {
+			continue
+		}
+		if ip := net.ParseIP(v.StringVal()); ip != nil {
+			return ip
+		}
+	}
+	return nil
+}
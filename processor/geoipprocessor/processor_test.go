@@ -0,0 +1,267 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geoipprocessor
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// fakeLocator looks up the IP addresses in a map.
+type fakeLocator struct {
+	locations map[string]location
+	closed    bool
+}
+
+func (l *fakeLocator) lookup(ip net.IP) (location, error) {
+	return l.locations[ip.String()], nil
+}
+
+func (l *fakeLocator) close() error {
+	l.closed = true
+	return nil
+}
+
+var testLocations = map[string]location{
+	"81.2.69.142": {
+		countryISOCode: "GB",
+		countryName:    "United Kingdom",
+		continentCode:  "EU",
+		cityName:       "London",
+		hasCoordinates: true,
+		latitude:       51.5142,
+		longitude:      -0.0931,
+	},
+	"1.128.0.1": {
+		countryISOCode: "AU",
+		countryName:    "Australia",
+		continentCode:  "OC",
+		asNumber:       1221,
+		asOrganization: "Telstra Pty Ltd",
+	},
+}
+
+func newTestProcessor(t *testing.T, attributes ...string) *geoIPProcessor {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CityDatabase = "GeoLite2-City.mmdb"
+	if len(attributes) > 0 {
+		cfg.Attributes = attributes
+	}
+	p, err := newGeoIPProcessor(zap.NewNop(), cfg)
+	require.NoError(t, err)
+	p.databases.current = &fakeLocator{locations: testLocations}
+	return p
+}
+
+func TestEnrich(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]pdata.AttributeValue
+		want  map[string]pdata.AttributeValue
+	}{
+		{
+			name:  "city",
+			attrs: map[string]pdata.AttributeValue{"client.ip": pdata.NewAttributeValueString("81.2.69.142")},
+			want: map[string]pdata.AttributeValue{
+				"client.ip":             pdata.NewAttributeValueString("81.2.69.142"),
+				countryISOCodeAttribute: pdata.NewAttributeValueString("GB"),
+				countryNameAttribute:    pdata.NewAttributeValueString("United Kingdom"),
+				continentCodeAttribute:  pdata.NewAttributeValueString("EU"),
+				cityNameAttribute:       pdata.NewAttributeValueString("London"),
+				latitudeAttribute:       pdata.NewAttributeValueDouble(51.5142),
+				longitudeAttribute:      pdata.NewAttributeValueDouble(-0.0931),
+			},
+		},
+		{
+			name:  "autonomous system",
+			attrs: map[string]pdata.AttributeValue{"net.peer.ip": pdata.NewAttributeValueString("1.128.0.1")},
+			want: map[string]pdata.AttributeValue{
+				"net.peer.ip":           pdata.NewAttributeValueString("1.128.0.1"),
+				countryISOCodeAttribute: pdata.NewAttributeValueString("AU"),
+				countryNameAttribute:    pdata.NewAttributeValueString("Australia"),
+				continentCodeAttribute:  pdata.NewAttributeValueString("OC"),
+				asNumberAttribute:       pdata.NewAttributeValueInt(1221),
+				asOrganizationAttribute: pdata.NewAttributeValueString("Telstra Pty Ltd"),
+			},
+		},
+		{
+			name: "first valid address",
+			attrs: map[string]pdata.AttributeValue{
+				"client.ip":   pdata.NewAttributeValueString("unknown"),
+				"net.peer.ip": pdata.NewAttributeValueString("1.128.0.1"),
+			},
+			want: map[string]pdata.AttributeValue{
+				"client.ip":             pdata.NewAttributeValueString("unknown"),
+				"net.peer.ip":           pdata.NewAttributeValueString("1.128.0.1"),
+				countryISOCodeAttribute: pdata.NewAttributeValueString("AU"),
+				countryNameAttribute:    pdata.NewAttributeValueString("Australia"),
+				continentCodeAttribute:  pdata.NewAttributeValueString("OC"),
+				asNumberAttribute:       pdata.NewAttributeValueInt(1221),
+				asOrganizationAttribute: pdata.NewAttributeValueString("Telstra Pty Ltd"),
+			},
+		},
+		{
+			name:  "unknown address",
+			attrs: map[string]pdata.AttributeValue{"client.ip": pdata.NewAttributeValueString("10.0.0.1")},
+			want:  map[string]pdata.AttributeValue{"client.ip": pdata.NewAttributeValueString("10.0.0.1")},
+		},
+		{
+			name:  "not a string",
+			attrs: map[string]pdata.AttributeValue{"client.ip": pdata.NewAttributeValueInt(42)},
+			want:  map[string]pdata.AttributeValue{"client.ip": pdata.NewAttributeValueInt(42)},
+		},
+		{
+			name:  "no address",
+			attrs: map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueString("GET")},
+			want:  map[string]pdata.AttributeValue{"http.method": pdata.NewAttributeValueString("GET")},
+		},
+	}
+	p := newTestProcessor(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := pdata.NewAttributeMap().InitFromMap(tt.attrs)
+			p.enrich(attrs)
+			assert.Equal(t, pdata.NewAttributeMap().InitFromMap(tt.want).Sort(), attrs.Sort())
+		})
+	}
+}
+
+func TestProcessTraces(t *testing.T) {
+	p := newTestProcessor(t)
+
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString("net.peer.ip", "1.128.0.1")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	rs.InstrumentationLibrarySpans().At(0).Spans().Resize(1)
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	span.Attributes().InsertString("client.ip", "81.2.69.142")
+
+	td, err := p.ProcessTraces(context.Background(), td)
+	require.NoError(t, err)
+
+	asn, ok := rs.Resource().Attributes().Get(asNumberAttribute)
+	require.True(t, ok)
+	assert.EqualValues(t, 1221, asn.IntVal())
+	city, ok := span.Attributes().Get(cityNameAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "London", city.StringVal())
+}
+
+func TestProcessMetrics(t *testing.T) {
+	p := newTestProcessor(t)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(2)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("client.ip", "81.2.69.142")
+
+	md, err := p.ProcessMetrics(context.Background(), md)
+	require.NoError(t, err)
+
+	country, ok := rm.Resource().Attributes().Get(countryISOCodeAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "GB", country.StringVal())
+	assert.True(t, md.ResourceMetrics().At(1).Resource().IsNil())
+}
+
+func TestProcessLogs(t *testing.T) {
+	p := newTestProcessor(t, "source.address")
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	rl.InstrumentationLibraryLogs().At(0).Logs().Resize(1)
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	lr.Attributes().InsertString("source.address", "81.2.69.142")
+
+	ld, err := p.ProcessLogs(context.Background(), ld)
+	require.NoError(t, err)
+
+	country, ok := lr.Attributes().Get(countryNameAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "United Kingdom", country.StringVal())
+}
+
+func TestDatabasesReload(t *testing.T) {
+	f, err := ioutil.TempFile("", "geoip")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	defer os.Remove(f.Name())
+
+	var opened []*fakeLocator
+	var openErr error
+	d := newDatabases([]string{f.Name()}, func() (locator, error) {
+		if openErr != nil {
+			return nil, openErr
+		}
+		l := &fakeLocator{locations: testLocations}
+		opened = append(opened, l)
+		return l, nil
+	})
+
+	_, err = d.lookup(net.ParseIP("81.2.69.142"))
+	assert.Equal(t, errNotLoaded, err)
+
+	reloaded, err := d.load()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	require.Len(t, opened, 1)
+
+	// The databases aren't reopened while the files are unchanged.
+	reloaded, err = d.load()
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Len(t, opened, 1)
+
+	// The previous databases are kept if the new ones fail to open.
+	modTime := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(f.Name(), modTime, modTime))
+	openErr = errors.New("truncated database")
+	_, err = d.load()
+	assert.Equal(t, openErr, err)
+	loc, err := d.lookup(net.ParseIP("81.2.69.142"))
+	require.NoError(t, err)
+	assert.Equal(t, "London", loc.cityName)
+	assert.False(t, opened[0].closed)
+
+	openErr = nil
+	reloaded, err = d.load()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+	require.Len(t, opened, 2)
+	assert.True(t, opened[0].closed)
+
+	require.NoError(t, os.Remove(f.Name()))
+	_, err = d.load()
+	assert.Error(t, err)
+
+	require.NoError(t, d.close())
+	assert.True(t, opened[1].closed)
+}
@@ -0,0 +1,484 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+// The aggregations of the data points of a stream, the gauges supporting
+// all but the sum.
+const (
+	aggregationLast = "last"
+	aggregationMin  = "min"
+	aggregationMax  = "max"
+	aggregationSum  = "sum"
+)
+
+// aggregator aggregates the data points of each stream, identified by its
+// resource, instrumentation library, metric and labels, until they are
+// flushed. The groups are kept in their order of arrival, so that the
+// flushed metrics are ordered as received.
+type aggregator struct {
+	gaugeAggregation string
+
+	resources     []*resourceGroup
+	resourceIndex map[string]*resourceGroup
+}
+
+type resourceGroup struct {
+	resource     pdata.Resource
+	libraries    []*libraryGroup
+	libraryIndex map[string]*libraryGroup
+}
+
+type libraryGroup struct {
+	library     pdata.InstrumentationLibrary
+	metrics     []*metricGroup
+	metricIndex map[string]*metricGroup
+}
+
+// metricGroup holds a copy of a metric, whose data points are the
+// aggregated points of its streams.
+type metricGroup struct {
+	metric pdata.Metric
+	points map[string]int
+}
+
+func newAggregator(gaugeAggregation string) *aggregator {
+	return &aggregator{
+		gaugeAggregation: gaugeAggregation,
+		resourceIndex:    map[string]*resourceGroup{},
+	}
+}
+
+// add aggregates the data points of the metrics.
+func (a *aggregator) add(md pdata.Metrics) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		rg := a.resourceGroup(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			lg := rg.libraryGroup(ilm.InstrumentationLibrary())
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				if metric.IsNil() || metric.DataType() == pdata.MetricDataTypeNone {
+					continue
+				}
+				a.addMetric(lg.metricGroup(metric), metric)
+			}
+		}
+	}
+}
+
+// flush returns the aggregated metrics and forgets them.
+func (a *aggregator) flush() pdata.Metrics {
+	md := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	rms.Resize(len(a.resources))
+	for i, rg := range a.resources {
+		rm := rms.At(i)
+		rg.resource.CopyTo(rm.Resource())
+		ilms := rm.InstrumentationLibraryMetrics()
+		ilms.Resize(len(rg.libraries))
+		for j, lg := range rg.libraries {
+			ilm := ilms.At(j)
+			lg.library.CopyTo(ilm.InstrumentationLibrary())
+			metrics := ilm.Metrics()
+			metrics.Resize(len(lg.metrics))
+			for k, mg := range lg.metrics {
+				mg.metric.CopyTo(metrics.At(k))
+			}
+		}
+	}
+	a.resources = nil
+	a.resourceIndex = map[string]*resourceGroup{}
+	return md
+}
+
+func (a *aggregator) resourceGroup(resource pdata.Resource) *resourceGroup {
+	key := ""
+	if !resource.IsNil() {
+		key = attributesKey(resource.Attributes())
+	}
+	rg, ok := a.resourceIndex[key]
+	if !ok {
+		rg = &resourceGroup{
+			resource:     pdata.NewResource(),
+			libraryIndex: map[string]*libraryGroup{},
+		}
+		resource.CopyTo(rg.resource)
+		a.resourceIndex[key] = rg
+		a.resources = append(a.resources, rg)
+	}
+	return rg
+}
+
+func (rg *resourceGroup) libraryGroup(library pdata.InstrumentationLibrary) *libraryGroup {
+	key := ""
+	if !library.IsNil() {
+		key = library.Name() + "\x00" + library.Version()
+	}
+	lg, ok := rg.libraryIndex[key]
+	if !ok {
+		lg = &libraryGroup{
+			library:     pdata.NewInstrumentationLibrary(),
+			metricIndex: map[string]*metricGroup{},
+		}
+		library.CopyTo(lg.library)
+		rg.libraryIndex[key] = lg
+		rg.libraries = append(rg.libraries, lg)
+	}
+	return lg
+}
+
+// metricGroup returns the group of the metric, the metrics of the same name
+// but of different types or temporalities being aggregated separately.
+func (lg *libraryGroup) metricGroup(metric pdata.Metric) *metricGroup {
+	key := metric.Name() + "\x00" + metric.DataType().String() + "\x00" + temporality(metric).String()
+	mg, ok := lg.metricIndex[key]
+	if !ok {
+		mg = &metricGroup{
+			metric: pdata.NewMetric(),
+			points: map[string]int{},
+		}
+		// Copy the metric with its descriptor, then drop its data points
+		// which are added as they are aggregated.
+		metric.CopyTo(mg.metric)
+		resetDataPoints(mg.metric)
+		lg.metricIndex[key] = mg
+		lg.metrics = append(lg.metrics, mg)
+	}
+	return mg
+}
+
+func (a *aggregator) addMetric(mg *metricGroup, metric pdata.Metric) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if metric.IntGauge().IsNil() {
+			return
+		}
+		a.addIntDataPoints(mg, mg.metric.IntGauge().DataPoints(), metric.IntGauge().DataPoints(), a.gaugeAggregation)
+	case pdata.MetricDataTypeDoubleGauge:
+		if metric.DoubleGauge().IsNil() {
+			return
+		}
+		a.addDoubleDataPoints(mg, mg.metric.DoubleGauge().DataPoints(), metric.DoubleGauge().DataPoints(), a.gaugeAggregation)
+	case pdata.MetricDataTypeIntSum:
+		if metric.IntSum().IsNil() {
+			return
+		}
+		a.addIntDataPoints(mg, mg.metric.IntSum().DataPoints(), metric.IntSum().DataPoints(), sumAggregation(metric.IntSum().AggregationTemporality()))
+	case pdata.MetricDataTypeDoubleSum:
+		if metric.DoubleSum().IsNil() {
+			return
+		}
+		a.addDoubleDataPoints(mg, mg.metric.DoubleSum().DataPoints(), metric.DoubleSum().DataPoints(), sumAggregation(metric.DoubleSum().AggregationTemporality()))
+	case pdata.MetricDataTypeIntHistogram:
+		if metric.IntHistogram().IsNil() {
+			return
+		}
+		delta := metric.IntHistogram().AggregationTemporality() == pdata.AggregationTemporalityDelta
+		a.addIntHistogramDataPoints(mg, mg.metric.IntHistogram().DataPoints(), metric.IntHistogram().DataPoints(), delta)
+	case pdata.MetricDataTypeDoubleHistogram:
+		if metric.DoubleHistogram().IsNil() {
+			return
+		}
+		delta := metric.DoubleHistogram().AggregationTemporality() == pdata.AggregationTemporalityDelta
+		a.addDoubleHistogramDataPoints(mg, mg.metric.DoubleHistogram().DataPoints(), metric.DoubleHistogram().DataPoints(), delta)
+	}
+}
+
+// sumAggregation returns how the points of a sum are aggregated: the delta
+// sums are summed, while the last point of the cumulative sums holds all the
+// previous ones.
+func sumAggregation(temporality pdata.AggregationTemporality) string {
+	if temporality == pdata.AggregationTemporalityDelta {
+		return aggregationSum
+	}
+	return aggregationLast
+}
+
+func (a *aggregator) addIntDataPoints(mg *metricGroup, dest, src pdata.IntDataPointSlice, aggregation string) {
+	for i := 0; i < src.Len(); i++ {
+		point := src.At(i)
+		if point.IsNil() {
+			continue
+		}
+		key := labelsKey(point.LabelsMap())
+		index, ok := mg.points[key]
+		if !ok {
+			mg.points[key] = dest.Len()
+			dest.Resize(dest.Len() + 1)
+			point.CopyTo(dest.At(dest.Len() - 1))
+			continue
+		}
+		existing := dest.At(index)
+		switch aggregation {
+		case aggregationSum:
+			existing.SetValue(existing.Value() + point.Value())
+		case aggregationMin:
+			if point.Value() < existing.Value() {
+				existing.SetValue(point.Value())
+			}
+		case aggregationMax:
+			if point.Value() > existing.Value() {
+				existing.SetValue(point.Value())
+			}
+		default:
+			if point.Timestamp() >= existing.Timestamp() {
+				point.CopyTo(existing)
+			}
+			continue
+		}
+		mergeTimes(existing, point)
+	}
+}
+
+func (a *aggregator) addDoubleDataPoints(mg *metricGroup, dest, src pdata.DoubleDataPointSlice, aggregation string) {
+	for i := 0; i < src.Len(); i++ {
+		point := src.At(i)
+		if point.IsNil() {
+			continue
+		}
+		key := labelsKey(point.LabelsMap())
+		index, ok := mg.points[key]
+		if !ok {
+			mg.points[key] = dest.Len()
+			dest.Resize(dest.Len() + 1)
+			point.CopyTo(dest.At(dest.Len() - 1))
+			continue
+		}
+		existing := dest.At(index)
+		switch aggregation {
+		case aggregationSum:
+			existing.SetValue(existing.Value() + point.Value())
+		case aggregationMin:
+			if point.Value() < existing.Value() {
+				existing.SetValue(point.Value())
+			}
+		case aggregationMax:
+			if point.Value() > existing.Value() {
+				existing.SetValue(point.Value())
+			}
+		default:
+			if point.Timestamp() >= existing.Timestamp() {
+				point.CopyTo(existing)
+			}
+			continue
+		}
+		mergeTimes(existing, point)
+	}
+}
+
+// addIntHistogramDataPoints keeps the last point of the cumulative
+// histograms, and merges the points of the delta histograms. The delta points
+// whose bounds changed replace the previous points, which can't be merged
+// with them.
+func (a *aggregator) addIntHistogramDataPoints(mg *metricGroup, dest, src pdata.IntHistogramDataPointSlice, delta bool) {
+	for i := 0; i < src.Len(); i++ {
+		point := src.At(i)
+		if point.IsNil() {
+			continue
+		}
+		key := labelsKey(point.LabelsMap())
+		index, ok := mg.points[key]
+		if !ok {
+			mg.points[key] = dest.Len()
+			dest.Resize(dest.Len() + 1)
+			point.CopyTo(dest.At(dest.Len() - 1))
+			continue
+		}
+		existing := dest.At(index)
+		if !delta || !mergeableBuckets(existing.ExplicitBounds(), point.ExplicitBounds(), existing.BucketCounts(), point.BucketCounts()) {
+			if point.Timestamp() >= existing.Timestamp() {
+				point.CopyTo(existing)
+			}
+			continue
+		}
+		existing.SetCount(existing.Count() + point.Count())
+		existing.SetSum(existing.Sum() + point.Sum())
+		existing.SetBucketCounts(addBucketCounts(existing.BucketCounts(), point.BucketCounts()))
+		mergeTimes(existing, point)
+	}
+}
+
+func (a *aggregator) addDoubleHistogramDataPoints(mg *metricGroup, dest, src pdata.DoubleHistogramDataPointSlice, delta bool) {
+	for i := 0; i < src.Len(); i++ {
+		point := src.At(i)
+		if point.IsNil() {
+			continue
+		}
+		key := labelsKey(point.LabelsMap())
+		index, ok := mg.points[key]
+		if !ok {
+			mg.points[key] = dest.Len()
+			dest.Resize(dest.Len() + 1)
+			point.CopyTo(dest.At(dest.Len() - 1))
+			continue
+		}
+		existing := dest.At(index)
+		if !delta || !mergeableBuckets(existing.ExplicitBounds(), point.ExplicitBounds(), existing.BucketCounts(), point.BucketCounts()) {
+			if point.Timestamp() >= existing.Timestamp() {
+				point.CopyTo(existing)
+			}
+			continue
+		}
+		existing.SetCount(existing.Count() + point.Count())
+		existing.SetSum(existing.Sum() + point.Sum())
+		existing.SetBucketCounts(addBucketCounts(existing.BucketCounts(), point.BucketCounts()))
+		mergeTimes(existing, point)
+	}
+}
+
+// timedPoint is implemented by all the data points.
+type timedPoint interface {
+	StartTime() pdata.TimestampUnixNano
+	SetStartTime(pdata.TimestampUnixNano)
+	Timestamp() pdata.TimestampUnixNano
+	SetTimestamp(pdata.TimestampUnixNano)
+}
+
+// mergeTimes extends the times of the aggregated point to cover the times of
+// the added point.
+func mergeTimes(existing, point timedPoint) {
+	if point.StartTime() != 0 && (existing.StartTime() == 0 || point.StartTime() < existing.StartTime()) {
+		existing.SetStartTime(point.StartTime())
+	}
+	if point.Timestamp() > existing.Timestamp() {
+		existing.SetTimestamp(point.Timestamp())
+	}
+}
+
+func mergeableBuckets(bounds, otherBounds []float64, counts, otherCounts []uint64) bool {
+	if len(bounds) != len(otherBounds) || len(counts) != len(otherCounts) {
+		return false
+	}
+	for i := range bounds {
+		if bounds[i] != otherBounds[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func addBucketCounts(counts, otherCounts []uint64) []uint64 {
+	sum := make([]uint64, len(counts))
+	for i := range counts {
+		sum[i] = counts[i] + otherCounts[i]
+	}
+	return sum
+}
+
+func temporality(metric pdata.Metric) pdata.AggregationTemporality {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		if !metric.IntSum().IsNil() {
+			return metric.IntSum().AggregationTemporality()
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if !metric.DoubleSum().IsNil() {
+			return metric.DoubleSum().AggregationTemporality()
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if !metric.IntHistogram().IsNil() {
+			return metric.IntHistogram().AggregationTemporality()
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if !metric.DoubleHistogram().IsNil() {
+			return metric.DoubleHistogram().AggregationTemporality()
+		}
+	}
+	return pdata.AggregationTemporalityUnspecified
+}
+
+func resetDataPoints(metric pdata.Metric) {
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		if !metric.IntGauge().IsNil() {
+			metric.IntGauge().DataPoints().Resize(0)
+		}
+	case pdata.MetricDataTypeDoubleGauge:
+		if !metric.DoubleGauge().IsNil() {
+			metric.DoubleGauge().DataPoints().Resize(0)
+		}
+	case pdata.MetricDataTypeIntSum:
+		if !metric.IntSum().IsNil() {
+			metric.IntSum().DataPoints().Resize(0)
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		if !metric.DoubleSum().IsNil() {
+			metric.DoubleSum().DataPoints().Resize(0)
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		if !metric.IntHistogram().IsNil() {
+			metric.IntHistogram().DataPoints().Resize(0)
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		if !metric.DoubleHistogram().IsNil() {
+			metric.DoubleHistogram().DataPoints().Resize(0)
+		}
+	}
+}
+
+func labelsKey(labels pdata.StringMap) string {
+	keys := make([]string, 0, labels.Len())
+	values := map[string]string{}
+	labels.ForEach(func(k string, v pdata.StringValue) {
+		keys = append(keys, k)
+		values[k] = v.Value()
+	})
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(values[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+func attributesKey(attrs pdata.AttributeMap) string {
+	keys := make([]string, 0, attrs.Len())
+	values := map[string]string{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		keys = append(keys, k)
+		values[k] = v.Type().String() + ":" + tracetranslator.AttributeValueToString(v, true)
+	})
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(strconv.Quote(k))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(values[k]))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
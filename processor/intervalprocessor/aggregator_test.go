@@ -0,0 +1,223 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// testMetrics returns metrics holding a single metric of a service, filled
+// by the function.
+func testMetrics(service string, fill func(pdata.Metric)) pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("service.name", service)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	rm.InstrumentationLibraryMetrics().At(0).Metrics().Resize(1)
+	fill(rm.InstrumentationLibraryMetrics().At(0).Metrics().At(0))
+	return md
+}
+
+func intGauge(name string, value int64, timestamp pdata.TimestampUnixNano, labels map[string]string) func(pdata.Metric) {
+	return func(metric pdata.Metric) {
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeIntGauge)
+		metric.IntGauge().InitEmpty()
+		metric.IntGauge().DataPoints().Resize(1)
+		point := metric.IntGauge().DataPoints().At(0)
+		point.LabelsMap().InitFromMap(labels)
+		point.SetTimestamp(timestamp)
+		point.SetValue(value)
+	}
+}
+
+func doubleSum(name string, temporality pdata.AggregationTemporality, value float64, start, timestamp pdata.TimestampUnixNano) func(pdata.Metric) {
+	return func(metric pdata.Metric) {
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeDoubleSum)
+		metric.DoubleSum().InitEmpty()
+		metric.DoubleSum().SetAggregationTemporality(temporality)
+		metric.DoubleSum().SetIsMonotonic(true)
+		metric.DoubleSum().DataPoints().Resize(1)
+		point := metric.DoubleSum().DataPoints().At(0)
+		point.SetStartTime(start)
+		point.SetTimestamp(timestamp)
+		point.SetValue(value)
+	}
+}
+
+func doubleHistogram(name string, temporality pdata.AggregationTemporality, bounds []float64, counts []uint64, sum float64, timestamp pdata.TimestampUnixNano) func(pdata.Metric) {
+	return func(metric pdata.Metric) {
+		metric.SetName(name)
+		metric.SetDataType(pdata.MetricDataTypeDoubleHistogram)
+		metric.DoubleHistogram().InitEmpty()
+		metric.DoubleHistogram().SetAggregationTemporality(temporality)
+		metric.DoubleHistogram().DataPoints().Resize(1)
+		point := metric.DoubleHistogram().DataPoints().At(0)
+		point.SetTimestamp(timestamp)
+		point.SetExplicitBounds(bounds)
+		point.SetBucketCounts(counts)
+		var count uint64
+		for _, c := range counts {
+			count += c
+		}
+		point.SetCount(count)
+		point.SetSum(sum)
+	}
+}
+
+// flushedMetric returns the single metric flushed by the aggregator.
+func flushedMetric(t *testing.T, a *aggregator) pdata.Metric {
+	md := a.flush()
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	ilms := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics()
+	require.Equal(t, 1, ilms.Len())
+	require.Equal(t, 1, ilms.At(0).Metrics().Len())
+	return ilms.At(0).Metrics().At(0)
+}
+
+func TestAggregateGauges(t *testing.T) {
+	tests := []struct {
+		aggregation string
+		want        int64
+	}{
+		{aggregation: aggregationLast, want: 5},
+		{aggregation: aggregationMin, want: 2},
+		{aggregation: aggregationMax, want: 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.aggregation, func(t *testing.T) {
+			a := newAggregator(tt.aggregation)
+			labels := map[string]string{"host": "a"}
+			a.add(testMetrics("api", intGauge("queue_size", 2, 10, labels)))
+			a.add(testMetrics("api", intGauge("queue_size", 9, 20, labels)))
+			a.add(testMetrics("api", intGauge("queue_size", 5, 30, labels)))
+			a.add(testMetrics("api", intGauge("queue_size", 7, 30, map[string]string{"host": "b"})))
+
+			points := flushedMetric(t, a).IntGauge().DataPoints()
+			require.Equal(t, 2, points.Len())
+			assert.Equal(t, tt.want, points.At(0).Value())
+			assert.Equal(t, pdata.TimestampUnixNano(30), points.At(0).Timestamp())
+			assert.Equal(t, int64(7), points.At(1).Value())
+		})
+	}
+}
+
+func TestAggregateLastIgnoresOlderPoints(t *testing.T) {
+	a := newAggregator(aggregationLast)
+	a.add(testMetrics("api", intGauge("queue_size", 5, 30, nil)))
+	a.add(testMetrics("api", intGauge("queue_size", 2, 10, nil)))
+
+	points := flushedMetric(t, a).IntGauge().DataPoints()
+	require.Equal(t, 1, points.Len())
+	assert.Equal(t, int64(5), points.At(0).Value())
+}
+
+func TestAggregateSums(t *testing.T) {
+	tests := []struct {
+		name        string
+		temporality pdata.AggregationTemporality
+		wantValue   float64
+		wantStart   pdata.TimestampUnixNano
+	}{
+		{
+			name:        "delta",
+			temporality: pdata.AggregationTemporalityDelta,
+			wantValue:   6,
+			wantStart:   5,
+		},
+		{
+			name:        "cumulative",
+			temporality: pdata.AggregationTemporalityCumulative,
+			wantValue:   3,
+			wantStart:   20,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAggregator(aggregationLast)
+			a.add(testMetrics("api", doubleSum("requests", tt.temporality, 1, 5, 10)))
+			a.add(testMetrics("api", doubleSum("requests", tt.temporality, 2, 10, 20)))
+			a.add(testMetrics("api", doubleSum("requests", tt.temporality, 3, 20, 30)))
+
+			metric := flushedMetric(t, a)
+			assert.Equal(t, tt.temporality, metric.DoubleSum().AggregationTemporality())
+			assert.True(t, metric.DoubleSum().IsMonotonic())
+			points := metric.DoubleSum().DataPoints()
+			require.Equal(t, 1, points.Len())
+			assert.Equal(t, tt.wantValue, points.At(0).Value())
+			assert.Equal(t, tt.wantStart, points.At(0).StartTime())
+			assert.Equal(t, pdata.TimestampUnixNano(30), points.At(0).Timestamp())
+		})
+	}
+}
+
+func TestAggregateHistograms(t *testing.T) {
+	bounds := []float64{10, 100}
+
+	a := newAggregator(aggregationLast)
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityDelta, bounds, []uint64{1, 2, 0}, 60, 10)))
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityDelta, bounds, []uint64{0, 1, 1}, 250, 20)))
+	point := flushedMetric(t, a).DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 3, 1}, point.BucketCounts())
+	assert.Equal(t, uint64(5), point.Count())
+	assert.Equal(t, float64(310), point.Sum())
+	assert.Equal(t, pdata.TimestampUnixNano(20), point.Timestamp())
+
+	// The points whose bounds changed replace the previous ones.
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityDelta, bounds, []uint64{1, 2, 0}, 60, 10)))
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityDelta, []float64{50}, []uint64{1, 1}, 70, 20)))
+	point = flushedMetric(t, a).DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 1}, point.BucketCounts())
+	assert.Equal(t, []float64{50}, point.ExplicitBounds())
+
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityCumulative, bounds, []uint64{1, 2, 0}, 60, 10)))
+	a.add(testMetrics("api", doubleHistogram("latency", pdata.AggregationTemporalityCumulative, bounds, []uint64{1, 3, 1}, 310, 20)))
+	point = flushedMetric(t, a).DoubleHistogram().DataPoints().At(0)
+	assert.Equal(t, []uint64{1, 3, 1}, point.BucketCounts())
+	assert.Equal(t, uint64(5), point.Count())
+}
+
+func TestAggregateStreams(t *testing.T) {
+	a := newAggregator(aggregationLast)
+	a.add(testMetrics("api", intGauge("queue_size", 1, 10, nil)))
+	a.add(testMetrics("worker", intGauge("queue_size", 2, 10, nil)))
+	a.add(testMetrics("api", doubleSum("queue_size", pdata.AggregationTemporalityDelta, 3, 0, 10)))
+	a.add(testMetrics("api", intGauge("queue_size", 4, 20, nil)))
+
+	md := a.flush()
+	rms := md.ResourceMetrics()
+	require.Equal(t, 2, rms.Len())
+
+	service, _ := rms.At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "api", service.StringVal())
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 2, metrics.Len())
+	assert.Equal(t, pdata.MetricDataTypeIntGauge, metrics.At(0).DataType())
+	assert.Equal(t, int64(4), metrics.At(0).IntGauge().DataPoints().At(0).Value())
+	assert.Equal(t, pdata.MetricDataTypeDoubleSum, metrics.At(1).DataType())
+
+	service, _ = rms.At(1).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "worker", service.StringVal())
+
+	// The aggregated points are forgotten once flushed.
+	assert.Equal(t, 0, a.flush().ResourceMetrics().Len())
+}
@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+// intervalProcessor aggregates the received data points, and sends the
+// aggregated points to the next consumer once per interval instead of
+// passing the received ones.
+type intervalProcessor struct {
+	logger       *zap.Logger
+	nextConsumer consumer.MetricsConsumer
+	interval     time.Duration
+
+	mu         sync.Mutex
+	aggregator *aggregator
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newIntervalProcessor(logger *zap.Logger, cfg *Config, nextConsumer consumer.MetricsConsumer) (*intervalProcessor, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+	return &intervalProcessor{
+		logger:       logger,
+		nextConsumer: nextConsumer,
+		interval:     cfg.Interval,
+		aggregator:   newAggregator(cfg.GaugeAggregation),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+func (p *intervalProcessor) start(context.Context, component.Host) error {
+	p.wg.Add(1)
+	go p.flushPeriodically()
+	return nil
+}
+
+// shutdown stops the flushes, then sends the points aggregated since the
+// last one.
+func (p *intervalProcessor) shutdown(ctx context.Context) error {
+	close(p.done)
+	p.wg.Wait()
+	return p.flush(ctx)
+}
+
+func (p *intervalProcessor) flushPeriodically() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := p.flush(context.Background()); err != nil {
+				p.logger.Warn("Failed to send the aggregated metrics", zap.Error(err))
+			}
+		case <-p.done:
+			return
+		}
+	}
+}
+
+func (p *intervalProcessor) flush(ctx context.Context) error {
+	p.mu.Lock()
+	md := p.aggregator.flush()
+	p.mu.Unlock()
+	if md.ResourceMetrics().Len() == 0 {
+		return nil
+	}
+	return p.nextConsumer.ConsumeMetrics(ctx, md)
+}
+
+// ProcessMetrics aggregates the data points, which are not passed to the
+// next consumer until the next flush.
+func (p *intervalProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	p.mu.Lock()
+	p.aggregator.add(md)
+	p.mu.Unlock()
+	return md, processorhelper.ErrSkipProcessingData
+}
@@ -0,0 +1,48 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package intervalprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestProcessMetrics(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, createDefaultConfig(), sink)
+	require.NoError(t, err)
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), testMetrics("api", doubleSum("requests", pdata.AggregationTemporalityDelta, 1, 0, 10))))
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), testMetrics("api", doubleSum("requests", pdata.AggregationTemporalityDelta, 2, 10, 20))))
+	// The points are only sent once per interval.
+	assert.Len(t, sink.AllMetrics(), 0)
+
+	// The points aggregated since the last flush are sent on shutdown.
+	require.NoError(t, mp.Shutdown(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	assert.Equal(t, 1, md.MetricCount())
+	point := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).DoubleSum().DataPoints().At(0)
+	assert.Equal(t, float64(3), point.Value())
+}
@@ -63,6 +63,32 @@ func (f *fakeClient) GetPodByIP(ip string) (*kube.Pod, bool) {
 	return p, ok
 }
 
+// GetPodByName looks up FakeClient.Pods map for a pod of the provided namespace and name.
+func (f *fakeClient) GetPodByName(namespace, name string) (*kube.Pod, bool) {
+	for _, p := range f.Pods {
+		if p.Namespace == namespace && p.Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+// GetPodsByName looks up FakeClient.Pods map for the pods of the provided name.
+func (f *fakeClient) GetPodsByName(name string) []*kube.Pod {
+	var pods []*kube.Pod
+	for _, p := range f.Pods {
+		if p.Name == name {
+			pods = append(pods, p)
+		}
+	}
+	return pods
+}
+
+// EstimateCacheSize returns the number of pods of FakeClient.Pods, without size estimate.
+func (f *fakeClient) EstimateCacheSize() kube.CacheSizeEstimate {
+	return kube.CacheSizeEstimate{Pods: len(f.Pods)}
+}
+
 // Start is a noop for FakeClient.
 func (f *fakeClient) Start() {
 	if f.Informer != nil {
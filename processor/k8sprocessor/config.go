@@ -15,6 +15,8 @@
 package k8sprocessor
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -32,6 +34,31 @@ type Config struct {
 	// directly from services to be able to correctly detect the pod IPs.
 	Passthrough bool `mapstructure:"passthrough"`
 
+	// LogFileAttribute is the attribute of the logs, or of their resource,
+	// holding the name or path of the file they were read from. The logs
+	// without IP context read from the container log files,
+	// /var/log/containers/<pod>_<namespace>_<container>-<id>.log, are
+	// associated with their pod by the name of the file. Default file_name.
+	LogFileAttribute string `mapstructure:"log_file_attribute"`
+
+	// PodNameAttribute is the resource attribute holding the name of the pod
+	// the data come from, e.g. host.name for the legacy agents setting it to
+	// the pod name rather than to its IP address. The data without IP context
+	// are associated with the pod of that name. Disabled if empty, the default.
+	PodNameAttribute string `mapstructure:"pod_name_attribute"`
+
+	// PodNamespaceAttribute is the resource attribute holding the namespace
+	// of the pod named by PodNameAttribute. The data without namespace are
+	// only associated if a single pod of that name is known across the
+	// namespaces. Default k8s.namespace.name.
+	PodNamespaceAttribute string `mapstructure:"pod_namespace_attribute"`
+
+	// CacheSizeEstimateInterval is the interval at which the number of cached
+	// pods and the approximate bytes they hold in memory are logged, to plan
+	// the memory of the collectors watching large clusters given the active
+	// extraction rules. Disabled if 0, the default.
+	CacheSizeEstimateInterval time.Duration `mapstructure:"cache_size_estimate_interval"`
+
 	// Extract section allows specifying extraction rules to extract
 	// data from k8s pod specs
 	Extract ExtractConfig `mapstructure:"extract"`
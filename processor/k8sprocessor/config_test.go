@@ -17,6 +17,7 @@ package k8sprocessor
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -53,7 +54,9 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "k8s_tagger",
 				NameVal: "k8s_tagger",
 			},
-			APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+			APIConfig:             k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+			LogFileAttribute:      "file_name",
+			PodNamespaceAttribute: "k8s.namespace.name",
 		})
 
 	p1 := config.Processors["k8s_tagger/2"]
@@ -63,8 +66,12 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "k8s_tagger",
 				NameVal: "k8s_tagger/2",
 			},
-			APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
-			Passthrough: false,
+			APIConfig:                 k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			Passthrough:               false,
+			LogFileAttribute:          "file_path",
+			PodNameAttribute:          "host.name",
+			PodNamespaceAttribute:     "namespace",
+			CacheSizeEstimateInterval: 10 * time.Minute,
 			Extract: ExtractConfig{
 				Metadata: []string{"podName", "podUID", "deployment", "cluster", "namespace", "node", "startTime"},
 				Annotations: []FieldExtractConfig{
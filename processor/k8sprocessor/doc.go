@@ -12,16 +12,34 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package k8sprocessor allow automatic tagging of spans and metrics with k8s metadata.
+// Package k8sprocessor allow automatic tagging of spans, metrics and logs with k8s metadata.
 //
 // The processor automatically discovers k8s resources (pods), extracts metadata from them and adds the
 // extracted metadata to the relevant spans and metrics. The processor use the kubernetes API to discover all pods
 // running in a cluster, keeps a record of their IP addresses and interesting metadata. Upon receiving spans,
 // the processor tries to identify the source IP address of the service that sent the spans and matches
-// it with the in memory data. To find a k8s pod producing metrics, the processor looks at "host.hostname"
-// resource attribute which is set by prometheus receiver and some metrics instrumentation libraries.
+// it with the in memory data. To find a k8s pod producing metrics, the processor looks at "net.peer.ip"
+// resource attribute set by receivers reporting the address of their clients, such as the statsd receiver,
+// and at "host.hostname" resource attribute which is set by prometheus receiver and some metrics
+// instrumentation libraries.
 // If a match is found, the cached metadata is added to the spans and metrics as resource attributes.
 //
+// Logs are matched by IP address as spans are. The logs read by an agent from the container log files of its
+// node, /var/log/containers/<pod>_<namespace>_<container>-<container id>.log, carry no IP address. Their pod
+// is instead identified by the name of the file, read from the "file_name" attribute of the resource or of all
+// its log records, which can be changed with the `log_file_attribute` config option. The "k8s.pod.name",
+// "k8s.namespace.name" and "k8s.container.name" resource attributes are set from the file name, even in
+// passthrough mode, and the cached metadata of the pod of that name is added.
+//
+// Some legacy agents set the "host.name" resource attribute to the name of the pod rather than to its IP address.
+// The data without IP address can be associated with their pod by name by setting the `pod_name_attribute`
+// config option to the resource attribute holding the pod name, e.g. "host.name" ("host.hostname" for the
+// metrics whose host name is held by the OpenCensus node). The namespace of the pod is read from the resource
+// attribute set by the `pod_namespace_attribute` config option, "k8s.namespace.name" by default. The data
+// without namespace are only associated if a single pod of that name is known: as pod names are only unique
+// within their namespace, the pods of the same name in several namespaces are ambiguous and none of them is
+// associated. The association by name is disabled in passthrough mode.
+//
 // RBAC
 //
 // TODO: mention the required RBAC rules.
@@ -99,6 +117,28 @@
 // that used to send data from agent to collector preserve "host.hostname" attribute. We need to rely on an additional
 // attribute keeping a k8s pod IP value in the passthrough mode.
 //
+// Memory usage
+//
+// The processor keeps in memory the extracted attributes of the pods it watches, as well as the pod objects
+// cached by its informer. Only the fields of the pods used by the processor are kept in the informer cache:
+// the name, namespace, start time, IP address and host network mode of the pods, the UID, cluster and node names
+// and creation time if extracted, and the labels and annotations read by the extraction rules. The specs of the
+// containers, the statuses and the other metadata, often several kilobytes per pod, are dropped as soon as the
+// pods are received.
+//
+// To plan the memory of the collectors watching large clusters, the `cache_size_estimate_interval` config option
+// logs at the given interval the number of cached pods and the approximate bytes they hold given the active
+// extraction rules, which can be multiplied by the expected number of pods:
+//
+//    k8s_tagger:
+//      cache_size_estimate_interval: 10m
+//
+// The estimates do not account for the memory allocator overhead and are typically 20% below the heap actually
+// used. The BenchmarkPodCache benchmark of the kube package measures the heap used per pod, with the pods
+// kept whole and trimmed:
+//
+//    go test -run none -bench BenchmarkPodCache ./kube
+//
 // Caveats
 //
 // There are some edge-cases and scenarios where k8s_tagger will not work properly.
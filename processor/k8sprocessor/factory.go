@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
@@ -39,7 +40,8 @@ func NewFactory() component.ProcessorFactory {
 		typeStr,
 		createDefaultConfig,
 		processorhelper.WithTraces(createTraceProcessor),
-		processorhelper.WithMetrics(createMetricsProcessor))
+		processorhelper.WithMetrics(createMetricsProcessor),
+		processorhelper.WithLogs(createLogsProcessor))
 }
 
 func createDefaultConfig() configmodels.Processor {
@@ -48,7 +50,9 @@ func createDefaultConfig() configmodels.Processor {
 			TypeVal: configmodels.Type(typeStr),
 			NameVal: typeStr,
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:             k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		LogFileAttribute:      defaultLogFileAttribute,
+		PodNamespaceAttribute: conventions.AttributeK8sNamespace,
 	}
 }
 
@@ -70,6 +74,15 @@ func createMetricsProcessor(
 	return newMetricsProcessor(params.Logger, nextMetricsConsumer, kubeClientProvider, createProcessorOpts(cfg)...)
 }
 
+func createLogsProcessor(
+	_ context.Context,
+	params component.ProcessorCreateParams,
+	cfg configmodels.Processor,
+	nextLogsConsumer consumer.LogsConsumer,
+) (component.LogsProcessor, error) {
+	return newLogsProcessor(params.Logger, nextLogsConsumer, kubeClientProvider, createProcessorOpts(cfg)...)
+}
+
 func createProcessorOpts(cfg configmodels.Processor) []Option {
 	oCfg := cfg.(*Config)
 	opts := []Option{}
@@ -89,5 +102,9 @@ func createProcessorOpts(cfg configmodels.Processor) []Option {
 	opts = append(opts, WithFilterFields(oCfg.Filter.Fields...))
 	opts = append(opts, WithAPIConfig(oCfg.APIConfig))
 
+	opts = append(opts, WithLogFileAttribute(oCfg.LogFileAttribute))
+	opts = append(opts, WithPodNameAssociation(oCfg.PodNameAttribute, oCfg.PodNamespaceAttribute))
+	opts = append(opts, WithCacheSizeEstimateInterval(oCfg.CacheSizeEstimateInterval))
+
 	return opts
 }
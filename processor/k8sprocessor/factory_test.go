@@ -45,6 +45,10 @@ func TestCreateProcessor(t *testing.T) {
 	assert.NotNil(t, mp)
 	assert.NoError(t, err)
 
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, nil)
+	assert.NotNil(t, lp)
+	assert.NoError(t, err)
+
 	oCfg := cfg.(*Config)
 	oCfg.Passthrough = true
 
@@ -55,4 +59,8 @@ func TestCreateProcessor(t *testing.T) {
 	mp, err = factory.CreateMetricsProcessor(context.Background(), params, cfg, nil)
 	assert.NotNil(t, mp)
 	assert.NoError(t, err)
+
+	lp, err = factory.CreateLogsProcessor(context.Background(), params, cfg, nil)
+	assert.NotNil(t, lp)
+	assert.NoError(t, err)
 }
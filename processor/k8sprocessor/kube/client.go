@@ -44,10 +44,16 @@ type WatchClient struct {
 	deploymentRegex *regexp.Regexp
 	deleteQueue     []deleteRequest
 	stopCh          chan struct{}
-
-	Pods    map[string]*Pod
-	Rules   ExtractionRules
-	Filters Filters
+	// podsByShortName indexes the pods of PodsByName by name alone, across
+	// the namespaces.
+	podsByShortName map[string][]*Pod
+
+	Pods map[string]*Pod
+	// PodsByName holds the pods by namespace and name, as keyed by
+	// podNameKey.
+	PodsByName map[string]*Pod
+	Rules      ExtractionRules
+	Filters    Filters
 }
 
 // Extract deployment name from the pod name. Pod name is created using
@@ -60,6 +66,8 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 	go c.deleteLoop(time.Second*30, defaultPodDeleteGracePeriod)
 
 	c.Pods = map[string]*Pod{}
+	c.PodsByName = map[string]*Pod{}
+	c.podsByShortName = map[string][]*Pod{}
 	if newClientSet == nil {
 		newClientSet = k8sconfig.MakeClient
 	}
@@ -80,7 +88,7 @@ func New(logger *zap.Logger, apiCfg k8sconfig.APIConfig, rules ExtractionRules,
 		zap.String("fieldSelector", fieldSelector.String()),
 	)
 	if newInformer == nil {
-		newInformer = newSharedInformer
+		newInformer = newTrimmingSharedInformer(rules)
 	}
 
 	c.informer = newInformer(c.kc, c.Filters.Namespace, labelSelector, fieldSelector)
@@ -159,6 +167,9 @@ func (c *WatchClient) deleteLoop(interval time.Duration, gracePeriod time.Durati
 						delete(c.Pods, d.ip)
 					}
 				}
+				if p, ok := c.PodsByName[podNameKey(d.namespace, d.name)]; ok && p.Address == d.ip {
+					c.deletePodByName(p)
+				}
 			}
 			c.m.Unlock()
 
@@ -183,6 +194,73 @@ func (c *WatchClient) GetPodByIP(ip string) (*Pod, bool) {
 	return nil, false
 }
 
+// GetPodByName takes the namespace and name of a pod and returns the pod.
+func (c *WatchClient) GetPodByName(namespace, name string) (*Pod, bool) {
+	c.m.RLock()
+	pod, ok := c.PodsByName[podNameKey(namespace, name)]
+	c.m.RUnlock()
+	if !ok || pod.Ignore {
+		return nil, false
+	}
+	return pod, true
+}
+
+// GetPodsByName takes the name of a pod and returns the pods of that name
+// across all the namespaces.
+func (c *WatchClient) GetPodsByName(name string) []*Pod {
+	var pods []*Pod
+	c.m.RLock()
+	for _, pod := range c.podsByShortName[name] {
+		if !pod.Ignore {
+			pods = append(pods, pod)
+		}
+	}
+	c.m.RUnlock()
+	return pods
+}
+
+// setPodByName stores the pod in PodsByName and in podsByShortName, in place
+// of the pod of the same namespace and name. The caller must hold the lock.
+func (c *WatchClient) setPodByName(pod *Pod) {
+	key := podNameKey(pod.Namespace, pod.Name)
+	if old, ok := c.PodsByName[key]; ok {
+		pods := c.podsByShortName[pod.Name]
+		for i, p := range pods {
+			if p == old {
+				pods[i] = pod
+				break
+			}
+		}
+	} else {
+		c.podsByShortName[pod.Name] = append(c.podsByShortName[pod.Name], pod)
+	}
+	c.PodsByName[key] = pod
+}
+
+// deletePodByName removes the pod from PodsByName and from podsByShortName.
+// The caller must hold the lock.
+func (c *WatchClient) deletePodByName(pod *Pod) {
+	delete(c.PodsByName, podNameKey(pod.Namespace, pod.Name))
+	pods := c.podsByShortName[pod.Name]
+	for i, p := range pods {
+		if p == pod {
+			pods[i] = pods[len(pods)-1]
+			pods[len(pods)-1] = nil
+			pods = pods[:len(pods)-1]
+			break
+		}
+	}
+	if len(pods) == 0 {
+		delete(c.podsByShortName, pod.Name)
+	} else {
+		c.podsByShortName[pod.Name] = pods
+	}
+}
+
+func podNameKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
 func (c *WatchClient) extractPodAttributes(pod *api_v1.Pod) map[string]string {
 	tags := map[string]string{}
 	if c.Rules.PodName {
@@ -270,6 +348,7 @@ func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
 	}
 	newPod := &Pod{
 		Name:      pod.Name,
+		Namespace: pod.Namespace,
 		Address:   pod.Status.PodIP,
 		StartTime: pod.Status.StartTime,
 	}
@@ -280,6 +359,7 @@ func (c *WatchClient) addOrUpdatePod(pod *api_v1.Pod) {
 		newPod.Attributes = c.extractPodAttributes(pod)
 	}
 	c.Pods[pod.Status.PodIP] = newPod
+	c.setPodByName(newPod)
 }
 
 func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
@@ -288,14 +368,17 @@ func (c *WatchClient) forgetPod(pod *api_v1.Pod) {
 	}
 	c.m.RLock()
 	p, ok := c.GetPodByIP(pod.Status.PodIP)
+	// The IP address may already be reused by another pod.
+	named, namedOK := c.PodsByName[podNameKey(pod.Namespace, pod.Name)]
 	c.m.RUnlock()
 
-	if ok && p.Name == pod.Name {
+	if (ok && p.Name == pod.Name) || (namedOK && named.Address == pod.Status.PodIP) {
 		c.deleteMut.Lock()
 		c.deleteQueue = append(c.deleteQueue, deleteRequest{
-			ip:   pod.Status.PodIP,
-			name: pod.Name,
-			ts:   time.Now(),
+			ip:        pod.Status.PodIP,
+			name:      pod.Name,
+			namespace: pod.Namespace,
+			ts:        time.Now(),
 		})
 		c.deleteMut.Unlock()
 	}
@@ -291,6 +291,94 @@ func TestGetIgnoredPod(t *testing.T) {
 	assert.False(t, ok)
 }
 
+func TestGetPodByName(t *testing.T) {
+	c, _ := newTestClient(t)
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns1"
+	pod.Status.PodIP = "1.1.1.1"
+	c.handlePodAdd(pod)
+
+	got, ok := c.GetPodByName("ns1", "podA")
+	require.True(t, ok)
+	assert.Equal(t, "1.1.1.1", got.Address)
+	assert.Equal(t, "ns1", got.Namespace)
+
+	_, ok = c.GetPodByName("ns2", "podA")
+	assert.False(t, ok)
+
+	c.PodsByName[podNameKey("ns1", "podA")].Ignore = true
+	got, ok = c.GetPodByName("ns1", "podA")
+	assert.Nil(t, got)
+	assert.False(t, ok)
+}
+
+func TestGetPodsByName(t *testing.T) {
+	c, _ := newTestClient(t)
+	for i, namespace := range []string{"ns1", "ns2"} {
+		pod := &api_v1.Pod{}
+		pod.Name = "podA"
+		pod.Namespace = namespace
+		pod.Status.PodIP = fmt.Sprintf("1.1.1.%d", i+1)
+		c.handlePodAdd(pod)
+	}
+
+	assert.Len(t, c.GetPodsByName("podA"), 2)
+	assert.Empty(t, c.GetPodsByName("podB"))
+
+	// an update of the pod replaces it in the index
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns1"
+	pod.Status.PodIP = "1.1.1.3"
+	c.handlePodUpdate(nil, pod)
+	require.Len(t, c.podsByShortName["podA"], 2)
+	assert.Same(t, c.PodsByName[podNameKey("ns1", "podA")], c.podsByShortName["podA"][0])
+
+	c.PodsByName[podNameKey("ns1", "podA")].Ignore = true
+	pods := c.GetPodsByName("podA")
+	require.Len(t, pods, 1)
+	assert.Equal(t, "ns2", pods[0].Namespace)
+}
+
+func TestDeleteLoopPodsByName(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	pod := &api_v1.Pod{}
+	pod.Name = "podA"
+	pod.Namespace = "ns1"
+	pod.Status.PodIP = "1.1.1.1"
+	c.handlePodAdd(pod)
+
+	// the IP address is reused by another pod before podA is deleted
+	podB := &api_v1.Pod{}
+	podB.Name = "podB"
+	podB.Namespace = "ns1"
+	podB.Status.PodIP = "1.1.1.1"
+	c.handlePodAdd(podB)
+	assert.Equal(t, 2, len(c.PodsByName))
+
+	c.handlePodDelete(pod)
+	assert.Equal(t, 1, len(c.deleteQueue))
+
+	gracePeriod := time.Millisecond * 10
+	go c.deleteLoop(time.Millisecond, gracePeriod)
+	go func() {
+		time.Sleep(gracePeriod + (time.Millisecond * 50))
+		c.m.Lock()
+		assert.Equal(t, 1, len(c.Pods))
+		assert.Equal(t, "podB", c.Pods["1.1.1.1"].Name)
+		assert.Equal(t, 1, len(c.PodsByName))
+		_, ok := c.PodsByName[podNameKey("ns1", "podB")]
+		assert.True(t, ok)
+		assert.Equal(t, 1, len(c.podsByShortName))
+		assert.Len(t, c.podsByShortName["podB"], 1)
+		c.m.Unlock()
+		close(c.stopCh)
+	}()
+	<-c.stopCh
+}
+
 func TestHandlerWrongType(t *testing.T) {
 	c, logs := newTestClientWithRulesAndFilters(t, ExtractionRules{}, Filters{})
 	assert.Equal(t, logs.Len(), 0)
@@ -634,7 +722,7 @@ func Test_selectorsFromFilters(t *testing.T) {
 	}
 }
 
-func newTestClientWithRulesAndFilters(t *testing.T, e ExtractionRules, f Filters) (*WatchClient, *observer.ObservedLogs) {
+func newTestClientWithRulesAndFilters(t testing.TB, e ExtractionRules, f Filters) (*WatchClient, *observer.ObservedLogs) {
 	observedLogger, logs := observer.New(zapcore.WarnLevel)
 	logger := zap.New(observedLogger)
 	c, err := New(logger, k8sconfig.APIConfig{}, e, f, newFakeAPIClientset, NewFakeInformer)
@@ -53,6 +53,30 @@ func newSharedInformer(
 	return informer
 }
 
+// newTrimmingSharedInformer returns an InformerProvider of shared informers
+// keeping only the fields of the pods used by the rules, see trimPod.
+func newTrimmingSharedInformer(rules ExtractionRules) InformerProvider {
+	return func(
+		client kubernetes.Interface,
+		namespace string,
+		ls labels.Selector,
+		fs fields.Selector,
+	) cache.SharedInformer {
+		trim := func(pod *api_v1.Pod) *api_v1.Pod {
+			return trimPod(pod, rules)
+		}
+		informer := cache.NewSharedInformer(
+			&cache.ListWatch{
+				ListFunc:  trimmingListFunc(informerListFuncWithSelectors(client, namespace, ls, fs), trim),
+				WatchFunc: trimmingWatchFunc(informerWatchFuncWithSelectors(client, namespace, ls, fs), trim),
+			},
+			&api_v1.Pod{},
+			watchSyncPeriod,
+		)
+		return informer
+	}
+}
+
 func informerListFuncWithSelectors(client kubernetes.Interface, namespace string, ls labels.Selector, fs fields.Selector) cache.ListFunc {
 	return func(opts metav1.ListOptions) (runtime.Object, error) {
 		opts.LabelSelector = ls.String()
@@ -69,3 +93,33 @@ func informerWatchFuncWithSelectors(client kubernetes.Interface, namespace strin
 		return client.CoreV1().Pods(namespace).Watch(context.Background(), opts)
 	}
 }
+
+func trimmingListFunc(list cache.ListFunc, trim func(*api_v1.Pod) *api_v1.Pod) cache.ListFunc {
+	return func(opts metav1.ListOptions) (runtime.Object, error) {
+		obj, err := list(opts)
+		if err != nil {
+			return nil, err
+		}
+		if pods, ok := obj.(*api_v1.PodList); ok {
+			for i := range pods.Items {
+				pods.Items[i] = *trim(&pods.Items[i])
+			}
+		}
+		return obj, nil
+	}
+}
+
+func trimmingWatchFunc(watchFunc cache.WatchFunc, trim func(*api_v1.Pod) *api_v1.Pod) cache.WatchFunc {
+	return func(opts metav1.ListOptions) (watch.Interface, error) {
+		w, err := watchFunc(opts)
+		if err != nil {
+			return nil, err
+		}
+		return watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+			if pod, ok := e.Object.(*api_v1.Pod); ok {
+				e.Object = trim(pod)
+			}
+			return e, true
+		}), nil
+	}
+}
@@ -15,6 +15,8 @@
 package kube
 
 import (
+	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -22,7 +24,10 @@ import (
 	"github.com/stretchr/testify/require"
 	api_v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -37,6 +42,59 @@ func Test_newSharedInformer(t *testing.T) {
 	assert.NotNil(t, informer)
 }
 
+func Test_newTrimmingSharedInformer(t *testing.T) {
+	labelSelector, fieldSelector, err := selectorsFromFilters(Filters{})
+	require.NoError(t, err)
+	client, err := newFakeAPIClientset(k8sconfig.APIConfig{})
+	require.NoError(t, err)
+	informer := newTrimmingSharedInformer(ExtractionRules{})(client, "testns", labelSelector, fieldSelector)
+	assert.NotNil(t, informer)
+}
+
+func Test_trimmingListFunc(t *testing.T) {
+	pod := newRealisticPod(1)
+	client := fake.NewSimpleClientset(pod)
+	ls, fs, err := selectorsFromFilters(Filters{})
+	require.NoError(t, err)
+	trim := func(pod *api_v1.Pod) *api_v1.Pod { return trimPod(pod, benchmarkRules) }
+	listFunc := trimmingListFunc(informerListFuncWithSelectors(client, pod.Namespace, ls, fs), trim)
+	obj, err := listFunc(metav1.ListOptions{})
+	require.NoError(t, err)
+	pods := obj.(*api_v1.PodList).Items
+	require.Len(t, pods, 1)
+	assert.Equal(t, *trimPod(pod, benchmarkRules), pods[0])
+
+	failing := func(metav1.ListOptions) (runtime.Object, error) { return nil, errors.New("unavailable") }
+	_, err = trimmingListFunc(failing, trim)(metav1.ListOptions{})
+	assert.EqualError(t, err, "unavailable")
+}
+
+func Test_trimmingWatchFunc(t *testing.T) {
+	pod := newRealisticPod(1)
+	client := fake.NewSimpleClientset()
+	ls, fs, err := selectorsFromFilters(Filters{})
+	require.NoError(t, err)
+	trim := func(pod *api_v1.Pod) *api_v1.Pod { return trimPod(pod, benchmarkRules) }
+	watchFunc := trimmingWatchFunc(informerWatchFuncWithSelectors(client, pod.Namespace, ls, fs), trim)
+	w, err := watchFunc(metav1.ListOptions{})
+	require.NoError(t, err)
+	defer w.Stop()
+
+	_, err = client.CoreV1().Pods(pod.Namespace).Create(context.Background(), pod, metav1.CreateOptions{})
+	require.NoError(t, err)
+	select {
+	case e := <-w.ResultChan():
+		assert.Equal(t, watch.Added, e.Type)
+		assert.Equal(t, trimPod(pod, benchmarkRules), e.Object)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no watch event received")
+	}
+
+	failing := func(metav1.ListOptions) (watch.Interface, error) { return nil, errors.New("unavailable") }
+	_, err = trimmingWatchFunc(failing, trim)(metav1.ListOptions{})
+	assert.EqualError(t, err, "unavailable")
+}
+
 func Test_informerListFuncWithSelectors(t *testing.T) {
 	ls, fs, err := selectorsFromFilters(Filters{
 		Fields: []FieldFilter{
@@ -47,6 +47,9 @@ var (
 // Client defines the main interface that allows querying pods by metadata.
 type Client interface {
 	GetPodByIP(string) (*Pod, bool)
+	GetPodByName(namespace, name string) (*Pod, bool)
+	GetPodsByName(name string) []*Pod
+	EstimateCacheSize() CacheSizeEstimate
 	Start()
 	Stop()
 }
@@ -61,6 +64,7 @@ type APIClientsetProvider func(config k8sconfig.APIConfig) (kubernetes.Interface
 // Pod represents a kubernetes pod.
 type Pod struct {
 	Name       string
+	Namespace  string
 	Address    string
 	Attributes map[string]string
 	StartTime  *metav1.Time
@@ -70,9 +74,10 @@ type Pod struct {
 }
 
 type deleteRequest struct {
-	ip   string
-	name string
-	ts   time.Time
+	ip        string
+	name      string
+	namespace string
+	ts        time.Time
 }
 
 // Filters is used to instruct the client on how to filter out k8s pods.
@@ -0,0 +1,189 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"unsafe"
+
+	api_v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Approximate sizes in bytes of the Go runtime structures holding the cache,
+// on 64-bit platforms. A map of strings has a header and buckets of 8 entries,
+// a new bucket being allocated for each 6.5 entries on average. An entry of
+// the large maps indexing the pods accounts for its key and value headers,
+// its hash and the unused slots of the buckets.
+const (
+	mapSize       = 48
+	bucketSize    = 8 + 8*2*16 + 8
+	mapEntrySize  = 48
+	pointerSize   = 8
+	podStructSize = int(unsafe.Sizeof(Pod{}))
+	podObjectSize = int(unsafe.Sizeof(api_v1.Pod{}))
+	timeSize      = int(unsafe.Sizeof(metav1.Time{}))
+)
+
+// CacheSizeEstimate is the approximate memory held by the pods cached by a
+// client, including the pod objects kept by its informer.
+type CacheSizeEstimate struct {
+	// Pods is the number of cached pods.
+	Pods int
+	// Bytes is the approximate number of bytes held by the cached pods.
+	Bytes int
+}
+
+// BytesPerPod returns the average number of bytes held by a cached pod.
+func (e CacheSizeEstimate) BytesPerPod() int {
+	if e.Pods == 0 {
+		return 0
+	}
+	return e.Bytes / e.Pods
+}
+
+// trimPod returns a copy of the pod holding only the fields read by the
+// client to extract the attributes of the pod with the rules, decide whether
+// it is ignored and key it, so that the informer does not keep the whole pod
+// specs and statuses, often several kilobytes each, in memory. The fields of
+// the metadata not extracted by the rules are dropped as well.
+func trimPod(pod *api_v1.Pod, rules ExtractionRules) *api_v1.Pod {
+	trimmed := &api_v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			ResourceVersion: pod.ResourceVersion,
+			Labels:          trimFields(pod.Labels, rules.Labels),
+			Annotations:     trimFields(pod.Annotations, rules.Annotations, ignoreAnnotation),
+		},
+		Spec: api_v1.PodSpec{
+			HostNetwork: pod.Spec.HostNetwork,
+		},
+		Status: api_v1.PodStatus{
+			PodIP:     pod.Status.PodIP,
+			StartTime: pod.Status.StartTime,
+		},
+	}
+	if rules.PodUID {
+		trimmed.UID = pod.UID
+	}
+	if rules.StartTime {
+		trimmed.CreationTimestamp = pod.CreationTimestamp
+	}
+	if rules.Cluster {
+		trimmed.ClusterName = pod.ClusterName
+	}
+	if rules.Node {
+		trimmed.Spec.NodeName = pod.Spec.NodeName
+	}
+	return trimmed
+}
+
+// trimFields returns the labels or annotations extracted by the rules, as
+// well as the extra keys.
+func trimFields(fields map[string]string, rules []FieldExtractionRule, extraKeys ...string) map[string]string {
+	var trimmed map[string]string
+	keep := func(key string) {
+		if v, ok := fields[key]; ok {
+			if trimmed == nil {
+				trimmed = map[string]string{}
+			}
+			trimmed[key] = v
+		}
+	}
+	for _, r := range rules {
+		keep(r.Key)
+	}
+	for _, key := range extraKeys {
+		keep(key)
+	}
+	return trimmed
+}
+
+// EstimateCacheSize returns the approximate memory held by the pods cached by
+// the client and by its informer. The pods without IP address are only held
+// by the informer and are not counted.
+func (c *WatchClient) EstimateCacheSize() CacheSizeEstimate {
+	var e CacheSizeEstimate
+	c.m.RLock()
+	pods := make(map[*Pod]struct{}, len(c.Pods))
+	for ip, pod := range c.Pods {
+		e.Bytes += estimateMapEntrySize(ip)
+		pods[pod] = struct{}{}
+	}
+	for key, pod := range c.PodsByName {
+		e.Bytes += estimateMapEntrySize(key)
+		pods[pod] = struct{}{}
+	}
+	for name, named := range c.podsByShortName {
+		e.Bytes += estimateMapEntrySize(name) + cap(named)*pointerSize
+	}
+	c.m.RUnlock()
+
+	// The cached pods are replaced rather than modified, they can be read
+	// without holding the lock.
+	for pod := range pods {
+		e.Bytes += estimatePodSize(pod)
+	}
+	e.Pods = len(pods)
+
+	for _, obj := range c.informer.GetStore().List() {
+		if pod, ok := obj.(*api_v1.Pod); ok {
+			e.Bytes += estimateMapEntrySize(podNameKey(pod.Namespace, pod.Name)) + estimatePodObjectSize(pod)
+		}
+	}
+	return e
+}
+
+// estimatePodSize returns the approximate number of bytes held by a cached
+// pod, excluding the entries of the maps it is indexed by.
+func estimatePodSize(pod *Pod) int {
+	size := podStructSize + len(pod.Name) + len(pod.Namespace) + len(pod.Address) + estimateMapSize(pod.Attributes)
+	if pod.StartTime != nil {
+		size += timeSize
+	}
+	return size
+}
+
+// estimatePodObjectSize returns the approximate number of bytes held by a pod
+// object trimmed by trimPod. The fields dropped by trimPod are not accounted.
+func estimatePodObjectSize(pod *api_v1.Pod) int {
+	size := podObjectSize +
+		len(pod.Name) + len(pod.Namespace) + len(pod.UID) + len(pod.ResourceVersion) + len(pod.ClusterName) +
+		len(pod.Spec.NodeName) + len(pod.Status.PodIP) +
+		estimateMapSize(pod.Labels) + estimateMapSize(pod.Annotations)
+	if pod.Status.StartTime != nil {
+		size += timeSize
+	}
+	return size
+}
+
+func estimateMapSize(m map[string]string) int {
+	if m == nil {
+		return 0
+	}
+	buckets := 1
+	for len(m) > 8 && float64(len(m)) > 6.5*float64(buckets) {
+		buckets *= 2
+	}
+	size := mapSize + buckets*bucketSize
+	for k, v := range m {
+		size += len(k) + len(v)
+	}
+	return size
+}
+
+func estimateMapEntrySize(key string) int {
+	return mapEntrySize + len(key)
+}
@@ -0,0 +1,281 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kube
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	api_v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+var benchmarkRules = ExtractionRules{
+	Deployment: true,
+	Namespace:  true,
+	PodName:    true,
+	PodUID:     true,
+	Node:       true,
+	Cluster:    true,
+	StartTime:  true,
+	Labels: []FieldExtractionRule{
+		{Name: "app", Key: "app.kubernetes.io/name"},
+		{Name: "version", Key: "app.kubernetes.io/version"},
+	},
+	Annotations: []FieldExtractionRule{
+		{Name: "git.sha", Key: "kubernetes.io/change-cause", Regex: regexp.MustCompile(`GIT_SHA=(?P<value>\w+)`)},
+	},
+}
+
+// newRealisticPod returns a pod of a deployment with the labels, annotations,
+// spec and status typically set on the pods of a production cluster.
+func newRealisticPod(i int) *api_v1.Pod {
+	start := meta_v1.Now()
+	labels := map[string]string{
+		"app.kubernetes.io/name":       "checkout",
+		"app.kubernetes.io/version":    "1.4.2",
+		"app.kubernetes.io/component":  "backend",
+		"app.kubernetes.io/part-of":    "webshop",
+		"app.kubernetes.io/managed-by": "helm",
+		"helm.sh/chart":                "checkout-1.4.2",
+		"pod-template-hash":            "5d8f7c9b6",
+		"team":                         "payments",
+	}
+	container := api_v1.Container{
+		Name:    "checkout",
+		Image:   "registry.example.com/webshop/checkout:1.4.2",
+		Command: []string{"/bin/checkout", "--config", "/etc/checkout/config.yaml"},
+		Ports:   []api_v1.ContainerPort{{Name: "http", ContainerPort: 8080, Protocol: api_v1.ProtocolTCP}},
+		Resources: api_v1.ResourceRequirements{
+			Limits:   api_v1.ResourceList{api_v1.ResourceCPU: resource.MustParse("1"), api_v1.ResourceMemory: resource.MustParse("512Mi")},
+			Requests: api_v1.ResourceList{api_v1.ResourceCPU: resource.MustParse("250m"), api_v1.ResourceMemory: resource.MustParse("256Mi")},
+		},
+		VolumeMounts: []api_v1.VolumeMount{{Name: "config", MountPath: "/etc/checkout"}},
+	}
+	for j := 0; j < 10; j++ {
+		container.Env = append(container.Env, api_v1.EnvVar{Name: fmt.Sprintf("CHECKOUT_SETTING_%d", j), Value: "some-configuration-value"})
+	}
+	return &api_v1.Pod{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:              fmt.Sprintf("checkout-5d8f7c9b6-%05d", i),
+			Namespace:         "webshop",
+			ClusterName:       "production",
+			UID:               types.UID(fmt.Sprintf("3f2b8a4e-1c6d-4e8f-9a0b-%012d", i)),
+			ResourceVersion:   "123456789",
+			CreationTimestamp: start,
+			Labels:            labels,
+			Annotations: map[string]string{
+				"kubernetes.io/change-cause":                       "2020-09-15T18:34:33Z APP_NAME=checkout GIT_SHA=58a1e39 CI_BUILD=4120",
+				"kubectl.kubernetes.io/last-applied-configuration": strings.Repeat(`{"apiVersion":"apps/v1","kind":"Deployment"}`, 40),
+				"prometheus.io/scrape":                             "true",
+				"prometheus.io/port":                               "8080",
+			},
+			OwnerReferences: []meta_v1.OwnerReference{{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "checkout-5d8f7c9b6"}},
+			ManagedFields: []meta_v1.ManagedFieldsEntry{
+				{Manager: "kube-controller-manager", Operation: meta_v1.ManagedFieldsOperationUpdate, FieldsType: "FieldsV1",
+					FieldsV1: &meta_v1.FieldsV1{Raw: []byte(strings.Repeat(`{"f:metadata":{"f:labels":{}}}`, 30))}},
+			},
+		},
+		Spec: api_v1.PodSpec{
+			NodeName:           fmt.Sprintf("ip-10-0-%d-%d.ec2.internal", i/256%256, i%256),
+			ServiceAccountName: "checkout",
+			Containers:         []api_v1.Container{container, container},
+			Volumes:            []api_v1.Volume{{Name: "config"}},
+		},
+		Status: api_v1.PodStatus{
+			Phase:     api_v1.PodRunning,
+			PodIP:     fmt.Sprintf("10.1.%d.%d", i/256%256, i%256),
+			HostIP:    "10.0.0.1",
+			StartTime: &start,
+			Conditions: []api_v1.PodCondition{
+				{Type: api_v1.PodReady, Status: api_v1.ConditionTrue},
+				{Type: api_v1.PodScheduled, Status: api_v1.ConditionTrue},
+			},
+			ContainerStatuses: []api_v1.ContainerStatus{
+				{Name: "checkout", Ready: true, Image: container.Image, ContainerID: "docker://" + strings.Repeat("0123456789abcdef", 4)},
+				{Name: "checkout", Ready: true, Image: container.Image, ContainerID: "docker://" + strings.Repeat("fedcba9876543210", 4)},
+			},
+		},
+	}
+}
+
+func Test_trimPod(t *testing.T) {
+	pod := newRealisticPod(1)
+	pod.Annotations[ignoreAnnotation] = "false"
+	trimmed := trimPod(pod, benchmarkRules)
+
+	assert.Equal(t, map[string]string{
+		"app.kubernetes.io/name":    "checkout",
+		"app.kubernetes.io/version": "1.4.2",
+	}, trimmed.Labels)
+	assert.Equal(t, map[string]string{
+		"kubernetes.io/change-cause": pod.Annotations["kubernetes.io/change-cause"],
+		ignoreAnnotation:             "false",
+	}, trimmed.Annotations)
+	assert.Empty(t, trimmed.ManagedFields)
+	assert.Empty(t, trimmed.OwnerReferences)
+	assert.Empty(t, trimmed.Spec.Containers)
+	assert.Empty(t, trimmed.Status.ContainerStatuses)
+
+	c, _ := newTestClientWithRulesAndFilters(t, benchmarkRules, Filters{})
+	assert.Equal(t, c.extractPodAttributes(pod), c.extractPodAttributes(trimmed))
+	assert.Equal(t, c.shouldIgnorePod(pod), c.shouldIgnorePod(trimmed))
+	assert.Equal(t, pod.Status.PodIP, trimmed.Status.PodIP)
+	assert.Equal(t, pod.Status.StartTime, trimmed.Status.StartTime)
+	assert.Equal(t, pod.ResourceVersion, trimmed.ResourceVersion)
+	key, err := cache.MetaNamespaceKeyFunc(trimmed)
+	assert.NoError(t, err)
+	assert.Equal(t, "webshop/"+pod.Name, key)
+
+	pod.Spec.HostNetwork = true
+	assert.True(t, c.shouldIgnorePod(trimPod(pod, benchmarkRules)))
+	pod.Spec.HostNetwork = false
+	pod.Annotations[ignoreAnnotation] = "True"
+	assert.True(t, c.shouldIgnorePod(trimPod(pod, benchmarkRules)))
+
+	// the attributes extracted from a trimmed pod are the same with any rules
+	for _, rules := range []ExtractionRules{
+		{},
+		{PodUID: true},
+		{StartTime: true},
+		{Cluster: true},
+		{Node: true},
+		{Deployment: true, PodName: true, Namespace: true},
+		{Labels: benchmarkRules.Labels},
+		{Annotations: benchmarkRules.Annotations},
+	} {
+		c, _ := newTestClientWithRulesAndFilters(t, rules, Filters{})
+		assert.Equal(t, c.extractPodAttributes(pod), c.extractPodAttributes(trimPod(pod, rules)))
+	}
+
+	// the metadata not extracted are dropped
+	bare := trimPod(pod, ExtractionRules{})
+	assert.Empty(t, bare.UID)
+	assert.Empty(t, bare.ClusterName)
+	assert.Empty(t, bare.Spec.NodeName)
+	assert.True(t, bare.CreationTimestamp.IsZero())
+	assert.Nil(t, bare.Labels)
+	assert.Equal(t, map[string]string{ignoreAnnotation: "True"}, bare.Annotations)
+	assert.Equal(t, pod.Name, bare.Name)
+	assert.Equal(t, pod.Status.PodIP, bare.Status.PodIP)
+	assert.Equal(t, pod.Status.StartTime, bare.Status.StartTime)
+
+	// no map is allocated for the pods without any of the fields
+	assert.Nil(t, trimPod(&api_v1.Pod{}, benchmarkRules).Labels)
+	assert.Nil(t, trimPod(&api_v1.Pod{}, benchmarkRules).Annotations)
+}
+
+func TestEstimateCacheSize(t *testing.T) {
+	c, _ := newTestClientWithRulesAndFilters(t, benchmarkRules, Filters{})
+	assert.Equal(t, CacheSizeEstimate{}, c.EstimateCacheSize())
+	assert.Equal(t, 0, c.EstimateCacheSize().BytesPerPod())
+
+	c.handlePodAdd(newRealisticPod(1))
+	one := c.EstimateCacheSize()
+	assert.Equal(t, 1, one.Pods)
+	assert.Equal(t, one.Bytes, one.BytesPerPod())
+	assert.Greater(t, one.Bytes, podStructSize)
+
+	c.handlePodAdd(newRealisticPod(2))
+	two := c.EstimateCacheSize()
+	assert.Equal(t, 2, two.Pods)
+	assert.InDelta(t, one.BytesPerPod(), two.BytesPerPod(), 16)
+
+	// fewer attributes are extracted without rules
+	bare, _ := newTestClient(t)
+	bare.handlePodAdd(newRealisticPod(1))
+	assert.Less(t, bare.EstimateCacheSize().Bytes, one.Bytes)
+}
+
+func TestEstimatePodObjectSize(t *testing.T) {
+	pod := newRealisticPod(1)
+	trimmed := trimPod(pod, benchmarkRules)
+	size := estimatePodObjectSize(trimmed)
+	assert.Greater(t, size, podObjectSize)
+	assert.Equal(t, size, estimatePodObjectSize(trimPod(trimmed, benchmarkRules)))
+
+	trimmed.Labels = nil
+	assert.Less(t, estimatePodObjectSize(trimmed), size)
+}
+
+// heapInUse returns the bytes of the heap in use after a garbage collection.
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+// benchmarkPodCache measures the memory held by the cache of the client and
+// the store of the informer once the pods are added, as the informer does,
+// reporting it in bytes/pod.
+func benchmarkPodCache(b *testing.B, rules ExtractionRules, transform func(*api_v1.Pod) *api_v1.Pod) {
+	const pods = 1000
+	b.ReportAllocs()
+	var held uint64
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		c, _ := newTestClientWithRulesAndFilters(b, rules, Filters{})
+		store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+		before := heapInUse()
+		b.StartTimer()
+
+		for j := 0; j < pods; j++ {
+			pod := transform(newRealisticPod(j))
+			if err := store.Add(pod); err != nil {
+				b.Fatal(err)
+			}
+			c.handlePodAdd(pod)
+		}
+
+		b.StopTimer()
+		if after := heapInUse(); after > before {
+			held += after - before
+		}
+		runtime.KeepAlive(store)
+		runtime.KeepAlive(c)
+		b.StartTimer()
+	}
+	b.ReportMetric(float64(held)/float64(b.N*pods), "bytes/pod")
+}
+
+func BenchmarkPodCache(b *testing.B) {
+	full := func(pod *api_v1.Pod) *api_v1.Pod { return pod }
+	trim := func(rules ExtractionRules) func(*api_v1.Pod) *api_v1.Pod {
+		return func(pod *api_v1.Pod) *api_v1.Pod { return trimPod(pod, rules) }
+	}
+	b.Run("full_pods", func(b *testing.B) { benchmarkPodCache(b, benchmarkRules, full) })
+	b.Run("trimmed_pods", func(b *testing.B) { benchmarkPodCache(b, benchmarkRules, trim(benchmarkRules)) })
+	b.Run("trimmed_pods_no_rules", func(b *testing.B) { benchmarkPodCache(b, ExtractionRules{}, trim(ExtractionRules{})) })
+}
+
+func BenchmarkEstimateCacheSize(b *testing.B) {
+	c, _ := newTestClientWithRulesAndFilters(b, benchmarkRules, Filters{})
+	for j := 0; j < 1000; j++ {
+		c.handlePodAdd(newRealisticPod(j))
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.EstimateCacheSize()
+	}
+}
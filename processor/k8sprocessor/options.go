@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"time"
 
 	"k8s.io/apimachinery/pkg/selection"
 
@@ -62,6 +63,39 @@ func WithPassthrough() Option {
 	}
 }
 
+// WithLogFileAttribute sets the attribute of the logs holding the name or path of the file
+// they were read from, used to identify the pod of the logs read from the container log
+// files. The association is disabled if the attribute is empty.
+func WithLogFileAttribute(attribute string) Option {
+	return func(p *kubernetesprocessor) error {
+		p.logFileAttribute = attribute
+		return nil
+	}
+}
+
+// WithPodNameAssociation sets the resource attributes holding the name and the namespace
+// of the pod of the data without IP context, used to associate the data with their pod
+// by its name. The association is disabled if the name attribute is empty.
+func WithPodNameAssociation(nameAttribute, namespaceAttribute string) Option {
+	return func(p *kubernetesprocessor) error {
+		p.podNameAttribute = nameAttribute
+		p.podNamespaceAttribute = namespaceAttribute
+		return nil
+	}
+}
+
+// WithCacheSizeEstimateInterval sets the interval at which the approximate memory held
+// by the cached pods is logged. The estimates are not logged if the interval is 0.
+func WithCacheSizeEstimateInterval(interval time.Duration) Option {
+	return func(p *kubernetesprocessor) error {
+		if interval < 0 {
+			return fmt.Errorf("cache size estimate interval must not be negative: %v", interval)
+		}
+		p.cacheSizeEstimateInterval = interval
+		return nil
+	}
+}
+
 // WithExtractMetadata allows specifying options to control extraction of pod metadata.
 // If no fields explicitly provided, all metadata extracted by default.
 func WithExtractMetadata(fields ...string) Option {
@@ -19,6 +19,7 @@ import (
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/selection"
@@ -69,6 +70,13 @@ func TestWithPassthrough(t *testing.T) {
 	assert.True(t, p.passthroughMode)
 }
 
+func TestWithCacheSizeEstimateInterval(t *testing.T) {
+	p := &kubernetesprocessor{}
+	assert.NoError(t, WithCacheSizeEstimateInterval(time.Minute)(p))
+	assert.Equal(t, time.Minute, p.cacheSizeEstimateInterval)
+	assert.Error(t, WithCacheSizeEstimateInterval(-time.Minute)(p))
+}
+
 func TestWithExtractAnnotations(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -17,12 +17,17 @@ package k8sprocessor
 import (
 	"context"
 	"net"
+	"path"
+	"regexp"
+	"time"
 
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
@@ -33,21 +38,37 @@ import (
 const (
 	k8sIPLabelName    string = "k8s.pod.ip"
 	clientIPLabelName string = "ip"
+	peerIPLabelName   string = "net.peer.ip"
+
+	defaultLogFileAttribute = "file_name"
 )
 
+// containerLogFileRegex matches the names of the files the kubelet writes the
+// logs of the containers to, /var/log/containers/<pod>_<namespace>_<container>-<id>.log.
+var containerLogFileRegex = regexp.MustCompile(`^(?P<pod>[^_]+)_(?P<namespace>[^_]+)_(?P<container>.+)-(?P<id>[0-9a-f]{64})\.log$`)
+
 type kubernetesprocessor struct {
-	logger              *zap.Logger
-	apiConfig           k8sconfig.APIConfig
-	kc                  kube.Client
-	passthroughMode     bool
-	rules               kube.ExtractionRules
-	filters             kube.Filters
-	nextTraceConsumer   consumer.TraceConsumer
-	nextMetricsConsumer consumer.MetricsConsumer
+	logger                *zap.Logger
+	apiConfig             k8sconfig.APIConfig
+	kc                    kube.Client
+	passthroughMode       bool
+	rules                 kube.ExtractionRules
+	filters               kube.Filters
+	logFileAttribute      string
+	podNameAttribute      string
+	podNamespaceAttribute string
+	// cacheSizeEstimateInterval is the interval of the logs of the cache
+	// size estimates, disabled if 0.
+	cacheSizeEstimateInterval time.Duration
+	stopCh                    chan struct{}
+	nextTraceConsumer         consumer.TraceConsumer
+	nextMetricsConsumer       consumer.MetricsConsumer
+	nextLogsConsumer          consumer.LogsConsumer
 }
 
 var _ (component.TraceProcessor) = (*kubernetesprocessor)(nil)
 var _ (component.MetricsProcessor) = (*kubernetesprocessor)(nil)
+var _ (component.LogsProcessor) = (*kubernetesprocessor)(nil)
 
 // newTraceProcessor returns a component.TraceProcessor that adds the WithAttributeMap(attributes) to all spans
 // passed to it.
@@ -90,6 +111,26 @@ func newMetricsProcessor(
 	return kp, nil
 }
 
+// newLogsProcessor returns a component.LogsProcessor that adds the k8s attributes to logs passed to it.
+func newLogsProcessor(
+	logger *zap.Logger,
+	nextLogsConsumer consumer.LogsConsumer,
+	kubeClient kube.ClientProvider,
+	options ...Option,
+) (component.LogsProcessor, error) {
+	kp := &kubernetesprocessor{logger: logger, nextLogsConsumer: nextLogsConsumer, logFileAttribute: defaultLogFileAttribute}
+	for _, opt := range options {
+		if err := opt(kp); err != nil {
+			return nil, err
+		}
+	}
+	err := kp.initKubeClient(logger, kubeClient)
+	if err != nil {
+		return nil, err
+	}
+	return kp, nil
+}
+
 func (kp *kubernetesprocessor) initKubeClient(logger *zap.Logger, kubeClient kube.ClientProvider) error {
 	if kubeClient == nil {
 		kubeClient = kube.New
@@ -111,6 +152,10 @@ func (kp *kubernetesprocessor) GetCapabilities() component.ProcessorCapabilities
 func (kp *kubernetesprocessor) Start(_ context.Context, _ component.Host) error {
 	if !kp.passthroughMode {
 		go kp.kc.Start()
+		if kp.cacheSizeEstimateInterval > 0 && kp.stopCh == nil {
+			kp.stopCh = make(chan struct{})
+			go kp.logCacheSizeEstimates(kp.cacheSizeEstimateInterval, kp.stopCh)
+		}
 	}
 	return nil
 }
@@ -118,10 +163,34 @@ func (kp *kubernetesprocessor) Start(_ context.Context, _ component.Host) error
 func (kp *kubernetesprocessor) Shutdown(context.Context) error {
 	if !kp.passthroughMode {
 		kp.kc.Stop()
+		if kp.stopCh != nil {
+			close(kp.stopCh)
+		}
 	}
 	return nil
 }
 
+// logCacheSizeEstimates logs the approximate memory held by the cached pods at
+// each interval until stopCh is closed.
+func (kp *kubernetesprocessor) logCacheSizeEstimates(interval time.Duration, stopCh <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e := kp.kc.EstimateCacheSize()
+			kp.logger.Info(
+				"k8s pod cache size estimate",
+				zap.Int("pods", e.Pods),
+				zap.Int("bytesPerPod", e.BytesPerPod()),
+				zap.Int("totalBytes", e.Bytes),
+			)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
 func (kp *kubernetesprocessor) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
 	rss := td.ResourceSpans()
 	for i := 0; i < rss.Len(); i++ {
@@ -161,6 +230,9 @@ func (kp *kubernetesprocessor) ConsumeTraces(ctx context.Context, td pdata.Trace
 
 		// add k8s tags to resource
 		attrsToAdd := kp.getAttributesForPodIP(podIP)
+		if podIP == "" && !resource.IsNil() {
+			attrsToAdd = kp.getAttributesForPodNameAttributes(resource.Attributes())
+		}
 		if len(attrsToAdd) == 0 {
 			continue
 		}
@@ -192,6 +264,13 @@ func (kp *kubernetesprocessor) ConsumeMetrics(ctx context.Context, metrics pdata
 			podIP = md.Resource.Labels[k8sIPLabelName]
 		}
 
+		// Receivers accepting metrics over the network, such as the StatsD one,
+		// can report the address of the client that sent them.
+		if podIP == "" && md.Resource.GetLabels()[peerIPLabelName] != "" {
+			podIP = md.Resource.Labels[peerIPLabelName]
+			md.Resource.Labels[k8sIPLabelName] = podIP
+		}
+
 		// Most of the metric receivers uses "host.hostname" resource label (which is represented as
 		// Node.Identifier.HostName in OpenCensus format) to identify metrics origin.
 		// In k8s environment, it's set to a pod IP address. If the value doesn't represent
@@ -212,6 +291,14 @@ func (kp *kubernetesprocessor) ConsumeMetrics(ctx context.Context, metrics pdata
 			}
 		}
 
+		// Legacy agents set the host name to the pod name rather than to its IP address,
+		// the metrics without IP address are associated with the pod of that name.
+		if podIP == "" && !kp.passthroughMode {
+			for k, v := range kp.getAttributesForPodNameLabels(md) {
+				md.Resource.Labels[k] = v
+			}
+		}
+
 		// Ignore metrics if cannot infer IP address of the origin pod.
 		if podIP == "" {
 			continue
@@ -237,6 +324,192 @@ func (kp *kubernetesprocessor) ConsumeMetrics(ctx context.Context, metrics pdata
 	return kp.nextMetricsConsumer.ConsumeMetrics(ctx, internaldata.OCSliceToMetrics(mds))
 }
 
+// ConsumeLogs process logs and add k8s metadata using the pod IP, or else the name of the
+// container log file they were read from, as pod origin.
+func (kp *kubernetesprocessor) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+
+		var podIP string
+		resource := rl.Resource()
+
+		// check if a collector/agent or a prior processor has already
+		// annotated the batch with IP.
+		if !resource.IsNil() {
+			podIP = kp.k8sIPFromAttributes(resource.Attributes())
+		}
+
+		// Check if the receiver detected client IP.
+		if podIP == "" {
+			if c, ok := client.FromContext(ctx); ok {
+				podIP = c.IP
+			}
+		}
+
+		if podIP != "" {
+			if resource.IsNil() {
+				resource.InitEmpty()
+			}
+			resource.Attributes().InsertString(k8sIPLabelName, podIP)
+			if !kp.passthroughMode {
+				insertAttributes(resource.Attributes(), kp.getAttributesForPodIP(podIP))
+			}
+			continue
+		}
+
+		// The logs read by an agent from the container log files of its node
+		// have no IP context, the pod is identified by the name of the file.
+		pod, namespace, container, ok := parseContainerLogFile(kp.logFileName(rl))
+		if !ok {
+			if !kp.passthroughMode && !resource.IsNil() {
+				insertAttributes(resource.Attributes(), kp.getAttributesForPodNameAttributes(resource.Attributes()))
+			}
+			continue
+		}
+		if resource.IsNil() {
+			resource.InitEmpty()
+		}
+		attrs := resource.Attributes()
+		attrs.InsertString(conventions.AttributeK8sPod, pod)
+		attrs.InsertString(conventions.AttributeK8sNamespace, namespace)
+		attrs.InsertString(conventions.AttributeK8sContainer, container)
+		if !kp.passthroughMode {
+			insertAttributes(attrs, kp.getAttributesForPodName(namespace, pod))
+		}
+	}
+
+	return kp.nextLogsConsumer.ConsumeLogs(ctx, ld)
+}
+
+// logFileName returns the name of the file the logs of the resource were read from, held by
+// the resource or by all its log records.
+func (kp *kubernetesprocessor) logFileName(rl pdata.ResourceLogs) string {
+	if kp.logFileAttribute == "" {
+		return ""
+	}
+	if resource := rl.Resource(); !resource.IsNil() {
+		if name := stringAttributeFromMap(resource.Attributes(), kp.logFileAttribute); name != "" {
+			return name
+		}
+	}
+
+	var name string
+	ills := rl.InstrumentationLibraryLogs()
+	for i := 0; i < ills.Len(); i++ {
+		ill := ills.At(i)
+		if ill.IsNil() {
+			continue
+		}
+		logs := ill.Logs()
+		for j := 0; j < logs.Len(); j++ {
+			lr := logs.At(j)
+			if lr.IsNil() {
+				continue
+			}
+			recordName := stringAttributeFromMap(lr.Attributes(), kp.logFileAttribute)
+			if recordName == "" || (name != "" && recordName != name) {
+				// The log records come from different files.
+				return ""
+			}
+			name = recordName
+		}
+	}
+	return name
+}
+
+// parseContainerLogFile returns the pod, namespace and container of a container log file,
+// the file name being either a base name or a path.
+func parseContainerLogFile(file string) (pod, namespace, container string, ok bool) {
+	if file == "" {
+		return "", "", "", false
+	}
+	match := containerLogFileRegex.FindStringSubmatch(path.Base(file))
+	if match == nil {
+		return "", "", "", false
+	}
+	return match[1], match[2], match[3], true
+}
+
+func insertAttributes(attrs pdata.AttributeMap, attrsToAdd map[string]string) {
+	for k, v := range attrsToAdd {
+		attrs.InsertString(k, v)
+	}
+}
+
+func (kp *kubernetesprocessor) getAttributesForPodName(namespace, name string) map[string]string {
+	pod, ok := kp.kc.GetPodByName(namespace, name)
+	if !ok {
+		return nil
+	}
+	return pod.Attributes
+}
+
+// getAttributesForPodNameAttributes returns the attributes of the pod named by the resource
+// attributes configured for the pod name association.
+func (kp *kubernetesprocessor) getAttributesForPodNameAttributes(attrs pdata.AttributeMap) map[string]string {
+	if kp.podNameAttribute == "" {
+		return nil
+	}
+	var namespace string
+	if kp.podNamespaceAttribute != "" {
+		namespace = stringAttributeFromMap(attrs, kp.podNamespaceAttribute)
+	}
+	return kp.getAttributesForPodNameAssociation(namespace, stringAttributeFromMap(attrs, kp.podNameAttribute))
+}
+
+// getAttributesForPodNameLabels returns the attributes of the pod named by the resource labels
+// configured for the pod name association, the host.hostname attribute being held by the node
+// identifier in OpenCensus format.
+func (kp *kubernetesprocessor) getAttributesForPodNameLabels(md *consumerdata.MetricsData) map[string]string {
+	if kp.podNameAttribute == "" {
+		return nil
+	}
+	name := md.Resource.GetLabels()[kp.podNameAttribute]
+	if name == "" && kp.podNameAttribute == conventions.AttributeHostHostname {
+		name = md.Node.GetIdentifier().GetHostName()
+	}
+	var namespace string
+	if kp.podNamespaceAttribute != "" {
+		namespace = md.Resource.GetLabels()[kp.podNamespaceAttribute]
+	}
+	attrs := kp.getAttributesForPodNameAssociation(namespace, name)
+	if len(attrs) > 0 {
+		if md.Resource == nil {
+			md.Resource = &resourcepb.Resource{}
+		}
+		if md.Resource.Labels == nil {
+			md.Resource.Labels = map[string]string{}
+		}
+	}
+	return attrs
+}
+
+// getAttributesForPodNameAssociation returns the attributes of the pod of the namespace and name,
+// or, if the namespace is unknown, of the only pod of that name. The pods of the same name in
+// several namespaces are ambiguous, none of them is associated.
+func (kp *kubernetesprocessor) getAttributesForPodNameAssociation(namespace, name string) map[string]string {
+	if name == "" {
+		return nil
+	}
+	if namespace != "" {
+		return kp.getAttributesForPodName(namespace, name)
+	}
+	pods := kp.kc.GetPodsByName(name)
+	if len(pods) > 1 {
+		kp.logger.Debug("Pod name matches pods of several namespaces, not associating",
+			zap.String("pod", name), zap.Int("pods", len(pods)))
+		return nil
+	}
+	if len(pods) == 0 {
+		return nil
+	}
+	return pods[0].Attributes
+}
+
 func (kp *kubernetesprocessor) getAttributesForPodIP(ip string) map[string]string {
 	pod, ok := kp.kc.GetPodByIP(ip)
 	if !ok {
@@ -32,6 +32,8 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/k8sprocessor/kube"
@@ -357,6 +359,28 @@ func TestStartStop(t *testing.T) {
 	assert.True(t, controller.HasStopped())
 }
 
+func TestCacheSizeEstimateLogs(t *testing.T) {
+	observedLogger, logs := observer.New(zapcore.InfoLevel)
+	p, err := newTraceProcessor(
+		zap.New(observedLogger),
+		exportertest.NewNopTraceExporter(),
+		newFakeClient,
+		WithCacheSizeEstimateInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	kp := p.(*kubernetesprocessor)
+	kp.kc.(*fakeClient).Pods["1.1.1.1"] = &kube.Pod{Name: "PodA"}
+
+	assert.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return logs.FilterMessage("k8s pod cache size estimate").Len() > 0
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, p.Shutdown(context.Background()))
+
+	fields := logs.FilterMessage("k8s pod cache size estimate").All()[0].ContextMap()
+	assert.Equal(t, int64(1), fields["pods"])
+}
+
 func TestNewMetricsProcessor(t *testing.T) {
 	_, err := newMetricsProcessor(
 		zap.NewNop(),
@@ -488,6 +512,39 @@ func TestMetricsProcessorPicksUpPassthoughPodIp(t *testing.T) {
 	require.Equal(t, "2", md.Resource.Labels["1"])
 }
 
+func TestMetricsProcessorPicksUpPeerIP(t *testing.T) {
+	next := &exportertest.SinkMetricsExporter{}
+	p, err := newMetricsProcessor(
+		zap.NewNop(),
+		next,
+		newFakeClient,
+		WithExtractMetadata(metadataPodName),
+	)
+	require.NoError(t, err)
+	kp := p.(*kubernetesprocessor)
+	kc := kp.kc.(*fakeClient)
+	kc.Pods["3.3.3.3"] = &kube.Pod{
+		Name: "PodA",
+		Attributes: map[string]string{
+			"k": "v",
+		},
+	}
+
+	mds := internaldata.MetricsToOC(generateMetricsWithPodIP())
+	require.Len(t, mds, 1)
+	mds[0].Resource.Labels = map[string]string{peerIPLabelName: "3.3.3.3"}
+
+	assert.NoError(t, p.ConsumeMetrics(context.Background(), internaldata.OCSliceToMetrics(mds)))
+	require.Len(t, next.AllMetrics(), 1)
+	mds = internaldata.MetricsToOC(next.AllMetrics()[0])
+	require.Equal(t, len(mds), 1)
+	md := mds[0]
+	require.Equal(t, 3, len(md.Resource.Labels))
+	require.Equal(t, "3.3.3.3", md.Resource.Labels[k8sIPLabelName])
+	require.Equal(t, "3.3.3.3", md.Resource.Labels[peerIPLabelName])
+	require.Equal(t, "v", md.Resource.Labels["k"])
+}
+
 func TestMetricsProcessorInvalidIP(t *testing.T) {
 	next := &exportertest.SinkMetricsExporter{}
 	p, err := newMetricsProcessor(
@@ -636,3 +693,301 @@ func assertResourceHasStringAttribute(t *testing.T, r pdata.Resource, k, v strin
 	assert.EqualValues(t, pdata.AttributeValueSTRING, got.Type(), "attribute %s is not of type string", k)
 	assert.EqualValues(t, v, got.StringVal(), "attribute %s is not equal to %s", k, v)
 }
+
+const testContainerLogFile = "/var/log/containers/PodA_ns1_app-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.log"
+
+func generateLogs(fileNames ...string) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rls.At(0).Resource().InitEmpty()
+	ills := rls.At(0).InstrumentationLibraryLogs()
+	ills.Resize(1)
+	logs := ills.At(0).Logs()
+	logs.Resize(len(fileNames))
+	for i, name := range fileNames {
+		logs.At(i).Body().SetStringVal("line")
+		if name != "" {
+			logs.At(i).Attributes().InsertString(defaultLogFileAttribute, name)
+		}
+	}
+	return ld
+}
+
+func assertResourceAttributes(t *testing.T, ld pdata.Logs, expected map[string]string) {
+	rls := ld.ResourceLogs()
+	require.Equal(t, 1, rls.Len())
+	attrs := rls.At(0).Resource().Attributes()
+	got := map[string]string{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		got[k] = v.StringVal()
+	})
+	assert.Equal(t, expected, got)
+}
+
+func TestNewLogsProcessor(t *testing.T) {
+	_, err := newLogsProcessor(
+		zap.NewNop(),
+		exportertest.NewNopLogsExporter(),
+		newFakeClient,
+	)
+	require.NoError(t, err)
+}
+
+func TestLogsProcessorBadOption(t *testing.T) {
+	opt := func(p *kubernetesprocessor) error {
+		return fmt.Errorf("bad option")
+	}
+	p, err := newLogsProcessor(
+		zap.NewNop(),
+		exportertest.NewNopLogsExporter(),
+		newFakeClient,
+		opt,
+	)
+	assert.Nil(t, p)
+	assert.Error(t, err)
+	assert.Equal(t, err.Error(), "bad option")
+}
+
+func TestLogsProcessorPodIP(t *testing.T) {
+	next := &exportertest.SinkLogsExporter{}
+	p, err := newLogsProcessor(
+		zap.NewNop(),
+		next,
+		newFakeClient,
+	)
+	require.NoError(t, err)
+	kc := p.(*kubernetesprocessor).kc.(*fakeClient)
+	kc.Pods["1.1.1.1"] = &kube.Pod{
+		Name:       "PodA",
+		Namespace:  "ns1",
+		Address:    "1.1.1.1",
+		Attributes: map[string]string{"k": "v"},
+	}
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "1.1.1.1"})
+	assert.NoError(t, p.ConsumeLogs(ctx, generateLogs(testContainerLogFile)))
+	require.Len(t, next.AllLogs(), 1)
+	assertResourceAttributes(t, next.AllLogs()[0], map[string]string{
+		k8sIPLabelName: "1.1.1.1",
+		"k":            "v",
+	})
+}
+
+func TestLogsProcessorLogFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		logs     func() pdata.Logs
+		expected map[string]string
+	}{
+		{
+			name: "record attribute",
+			logs: func() pdata.Logs {
+				return generateLogs(testContainerLogFile, testContainerLogFile)
+			},
+			expected: map[string]string{
+				"k8s.pod.name":       "PodA",
+				"k8s.namespace.name": "ns1",
+				"k8s.container.name": "app",
+				"k":                  "v",
+			},
+		},
+		{
+			name: "resource attribute",
+			logs: func() pdata.Logs {
+				ld := generateLogs("")
+				ld.ResourceLogs().At(0).Resource().Attributes().InsertString(defaultLogFileAttribute, testContainerLogFile)
+				return ld
+			},
+			expected: map[string]string{
+				defaultLogFileAttribute: testContainerLogFile,
+				"k8s.pod.name":          "PodA",
+				"k8s.namespace.name":    "ns1",
+				"k8s.container.name":    "app",
+				"k":                     "v",
+			},
+		},
+		{
+			name: "different files",
+			logs: func() pdata.Logs {
+				return generateLogs(testContainerLogFile, "/var/log/containers/PodB_ns1_app-0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef.log")
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "missing file",
+			logs: func() pdata.Logs {
+				return generateLogs(testContainerLogFile, "")
+			},
+			expected: map[string]string{},
+		},
+		{
+			name: "not a container log file",
+			logs: func() pdata.Logs {
+				return generateLogs("/var/log/syslog")
+			},
+			expected: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &exportertest.SinkLogsExporter{}
+			p, err := newLogsProcessor(
+				zap.NewNop(),
+				next,
+				newFakeClient,
+			)
+			require.NoError(t, err)
+			kc := p.(*kubernetesprocessor).kc.(*fakeClient)
+			kc.Pods["1.1.1.1"] = &kube.Pod{
+				Name:       "PodA",
+				Namespace:  "ns1",
+				Address:    "1.1.1.1",
+				Attributes: map[string]string{"k": "v"},
+			}
+
+			assert.NoError(t, p.ConsumeLogs(context.Background(), tt.logs()))
+			require.Len(t, next.AllLogs(), 1)
+			assertResourceAttributes(t, next.AllLogs()[0], tt.expected)
+		})
+	}
+}
+
+func TestLogsProcessorLogFilePassthrough(t *testing.T) {
+	next := &exportertest.SinkLogsExporter{}
+	p, err := newLogsProcessor(
+		zap.NewNop(),
+		next,
+		newFakeClient,
+		WithPassthrough(),
+		WithLogFileAttribute("log.file.path"),
+	)
+	require.NoError(t, err)
+
+	ld := generateLogs("")
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("log.file.path", testContainerLogFile)
+	assert.NoError(t, p.ConsumeLogs(context.Background(), ld))
+	require.Len(t, next.AllLogs(), 1)
+	assertResourceAttributes(t, next.AllLogs()[0], map[string]string{
+		"log.file.path":      testContainerLogFile,
+		"k8s.pod.name":       "PodA",
+		"k8s.namespace.name": "ns1",
+		"k8s.container.name": "app",
+	})
+}
+
+func addPodNameTestPods(kc *fakeClient) {
+	kc.Pods["1.1.1.1"] = &kube.Pod{Name: "PodA", Namespace: "ns1", Attributes: map[string]string{"k": "a1"}}
+	kc.Pods["2.2.2.2"] = &kube.Pod{Name: "PodA", Namespace: "ns2", Attributes: map[string]string{"k": "a2"}}
+	kc.Pods["3.3.3.3"] = &kube.Pod{Name: "PodB", Namespace: "ns1", Attributes: map[string]string{"k": "b1"}}
+}
+
+func TestTraceProcessorPodName(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  []Option
+		attrs    map[string]string
+		expected string
+	}{
+		{
+			name:     "name and namespace",
+			options:  []Option{WithPodNameAssociation("host.name", "k8s.namespace.name")},
+			attrs:    map[string]string{"host.name": "PodA", "k8s.namespace.name": "ns2"},
+			expected: "a2",
+		},
+		{
+			name:     "unique name",
+			options:  []Option{WithPodNameAssociation("host.name", "k8s.namespace.name")},
+			attrs:    map[string]string{"host.name": "PodB"},
+			expected: "b1",
+		},
+		{
+			name:    "name of several namespaces",
+			options: []Option{WithPodNameAssociation("host.name", "k8s.namespace.name")},
+			attrs:   map[string]string{"host.name": "PodA"},
+		},
+		{
+			name:     "namespace attribute not configured",
+			options:  []Option{WithPodNameAssociation("host.name", "")},
+			attrs:    map[string]string{"host.name": "PodB", "k8s.namespace.name": "ns2"},
+			expected: "b1",
+		},
+		{
+			name:    "unknown namespace",
+			options: []Option{WithPodNameAssociation("host.name", "k8s.namespace.name")},
+			attrs:   map[string]string{"host.name": "PodB", "k8s.namespace.name": "ns2"},
+		},
+		{
+			name:  "disabled",
+			attrs: map[string]string{"host.name": "PodB"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &exportertest.SinkTraceExporter{}
+			p, err := newTraceProcessor(zap.NewNop(), next, newFakeClient, tt.options...)
+			require.NoError(t, err)
+			addPodNameTestPods(p.(*kubernetesprocessor).kc.(*fakeClient))
+
+			traces := generateTraces()
+			traces.ResourceSpans().At(0).Resource().InitEmpty()
+			for k, v := range tt.attrs {
+				traces.ResourceSpans().At(0).Resource().Attributes().InsertString(k, v)
+			}
+			require.NoError(t, p.ConsumeTraces(context.Background(), traces))
+
+			require.Len(t, next.AllTraces(), 1)
+			got, ok := next.AllTraces()[0].ResourceSpans().At(0).Resource().Attributes().Get("k")
+			if tt.expected == "" {
+				assert.False(t, ok)
+			} else {
+				require.True(t, ok)
+				assert.Equal(t, tt.expected, got.StringVal())
+			}
+		})
+	}
+}
+
+func TestMetricsProcessorPodName(t *testing.T) {
+	next := &exportertest.SinkMetricsExporter{}
+	p, err := newMetricsProcessor(
+		zap.NewNop(),
+		next,
+		newFakeClient,
+		WithPodNameAssociation("host.hostname", "k8s.namespace.name"),
+	)
+	require.NoError(t, err)
+	addPodNameTestPods(p.(*kubernetesprocessor).kc.(*fakeClient))
+
+	mds := internaldata.MetricsToOC(generateMetricsWithHostname())
+	mds[0].Node.Identifier.HostName = "PodB"
+	require.NoError(t, p.ConsumeMetrics(context.Background(), internaldata.OCSliceToMetrics(mds)))
+
+	require.Len(t, next.AllMetrics(), 1)
+	mds = internaldata.MetricsToOC(next.AllMetrics()[0])
+	require.Len(t, mds, 1)
+	assert.Equal(t, map[string]string{"k": "b1"}, mds[0].Resource.Labels)
+}
+
+func TestLogsProcessorPodName(t *testing.T) {
+	next := &exportertest.SinkLogsExporter{}
+	p, err := newLogsProcessor(
+		zap.NewNop(),
+		next,
+		newFakeClient,
+		WithPodNameAssociation("host.name", "k8s.namespace.name"),
+	)
+	require.NoError(t, err)
+	addPodNameTestPods(p.(*kubernetesprocessor).kc.(*fakeClient))
+
+	ld := generateLogs("")
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("host.name", "PodA")
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("k8s.namespace.name", "ns1")
+	assert.NoError(t, p.ConsumeLogs(context.Background(), ld))
+	require.Len(t, next.AllLogs(), 1)
+	assertResourceAttributes(t, next.AllLogs()[0], map[string]string{
+		"host.name":          "PodA",
+		"k8s.namespace.name": "ns1",
+		"k":                  "a1",
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the logs transform processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Operators are the operators applied to each log record, in order.
+	Operators []OperatorConfig `mapstructure:"operators"`
+}
+
+// OperatorConfig defines an operator, the settings used depending on its
+// type.
+type OperatorConfig struct {
+	// Type is the type of the operator, one of "regex_parser", "json_parser"
+	// or "severity_parser".
+	Type string `mapstructure:"type"`
+
+	// ParseFrom is the field parsed by the operator, either "body",
+	// "body.<key>" for an entry of a map body, or "attributes.<key>".
+	// Defaults to "body" for the regex and JSON parsers, required for the
+	// severity parser.
+	ParseFrom string `mapstructure:"parse_from"`
+
+	// OnError is what happens to the log records the operator fails to
+	// parse, either "send" (the default) sending them unchanged by the
+	// operator, or "drop".
+	OnError string `mapstructure:"on_error"`
+
+	// Regex is the regular expression of the regex parser, its named
+	// capturing groups being added as attributes.
+	Regex string `mapstructure:"regex"`
+
+	// Mapping maps the severities of the severity parser, e.g. "error", to
+	// the additional values parsed as these severities.
+	Mapping map[string][]string `mapstructure:"mapping"`
+}
@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// jsonParser adds the entries of a JSON object held by a field of the log
+// records as attributes.
+type jsonParser struct {
+	from field
+}
+
+func (p *jsonParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.getString(lr)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+	decoder.UseNumber()
+	var object map[string]interface{}
+	if err := decoder.Decode(&object); err != nil {
+		return fmt.Errorf("%s is not a JSON object: %w", p.from, err)
+	}
+
+	attrs := lr.Attributes()
+	for _, key := range sortedKeys(object) {
+		attrs.Upsert(key, toAttributeValue(object[key]))
+	}
+	return nil
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toAttributeValue converts a decoded JSON value, integers being converted
+// to int values and the other numbers to double values.
+func toAttributeValue(v interface{}) pdata.AttributeValue {
+	switch v := v.(type) {
+	case string:
+		return pdata.NewAttributeValueString(v)
+	case bool:
+		return pdata.NewAttributeValueBool(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return pdata.NewAttributeValueInt(i)
+		}
+		f, _ := v.Float64()
+		return pdata.NewAttributeValueDouble(f)
+	case map[string]interface{}:
+		m := pdata.NewAttributeMap()
+		for _, key := range sortedKeys(v) {
+			m.Insert(key, toAttributeValue(v[key]))
+		}
+		av := pdata.NewAttributeValueMap()
+		av.SetMapVal(m)
+		return av
+	case []interface{}:
+		arr := pdata.NewAnyValueArray()
+		for _, entry := range v {
+			arr.Append(toAttributeValue(entry))
+		}
+		av := pdata.NewAttributeValueArray()
+		av.SetArrayVal(arr)
+		return av
+	}
+	return pdata.NewAttributeValueNull()
+}
@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestJSONParser(t *testing.T) {
+	p := &jsonParser{from: field{attribute: true, key: "message"}}
+
+	lr := testLogRecord("")
+	lr.Attributes().InsertString("message", `{"msg":"done","status":200,"duration":1.5,"cached":false,"user":{"id":"42"},"tags":["a",1],"trace":null}`)
+	require.NoError(t, p.process(lr))
+
+	attrs := attributesAsMap(lr.Attributes())
+	tags := attrs["tags"].(pdata.AttributeValue).ArrayVal()
+	delete(attrs, "tags")
+	trace := attrs["trace"].(pdata.AttributeValue)
+	delete(attrs, "trace")
+	assert.Equal(t, map[string]interface{}{
+		"message":  `{"msg":"done","status":200,"duration":1.5,"cached":false,"user":{"id":"42"},"tags":["a",1],"trace":null}`,
+		"msg":      "done",
+		"status":   int64(200),
+		"duration": 1.5,
+		"cached":   false,
+		"user":     map[string]interface{}{"id": "42"},
+	}, attrs)
+	require.Equal(t, 2, tags.Len())
+	assert.Equal(t, "a", tags.At(0).StringVal())
+	assert.Equal(t, int64(1), tags.At(1).IntVal())
+	assert.Equal(t, pdata.AttributeValueNULL, trace.Type())
+}
+
+func TestJSONParserErrors(t *testing.T) {
+	p := &jsonParser{}
+
+	assert.EqualError(t, p.process(testLogRecord(`["a"]`)),
+		"body is not a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}")
+	assert.EqualError(t, p.process(testLogRecord(`{"msg":`)),
+		"body is not a JSON object: unexpected EOF")
+
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	assert.EqualError(t, p.process(lr), "field body not found")
+}
@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	tracetranslator "go.opentelemetry.io/collector/translator/trace"
+)
+
+const (
+	regexParserType    = "regex_parser"
+	jsonParserType     = "json_parser"
+	severityParserType = "severity_parser"
+
+	onErrorSend = "send"
+	onErrorDrop = "drop"
+
+	bodyField       = "body"
+	bodyPrefix      = "body."
+	attributePrefix = "attributes."
+)
+
+// operator transforms a log record, returning an error if it fails to parse
+// it.
+type operator interface {
+	process(lr pdata.LogRecord) error
+}
+
+// configuredOperator is an operator with the settings common to all the
+// types.
+type configuredOperator struct {
+	operator
+	typ  string
+	drop bool
+}
+
+func buildOperators(configs []OperatorConfig) ([]configuredOperator, error) {
+	operators := make([]configuredOperator, 0, len(configs))
+	for i, cfg := range configs {
+		op, err := buildOperator(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("operator %d: %w", i, err)
+		}
+
+		var drop bool
+		switch cfg.OnError {
+		case "", onErrorSend:
+		case onErrorDrop:
+			drop = true
+		default:
+			return nil, fmt.Errorf("operator %d: unsupported on_error %q", i, cfg.OnError)
+		}
+
+		operators = append(operators, configuredOperator{operator: op, typ: cfg.Type, drop: drop})
+	}
+	return operators, nil
+}
+
+func buildOperator(cfg OperatorConfig) (operator, error) {
+	switch cfg.Type {
+	case regexParserType:
+		from, err := parseField(cfg.ParseFrom, bodyField)
+		if err != nil {
+			return nil, err
+		}
+		return newRegexParser(from, cfg.Regex)
+	case jsonParserType:
+		from, err := parseField(cfg.ParseFrom, bodyField)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonParser{from: from}, nil
+	case severityParserType:
+		if cfg.ParseFrom == "" {
+			return nil, errors.New("parse_from must be set")
+		}
+		from, err := parseField(cfg.ParseFrom, "")
+		if err != nil {
+			return nil, err
+		}
+		return newSeverityParser(from, cfg.Mapping)
+	case "":
+		return nil, errors.New("type must be set")
+	default:
+		return nil, fmt.Errorf("unsupported type %q", cfg.Type)
+	}
+}
+
+// field references the body of the log records, an entry of their map
+// body or one of their attributes.
+type field struct {
+	attribute bool
+	key       string
+}
+
+func parseField(s, defaultField string) (field, error) {
+	if s == "" {
+		s = defaultField
+	}
+	switch {
+	case s == bodyField:
+		return field{}, nil
+	case strings.HasPrefix(s, bodyPrefix) && len(s) > len(bodyPrefix):
+		return field{key: s[len(bodyPrefix):]}, nil
+	case strings.HasPrefix(s, attributePrefix) && len(s) > len(attributePrefix):
+		return field{attribute: true, key: s[len(attributePrefix):]}, nil
+	}
+	return field{}, fmt.Errorf("invalid field %q, must be body, body.<key> or attributes.<key>", s)
+}
+
+func (f field) String() string {
+	switch {
+	case f.attribute:
+		return attributePrefix + f.key
+	case f.key != "":
+		return bodyPrefix + f.key
+	}
+	return bodyField
+}
+
+// get returns the value of the field in the log record.
+func (f field) get(lr pdata.LogRecord) (pdata.AttributeValue, bool) {
+	if f.attribute {
+		return lr.Attributes().Get(f.key)
+	}
+	body := lr.Body()
+	if body.IsNil() || body.Type() == pdata.AttributeValueNULL {
+		return pdata.AttributeValue{}, false
+	}
+	if f.key == "" {
+		return body, true
+	}
+	if body.Type() != pdata.AttributeValueMAP {
+		return pdata.AttributeValue{}, false
+	}
+	return body.MapVal().Get(f.key)
+}
+
+// getString returns the value of the field in the log record, converted to
+// a string.
+func (f field) getString(lr pdata.LogRecord) (string, error) {
+	v, ok := f.get(lr)
+	if !ok {
+		return "", fmt.Errorf("field %s not found", f)
+	}
+	return tracetranslator.AttributeValueToString(v, false), nil
+}
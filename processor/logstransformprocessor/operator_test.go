@@ -0,0 +1,135 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestBuildOperatorsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  OperatorConfig
+		wantErr string
+	}{
+		{
+			name:    "no type",
+			config:  OperatorConfig{},
+			wantErr: "operator 0: type must be set",
+		},
+		{
+			name:    "unsupported type",
+			config:  OperatorConfig{Type: "csv_parser"},
+			wantErr: `operator 0: unsupported type "csv_parser"`,
+		},
+		{
+			name:    "unsupported on_error",
+			config:  OperatorConfig{Type: jsonParserType, OnError: "retry"},
+			wantErr: `operator 0: unsupported on_error "retry"`,
+		},
+		{
+			name:    "invalid field",
+			config:  OperatorConfig{Type: jsonParserType, ParseFrom: "resource.host"},
+			wantErr: `operator 0: invalid field "resource.host", must be body, body.<key> or attributes.<key>`,
+		},
+		{
+			name:    "empty attribute key",
+			config:  OperatorConfig{Type: jsonParserType, ParseFrom: "attributes."},
+			wantErr: `operator 0: invalid field "attributes.", must be body, body.<key> or attributes.<key>`,
+		},
+		{
+			name:    "no regex",
+			config:  OperatorConfig{Type: regexParserType},
+			wantErr: "operator 0: regex must be set",
+		},
+		{
+			name:    "invalid regex",
+			config:  OperatorConfig{Type: regexParserType, Regex: "(?P<level>"},
+			wantErr: "operator 0: invalid regex: error parsing regexp: missing closing ): `(?P<level>`",
+		},
+		{
+			name:    "no named group",
+			config:  OperatorConfig{Type: regexParserType, Regex: `^(\w+) .*$`},
+			wantErr: "operator 0: regex must have at least one named capturing group",
+		},
+		{
+			name:    "no severity field",
+			config:  OperatorConfig{Type: severityParserType},
+			wantErr: "operator 0: parse_from must be set",
+		},
+		{
+			name: "unsupported severity",
+			config: OperatorConfig{
+				Type:      severityParserType,
+				ParseFrom: "attributes.level",
+				Mapping:   map[string][]string{"notice": {"n"}},
+			},
+			wantErr: `operator 0: unsupported severity "notice" in mapping`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operators, err := buildOperators([]OperatorConfig{tt.config})
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, operators)
+		})
+	}
+}
+
+func TestField(t *testing.T) {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.Attributes().InsertInt("status", 500)
+
+	body := pdata.NewAttributeMap()
+	body.InsertString("log", "GET /")
+	lr.Body().SetMapVal(body)
+
+	tests := []struct {
+		field     string
+		wantValue string
+		wantErr   string
+	}{
+		{field: "attributes.status", wantValue: "500"},
+		{field: "body.log", wantValue: "GET /"},
+		{field: "body", wantValue: `{"log":"GET /"}`},
+		{field: "attributes.missing", wantErr: "field attributes.missing not found"},
+		{field: "body.missing", wantErr: "field body.missing not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			f, err := parseField(tt.field, "")
+			require.NoError(t, err)
+			assert.Equal(t, tt.field, f.String())
+
+			value, err := f.getString(lr)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+
+	// A string body has no entries.
+	lr.Body().SetStringVal("GET /")
+	_, err := field{key: "log"}.getString(lr)
+	assert.EqualError(t, err, "field body.log not found")
+}
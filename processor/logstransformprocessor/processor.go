@@ -0,0 +1,91 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// logsTransformProcessor applies the operators to each log record, in order.
+type logsTransformProcessor struct {
+	logger    *zap.Logger
+	operators []configuredOperator
+}
+
+func newLogsTransformProcessor(logger *zap.Logger, cfg *Config) (*logsTransformProcessor, error) {
+	operators, err := buildOperators(cfg.Operators)
+	if err != nil {
+		return nil, err
+	}
+	return &logsTransformProcessor{
+		logger:    logger,
+		operators: operators,
+	}, nil
+}
+
+func (p *logsTransformProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			ill := ills.At(j)
+			if ill.IsNil() {
+				continue
+			}
+			p.processLogSlice(ill.Logs())
+		}
+	}
+	return ld, nil
+}
+
+// processLogSlice transforms the log records of the slice, removing the
+// dropped ones.
+func (p *logsTransformProcessor) processLogSlice(logs pdata.LogSlice) {
+	kept := pdata.NewLogSlice()
+	var dropped bool
+	for k := 0; k < logs.Len(); k++ {
+		lr := logs.At(k)
+		if lr.IsNil() || p.transform(lr) {
+			kept.Append(lr)
+		} else {
+			dropped = true
+		}
+	}
+	if dropped {
+		logs.Resize(0)
+		kept.MoveAndAppendTo(logs)
+	}
+}
+
+// transform applies the operators to the log record, returning false if it
+// must be dropped.
+func (p *logsTransformProcessor) transform(lr pdata.LogRecord) bool {
+	for _, op := range p.operators {
+		if err := op.process(lr); err != nil {
+			p.logger.Debug("Failed to apply operator", zap.String("type", op.typ), zap.Error(err))
+			if op.drop {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,107 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func testLogs(bodies ...string) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(bodies))
+	for i, body := range bodies {
+		logs.At(i).Body().SetStringVal(body)
+	}
+	return ld
+}
+
+func TestProcessLogs(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Operators = []OperatorConfig{
+		{
+			Type:    regexParserType,
+			Regex:   `^(?P<level>\w+) (?P<message>.*)$`,
+			OnError: onErrorDrop,
+		},
+		{
+			Type:      jsonParserType,
+			ParseFrom: "attributes.message",
+		},
+		{
+			Type:      severityParserType,
+			ParseFrom: "attributes.level",
+		},
+	}
+	sink := &exportertest.SinkLogsExporter{}
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+
+	ld := testLogs(
+		`ERROR {"msg":"connection refused","db":"orders"}`,
+		"---",
+		"WARN disk almost full",
+		"notice restarted",
+	)
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+	require.Len(t, sink.AllLogs(), 1)
+	logs := sink.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 3, logs.Len())
+
+	// All the operators apply.
+	assert.Equal(t, pdata.SeverityNumberERROR, logs.At(0).SeverityNumber())
+	assert.Equal(t, map[string]interface{}{
+		"level":   "ERROR",
+		"message": `{"msg":"connection refused","db":"orders"}`,
+		"msg":     "connection refused",
+		"db":      "orders",
+	}, attributesAsMap(logs.At(0).Attributes()))
+
+	// The JSON parser fails, the record is sent.
+	assert.Equal(t, "WARN disk almost full", logs.At(1).Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberWARN, logs.At(1).SeverityNumber())
+	assert.Equal(t, map[string]interface{}{
+		"level":   "WARN",
+		"message": "disk almost full",
+	}, attributesAsMap(logs.At(1).Attributes()))
+
+	// The severity parser fails after the regex parser applied.
+	assert.Equal(t, "notice restarted", logs.At(2).Body().StringVal())
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, logs.At(2).SeverityNumber())
+	assert.Equal(t, 2, logs.At(2).Attributes().Len())
+}
+
+func TestProcessLogsDropAll(t *testing.T) {
+	p, err := newLogsTransformProcessor(zap.NewNop(), &Config{
+		Operators: []OperatorConfig{{Type: jsonParserType, OnError: onErrorDrop}},
+	})
+	require.NoError(t, err)
+
+	ld, err := p.ProcessLogs(context.Background(), testLogs("a", "b"))
+	require.NoError(t, err)
+	assert.Equal(t, 0, ld.LogRecordCount())
+}
@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// regexParser adds the named capturing groups of a regular expression
+// matching a field of the log records as string attributes.
+type regexParser struct {
+	from  field
+	regex *regexp.Regexp
+}
+
+func newRegexParser(from field, expr string) (*regexParser, error) {
+	if expr == "" {
+		return nil, errors.New("regex must be set")
+	}
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex: %w", err)
+	}
+	var named bool
+	for _, name := range regex.SubexpNames() {
+		named = named || name != ""
+	}
+	if !named {
+		return nil, errors.New("regex must have at least one named capturing group")
+	}
+	return &regexParser{from: from, regex: regex}, nil
+}
+
+func (p *regexParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.getString(lr)
+	if err != nil {
+		return err
+	}
+	match := p.regex.FindStringSubmatch(value)
+	if match == nil {
+		return fmt.Errorf("regex does not match %s", p.from)
+	}
+
+	attrs := lr.Attributes()
+	for i, name := range p.regex.SubexpNames() {
+		if name != "" {
+			attrs.UpsertString(name, match[i])
+		}
+	}
+	return nil
+}
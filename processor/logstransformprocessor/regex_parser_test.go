@@ -0,0 +1,69 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func testLogRecord(body string) pdata.LogRecord {
+	lr := pdata.NewLogRecord()
+	lr.InitEmpty()
+	lr.Body().SetStringVal(body)
+	return lr
+}
+
+func attributesAsMap(attrs pdata.AttributeMap) map[string]interface{} {
+	result := map[string]interface{}{}
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		switch v.Type() {
+		case pdata.AttributeValueSTRING:
+			result[k] = v.StringVal()
+		case pdata.AttributeValueINT:
+			result[k] = v.IntVal()
+		case pdata.AttributeValueDOUBLE:
+			result[k] = v.DoubleVal()
+		case pdata.AttributeValueBOOL:
+			result[k] = v.BoolVal()
+		case pdata.AttributeValueMAP:
+			result[k] = attributesAsMap(v.MapVal())
+		default:
+			result[k] = v
+		}
+	})
+	return result
+}
+
+func TestRegexParser(t *testing.T) {
+	p, err := newRegexParser(field{}, `^(?P<level>\w+) (\S+) (?P<message>.*)$`)
+	require.NoError(t, err)
+
+	lr := testLogRecord("ERROR db connection refused")
+	lr.Attributes().InsertString("level", "overridden")
+	require.NoError(t, p.process(lr))
+	assert.Equal(t, map[string]interface{}{
+		"level":   "ERROR",
+		"message": "connection refused",
+	}, attributesAsMap(lr.Attributes()))
+	assert.Equal(t, "ERROR db connection refused", lr.Body().StringVal())
+
+	lr = testLogRecord("connection refused")
+	assert.EqualError(t, p.process(lr), "regex does not match body")
+	assert.Equal(t, 0, lr.Attributes().Len())
+}
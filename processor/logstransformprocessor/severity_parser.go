@@ -0,0 +1,99 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// severities are the severities which can be mapped by name.
+var severities = map[string]pdata.SeverityNumber{
+	"trace":  pdata.SeverityNumberTRACE,
+	"trace2": pdata.SeverityNumberTRACE2,
+	"trace3": pdata.SeverityNumberTRACE3,
+	"trace4": pdata.SeverityNumberTRACE4,
+	"debug":  pdata.SeverityNumberDEBUG,
+	"debug2": pdata.SeverityNumberDEBUG2,
+	"debug3": pdata.SeverityNumberDEBUG3,
+	"debug4": pdata.SeverityNumberDEBUG4,
+	"info":   pdata.SeverityNumberINFO,
+	"info2":  pdata.SeverityNumberINFO2,
+	"info3":  pdata.SeverityNumberINFO3,
+	"info4":  pdata.SeverityNumberINFO4,
+	"warn":   pdata.SeverityNumberWARN,
+	"warn2":  pdata.SeverityNumberWARN2,
+	"warn3":  pdata.SeverityNumberWARN3,
+	"warn4":  pdata.SeverityNumberWARN4,
+	"error":  pdata.SeverityNumberERROR,
+	"error2": pdata.SeverityNumberERROR2,
+	"error3": pdata.SeverityNumberERROR3,
+	"error4": pdata.SeverityNumberERROR4,
+	"fatal":  pdata.SeverityNumberFATAL,
+	"fatal2": pdata.SeverityNumberFATAL2,
+	"fatal3": pdata.SeverityNumberFATAL3,
+	"fatal4": pdata.SeverityNumberFATAL4,
+}
+
+// defaultSeverityValues are the values recognized without a mapping, in
+// addition to the names of the severities.
+var defaultSeverityValues = map[string]pdata.SeverityNumber{
+	"warning":  pdata.SeverityNumberWARN,
+	"err":      pdata.SeverityNumberERROR,
+	"critical": pdata.SeverityNumberFATAL,
+}
+
+// severityParser sets the severity of the log records from one of their
+// fields, the values being matched case-insensitively.
+type severityParser struct {
+	from   field
+	values map[string]pdata.SeverityNumber
+}
+
+func newSeverityParser(from field, mapping map[string][]string) (*severityParser, error) {
+	values := make(map[string]pdata.SeverityNumber, len(severities)+len(defaultSeverityValues))
+	for name, severity := range severities {
+		values[name] = severity
+	}
+	for value, severity := range defaultSeverityValues {
+		values[value] = severity
+	}
+	for name, mapped := range mapping {
+		severity, ok := severities[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported severity %q in mapping", name)
+		}
+		for _, value := range mapped {
+			values[strings.ToLower(value)] = severity
+		}
+	}
+	return &severityParser{from: from, values: values}, nil
+}
+
+func (p *severityParser) process(lr pdata.LogRecord) error {
+	value, err := p.from.getString(lr)
+	if err != nil {
+		return err
+	}
+	severity, ok := p.values[strings.ToLower(value)]
+	if !ok {
+		return fmt.Errorf("unrecognized severity %q", value)
+	}
+	lr.SetSeverityNumber(severity)
+	lr.SetSeverityText(value)
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logstransformprocessor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestSeverityParser(t *testing.T) {
+	p, err := newSeverityParser(field{attribute: true, key: "level"}, map[string][]string{
+		"warn":  {"W", "wrn"},
+		"Error": {"e", "500"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		level interface{}
+		want  pdata.SeverityNumber
+	}{
+		{level: "INFO", want: pdata.SeverityNumberINFO},
+		{level: "debug3", want: pdata.SeverityNumberDEBUG3},
+		{level: "Warning", want: pdata.SeverityNumberWARN},
+		{level: "w", want: pdata.SeverityNumberWARN},
+		{level: "err", want: pdata.SeverityNumberERROR},
+		{level: "E", want: pdata.SeverityNumberERROR},
+		{level: int64(500), want: pdata.SeverityNumberERROR},
+		{level: "critical", want: pdata.SeverityNumberFATAL},
+	}
+	for _, tt := range tests {
+		lr := testLogRecord("")
+		var text string
+		switch level := tt.level.(type) {
+		case string:
+			lr.Attributes().InsertString("level", level)
+			text = level
+		case int64:
+			lr.Attributes().InsertInt("level", level)
+			text = "500"
+		}
+		require.NoError(t, p.process(lr), tt.level)
+		assert.Equal(t, tt.want, lr.SeverityNumber(), tt.level)
+		assert.Equal(t, text, lr.SeverityText(), tt.level)
+	}
+
+	lr := testLogRecord("")
+	lr.Attributes().InsertString("level", "notice")
+	assert.EqualError(t, p.process(lr), `unrecognized severity "notice"`)
+	assert.Equal(t, pdata.SeverityNumberUNDEFINED, lr.SeverityNumber())
+}
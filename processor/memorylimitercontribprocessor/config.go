@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimitercontribprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the contrib memory limiter processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// CheckInterval is the time between measurements of memory usage for the
+	// purposes of avoiding going over the limits. Defaults to zero, so no
+	// checks will be performed.
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+
+	// MemoryLimitMiB is the hard limit, in MiB, of the memory allocated by the
+	// process above which all the data of the pipeline is refused.
+	MemoryLimitMiB uint32 `mapstructure:"limit_mib"`
+
+	// MemorySpikeLimitMiB is the maximum, in MiB, spike expected between the
+	// measurements of memory usage. The soft limit, above which only the data
+	// of the noisy tenants is refused, is MemoryLimitMiB - MemorySpikeLimitMiB.
+	MemorySpikeLimitMiB uint32 `mapstructure:"spike_limit_mib"`
+
+	// BallastSizeMiB is the size, in MiB, of the ballast size being used by the
+	// process.
+	BallastSizeMiB uint32 `mapstructure:"ballast_size_mib"`
+
+	// TenantAttribute is the resource attribute identifying the tenant of the
+	// data. If empty, the pipeline is a single tenant and all its data is
+	// refused above the soft limit.
+	TenantAttribute string `mapstructure:"tenant_attribute"`
+
+	// MaxTenantShare is the share of the items received by the pipeline during
+	// the last check interval above which a tenant is refused between the soft
+	// and hard limits. Defaults to zero, meaning a fair share of 1/N of the
+	// items, N being the number of tenants that sent data during the interval.
+	MaxTenantShare float64 `mapstructure:"max_tenant_share"`
+}
+
+const ballastSizeMibKey = "ballast_size_mib"
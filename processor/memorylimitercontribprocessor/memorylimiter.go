@@ -0,0 +1,395 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimitercontribprocessor
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+var (
+	// errForcedDrop will be returned to callers of ConsumeTraceData to indicate
+	// that data is being refused due to high memory usage.
+	errForcedDrop = errors.New("data refused due to high memory usage")
+
+	// Construction errors
+
+	errCheckIntervalOutOfRange = errors.New(
+		"checkInterval must be greater than zero")
+
+	errMemAllocLimitOutOfRange = errors.New(
+		"memAllocLimit must be greater than zero")
+
+	errMemSpikeLimitOutOfRange = errors.New(
+		"memSpikeLimit must be smaller than memAllocLimit")
+
+	errMaxTenantShareOutOfRange = errors.New(
+		"maxTenantShare must be between 0 and 1")
+)
+
+// The memory usage levels, stored in memoryLimiter.level.
+const (
+	levelNormal int64 = iota
+	// levelSoft is above the soft limit, the data of the noisy tenants is refused.
+	levelSoft
+	// levelHard is above the hard limit, all the data is refused.
+	levelHard
+)
+
+type memoryLimiter struct {
+	memAllocLimit uint64
+	memSpikeLimit uint64
+	ballastSize   uint64
+
+	tenantAttribute string
+	maxTenantShare  float64
+
+	// level is used atomically to indicate which limit the memory usage is above.
+	level int64
+
+	mu sync.Mutex
+	// received counts the items received from each tenant since the last check.
+	received map[string]int
+	// noisy is the set of tenants refused above the soft limit, computed at each
+	// check from the items received during the last interval.
+	noisy map[string]bool
+
+	ticker *time.Ticker
+
+	// The function to read the mem values is set as a reference to help with
+	// testing different values.
+	readMemStatsFn func(m *runtime.MemStats)
+
+	// Fields used for logging.
+	procName               string
+	logger                 *zap.Logger
+	configMismatchedLogged bool
+}
+
+// newMemoryLimiter returns a new memory limiter processor.
+func newMemoryLimiter(logger *zap.Logger, cfg *Config) (*memoryLimiter, error) {
+	const mibBytes = 1024 * 1024
+	memAllocLimit := uint64(cfg.MemoryLimitMiB) * mibBytes
+	memSpikeLimit := uint64(cfg.MemorySpikeLimitMiB) * mibBytes
+	ballastSize := uint64(cfg.BallastSizeMiB) * mibBytes
+
+	if cfg.CheckInterval <= 0 {
+		return nil, errCheckIntervalOutOfRange
+	}
+	if memAllocLimit == 0 {
+		return nil, errMemAllocLimitOutOfRange
+	}
+	if memSpikeLimit >= memAllocLimit {
+		return nil, errMemSpikeLimitOutOfRange
+	}
+	if cfg.MaxTenantShare < 0 || cfg.MaxTenantShare > 1 {
+		return nil, errMaxTenantShareOutOfRange
+	}
+
+	ml := &memoryLimiter{
+		memAllocLimit:   memAllocLimit,
+		memSpikeLimit:   memSpikeLimit,
+		ballastSize:     ballastSize,
+		tenantAttribute: cfg.TenantAttribute,
+		maxTenantShare:  cfg.MaxTenantShare,
+		received:        map[string]int{},
+		noisy:           map[string]bool{},
+		ticker:          time.NewTicker(cfg.CheckInterval),
+		readMemStatsFn:  runtime.ReadMemStats,
+		procName:        cfg.Name(),
+		logger:          logger,
+	}
+
+	ml.startMonitoring()
+
+	return ml, nil
+}
+
+func (ml *memoryLimiter) shutdown(context.Context) error {
+	ml.ticker.Stop()
+	return nil
+}
+
+// ProcessTraces implements the TProcessor interface
+func (ml *memoryLimiter) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	numSpans := td.SpanCount()
+	if ml.refuse(ml.tracesByTenant(td)) {
+		obsreport.ProcessorTraceDataRefused(ctx, numSpans)
+		return td, errForcedDrop
+	}
+
+	// Even if the next consumer returns error record the data as accepted by
+	// this processor.
+	obsreport.ProcessorTraceDataAccepted(ctx, numSpans)
+	return td, nil
+}
+
+// ProcessMetrics implements the MProcessor interface
+func (ml *memoryLimiter) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	_, numDataPoints := md.MetricAndDataPointCount()
+	if ml.refuse(ml.metricsByTenant(md)) {
+		obsreport.ProcessorMetricsDataRefused(ctx, numDataPoints)
+		return md, errForcedDrop
+	}
+
+	// Even if the next consumer returns error record the data as accepted by
+	// this processor.
+	obsreport.ProcessorMetricsDataAccepted(ctx, numDataPoints)
+	return md, nil
+}
+
+// ProcessLogs implements the LProcessor interface
+func (ml *memoryLimiter) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	numRecords := ld.LogRecordCount()
+	if ml.refuse(ml.logsByTenant(ld)) {
+		obsreport.ProcessorLogRecordsRefused(ctx, numRecords)
+		return ld, errForcedDrop
+	}
+
+	// Even if the next consumer returns error record the data as accepted by
+	// this processor.
+	obsreport.ProcessorLogRecordsAccepted(ctx, numRecords)
+	return ld, nil
+}
+
+// refuse counts the items received from each tenant and indicates whether the data
+// should be refused. Above the soft limit a batch is refused if it holds data of a
+// noisy tenant.
+func (ml *memoryLimiter) refuse(items map[string]int) bool {
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+	for tenant, n := range items {
+		ml.received[tenant] += n
+	}
+
+	switch atomic.LoadInt64(&ml.level) {
+	case levelHard:
+		return true
+	case levelSoft:
+		if ml.tenantAttribute == "" {
+			return true
+		}
+		for tenant := range items {
+			if ml.noisy[tenant] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tenant returns the tenant of the data of a resource, empty if the resource doesn't
+// have the tenant attribute.
+func (ml *memoryLimiter) tenant(resource pdata.Resource) string {
+	if ml.tenantAttribute == "" || resource.IsNil() {
+		return ""
+	}
+	if v, ok := resource.Attributes().Get(ml.tenantAttribute); ok && v.Type() == pdata.AttributeValueSTRING {
+		return v.StringVal()
+	}
+	return ""
+}
+
+func (ml *memoryLimiter) tracesByTenant(td pdata.Traces) map[string]int {
+	items := map[string]int{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		n := 0
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			if ils := ilss.At(j); !ils.IsNil() {
+				n += ils.Spans().Len()
+			}
+		}
+		items[ml.tenant(rs.Resource())] += n
+	}
+	return items
+}
+
+func (ml *memoryLimiter) metricsByTenant(md pdata.Metrics) map[string]int {
+	items := map[string]int{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		n := 0
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			ms := ilm.Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				if m := ms.At(k); !m.IsNil() {
+					n += dataPointCount(m)
+				}
+			}
+		}
+		items[ml.tenant(rm.Resource())] += n
+	}
+	return items
+}
+
+func (ml *memoryLimiter) logsByTenant(ld pdata.Logs) map[string]int {
+	items := map[string]int{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		n := 0
+		ills := rl.InstrumentationLibraryLogs()
+		for j := 0; j < ills.Len(); j++ {
+			if ill := ills.At(j); !ill.IsNil() {
+				n += ill.Logs().Len()
+			}
+		}
+		items[ml.tenant(rl.Resource())] += n
+	}
+	return items
+}
+
+func dataPointCount(m pdata.Metric) int {
+	switch m.DataType() {
+	case pdata.MetricDataTypeIntGauge:
+		return m.IntGauge().DataPoints().Len()
+	case pdata.MetricDataTypeDoubleGauge:
+		return m.DoubleGauge().DataPoints().Len()
+	case pdata.MetricDataTypeIntSum:
+		return m.IntSum().DataPoints().Len()
+	case pdata.MetricDataTypeDoubleSum:
+		return m.DoubleSum().DataPoints().Len()
+	case pdata.MetricDataTypeIntHistogram:
+		return m.IntHistogram().DataPoints().Len()
+	case pdata.MetricDataTypeDoubleHistogram:
+		return m.DoubleHistogram().DataPoints().Len()
+	}
+	return 0
+}
+
+func (ml *memoryLimiter) readMemStats(ms *runtime.MemStats) {
+	ml.readMemStatsFn(ms)
+	// If proper configured ms.Alloc should be at least ml.ballastSize but since
+	// a misconfiguration is possible check for that here.
+	if ms.Alloc >= ml.ballastSize {
+		ms.Alloc -= ml.ballastSize
+	} else if !ml.configMismatchedLogged {
+		// This indicates misconfiguration. Log it once.
+		ml.configMismatchedLogged = true
+		ml.logger.Warn(typeStr + " is likely incorrectly configured. " + ballastSizeMibKey +
+			" must be set equal to --mem-ballast-size-mib command line option.")
+	}
+}
+
+// startMonitoring starts a ticker'd goroutine that will check memory usage
+// every checkInterval period.
+func (ml *memoryLimiter) startMonitoring() {
+	go func() {
+		for range ml.ticker.C {
+			ml.memCheck()
+		}
+	}()
+}
+
+func (ml *memoryLimiter) memCheck() {
+	ms := &runtime.MemStats{}
+	ml.readMemStats(ms)
+	ml.memLimiting(ms)
+}
+
+// memoryLevel returns the limit the memory usage is above of.
+func (ml *memoryLimiter) memoryLevel(ms *runtime.MemStats) int64 {
+	if ml.memAllocLimit <= ms.Alloc {
+		return levelHard
+	}
+	if ml.memAllocLimit-ms.Alloc <= ml.memSpikeLimit {
+		return levelSoft
+	}
+	return levelNormal
+}
+
+func (ml *memoryLimiter) memLimiting(ms *runtime.MemStats) {
+	level := ml.memoryLevel(ms)
+
+	ml.mu.Lock()
+	ml.noisy = ml.noisyTenants()
+	ml.received = map[string]int{}
+	ml.mu.Unlock()
+
+	previous := atomic.SwapInt64(&ml.level, level)
+	if level != previous {
+		ml.logger.Info("Memory usage level changed",
+			zap.String("processor", ml.procName),
+			zap.String("level", levelName(level)),
+			zap.Uint64("alloc_mib", ms.Alloc/(1024*1024)))
+	}
+
+	if level != levelNormal {
+		// Force a GC at this point and see if this is enough to get to
+		// the desired level.
+		runtime.GC()
+	}
+}
+
+// noisyTenants returns the tenants whose share of the items received since the last
+// check is greater than the maximum share. It must be called holding ml.mu.
+func (ml *memoryLimiter) noisyTenants() map[string]bool {
+	total := 0
+	for _, n := range ml.received {
+		total += n
+	}
+	if total == 0 {
+		return map[string]bool{}
+	}
+
+	maxShare := ml.maxTenantShare
+	if maxShare == 0 {
+		maxShare = 1 / float64(len(ml.received))
+	}
+
+	noisy := map[string]bool{}
+	for tenant, n := range ml.received {
+		if float64(n)/float64(total) > maxShare {
+			noisy[tenant] = true
+		}
+	}
+	return noisy
+}
+
+func levelName(level int64) string {
+	switch level {
+	case levelSoft:
+		return "soft"
+	case levelHard:
+		return "hard"
+	}
+	return "normal"
+}
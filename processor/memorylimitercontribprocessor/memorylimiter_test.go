@@ -0,0 +1,308 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memorylimitercontribprocessor
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
+)
+
+func TestNew(t *testing.T) {
+	type args struct {
+		nextConsumer        consumer.TraceConsumer
+		checkInterval       time.Duration
+		memoryLimitMiB      uint32
+		memorySpikeLimitMiB uint32
+		maxTenantShare      float64
+	}
+	sink := new(exportertest.SinkTraceExporter)
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "zero_checkInterval",
+			args: args{
+				nextConsumer: sink,
+			},
+			wantErr: errCheckIntervalOutOfRange,
+		},
+		{
+			name: "zero_memAllocLimit",
+			args: args{
+				nextConsumer:  sink,
+				checkInterval: 100 * time.Millisecond,
+			},
+			wantErr: errMemAllocLimitOutOfRange,
+		},
+		{
+			name: "memSpikeLimit_gt_memAllocLimit",
+			args: args{
+				nextConsumer:        sink,
+				checkInterval:       100 * time.Millisecond,
+				memoryLimitMiB:      1,
+				memorySpikeLimitMiB: 2,
+			},
+			wantErr: errMemSpikeLimitOutOfRange,
+		},
+		{
+			name: "maxTenantShare_gt_1",
+			args: args{
+				nextConsumer:   sink,
+				checkInterval:  100 * time.Millisecond,
+				memoryLimitMiB: 1024,
+				maxTenantShare: 1.5,
+			},
+			wantErr: errMaxTenantShareOutOfRange,
+		},
+		{
+			name: "success",
+			args: args{
+				nextConsumer:   sink,
+				checkInterval:  100 * time.Millisecond,
+				memoryLimitMiB: 1024,
+				maxTenantShare: 0.5,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.CheckInterval = tt.args.checkInterval
+			cfg.MemoryLimitMiB = tt.args.memoryLimitMiB
+			cfg.MemorySpikeLimitMiB = tt.args.memorySpikeLimitMiB
+			cfg.MaxTenantShare = tt.args.maxTenantShare
+			got, err := newMemoryLimiter(zap.NewNop(), cfg)
+			if err != tt.wantErr {
+				t.Errorf("newMemoryLimiter() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil {
+				assert.NoError(t, got.shutdown(context.Background()))
+			}
+		})
+	}
+}
+
+func newTestMemoryLimiter(tenantAttribute string, memAlloc *uint64) *memoryLimiter {
+	return &memoryLimiter{
+		memAllocLimit:   1024,
+		memSpikeLimit:   512,
+		tenantAttribute: tenantAttribute,
+		received:        map[string]int{},
+		noisy:           map[string]bool{},
+		readMemStatsFn: func(ms *runtime.MemStats) {
+			ms.Alloc = *memAlloc
+		},
+		logger: zap.NewNop(),
+	}
+}
+
+// generateTraces returns traces holding the given number of spans for each tenant,
+// the spans of the tenant "" being sent without the tenant attribute.
+func generateTraces(spans map[string]int) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(len(spans))
+	i := 0
+	for tenant, n := range spans {
+		rs := td.ResourceSpans().At(i)
+		i++
+		if tenant != "" {
+			rs.Resource().InitEmpty()
+			rs.Resource().Attributes().InsertString("tenant.id", tenant)
+		}
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).Spans().Resize(n)
+	}
+	return td
+}
+
+// TestTraceMemoryPressureResponse manipulates results from querying memory and
+// check expected side effects.
+func TestTraceMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	ml := newTestMemoryLimiter("", &currentMemAlloc)
+	tp, err := processorhelper.NewTraceProcessor(
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: typeStr,
+				NameVal: typeStr,
+			},
+		},
+		exportertest.NewNopTraceExporter(),
+		ml,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithShutdown(ml.shutdown))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	td := generateTraces(map[string]int{"": 10})
+
+	// Below the soft limit.
+	currentMemAlloc = 500
+	ml.memCheck()
+	assert.NoError(t, tp.ConsumeTraces(ctx, td))
+
+	// Above the soft limit, without tenants all the data is refused.
+	currentMemAlloc = 550
+	ml.memCheck()
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, td))
+
+	// Above the hard limit.
+	currentMemAlloc = 1800
+	ml.memCheck()
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, td))
+
+	// Check ballast effect
+	ml.ballastSize = 1000
+
+	// Below the soft limit accounting for ballast.
+	currentMemAlloc = 500 + ml.ballastSize
+	ml.memCheck()
+	assert.NoError(t, tp.ConsumeTraces(ctx, td))
+}
+
+// TestTenantMemoryPressureResponse checks that only the noisy tenants are refused
+// between the soft and hard limits.
+func TestTenantMemoryPressureResponse(t *testing.T) {
+	var currentMemAlloc uint64
+	ml := newTestMemoryLimiter("tenant.id", &currentMemAlloc)
+	tp, err := processorhelper.NewTraceProcessor(
+		&Config{
+			ProcessorSettings: configmodels.ProcessorSettings{
+				TypeVal: typeStr,
+				NameVal: typeStr,
+			},
+		},
+		exportertest.NewNopTraceExporter(),
+		ml,
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithShutdown(ml.shutdown))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	noisy := generateTraces(map[string]int{"noisy": 80})
+	quiet := generateTraces(map[string]int{"quiet": 10})
+	untagged := generateTraces(map[string]int{"": 10})
+	mixed := generateTraces(map[string]int{"noisy": 1, "quiet": 1})
+
+	// Below the soft limit all the data is accepted and counted.
+	currentMemAlloc = 500
+	ml.memCheck()
+	assert.NoError(t, tp.ConsumeTraces(ctx, noisy))
+	assert.NoError(t, tp.ConsumeTraces(ctx, quiet))
+	assert.NoError(t, tp.ConsumeTraces(ctx, untagged))
+
+	// Above the soft limit the tenant above its fair share of 1/3 is refused.
+	currentMemAlloc = 550
+	ml.memCheck()
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, noisy))
+	assert.NoError(t, tp.ConsumeTraces(ctx, quiet))
+	assert.NoError(t, tp.ConsumeTraces(ctx, untagged))
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, mixed))
+
+	// Above the hard limit all the data is refused.
+	currentMemAlloc = 1800
+	ml.memCheck()
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, noisy))
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, quiet))
+	assert.Equal(t, errForcedDrop, tp.ConsumeTraces(ctx, untagged))
+
+	// Back below the soft limit.
+	currentMemAlloc = 500
+	ml.memCheck()
+	assert.NoError(t, tp.ConsumeTraces(ctx, noisy))
+}
+
+func TestNoisyTenants(t *testing.T) {
+	tests := []struct {
+		name           string
+		received       map[string]int
+		maxTenantShare float64
+		want           map[string]bool
+	}{
+		{
+			name: "nothing_received",
+			want: map[string]bool{},
+		},
+		{
+			name:     "fair_share",
+			received: map[string]int{"a": 60, "b": 30, "c": 10},
+			want:     map[string]bool{"a": true},
+		},
+		{
+			name:     "single_tenant",
+			received: map[string]int{"a": 60},
+			want:     map[string]bool{},
+		},
+		{
+			name:           "max_share",
+			received:       map[string]int{"a": 60, "b": 30, "c": 10},
+			maxTenantShare: 0.25,
+			want:           map[string]bool{"a": true, "b": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ml := &memoryLimiter{received: tt.received, maxTenantShare: tt.maxTenantShare}
+			assert.Equal(t, tt.want, ml.noisyTenants())
+		})
+	}
+}
+
+func TestMetricsByTenant(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(2)
+	for i, tenant := range []string{"a", "b"} {
+		rm := md.ResourceMetrics().At(i)
+		rm.Resource().InitEmpty()
+		rm.Resource().Attributes().InsertString("tenant.id", tenant)
+		rm.InstrumentationLibraryMetrics().Resize(1)
+		ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+		ms.Resize(1)
+		ms.At(0).SetDataType(pdata.MetricDataTypeDoubleSum)
+		ms.At(0).DoubleSum().InitEmpty()
+		ms.At(0).DoubleSum().DataPoints().Resize(i + 1)
+	}
+
+	ml := &memoryLimiter{tenantAttribute: "tenant.id"}
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, ml.metricsByTenant(md))
+}
+
+func TestLogsByTenant(t *testing.T) {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(2)
+	ld.ResourceLogs().At(0).Resource().InitEmpty()
+	ld.ResourceLogs().At(0).Resource().Attributes().InsertString("tenant.id", "a")
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(3)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().Resize(2)
+
+	ml := &memoryLimiter{tenantAttribute: "tenant.id"}
+	assert.Equal(t, map[string]int{"a": 3, "": 2}, ml.logsByTenant(ld))
+}
@@ -0,0 +1,74 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the rate limit processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+
+	// Key is the resource attribute whose values are rate limited
+	// independently (default service.name).
+	Key string `mapstructure:"key"`
+
+	// Rate is the number of spans, metric data points or log records per
+	// second allowed for each value of the key.
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the number of items allowed at once for each value of the key
+	// (default the rate, rounded up).
+	Burst int `mapstructure:"burst"`
+
+	// Policy is what happens to the data over budget, either drop (the
+	// default) or defer.
+	Policy Policy `mapstructure:"policy"`
+
+	// MaxDelay is the longest the data is held with the defer policy waiting
+	// for budget, the data which would be held longer being dropped (default
+	// 1s).
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+
+	// Overrides are the budgets of specific values of the key.
+	Overrides []OverrideConfig `mapstructure:"overrides"`
+}
+
+// OverrideConfig defines the budget of a value of the key.
+type OverrideConfig struct {
+	// Value is the value of the key.
+	Value string `mapstructure:"value"`
+
+	// Rate is the number of items per second allowed for the value.
+	Rate float64 `mapstructure:"rate"`
+
+	// Burst is the number of items allowed at once for the value (default
+	// the rate, rounded up).
+	Burst int `mapstructure:"burst"`
+}
+
+// Policy is what happens to the data over budget.
+type Policy string
+
+const (
+	// DropPolicy drops the data over budget.
+	DropPolicy Policy = "drop"
+	// DeferPolicy holds the data over budget until there is budget for it,
+	// for at most the max delay.
+	DeferPolicy Policy = "defer"
+)
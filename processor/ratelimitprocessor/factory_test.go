@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func testConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Rate = 100
+	return cfg
+}
+
+func TestCreateProcessors(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	cfg := testConfig()
+
+	tp, err := factory.CreateTraceProcessor(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	require.NoError(t, err)
+	assert.NotNil(t, tp)
+	assert.False(t, tp.GetCapabilities().MutatesConsumedData)
+
+	mp, err := factory.CreateMetricsProcessor(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	assert.NotNil(t, mp)
+
+	lp, err := factory.CreateLogsProcessor(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	require.NoError(t, err)
+	assert.NotNil(t, lp)
+}
+
+func TestCreateProcessorInvalidConfig(t *testing.T) {
+	factory := NewFactory()
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "no key",
+			modify: func(cfg *Config) { cfg.Key = "" },
+			err:    "`key` not specified",
+		},
+		{
+			name:   "no rate",
+			modify: func(cfg *Config) { cfg.Rate = 0 },
+			err:    "`rate` must be positive",
+		},
+		{
+			name:   "negative burst",
+			modify: func(cfg *Config) { cfg.Burst = -1 },
+			err:    "`burst` must not be negative",
+		},
+		{
+			name:   "unsupported policy",
+			modify: func(cfg *Config) { cfg.Policy = "queue" },
+			err:    `unsupported policy "queue"`,
+		},
+		{
+			name: "no max delay",
+			modify: func(cfg *Config) {
+				cfg.Policy = DeferPolicy
+				cfg.MaxDelay = 0
+			},
+			err: "`max_delay` must be positive",
+		},
+		{
+			name:   "no override value",
+			modify: func(cfg *Config) { cfg.Overrides = []OverrideConfig{{Rate: 10}} },
+			err:    "override 0: value must be set",
+		},
+		{
+			name: "duplicate override",
+			modify: func(cfg *Config) {
+				cfg.Overrides = []OverrideConfig{{Value: "a", Rate: 10}, {Value: "a", Rate: 20}}
+			},
+			err: `value "a" overridden more than once`,
+		},
+		{
+			name:   "no override rate",
+			modify: func(cfg *Config) { cfg.Overrides = []OverrideConfig{{Value: "a"}} },
+			err:    `override "a": rate must be positive`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.modify(cfg)
+			tp, err := factory.CreateTraceProcessor(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+			assert.EqualError(t, err, tt.err)
+			assert.Nil(t, tp)
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiter holds a token bucket for each value of the key, created when the
+// value is first seen.
+type limiter struct {
+	policy    Policy
+	maxDelay  time.Duration
+	rate      rate.Limit
+	burst     int
+	overrides map[string]OverrideConfig
+
+	mu      sync.Mutex
+	buckets map[string]*rate.Limiter
+}
+
+func validateConfig(cfg *Config) error {
+	if cfg.Key == "" {
+		return errors.New("`key` not specified")
+	}
+	if cfg.Rate <= 0 {
+		return errors.New("`rate` must be positive")
+	}
+	if cfg.Burst < 0 {
+		return errors.New("`burst` must not be negative")
+	}
+	if cfg.Policy != DropPolicy && cfg.Policy != DeferPolicy {
+		return fmt.Errorf("unsupported policy %q", cfg.Policy)
+	}
+	if cfg.Policy == DeferPolicy && cfg.MaxDelay <= 0 {
+		return errors.New("`max_delay` must be positive")
+	}
+	values := map[string]bool{}
+	for i, override := range cfg.Overrides {
+		if override.Value == "" {
+			return fmt.Errorf("override %d: value must be set", i)
+		}
+		if values[override.Value] {
+			return fmt.Errorf("value %q overridden more than once", override.Value)
+		}
+		values[override.Value] = true
+		if override.Rate <= 0 {
+			return fmt.Errorf("override %q: rate must be positive", override.Value)
+		}
+		if override.Burst < 0 {
+			return fmt.Errorf("override %q: burst must not be negative", override.Value)
+		}
+	}
+	return nil
+}
+
+func newLimiter(cfg *Config) (*limiter, error) {
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	l := &limiter{
+		policy:    cfg.Policy,
+		maxDelay:  cfg.MaxDelay,
+		rate:      rate.Limit(cfg.Rate),
+		burst:     burst(cfg.Rate, cfg.Burst),
+		overrides: map[string]OverrideConfig{},
+		buckets:   map[string]*rate.Limiter{},
+	}
+	for _, override := range cfg.Overrides {
+		l.overrides[override.Value] = override
+	}
+	return l, nil
+}
+
+// burst returns the configured burst, or the rate rounded up if not set.
+func burst(r float64, b int) int {
+	if b > 0 {
+		return b
+	}
+	return int(math.Ceil(r))
+}
+
+func (l *limiter) bucket(value string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[value]
+	if !ok {
+		if override, ok := l.overrides[value]; ok {
+			b = rate.NewLimiter(rate.Limit(override.Rate), burst(override.Rate, override.Burst))
+		} else {
+			b = rate.NewLimiter(l.rate, l.burst)
+		}
+		l.buckets[value] = b
+	}
+	return b
+}
+
+// reserve takes n items from the bucket of value, returning whether they are
+// within budget and, with the defer policy, the delay after which they are.
+// The items over budget are not taken from the bucket.
+func (l *limiter) reserve(value string, n int, now time.Time) (time.Duration, bool) {
+	b := l.bucket(value)
+	if l.policy == DropPolicy {
+		return 0, b.AllowN(now, n)
+	}
+
+	r := b.ReserveN(now, n)
+	if !r.OK() {
+		// More items than the burst, they never fit in the bucket.
+		return 0, false
+	}
+	delay := r.DelayFrom(now)
+	if delay > l.maxDelay {
+		r.CancelAt(now)
+		return 0, false
+	}
+	return delay, true
+}
@@ -0,0 +1,81 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewItemsAccepted,
+		viewItemsDropped,
+		viewItemsDeferred,
+	)
+}
+
+var (
+	tagProcessorKey, _ = tag.NewKey("processor")
+	tagLimitKey, _     = tag.NewKey("key")
+
+	mItemsAccepted = stats.Int64("otelcol/ratelimit/items_accepted", "Number of spans, metric data points or log records within budget", "1")
+	mItemsDropped  = stats.Int64("otelcol/ratelimit/items_dropped", "Number of spans, metric data points or log records dropped over budget", "1")
+	mItemsDeferred = stats.Int64("otelcol/ratelimit/items_deferred", "Number of spans, metric data points or log records held waiting for budget", "1")
+)
+
+var viewItemsAccepted = &view.View{
+	Name:        mItemsAccepted.Name(),
+	Description: mItemsAccepted.Description(),
+	Measure:     mItemsAccepted,
+	TagKeys:     []tag.Key{tagProcessorKey, tagLimitKey},
+	Aggregation: view.Sum(),
+}
+
+var viewItemsDropped = &view.View{
+	Name:        mItemsDropped.Name(),
+	Description: mItemsDropped.Description(),
+	Measure:     mItemsDropped,
+	TagKeys:     []tag.Key{tagProcessorKey, tagLimitKey},
+	Aggregation: view.Sum(),
+}
+
+var viewItemsDeferred = &view.View{
+	Name:        mItemsDeferred.Name(),
+	Description: mItemsDeferred.Description(),
+	Measure:     mItemsDeferred,
+	TagKeys:     []tag.Key{tagProcessorKey, tagLimitKey},
+	Aggregation: view.Sum(),
+}
+
+func recordItemsAccepted(processor string, key string, n int) {
+	record(processor, key, mItemsAccepted.M(int64(n)))
+}
+
+func recordItemsDropped(processor string, key string, n int) {
+	record(processor, key, mItemsDropped.M(int64(n)))
+}
+
+func recordItemsDeferred(processor string, key string, n int) {
+	record(processor, key, mItemsDeferred.M(int64(n)))
+}
+
+func record(processor string, key string, m stats.Measurement) {
+	mutators := []tag.Mutator{tag.Upsert(tagProcessorKey, processor), tag.Upsert(tagLimitKey, key)}
+	_ = stats.RecordWithTags(context.Background(), mutators, m)
+}
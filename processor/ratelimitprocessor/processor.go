@@ -0,0 +1,219 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+// rateLimitProcessor forwards the data of the resources whose value of the
+// key is within budget. The data of each resource is either forwarded or
+// dropped as a whole.
+type rateLimitProcessor struct {
+	name    string
+	key     string
+	limiter *limiter
+}
+
+func newRateLimitProcessor(cfg *Config) (*rateLimitProcessor, error) {
+	l, err := newLimiter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &rateLimitProcessor{
+		name:    cfg.Name(),
+		key:     cfg.Key,
+		limiter: l,
+	}, nil
+}
+
+func (p *rateLimitProcessor) ProcessTraces(ctx context.Context, td pdata.Traces) (pdata.Traces, error) {
+	now := time.Now()
+	var delay time.Duration
+	kept := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		if d, ok := p.admit(rs.Resource(), spanCount(rs), now); ok {
+			kept.ResourceSpans().Append(rs)
+			delay = maxDuration(delay, d)
+		}
+	}
+	if err := wait(ctx, delay); err != nil {
+		return td, err
+	}
+	return kept, nil
+}
+
+func (p *rateLimitProcessor) ProcessMetrics(ctx context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	now := time.Now()
+	var delay time.Duration
+	kept := pdata.NewMetrics()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		if d, ok := p.admit(rm.Resource(), dataPointCount(rm), now); ok {
+			kept.ResourceMetrics().Append(rm)
+			delay = maxDuration(delay, d)
+		}
+	}
+	if kept.ResourceMetrics().Len() == 0 && rms.Len() > 0 {
+		return md, processorhelper.ErrSkipProcessingData
+	}
+	if err := wait(ctx, delay); err != nil {
+		return md, err
+	}
+	return kept, nil
+}
+
+func (p *rateLimitProcessor) ProcessLogs(ctx context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	now := time.Now()
+	var delay time.Duration
+	kept := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		if d, ok := p.admit(rl.Resource(), logRecordCount(rl), now); ok {
+			kept.ResourceLogs().Append(rl)
+			delay = maxDuration(delay, d)
+		}
+	}
+	if err := wait(ctx, delay); err != nil {
+		return ld, err
+	}
+	return kept, nil
+}
+
+// admit takes the n items of a resource from the bucket of its value of the
+// key, returning whether they are kept and the delay after which they can be
+// forwarded. The resources without the key share the bucket of the empty
+// value.
+func (p *rateLimitProcessor) admit(resource pdata.Resource, n int, now time.Time) (time.Duration, bool) {
+	value := p.value(resource)
+	if n == 0 {
+		return 0, true
+	}
+
+	delay, ok := p.limiter.reserve(value, n, now)
+	if !ok {
+		recordItemsDropped(p.name, value, n)
+		return 0, false
+	}
+	if delay > 0 {
+		recordItemsDeferred(p.name, value, n)
+	}
+	recordItemsAccepted(p.name, value, n)
+	return delay, true
+}
+
+func (p *rateLimitProcessor) value(resource pdata.Resource) string {
+	if resource.IsNil() {
+		return ""
+	}
+	if v, ok := resource.Attributes().Get(p.key); ok && v.Type() == pdata.AttributeValueSTRING {
+		return v.StringVal()
+	}
+	return ""
+}
+
+// wait holds the data for the given delay, unless the context is done first.
+func wait(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func spanCount(rs pdata.ResourceSpans) int {
+	n := 0
+	ilss := rs.InstrumentationLibrarySpans()
+	for i := 0; i < ilss.Len(); i++ {
+		if ils := ilss.At(i); !ils.IsNil() {
+			n += ils.Spans().Len()
+		}
+	}
+	return n
+}
+
+func dataPointCount(rm pdata.ResourceMetrics) int {
+	n := 0
+	ilms := rm.InstrumentationLibraryMetrics()
+	for i := 0; i < ilms.Len(); i++ {
+		ilm := ilms.At(i)
+		if ilm.IsNil() {
+			continue
+		}
+		ms := ilm.Metrics()
+		for j := 0; j < ms.Len(); j++ {
+			m := ms.At(j)
+			if m.IsNil() {
+				continue
+			}
+			switch m.DataType() {
+			case pdata.MetricDataTypeIntGauge:
+				n += m.IntGauge().DataPoints().Len()
+			case pdata.MetricDataTypeDoubleGauge:
+				n += m.DoubleGauge().DataPoints().Len()
+			case pdata.MetricDataTypeIntSum:
+				n += m.IntSum().DataPoints().Len()
+			case pdata.MetricDataTypeDoubleSum:
+				n += m.DoubleSum().DataPoints().Len()
+			case pdata.MetricDataTypeIntHistogram:
+				n += m.IntHistogram().DataPoints().Len()
+			case pdata.MetricDataTypeDoubleHistogram:
+				n += m.DoubleHistogram().DataPoints().Len()
+			}
+		}
+	}
+	return n
+}
+
+func logRecordCount(rl pdata.ResourceLogs) int {
+	n := 0
+	ills := rl.InstrumentationLibraryLogs()
+	for i := 0; i < ills.Len(); i++ {
+		if ill := ills.At(i); !ill.IsNil() {
+			n += ill.Logs().Len()
+		}
+	}
+	return n
+}
@@ -0,0 +1,233 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimitprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+type resourceItems struct {
+	value string
+	n     int
+}
+
+// generateTraces returns traces with a resource for each of the given values
+// of service.name, the empty value leaving the attribute out.
+func generateTraces(resources ...resourceItems) pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(len(resources))
+	for i, r := range resources {
+		rs := td.ResourceSpans().At(i)
+		if r.value != "" {
+			rs.Resource().InitEmpty()
+			rs.Resource().Attributes().InsertString("service.name", r.value)
+		}
+		rs.InstrumentationLibrarySpans().Resize(1)
+		rs.InstrumentationLibrarySpans().At(0).Spans().Resize(r.n)
+	}
+	return td
+}
+
+func services(td pdata.Traces) []string {
+	var values []string
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		value := ""
+		if !rs.Resource().IsNil() {
+			if v, ok := rs.Resource().Attributes().Get("service.name"); ok {
+				value = v.StringVal()
+			}
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+func newTraceProcessor(t *testing.T, cfg *Config) (component.TraceProcessor, *exportertest.SinkTraceExporter) {
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewFactory().CreateTraceProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	return tp, sink
+}
+
+// droppedItems returns the number of items dropped by the processor for each value of the key.
+func droppedItems(t *testing.T, processor string) map[string]float64 {
+	rows, err := view.RetrieveData(viewItemsDropped.Name)
+	require.NoError(t, err)
+	dropped := map[string]float64{}
+	for _, row := range rows {
+		tags := map[tag.Key]string{}
+		for _, tg := range row.Tags {
+			tags[tg.Key] = tg.Value
+		}
+		if tags[tagProcessorKey] == processor {
+			dropped[tags[tagLimitKey]] = row.Data.(*view.SumData).Value
+		}
+	}
+	return dropped
+}
+
+func TestProcessTracesDrop(t *testing.T) {
+	cfg := testConfig()
+	cfg.SetName("ratelimit/drop")
+	cfg.Rate = 0.001
+	cfg.Burst = 10
+	tp, sink := newTraceProcessor(t, cfg)
+	before := droppedItems(t, "ratelimit/drop")
+
+	ctx := context.Background()
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"a", 8}, resourceItems{"b", 8}, resourceItems{"", 3})))
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"a", 5}, resourceItems{"b", 1}, resourceItems{"", 8})))
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"c", 11})))
+
+	traces := sink.AllTraces()
+	require.Len(t, traces, 3)
+	assert.Equal(t, []string{"a", "b", ""}, services(traces[0]))
+	assert.Equal(t, []string{"b"}, services(traces[1]))
+	assert.Equal(t, 0, traces[2].ResourceSpans().Len())
+
+	after := droppedItems(t, "ratelimit/drop")
+	dropped := map[string]float64{}
+	for value, n := range after {
+		dropped[value] = n - before[value]
+	}
+	// The resources without service.name share a bucket, and no batch larger than the burst fits.
+	assert.Equal(t, map[string]float64{"a": 5, "": 8, "c": 11}, dropped)
+}
+
+func TestProcessTracesOverrides(t *testing.T) {
+	cfg := testConfig()
+	cfg.Rate = 0.001
+	cfg.Burst = 1
+	cfg.Overrides = []OverrideConfig{{Value: "premium", Rate: 0.001, Burst: 100}}
+	tp, sink := newTraceProcessor(t, cfg)
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), generateTraces(resourceItems{"premium", 50}, resourceItems{"other", 50})))
+
+	traces := sink.AllTraces()
+	require.Len(t, traces, 1)
+	assert.Equal(t, []string{"premium"}, services(traces[0]))
+}
+
+func TestProcessTracesDefer(t *testing.T) {
+	cfg := testConfig()
+	cfg.Rate = 100
+	cfg.Burst = 10
+	cfg.Policy = DeferPolicy
+	cfg.MaxDelay = 150 * time.Millisecond
+	tp, sink := newTraceProcessor(t, cfg)
+
+	ctx := context.Background()
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"a", 10})))
+
+	// The bucket is empty, the spans are held until it refills.
+	start := time.Now()
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"a", 5})))
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(40*time.Millisecond))
+
+	// The spans needing more than the max delay, or larger than the burst, are dropped.
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"a", 10}, resourceItems{"a", 10})))
+	require.NoError(t, tp.ConsumeTraces(ctx, generateTraces(resourceItems{"b", 11})))
+
+	traces := sink.AllTraces()
+	require.Len(t, traces, 4)
+	assert.Equal(t, 1, traces[1].ResourceSpans().Len())
+	assert.Equal(t, 1, traces[2].ResourceSpans().Len())
+	assert.Equal(t, 0, traces[3].ResourceSpans().Len())
+}
+
+func TestProcessTracesDeferCanceled(t *testing.T) {
+	cfg := testConfig()
+	cfg.Rate = 1
+	cfg.Burst = 10
+	cfg.Policy = DeferPolicy
+	cfg.MaxDelay = 10 * time.Second
+	p, err := newRateLimitProcessor(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = p.ProcessTraces(ctx, generateTraces(resourceItems{"a", 10}))
+	require.NoError(t, err)
+
+	cancel()
+	_, err = p.ProcessTraces(ctx, generateTraces(resourceItems{"a", 5}))
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestProcessMetrics(t *testing.T) {
+	cfg := testConfig()
+	cfg.Key = "tenant.id"
+	cfg.Rate = 0.001
+	cfg.Burst = 3
+	sink := new(exportertest.SinkMetricsExporter)
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("tenant.id", "a")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	ms := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	ms.Resize(1)
+	ms.At(0).SetDataType(pdata.MetricDataTypeIntGauge)
+	ms.At(0).IntGauge().InitEmpty()
+	ms.At(0).IntGauge().DataPoints().Resize(2)
+
+	ctx := context.Background()
+	require.NoError(t, mp.ConsumeMetrics(ctx, md))
+	// The batches left empty are not forwarded.
+	require.NoError(t, mp.ConsumeMetrics(ctx, md))
+
+	metrics := sink.AllMetrics()
+	require.Len(t, metrics, 1)
+	_, points := metrics[0].MetricAndDataPointCount()
+	assert.Equal(t, 2, points)
+}
+
+func TestProcessLogs(t *testing.T) {
+	cfg := testConfig()
+	cfg.Rate = 0.001
+	cfg.Burst = 3
+	sink := new(exportertest.SinkLogsExporter)
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().Resize(2)
+
+	ctx := context.Background()
+	require.NoError(t, lp.ConsumeLogs(ctx, ld))
+	require.NoError(t, lp.ConsumeLogs(ctx, ld))
+
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	assert.Equal(t, 2, logs[0].LogRecordCount())
+	assert.Equal(t, 0, logs[1].LogRecordCount())
+}
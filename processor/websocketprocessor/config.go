@@ -0,0 +1,31 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the WebSocket processor.
+type Config struct {
+	configmodels.ProcessorSettings `mapstructure:",squash"`
+	confighttp.HTTPServerSettings  `mapstructure:",squash"`
+
+	// RateLimit is the maximum number of batches per second streamed to the
+	// clients, the other batches only going through the processor
+	// (default 1).
+	RateLimit float64 `mapstructure:"rate_limit"`
+}
@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/processor/websocketprocessor
+
+go 1.14
+
+require (
+	github.com/gogo/protobuf v1.3.1
+	github.com/gorilla/websocket v1.4.2
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
+	go.uber.org/zap v1.16.0
+	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
+)
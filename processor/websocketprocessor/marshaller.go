@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"bytes"
+
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// Marshaler configuration used for marshaling Protobuf to JSON. Use default
+// config.
+var marshaler = &jsonpb.Marshaler{}
+
+// marshalTraces encodes the traces as an OTLP/JSON ExportTraceServiceRequest.
+func marshalTraces(td pdata.Traces) ([]byte, error) {
+	rss := pdata.TracesToOtlp(td)
+	resources := make([]proto.Message, len(rss))
+	for i, rs := range rss {
+		resources[i] = rs
+	}
+	return marshalRequest("resourceSpans", resources)
+}
+
+// marshalMetrics encodes the metrics as an OTLP/JSON
+// ExportMetricsServiceRequest.
+func marshalMetrics(md pdata.Metrics) ([]byte, error) {
+	rms := pdata.MetricsToOtlp(md)
+	resources := make([]proto.Message, len(rms))
+	for i, rm := range rms {
+		resources[i] = rm
+	}
+	return marshalRequest("resourceMetrics", resources)
+}
+
+// marshalLogs encodes the logs as an OTLP/JSON ExportLogsServiceRequest.
+func marshalLogs(ld pdata.Logs) ([]byte, error) {
+	rls := *ld.InternalRep().Orig
+	resources := make([]proto.Message, len(rls))
+	for i, rl := range rls {
+		resources[i] = rl
+	}
+	return marshalRequest("resourceLogs", resources)
+}
+
+// marshalRequest encodes an export request from its resources, as the
+// request types are internal to the Collector.
+func marshalRequest(field string, resources []proto.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"` + field + `":[`)
+	for i, resource := range resources {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := marshaler.Marshal(&buf, resource); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteString("]}")
+	return buf.Bytes(), nil
+}
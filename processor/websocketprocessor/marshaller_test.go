@@ -0,0 +1,141 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func testTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(1)
+	rs := td.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "frontend")
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	spans.Resize(2)
+	for i, name := range []string{"GET /api/orders", "SELECT orders"} {
+		spans.At(i).SetName(name)
+		spans.At(i).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		spans.At(i).SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+	return td
+}
+
+func testMetrics() pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName("requests")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().InitEmpty()
+	metrics.At(0).IntSum().DataPoints().Resize(1)
+	metrics.At(0).IntSum().DataPoints().At(0).SetValue(42)
+	return md
+}
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	logs.At(0).SetName("access")
+	logs.At(0).Body().SetStringVal("GET /api/orders 200")
+	return ld
+}
+
+// request is the subset of the OTLP/JSON export requests checked by the
+// tests.
+type request struct {
+	ResourceSpans []struct {
+		Resource struct {
+			Attributes []struct {
+				Key string `json:"key"`
+			} `json:"attributes"`
+		} `json:"resource"`
+		InstrumentationLibrarySpans []struct {
+			Spans []struct {
+				TraceID string `json:"traceId"`
+				Name    string `json:"name"`
+			} `json:"spans"`
+		} `json:"instrumentationLibrarySpans"`
+	} `json:"resourceSpans"`
+	ResourceMetrics []struct {
+		InstrumentationLibraryMetrics []struct {
+			Metrics []struct {
+				Name string `json:"name"`
+			} `json:"metrics"`
+		} `json:"instrumentationLibraryMetrics"`
+	} `json:"resourceMetrics"`
+	ResourceLogs []struct {
+		InstrumentationLibraryLogs []struct {
+			Logs []struct {
+				Name string `json:"name"`
+			} `json:"logs"`
+		} `json:"instrumentationLibraryLogs"`
+	} `json:"resourceLogs"`
+}
+
+func TestMarshalTraces(t *testing.T) {
+	msg, err := marshalTraces(testTraces())
+	require.NoError(t, err)
+
+	var req request
+	require.NoError(t, json.Unmarshal(msg, &req))
+	require.Len(t, req.ResourceSpans, 1)
+	assert.Equal(t, "service.name", req.ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := req.ResourceSpans[0].InstrumentationLibrarySpans[0].Spans
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /api/orders", spans[0].Name)
+	assert.Equal(t, "SELECT orders", spans[1].Name)
+	assert.NotEmpty(t, spans[0].TraceID)
+}
+
+func TestMarshalMetrics(t *testing.T) {
+	msg, err := marshalMetrics(testMetrics())
+	require.NoError(t, err)
+
+	var req request
+	require.NoError(t, json.Unmarshal(msg, &req))
+	require.Len(t, req.ResourceMetrics, 1)
+	assert.Equal(t, "requests", req.ResourceMetrics[0].InstrumentationLibraryMetrics[0].Metrics[0].Name)
+}
+
+func TestMarshalLogs(t *testing.T) {
+	msg, err := marshalLogs(testLogs())
+	require.NoError(t, err)
+
+	var req request
+	require.NoError(t, json.Unmarshal(msg, &req))
+	require.Len(t, req.ResourceLogs, 1)
+	assert.Equal(t, "access", req.ResourceLogs[0].InstrumentationLibraryLogs[0].Logs[0].Name)
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	msg, err := marshalTraces(pdata.NewTraces())
+	require.NoError(t, err)
+	assert.Equal(t, `{"resourceSpans":[]}`, string(msg))
+}
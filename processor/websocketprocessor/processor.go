@@ -0,0 +1,48 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// wsProcessor samples the data passing it to its tap, leaving the data
+// unchanged.
+type wsProcessor struct {
+	tap *tap
+}
+
+func (p *wsProcessor) ProcessTraces(_ context.Context, td pdata.Traces) (pdata.Traces, error) {
+	p.tap.sample(func() ([]byte, error) {
+		return marshalTraces(td)
+	})
+	return td, nil
+}
+
+func (p *wsProcessor) ProcessMetrics(_ context.Context, md pdata.Metrics) (pdata.Metrics, error) {
+	p.tap.sample(func() ([]byte, error) {
+		return marshalMetrics(md)
+	})
+	return md, nil
+}
+
+func (p *wsProcessor) ProcessLogs(_ context.Context, ld pdata.Logs) (pdata.Logs, error) {
+	p.tap.sample(func() ([]byte, error) {
+		return marshalLogs(ld)
+	})
+	return ld, nil
+}
@@ -0,0 +1,239 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// The number of messages buffered for each client, a message being
+	// dropped for a client whose buffer is full.
+	clientBufferSize = 16
+	// The timeout of the writes to the WebSocket clients.
+	writeTimeout = 10 * time.Second
+)
+
+// tap streams a rate limited sample of the data passing the processors to the
+// clients connected to its endpoint.
+type tap struct {
+	config   *Config
+	logger   *zap.Logger
+	limiter  *rate.Limiter
+	upgrader websocket.Upgrader
+
+	mu      sync.Mutex
+	refs    int
+	server  *http.Server
+	clients map[*client]struct{}
+}
+
+// client is a connected WebSocket or HTTP client.
+type client struct {
+	messages chan []byte
+	// closed is closed when the tap shuts down.
+	closed chan struct{}
+}
+
+func newTap(config *Config, logger *zap.Logger) *tap {
+	return &tap{
+		config:  config,
+		logger:  logger,
+		limiter: rate.NewLimiter(rate.Limit(config.RateLimit), 1),
+		clients: map[*client]struct{}{},
+	}
+}
+
+// start starts the server on the first start of the processors sharing the
+// tap.
+func (t *tap) start(_ context.Context, host component.Host) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.refs++
+	if t.refs > 1 {
+		return nil
+	}
+
+	ln, err := t.config.ToListener()
+	if err != nil {
+		t.refs--
+		return err
+	}
+	t.server = t.config.ToServer(t)
+	go func(server *http.Server) {
+		if err := server.Serve(ln); err != http.ErrServerClosed {
+			host.ReportFatalError(err)
+		}
+	}(t.server)
+	return nil
+}
+
+// shutdown stops the server and disconnects the clients on the last shutdown
+// of the processors sharing the tap.
+func (t *tap) shutdown(context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.refs == 0 {
+		return nil
+	}
+	t.refs--
+	if t.refs > 0 {
+		return nil
+	}
+
+	for c := range t.clients {
+		close(c.closed)
+		delete(t.clients, c)
+	}
+	err := t.server.Close()
+	t.server = nil
+	return err
+}
+
+// sample sends a message to the connected clients if the rate limit allows
+// it, marshal being only called in that case.
+func (t *tap) sample(marshal func() ([]byte, error)) {
+	t.mu.Lock()
+	numClients := len(t.clients)
+	t.mu.Unlock()
+	if numClients == 0 || !t.limiter.Allow() {
+		return
+	}
+
+	msg, err := marshal()
+	if err != nil {
+		t.logger.Debug("Failed to marshal the sampled data", zap.Error(err))
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for c := range t.clients {
+		select {
+		case c.messages <- msg:
+		default:
+			// Never block the pipeline for a slow client.
+		}
+	}
+}
+
+func (t *tap) subscribe() *client {
+	c := &client{
+		messages: make(chan []byte, clientBufferSize),
+		closed:   make(chan struct{}),
+	}
+	t.mu.Lock()
+	t.clients[c] = struct{}{}
+	t.mu.Unlock()
+	return c
+}
+
+func (t *tap) unsubscribe(c *client) {
+	t.mu.Lock()
+	delete(t.clients, c)
+	t.mu.Unlock()
+}
+
+// ServeHTTP streams the messages over a WebSocket, or as newline delimited
+// JSON for the plain HTTP requests.
+func (t *tap) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		t.serveWebSocket(w, r)
+		return
+	}
+	t.serveStream(w, r)
+}
+
+func (t *tap) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := t.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader already replied with an error.
+		return
+	}
+	defer conn.Close()
+
+	c := t.subscribe()
+	defer t.unsubscribe(c)
+
+	// The messages of the client are discarded, reading them is needed to
+	// detect that the connection is closed.
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-c.messages:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				return
+			}
+		case <-readDone:
+			return
+		case <-c.closed:
+			_ = conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseGoingAway, ""),
+				time.Now().Add(writeTimeout))
+			return
+		}
+	}
+}
+
+func (t *tap) serveStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	c := t.subscribe()
+	defer t.unsubscribe(c)
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case msg := <-c.messages:
+			if _, err := w.Write(append(msg, '\n')); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		case <-c.closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websocketprocessor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+func newTestConfig(t *testing.T) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	return cfg
+}
+
+// waitForClients waits for the clients to be connected, and lifts the rate
+// limit of the tap for the tests.
+func waitForClients(t *testing.T, tap *tap, n int) {
+	tap.limiter.SetLimit(rate.Inf)
+	require.Eventually(t, func() bool {
+		tap.mu.Lock()
+		defer tap.mu.Unlock()
+		return len(tap.clients) == n
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestWebSocketStream(t *testing.T) {
+	cfg := newTestConfig(t)
+	sink := new(exportertest.SinkTraceExporter)
+	tp, err := NewFactory().CreateTraceProcessor(
+		context.Background(), component.ProcessorCreateParams{Logger: zap.NewNop()}, cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+cfg.Endpoint, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	waitForClients(t, taps[cfg], 1)
+
+	require.NoError(t, tp.ConsumeTraces(context.Background(), testTraces()))
+	// The data goes through the processor unchanged.
+	assert.Equal(t, 2, sink.SpansCount())
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	typ, msg, err := conn.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, websocket.TextMessage, typ)
+	var req request
+	require.NoError(t, json.Unmarshal(msg, &req))
+	require.Len(t, req.ResourceSpans, 1)
+
+	// The clients are disconnected on shutdown.
+	require.NoError(t, tp.Shutdown(context.Background()))
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+}
+
+func TestHTTPStream(t *testing.T) {
+	cfg := newTestConfig(t)
+	params := component.ProcessorCreateParams{Logger: zap.NewNop()}
+	mp, err := NewFactory().CreateMetricsProcessor(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	lp, err := NewFactory().CreateLogsProcessor(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	require.NoError(t, err)
+	// Both processors share the endpoint.
+	require.NoError(t, mp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, lp.Start(context.Background(), componenttest.NewNopHost()))
+
+	resp, err := http.Get("http://" + cfg.Endpoint)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	waitForClients(t, taps[cfg], 1)
+
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), testMetrics()))
+	require.NoError(t, lp.ConsumeLogs(context.Background(), testLogs()))
+
+	lines := bufio.NewScanner(resp.Body)
+	var metrics, logs request
+	require.True(t, lines.Scan())
+	require.NoError(t, json.Unmarshal(lines.Bytes(), &metrics))
+	assert.Len(t, metrics.ResourceMetrics, 1)
+	require.True(t, lines.Scan())
+	require.NoError(t, json.Unmarshal(lines.Bytes(), &logs))
+	assert.Len(t, logs.ResourceLogs, 1)
+
+	// The endpoint is only closed by the last shutdown.
+	require.NoError(t, mp.Shutdown(context.Background()))
+	require.NoError(t, mp.ConsumeMetrics(context.Background(), testMetrics()))
+	require.True(t, lines.Scan())
+	require.NoError(t, lp.Shutdown(context.Background()))
+	assert.False(t, lines.Scan())
+}
+
+func TestSampleRateLimit(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.RateLimit = 0.001
+	tap := newTap(cfg, zap.NewNop())
+
+	marshalled := 0
+	marshal := func() ([]byte, error) {
+		marshalled++
+		return []byte("{}"), nil
+	}
+
+	// Nothing is marshalled without clients.
+	tap.sample(marshal)
+	assert.Equal(t, 0, marshalled)
+
+	c := tap.subscribe()
+	defer tap.unsubscribe(c)
+	for i := 0; i < 3; i++ {
+		tap.sample(marshal)
+	}
+	assert.Equal(t, 1, marshalled)
+	assert.Len(t, c.messages, 1)
+}
+
+func TestSampleSlowClient(t *testing.T) {
+	cfg := newTestConfig(t)
+	tap := newTap(cfg, zap.NewNop())
+	tap.limiter.SetLimit(rate.Inf)
+
+	c := tap.subscribe()
+	defer tap.unsubscribe(c)
+	for i := 0; i < clientBufferSize*2; i++ {
+		tap.sample(func() ([]byte, error) {
+			return []byte("{}"), nil
+		})
+	}
+	// The messages beyond the buffer of the client are dropped.
+	assert.Len(t, c.messages, clientBufferSize)
+}
+
+func TestStartError(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Endpoint = "localhost:invalid"
+	tap := newTap(cfg, zap.NewNop())
+
+	assert.Error(t, tap.start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, tap.shutdown(context.Background()))
+}
@@ -0,0 +1,144 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// See https://www.aerospike.com/docs/tools/asinfo/ for the protocol.
+	infoProtoVersion = 2
+	infoProtoType    = 1
+	infoHeaderSize   = 8
+
+	// maxInfoResponseSize guards against allocating huge buffers if the peer
+	// is not an Aerospike node.
+	maxInfoResponseSize = 64 << 20
+)
+
+// client sends info commands to an Aerospike node.
+type client interface {
+	info(ctx context.Context, commands ...string) (map[string]string, error)
+}
+
+type infoClient struct {
+	endpoint string
+	timeout  time.Duration
+}
+
+var _ client = (*infoClient)(nil)
+
+// info sends the commands in a single request and returns the value of each
+// of them, keyed by command.
+func (c *infoClient) info(ctx context.Context, commands ...string) (map[string]string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if c.timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = conn.Write(encodeInfoRequest(commands)); err != nil {
+		return nil, err
+	}
+	return readInfoResponse(bufio.NewReader(conn))
+}
+
+func encodeInfoRequest(commands []string) []byte {
+	var body bytes.Buffer
+	for _, command := range commands {
+		body.WriteString(command)
+		body.WriteByte('\n')
+	}
+
+	msg := make([]byte, infoHeaderSize, infoHeaderSize+body.Len())
+	binary.BigEndian.PutUint64(msg, uint64(body.Len()))
+	msg[0] = infoProtoVersion
+	msg[1] = infoProtoType
+	return append(msg, body.Bytes()...)
+}
+
+func readInfoResponse(r io.Reader) (map[string]string, error) {
+	header := make([]byte, infoHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0] != infoProtoVersion || header[1] != infoProtoType {
+		return nil, fmt.Errorf("unexpected info protocol header %v", header[:2])
+	}
+
+	size := binary.BigEndian.Uint64(header) & (1<<48 - 1)
+	if size > maxInfoResponseSize {
+		return nil, fmt.Errorf("info response too large: %d bytes", size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for _, line := range strings.Split(string(body), "\n") {
+		if line == "" {
+			continue
+		}
+		// Each line is the command followed by a tab and its value.
+		parts := strings.SplitN(line, "\t", 2)
+		if len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		} else {
+			result[parts[0]] = ""
+		}
+	}
+	return result, nil
+}
+
+// parseStats parses the "key=value;key=value" format used by the statistics
+// commands.
+func parseStats(s string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(s, ";") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		}
+	}
+	return result
+}
+
+// parseList parses the ";" separated lists, such as the namespaces.
+func parseList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ";") {
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
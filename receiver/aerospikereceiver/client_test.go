@@ -0,0 +1,120 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer starts a fake Aerospike node answering the info commands with
+// the given values, unknown commands being answered with an empty value.
+func startServer(t *testing.T, values map[string]string) string {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				request, err := readInfoResponse(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				var body bytes.Buffer
+				for command := range request {
+					body.WriteString(command + "\t" + values[command] + "\n")
+				}
+				msg := make([]byte, infoHeaderSize)
+				binary.BigEndian.PutUint64(msg, uint64(body.Len()))
+				msg[0] = infoProtoVersion
+				msg[1] = infoProtoType
+				conn.Write(append(msg, body.Bytes()...))
+			}()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestInfoClient(t *testing.T) {
+	endpoint := startServer(t, map[string]string{
+		"node":       "BB9020011AC4202",
+		"namespaces": "test;bar",
+	})
+
+	c := &infoClient{endpoint: endpoint, timeout: 5 * time.Second}
+	info, err := c.info(context.Background(), "node", "namespaces", "unknown")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"node":       "BB9020011AC4202",
+		"namespaces": "test;bar",
+		"unknown":    "",
+	}, info)
+}
+
+func TestInfoClientConnectionError(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	endpoint := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	c := &infoClient{endpoint: endpoint, timeout: 5 * time.Second}
+	_, err = c.info(context.Background(), "node")
+	assert.Error(t, err)
+}
+
+func TestEncodeInfoRequest(t *testing.T) {
+	msg := encodeInfoRequest([]string{"node", "statistics"})
+	assert.Equal(t, []byte{2, 1, 0, 0, 0, 0, 0, 16}, msg[:infoHeaderSize])
+	assert.Equal(t, "node\nstatistics\n", string(msg[infoHeaderSize:]))
+}
+
+func TestReadInfoResponseErrors(t *testing.T) {
+	_, err := readInfoResponse(bytes.NewReader([]byte{3, 1, 0, 0, 0, 0, 0, 0}))
+	assert.EqualError(t, err, "unexpected info protocol header [3 1]")
+
+	_, err = readInfoResponse(bytes.NewReader([]byte{2, 1, 0, 0, 0xff, 0, 0, 0}))
+	assert.EqualError(t, err, "info response too large: 4278190080 bytes")
+
+	_, err = readInfoResponse(strings.NewReader("HTTP/1.1"))
+	assert.Error(t, err)
+}
+
+func TestParseStats(t *testing.T) {
+	assert.Equal(t, map[string]string{
+		"objects":     "10",
+		"stop_writes": "false",
+		"ns-cluster":  "",
+	}, parseStats("objects=10;stop_writes=false;ns-cluster=;invalid"))
+}
+
+func TestParseList(t *testing.T) {
+	assert.Equal(t, []string{"test", "bar"}, parseList("test;bar;"))
+	assert.Nil(t, parseList(""))
+}
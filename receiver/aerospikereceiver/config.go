@@ -0,0 +1,35 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/confignet"
+)
+
+// Config defines configuration for the Aerospike receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+	confignet.TCPAddr             `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the node is queried.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Timeout is the maximum time of a query, including the time to connect
+	// to the node.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
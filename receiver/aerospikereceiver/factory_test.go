@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateMetricsReceiver(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	r, err := createMetricsReceiver(context.Background(), params, createDefaultConfig(), exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.NotNil(t, r)
+
+	r, err = createMetricsReceiver(context.Background(), params, createDefaultConfig(), nil)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+	assert.Nil(t, r)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = 0
+	r, err = createMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.EqualError(t, err, `collection_interval must be positive for receiver "aerospike"`)
+	assert.Nil(t, r)
+}
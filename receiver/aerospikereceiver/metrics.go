@@ -0,0 +1,246 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const namespaceLabel = "aerospike.namespace"
+
+// label is a fixed label of a statMetric.
+type label struct {
+	key   string
+	value string
+}
+
+// statMetric defines how a statistic reported by Aerospike is converted into
+// a metric.
+type statMetric struct {
+	key    string
+	name   string
+	unit   string
+	desc   string
+	labels []label
+	mdType metricspb.MetricDescriptor_Type
+}
+
+// nodeMetrics are built from the "statistics" info command.
+var nodeMetrics = []*statMetric{
+	{
+		key:    "client_connections",
+		name:   "aerospike.node.connection.open",
+		unit:   "1",
+		desc:   "Number of open connections to the node.",
+		labels: []label{{"type", "client"}},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "fabric_connections",
+		name:   "aerospike.node.connection.open",
+		unit:   "1",
+		desc:   "Number of open connections to the node.",
+		labels: []label{{"type", "fabric"}},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "heartbeat_connections",
+		name:   "aerospike.node.connection.open",
+		unit:   "1",
+		desc:   "Number of open connections to the node.",
+		labels: []label{{"type", "heartbeat"}},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "cluster_size",
+		name:   "aerospike.node.cluster.size",
+		unit:   "1",
+		desc:   "Number of nodes in the cluster, as seen by the node.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "system_free_mem_pct",
+		name:   "aerospike.node.memory.free",
+		unit:   "%",
+		desc:   "Percentage of the system memory that is free.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "uptime",
+		name:   "aerospike.node.uptime",
+		unit:   "s",
+		desc:   "Time since the node started.",
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+}
+
+// namespaceMetrics are built from the "namespace/<name>" info command.
+var namespaceMetrics = []*statMetric{
+	{
+		key:    "objects",
+		name:   "aerospike.namespace.objects",
+		unit:   "1",
+		desc:   "Number of records stored in the namespace on the node.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "memory_used_bytes",
+		name:   "aerospike.namespace.memory.usage",
+		unit:   "By",
+		desc:   "Memory used by the namespace on the node.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "device_used_bytes",
+		name:   "aerospike.namespace.disk.usage",
+		unit:   "By",
+		desc:   "Storage used by the namespace on the node.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "device_available_pct",
+		name:   "aerospike.namespace.disk.available",
+		unit:   "%",
+		desc:   "Percentage of the storage of the namespace that is contiguously free and available for writes.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "stop_writes",
+		name:   "aerospike.namespace.stop_writes",
+		unit:   "1",
+		desc:   "Whether the namespace rejects writes because it is out of memory or storage (1) or not (0).",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		key:    "client_read_success",
+		name:   "aerospike.namespace.transaction.count",
+		unit:   "1",
+		desc:   "Number of client transactions processed by the namespace on the node.",
+		labels: []label{{"type", "read"}, {"result", "success"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		key:    "client_read_error",
+		name:   "aerospike.namespace.transaction.count",
+		unit:   "1",
+		desc:   "Number of client transactions processed by the namespace on the node.",
+		labels: []label{{"type", "read"}, {"result", "error"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		key:    "client_write_success",
+		name:   "aerospike.namespace.transaction.count",
+		unit:   "1",
+		desc:   "Number of client transactions processed by the namespace on the node.",
+		labels: []label{{"type", "write"}, {"result", "success"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		key:    "client_write_error",
+		name:   "aerospike.namespace.transaction.count",
+		unit:   "1",
+		desc:   "Number of client transactions processed by the namespace on the node.",
+		labels: []label{{"type", "write"}, {"result", "error"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+}
+
+// metricsBuilder accumulates the time series of the metrics, merging the
+// statistics reported under the same metric name.
+type metricsBuilder struct {
+	now     *timestamppb.Timestamp
+	start   *timestamppb.Timestamp
+	metrics []*metricspb.Metric
+	byName  map[string]*metricspb.Metric
+	errs    []error
+}
+
+// newMetricsBuilder creates a metricsBuilder, cumulative metrics starting at
+// the start time of the node.
+func newMetricsBuilder(now, nodeStart time.Time) *metricsBuilder {
+	return &metricsBuilder{
+		now:    timestamppb.New(now),
+		start:  timestamppb.New(nodeStart),
+		byName: make(map[string]*metricspb.Metric),
+	}
+}
+
+// add converts the statistics into metrics. The statistics not reported by
+// the node, for instance because of its version, are ignored.
+func (b *metricsBuilder) add(defs []*statMetric, stats map[string]string, extraLabels ...label) {
+	for _, def := range defs {
+		raw, ok := stats[def.key]
+		if !ok {
+			continue
+		}
+		value, err := parseStatValue(raw)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("statistic %q: %w", def.key, err))
+			continue
+		}
+
+		labels := append(append([]label(nil), extraLabels...), def.labels...)
+		metric, ok := b.byName[def.name]
+		if !ok {
+			labelKeys := make([]*metricspb.LabelKey, 0, len(labels))
+			for _, l := range labels {
+				labelKeys = append(labelKeys, &metricspb.LabelKey{Key: l.key})
+			}
+			metric = &metricspb.Metric{
+				MetricDescriptor: &metricspb.MetricDescriptor{
+					Name:        def.name,
+					Description: def.desc,
+					Unit:        def.unit,
+					Type:        def.mdType,
+					LabelKeys:   labelKeys,
+				},
+			}
+			b.byName[def.name] = metric
+			b.metrics = append(b.metrics, metric)
+		}
+
+		labelValues := make([]*metricspb.LabelValue, 0, len(labels))
+		for _, l := range labels {
+			labelValues = append(labelValues, &metricspb.LabelValue{Value: l.value, HasValue: true})
+		}
+		timeseries := &metricspb.TimeSeries{
+			LabelValues: labelValues,
+			Points: []*metricspb.Point{
+				{Timestamp: b.now, Value: &metricspb.Point_Int64Value{Int64Value: value}},
+			},
+		}
+		if def.mdType == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+			timeseries.StartTimestamp = b.start
+		}
+		metric.Timeseries = append(metric.Timeseries, timeseries)
+	}
+}
+
+// parseStatValue parses an integer statistic, booleans being converted to 0
+// or 1.
+func parseStatValue(s string) (int64, error) {
+	switch s {
+	case "true":
+		return 1, nil
+	case "false":
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsBuilder(t *testing.T) {
+	now := time.Unix(1000, 0)
+	b := newMetricsBuilder(now, now.Add(-100*time.Second))
+	b.add(nodeMetrics, map[string]string{
+		"client_connections": "12",
+		"fabric_connections": "4",
+		"uptime":             "100",
+	})
+	b.add(namespaceMetrics, map[string]string{
+		"objects":           "42",
+		"stop_writes":       "true",
+		"client_read_error": "invalid",
+	}, label{namespaceLabel, "test"})
+	require.Len(t, b.errs, 1)
+	assert.EqualError(t, b.errs[0], `statistic "client_read_error": strconv.ParseInt: parsing "invalid": invalid syntax`)
+
+	require.Len(t, b.metrics, 4)
+
+	connections := b.metrics[0]
+	assert.Equal(t, "aerospike.node.connection.open", connections.MetricDescriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "type"}}, connections.MetricDescriptor.LabelKeys)
+	require.Len(t, connections.Timeseries, 2)
+	assert.Equal(t, "client", connections.Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(12), connections.Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "fabric", connections.Timeseries[1].LabelValues[0].Value)
+	assert.Equal(t, int64(4), connections.Timeseries[1].Points[0].GetInt64Value())
+
+	uptime := b.metrics[1]
+	assert.Equal(t, "aerospike.node.uptime", uptime.MetricDescriptor.Name)
+	assert.Equal(t, int64(900), uptime.Timeseries[0].StartTimestamp.Seconds)
+	assert.Equal(t, int64(1000), uptime.Timeseries[0].Points[0].Timestamp.Seconds)
+
+	objects := b.metrics[2]
+	assert.Equal(t, "aerospike.namespace.objects", objects.MetricDescriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: namespaceLabel}}, objects.MetricDescriptor.LabelKeys)
+	assert.Equal(t, "test", objects.Timeseries[0].LabelValues[0].Value)
+	assert.Nil(t, objects.Timeseries[0].StartTimestamp)
+
+	stopWrites := b.metrics[3]
+	assert.Equal(t, "aerospike.namespace.stop_writes", stopWrites.MetricDescriptor.Name)
+	assert.Equal(t, int64(1), stopWrites.Timeseries[0].Points[0].GetInt64Value())
+}
@@ -0,0 +1,168 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "aerospike"
+	transport  = "tcp"
+
+	nodeNameLabel    = "aerospike.node.name"
+	clusterNameLabel = "aerospike.cluster.name"
+)
+
+var _ component.MetricsReceiver = (*aerospikeReceiver)(nil)
+
+// aerospikeReceiver implements the component.MetricsReceiver for Aerospike.
+type aerospikeReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       client
+	cancel       context.CancelFunc
+}
+
+// newAerospikeReceiver creates the Aerospike receiver with the given parameters.
+func newAerospikeReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	r := &aerospikeReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client: &infoClient{
+			endpoint: config.Endpoint,
+			timeout:  config.Timeout,
+		},
+	}
+	return r, nil
+}
+
+// Start begins collecting the metrics of the Aerospike node.
+func (r *aerospikeReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect Aerospike metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the Aerospike receiver.
+func (r *aerospikeReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *aerospikeReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	md, err := r.buildMetricsData(ctx)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+func (r *aerospikeReceiver) buildMetricsData(ctx context.Context) (consumerdata.MetricsData, error) {
+	info, err := r.client.info(ctx, "node", "cluster-name", "statistics", "namespaces")
+	if err != nil {
+		return consumerdata.MetricsData{}, err
+	}
+
+	namespaces := parseList(info["namespaces"])
+	namespaceCommands := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		namespaceCommands = append(namespaceCommands, "namespace/"+namespace)
+	}
+	var namespaceInfo map[string]string
+	if len(namespaceCommands) > 0 {
+		if namespaceInfo, err = r.client.info(ctx, namespaceCommands...); err != nil {
+			return consumerdata.MetricsData{}, err
+		}
+	}
+
+	now := time.Now()
+	stats := parseStats(info["statistics"])
+	nodeStart := now
+	if uptime, err := strconv.ParseInt(stats["uptime"], 10, 64); err == nil {
+		nodeStart = now.Add(-time.Duration(uptime) * time.Second)
+	}
+
+	b := newMetricsBuilder(now, nodeStart)
+	b.add(nodeMetrics, stats)
+	for _, namespace := range namespaces {
+		b.add(namespaceMetrics, parseStats(namespaceInfo["namespace/"+namespace]), label{namespaceLabel, namespace})
+	}
+	if len(b.errs) > 0 {
+		r.logger.Warn("Failed to parse Aerospike statistics", zap.Errors("errors", b.errs))
+	}
+
+	labels := map[string]string{nodeNameLabel: info["node"]}
+	// Nodes without a configured cluster name report "null".
+	if clusterName := info["cluster-name"]; clusterName != "" && clusterName != "null" {
+		labels[clusterNameLabel] = clusterName
+	}
+	return consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{
+			Type:   typeStr,
+			Labels: labels,
+		},
+		Metrics: b.metrics,
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aerospikereceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+var testValues = map[string]string{
+	"node":           "BB9020011AC4202",
+	"cluster-name":   "production",
+	"statistics":     "cluster_size=3;uptime=3600;client_connections=12;fabric_connections=24;heartbeat_connections=2;system_free_mem_pct=57",
+	"namespaces":     "test;bar",
+	"namespace/test": "objects=100;memory_used_bytes=4096;device_used_bytes=8192;device_available_pct=99;stop_writes=false;client_read_success=10;client_read_error=1;client_write_success=5;client_write_error=0",
+	"namespace/bar":  "objects=7;memory_used_bytes=512;stop_writes=false",
+}
+
+func TestReceiverCollect(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t, testValues)
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newAerospikeReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*aerospikeReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	assert.Equal(t, map[string]string{
+		nodeNameLabel:    "BB9020011AC4202",
+		clusterNameLabel: "production",
+	}, ocmds[0].Resource.Labels)
+
+	metrics := make(map[string]int)
+	for _, metric := range ocmds[0].Metrics {
+		metrics[metric.MetricDescriptor.Name] = len(metric.Timeseries)
+	}
+	assert.Equal(t, map[string]int{
+		"aerospike.node.connection.open":        3,
+		"aerospike.node.cluster.size":           1,
+		"aerospike.node.memory.free":            1,
+		"aerospike.node.uptime":                 1,
+		"aerospike.namespace.objects":           2,
+		"aerospike.namespace.memory.usage":      2,
+		"aerospike.namespace.disk.usage":        1,
+		"aerospike.namespace.disk.available":    1,
+		"aerospike.namespace.stop_writes":       2,
+		"aerospike.namespace.transaction.count": 4,
+	}, metrics)
+}
+
+func TestReceiverCollectWithoutClusterName(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t, map[string]string{
+		"node":         "BB9020011AC4202",
+		"cluster-name": "null",
+	})
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newAerospikeReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*aerospikeReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+	assert.Equal(t, map[string]string{nodeNameLabel: "BB9020011AC4202"}, ocmds[0].Resource.Labels)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t, testValues)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newAerospikeReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,73 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type metricDataAccumulator struct {
+	m    []consumerdata.MetricsData
+	time time.Time
+}
+
+func (a *metricDataAccumulator) containerStats(task map[string]string, c ContainerMetadata, s *ContainerStats) {
+	var startTime *timestamppb.Timestamp
+	if !c.StartedAt.IsZero() {
+		startTime = timestamppb.New(c.StartedAt)
+	}
+
+	a.accumulate(
+		startTime,
+		containerResource(task, c),
+
+		cpuMetrics(containerPrefix, s),
+		memMetrics(containerPrefix, s.Memory),
+		networkMetrics(containerPrefix, s.Network),
+		diskMetrics(containerPrefix, s.Disk),
+		[]*metricspb.Metric{
+			cpuReservedMetric(containerPrefix, c.Limits),
+			memReservedMetric(containerPrefix, c.Limits),
+		},
+	)
+}
+
+func (a *metricDataAccumulator) accumulate(
+	startTime *timestamppb.Timestamp,
+	r *resourcepb.Resource,
+	m ...[]*metricspb.Metric,
+) {
+	var resourceMetrics []*metricspb.Metric
+	for _, metrics := range m {
+		for _, metric := range applyCurrentTime(metrics, a.time) {
+			if metric != nil {
+				if metric.MetricDescriptor.Type == metricspb.MetricDescriptor_CUMULATIVE_INT64 ||
+					metric.MetricDescriptor.Type == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
+					metric.Timeseries[0].StartTimestamp = startTime
+				}
+				resourceMetrics = append(resourceMetrics, metric)
+			}
+		}
+	}
+	a.m = append(a.m, consumerdata.MetricsData{
+		Resource: r,
+		Metrics:  resourceMetrics,
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EndpointEnvKey is the environment variable set by the ECS agent in the
+// containers to the URI of the task metadata endpoint v4.
+const EndpointEnvKey = "ECS_CONTAINER_METADATA_URI_V4"
+
+const defaultTimeout = 5 * time.Second
+
+// Client gets the responses of the task metadata endpoint.
+type Client interface {
+	Get(path string) ([]byte, error)
+}
+
+// NewClient creates a client of the task metadata endpoint at the given URI.
+func NewClient(endpoint string, logger *zap.Logger) Client {
+	return &clientImpl{
+		baseURL:    endpoint,
+		httpClient: http.Client{Timeout: defaultTimeout},
+		logger:     logger,
+	}
+}
+
+var _ Client = (*clientImpl)(nil)
+
+type clientImpl struct {
+	baseURL    string
+	httpClient http.Client
+	logger     *zap.Logger
+}
+
+func (c *clientImpl) Get(path string) ([]byte, error) {
+	req, err := http.NewRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			c.logger.Warn("failed to close response body", zap.Error(closeErr))
+		}
+	}()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task metadata endpoint response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("task metadata endpoint request GET %s failed - %q, response: %q",
+			req.URL.String(), resp.Status, string(body))
+	}
+
+	return body, nil
+}
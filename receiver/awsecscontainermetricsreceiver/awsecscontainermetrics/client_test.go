@@ -0,0 +1,59 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestClientGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v4/abc/task", r.URL.Path)
+		_, _ = w.Write([]byte(`{"Cluster":"default"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL+"/v4/abc", zap.NewNop())
+	resp, err := client.Get("/task")
+	require.NoError(t, err)
+	require.Equal(t, `{"Cluster":"default"}`, string(resp))
+}
+
+func TestClientGetBadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, zap.NewNop())
+	resp, err := client.Get("/task/stats")
+	require.Nil(t, resp)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "503 Service Unavailable")
+}
+
+func TestClientGetUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	client := NewClient(server.URL, zap.NewNop())
+	_, err := client.Get("/task")
+	require.Error(t, err)
+}
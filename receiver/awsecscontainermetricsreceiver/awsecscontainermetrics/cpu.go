@@ -0,0 +1,60 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+func cpuMetrics(prefix string, s *ContainerStats) []*metricspb.Metric {
+	if s.CPU == nil {
+		return nil
+	}
+	return []*metricspb.Metric{
+		cpuUsageMetric(prefix, s),
+		cpuCumulativeUsageMetric(prefix, s.CPU),
+	}
+}
+
+// cpuUsageMetric is the number of cores used by the container between the
+// previous read of its stats and the current one, nil on the first read.
+func cpuUsageMetric(prefix string, s *ContainerStats) *metricspb.Metric {
+	if s.PreviousCPU == nil || s.CPU.CPUUsage.TotalUsage == nil || s.PreviousCPU.CPUUsage.TotalUsage == nil ||
+		s.PreviousRead.IsZero() || !s.Read.After(s.PreviousRead) {
+		return nil
+	}
+	current, previous := *s.CPU.CPUUsage.TotalUsage, *s.PreviousCPU.CPUUsage.TotalUsage
+	if current < previous {
+		return nil
+	}
+	value := float64(current-previous) / float64(s.Read.Sub(s.PreviousRead)/time.Nanosecond)
+	return doubleGauge(prefix+"cpu.utilization", "1", &value)
+}
+
+func cpuCumulativeUsageMetric(prefix string, s *CPUStats) *metricspb.Metric {
+	if s.CPUUsage.TotalUsage == nil {
+		return nil
+	}
+	value := float64(*s.CPUUsage.TotalUsage) / 1_000_000_000
+	return cumulativeDouble(prefix+"cpu.time", "s", &value)
+}
+
+// cpuReservedMetric is the number of CPU units reserved for the container in
+// the task definition, 1024 units being a vCPU.
+func cpuReservedMetric(prefix string, limits Limits) *metricspb.Metric {
+	return doubleGauge(prefix+"cpu.reserved", "1", limits.CPU)
+}
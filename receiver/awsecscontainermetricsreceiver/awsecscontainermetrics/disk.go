@@ -0,0 +1,75 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// diskMetrics are the bytes read from and written to each block device by the
+// container.
+func diskMetrics(prefix string, s *DiskStats) []*metricspb.Metric {
+	if s == nil {
+		return nil
+	}
+	type deviceIO struct {
+		read, write *uint64
+	}
+	devices := map[string]*deviceIO{}
+	for _, entry := range s.IoServiceBytesRecursives {
+		if entry.Major == nil || entry.Minor == nil || entry.Value == nil {
+			continue
+		}
+		device := fmt.Sprintf("%d:%d", *entry.Major, *entry.Minor)
+		io, ok := devices[device]
+		if !ok {
+			io = &deviceIO{}
+			devices[device] = io
+		}
+		value := *entry.Value
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			io.read = &value
+		case "write":
+			io.write = &value
+		}
+	}
+
+	names := make([]string, 0, len(devices))
+	for name := range devices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var metrics []*metricspb.Metric
+	for _, name := range names {
+		io := devices[name]
+		metrics = append(metrics,
+			diskMetric(prefix+"disk.io", name, "read", io.read),
+			diskMetric(prefix+"disk.io", name, "write", io.write),
+		)
+	}
+	return metrics
+}
+
+func diskMetric(metricName, device, direction string, value *uint64) *metricspb.Metric {
+	metric := cumulativeInt(metricName, "By", value)
+	applyLabels(metric, map[string]string{deviceLabel: device, directionLabel: direction})
+	return metric
+}
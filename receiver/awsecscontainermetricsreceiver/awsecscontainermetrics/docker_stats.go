@@ -0,0 +1,78 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import "time"
+
+// ContainerStats are the docker stats of a container, as returned for each
+// container by the /task/stats path of the task metadata endpoint v4.
+type ContainerStats struct {
+	Name         string                  `json:"name"`
+	ID           string                  `json:"id"`
+	Read         time.Time               `json:"read"`
+	PreviousRead time.Time               `json:"preread"`
+	Memory       *MemoryStats            `json:"memory_stats,omitempty"`
+	Disk         *DiskStats              `json:"blkio_stats,omitempty"`
+	Network      map[string]NetworkStats `json:"networks,omitempty"`
+	CPU          *CPUStats               `json:"cpu_stats,omitempty"`
+	PreviousCPU  *CPUStats               `json:"precpu_stats,omitempty"`
+}
+
+// MemoryStats are the memory stats of a container, in bytes.
+type MemoryStats struct {
+	Usage    *uint64 `json:"usage,omitempty"`
+	MaxUsage *uint64 `json:"max_usage,omitempty"`
+	Limit    *uint64 `json:"limit,omitempty"`
+}
+
+// CPUStats are the CPU stats of a container.
+type CPUStats struct {
+	CPUUsage       CPUUsage `json:"cpu_usage,omitempty"`
+	SystemCPUUsage *uint64  `json:"system_cpu_usage,omitempty"`
+	OnlineCpus     *uint64  `json:"online_cpus,omitempty"`
+}
+
+// CPUUsage is the CPU time used by a container, in nanoseconds.
+type CPUUsage struct {
+	TotalUsage        *uint64 `json:"total_usage,omitempty"`
+	UsageInKernelmode *uint64 `json:"usage_in_kernelmode,omitempty"`
+	UsageInUserMode   *uint64 `json:"usage_in_usermode,omitempty"`
+}
+
+// NetworkStats are the network stats of an interface of a container.
+type NetworkStats struct {
+	RxBytes   *uint64 `json:"rx_bytes,omitempty"`
+	RxPackets *uint64 `json:"rx_packets,omitempty"`
+	RxErrors  *uint64 `json:"rx_errors,omitempty"`
+	RxDropped *uint64 `json:"rx_dropped,omitempty"`
+	TxBytes   *uint64 `json:"tx_bytes,omitempty"`
+	TxPackets *uint64 `json:"tx_packets,omitempty"`
+	TxErrors  *uint64 `json:"tx_errors,omitempty"`
+	TxDropped *uint64 `json:"tx_dropped,omitempty"`
+}
+
+// DiskStats are the block I/O stats of a container.
+type DiskStats struct {
+	IoServiceBytesRecursives []IoServiceBytesRecursive `json:"io_service_bytes_recursive,omitempty"`
+}
+
+// IoServiceBytesRecursive is the number of bytes transferred by an operation
+// to a block device.
+type IoServiceBytesRecursive struct {
+	Major *uint64 `json:"major,omitempty"`
+	Minor *uint64 `json:"minor,omitempty"`
+	Op    string  `json:"op,omitempty"`
+	Value *uint64 `json:"value,omitempty"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+
+func memMetrics(prefix string, s *MemoryStats) []*metricspb.Metric {
+	if s == nil {
+		return nil
+	}
+	return []*metricspb.Metric{
+		intGauge(prefix+"memory.usage", "By", s.Usage),
+		intGauge(prefix+"memory.usage.max", "By", s.MaxUsage),
+		intGauge(prefix+"memory.limit", "By", s.Limit),
+	}
+}
+
+// memReservedMetric is the memory reserved for the container in the task
+// definition, in bytes.
+func memReservedMetric(prefix string, limits Limits) *metricspb.Metric {
+	if limits.Memory == nil {
+		return nil
+	}
+	value := *limits.Memory * 1024 * 1024
+	return intGauge(prefix+"memory.reserved", "By", &value)
+}
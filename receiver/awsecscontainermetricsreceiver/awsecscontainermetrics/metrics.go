@@ -15,33 +15,27 @@
 package awsecscontainermetrics
 
 import (
-	"strconv"
 	"time"
 
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
-	"go.opentelemetry.io/collector/testutil/metricstestutil"
 )
 
-// GenerateDummyMetrics generates some dummy metrics
-func GenerateDummyMetrics() consumerdata.MetricsData {
-	md := consumerdata.MetricsData{}
-	ts := time.Now()
-	for i := 0; i < 5; i++ {
-		md.Metrics = append(md.Metrics,
-			metricstestutil.Gauge(
-				"test_"+strconv.Itoa(i),
-				[]string{"k0", "k1"},
-				metricstestutil.Timeseries(
-					time.Now(),
-					[]string{"v0", "v1"},
-					&metricspb.Point{
-						Timestamp: metricstestutil.Timestamp(ts),
-						Value:     &metricspb.Point_Int64Value{Int64Value: int64(i)},
-					},
-				),
-			),
-		)
+// MetricsData converts the stats of the containers of the task to metrics, a
+// MetricsData per running container, whose resource holds the labels of the task
+// and of the container.
+func MetricsData(metadata *TaskMetadata, stats map[string]*ContainerStats) []consumerdata.MetricsData {
+	acc := &metricDataAccumulator{
+		time: time.Now(),
 	}
-	return md
+
+	task := taskLabels(metadata)
+	for _, c := range metadata.Containers {
+		s := stats[c.DockerID]
+		if s == nil {
+			// the stats of the containers which are not running are null
+			continue
+		}
+		acc.containerStats(task, c, s)
+	}
+	return acc.m
 }
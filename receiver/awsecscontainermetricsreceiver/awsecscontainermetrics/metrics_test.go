@@ -0,0 +1,161 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+var _ RestClient = (*fakeRestClient)(nil)
+
+type fakeRestClient struct {
+	metadataErr error
+	statsErr    error
+}
+
+func (f *fakeRestClient) TaskMetadata() ([]byte, error) {
+	if f.metadataErr != nil {
+		return nil, f.metadataErr
+	}
+	return ioutil.ReadFile(filepath.Join("testdata", "task_metadata.json"))
+}
+
+func (f *fakeRestClient) TaskStats() ([]byte, error) {
+	if f.statsErr != nil {
+		return nil, f.statsErr
+	}
+	return ioutil.ReadFile(filepath.Join("testdata", "task_stats.json"))
+}
+
+func TestGetStats(t *testing.T) {
+	metadata, stats, err := NewStatsProvider(&fakeRestClient{}).GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, "FARGATE", metadata.LaunchType)
+	require.Len(t, metadata.Containers, 2)
+	require.Len(t, stats, 2)
+	assert.NotNil(t, stats[metadata.Containers[0].DockerID])
+	assert.Nil(t, stats[metadata.Containers[1].DockerID], "the stats of the stopped container should be null")
+}
+
+func TestGetStatsErrors(t *testing.T) {
+	_, _, err := NewStatsProvider(&fakeRestClient{metadataErr: errors.New("no metadata")}).GetStats()
+	assert.EqualError(t, err, "no metadata")
+	_, _, err = NewStatsProvider(&fakeRestClient{statsErr: errors.New("no stats")}).GetStats()
+	assert.EqualError(t, err, "no stats")
+}
+
+func TestMetricsData(t *testing.T) {
+	metadata, stats, err := NewStatsProvider(&fakeRestClient{}).GetStats()
+	require.NoError(t, err)
+
+	mds := MetricsData(metadata, stats)
+	require.Len(t, mds, 1, "only the running container should have metrics")
+
+	assert.Equal(t, map[string]string{
+		conventions.AttributeCloudProvider:  "aws",
+		conventions.AttributeCloudRegion:    "us-west-2",
+		conventions.AttributeCloudZone:      "us-west-2d",
+		attributeECSCluster:                 "arn:aws:ecs:us-west-2:111122223333:cluster/default",
+		attributeECSTaskARN:                 "arn:aws:ecs:us-west-2:111122223333:task/default/158d1c8083dd49d6b527399fd6414f5c",
+		attributeECSTaskFamily:              "curltest",
+		attributeECSTaskRevision:            "26",
+		attributeECSLaunchType:              "fargate",
+		conventions.AttributeContainerName:  "curl",
+		conventions.AttributeContainerID:    "ee08638adaaf009d78c248913f629e38299471d45fe7dc944d1039077e3424ca",
+		attributeContainerARN:               "arn:aws:ecs:us-west-2:111122223333:container/0206b271-b33f-47ab-86c6-a0ba208a70a9",
+		conventions.AttributeContainerImage: "111122223333.dkr.ecr.us-west-2.amazonaws.com/curltest",
+		conventions.AttributeContainerTag:   "latest",
+	}, mds[0].Resource.Labels)
+
+	metrics := map[string][]*metricspb.Metric{}
+	for _, metric := range mds[0].Metrics {
+		metrics[metric.MetricDescriptor.Name] = append(metrics[metric.MetricDescriptor.Name], metric)
+		require.NotNil(t, metric.Timeseries[0].Points[0].Timestamp)
+	}
+
+	assert.InDelta(t, 0.05, metrics["container.cpu.utilization"][0].Timeseries[0].Points[0].GetDoubleValue(), 0.0001)
+	assert.InDelta(t, 0.360968065, metrics["container.cpu.time"][0].Timeseries[0].Points[0].GetDoubleValue(), 1e-9)
+	assert.Equal(t, 10.0, metrics["container.cpu.reserved"][0].Timeseries[0].Points[0].GetDoubleValue())
+	assert.EqualValues(t, 3432448, metrics["container.memory.usage"][0].Timeseries[0].Points[0].GetInt64Value())
+	assert.EqualValues(t, 4300800, metrics["container.memory.usage.max"][0].Timeseries[0].Points[0].GetInt64Value())
+	assert.EqualValues(t, 134217728, metrics["container.memory.limit"][0].Timeseries[0].Points[0].GetInt64Value())
+	assert.EqualValues(t, 128*1024*1024, metrics["container.memory.reserved"][0].Timeseries[0].Points[0].GetInt64Value())
+
+	require.Len(t, metrics["container.network.io"], 2)
+	rx := metrics["container.network.io"][0]
+	assert.Equal(t, []*metricspb.LabelKey{{Key: directionLabel}, {Key: interfaceLabel}}, rx.MetricDescriptor.LabelKeys)
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "receive", HasValue: true}, {Value: "eth1", HasValue: true}}, rx.Timeseries[0].LabelValues)
+	assert.EqualValues(t, 564655295, rx.Timeseries[0].Points[0].GetInt64Value())
+	assert.NotNil(t, rx.Timeseries[0].StartTimestamp, "the cumulative metrics should start when the container started")
+	assert.Len(t, metrics["container.network.packets"], 2)
+	assert.Len(t, metrics["container.network.errors"], 2)
+	assert.Len(t, metrics["container.network.dropped"], 2)
+
+	require.Len(t, metrics["container.disk.io"], 2)
+	read, write := metrics["container.disk.io"][0], metrics["container.disk.io"][1]
+	assert.Equal(t, []*metricspb.LabelValue{{Value: "202:26368", HasValue: true}, {Value: "read", HasValue: true}}, read.Timeseries[0].LabelValues)
+	assert.EqualValues(t, 638976, read.Timeseries[0].Points[0].GetInt64Value())
+	assert.EqualValues(t, 12288, write.Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestMetricsDataFirstRead(t *testing.T) {
+	usage := uint64(1000)
+	metadata := &TaskMetadata{
+		LaunchType: "EC2",
+		Containers: []ContainerMetadata{{ContainerName: "app", DockerID: "abc", Image: "nginx"}},
+	}
+	stats := map[string]*ContainerStats{
+		"abc": {CPU: &CPUStats{CPUUsage: CPUUsage{TotalUsage: &usage}}},
+	}
+
+	mds := MetricsData(metadata, stats)
+	require.Len(t, mds, 1)
+	require.Len(t, mds[0].Metrics, 1, "the CPU utilization should not be computed without a previous read")
+	assert.Equal(t, "container.cpu.time", mds[0].Metrics[0].MetricDescriptor.Name)
+	assert.Nil(t, mds[0].Metrics[0].Timeseries[0].StartTimestamp)
+	assert.Equal(t, "ec2", mds[0].Resource.Labels[attributeECSLaunchType])
+	assert.Equal(t, "nginx", mds[0].Resource.Labels[conventions.AttributeContainerImage])
+	assert.NotContains(t, mds[0].Resource.Labels, conventions.AttributeContainerTag)
+	assert.NotContains(t, mds[0].Resource.Labels, conventions.AttributeCloudRegion)
+}
+
+func TestSplitImage(t *testing.T) {
+	tests := []struct {
+		image string
+		name  string
+		tag   string
+	}{
+		{"nginx", "nginx", ""},
+		{"nginx:1.19", "nginx", "1.19"},
+		{"localhost:5000/app", "localhost:5000/app", ""},
+		{"localhost:5000/app:v2", "localhost:5000/app", "v2"},
+		{"amazon/aws-xray-daemon@sha256:d691691e9652", "amazon/aws-xray-daemon", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, tag := splitImage(tt.image)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"sort"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+func networkMetrics(prefix string, s map[string]NetworkStats) []*metricspb.Metric {
+	interfaces := make([]string, 0, len(s))
+	for name := range s {
+		interfaces = append(interfaces, name)
+	}
+	sort.Strings(interfaces)
+
+	var metrics []*metricspb.Metric
+	for _, name := range interfaces {
+		stats := s[name]
+		metrics = append(metrics,
+			networkMetric(prefix+"network.io", "By", name, "receive", stats.RxBytes),
+			networkMetric(prefix+"network.io", "By", name, "transmit", stats.TxBytes),
+			networkMetric(prefix+"network.packets", "1", name, "receive", stats.RxPackets),
+			networkMetric(prefix+"network.packets", "1", name, "transmit", stats.TxPackets),
+			networkMetric(prefix+"network.errors", "1", name, "receive", stats.RxErrors),
+			networkMetric(prefix+"network.errors", "1", name, "transmit", stats.TxErrors),
+			networkMetric(prefix+"network.dropped", "1", name, "receive", stats.RxDropped),
+			networkMetric(prefix+"network.dropped", "1", name, "transmit", stats.TxDropped),
+		)
+	}
+	return metrics
+}
+
+func networkMetric(metricName, units, iface, direction string, value *uint64) *metricspb.Metric {
+	metric := cumulativeInt(metricName, units, value)
+	applyLabels(metric, map[string]string{interfaceLabel: iface, directionLabel: direction})
+	return metric
+}
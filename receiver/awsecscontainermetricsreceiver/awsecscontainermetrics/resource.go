@@ -0,0 +1,84 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"strings"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func taskLabels(metadata *TaskMetadata) map[string]string {
+	labels := map[string]string{
+		conventions.AttributeCloudProvider: cloudProviderAWS,
+	}
+	setLabel(labels, attributeECSCluster, metadata.Cluster)
+	setLabel(labels, attributeECSTaskARN, metadata.TaskARN)
+	setLabel(labels, attributeECSTaskFamily, metadata.Family)
+	setLabel(labels, attributeECSTaskRevision, metadata.Revision)
+	setLabel(labels, attributeECSLaunchType, strings.ToLower(metadata.LaunchType))
+	setLabel(labels, conventions.AttributeCloudZone, metadata.AvailabilityZone)
+	if region := regionFromARN(metadata.TaskARN); region != "" {
+		labels[conventions.AttributeCloudRegion] = region
+	}
+	return labels
+}
+
+func containerResource(task map[string]string, c ContainerMetadata) *resourcepb.Resource {
+	labels := make(map[string]string, len(task)+5)
+	for k, v := range task {
+		labels[k] = v
+	}
+	// augment the container resource with the task labels
+	setLabel(labels, conventions.AttributeContainerName, c.ContainerName)
+	setLabel(labels, conventions.AttributeContainerID, c.DockerID)
+	setLabel(labels, attributeContainerARN, c.ContainerARN)
+	name, tag := splitImage(c.Image)
+	setLabel(labels, conventions.AttributeContainerImage, name)
+	setLabel(labels, conventions.AttributeContainerTag, tag)
+	return &resourcepb.Resource{
+		Type:   "container",
+		Labels: labels,
+	}
+}
+
+func setLabel(labels map[string]string, key, value string) {
+	if value != "" {
+		labels[key] = value
+	}
+}
+
+// regionFromARN returns the region of an ARN, arn:partition:service:region:...
+func regionFromARN(arn string) string {
+	parts := strings.SplitN(arn, ":", 5)
+	if len(parts) < 5 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
+
+// splitImage splits an image reference into its name and tag, the digest of
+// the images referenced by digest being dropped.
+func splitImage(image string) (string, string) {
+	if i := strings.IndexByte(image, '@'); i >= 0 {
+		image = image[:i]
+	}
+	i := strings.LastIndexByte(image, ':')
+	if i < 0 || strings.ContainsRune(image[i:], '/') {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
@@ -0,0 +1,39 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+// RestClient is swappable for testing.
+type RestClient interface {
+	TaskMetadata() ([]byte, error)
+	TaskStats() ([]byte, error)
+}
+
+// HTTPRestClient is a thin wrapper around a task metadata endpoint client,
+// encapsulating its paths.
+type HTTPRestClient struct {
+	client Client
+}
+
+func NewRestClient(client Client) *HTTPRestClient {
+	return &HTTPRestClient{client: client}
+}
+
+func (c *HTTPRestClient) TaskMetadata() ([]byte, error) {
+	return c.client.Get("/task")
+}
+
+func (c *HTTPRestClient) TaskStats() ([]byte, error) {
+	return c.client.Get("/task/stats")
+}
@@ -0,0 +1,37 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestClient(t *testing.T) {
+	rest := NewRestClient(&fakeClient{})
+	resp, _ := rest.TaskMetadata()
+	require.Equal(t, "/task", string(resp))
+	resp, _ = rest.TaskStats()
+	require.Equal(t, "/task/stats", string(resp))
+}
+
+var _ Client = (*fakeClient)(nil)
+
+type fakeClient struct{}
+
+func (f *fakeClient) Get(path string) ([]byte, error) {
+	return []byte(path), nil
+}
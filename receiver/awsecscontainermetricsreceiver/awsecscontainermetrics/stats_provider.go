@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// StatsProvider gets the metadata and the docker stats of the containers of
+// the task.
+type StatsProvider struct {
+	rc RestClient
+}
+
+func NewStatsProvider(rc RestClient) *StatsProvider {
+	return &StatsProvider{rc: rc}
+}
+
+// GetStats calls the /task and /task/stats paths of the task metadata endpoint
+// and returns the task metadata and the stats of its containers by docker ID.
+// The stats of the containers which are not running are nil.
+func (p *StatsProvider) GetStats() (*TaskMetadata, map[string]*ContainerStats, error) {
+	metadataJSON, err := p.rc.TaskMetadata()
+	if err != nil {
+		return nil, nil, err
+	}
+	var metadata TaskMetadata
+	if err = json.Unmarshal(metadataJSON, &metadata); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal the task metadata: %w", err)
+	}
+
+	statsJSON, err := p.rc.TaskStats()
+	if err != nil {
+		return nil, nil, err
+	}
+	var stats map[string]*ContainerStats
+	if err = json.Unmarshal(statsJSON, &stats); err != nil {
+		return nil, nil, fmt.Errorf("failed to unmarshal the task stats: %w", err)
+	}
+	return &metadata, stats, nil
+}
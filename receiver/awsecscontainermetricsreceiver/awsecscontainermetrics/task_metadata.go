@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import "time"
+
+// TaskMetadata is the metadata of an ECS task, as returned by the /task path
+// of the task metadata endpoint v4.
+type TaskMetadata struct {
+	Cluster          string              `json:"Cluster,omitempty"`
+	TaskARN          string              `json:"TaskARN,omitempty"`
+	Family           string              `json:"Family,omitempty"`
+	Revision         string              `json:"Revision,omitempty"`
+	AvailabilityZone string              `json:"AvailabilityZone,omitempty"`
+	LaunchType       string              `json:"LaunchType,omitempty"`
+	KnownStatus      string              `json:"KnownStatus,omitempty"`
+	Limits           Limits              `json:"Limits,omitempty"`
+	Containers       []ContainerMetadata `json:"Containers,omitempty"`
+}
+
+// ContainerMetadata is the metadata of a container of an ECS task.
+type ContainerMetadata struct {
+	ContainerName string    `json:"Name,omitempty"`
+	DockerID      string    `json:"DockerId,omitempty"`
+	DockerName    string    `json:"DockerName,omitempty"`
+	ContainerARN  string    `json:"ContainerARN,omitempty"`
+	Image         string    `json:"Image,omitempty"`
+	ImageID       string    `json:"ImageID,omitempty"`
+	KnownStatus   string    `json:"KnownStatus,omitempty"`
+	Limits        Limits    `json:"Limits,omitempty"`
+	CreatedAt     time.Time `json:"CreatedAt,omitempty"`
+	StartedAt     time.Time `json:"StartedAt,omitempty"`
+}
+
+// Limits are the CPU units and memory in MiB reserved for a task or a
+// container.
+type Limits struct {
+	CPU    *float64 `json:"CPU,omitempty"`
+	Memory *uint64  `json:"Memory,omitempty"`
+}
@@ -0,0 +1,54 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsecscontainermetrics
+
+import (
+	"sort"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func applyCurrentTime(metrics []*metricspb.Metric, t time.Time) []*metricspb.Metric {
+	currentTime := timestamppb.New(t)
+	for _, metric := range metrics {
+		if metric != nil {
+			metric.Timeseries[0].Points[0].Timestamp = currentTime
+		}
+	}
+	return metrics
+}
+
+// applyLabels sets the labels of the single time series of the metric, in the
+// order of their keys.
+func applyLabels(metric *metricspb.Metric, labels map[string]string) {
+	if metric == nil {
+		return
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	labelKeys := make([]*metricspb.LabelKey, 0, len(keys))
+	labelValues := make([]*metricspb.LabelValue, 0, len(keys))
+	for _, key := range keys {
+		labelKeys = append(labelKeys, &metricspb.LabelKey{Key: key})
+		labelValues = append(labelValues, &metricspb.LabelValue{Value: labels[key], HasValue: true})
+	}
+	metric.MetricDescriptor.LabelKeys, metric.Timeseries[0].LabelValues = labelKeys, labelValues
+}
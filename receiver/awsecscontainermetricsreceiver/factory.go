@@ -16,12 +16,16 @@ package awsecscontainermetricsreceiver
 
 import (
 	"context"
+	"errors"
+	"os"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
 const (
@@ -55,6 +59,12 @@ func createMetricsReceiver(
 	cfg configmodels.Receiver,
 	nextConsumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
+	endpoint := os.Getenv(awsecscontainermetrics.EndpointEnvKey)
+	if endpoint == "" {
+		return nil, errors.New("no ECS task metadata endpoint detected, " +
+			awsecscontainermetrics.EndpointEnvKey + " is not set")
+	}
+	rest := awsecscontainermetrics.NewRestClient(awsecscontainermetrics.NewClient(endpoint, params.Logger))
 	rCfg := cfg.(*Config)
-	return newAwsEcsContainerMetricsReceiver(params.Logger, rCfg, nextConsumer)
+	return newAwsEcsContainerMetricsReceiver(params.Logger, rCfg, nextConsumer, rest)
 }
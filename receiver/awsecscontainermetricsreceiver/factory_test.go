@@ -16,6 +16,7 @@ package awsecscontainermetricsreceiver
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
 func TestValidConfig(t *testing.T) {
@@ -32,6 +35,7 @@ func TestValidConfig(t *testing.T) {
 }
 
 func TestCreateMetricsReceiver(t *testing.T) {
+	setEndpointEnv(t, "http://169.254.170.2/v4/158d1c8083dd49d6b527399fd6414f5c")
 	metricsReceiver, err := createMetricsReceiver(
 		context.Background(),
 		component.ReceiverCreateParams{Logger: zap.NewNop()},
@@ -43,6 +47,7 @@ func TestCreateMetricsReceiver(t *testing.T) {
 }
 
 func TestCreateMetricsReceiverWithNilConsumer(t *testing.T) {
+	setEndpointEnv(t, "http://169.254.170.2/v4/158d1c8083dd49d6b527399fd6414f5c")
 	metricsReceiver, err := createMetricsReceiver(
 		context.Background(),
 		component.ReceiverCreateParams{Logger: zap.NewNop()},
@@ -52,3 +57,27 @@ func TestCreateMetricsReceiverWithNilConsumer(t *testing.T) {
 	require.Nil(t, metricsReceiver)
 	require.Equal(t, err, componenterror.ErrNilNextConsumer)
 }
+
+func TestCreateMetricsReceiverWithoutEndpoint(t *testing.T) {
+	setEndpointEnv(t, "")
+	metricsReceiver, err := createMetricsReceiver(
+		context.Background(),
+		component.ReceiverCreateParams{Logger: zap.NewNop()},
+		createDefaultConfig(),
+		&testbed.MockMetricConsumer{},
+	)
+	require.Nil(t, metricsReceiver)
+	require.EqualError(t, err, "no ECS task metadata endpoint detected, ECS_CONTAINER_METADATA_URI_V4 is not set")
+}
+
+func setEndpointEnv(t *testing.T, endpoint string) {
+	previous, set := os.LookupEnv(awsecscontainermetrics.EndpointEnvKey)
+	os.Setenv(awsecscontainermetrics.EndpointEnvKey, endpoint)
+	t.Cleanup(func() {
+		if set {
+			os.Setenv(awsecscontainermetrics.EndpointEnvKey, previous)
+		} else {
+			os.Unsetenv(awsecscontainermetrics.EndpointEnvKey)
+		}
+	})
+}
@@ -28,6 +28,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
+const transport = "http"
+
 var _ component.MetricsReceiver = (*awsEcsContainerMetricsReceiver)(nil)
 
 // awsEcsContainerMetricsReceiver implements the component.MetricsReceiver for aws ecs container metrics.
@@ -36,13 +38,15 @@ type awsEcsContainerMetricsReceiver struct {
 	nextConsumer consumer.MetricsConsumer
 	config       *Config
 	cancel       context.CancelFunc
+	provider     *awsecscontainermetrics.StatsProvider
 }
 
 // newAwsEcsContainerMetricsReceiver creates the aws ecs container metrics receiver with the given parameters.
 func newAwsEcsContainerMetricsReceiver(
 	logger *zap.Logger,
 	config *Config,
-	nextConsumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
+	nextConsumer consumer.MetricsConsumer,
+	rest awsecscontainermetrics.RestClient) (component.MetricsReceiver, error) {
 	if nextConsumer == nil {
 		return nil, componenterror.ErrNilNextConsumer
 	}
@@ -51,13 +55,14 @@ func newAwsEcsContainerMetricsReceiver(
 		logger:       logger,
 		nextConsumer: nextConsumer,
 		config:       config,
+		provider:     awsecscontainermetrics.NewStatsProvider(rest),
 	}
 	return r, nil
 }
 
 // Start begins collecting metrics from Amazon ECS task metadata endpoint.
 func (aecmr *awsEcsContainerMetricsReceiver) Start(ctx context.Context, host component.Host) error {
-	ctx, aecmr.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, "http", aecmr.config.Name()))
+	ctx, aecmr.cancel = context.WithCancel(obsreport.ReceiverContext(ctx, typeStr, transport, aecmr.config.Name()))
 	go func() {
 		ticker := time.NewTicker(aecmr.config.CollectionInterval)
 		defer ticker.Stop()
@@ -65,7 +70,7 @@ func (aecmr *awsEcsContainerMetricsReceiver) Start(ctx context.Context, host com
 		for {
 			select {
 			case <-ticker.C:
-				aecmr.collectDataFromEndpoint(ctx)
+				_ = aecmr.collectDataFromEndpoint(ctx)
 			case <-ctx.Done():
 				return
 			}
@@ -81,10 +86,24 @@ func (aecmr *awsEcsContainerMetricsReceiver) Shutdown(context.Context) error {
 }
 
 // collectDataFromEndpoint collects container stats from Amazon ECS Task Metadata Endpoint
-// TODO: Replace with acutal logic.
+// and passes them to the next consumer.
 func (aecmr *awsEcsContainerMetricsReceiver) collectDataFromEndpoint(ctx context.Context) error {
-	md := awsecscontainermetrics.GenerateDummyMetrics()
+	metadata, stats, err := aecmr.provider.GetStats()
+	if err != nil {
+		aecmr.logger.Error("Failed to collect the stats from the ECS task metadata endpoint", zap.Error(err))
+		return err
+	}
 
-	err := aecmr.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	metrics := internaldata.OCSliceToMetrics(awsecscontainermetrics.MetricsData(metadata, stats))
+
+	var numTimeSeries, numPoints int
+	ctx = obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
+	err = aecmr.nextConsumer.ConsumeMetrics(ctx, metrics)
+	if err != nil {
+		aecmr.logger.Error("ConsumeMetricsData failed", zap.Error(err))
+	} else {
+		numTimeSeries, numPoints = metrics.MetricAndDataPointCount()
+	}
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, numTimeSeries, numPoints, err)
 	return err
 }
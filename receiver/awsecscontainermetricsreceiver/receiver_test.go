@@ -16,12 +16,17 @@ package awsecscontainermetricsreceiver
 
 import (
 	"context"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsecscontainermetricsreceiver/awsecscontainermetrics"
 )
 
 func TestReceiver(t *testing.T) {
@@ -30,6 +35,7 @@ func TestReceiver(t *testing.T) {
 		zap.NewNop(),
 		cfg,
 		exportertest.NewNopMetricsExporter(),
+		&fakeRestClient{},
 	)
 
 	require.NoError(t, err)
@@ -47,10 +53,12 @@ func TestReceiver(t *testing.T) {
 
 func TestCollectDataFromEndpoint(t *testing.T) {
 	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
 	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
 		zap.NewNop(),
 		cfg,
-		new(exportertest.SinkMetricsExporter),
+		sink,
+		&fakeRestClient{},
 	)
 
 	require.NoError(t, err)
@@ -61,4 +69,62 @@ func TestCollectDataFromEndpoint(t *testing.T) {
 
 	err = r.collectDataFromEndpoint(ctx)
 	require.NoError(t, err)
+
+	require.Len(t, sink.AllMetrics(), 1)
+	require.Equal(t, 1, sink.AllMetrics()[0].ResourceMetrics().Len(), "only the running container should have metrics")
+	require.Equal(t, 17, sink.MetricsCount())
+}
+
+func TestCollectDataFromEndpointWithEndpointError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
+	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
+		zap.NewNop(),
+		cfg,
+		sink,
+		&fakeRestClient{err: errors.New("endpoint unavailable")},
+	)
+	require.NoError(t, err)
+
+	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	err = r.collectDataFromEndpoint(context.Background())
+	require.EqualError(t, err, "endpoint unavailable")
+	require.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestCollectDataFromEndpointWithConsumerError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
+	sink.SetConsumeMetricsError(errors.New("consumer failed"))
+	metricsReceiver, err := newAwsEcsContainerMetricsReceiver(
+		zap.NewNop(),
+		cfg,
+		sink,
+		&fakeRestClient{},
+	)
+	require.NoError(t, err)
+
+	r := metricsReceiver.(*awsEcsContainerMetricsReceiver)
+	err = r.collectDataFromEndpoint(context.Background())
+	require.Error(t, err)
+}
+
+var _ awsecscontainermetrics.RestClient = (*fakeRestClient)(nil)
+
+type fakeRestClient struct {
+	err error
+}
+
+func (f *fakeRestClient) TaskMetadata() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return ioutil.ReadFile(filepath.Join("awsecscontainermetrics", "testdata", "task_metadata.json"))
+}
+
+func (f *fakeRestClient) TaskStats() ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return ioutil.ReadFile(filepath.Join("awsecscontainermetrics", "testdata", "task_stats.json"))
 }
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayreceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+// maxBatchSegments is the number of segments after which a batch is sent
+// downstream without waiting for the end of the batch window.
+const maxBatchSegments = 512
+
+// segmentBatch holds the segments converted since the last flush.
+type segmentBatch struct {
+	traces *translator.Batch
+	// ops holds the receive operation of each segment of the batch, ended
+	// once the batch is consumed.
+	ops []batchedOp
+}
+
+type batchedOp struct {
+	ctx   context.Context
+	count int
+}
+
+func newSegmentBatch(opts translator.Options) *segmentBatch {
+	return &segmentBatch{traces: translator.NewBatch(opts)}
+}
+
+// addToBatch converts the segment into the batch, which is flushed if full.
+func (x *xrayReceiver) addToBatch(batch *segmentBatch, seg udppoller.RawSegment) {
+	recordSegmentReceived(x.instanceName)
+	totalSpansCount, err := batch.traces.Add(seg.Payload)
+	if err != nil {
+		x.logger.Warn("X-Ray segment to OT traces conversion failed", zap.Error(err))
+		recordSegmentRejected(x.instanceName, err)
+		obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, err)
+		return
+	}
+	recordSegmentParsed(x.instanceName)
+	batch.ops = append(batch.ops, batchedOp{ctx: seg.Ctx, count: totalSpansCount})
+	if len(batch.ops) >= maxBatchSegments {
+		x.flushBatch(batch)
+	}
+}
+
+// flushBatch passes the segments of the batch to the next consumer, if any,
+// and empties the batch.
+func (x *xrayReceiver) flushBatch(current *segmentBatch) {
+	if len(current.ops) == 0 {
+		return
+	}
+	batch := *current
+	*current = *newSegmentBatch(x.translatorOptions)
+
+	recordBatchSize(x.instanceName, len(batch.ops))
+	// the segments are all received by this receiver, the context of the
+	// first one is used
+	err := x.consumer.ConsumeTraces(batch.ops[0].ctx, batch.traces.Traces())
+	if err != nil {
+		x.logger.Warn("Trace consumer errored out", zap.Error(err))
+	}
+	for _, op := range batch.ops {
+		obsreport.EndTraceDataReceiveOp(op.ctx, awsxray.TypeStr, op.count, err)
+	}
+}
@@ -15,6 +15,9 @@
 package awsxrayreceiver
 
 import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 
@@ -31,4 +34,53 @@ type Config struct {
 
 	// ProxyServer defines configurations related to the local TCP proxy server.
 	ProxyServer *proxy.Config `mapstructure:"proxy_server"`
+
+	// HTTPServer defines the HTTP endpoint accepting JSON arrays of segment
+	// documents, for the environments where UDP is not available. Segments
+	// are only received over UDP if not set.
+	HTTPServer *confighttp.HTTPServerSettings `mapstructure:"http_server"`
+
+	// InProgressWindow is the duration the in-progress segments are held
+	// before being sent downstream, an in-progress segment being dropped if
+	// its completed version is received within the window. The segments are
+	// not held if not set.
+	InProgressWindow time.Duration `mapstructure:"in_progress_window"`
+
+	// BatchWindow is the duration the converted segments are grouped for
+	// before being sent downstream at once, the segments of the same
+	// resource sharing their ResourceSpans. Each segment is sent on its own
+	// if not set.
+	BatchWindow time.Duration `mapstructure:"batch_window"`
+
+	// PreserveUnknownFields adds the fields of the segments which are not
+	// part of the X-Ray segment document schema, such as vendor extensions,
+	// as a JSON object to the aws.xray.raw attribute of their spans instead
+	// of dropping them.
+	PreserveUnknownFields bool `mapstructure:"preserve_unknown_fields"`
+
+	// AttributeLimits limits the attributes of the converted spans.
+	AttributeLimits AttributeLimitsConfig `mapstructure:"attribute_limits"`
+
+	// NumWorkers is the number of goroutines converting the received
+	// segments to spans, 1 if not set.
+	NumWorkers int `mapstructure:"num_workers"`
+
+	// ReadBufferSize is the size in bytes of the receive buffer of the UDP
+	// socket, the SO_RCVBUF socket option. The system default is used if
+	// not set.
+	ReadBufferSize int `mapstructure:"read_buffer_size"`
+}
+
+// AttributeLimitsConfig defines the limits of the attributes of the spans and
+// of their events, unlimited if zero.
+type AttributeLimitsConfig struct {
+	// MaxCount is the maximum number of attributes of a span or an event,
+	// the attributes beyond it being dropped and counted in the dropped
+	// attributes count of the span or event.
+	MaxCount int `mapstructure:"max_count"`
+
+	// MaxValueLength is the maximum length in bytes of the string values of
+	// the attributes, the longer values being truncated. The metadata and
+	// the unknown fields are dropped instead.
+	MaxValueLength int `mapstructure:"max_value_length"`
 }
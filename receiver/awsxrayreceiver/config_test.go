@@ -17,10 +17,12 @@ package awsxrayreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
@@ -43,7 +45,7 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
 
-	assert.Equal(t, len(cfg.Receivers), 3)
+	assert.Equal(t, len(cfg.Receivers), 5)
 
 	// ensure default configurations are generated when users provide
 	// nothing.
@@ -106,4 +108,17 @@ func TestLoadConfig(t *testing.T) {
 			},
 		},
 		r2)
+
+	// ensure the in-progress segments window is properly overwritten
+	r3 := cfg.Receivers[awsxray.TypeStr+"/in_progress_window"].(*Config)
+	assert.Equal(t, 5*time.Second, r3.InProgressWindow)
+	assert.Equal(t, 200*time.Millisecond, r3.BatchWindow)
+	assert.True(t, r3.PreserveUnknownFields)
+	assert.Equal(t, AttributeLimitsConfig{MaxCount: 64, MaxValueLength: 4096}, r3.AttributeLimits)
+	assert.Equal(t, 4, r3.NumWorkers)
+	assert.Equal(t, 4194304, r3.ReadBufferSize)
+
+	// ensure the HTTP endpoint is properly overwritten
+	r4 := cfg.Receivers[awsxray.TypeStr+"/http_server"].(*Config)
+	assert.Equal(t, &confighttp.HTTPServerSettings{Endpoint: "0.0.0.0:2001"}, r4.HTTPServer)
 }
@@ -7,6 +7,7 @@ require (
 	github.com/google/uuid v1.1.2
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray v0.0.0-00010101000000-000000000000
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
 	go.uber.org/zap v1.16.0
 )
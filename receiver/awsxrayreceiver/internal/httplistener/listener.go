@@ -0,0 +1,177 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httplistener provides an HTTP server accepting X-Ray segment
+// documents, for the environments where the segments can't be sent over UDP.
+package httplistener
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+const (
+	// Transport is the network transport protocol used
+	// by the listener
+	Transport = "http"
+
+	// maximum size of a request body, the X-Ray PutTraceSegments API
+	// accepts up to 50 documents of 64KB.
+	maxRequestBodySize = 5 * 1024 * 1024
+
+	// the size of the channel between the HTTP listener
+	// and OT consumer
+	segChanSize = 30
+)
+
+// Listener represents an HTTP server accepting the segment documents
+type Listener interface {
+	SegmentsChan() <-chan udppoller.RawSegment
+	Start(receiverLongLivedCtx context.Context) error
+	Close() error
+}
+
+// Config represents the configurations needed to
+// start the HTTP listener
+type Config struct {
+	ReceiverInstanceName string
+	confighttp.HTTPServerSettings
+}
+
+type listener struct {
+	receiverInstanceName string
+	settings             confighttp.HTTPServerSettings
+	logger               *zap.Logger
+
+	receiverLongLivedCtx context.Context
+	server               *http.Server
+	segChan              chan udppoller.RawSegment
+	closeOnce            sync.Once
+}
+
+// New creates a new HTTP listener, the endpoint being only listened on
+// once the listener is started.
+func New(cfg *Config, logger *zap.Logger) (Listener, error) {
+	if _, err := net.ResolveTCPAddr("tcp", cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid HTTP endpoint %q: %w", cfg.Endpoint, err)
+	}
+	return &listener{
+		receiverInstanceName: cfg.ReceiverInstanceName,
+		settings:             cfg.HTTPServerSettings,
+		logger:               logger,
+		segChan:              make(chan udppoller.RawSegment, segChanSize),
+	}, nil
+}
+
+func (l *listener) SegmentsChan() <-chan udppoller.RawSegment {
+	return l.segChan
+}
+
+func (l *listener) Start(receiverLongLivedCtx context.Context) error {
+	ln, err := l.settings.ToListener()
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q for X-Ray segments: %w", l.settings.Endpoint, err)
+	}
+	l.receiverLongLivedCtx = obsreport.ReceiverContext(receiverLongLivedCtx, l.receiverInstanceName, Transport, "")
+	l.server = l.settings.ToServer(l)
+	go func() {
+		if err := l.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			l.logger.Error("X-Ray HTTP listener failed", zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Close stops the server, waiting for the requests being handled and then
+// closes the segments channel.
+func (l *listener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		if l.server != nil {
+			err = l.server.Shutdown(context.Background())
+		}
+		close(l.segChan)
+	})
+	return err
+}
+
+// ServeHTTP accepts a JSON array of segment documents, each being either a
+// document serialized as a string, as in the PutTraceSegments API, or a
+// document object.
+func (l *listener) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST requests are accepted", http.StatusMethodNotAllowed)
+		return
+	}
+
+	documents, err := splitDocuments(http.MaxBytesReader(w, req.Body, maxRequestBodySize))
+	if err != nil {
+		l.logger.Debug("Invalid X-Ray segments request", zap.Error(err))
+		// The rejected request is reported as a single refused segment, its
+		// documents being unknown, as for the invalid UDP packets.
+		ctx := obsreport.StartTraceDataReceiveOp(
+			l.receiverLongLivedCtx,
+			l.receiverInstanceName,
+			Transport,
+			obsreport.WithLongLivedCtx())
+		obsreport.EndTraceDataReceiveOp(ctx, awsxray.TypeStr, 1, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, document := range documents {
+		ctx := obsreport.StartTraceDataReceiveOp(
+			l.receiverLongLivedCtx,
+			l.receiverInstanceName,
+			Transport,
+			obsreport.WithLongLivedCtx())
+		l.segChan <- udppoller.RawSegment{
+			Payload: document,
+			Ctx:     ctx,
+		}
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// splitDocuments returns the segment documents of a JSON array, the invalid
+// documents being reported by the translator. The array is decoded as it is
+// read, only the documents being held in memory, and all of them are decoded
+// before being queued so that a request is either accepted or rejected as a
+// whole.
+func splitDocuments(body io.Reader) ([][]byte, error) {
+	decoder := awsxray.NewDocumentsDecoder(body)
+	documents := make([][]byte, 0)
+	for {
+		document, err := decoder.Next()
+		if err == io.EOF {
+			return documents, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("the request body must be a JSON array of segment documents: %w", err)
+		}
+		documents = append(documents, document)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httplistener
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/obsreport/obsreporttest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "strings",
+			body: `["{\"id\":\"a\"}", "{\"id\":\"b\"}"]`,
+			want: []string{`{"id":"a"}`, `{"id":"b"}`},
+		},
+		{
+			name: "objects",
+			body: `[{"id":"a"}, {"id": "b"}]`,
+			want: []string{`{"id":"a"}`, `{"id": "b"}`},
+		},
+		{
+			name: "empty",
+			body: `[]`,
+			want: []string{},
+		},
+		{
+			name:    "not an array",
+			body:    `{"id":"a"}`,
+			wantErr: true,
+		},
+		{
+			name:    "number",
+			body:    `[42]`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid document",
+			body:    `[{"id":"a"}, {"id":]`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing data",
+			body:    `[{"id":"a"}] {}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			documents, err := splitDocuments(strings.NewReader(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			got := make([]string, 0, len(documents))
+			for _, document := range documents {
+				got = append(got, string(document))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	require.NoError(t, err)
+	defer doneFn()
+
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	l, err := New(&Config{
+		ReceiverInstanceName: "TestServeHTTP",
+		HTTPServerSettings:   confighttp.HTTPServerSettings{Endpoint: endpoint},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, l.Start(context.Background()))
+	defer l.Close()
+
+	url := "http://" + endpoint + "/"
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(`["{\"id\":\"a\"}", {"id":"b"}]`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	for _, want := range []string{`{"id":"a"}`, `{"id":"b"}`} {
+		seg := <-l.SegmentsChan()
+		assert.Equal(t, want, string(seg.Payload))
+		assert.NotNil(t, seg.Ctx)
+		obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, 1, nil)
+	}
+
+	resp, err = http.Post(url, "application/json", bytes.NewBufferString(`invalid`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	obsreporttest.CheckReceiverTracesViews(t, "TestServeHTTP", Transport, 2, 1)
+
+	resp, err = http.Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	_, err := New(&Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "invalidAddr"},
+	}, zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestStartEndpointInUse(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	l, err := New(&Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: ln.Addr().String()},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Error(t, l.Start(context.Background()))
+}
+
+func TestCloseClosesSegmentsChan(t *testing.T) {
+	l, err := New(&Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{Endpoint: "localhost:0"},
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	assert.NoError(t, l.Close())
+	_, ok := <-l.SegmentsChan()
+	assert.False(t, ok, "the segments channel should be closed")
+	assert.NoError(t, l.Close(), "closing twice should not fail")
+}
@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package segmentbuffer holds the in-progress X-Ray segments for a short
+// window so that they can be replaced by their completed version.
+package segmentbuffer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+// segmentKey identifies a segment or subsegment across its versions.
+type segmentKey struct {
+	traceID string
+	id      string
+}
+
+// segmentState holds the fields of a segment document used to buffer it.
+type segmentState struct {
+	TraceID    string `json:"trace_id"`
+	ID         string `json:"id"`
+	InProgress bool   `json:"in_progress"`
+}
+
+type entry struct {
+	segment  udppoller.RawSegment
+	deadline time.Time
+}
+
+// Buffer holds the in-progress segments until their completed version is
+// received or until the window elapsed. A Buffer is not safe for concurrent
+// use.
+type Buffer struct {
+	window  time.Duration
+	pending map[segmentKey]*entry
+	// order holds the keys of the pending segments in the order they were
+	// first received, so that they are released in that order.
+	order []segmentKey
+}
+
+// New creates a Buffer holding the in-progress segments for the window.
+func New(window time.Duration) *Buffer {
+	return &Buffer{
+		window:  window,
+		pending: make(map[segmentKey]*entry),
+	}
+}
+
+// Add returns the segments to process right away: nothing for an in-progress
+// segment, which is held, and the segment itself otherwise. The in-progress
+// version of a completed segment is dropped and returned as replaced, the
+// operation started for it by the poller being left to the caller. A newer
+// in-progress version replaces the held one, keeping its deadline.
+func (b *Buffer) Add(seg udppoller.RawSegment, now time.Time) (ready []udppoller.RawSegment, replaced []udppoller.RawSegment) {
+	var state segmentState
+	// The invalid documents are processed right away, the translator reporting
+	// the error.
+	if err := json.Unmarshal(seg.Payload, &state); err != nil || state.TraceID == "" || state.ID == "" {
+		return []udppoller.RawSegment{seg}, nil
+	}
+
+	key := segmentKey{traceID: state.TraceID, id: state.ID}
+	held, ok := b.pending[key]
+	if state.InProgress {
+		if ok {
+			replaced = append(replaced, held.segment)
+			held.segment = seg
+			return nil, replaced
+		}
+		b.pending[key] = &entry{segment: seg, deadline: now.Add(b.window)}
+		b.order = append(b.order, key)
+		return nil, nil
+	}
+
+	if ok {
+		replaced = append(replaced, held.segment)
+		b.remove(key)
+	}
+	return []udppoller.RawSegment{seg}, replaced
+}
+
+// Expired removes and returns the in-progress segments whose window elapsed,
+// in the order they were first received.
+func (b *Buffer) Expired(now time.Time) []udppoller.RawSegment {
+	var expired []udppoller.RawSegment
+	for len(b.order) > 0 {
+		held := b.pending[b.order[0]]
+		if now.Before(held.deadline) {
+			break
+		}
+		expired = append(expired, held.segment)
+		delete(b.pending, b.order[0])
+		b.order = b.order[1:]
+	}
+	return expired
+}
+
+// Flush removes and returns all the in-progress segments held, in the order
+// they were first received.
+func (b *Buffer) Flush() []udppoller.RawSegment {
+	flushed := make([]udppoller.RawSegment, 0, len(b.order))
+	for _, key := range b.order {
+		flushed = append(flushed, b.pending[key].segment)
+	}
+	b.pending = make(map[segmentKey]*entry)
+	b.order = nil
+	return flushed
+}
+
+// Len returns the number of in-progress segments held.
+func (b *Buffer) Len() int {
+	return len(b.pending)
+}
+
+func (b *Buffer) remove(key segmentKey) {
+	delete(b.pending, key)
+	for i, k := range b.order {
+		if k == key {
+			b.order = append(b.order[:i], b.order[i+1:]...)
+			return
+		}
+	}
+}
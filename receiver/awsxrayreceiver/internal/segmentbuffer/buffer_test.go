@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package segmentbuffer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+const traceID = "1-5f5f4b8a-0123456789abcdef01234567"
+
+func segment(id string, inProgress bool) udppoller.RawSegment {
+	return udppoller.RawSegment{
+		Ctx: context.Background(),
+		Payload: []byte(fmt.Sprintf(`{"trace_id":%q,"id":%q,"name":"test","start_time":1,"in_progress":%t}`,
+			traceID, id, inProgress)),
+	}
+}
+
+func TestCompletedSegmentReadyRightAway(t *testing.T) {
+	b := New(time.Second)
+	seg := segment("a", false)
+
+	ready, replaced := b.Add(seg, time.Now())
+	assert.Equal(t, []udppoller.RawSegment{seg}, ready)
+	assert.Empty(t, replaced)
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestInvalidSegmentReadyRightAway(t *testing.T) {
+	b := New(time.Second)
+	for _, payload := range []string{"invalid", `{"id":"a","in_progress":true}`} {
+		seg := udppoller.RawSegment{Ctx: context.Background(), Payload: []byte(payload)}
+		ready, replaced := b.Add(seg, time.Now())
+		assert.Equal(t, []udppoller.RawSegment{seg}, ready)
+		assert.Empty(t, replaced)
+	}
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestInProgressSegmentReplaced(t *testing.T) {
+	b := New(time.Second)
+	now := time.Now()
+	inProgress := segment("a", true)
+	completed := segment("a", false)
+
+	ready, replaced := b.Add(inProgress, now)
+	assert.Empty(t, ready)
+	assert.Empty(t, replaced)
+	assert.Equal(t, 1, b.Len())
+
+	ready, replaced = b.Add(completed, now.Add(time.Millisecond))
+	assert.Equal(t, []udppoller.RawSegment{completed}, ready)
+	assert.Equal(t, []udppoller.RawSegment{inProgress}, replaced)
+	assert.Equal(t, 0, b.Len())
+	assert.Empty(t, b.Expired(now.Add(time.Hour)))
+}
+
+func TestNewerInProgressSegmentKeepsDeadline(t *testing.T) {
+	b := New(time.Second)
+	now := time.Now()
+	first := segment("a", true)
+	second := segment("a", true)
+	second.Payload = append(second.Payload, ' ')
+
+	b.Add(first, now)
+	ready, replaced := b.Add(second, now.Add(500*time.Millisecond))
+	assert.Empty(t, ready)
+	assert.Equal(t, []udppoller.RawSegment{first}, replaced)
+
+	assert.Empty(t, b.Expired(now.Add(999*time.Millisecond)))
+	assert.Equal(t, []udppoller.RawSegment{second}, b.Expired(now.Add(time.Second)))
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestExpiredInOrder(t *testing.T) {
+	b := New(time.Second)
+	now := time.Now()
+	a, c, d := segment("a", true), segment("c", true), segment("d", true)
+
+	b.Add(a, now)
+	b.Add(c, now.Add(100*time.Millisecond))
+	b.Add(d, now.Add(200*time.Millisecond))
+	b.Add(segment("c", false), now.Add(300*time.Millisecond))
+
+	assert.Equal(t, []udppoller.RawSegment{a}, b.Expired(now.Add(time.Second)))
+	assert.Equal(t, []udppoller.RawSegment{d}, b.Expired(now.Add(2*time.Second)))
+	assert.Equal(t, 0, b.Len())
+}
+
+func TestFlush(t *testing.T) {
+	b := New(time.Second)
+	now := time.Now()
+	a, c := segment("a", true), segment("c", true)
+
+	b.Add(a, now)
+	b.Add(c, now)
+
+	assert.Equal(t, []udppoller.RawSegment{a, c}, b.Flush())
+	assert.Equal(t, 0, b.Len())
+	assert.Empty(t, b.Flush())
+}
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// Batch converts several X-Ray segments (and their subsegments) to OT
+// traces, the segments of the same resource sharing their ResourceSpans.
+// A Batch is not safe for concurrent use.
+type Batch struct {
+	traces pdata.Traces
+	// spans holds the spans of each resource, by resource identity
+	spans         map[string]pdata.SpanSlice
+	segmentsCount int
+	opts          Options
+}
+
+// NewBatch creates an empty Batch converting the segments with the options.
+func NewBatch(opts Options) *Batch {
+	return &Batch{
+		traces: pdata.NewTraces(),
+		spans:  make(map[string]pdata.SpanSlice),
+		opts:   opts,
+	}
+}
+
+// Add converts the segment and adds its spans to the ResourceSpans of its
+// resource, returning the total count of the segment and its subsegments.
+// The batch is left unchanged if the segment can't be converted.
+func (b *Batch) Add(rawSeg []byte) (int, error) {
+	seg, raw, count, err := parseSegment(rawSeg, b.opts)
+	if err != nil {
+		return count, err
+	}
+	defer awsxray.ReleaseSegment(seg)
+
+	converted := pdata.NewSpanSlice()
+	converted.Resize(count)
+	_, _, err = segToSpans(seg, raw, b.opts, seg.TraceID, nil, &converted, 0)
+	if err != nil {
+		return count, &ConversionError{Reason: ReasonTranslationError, Err: err}
+	}
+
+	resource := pdata.NewResource()
+	resource.InitEmpty()
+	populateResource(seg, &resource)
+	key := resourceKey(resource.Attributes())
+
+	spans, ok := b.spans[key]
+	if !ok {
+		rspanSlice := b.traces.ResourceSpans()
+		rspanSlice.Resize(rspanSlice.Len() + 1)
+		rspan := rspanSlice.At(rspanSlice.Len() - 1)
+		resource.CopyTo(rspan.Resource())
+		rspan.InstrumentationLibrarySpans().Resize(1)
+		spans = rspan.InstrumentationLibrarySpans().At(0).Spans()
+		b.spans[key] = spans
+	}
+	converted.MoveAndAppendTo(spans)
+	b.segmentsCount += count
+	return count, nil
+}
+
+// Traces returns the traces holding the spans of all the segments added.
+func (b *Batch) Traces() pdata.Traces {
+	return b.traces
+}
+
+// SegmentsCount returns the total count of the segments and subsegments
+// added.
+func (b *Batch) SegmentsCount() int {
+	return b.segmentsCount
+}
+
+// resourceKey returns the identity of a resource, made of its sorted
+// attributes.
+func resourceKey(attrs pdata.AttributeMap) string {
+	var key strings.Builder
+	attrs.Sort().ForEach(func(k string, v pdata.AttributeValue) {
+		key.WriteString(strconv.Quote(k))
+		key.WriteByte('=')
+		switch v.Type() {
+		case pdata.AttributeValueSTRING:
+			key.WriteString(strconv.Quote(v.StringVal()))
+		case pdata.AttributeValueINT:
+			key.WriteString(strconv.FormatInt(v.IntVal(), 10))
+		case pdata.AttributeValueDOUBLE:
+			key.WriteString(strconv.FormatFloat(v.DoubleVal(), 'g', -1, 64))
+		case pdata.AttributeValueBOOL:
+			key.WriteString(strconv.FormatBool(v.BoolVal()))
+		}
+		key.WriteByte(',')
+	})
+	return key.String()
+}
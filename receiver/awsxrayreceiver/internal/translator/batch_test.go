@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSample(t *testing.T, name string) []byte {
+	content, err := ioutil.ReadFile(path.Join("../../../../internal/awsxray", "testdata", name))
+	require.NoError(t, err, "can not read raw segment")
+	return content
+}
+
+func TestBatchSharesResourceSpans(t *testing.T) {
+	batch := NewBatch(Options{})
+	server := readSample(t, "serverSample.txt")
+	ddb := readSample(t, "ddbSample.txt")
+
+	count, err := batch.Add(server)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	_, err = batch.Add(server)
+	require.NoError(t, err)
+	ddbCount, err := batch.Add(ddb)
+	require.NoError(t, err)
+
+	traces := batch.Traces()
+	// the server segments share the same resource, the DynamoDB segment
+	// comes from another application
+	assert.Equal(t, 2, traces.ResourceSpans().Len())
+	assert.Equal(t, 2, traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().Len())
+	assert.Equal(t, ddbCount, traces.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().Len())
+	assert.Equal(t, 2+ddbCount, batch.SegmentsCount())
+	assert.Equal(t, 2+ddbCount, traces.SpanCount())
+
+	// the batch holds the same spans as the segments converted one by one
+	single, _, err := ToTraces(ddb, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, single.ResourceSpans().At(0).Resource().Attributes().Sort(),
+		traces.ResourceSpans().At(1).Resource().Attributes().Sort())
+	assert.Equal(t, single.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name(),
+		traces.ResourceSpans().At(1).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+}
+
+func TestBatchInvalidSegment(t *testing.T) {
+	batch := NewBatch(Options{})
+	_, err := batch.Add(readSample(t, "serverSample.txt"))
+	require.NoError(t, err)
+
+	count, err := batch.Add([]byte("invalid"))
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = batch.Add(readSample(t, "segmentValidationFailed.txt"))
+	assert.Error(t, err)
+
+	assert.Equal(t, 1, batch.Traces().ResourceSpans().Len())
+	assert.Equal(t, 1, batch.SegmentsCount())
+	assert.Equal(t, 1, batch.Traces().SpanCount())
+}
@@ -29,20 +29,6 @@ func addCause(seg *awsxray.Segment, span *pdata.Span) {
 		return
 	}
 
-	// In the `rawExpectedSegmentForInstrumentedApp` X-Ray segment example in
-	// awsxray/tracesegment_test.go, you can see that sometimes the HTTP Response is not
-	// set but the Cause field is set for the root segment. And the actual HTTP status
-	// can only be found in one of the (nested) subsegmets. So we need to signal that
-	// in this case, the status of the span is not otlptrace.Status_Ok by
-	// temporarily setting the status to otlptrace.Status_UnknownError. This will be
-	// updated to a more specific error in the `segToSpans()` in translator.go once
-	// we traverse through all the subsegments.
-	if span.Status().Code() == pdata.StatusCodeOk {
-		// otlptrace.Status_Ok is the default value after span.Status().InitEmpty()
-		// is called
-		span.Status().SetCode(pdata.StatusCodeUnknownError)
-	}
-
 	switch seg.Cause.Type {
 	case awsxray.CauseTypeExceptionID:
 		// Right now the X-Ray exporter does not support the case where
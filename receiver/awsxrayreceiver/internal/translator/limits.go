@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// AttributeLimits limits the attributes of the spans and of their events,
+// protecting the memory of the collector from the segments holding many
+// annotations or large metadata. A zero limit is no limit.
+type AttributeLimits struct {
+	// MaxCount is the maximum number of attributes of a span or an event,
+	// the attributes beyond it being dropped. The annotations and the
+	// metadata being converted last, they are dropped first.
+	MaxCount int
+	// MaxValueLength is the maximum length in bytes of the string values,
+	// the longer values being truncated. The metadata and the unknown
+	// fields, JSON objects which would be corrupted by a truncation, are
+	// dropped instead.
+	MaxValueLength int
+}
+
+func (l AttributeLimits) enabled() bool {
+	return l.MaxCount > 0 || l.MaxValueLength > 0
+}
+
+// apply drops and truncates the attributes beyond the limits, returning the
+// number of attributes dropped.
+func (l AttributeLimits) apply(attrs pdata.AttributeMap) uint32 {
+	var dropped []string
+	kept := 0
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		if l.MaxValueLength > 0 && v.Type() == pdata.AttributeValueSTRING && len(v.StringVal()) > l.MaxValueLength {
+			if isJSONAttribute(k) {
+				dropped = append(dropped, k)
+				return
+			}
+			v.SetStringVal(truncateString(v.StringVal(), l.MaxValueLength))
+		}
+		if l.MaxCount > 0 && kept >= l.MaxCount {
+			dropped = append(dropped, k)
+			return
+		}
+		kept++
+	})
+	for _, k := range dropped {
+		attrs.Delete(k)
+	}
+	return uint32(len(dropped))
+}
+
+// applyToSpan applies the limits to the attributes of the span and of its
+// events, adding the attributes dropped to their dropped attributes count.
+func (l AttributeLimits) applyToSpan(span *pdata.Span) {
+	if !l.enabled() {
+		return
+	}
+	span.SetDroppedAttributesCount(span.DroppedAttributesCount() + l.apply(span.Attributes()))
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		event.SetDroppedAttributesCount(event.DroppedAttributesCount() + l.apply(event.Attributes()))
+	}
+}
+
+func isJSONAttribute(key string) bool {
+	return key == awsxray.AWSXRayRawAttribute || strings.HasPrefix(key, awsxray.AWSXraySegmentMetadataAttributePrefix)
+}
+
+// truncateString truncates s to at most n bytes without splitting a UTF-8
+// encoded character.
+func truncateString(s string, n int) string {
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
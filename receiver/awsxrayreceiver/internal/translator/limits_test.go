@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+func TestAttributeLimits(t *testing.T) {
+	attrs := pdata.NewAttributeMap()
+	attrs.InitEmptyWithCapacity(6)
+	attrs.InsertString("a", "short")
+	attrs.InsertString("b", "exceeding")
+	attrs.InsertInt("c", 123456789)
+	attrs.InsertString(awsxray.AWSXraySegmentMetadataAttributePrefix+"default", `{"key":"value"}`)
+	attrs.InsertString("d", "é€a")
+	attrs.InsertBool("e", true)
+
+	dropped := AttributeLimits{MaxCount: 4, MaxValueLength: 5}.apply(attrs)
+	// the metadata is dropped rather than truncated, the attributes beyond
+	// the count once it is dropped are dropped too
+	assert.Equal(t, uint32(2), dropped)
+	assert.Equal(t, 4, attrs.Len())
+	expected := map[string]pdata.AttributeValue{
+		"a": pdata.NewAttributeValueString("short"),
+		"b": pdata.NewAttributeValueString("excee"),
+		"c": pdata.NewAttributeValueInt(123456789),
+		// é and € are 2 and 3 bytes long
+		"d": pdata.NewAttributeValueString("é€"),
+	}
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(expected).Sort(), attrs.Sort())
+}
+
+func TestAttributeLimitsZeroIsUnlimited(t *testing.T) {
+	assert.False(t, AttributeLimits{}.enabled())
+
+	attrs := pdata.NewAttributeMap()
+	attrs.InitEmptyWithCapacity(2)
+	attrs.InsertString("a", strings.Repeat("a", 1024))
+	attrs.InsertString("b", "b")
+	assert.Equal(t, uint32(1), AttributeLimits{MaxCount: 1}.apply(attrs))
+	assert.Equal(t, 1, attrs.Len())
+	v, _ := attrs.Get("a")
+	assert.Len(t, v.StringVal(), 1024)
+}
+
+func TestAttributeLimitsOfSegments(t *testing.T) {
+	limits := AttributeLimits{MaxCount: 3, MaxValueLength: 16}
+	traces, _, err := ToTraces([]byte(segmentWithUnknownFields), Options{PreserveUnknownFields: true, AttributeLimits: limits})
+	require.NoError(t, err)
+	spans := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+
+	segment := spans.At(0)
+	assert.LessOrEqual(t, segment.Attributes().Len(), 3)
+	assert.NotZero(t, segment.DroppedAttributesCount())
+	_, ok := segment.Attributes().Get(awsxray.AWSXRayRawAttribute)
+	assert.False(t, ok, "the unknown fields are dropped first")
+	segment.Attributes().ForEach(func(k string, v pdata.AttributeValue) {
+		if v.Type() == pdata.AttributeValueSTRING {
+			assert.LessOrEqual(t, len(v.StringVal()), 16, k)
+		}
+	})
+
+	// the two exceptions of the segment, with their ID, message and type
+	require.Equal(t, 2, segment.Events().Len())
+	event := segment.Events().At(1)
+	assert.Equal(t, uint32(0), event.DroppedAttributesCount())
+	message, ok := event.Attributes().Get(conventions.AttributeExceptionMessage)
+	require.True(t, ok)
+	assert.Equal(t, "timeout", message.StringVal())
+
+	// the subsegment without unknown fields is within the limits
+	assert.Equal(t, uint32(0), spans.At(2).DroppedAttributesCount())
+
+	batch := NewBatch(Options{AttributeLimits: limits})
+	_, err = batch.Add([]byte(segmentWithUnknownFields))
+	require.NoError(t, err)
+	batchSpans := batch.Traces().ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	assert.NotZero(t, batchSpans.At(0).DroppedAttributesCount())
+}
@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+var segmentType = reflect.TypeOf(awsxray.Segment{})
+
+// jsonFieldsCache holds the JSON fields of the types of the segment fields,
+// by type.
+var jsonFieldsCache sync.Map
+
+// parseRawSegment decodes the segment document as generic JSON values, the
+// numbers being kept as written.
+func parseRawSegment(rawSeg []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(rawSeg))
+	decoder.UseNumber()
+	var raw interface{}
+	err := decoder.Decode(&raw)
+	return raw, err
+}
+
+// rawSubsegment returns the i-th embedded subsegment of the decoded segment,
+// or nil if the segment was not decoded.
+func rawSubsegment(raw interface{}, i int) interface{} {
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for key, value := range obj {
+		if strings.EqualFold(key, "subsegments") {
+			if subsegments, ok := value.([]interface{}); ok && i < len(subsegments) {
+				return subsegments[i]
+			}
+		}
+	}
+	return nil
+}
+
+// addRawFields adds the fields of the decoded segment which are not part of
+// awsxray.Segment, such as vendor extensions, as a JSON object to the
+// AWSXRayRawAttribute. The fields of the embedded subsegments are added to
+// their own spans.
+func addRawFields(raw interface{}, attrs *pdata.AttributeMap) error {
+	unknown := unknownFields(raw, segmentType)
+	if unknown == nil {
+		return nil
+	}
+	b, err := json.Marshal(unknown)
+	if err != nil {
+		return err
+	}
+	attrs.UpsertString(awsxray.AWSXRayRawAttribute, string(b))
+	return nil
+}
+
+// unknownFields returns the parts of the JSON value which are not
+// unmarshalled into the type, at the same path, or nil if there are none.
+// The maps and the interface{} values hold any JSON value, such as the
+// annotations and the metadata.
+func unknownFields(raw interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		unknown := make(map[string]interface{})
+		for key, value := range obj {
+			// as encoding/json, match the field names case-insensitively
+			name := strings.ToLower(key)
+			if t == segmentType && name == "subsegments" {
+				continue
+			}
+			fieldType, ok := fields[name]
+			if !ok {
+				unknown[key] = value
+				continue
+			}
+			if u := unknownFields(value, fieldType); u != nil {
+				unknown[key] = u
+			}
+		}
+		if len(unknown) == 0 {
+			return nil
+		}
+		return unknown
+	case reflect.Slice:
+		arr, ok := raw.([]interface{})
+		if !ok {
+			return nil
+		}
+		// the elements without unknown fields are kept as null to
+		// preserve the position of the others
+		var unknown []interface{}
+		for i, value := range arr {
+			if u := unknownFields(value, t.Elem()); u != nil {
+				if unknown == nil {
+					unknown = make([]interface{}, len(arr))
+				}
+				unknown[i] = u
+			}
+		}
+		if unknown == nil {
+			return nil
+		}
+		return unknown
+	}
+	return nil
+}
+
+// jsonFields returns the types of the struct fields by lower-cased JSON name,
+// the fields of the embedded structs being promoted as by encoding/json.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+	fields := make(map[string]reflect.Type)
+	addJSONFields(t, fields)
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+func addJSONFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			addJSONFields(field.Type, fields)
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Type
+	}
+}
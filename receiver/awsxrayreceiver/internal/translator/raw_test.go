@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+const segmentWithUnknownFields = `{
+	"trace_id": "1-5f187253-6a106696d56b1f4ef9eba2ed",
+	"id": "5cc4a447f5d4d696",
+	"name": "checkout",
+	"start_time": 1595437651.680097,
+	"end_time": 1595437652.197392,
+	"vendor": {"region": "eu-west-1", "shard": 9007199254740993},
+	"http": {
+		"request": {"method": "GET", "x_request_id": "b1d2"},
+		"response": {"status": 500}
+	},
+	"cause": {
+		"working_directory": "/app",
+		"exceptions": [
+			{"id": "e1", "message": "boom"},
+			{"id": "e2", "message": "timeout", "retryable": true}
+		]
+	},
+	"annotations": {"tenant": "acme"},
+	"metadata": {"default": {"anything": {"goes": 1}}},
+	"subsegments": [
+		{
+			"id": "7df694142c905d8d",
+			"name": "payments",
+			"start_time": 1595437651.680097,
+			"end_time": 1595437652.197392,
+			"Namespace": "remote",
+			"queue": "payments-high"
+		},
+		{
+			"id": "7df694142c905d8e",
+			"name": "inventory",
+			"start_time": 1595437651.680097,
+			"end_time": 1595437652.197392
+		}
+	]
+}`
+
+func TestPreserveUnknownFields(t *testing.T) {
+	traces, count, err := ToTraces([]byte(segmentWithUnknownFields), Options{PreserveUnknownFields: true})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	spans := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	require.Equal(t, 3, spans.Len())
+
+	raw, ok := spans.At(0).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	require.True(t, ok)
+	assert.JSONEq(t, `{
+		"vendor": {"region": "eu-west-1", "shard": 9007199254740993},
+		"http": {"request": {"x_request_id": "b1d2"}},
+		"cause": {"exceptions": [null, {"retryable": true}]}
+	}`, raw.StringVal())
+	// the numbers are kept as written
+	assert.Contains(t, raw.StringVal(), "9007199254740993")
+
+	// the fields are matched case-insensitively, as by encoding/json
+	raw, ok = spans.At(1).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"queue": "payments-high"}`, raw.StringVal())
+
+	_, ok = spans.At(2).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	assert.False(t, ok)
+}
+
+func TestPreserveUnknownFieldsBatch(t *testing.T) {
+	batch := NewBatch(Options{PreserveUnknownFields: true})
+	_, err := batch.Add([]byte(segmentWithUnknownFields))
+	require.NoError(t, err)
+	spans := batch.Traces().ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	_, ok := spans.At(0).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	assert.True(t, ok)
+}
+
+func TestUnknownFieldsDroppedByDefault(t *testing.T) {
+	traces, _, err := ToTraces([]byte(segmentWithUnknownFields), Options{})
+	require.NoError(t, err)
+	spans := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans()
+	for i := 0; i < spans.Len(); i++ {
+		_, ok := spans.At(i).Attributes().Get(awsxray.AWSXRayRawAttribute)
+		assert.False(t, ok)
+	}
+}
+
+func TestUnknownFieldsOfSDKSamples(t *testing.T) {
+	traces, _, err := ToTraces(readSample(t, "indepSubsegmentWithSql.txt"), Options{PreserveUnknownFields: true})
+	require.NoError(t, err)
+	_, ok := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	assert.False(t, ok, "the fields of the subsegment are all part of the schema")
+
+	// the SDKs send fields outside of the schema
+	traces, _, err = ToTraces(readSample(t, "serverSample.txt"), Options{PreserveUnknownFields: true})
+	require.NoError(t, err)
+	raw, ok := traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"Dummy": false}`, raw.StringVal())
+
+	traces, _, err = ToTraces(readSample(t, "ddbSample.txt"), Options{PreserveUnknownFields: true})
+	require.NoError(t, err)
+	raw, ok = traces.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(12).Attributes().Get(awsxray.AWSXRayRawAttribute)
+	require.True(t, ok)
+	assert.JSONEq(t, `{"Dummy": false, "aws": {"consumed_capacity": null, "item_collection_metrics": null}}`, raw.StringVal())
+}
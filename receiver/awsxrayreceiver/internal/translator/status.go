@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+// addStatus completes the status set from the HTTP response of the segment
+// by `addHTTP()`. The X-Ray SDKs do not always record the HTTP response of
+// the failed segments, for instance of the root segment of the
+// `rawExpectedSegmentForInstrumentedApp` example in
+// awsxray/tracesegment_test.go, so the status is then derived from the
+// error flags of the segment:
+// - throttle (429) maps to `StatusCodeResourceExhausted`,
+// - error (4xx, client error) maps to `StatusCodeInvalidArgument`,
+// - fault (5xx, server error) maps to `StatusCodeInternalError`,
+// and a segment with a cause but none of the flags maps to
+// `StatusCodeUnknownError`. This code is updated to the more specific code
+// of the first failed subsegment in the `segToSpans()` in translator.go.
+func addStatus(seg *awsxray.Segment, span *pdata.Span) {
+	status := span.Status()
+	if !hasHTTPStatus(seg) {
+		switch {
+		case seg.Throttle != nil && *seg.Throttle:
+			status.SetCode(pdata.StatusCodeResourceExhausted)
+		case seg.Error != nil && *seg.Error:
+			status.SetCode(pdata.StatusCodeInvalidArgument)
+		case seg.Fault != nil && *seg.Fault:
+			status.SetCode(pdata.StatusCodeInternalError)
+		case seg.Cause != nil:
+			status.SetCode(pdata.StatusCodeUnknownError)
+		}
+	}
+
+	if status.Message() == "" && status.Code() != pdata.StatusCodeOk {
+		status.SetMessage(causeMessage(seg.Cause))
+	}
+}
+
+// hasHTTPStatus returns whether the status of the span is set from the
+// HTTP response of the segment.
+func hasHTTPStatus(seg *awsxray.Segment) bool {
+	return seg.HTTP != nil && seg.HTTP.Response != nil && seg.HTTP.Response.Status != nil
+}
+
+// causeMessage returns the message of the first exception of the cause, or
+// its type if it has no message. The cause made of an exception ID is
+// already passed as the status message by `addCause()`.
+func causeMessage(cause *awsxray.CauseData) string {
+	if cause == nil || cause.Type != awsxray.CauseTypeObject {
+		return ""
+	}
+	for _, excp := range cause.Exceptions {
+		if excp.Message != nil && *excp.Message != "" {
+			return *excp.Message
+		}
+		if excp.Type != nil && *excp.Type != "" {
+			return *excp.Type
+		}
+	}
+	return ""
+}
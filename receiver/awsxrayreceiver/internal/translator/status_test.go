@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+)
+
+func TestAddStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		rawSeg      string
+		wantCode    pdata.StatusCode
+		wantMessage string
+	}{
+		{
+			name:     "no error",
+			rawSeg:   `{}`,
+			wantCode: pdata.StatusCodeOk,
+		},
+		{
+			name:        "http not found",
+			rawSeg:      `{"fault": true, "http": {"response": {"status": 404}}, "cause": {"exceptions": [{"id": "1", "message": "no such item"}]}}`,
+			wantCode:    pdata.StatusCodeNotFound,
+			wantMessage: "no such item",
+		},
+		{
+			name:        "throttle",
+			rawSeg:      `{"error": true, "throttle": true, "cause": {"exceptions": [{"id": "1", "type": "ThrottlingException"}]}}`,
+			wantCode:    pdata.StatusCodeResourceExhausted,
+			wantMessage: "ThrottlingException",
+		},
+		{
+			name:     "client error",
+			rawSeg:   `{"error": true}`,
+			wantCode: pdata.StatusCodeInvalidArgument,
+		},
+		{
+			name:        "server fault",
+			rawSeg:      `{"fault": true, "cause": {"exceptions": [{"id": "1", "type": "NullPointerException", "message": "value is null"}]}}`,
+			wantCode:    pdata.StatusCodeInternalError,
+			wantMessage: "value is null",
+		},
+		{
+			name:        "cause without flags",
+			rawSeg:      `{"cause": "abcdefghijklmnop"}`,
+			wantCode:    pdata.StatusCodeUnknownError,
+			wantMessage: "abcdefghijklmnop",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seg awsxray.Segment
+			require.NoError(t, json.Unmarshal([]byte(tt.rawSeg), &seg))
+
+			span := pdata.NewSpan()
+			span.InitEmpty()
+			span.Status().InitEmpty()
+			addHTTP(&seg, &span)
+			addCause(&seg, &span)
+			addStatus(&seg, &span)
+
+			assert.Equal(t, tt.wantCode, span.Status().Code())
+			assert.Equal(t, tt.wantMessage, span.Status().Message())
+		})
+	}
+}
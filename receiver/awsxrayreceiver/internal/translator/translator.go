@@ -15,12 +15,11 @@
 package translator
 
 import (
-	"encoding/json"
-
 	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	awsxraytranslator "github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray/translator"
 )
 
 const (
@@ -28,24 +27,52 @@ const (
 	initAttrCapacity = 15
 )
 
-// TODO: It might be nice to consolidate the `fromPdata` in x-ray exporter and
-// `toPdata` in this receiver to a common package later
+// The reasons of the rejection of the segments which cannot be converted.
+const (
+	// ReasonMalformedJSON is the reason of the segments which are not valid
+	// JSON documents.
+	ReasonMalformedJSON = "malformed_json"
+	// ReasonValidationFailure is the reason of the segments missing required
+	// fields.
+	ReasonValidationFailure = "validation_failure"
+	// ReasonTranslationError is the reason of the segments whose fields
+	// cannot be converted to spans.
+	ReasonTranslationError = "translation_error"
+)
 
-// ToTraces converts X-Ray segment (and its subsegments) to an OT ResourceSpans.
-func ToTraces(rawSeg []byte) (*pdata.Traces, int, error) {
-	var seg awsxray.Segment
-	err := json.Unmarshal(rawSeg, &seg)
-	if err != nil {
-		// return 1 as total segment (&subsegments) count
-		// because we can't parse the body the UDP packet.
-		return nil, 1, err
-	}
-	count := totalSegmentsCount(seg)
+// Options are the options of the conversion of the segments to spans.
+type Options struct {
+	// PreserveUnknownFields adds the fields of the segments which are not
+	// part of awsxray.Segment, such as vendor extensions, to the
+	// AWSXRayRawAttribute of their spans instead of dropping them.
+	PreserveUnknownFields bool
+	// AttributeLimits limits the attributes of the spans and of their
+	// events.
+	AttributeLimits AttributeLimits
+}
 
-	err = seg.Validate()
+// ConversionError is the error returned for the segments which cannot be
+// converted, along with the reason of their rejection.
+type ConversionError struct {
+	Reason string
+	Err    error
+}
+
+func (e *ConversionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// ToTraces converts X-Ray segment (and its subsegments) to an OT ResourceSpans.
+func ToTraces(rawSeg []byte, opts Options) (*pdata.Traces, int, error) {
+	seg, raw, count, err := parseSegment(rawSeg, opts)
 	if err != nil {
 		return nil, count, err
 	}
+	defer awsxray.ReleaseSegment(seg)
 
 	traceData := pdata.NewTraces()
 	rspanSlice := traceData.ResourceSpans()
@@ -66,54 +93,84 @@ func ToTraces(rawSeg []byte) (*pdata.Traces, int, error) {
 	spans := ils.Spans()
 
 	// populating global attributes shared among segment and embedded subsegment(s)
-	populateResource(&seg, &resource)
+	populateResource(seg, &resource)
 
 	// recursively traverse segment and embedded subsegments
 	// to populate the spans. We also need to pass in the
 	// TraceID of the root segment in because embedded subsegments
 	// do not have that information, but it's needed after we flatten
 	// the embedded subsegment to generate independent child spans.
-	_, _, err = segToSpans(seg, seg.TraceID, nil, &spans, 0)
+	_, _, err = segToSpans(seg, raw, opts, seg.TraceID, nil, &spans, 0)
 	if err != nil {
-		return nil, count, err
+		return nil, count, &ConversionError{Reason: ReasonTranslationError, Err: err}
 	}
 
 	return &traceData, count, nil
 }
 
-func segToSpans(seg awsxray.Segment,
+// parseSegment parses and validates the segment document, returning the
+// total count of the segment and its subsegments. The document is also
+// decoded as generic JSON values if the unknown fields are preserved. The
+// segment comes from the pool of awsxray.UnmarshalSegment and must be
+// released once converted, it is nil if an error is returned.
+func parseSegment(rawSeg []byte, opts Options) (*awsxray.Segment, interface{}, int, error) {
+	seg, err := awsxray.UnmarshalSegment(rawSeg)
+	if err != nil {
+		// return 1 as total segment (&subsegments) count
+		// because we can't parse the body the UDP packet.
+		return nil, nil, 1, &ConversionError{Reason: ReasonMalformedJSON, Err: err}
+	}
+	count := totalSegmentsCount(seg)
+
+	err = seg.Validate()
+	if err != nil {
+		awsxray.ReleaseSegment(seg)
+		return nil, nil, count, &ConversionError{Reason: ReasonValidationFailure, Err: err}
+	}
+
+	var raw interface{}
+	if opts.PreserveUnknownFields {
+		raw, err = parseRawSegment(rawSeg)
+		if err != nil {
+			awsxray.ReleaseSegment(seg)
+			return nil, nil, count, &ConversionError{Reason: ReasonMalformedJSON, Err: err}
+		}
+	}
+	return seg, raw, count, nil
+}
+
+func segToSpans(seg *awsxray.Segment, raw interface{}, opts Options,
 	traceID, parentID *string,
 	spans *pdata.SpanSlice, startingIndex int) (int, *pdata.Span, error) {
 
 	span := spans.At(startingIndex)
 
-	err := populateSpan(&seg, traceID, parentID, &span)
+	err := populateSpan(seg, raw, traceID, parentID, &span)
 	if err != nil {
 		return 0, nil, err
 	}
+	opts.AttributeLimits.applyToSpan(&span)
 
 	startingIndexForSubsegment := 1 + startingIndex
 	var populatedChildSpan *pdata.Span
-	for _, s := range seg.Subsegments {
-		startingIndexForSubsegment, populatedChildSpan, err = segToSpans(s,
+	// when the status of the segment does not come from its HTTP response, the
+	// actual HTTP status can only be found in one of the (nested)
+	// subsegments, see `addStatus()`.
+	inheritStatus := seg.Cause != nil && !hasHTTPStatus(seg)
+	for i := range seg.Subsegments {
+		startingIndexForSubsegment, populatedChildSpan, err = segToSpans(&seg.Subsegments[i], rawSubsegment(raw, i), opts,
 			traceID, seg.ID,
 			spans, startingIndexForSubsegment)
 		if err != nil {
 			return 0, nil, err
 		}
 
-		if seg.Cause != nil &&
+		if inheritStatus &&
 			populatedChildSpan.Status().Code() != pdata.StatusCodeOk {
-			// if seg.Cause is not nil, then one of the subsegments must contain a
-			// HTTP error code. Also, span.Status().Code() is already
-			// set to `StatusCodeUnknownError` by `addCause()` in
-			// `populateSpan()` above, so here we are just trying to figure out
-			// whether we can get an even more specific error code.
-
-			if span.Status().Code() == pdata.StatusCodeUnknownError {
-				// update the error code to a possibly more specific code
-				span.Status().SetCode(populatedChildSpan.Status().Code())
-			}
+			// update the error code to the possibly more specific code of
+			// the first failed subsegment
+			span.Status().SetCode(populatedChildSpan.Status().Code())
+			inheritStatus = false
 		}
 	}
 
@@ -122,6 +179,7 @@ func segToSpans(seg awsxray.Segment,
 
 func populateSpan(
 	seg *awsxray.Segment,
+	raw interface{},
 	traceID, parentID *string,
 	span *pdata.Span) error {
 
@@ -150,17 +208,21 @@ func populateSpan(
 
 	addHTTP(seg, span)
 	addCause(seg, span)
-	addAWSToSpan(seg.AWS, &attrs)
-	err = addSQLToSpan(seg.SQL, &attrs)
+	addStatus(seg, span)
+	awsxraytranslator.AddAWSToSpan(seg.AWS, &attrs)
+	err = awsxraytranslator.AddSQLToSpan(seg.SQL, &attrs)
 	if err != nil {
 		return err
 	}
 
 	addBool(seg.Traced, awsxray.AWSXRayTracedAttribute, &attrs)
 
-	addAnnotations(seg.Annotations, &attrs)
-	addMetadata(seg.Metadata, &attrs)
+	awsxraytranslator.AddAnnotations(seg.Annotations, &attrs)
+	awsxraytranslator.AddMetadata(seg.Metadata, &attrs)
 
+	if raw != nil {
+		return addRawFields(raw, &attrs)
+	}
 	return nil
 }
 
@@ -169,7 +231,7 @@ func populateResource(seg *awsxray.Segment, rs *pdata.Resource) {
 	attrs := rs.Attributes()
 	attrs.InitEmptyWithCapacity(initAttrCapacity)
 
-	addAWSToResource(seg.AWS, &attrs)
+	awsxraytranslator.AddAWSToResource(seg.AWS, &attrs)
 	addSdkToResource(seg, &attrs)
 	if seg.Service != nil {
 		addString(
@@ -181,10 +243,10 @@ func populateResource(seg *awsxray.Segment, rs *pdata.Resource) {
 	addString(seg.ResourceARN, awsxray.AWSXRayResourceARNAttribute, &attrs)
 }
 
-func totalSegmentsCount(seg awsxray.Segment) int {
+func totalSegmentsCount(seg *awsxray.Segment) int {
 	subsegmentCount := 0
-	for _, s := range seg.Subsegments {
-		subsegmentCount += totalSegmentsCount(s)
+	for i := range seg.Subsegments {
+		subsegmentCount += totalSegmentsCount(&seg.Subsegments[i])
 	}
 
 	return 1 + subsegmentCount
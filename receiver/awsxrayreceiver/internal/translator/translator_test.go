@@ -16,6 +16,7 @@ package translator
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"path"
@@ -52,6 +53,33 @@ type eventProps struct {
 	attrs map[string]pdata.AttributeValue
 }
 
+func TestConversionErrorReasons(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawSeg []byte
+		reason string
+	}{
+		{name: "malformed JSON", rawSeg: []byte("invalid"), reason: ReasonMalformedJSON},
+		{name: "validation failure", rawSeg: readSample(t, "segmentValidationFailed.txt"), reason: ReasonValidationFailure},
+		{name: "translation error", rawSeg: readSample(t, "invalidNamespace.txt"), reason: ReasonTranslationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ToTraces(tt.rawSeg, Options{})
+			var convErr *ConversionError
+			if assert.True(t, errors.As(err, &convErr), "the error should be a ConversionError") {
+				assert.Equal(t, tt.reason, convErr.Reason)
+				assert.Equal(t, convErr.Err.Error(), err.Error())
+			}
+
+			_, err = NewBatch(Options{}).Add(tt.rawSeg)
+			if assert.True(t, errors.As(err, &convErr), "the error should be a ConversionError") {
+				assert.Equal(t, tt.reason, convErr.Reason)
+			}
+		})
+	}
+}
+
 func TestTranslation(t *testing.T) {
 	var defaultServerSpanAttrs = func(seg *awsxray.Segment) map[string]pdata.AttributeValue {
 		attrs := make(map[string]pdata.AttributeValue)
@@ -150,7 +178,8 @@ func TestTranslation(t *testing.T) {
 					endTimeSec:   seg.EndTime,
 					spanKind:     pdata.SpanKindINTERNAL,
 					spanStatus: spanSt{
-						code: pdata.StatusCodeInvalidArgument,
+						message: *seg.Cause.Exceptions[0].Message,
+						code:    pdata.StatusCodeInvalidArgument,
 					},
 					eventsProps: rootSpanEvts,
 					attrs:       rootSpanAttrs,
@@ -181,7 +210,8 @@ func TestTranslation(t *testing.T) {
 					endTimeSec:   subseg7df6.EndTime,
 					spanKind:     pdata.SpanKindINTERNAL,
 					spanStatus: spanSt{
-						code: pdata.StatusCodeInvalidArgument,
+						message: *subseg7df6.Cause.Exceptions[0].Message,
+						code:    pdata.StatusCodeInvalidArgument,
 					},
 					eventsProps: childSpan7df6Evts,
 					attrs:       childSpan7df6Attrs,
@@ -423,7 +453,8 @@ func TestTranslation(t *testing.T) {
 					endTimeSec:   subseg7163.EndTime,
 					spanKind:     pdata.SpanKindCLIENT,
 					spanStatus: spanSt{
-						code: pdata.StatusCodeInvalidArgument,
+						message: *subseg7163.Cause.Exceptions[0].Message,
+						code:    pdata.StatusCodeInvalidArgument,
 					},
 					eventsProps: childSpan7163Evts,
 					attrs:       childSpan7163Attrs,
@@ -457,7 +488,8 @@ func TestTranslation(t *testing.T) {
 					endTimeSec:   subseg56b1.EndTime,
 					spanKind:     pdata.SpanKindINTERNAL,
 					spanStatus: spanSt{
-						code: pdata.StatusCodeUnknownError,
+						message: *subseg56b1.Cause.Exceptions[0].Message,
+						code:    pdata.StatusCodeInternalError,
 					},
 					eventsProps: childSpan56b1Evts,
 					attrs:       nil,
@@ -507,7 +539,8 @@ func TestTranslation(t *testing.T) {
 					endTimeSec:   subsegBa8d.EndTime,
 					spanKind:     pdata.SpanKindINTERNAL,
 					spanStatus: spanSt{
-						code: pdata.StatusCodeUnknownError,
+						message: *subsegBa8d.Cause.Exceptions[0].Message,
+						code:    pdata.StatusCodeInternalError,
 					},
 					eventsProps: childSpanBa8dEvts,
 					attrs:       nil,
@@ -657,7 +690,7 @@ func TestTranslation(t *testing.T) {
 					spanKind:     pdata.SpanKindINTERNAL,
 					spanStatus: spanSt{
 						message: *seg.Cause.ExceptionID,
-						code:    pdata.StatusCodeUnknownError,
+						code:    pdata.StatusCodeInternalError,
 					},
 					attrs: nil,
 				}
@@ -862,7 +895,7 @@ func TestTranslation(t *testing.T) {
 			)
 		}
 
-		traces, totalSpansCount, err := ToTraces(content)
+		traces, totalSpansCount, err := ToTraces(content, Options{})
 		if err == nil || (expectedRs != nil && expectedRs.InstrumentationLibrarySpans().Len() > 0 &&
 			expectedRs.InstrumentationLibrarySpans().At(0).Spans().Len() > 0) {
 			assert.Equal(t, totalSpansCount,
@@ -994,12 +1027,14 @@ func initResourceSpans(expectedSeg *awsxray.Segment,
 // abbrev. as exp) and actual ResourceSpans (abbrev. as act):
 // 1. clears the resource attributes on both exp and act, after verifying
 // .  both sets are the same.
-// 2. clears the span attributes of all the
-//    spans on both exp and act, after going through all the spans
+//  2. clears the span attributes of all the
+//     spans on both exp and act, after going through all the spans
+//
 // .  on both exp and act and verify that all the attributes match.
-// 3. similarly, for all the events and their attributes within a span,
-//    this function performs the same equality verification, then clears
-//    up all the attribute.
+//  3. similarly, for all the events and their attributes within a span,
+//     this function performs the same equality verification, then clears
+//     up all the attribute.
+//
 // The reason for doing so is just to be able to use deep equal via assert.Equal()
 func compare2ResourceSpans(t *testing.T, testCase string, exp, act *pdata.ResourceSpans) {
 	assert.Equal(t, exp.InstrumentationLibrarySpans().Len(),
@@ -68,6 +68,9 @@ type Config struct {
 	Transport            string
 	Endpoint             string
 	NumOfPollerToStart   int
+	// ReadBufferSize is the size in bytes of the receive buffer of the
+	// socket, the system default if zero.
+	ReadBufferSize int
 }
 
 type poller struct {
@@ -102,6 +105,12 @@ func New(cfg *Config, logger *zap.Logger) (Poller, error) {
 	if err != nil {
 		return nil, err
 	}
+	if cfg.ReadBufferSize > 0 {
+		if err = sock.SetReadBuffer(cfg.ReadBufferSize); err != nil {
+			sock.Close()
+			return nil, fmt.Errorf("failed to set the UDP read buffer size: %w", err)
+		}
+	}
 	logger.Info("Listening on endpoint for X-Ray segments",
 		zap.String(Transport, addr.String()))
 
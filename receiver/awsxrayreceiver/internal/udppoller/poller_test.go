@@ -84,6 +84,23 @@ func TestUDPPortUnavailable(t *testing.T) {
 	assert.Contains(t, err.Error(), "address already in use", "error message should complain about address in-use")
 }
 
+func TestReadBufferSize(t *testing.T) {
+	addr, err := findAvailableAddress()
+	assert.NoError(t, err, "there should be address available")
+
+	p, err := New(
+		&Config{
+			Transport:          Transport,
+			Endpoint:           addr,
+			NumOfPollerToStart: 2,
+			ReadBufferSize:     1 << 20,
+		},
+		zap.NewNop(),
+	)
+	assert.NoError(t, err, "the read buffer size should be set")
+	assert.NoError(t, p.Close())
+}
+
 func TestCloseStopsPoller(t *testing.T) {
 	addr, err := findAvailableAddress()
 	assert.NoError(t, err, "there should be address available")
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayreceiver
+
+import (
+	"context"
+	"errors"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
+)
+
+func init() {
+	view.Register(
+		viewSegmentsReceived,
+		viewSegmentsParsed,
+		viewSegmentsRejected,
+		viewBatchSize,
+	)
+}
+
+var (
+	tagReceiverKey, _ = tag.NewKey("receiver")
+	tagReasonKey, _   = tag.NewKey("reason")
+
+	mSegmentsReceived = stats.Int64("otelcol/awsxray/segments_received", "Number of X-Ray segment documents received", "1")
+	mSegmentsParsed   = stats.Int64("otelcol/awsxray/segments_parsed", "Number of X-Ray segment documents successfully converted to spans", "1")
+	mSegmentsRejected = stats.Int64("otelcol/awsxray/segments_rejected", "Number of X-Ray segment documents which could not be converted to spans", "1")
+	mBatchSize        = stats.Int64("otelcol/awsxray/batch_size", "Number of X-Ray segment documents passed at once to the next consumer", "1")
+)
+
+var viewSegmentsReceived = &view.View{
+	Name:        mSegmentsReceived.Name(),
+	Description: mSegmentsReceived.Description(),
+	Measure:     mSegmentsReceived,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewSegmentsParsed = &view.View{
+	Name:        mSegmentsParsed.Name(),
+	Description: mSegmentsParsed.Description(),
+	Measure:     mSegmentsParsed,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewSegmentsRejected = &view.View{
+	Name:        mSegmentsRejected.Name(),
+	Description: mSegmentsRejected.Description(),
+	Measure:     mSegmentsRejected,
+	TagKeys:     []tag.Key{tagReceiverKey, tagReasonKey},
+	Aggregation: view.Sum(),
+}
+
+var viewBatchSize = &view.View{
+	Name:        mBatchSize.Name(),
+	Description: mBatchSize.Description(),
+	Measure:     mBatchSize,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Distribution(1, 2, 5, 10, 20, 50, 100, 200, 512),
+}
+
+func recordSegmentReceived(receiver string) {
+	record(receiver, nil, mSegmentsReceived.M(1))
+}
+
+func recordSegmentParsed(receiver string) {
+	record(receiver, nil, mSegmentsParsed.M(1))
+}
+
+// recordSegmentRejected records the rejection of a segment for the reason of
+// the conversion error.
+func recordSegmentRejected(receiver string, err error) {
+	reason := translator.ReasonTranslationError
+	var convErr *translator.ConversionError
+	if errors.As(err, &convErr) {
+		reason = convErr.Reason
+	}
+	record(receiver, []tag.Mutator{tag.Upsert(tagReasonKey, reason)}, mSegmentsRejected.M(1))
+}
+
+func recordBatchSize(receiver string, n int) {
+	record(receiver, nil, mBatchSize.M(int64(n)))
+}
+
+func record(receiver string, mutators []tag.Mutator, m stats.Measurement) {
+	mutators = append(mutators, tag.Upsert(tagReceiverKey, receiver))
+	_ = stats.RecordWithTags(context.Background(), mutators, m)
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awsxrayreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
+)
+
+func TestSegmentsMetrics(t *testing.T) {
+	tests := []struct {
+		name        string
+		batchWindow time.Duration
+		wantBatches int64
+	}{
+		{name: "awsxray/metrics_unbatched", wantBatches: 2},
+		{name: "awsxray/metrics_batched", batchWindow: time.Hour, wantBatches: 1},
+	}
+	invalidNamespace, err := ioutil.ReadFile(path.Join("..", "..", "internal", "awsxray", "testdata", "invalidNamespace.txt"))
+	require.NoError(t, err)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments := make(chan udppoller.RawSegment, 5)
+			rcvr := &xrayReceiver{
+				instanceName: tt.name,
+				batchWindow:  tt.batchWindow,
+				poller:       &segmentsPoller{segments: segments},
+				logger:       zap.NewNop(),
+				consumer:     new(exportertest.SinkTraceExporter),
+			}
+
+			segments <- rawSegment(t, "5f5f4b8a00000001", false)
+			segments <- rawSegment(t, "5f5f4b8a00000002", false)
+			segments <- udppoller.RawSegment{Ctx: context.Background(), Payload: []byte("invalidSegment")}
+			segments <- udppoller.RawSegment{Ctx: context.Background(), Payload: []byte("{}")}
+			segments <- udppoller.RawSegment{Ctx: context.Background(), Payload: invalidNamespace}
+			close(segments)
+			rcvr.start()
+
+			assert.Equal(t, int64(5), viewSum(t, viewSegmentsReceived, tt.name, ""))
+			assert.Equal(t, int64(2), viewSum(t, viewSegmentsParsed, tt.name, ""))
+			assert.Equal(t, int64(1), viewSum(t, viewSegmentsRejected, tt.name, translator.ReasonMalformedJSON))
+			assert.Equal(t, int64(1), viewSum(t, viewSegmentsRejected, tt.name, translator.ReasonValidationFailure))
+			assert.Equal(t, int64(1), viewSum(t, viewSegmentsRejected, tt.name, translator.ReasonTranslationError))
+
+			batches := viewDistribution(t, viewBatchSize, tt.name)
+			require.NotNil(t, batches)
+			assert.Equal(t, tt.wantBatches, batches.Count)
+			assert.Equal(t, 2.0, batches.Mean*float64(batches.Count), "the batches should hold the parsed segments")
+		})
+	}
+}
+
+// viewSum returns the value of the view for the receiver, and for the reason
+// if not empty.
+func viewSum(t *testing.T, v *view.View, receiver, reason string) int64 {
+	for _, row := range viewRows(t, v, receiver) {
+		if reason != "" && tagValue(row, tagReasonKey) != reason {
+			continue
+		}
+		return int64(row.Data.(*view.SumData).Value)
+	}
+	return 0
+}
+
+// viewDistribution returns the distribution of the view for the receiver.
+func viewDistribution(t *testing.T, v *view.View, receiver string) *view.DistributionData {
+	rows := viewRows(t, v, receiver)
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows[0].Data.(*view.DistributionData)
+}
+
+func viewRows(t *testing.T, v *view.View, receiver string) []*view.Row {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+	var matching []*view.Row
+	for _, row := range rows {
+		if tagValue(row, tagReceiverKey) == receiver {
+			matching = append(matching, row)
+		}
+	}
+	return matching
+}
+
+func tagValue(row *view.Row, key tag.Key) string {
+	for _, tg := range row.Tags {
+		if tg.Key == key {
+			return tg.Value
+		}
+	}
+	return ""
+}
@@ -16,8 +16,11 @@ package awsxrayreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
@@ -26,7 +29,9 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/awsxray"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/httplistener"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/segmentbuffer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/translator"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
@@ -35,13 +40,25 @@ const (
 	// number of goroutines polling the UDP socket.
 	// https://github.com/aws/aws-xray-daemon/blob/master/pkg/cfg/cfg.go#L184
 	maxPollerCount = 2
+
+	// minExpiryCheckInterval is the minimum interval between the checks of
+	// the in-progress segments whose window elapsed.
+	minExpiryCheckInterval = 10 * time.Millisecond
 )
 
 // xrayReceiver implements the component.TraceReceiver interface for converting
 // AWS X-Ray segment document into the OT internal trace format.
 type xrayReceiver struct {
-	instanceName string
+	instanceName     string
+	inProgressWindow time.Duration
+	batchWindow      time.Duration
+	// translatorOptions are the options of the conversion of the
+	// segments to spans.
+	translatorOptions translator.Options
+	// numWorkers is the number of goroutines converting the segments.
+	numWorkers   int
 	poller       udppoller.Poller
+	listener     httplistener.Listener
 	server       proxy.Server
 	logger       *zap.Logger
 	consumer     consumer.TraceConsumer
@@ -58,6 +75,14 @@ func newReceiver(config *Config,
 		return nil, componenterror.ErrNilNextConsumer
 	}
 
+	if config.AttributeLimits.MaxCount < 0 || config.AttributeLimits.MaxValueLength < 0 {
+		return nil, errors.New("the attribute limits must not be negative")
+	}
+
+	if config.NumWorkers < 0 || config.ReadBufferSize < 0 {
+		return nil, errors.New("num_workers and read_buffer_size must not be negative")
+	}
+
 	logger.Info("Going to listen on endpoint for X-Ray segments",
 		zap.String(udppoller.Transport, config.Endpoint))
 	poller, err := udppoller.New(&udppoller.Config{
@@ -65,6 +90,7 @@ func newReceiver(config *Config,
 		Transport:            config.Transport,
 		Endpoint:             config.Endpoint,
 		NumOfPollerToStart:   maxPollerCount,
+		ReadBufferSize:       config.ReadBufferSize,
 	}, logger)
 	if err != nil {
 		return nil, err
@@ -73,17 +99,39 @@ func newReceiver(config *Config,
 	logger.Info("Listening on endpoint for X-Ray segments",
 		zap.String(udppoller.Transport, config.Endpoint))
 
+	var listener httplistener.Listener
+	if config.HTTPServer != nil {
+		listener, err = httplistener.New(&httplistener.Config{
+			ReceiverInstanceName: config.Name(),
+			HTTPServerSettings:   *config.HTTPServer,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	srv, err := proxy.NewServer(config.ProxyServer, logger)
 	if err != nil {
 		return nil, err
 	}
 
 	return &xrayReceiver{
-		instanceName: config.Name(),
-		poller:       poller,
-		server:       srv,
-		logger:       logger,
-		consumer:     consumer,
+		instanceName:     config.Name(),
+		inProgressWindow: config.InProgressWindow,
+		batchWindow:      config.BatchWindow,
+		numWorkers:       config.NumWorkers,
+		translatorOptions: translator.Options{
+			PreserveUnknownFields: config.PreserveUnknownFields,
+			AttributeLimits: translator.AttributeLimits{
+				MaxCount:       config.AttributeLimits.MaxCount,
+				MaxValueLength: config.AttributeLimits.MaxValueLength,
+			},
+		},
+		poller:   poller,
+		listener: listener,
+		server:   srv,
+		logger:   logger,
+		consumer: consumer,
 	}, nil
 }
 
@@ -91,10 +139,23 @@ func (x *xrayReceiver) Start(ctx context.Context, host component.Host) error {
 	// TODO: Might want to pass `host` into read() below to report a fatal error
 	var err = componenterror.ErrAlreadyStarted
 	x.startOnce.Do(func() {
+		if x.listener != nil {
+			if err = x.listener.Start(ctx); err != nil {
+				return
+			}
+			x.logger.Info("X-Ray HTTP listener started")
+		}
 		x.longLivedCtx = obsreport.ReceiverContext(ctx, x.instanceName, udppoller.Transport, "")
 		x.poller.Start(x.longLivedCtx)
 		go x.start()
-		go x.server.ListenAndServe()
+		go func() {
+			// The SDKs can't get their sampling rules and targets without the
+			// proxy, a failure to serve must not go unnoticed.
+			if proxyErr := x.server.ListenAndServe(); proxyErr != nil && proxyErr != http.ErrServerClosed {
+				x.logger.Error("X-Ray TCP proxy server failed", zap.Error(proxyErr))
+				host.ReportFatalError(fmt.Errorf("X-Ray TCP proxy server failed: %w", proxyErr))
+			}
+		}()
 		x.logger.Info("X-Ray TCP proxy server started")
 		err = nil
 	})
@@ -105,6 +166,13 @@ func (x *xrayReceiver) Shutdown(_ context.Context) error {
 	var err = componenterror.ErrAlreadyStopped
 	x.stopOnce.Do(func() {
 		err = nil
+		if x.listener != nil {
+			// The listener is closed first so that the segments of the
+			// requests being handled are still consumed.
+			if listenerErr := x.listener.Close(); listenerErr != nil {
+				x.logger.Error("Failed to close the X-Ray HTTP listener", zap.Error(listenerErr))
+			}
+		}
 		pollerErr := x.poller.Close()
 		if pollerErr != nil {
 			err = pollerErr
@@ -123,22 +191,145 @@ func (x *xrayReceiver) Shutdown(_ context.Context) error {
 	return err
 }
 
+// segments returns the channel of the segments received over UDP and, if
+// enabled, over HTTP, closed once the poller and the listener are closed.
+func (x *xrayReceiver) segments() <-chan udppoller.RawSegment {
+	if x.listener == nil {
+		return x.poller.SegmentsChan()
+	}
+
+	merged := make(chan udppoller.RawSegment)
+	var wg sync.WaitGroup
+	for _, segments := range []<-chan udppoller.RawSegment{x.poller.SegmentsChan(), x.listener.SegmentsChan()} {
+		wg.Add(1)
+		go func(segments <-chan udppoller.RawSegment) {
+			defer wg.Done()
+			for seg := range segments {
+				merged <- seg
+			}
+		}(segments)
+	}
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+	return merged
+}
+
+// start converts the received segments with numWorkers goroutines, the
+// in-progress segments being held first by a goroutine of their own if
+// enabled, and returns once all the segments are converted.
 func (x *xrayReceiver) start() {
-	incomingSegments := x.poller.SegmentsChan()
-	for seg := range incomingSegments {
-		traces, totalSpansCount, err := translator.ToTraces(seg.Payload)
-		if err != nil {
-			x.logger.Warn("X-Ray segment to OT traces conversion failed", zap.Error(err))
-			obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, err)
-			continue
+	segments := x.segments()
+	if x.inProgressWindow > 0 {
+		segments = x.holdInProgress(segments)
+	}
+
+	workers := x.numWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			x.convert(segments)
+		}()
+	}
+	wg.Wait()
+}
+
+// holdInProgress returns the channel of the segments once they are ready to
+// be sent downstream, the in-progress segments being held for the
+// in-progress window. The channel is closed once incomingSegments is closed,
+// the segments still held being passed on as is.
+func (x *xrayReceiver) holdInProgress(incomingSegments <-chan udppoller.RawSegment) <-chan udppoller.RawSegment {
+	ready := make(chan udppoller.RawSegment)
+	go func() {
+		defer close(ready)
+		buffer := segmentbuffer.New(x.inProgressWindow)
+		checkInterval := x.inProgressWindow / 10
+		if checkInterval < minExpiryCheckInterval {
+			checkInterval = minExpiryCheckInterval
 		}
+		ticker := time.NewTicker(checkInterval)
+		defer ticker.Stop()
 
-		err = x.consumer.ConsumeTraces(seg.Ctx, *traces)
-		if err != nil {
-			x.logger.Warn("Trace consumer errored out", zap.Error(err))
-			obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, err)
-			continue
+		for {
+			select {
+			case seg, ok := <-incomingSegments:
+				if !ok {
+					// The poller is closed, the segments still held are sent as is.
+					for _, held := range buffer.Flush() {
+						ready <- held
+					}
+					return
+				}
+				passed, replaced := buffer.Add(seg, time.Now())
+				for _, r := range replaced {
+					// The replaced in-progress segments are not sent downstream.
+					obsreport.EndTraceDataReceiveOp(r.Ctx, awsxray.TypeStr, 0, nil)
+				}
+				for _, r := range passed {
+					ready <- r
+				}
+			case now := <-ticker.C:
+				for _, held := range buffer.Expired(now) {
+					ready <- held
+				}
+			}
 		}
-		obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, nil)
+	}()
+	return ready
+}
+
+// convert converts the segments until the channel is closed, each segment
+// being sent downstream on its own or, if batching is enabled, in the batch
+// of the goroutine.
+func (x *xrayReceiver) convert(segments <-chan udppoller.RawSegment) {
+	if x.batchWindow <= 0 {
+		for seg := range segments {
+			x.process(seg)
+		}
+		return
+	}
+
+	batch := newSegmentBatch(x.translatorOptions)
+	ticker := time.NewTicker(x.batchWindow)
+	defer ticker.Stop()
+	for {
+		select {
+		case seg, ok := <-segments:
+			if !ok {
+				x.flushBatch(batch)
+				return
+			}
+			x.addToBatch(batch, seg)
+		case <-ticker.C:
+			x.flushBatch(batch)
+		}
+	}
+}
+
+// process converts a segment and sends it downstream on its own.
+func (x *xrayReceiver) process(seg udppoller.RawSegment) {
+	recordSegmentReceived(x.instanceName)
+	traces, totalSpansCount, err := translator.ToTraces(seg.Payload, x.translatorOptions)
+	if err != nil {
+		x.logger.Warn("X-Ray segment to OT traces conversion failed", zap.Error(err))
+		recordSegmentRejected(x.instanceName, err)
+		obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, err)
+		return
+	}
+	recordSegmentParsed(x.instanceName)
+
+	recordBatchSize(x.instanceName, 1)
+	err = x.consumer.ConsumeTraces(seg.Ctx, *traces)
+	if err != nil {
+		x.logger.Warn("Trace consumer errored out", zap.Error(err))
+		obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, err)
+		return
 	}
+	obsreport.EndTraceDataReceiveOp(seg.Ctx, awsxray.TypeStr, totalSpansCount, nil)
 }
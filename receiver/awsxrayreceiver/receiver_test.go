@@ -20,17 +20,20 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
@@ -42,6 +45,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/httplistener"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/proxy"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/awsxrayreceiver/internal/udppoller"
 )
@@ -74,6 +78,36 @@ func TestConsumerCantBeNil(t *testing.T) {
 	assert.True(t, errors.Is(err, componenterror.ErrNilNextConsumer), "consumer is nil should be detected")
 }
 
+func TestNegativeAttributeLimits(t *testing.T) {
+	_, err := newReceiver(
+		&Config{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "localhost:0",
+				Transport: udppoller.Transport,
+			},
+			AttributeLimits: AttributeLimitsConfig{MaxValueLength: -1},
+		},
+		new(exportertest.SinkTraceExporter),
+		zap.NewNop(),
+	)
+	assert.EqualError(t, err, "the attribute limits must not be negative")
+}
+
+func TestNegativeNumWorkers(t *testing.T) {
+	_, err := newReceiver(
+		&Config{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  "localhost:0",
+				Transport: udppoller.Transport,
+			},
+			NumWorkers: -1,
+		},
+		new(exportertest.SinkTraceExporter),
+		zap.NewNop(),
+	)
+	assert.EqualError(t, err, "num_workers and read_buffer_size must not be negative")
+}
+
 func TestProxyCreationFailed(t *testing.T) {
 	addr, err := findAvailableUDPAddress()
 	assert.NoError(t, err, "there should be address available")
@@ -148,6 +182,45 @@ func TestCantStartAnInstanceTwice(t *testing.T) {
 	assert.True(t, errors.Is(err, componenterror.ErrAlreadyStarted), "should not start receiver instance twice")
 }
 
+func TestProxyListenErrorReported(t *testing.T) {
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(defaultRegionEnvName, mockRegion)
+
+	addr, err := findAvailableUDPAddress()
+	assert.NoError(t, err, "there should be address available")
+	// occupy the TCP endpoint of the proxy
+	tcpListener, err := net.Listen("tcp", "localhost:0")
+	assert.NoError(t, err, "there should be address available")
+	defer tcpListener.Close()
+
+	rcvr, err := newReceiver(
+		&Config{
+			NetAddr: confignet.NetAddr{
+				Endpoint:  addr,
+				Transport: udppoller.Transport,
+			},
+			ProxyServer: &proxy.Config{
+				TCPAddr: confignet.TCPAddr{
+					Endpoint: tcpListener.Addr().String(),
+				},
+			},
+		},
+		new(exportertest.SinkTraceExporter),
+		zap.NewNop(),
+	)
+	assert.NoError(t, err, "receiver should be created")
+
+	host := componenttest.NewErrorWaitingHost()
+	err = rcvr.Start(context.Background(), host)
+	assert.NoError(t, err, "should be able to start the receiver")
+	defer rcvr.Shutdown(context.Background())
+
+	received, err := host.WaitForFatalError(5 * time.Second)
+	assert.True(t, received, "the proxy failure should be reported")
+	assert.Contains(t, err.Error(), "X-Ray TCP proxy server failed")
+}
+
 func TestCantStopAnInstanceTwice(t *testing.T) {
 	env := stashEnv()
 	defer restoreEnv(env)
@@ -234,6 +307,65 @@ func TestSegmentsPassedToConsumer(t *testing.T) {
 	obsreporttest.CheckReceiverTracesViews(t, receiverName, udppoller.Transport, 18, 0)
 }
 
+func TestSegmentsOverHTTPPassedToConsumer(t *testing.T) {
+	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
+	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
+	defer doneFn()
+
+	env := stashEnv()
+	defer restoreEnv(env)
+	os.Setenv(defaultRegionEnvName, mockRegion)
+
+	const receiverName = "TestSegmentsOverHTTPPassedToConsumer"
+
+	addr, err := findAvailableUDPAddress()
+	assert.NoError(t, err, "there should be address available")
+	httpAddr := testutil.GetAvailableLocalAddress(t)
+
+	sink := new(exportertest.SinkTraceExporter)
+	rcvr, err := newReceiver(
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				NameVal: receiverName,
+			},
+			NetAddr: confignet.NetAddr{
+				Endpoint:  addr,
+				Transport: udppoller.Transport,
+			},
+			ProxyServer: &proxy.Config{
+				TCPAddr: confignet.TCPAddr{
+					Endpoint: testutil.GetAvailableLocalAddress(t),
+				},
+			},
+			HTTPServer: &confighttp.HTTPServerSettings{
+				Endpoint: httpAddr,
+			},
+		},
+		sink,
+		zap.NewNop(),
+	)
+	assert.NoError(t, err, "receiver should be created")
+	err = rcvr.Start(context.Background(), componenttest.NewNopHost())
+	assert.NoError(t, err, "receiver should be started")
+	defer rcvr.Shutdown(context.Background())
+
+	content, err := ioutil.ReadFile(path.Join("../../internal/awsxray", "testdata", "ddbSample.txt"))
+	assert.NoError(t, err, "can not read raw segment")
+
+	resp, err := http.Post("http://"+httpAddr, "application/json",
+		strings.NewReader("["+string(content)+"]"))
+	assert.NoError(t, err, "can not post the segments")
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	testutil.WaitFor(t, func() bool {
+		got := sink.AllTraces()
+		return len(got) == 1
+	}, "consumer should eventually get the X-Ray span")
+
+	obsreporttest.CheckReceiverTracesViews(t, receiverName, httplistener.Transport, 18, 0)
+}
+
 func TestTranslatorErrorsOut(t *testing.T) {
 	doneFn, err := obsreporttest.SetupRecordedMetricsTest()
 	assert.NoError(t, err, "SetupRecordedMetricsTest should succeed")
@@ -335,6 +467,163 @@ func TestBothPollerAndProxyCloseError(t *testing.T) {
 		"expected error")
 }
 
+func TestInProgressSegmentReplaced(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment, 2)
+	rcvr := &xrayReceiver{
+		inProgressWindow: time.Hour,
+		poller:           &segmentsPoller{segments: segments},
+		logger:           zap.NewNop(),
+		consumer:         sink,
+	}
+
+	segments <- rawSegment(t, "5f5f4b8a00000001", true)
+	segments <- rawSegment(t, "5f5f4b8a00000001", false)
+	close(segments)
+	rcvr.start()
+
+	assert.Equal(t, 1, sink.SpansCount(), "only the completed segment should be passed on")
+}
+
+func TestInProgressSegmentPassedOnAfterWindow(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment)
+	rcvr := &xrayReceiver{
+		inProgressWindow: 10 * time.Millisecond,
+		poller:           &segmentsPoller{segments: segments},
+		logger:           zap.NewNop(),
+		consumer:         sink,
+	}
+	go rcvr.start()
+	defer close(segments)
+
+	segments <- rawSegment(t, "5f5f4b8a00000001", true)
+	assert.Equal(t, 0, sink.SpansCount(), "the in-progress segment should be held")
+
+	testutil.WaitFor(t, func() bool {
+		return sink.SpansCount() == 1
+	}, "the in-progress segment should be passed on once the window elapsed")
+}
+
+func TestInProgressSegmentPassedOnAtShutdown(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment, 1)
+	rcvr := &xrayReceiver{
+		inProgressWindow: time.Hour,
+		poller:           &segmentsPoller{segments: segments},
+		logger:           zap.NewNop(),
+		consumer:         sink,
+	}
+
+	segments <- rawSegment(t, "5f5f4b8a00000001", true)
+	close(segments)
+	rcvr.start()
+
+	assert.Equal(t, 1, sink.SpansCount(), "the held segment should be passed on")
+}
+
+func TestSegmentsBatched(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment, 3)
+	rcvr := &xrayReceiver{
+		batchWindow: time.Hour,
+		poller:      &segmentsPoller{segments: segments},
+		logger:      zap.NewNop(),
+		consumer:    sink,
+	}
+
+	segments <- rawSegment(t, "5f5f4b8a00000001", false)
+	segments <- rawSegment(t, "5f5f4b8a00000002", false)
+	segments <- udppoller.RawSegment{Ctx: context.Background(), Payload: []byte("invalidSegment")}
+	close(segments)
+	rcvr.start()
+
+	got := sink.AllTraces()
+	assert.Len(t, got, 1, "the segments should be passed on at once")
+	assert.Equal(t, 1, got[0].ResourceSpans().Len(), "the segments of the same resource should share their ResourceSpans")
+	assert.Equal(t, 2, got[0].SpanCount())
+}
+
+func TestSegmentsBatchedInProgressReplaced(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment)
+	rcvr := &xrayReceiver{
+		inProgressWindow: time.Hour,
+		batchWindow:      10 * time.Millisecond,
+		poller:           &segmentsPoller{segments: segments},
+		logger:           zap.NewNop(),
+		consumer:         sink,
+	}
+	go rcvr.start()
+	defer close(segments)
+
+	segments <- rawSegment(t, "5f5f4b8a00000001", true)
+	segments <- rawSegment(t, "5f5f4b8a00000001", false)
+	segments <- rawSegment(t, "5f5f4b8a00000002", false)
+
+	testutil.WaitFor(t, func() bool {
+		return sink.SpansCount() == 2
+	}, "the batch should be passed on once the window elapsed")
+}
+
+func TestSegmentsConvertedByWorkers(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment, 16)
+	rcvr := &xrayReceiver{
+		numWorkers: 4,
+		poller:     &segmentsPoller{segments: segments},
+		logger:     zap.NewNop(),
+		consumer:   sink,
+	}
+
+	for i := 0; i < 16; i++ {
+		segments <- rawSegment(t, fmt.Sprintf("5f5f4b8a%08x", i), false)
+	}
+	close(segments)
+	rcvr.start()
+
+	assert.Equal(t, 16, sink.SpansCount(), "all the segments should be passed on")
+}
+
+func TestSegmentsBatchedByWorkers(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	segments := make(chan udppoller.RawSegment, 16)
+	rcvr := &xrayReceiver{
+		inProgressWindow: time.Hour,
+		batchWindow:      time.Hour,
+		numWorkers:       4,
+		poller:           &segmentsPoller{segments: segments},
+		logger:           zap.NewNop(),
+		consumer:         sink,
+	}
+
+	segments <- rawSegment(t, "5f5f4b8a00000000", true)
+	for i := 0; i < 16; i++ {
+		if i == 1 {
+			continue
+		}
+		segments <- rawSegment(t, fmt.Sprintf("5f5f4b8a%08x", i), false)
+	}
+	close(segments)
+	rcvr.start()
+
+	assert.Equal(t, 15, sink.SpansCount(), "the batches of all the workers should be passed on")
+	assert.LessOrEqual(t, len(sink.AllTraces()), 4, "each worker should pass its segments on at once")
+}
+
+func rawSegment(t *testing.T, id string, inProgress bool) udppoller.RawSegment {
+	doc := fmt.Sprintf(`{"trace_id":"1-5f5f4b8a-0123456789abcdef01234567","id":%q,"name":"test","start_time":1599687562.1,`, id)
+	if inProgress {
+		doc += `"in_progress":true}`
+	} else {
+		doc += `"end_time":1599687563.1}`
+	}
+	return udppoller.RawSegment{
+		Ctx:     context.Background(),
+		Payload: []byte(doc),
+	}
+}
+
 type mockConsumer struct {
 	mu         sync.Mutex
 	consumeErr error
@@ -368,6 +657,20 @@ func (m *mockPoller) Close() error {
 	return nil
 }
 
+type segmentsPoller struct {
+	segments chan udppoller.RawSegment
+}
+
+func (p *segmentsPoller) SegmentsChan() <-chan udppoller.RawSegment {
+	return p.segments
+}
+
+func (p *segmentsPoller) Start(ctx context.Context) {}
+
+func (p *segmentsPoller) Close() error {
+	return nil
+}
+
 type mockProxy struct {
 	closeErr error
 }
@@ -93,13 +93,22 @@ func New(
 		config:       &config,
 		nextConsumer: nextConsumer,
 		server:       server,
-		reporter:     newReporter(config.Name(), logger),
+		reporter:     newReporter(config.Name(), transportName(config.Transport), logger),
 		parser:       parser,
 	}
 
 	return &r, nil
 }
 
+// transportName returns the transport reported with the receive operations,
+// "tcp" if not set.
+func transportName(configured string) string {
+	if configured == "" {
+		return "tcp"
+	}
+	return strings.ToLower(configured)
+}
+
 func buildTransportServer(config Config, logger *zap.Logger) (transport.Server, error) {
 	switch strings.ToLower(config.Transport) {
 	case "", "tcp":
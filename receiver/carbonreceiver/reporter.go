@@ -28,6 +28,7 @@ import (
 // observability per Collector metric observability package.
 type reporter struct {
 	name          string
+	transport     string
 	spanName      string
 	logger        *zap.Logger
 	sugaredLogger *zap.SugaredLogger // Used for generic debug logging
@@ -35,9 +36,12 @@ type reporter struct {
 
 var _ (transport.Reporter) = (*reporter)(nil)
 
-func newReporter(receiverName string, logger *zap.Logger) transport.Reporter {
+// newReporter creates the reporter of a receiver, transportType being the
+// transport of its server, reported with the receive operations.
+func newReporter(receiverName string, transportType string, logger *zap.Logger) transport.Reporter {
 	return &reporter{
 		name:          receiverName,
+		transport:     transportType,
 		spanName:      receiverName + ".receiver",
 		logger:        logger,
 		sugaredLogger: logger.Sugar(),
@@ -49,8 +53,8 @@ func newReporter(receiverName string, logger *zap.Logger) transport.Reporter {
 // reporter instance. The caller code should include a call to end the
 // returned span.
 func (r *reporter) OnDataReceived(ctx context.Context) context.Context {
-	ctx = obsreport.ReceiverContext(ctx, r.name, "tcp", r.name)
-	return obsreport.StartMetricsReceiveOp(ctx, r.name, "tcp")
+	ctx = obsreport.ReceiverContext(ctx, r.name, r.transport, r.name)
+	return obsreport.StartMetricsReceiveOp(ctx, r.name, r.transport)
 }
 
 // OnTranslationError is used to report a translation error from original
@@ -100,7 +104,9 @@ func (r *reporter) OnMetricsProcessed(
 		})
 	}
 
-	obsreport.EndMetricsReceiveOp(ctx, "carbon", numReceivedTimeseries, numReceivedTimeseries, err)
+	// The invalid lines are not passed to the next consumer.
+	numMetricPoints := numReceivedTimeseries - numInvalidTimeseries
+	obsreport.EndMetricsReceiveOp(ctx, "carbon", numMetricPoints, numMetricPoints, err)
 }
 
 func (r *reporter) OnDebugf(template string, args ...interface{}) {
@@ -30,18 +30,19 @@ func TestReporterObservability(t *testing.T) {
 	defer doneFn()
 
 	const receiverName = "fake_carbon_receiver"
-	reporter := newReporter(receiverName, zap.NewNop())
+	reporter := newReporter(receiverName, "tcp", zap.NewNop())
 
 	ctx := reporter.OnDataReceived(context.Background())
 
 	reporter.OnMetricsProcessed(ctx, 17, 13, nil)
 
-	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 17, 0)
+	// The invalid lines are not accepted.
+	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 4, 0)
 
 	// Below just exercise the error paths.
 	err = errors.New("fake error for tests")
 	reporter.OnTranslationError(ctx, err)
-	reporter.OnMetricsProcessed(ctx, 10, 10, err)
+	reporter.OnMetricsProcessed(ctx, 10, 0, err)
 
-	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 17, 10)
+	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 4, 10)
 }
@@ -0,0 +1,204 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+const (
+	chronycClientName = "chronyc"
+	ntpqClientName    = "ntpq"
+
+	// chronyc -c tracking prints 14 comma separated fields.
+	chronycTrackingFields = 14
+
+	// Leap status reported by chronyc when the clock is not synchronized.
+	chronycNotSynchronised = "Not synchronised"
+
+	// ntpd leap indicator value meaning the clock is not synchronized.
+	ntpqLeapAlarm = "11"
+)
+
+// tracking is the clock synchronization state reported by the time daemon.
+type tracking struct {
+	referenceID    string
+	stratum        int64
+	correction     float64
+	frequency      float64
+	rootDelay      float64
+	rootDispersion float64
+	leapStatus     string
+	synchronized   bool
+
+	// Only reported by chronyd.
+	lastOffset *float64
+	rmsOffset  *float64
+	skew       *float64
+}
+
+// commandRunner runs the named program and returns its standard output.
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// client retrieves the tracking information from a time daemon.
+type client interface {
+	tracking(ctx context.Context) (*tracking, error)
+}
+
+func newClient(config *Config, run commandRunner) (client, error) {
+	path := config.ClientPath
+	switch config.Client {
+	case "", chronycClientName:
+		if path == "" {
+			path = chronycClientName
+		}
+		return &chronycClient{path: path, run: run}, nil
+	case ntpqClientName:
+		if path == "" {
+			path = ntpqClientName
+		}
+		return &ntpqClient{path: path, run: run}, nil
+	}
+	return nil, fmt.Errorf("unsupported client %q for receiver %q", config.Client, config.Name())
+}
+
+// chronycClient queries chronyd using "chronyc -c tracking".
+type chronycClient struct {
+	path string
+	run  commandRunner
+}
+
+func (c *chronycClient) tracking(ctx context.Context) (*tracking, error) {
+	out, err := c.run(ctx, c.path, "-c", "tracking")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", c.path, err)
+	}
+	return parseChronycTracking(string(out))
+}
+
+func parseChronycTracking(out string) (*tracking, error) {
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse chronyc output: %w", err)
+	}
+	if len(records) != 1 || len(records[0]) != chronycTrackingFields {
+		return nil, fmt.Errorf("unexpected chronyc output: %q", out)
+	}
+	fields := records[0]
+
+	var errs []string
+	parseFloat := func(i int) float64 {
+		f, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		return f
+	}
+
+	stratum, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		errs = append(errs, err.Error())
+	}
+	lastOffset := parseFloat(5)
+	rmsOffset := parseFloat(6)
+	skew := parseFloat(9)
+	t := &tracking{
+		referenceID:    fields[0],
+		stratum:        stratum,
+		correction:     parseFloat(4),
+		lastOffset:     &lastOffset,
+		rmsOffset:      &rmsOffset,
+		frequency:      parseFloat(7),
+		skew:           &skew,
+		rootDelay:      parseFloat(10),
+		rootDispersion: parseFloat(11),
+		leapStatus:     fields[13],
+		synchronized:   fields[13] != chronycNotSynchronised,
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to parse chronyc output: %s", strings.Join(errs, "; "))
+	}
+	return t, nil
+}
+
+// ntpqClient queries ntpd using "ntpq -c rv".
+type ntpqClient struct {
+	path string
+	run  commandRunner
+}
+
+func (c *ntpqClient) tracking(ctx context.Context) (*tracking, error) {
+	out, err := c.run(ctx, c.path, "-n", "-c", "rv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", c.path, err)
+	}
+	return parseNtpqVariables(string(out))
+}
+
+func parseNtpqVariables(out string) (*tracking, error) {
+	vars := make(map[string]string)
+	for _, pair := range strings.FieldsFunc(out, func(r rune) bool { return r == ',' || r == '\n' }) {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) == 2 {
+			vars[kv[0]] = strings.Trim(kv[1], `"`)
+		}
+	}
+
+	var errs []string
+	parseMillis := func(key string) float64 {
+		f, err := strconv.ParseFloat(vars[key], 64)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", key, err))
+		}
+		return f / 1e3
+	}
+
+	leap, ok := vars["leap"]
+	if !ok {
+		return nil, errors.New("unexpected ntpq output: missing leap indicator")
+	}
+	stratum, err := strconv.ParseInt(vars["stratum"], 10, 64)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("stratum: %v", err))
+	}
+	frequency, err := strconv.ParseFloat(vars["frequency"], 64)
+	if err != nil {
+		errs = append(errs, fmt.Sprintf("frequency: %v", err))
+	}
+	t := &tracking{
+		referenceID:    vars["refid"],
+		stratum:        stratum,
+		correction:     parseMillis("offset"),
+		frequency:      frequency,
+		rootDelay:      parseMillis("rootdelay"),
+		rootDispersion: parseMillis("rootdisp"),
+		leapStatus:     leap,
+		synchronized:   leap != ntpqLeapAlarm,
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to parse ntpq output: %s", strings.Join(errs, "; "))
+	}
+	return t, nil
+}
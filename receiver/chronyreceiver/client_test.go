@@ -0,0 +1,116 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeRunner(t *testing.T, file string, wantName string, wantArgs ...string) commandRunner {
+	return func(_ context.Context, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, wantName, name)
+		assert.Equal(t, wantArgs, args)
+		return ioutil.ReadFile(path.Join(".", "testdata", file))
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
+
+func TestChronycClient(t *testing.T) {
+	c, err := newClient(createDefaultConfig().(*Config), fakeRunner(t, "chronyc_tracking.csv", "chronyc", "-c", "tracking"))
+	require.NoError(t, err)
+
+	got, err := c.tracking(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &tracking{
+		referenceID:    "A9FEA9FE",
+		stratum:        3,
+		correction:     -0.000012345,
+		lastOffset:     floatPtr(0.000001234),
+		rmsOffset:      floatPtr(0.000023456),
+		frequency:      -5.123,
+		skew:           floatPtr(0.012),
+		rootDelay:      0.000456,
+		rootDispersion: 0.000789,
+		leapStatus:     "Normal",
+		synchronized:   true,
+	}, got)
+}
+
+func TestNtpqClient(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Client = "ntpq"
+	cfg.ClientPath = "/usr/sbin/ntpq"
+	c, err := newClient(cfg, fakeRunner(t, "ntpq_rv.txt", "/usr/sbin/ntpq", "-n", "-c", "rv"))
+	require.NoError(t, err)
+
+	got, err := c.tracking(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", got.referenceID)
+	assert.Equal(t, int64(2), got.stratum)
+	assert.InDelta(t, -0.000047, got.correction, 1e-12)
+	assert.Equal(t, -12.345, got.frequency)
+	assert.InDelta(t, 0.001506, got.rootDelay, 1e-12)
+	assert.InDelta(t, 0.021098, got.rootDispersion, 1e-12)
+	assert.Equal(t, "00", got.leapStatus)
+	assert.True(t, got.synchronized)
+	assert.Nil(t, got.lastOffset)
+	assert.Nil(t, got.rmsOffset)
+	assert.Nil(t, got.skew)
+}
+
+func TestClientErrors(t *testing.T) {
+	run := func(context.Context, string, ...string) ([]byte, error) {
+		return nil, errors.New("not found")
+	}
+	c, err := newClient(createDefaultConfig().(*Config), run)
+	require.NoError(t, err)
+	_, err = c.tracking(context.Background())
+	assert.EqualError(t, err, "failed to run chronyc: not found")
+}
+
+func TestParseChronycTracking(t *testing.T) {
+	got, err := parseChronycTracking("00000000,,0,0.000000000,0.000000000,0.000000000,0.000000000,0.000,0.000,0.000,1.000000000,1.000000000,0.0,Not synchronised\n")
+	require.NoError(t, err)
+	assert.False(t, got.synchronized)
+	assert.Equal(t, "Not synchronised", got.leapStatus)
+
+	_, err = parseChronycTracking("506 Cannot talk to daemon\n")
+	assert.Error(t, err)
+
+	_, err = parseChronycTracking("A9FEA9FE,169.254.169.254,x,0,0,0,0,0,0,0,0,0,0,Normal\n")
+	assert.Error(t, err)
+}
+
+func TestParseNtpqVariables(t *testing.T) {
+	got, err := parseNtpqVariables("leap=11, stratum=16, rootdelay=0.000, rootdisp=0.000, offset=0.000, frequency=0.000")
+	require.NoError(t, err)
+	assert.False(t, got.synchronized)
+
+	_, err = parseNtpqVariables("ntpq: read: Connection refused")
+	assert.EqualError(t, err, "unexpected ntpq output: missing leap indicator")
+
+	_, err = parseNtpqVariables("leap=00, stratum=2")
+	assert.Error(t, err)
+}
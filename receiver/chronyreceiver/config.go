@@ -0,0 +1,41 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the chrony receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the tracking information
+	// is collected.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Timeout bounds the execution time of the client command.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// Client is the command line client used to query the time daemon,
+	// either "chronyc" for chronyd or "ntpq" for ntpd.
+	Client string `mapstructure:"client"`
+
+	// ClientPath is the path of the client binary. By default the client is
+	// looked up in the PATH.
+	ClientPath string `mapstructure:"client_path"`
+}
@@ -0,0 +1,99 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const leapStatusLabel = "leap.status"
+
+// buildMetrics converts the tracking information into metrics.
+func buildMetrics(t *tracking, now time.Time) []*metricspb.Metric {
+	ts := timestamppb.New(now)
+
+	synchronized := int64(0)
+	if t.synchronized {
+		synchronized = 1
+	}
+
+	metrics := []*metricspb.Metric{
+		intGauge("ntp.stratum", "Number of hops to the reference clock.", "1", t.stratum, ts),
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        "ntp.synchronized",
+				Description: "Whether the system clock is synchronized (1) or not (0).",
+				Unit:        "1",
+				Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+				LabelKeys:   []*metricspb.LabelKey{{Key: leapStatusLabel}},
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					LabelValues: []*metricspb.LabelValue{{Value: t.leapStatus, HasValue: true}},
+					Points: []*metricspb.Point{
+						{Timestamp: ts, Value: &metricspb.Point_Int64Value{Int64Value: synchronized}},
+					},
+				},
+			},
+		},
+		doubleGauge("ntp.time.correction", "Offset of the system clock from NTP time as reported by the time daemon.", "s", t.correction, ts),
+		doubleGauge("ntp.time.root_delay", "Total network path delay to the reference clock.", "s", t.rootDelay, ts),
+		doubleGauge("ntp.time.root_dispersion", "Total dispersion accumulated through all the hops to the reference clock.", "s", t.rootDispersion, ts),
+		doubleGauge("ntp.frequency.offset", "Rate at which the system clock would be wrong if not corrected.", "ppm", t.frequency, ts),
+	}
+	if t.lastOffset != nil {
+		metrics = append(metrics, doubleGauge("ntp.time.last_offset", "Estimated local offset on the last clock update.", "s", *t.lastOffset, ts))
+	}
+	if t.rmsOffset != nil {
+		metrics = append(metrics, doubleGauge("ntp.time.rms_offset", "Long-term average of the offset value.", "s", *t.rmsOffset, ts))
+	}
+	if t.skew != nil {
+		metrics = append(metrics, doubleGauge("ntp.skew", "Estimated error bound on the frequency.", "ppm", *t.skew, ts))
+	}
+	return metrics
+}
+
+func intGauge(name, description, unit string, value int64, ts *timestamppb.Timestamp) *metricspb.Metric {
+	return gauge(name, description, unit, metricspb.MetricDescriptor_GAUGE_INT64, &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_Int64Value{Int64Value: value},
+	})
+}
+
+func doubleGauge(name, description, unit string, value float64, ts *timestamppb.Timestamp) *metricspb.Metric {
+	return gauge(name, description, unit, metricspb.MetricDescriptor_GAUGE_DOUBLE, &metricspb.Point{
+		Timestamp: ts,
+		Value:     &metricspb.Point_DoubleValue{DoubleValue: value},
+	})
+}
+
+func gauge(name, description, unit string, metricType metricspb.MetricDescriptor_Type, point *metricspb.Point) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Type:        metricType,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				Points: []*metricspb.Point{point},
+			},
+		},
+	}
+}
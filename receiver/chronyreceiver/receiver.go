@@ -0,0 +1,126 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "chrony"
+	transport  = "exec"
+)
+
+var _ component.MetricsReceiver = (*chronyReceiver)(nil)
+
+// chronyReceiver implements the component.MetricsReceiver for clock
+// synchronization metrics.
+type chronyReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       client
+	cancel       context.CancelFunc
+}
+
+// newChronyReceiver creates the chrony receiver with the given parameters.
+func newChronyReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	c, err := newClient(config, runCommand)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &chronyReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client:       c,
+	}
+	return r, nil
+}
+
+// Start begins collecting the tracking information from the time daemon.
+func (r *chronyReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect clock synchronization metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the chrony receiver.
+func (r *chronyReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *chronyReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	clientCtx, cancel := ctx, context.CancelFunc(func() {})
+	if r.config.Timeout > 0 {
+		clientCtx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+	}
+	t, err := r.client.tracking(clientCtx)
+	cancel()
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: buildMetrics(t, time.Now()),
+	}
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
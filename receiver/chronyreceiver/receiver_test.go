@@ -0,0 +1,79 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package chronyreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newChronyReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*chronyReceiver)
+	r.client, err = newClient(cfg, fakeRunner(t, "chronyc_tracking.csv", "chronyc", "-c", "tracking"))
+	require.NoError(t, err)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	values := make(map[string]float64)
+	for _, m := range ocmds[0].Metrics {
+		p := m.GetTimeseries()[0].GetPoints()[0]
+		values[m.GetMetricDescriptor().GetName()] = p.GetDoubleValue() + float64(p.GetInt64Value())
+	}
+	assert.Equal(t, map[string]float64{
+		"ntp.stratum":              3,
+		"ntp.synchronized":         1,
+		"ntp.time.correction":      -0.000012345,
+		"ntp.time.root_delay":      0.000456,
+		"ntp.time.root_dispersion": 0.000789,
+		"ntp.frequency.offset":     -5.123,
+		"ntp.time.last_offset":     0.000001234,
+		"ntp.time.rms_offset":      0.000023456,
+		"ntp.skew":                 0.012,
+	}, values)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newChronyReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*chronyReceiver)
+	r.client, err = newClient(cfg, fakeRunner(t, "chronyc_tracking.csv", "chronyc", "-c", "tracking"))
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
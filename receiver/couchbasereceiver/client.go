@@ -0,0 +1,140 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	poolPath    = "/pools/default"
+	bucketsPath = "/pools/default/buckets"
+)
+
+// pool is the subset of the /pools/default response used by the receiver.
+type pool struct {
+	ClusterName string `json:"clusterName"`
+	Nodes       []node `json:"nodes"`
+}
+
+type node struct {
+	Hostname         string           `json:"hostname"`
+	Status           string           `json:"status"`
+	SystemStats      systemStats      `json:"systemStats"`
+	InterestingStats interestingStats `json:"interestingStats"`
+}
+
+type systemStats struct {
+	CPUUtilizationRate float64 `json:"cpu_utilization_rate"`
+	MemTotal           float64 `json:"mem_total"`
+	MemFree            float64 `json:"mem_free"`
+}
+
+type interestingStats struct {
+	CurrItems           float64 `json:"curr_items"`
+	Ops                 float64 `json:"ops"`
+	CouchDocsActualDisk float64 `json:"couch_docs_actual_disk_size"`
+	MemUsed             float64 `json:"mem_used"`
+}
+
+// bucket is the subset of an element of the /pools/default/buckets response
+// used by the receiver.
+type bucket struct {
+	Name       string      `json:"name"`
+	BasicStats bucketStats `json:"basicStats"`
+}
+
+type bucketStats struct {
+	QuotaPercentUsed float64 `json:"quotaPercentUsed"`
+	OpsPerSec        float64 `json:"opsPerSec"`
+	DiskFetches      float64 `json:"diskFetches"`
+	ItemCount        float64 `json:"itemCount"`
+	DiskUsed         float64 `json:"diskUsed"`
+	DataUsed         float64 `json:"dataUsed"`
+	MemUsed          float64 `json:"memUsed"`
+}
+
+// client retrieves the statistics of a Couchbase cluster.
+type client interface {
+	getPool(ctx context.Context) (*pool, error)
+	getBuckets(ctx context.Context) ([]bucket, error)
+}
+
+type restClient struct {
+	client   *http.Client
+	endpoint string
+	username string
+	password string
+}
+
+var _ client = (*restClient)(nil)
+
+func newRESTClient(config *Config) (*restClient, error) {
+	httpClient, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+	return &restClient{
+		client:   httpClient,
+		endpoint: strings.TrimSuffix(config.Endpoint, "/"),
+		username: config.Username,
+		password: config.Password,
+	}, nil
+}
+
+func (c *restClient) getPool(ctx context.Context) (*pool, error) {
+	var p pool
+	if err := c.get(ctx, poolPath, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (c *restClient) getBuckets(ctx context.Context) ([]bucket, error) {
+	var buckets []bucket
+	if err := c.get(ctx, bucketsPath, &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+func (c *restClient) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.endpoint+path, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
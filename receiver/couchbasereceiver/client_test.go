@@ -0,0 +1,101 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer starts a fake Couchbase REST API serving the responses of
+// testdata and requiring the otel/secret credentials.
+func startServer(t *testing.T) string {
+	mux := http.NewServeMux()
+	serveFile := func(file string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if username, password, ok := r.BasicAuth(); !ok || username != "otel" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body, err := ioutil.ReadFile(path.Join(".", "testdata", file))
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		}
+	}
+	mux.HandleFunc(poolPath, serveFile("pool.json"))
+	mux.HandleFunc(bucketsPath, serveFile("buckets.json"))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newTestClient(t *testing.T, endpoint string) *restClient {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint + "/"
+	cfg.Username = "otel"
+	cfg.Password = "secret"
+	c, err := newRESTClient(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClientGetPool(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+
+	p, err := c.getPool(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "production", p.ClusterName)
+	require.Len(t, p.Nodes, 2)
+	assert.Equal(t, "10.0.0.1:8091", p.Nodes[0].Hostname)
+	assert.Equal(t, "healthy", p.Nodes[0].Status)
+	assert.Equal(t, 12.5, p.Nodes[0].SystemStats.CPUUtilizationRate)
+	assert.Equal(t, float64(7303), p.Nodes[0].InterestingStats.CurrItems)
+}
+
+func TestClientGetBuckets(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+
+	buckets, err := c.getBuckets(context.Background())
+	require.NoError(t, err)
+	require.Len(t, buckets, 1)
+	assert.Equal(t, "travel-sample", buckets[0].Name)
+	assert.Equal(t, float64(31591), buckets[0].BasicStats.ItemCount)
+}
+
+func TestClientErrors(t *testing.T) {
+	endpoint := startServer(t)
+
+	c := newTestClient(t, endpoint)
+	c.password = "invalid"
+	_, err := c.getPool(context.Background())
+	assert.EqualError(t, err, "request to /pools/default failed: 401 Unauthorized")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer server.Close()
+	c = newTestClient(t, server.URL)
+	_, err = c.getBuckets(context.Background())
+	assert.EqualError(t, err, "failed to decode response of /pools/default/buckets: invalid character '<' looking for beginning of value")
+}
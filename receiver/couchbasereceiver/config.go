@@ -0,0 +1,36 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the Couchbase receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+
+	// Username and Password are the credentials used to authenticate to the
+	// REST API. The user needs the "Cluster Admin" or a read-only role.
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+
+	// CollectionInterval is the interval at which the cluster is queried.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+}
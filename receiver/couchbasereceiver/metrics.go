@@ -0,0 +1,219 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	nodeHostnameLabel = "couchbase.node.hostname"
+	bucketNameLabel   = "couchbase.bucket.name"
+
+	statusHealthy = "healthy"
+)
+
+// metricDef describes a metric reported with a single time series per node
+// or bucket.
+type metricDef struct {
+	name     string
+	unit     string
+	desc     string
+	labelKey string
+	mdType   metricspb.MetricDescriptor_Type
+}
+
+var (
+	nodeUpMetric = &metricDef{
+		name:     "couchbase.node.up",
+		unit:     "1",
+		desc:     "Whether the node is healthy (1) or not (0).",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeCPUUtilizationMetric = &metricDef{
+		name:     "couchbase.node.cpu.utilization",
+		unit:     "%",
+		desc:     "CPU utilization of the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+	nodeMemoryTotalMetric = &metricDef{
+		name:     "couchbase.node.memory.total",
+		unit:     "By",
+		desc:     "Total memory of the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeMemoryFreeMetric = &metricDef{
+		name:     "couchbase.node.memory.free",
+		unit:     "By",
+		desc:     "Free memory of the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeMemoryUsageMetric = &metricDef{
+		name:     "couchbase.node.memory.usage",
+		unit:     "By",
+		desc:     "Memory used by the data service of the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeDiskUsageMetric = &metricDef{
+		name:     "couchbase.node.disk.usage",
+		unit:     "By",
+		desc:     "Storage used by the documents stored on the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeItemsMetric = &metricDef{
+		name:     "couchbase.node.items",
+		unit:     "1",
+		desc:     "Number of items stored on the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	nodeOperationsMetric = &metricDef{
+		name:     "couchbase.node.operations",
+		unit:     "1/s",
+		desc:     "Rate of operations processed by the node.",
+		labelKey: nodeHostnameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+
+	bucketQuotaUsageMetric = &metricDef{
+		name:     "couchbase.bucket.memory.quota.usage",
+		unit:     "%",
+		desc:     "Percentage of the memory quota of the bucket in use.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+	bucketMemoryUsageMetric = &metricDef{
+		name:     "couchbase.bucket.memory.usage",
+		unit:     "By",
+		desc:     "Memory used by the bucket.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	bucketDiskUsageMetric = &metricDef{
+		name:     "couchbase.bucket.disk.usage",
+		unit:     "By",
+		desc:     "Storage used by the bucket.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	bucketDataUsageMetric = &metricDef{
+		name:     "couchbase.bucket.data.usage",
+		unit:     "By",
+		desc:     "Storage used by the data files of the bucket.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	bucketItemsMetric = &metricDef{
+		name:     "couchbase.bucket.items",
+		unit:     "1",
+		desc:     "Number of items stored in the bucket.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	bucketOperationsMetric = &metricDef{
+		name:     "couchbase.bucket.operations",
+		unit:     "1/s",
+		desc:     "Rate of operations processed by the bucket.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+	bucketDiskFetchesMetric = &metricDef{
+		name:     "couchbase.bucket.disk.fetches",
+		unit:     "1/s",
+		desc:     "Rate of items fetched from disk because they were not resident in memory.",
+		labelKey: bucketNameLabel,
+		mdType:   metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+)
+
+// metricsBuilder accumulates the time series of the metrics, one time series
+// being added per node or bucket.
+type metricsBuilder struct {
+	now     *timestamppb.Timestamp
+	metrics []*metricspb.Metric
+	byDef   map[*metricDef]*metricspb.Metric
+}
+
+func newMetricsBuilder(now time.Time) *metricsBuilder {
+	return &metricsBuilder{
+		now:   timestamppb.New(now),
+		byDef: make(map[*metricDef]*metricspb.Metric),
+	}
+}
+
+func (b *metricsBuilder) addNode(n *node) {
+	up := int64(0)
+	if n.Status == statusHealthy {
+		up = 1
+	}
+	b.add(nodeUpMetric, n.Hostname, float64(up))
+	b.add(nodeCPUUtilizationMetric, n.Hostname, n.SystemStats.CPUUtilizationRate)
+	b.add(nodeMemoryTotalMetric, n.Hostname, n.SystemStats.MemTotal)
+	b.add(nodeMemoryFreeMetric, n.Hostname, n.SystemStats.MemFree)
+	b.add(nodeMemoryUsageMetric, n.Hostname, n.InterestingStats.MemUsed)
+	b.add(nodeDiskUsageMetric, n.Hostname, n.InterestingStats.CouchDocsActualDisk)
+	b.add(nodeItemsMetric, n.Hostname, n.InterestingStats.CurrItems)
+	b.add(nodeOperationsMetric, n.Hostname, n.InterestingStats.Ops)
+}
+
+func (b *metricsBuilder) addBucket(bkt *bucket) {
+	stats := &bkt.BasicStats
+	b.add(bucketQuotaUsageMetric, bkt.Name, stats.QuotaPercentUsed)
+	b.add(bucketMemoryUsageMetric, bkt.Name, stats.MemUsed)
+	b.add(bucketDiskUsageMetric, bkt.Name, stats.DiskUsed)
+	b.add(bucketDataUsageMetric, bkt.Name, stats.DataUsed)
+	b.add(bucketItemsMetric, bkt.Name, stats.ItemCount)
+	b.add(bucketOperationsMetric, bkt.Name, stats.OpsPerSec)
+	b.add(bucketDiskFetchesMetric, bkt.Name, stats.DiskFetches)
+}
+
+// add appends a time series to the metric. The REST API reports all the
+// statistics as JSON numbers, they are converted to the type of the metric.
+func (b *metricsBuilder) add(def *metricDef, labelValue string, value float64) {
+	metric, ok := b.byDef[def]
+	if !ok {
+		metric = &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        def.name,
+				Description: def.desc,
+				Unit:        def.unit,
+				Type:        def.mdType,
+				LabelKeys:   []*metricspb.LabelKey{{Key: def.labelKey}},
+			},
+		}
+		b.byDef[def] = metric
+		b.metrics = append(b.metrics, metric)
+	}
+
+	point := &metricspb.Point{Timestamp: b.now}
+	if def.mdType == metricspb.MetricDescriptor_GAUGE_DOUBLE {
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: value}
+	} else {
+		point.Value = &metricspb.Point_Int64Value{Int64Value: int64(value)}
+	}
+	metric.Timeseries = append(metric.Timeseries, &metricspb.TimeSeries{
+		LabelValues: []*metricspb.LabelValue{{Value: labelValue, HasValue: true}},
+		Points:      []*metricspb.Point{point},
+	})
+}
@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsBuilder(t *testing.T) {
+	b := newMetricsBuilder(time.Unix(1000, 0))
+	b.addNode(&node{Hostname: "node-1:8091", Status: "healthy", SystemStats: systemStats{CPUUtilizationRate: 12.5}})
+	b.addNode(&node{Hostname: "node-2:8091", Status: "warmup"})
+	b.addBucket(&bucket{Name: "default", BasicStats: bucketStats{ItemCount: 42, OpsPerSec: 3.5}})
+
+	require.Len(t, b.metrics, 15)
+
+	up := b.metrics[0]
+	assert.Equal(t, "couchbase.node.up", up.MetricDescriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: nodeHostnameLabel}}, up.MetricDescriptor.LabelKeys)
+	require.Len(t, up.Timeseries, 2)
+	assert.Equal(t, "node-1:8091", up.Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(1), up.Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "node-2:8091", up.Timeseries[1].LabelValues[0].Value)
+	assert.Equal(t, int64(0), up.Timeseries[1].Points[0].GetInt64Value())
+	assert.Equal(t, int64(1000), up.Timeseries[0].Points[0].Timestamp.Seconds)
+
+	cpu := b.metrics[1]
+	assert.Equal(t, "couchbase.node.cpu.utilization", cpu.MetricDescriptor.Name)
+	assert.Equal(t, 12.5, cpu.Timeseries[0].Points[0].GetDoubleValue())
+
+	items := b.metrics[12]
+	assert.Equal(t, "couchbase.bucket.items", items.MetricDescriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: bucketNameLabel}}, items.MetricDescriptor.LabelKeys)
+	assert.Equal(t, "default", items.Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, int64(42), items.Timeseries[0].Points[0].GetInt64Value())
+
+	operations := b.metrics[13]
+	assert.Equal(t, "couchbase.bucket.operations", operations.MetricDescriptor.Name)
+	assert.Equal(t, 3.5, operations.Timeseries[0].Points[0].GetDoubleValue())
+}
@@ -0,0 +1,151 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "couchbase"
+	transport  = "http"
+
+	clusterNameLabel = "couchbase.cluster.name"
+)
+
+var _ component.MetricsReceiver = (*couchbaseReceiver)(nil)
+
+// couchbaseReceiver implements the component.MetricsReceiver for Couchbase.
+type couchbaseReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       client
+	cancel       context.CancelFunc
+}
+
+// newCouchbaseReceiver creates the Couchbase receiver with the given parameters.
+func newCouchbaseReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	c, err := newRESTClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &couchbaseReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client:       c,
+	}
+	return r, nil
+}
+
+// Start begins collecting the metrics of the Couchbase cluster.
+func (r *couchbaseReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect Couchbase metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the Couchbase receiver.
+func (r *couchbaseReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *couchbaseReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	md, err := r.buildMetricsData(ctx)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+func (r *couchbaseReceiver) buildMetricsData(ctx context.Context) (consumerdata.MetricsData, error) {
+	p, err := r.client.getPool(ctx)
+	if err != nil {
+		return consumerdata.MetricsData{}, err
+	}
+	buckets, err := r.client.getBuckets(ctx)
+	if err != nil {
+		return consumerdata.MetricsData{}, err
+	}
+
+	b := newMetricsBuilder(time.Now())
+	for i := range p.Nodes {
+		b.addNode(&p.Nodes[i])
+	}
+	for i := range buckets {
+		b.addBucket(&buckets[i])
+	}
+
+	labels := map[string]string{}
+	if p.ClusterName != "" {
+		labels[clusterNameLabel] = p.ClusterName
+	}
+	return consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{
+			Type:   typeStr,
+			Labels: labels,
+		},
+		Metrics: b.metrics,
+	}, nil
+}
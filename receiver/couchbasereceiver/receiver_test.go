@@ -0,0 +1,100 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package couchbasereceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func newTestConfig(t *testing.T) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t)
+	cfg.Username = "otel"
+	cfg.Password = "secret"
+	return cfg
+}
+
+func TestReceiverCollect(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newCouchbaseReceiver(zap.NewNop(), newTestConfig(t), sink)
+	require.NoError(t, err)
+	r := rcv.(*couchbaseReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	assert.Equal(t, map[string]string{clusterNameLabel: "production"}, ocmds[0].Resource.Labels)
+
+	metrics := make(map[string]int)
+	for _, metric := range ocmds[0].Metrics {
+		metrics[metric.MetricDescriptor.Name] = len(metric.Timeseries)
+	}
+	assert.Equal(t, map[string]int{
+		"couchbase.node.up":                   2,
+		"couchbase.node.cpu.utilization":      2,
+		"couchbase.node.memory.total":         2,
+		"couchbase.node.memory.free":          2,
+		"couchbase.node.memory.usage":         2,
+		"couchbase.node.disk.usage":           2,
+		"couchbase.node.items":                2,
+		"couchbase.node.operations":           2,
+		"couchbase.bucket.memory.quota.usage": 1,
+		"couchbase.bucket.memory.usage":       1,
+		"couchbase.bucket.disk.usage":         1,
+		"couchbase.bucket.data.usage":         1,
+		"couchbase.bucket.items":              1,
+		"couchbase.bucket.operations":         1,
+		"couchbase.bucket.disk.fetches":       1,
+	}, metrics)
+}
+
+func TestReceiverCollectError(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.Password = "invalid"
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newCouchbaseReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*couchbaseReceiver)
+
+	assert.Error(t, r.collect(context.Background()))
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	cfg := newTestConfig(t)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newCouchbaseReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,12 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/envoyalsreceiver
+
+go 1.14
+
+require (
+	github.com/envoyproxy/go-control-plane v0.9.7
+	github.com/golang/protobuf v1.4.2
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
+	go.uber.org/zap v1.16.0
+	google.golang.org/grpc v1.32.0
+)
@@ -0,0 +1,135 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyalsreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	alsv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+var _ component.LogsReceiver = (*envoyALSReceiver)(nil)
+
+// envoyALSReceiver implements the component.LogsReceiver for the Envoy
+// Access Log Service.
+type envoyALSReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.LogsConsumer
+
+	server *grpc.Server
+	done   chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// newEnvoyALSReceiver creates the Envoy ALS receiver with the given
+// parameters.
+func newEnvoyALSReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	r := &envoyALSReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}
+	return r, nil
+}
+
+// Start starts the gRPC server of the Access Log Service.
+func (r *envoyALSReceiver) Start(_ context.Context, host component.Host) error {
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		var opts []grpc.ServerOption
+		opts, err = r.config.GRPCServerSettings.ToServerOption()
+		if err != nil {
+			return
+		}
+
+		var ln net.Listener
+		ln, err = r.config.GRPCServerSettings.ToListener()
+		if err != nil {
+			return
+		}
+
+		r.server = grpc.NewServer(opts...)
+		alsv3.RegisterAccessLogServiceServer(r.server, r)
+		r.done = make(chan struct{})
+		go func() {
+			defer close(r.done)
+			if err := r.server.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				host.ReportFatalError(err)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops the gRPC server, closing the active streams.
+func (r *envoyALSReceiver) Shutdown(context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.server != nil {
+			r.server.Stop()
+			<-r.done
+		}
+	})
+	return err
+}
+
+// StreamAccessLogs implements alsv3.AccessLogServiceServer. Envoy only sends
+// the identifier of the node in the first message of each stream.
+func (r *envoyALSReceiver) StreamAccessLogs(stream alsv3.AccessLogService_StreamAccessLogsServer) error {
+	var identifier *alsv3.StreamAccessLogsMessage_Identifier
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&alsv3.StreamAccessLogsResponse{})
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.GetIdentifier() != nil {
+			identifier = msg.GetIdentifier()
+		}
+
+		ld := messageToLogs(identifier, msg)
+		if ld.LogRecordCount() == 0 {
+			continue
+		}
+		if err := r.nextConsumer.ConsumeLogs(stream.Context(), ld); err != nil {
+			// The entries are dropped, Envoy does not retry them anyway.
+			r.logger.Error("Failed to consume Envoy access logs", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyalsreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	alsv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func TestReceiverStreamAccessLogs(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = addr
+	sink := new(exportertest.SinkLogsExporter)
+	r, err := newEnvoyALSReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+	assert.Equal(t, componenterror.ErrAlreadyStarted, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := alsv3.NewAccessLogServiceClient(conn).StreamAccessLogs(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(testHTTPMessage(t)))
+	require.NoError(t, stream.Send(&alsv3.StreamAccessLogsMessage{
+		LogEntries: &alsv3.StreamAccessLogsMessage_HttpLogs{
+			HttpLogs: &alsv3.StreamAccessLogsMessage_HTTPAccessLogEntries{
+				LogEntry: []*accesslogv3.HTTPAccessLogEntry{{}, {}},
+			},
+		},
+	}))
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The identifier of the first message applies to the whole stream.
+	logs := sink.AllLogs()
+	require.Len(t, logs, 2)
+	attrs := logs[1].ResourceLogs().At(0).Resource().Attributes()
+	serviceName, ok := attrs.Get(serviceNameAttribute)
+	require.True(t, ok)
+	assert.Equal(t, "frontend", serviceName.StringVal())
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
+}
+
+func TestReceiverStartError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = "localhost:invalid"
+	r, err := newEnvoyALSReceiver(zap.NewNop(), cfg, new(exportertest.SinkLogsExporter))
+	require.NoError(t, err)
+
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,196 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyalsreceiver
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	alsv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/golang/protobuf/ptypes"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+const (
+	serviceNameAttribute = "service.name"
+	nodeIDAttribute      = "envoy.node.id"
+
+	httpMethodAttribute                = "http.method"
+	httpSchemeAttribute                = "http.scheme"
+	httpHostAttribute                  = "http.host"
+	httpTargetAttribute                = "http.target"
+	httpFlavorAttribute                = "http.flavor"
+	httpUserAgentAttribute             = "http.user_agent"
+	httpStatusCodeAttribute            = "http.status_code"
+	httpClientIPAttribute              = "http.client_ip"
+	httpRequestContentLengthAttribute  = "http.request_content_length"
+	httpResponseContentLengthAttribute = "http.response_content_length"
+	netPeerIPAttribute                 = "net.peer.ip"
+	netPeerPortAttribute               = "net.peer.port"
+
+	upstreamClusterAttribute = "envoy.upstream.cluster"
+	upstreamHostAttribute    = "envoy.upstream.host"
+	routeNameAttribute       = "envoy.route.name"
+	requestIDAttribute       = "envoy.request.id"
+	durationAttribute        = "envoy.duration"
+	bytesReceivedAttribute   = "envoy.bytes.received"
+	bytesSentAttribute       = "envoy.bytes.sent"
+)
+
+var httpFlavors = map[accesslogv3.HTTPAccessLogEntry_HTTPVersion]string{
+	accesslogv3.HTTPAccessLogEntry_HTTP10: "1.0",
+	accesslogv3.HTTPAccessLogEntry_HTTP11: "1.1",
+	accesslogv3.HTTPAccessLogEntry_HTTP2:  "2",
+	accesslogv3.HTTPAccessLogEntry_HTTP3:  "3",
+}
+
+// messageToLogs converts the entries of a message of an access log stream
+// into log records, identifier being the identifier sent in the first
+// message of the stream.
+func messageToLogs(identifier *alsv3.StreamAccessLogsMessage_Identifier, msg *alsv3.StreamAccessLogsMessage) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	rl.Resource().InitEmpty()
+	fillResource(rl.Resource().Attributes(), identifier.GetNode())
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+
+	httpEntries := msg.GetHttpLogs().GetLogEntry()
+	tcpEntries := msg.GetTcpLogs().GetLogEntry()
+	logs.Resize(len(httpEntries) + len(tcpEntries))
+	for i, entry := range httpEntries {
+		lr := logs.At(i)
+		lr.SetName(identifier.GetLogName())
+		fillHTTPLogRecord(lr, entry)
+	}
+	for i, entry := range tcpEntries {
+		lr := logs.At(len(httpEntries) + i)
+		lr.SetName(identifier.GetLogName())
+		fillTCPLogRecord(lr, entry)
+	}
+	return ld
+}
+
+// fillResource describes the Envoy node that sent the access logs, its
+// cluster being the name of the service.
+func fillResource(attrs pdata.AttributeMap, node *corev3.Node) {
+	if cluster := node.GetCluster(); cluster != "" {
+		attrs.InsertString(serviceNameAttribute, cluster)
+	}
+	if id := node.GetId(); id != "" {
+		attrs.InsertString(nodeIDAttribute, id)
+	}
+}
+
+func fillHTTPLogRecord(lr pdata.LogRecord, entry *accesslogv3.HTTPAccessLogEntry) {
+	attrs := lr.Attributes()
+	fillCommonAttributes(lr, entry.GetCommonProperties())
+
+	request := entry.GetRequest()
+	response := entry.GetResponse()
+	var method string
+	if request.GetRequestMethod() != corev3.RequestMethod_METHOD_UNSPECIFIED {
+		method = request.GetRequestMethod().String()
+	}
+	insertString(attrs, httpMethodAttribute, method)
+	insertString(attrs, httpSchemeAttribute, request.GetScheme())
+	insertString(attrs, httpHostAttribute, request.GetAuthority())
+	insertString(attrs, httpTargetAttribute, request.GetPath())
+	insertString(attrs, httpUserAgentAttribute, request.GetUserAgent())
+	insertString(attrs, requestIDAttribute, request.GetRequestId())
+	if forwardedFor := request.GetForwardedFor(); forwardedFor != "" {
+		// The first address of X-Forwarded-For is the original client.
+		attrs.InsertString(httpClientIPAttribute, strings.TrimSpace(strings.SplitN(forwardedFor, ",", 2)[0]))
+	}
+	flavor, hasFlavor := httpFlavors[entry.GetProtocolVersion()]
+	if hasFlavor {
+		attrs.InsertString(httpFlavorAttribute, flavor)
+	}
+	if request != nil {
+		attrs.InsertInt(httpRequestContentLengthAttribute, int64(request.GetRequestBodyBytes()))
+	}
+	if response != nil {
+		attrs.InsertInt(httpResponseContentLengthAttribute, int64(response.GetResponseBodyBytes()))
+	}
+	statusCode := response.GetResponseCode()
+	if statusCode != nil {
+		attrs.InsertInt(httpStatusCodeAttribute, int64(statusCode.GetValue()))
+	}
+
+	// The body summarizes the request as the default Envoy access log format,
+	// for instance "GET /path HTTP/1.1 200".
+	var parts []string
+	if method != "" {
+		parts = append(parts, method)
+	}
+	if path := request.GetPath(); path != "" {
+		parts = append(parts, path)
+	}
+	if hasFlavor {
+		parts = append(parts, "HTTP/"+flavor)
+	}
+	if statusCode != nil {
+		parts = append(parts, fmt.Sprint(statusCode.GetValue()))
+	}
+	lr.Body().InitEmpty()
+	lr.Body().SetStringVal(strings.Join(parts, " "))
+}
+
+func fillTCPLogRecord(lr pdata.LogRecord, entry *accesslogv3.TCPAccessLogEntry) {
+	attrs := lr.Attributes()
+	fillCommonAttributes(lr, entry.GetCommonProperties())
+
+	if connection := entry.GetConnectionProperties(); connection != nil {
+		attrs.InsertInt(bytesReceivedAttribute, int64(connection.GetReceivedBytes()))
+		attrs.InsertInt(bytesSentAttribute, int64(connection.GetSentBytes()))
+	}
+}
+
+// fillCommonAttributes sets the timestamp and the attributes shared by the
+// HTTP and TCP entries.
+func fillCommonAttributes(lr pdata.LogRecord, common *accesslogv3.AccessLogCommon) {
+	attrs := lr.Attributes()
+	if start, err := ptypes.Timestamp(common.GetStartTime()); err == nil {
+		lr.SetTimestamp(pdata.TimestampUnixNano(start.UnixNano()))
+	}
+	if peer := common.GetDownstreamRemoteAddress().GetSocketAddress(); peer != nil {
+		insertString(attrs, netPeerIPAttribute, peer.GetAddress())
+		if port := peer.GetPortValue(); port != 0 {
+			attrs.InsertInt(netPeerPortAttribute, int64(port))
+		}
+	}
+	insertString(attrs, upstreamClusterAttribute, common.GetUpstreamCluster())
+	if upstream := common.GetUpstreamRemoteAddress().GetSocketAddress(); upstream != nil && upstream.GetAddress() != "" {
+		attrs.InsertString(upstreamHostAttribute, fmt.Sprintf("%s:%d", upstream.GetAddress(), upstream.GetPortValue()))
+	}
+	insertString(attrs, routeNameAttribute, common.GetRouteName())
+	if last := common.GetTimeToLastDownstreamTxByte(); last != nil {
+		if d, err := ptypes.Duration(last); err == nil {
+			attrs.InsertInt(durationAttribute, int64(d/time.Millisecond))
+		}
+	}
+}
+
+// insertString inserts the attribute unless value is empty.
+func insertString(attrs pdata.AttributeMap, key, value string) {
+	if value != "" {
+		attrs.InsertString(key, value)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envoyalsreceiver
+
+import (
+	"testing"
+	"time"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	accesslogv3 "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v3"
+	alsv3 "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v3"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var testStartTime = time.Date(2020, 9, 15, 12, 0, 0, 0, time.UTC)
+
+func testIdentifier() *alsv3.StreamAccessLogsMessage_Identifier {
+	return &alsv3.StreamAccessLogsMessage_Identifier{
+		Node: &corev3.Node{
+			Id:      "sidecar~10.0.0.5~frontend-7d9c~default.svc.cluster.local",
+			Cluster: "frontend",
+		},
+		LogName: "http_access_log",
+	}
+}
+
+func socketAddress(ip string, port uint32) *corev3.Address {
+	return &corev3.Address{
+		Address: &corev3.Address_SocketAddress{
+			SocketAddress: &corev3.SocketAddress{
+				Address:       ip,
+				PortSpecifier: &corev3.SocketAddress_PortValue{PortValue: port},
+			},
+		},
+	}
+}
+
+func testCommonProperties(t *testing.T) *accesslogv3.AccessLogCommon {
+	start, err := ptypes.TimestampProto(testStartTime)
+	require.NoError(t, err)
+	return &accesslogv3.AccessLogCommon{
+		DownstreamRemoteAddress:    socketAddress("10.0.0.7", 51234),
+		UpstreamRemoteAddress:      socketAddress("10.0.1.3", 8080),
+		UpstreamCluster:            "backend",
+		RouteName:                  "default",
+		StartTime:                  start,
+		TimeToLastDownstreamTxByte: ptypes.DurationProto(42 * time.Millisecond),
+	}
+}
+
+func testHTTPMessage(t *testing.T) *alsv3.StreamAccessLogsMessage {
+	return &alsv3.StreamAccessLogsMessage{
+		Identifier: testIdentifier(),
+		LogEntries: &alsv3.StreamAccessLogsMessage_HttpLogs{
+			HttpLogs: &alsv3.StreamAccessLogsMessage_HTTPAccessLogEntries{
+				LogEntry: []*accesslogv3.HTTPAccessLogEntry{{
+					CommonProperties: testCommonProperties(t),
+					ProtocolVersion:  accesslogv3.HTTPAccessLogEntry_HTTP11,
+					Request: &accesslogv3.HTTPRequestProperties{
+						RequestMethod:    corev3.RequestMethod_GET,
+						Scheme:           "http",
+						Authority:        "frontend.example.com",
+						Path:             "/api/orders?id=1",
+						UserAgent:        "curl/7.68.0",
+						ForwardedFor:     "203.0.113.9, 10.0.0.2",
+						RequestId:        "3c9c3d6b-8a10-4e7c-b3a1-0d4b8e7f2a11",
+						RequestBodyBytes: 0,
+					},
+					Response: &accesslogv3.HTTPResponseProperties{
+						ResponseCode:      &wrappers.UInt32Value{Value: 200},
+						ResponseBodyBytes: 512,
+					},
+				}},
+			},
+		},
+	}
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	m := make(map[string]interface{}, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		switch v.Type() {
+		case pdata.AttributeValueINT:
+			m[k] = v.IntVal()
+		default:
+			m[k] = v.StringVal()
+		}
+	})
+	return m
+}
+
+func TestMessageToLogsHTTP(t *testing.T) {
+	msg := testHTTPMessage(t)
+	ld := messageToLogs(msg.Identifier, msg)
+
+	require.Equal(t, 1, ld.ResourceLogs().Len())
+	rl := ld.ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		serviceNameAttribute: "frontend",
+		nodeIDAttribute:      "sidecar~10.0.0.5~frontend-7d9c~default.svc.cluster.local",
+	}, attributesToMap(rl.Resource().Attributes()))
+
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 1, logs.Len())
+	lr := logs.At(0)
+	assert.Equal(t, "http_access_log", lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(testStartTime.UnixNano()), lr.Timestamp())
+	assert.Equal(t, "GET /api/orders?id=1 HTTP/1.1 200", lr.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{
+		httpMethodAttribute:                "GET",
+		httpSchemeAttribute:                "http",
+		httpHostAttribute:                  "frontend.example.com",
+		httpTargetAttribute:                "/api/orders?id=1",
+		httpFlavorAttribute:                "1.1",
+		httpUserAgentAttribute:             "curl/7.68.0",
+		httpStatusCodeAttribute:            int64(200),
+		httpClientIPAttribute:              "203.0.113.9",
+		httpRequestContentLengthAttribute:  int64(0),
+		httpResponseContentLengthAttribute: int64(512),
+		netPeerIPAttribute:                 "10.0.0.7",
+		netPeerPortAttribute:               int64(51234),
+		upstreamClusterAttribute:           "backend",
+		upstreamHostAttribute:              "10.0.1.3:8080",
+		routeNameAttribute:                 "default",
+		requestIDAttribute:                 "3c9c3d6b-8a10-4e7c-b3a1-0d4b8e7f2a11",
+		durationAttribute:                  int64(42),
+	}, attributesToMap(lr.Attributes()))
+}
+
+func TestMessageToLogsTCP(t *testing.T) {
+	msg := &alsv3.StreamAccessLogsMessage{
+		LogEntries: &alsv3.StreamAccessLogsMessage_TcpLogs{
+			TcpLogs: &alsv3.StreamAccessLogsMessage_TCPAccessLogEntries{
+				LogEntry: []*accesslogv3.TCPAccessLogEntry{{
+					CommonProperties: &accesslogv3.AccessLogCommon{
+						DownstreamRemoteAddress: socketAddress("10.0.0.7", 40000),
+						UpstreamCluster:         "postgres",
+					},
+					ConnectionProperties: &accesslogv3.ConnectionProperties{
+						ReceivedBytes: 1024,
+						SentBytes:     4096,
+					},
+				}},
+			},
+		},
+	}
+	// Only the first message of a stream carries the identifier.
+	ld := messageToLogs(testIdentifier(), msg)
+
+	require.Equal(t, 1, ld.LogRecordCount())
+	lr := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "http_access_log", lr.Name())
+	assert.True(t, lr.Body().IsNil())
+	assert.Equal(t, map[string]interface{}{
+		netPeerIPAttribute:       "10.0.0.7",
+		netPeerPortAttribute:     int64(40000),
+		upstreamClusterAttribute: "postgres",
+		bytesReceivedAttribute:   int64(1024),
+		bytesSentAttribute:       int64(4096),
+	}, attributesToMap(lr.Attributes()))
+}
+
+func TestMessageToLogsEmpty(t *testing.T) {
+	ld := messageToLogs(nil, &alsv3.StreamAccessLogsMessage{})
+	assert.Equal(t, 0, ld.LogRecordCount())
+	assert.Equal(t, 0, ld.ResourceLogs().At(0).Resource().Attributes().Len())
+}
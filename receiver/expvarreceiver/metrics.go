@@ -0,0 +1,206 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	memStatsVar = "memstats"
+	cmdlineVar  = "cmdline"
+
+	memStatsPrefix = "process.runtime.memstats."
+)
+
+// memStatsMetric describes how a field of runtime.MemStats is reported.
+type memStatsMetric struct {
+	name        string
+	description string
+	unit        string
+	cumulative  bool
+	value       func(*runtime.MemStats) uint64
+}
+
+var memStatsMetrics = []memStatsMetric{
+	{"alloc", "Bytes of allocated heap objects.", "By", false, func(m *runtime.MemStats) uint64 { return m.Alloc }},
+	{"total_alloc", "Cumulative bytes allocated for heap objects.", "By", true, func(m *runtime.MemStats) uint64 { return m.TotalAlloc }},
+	{"sys", "Total bytes of memory obtained from the OS.", "By", false, func(m *runtime.MemStats) uint64 { return m.Sys }},
+	{"lookups", "Number of pointer lookups performed by the runtime.", "1", true, func(m *runtime.MemStats) uint64 { return m.Lookups }},
+	{"mallocs", "Cumulative count of heap objects allocated.", "1", true, func(m *runtime.MemStats) uint64 { return m.Mallocs }},
+	{"frees", "Cumulative count of heap objects freed.", "1", true, func(m *runtime.MemStats) uint64 { return m.Frees }},
+	{"heap_alloc", "Bytes of allocated heap objects.", "By", false, func(m *runtime.MemStats) uint64 { return m.HeapAlloc }},
+	{"heap_sys", "Bytes of heap memory obtained from the OS.", "By", false, func(m *runtime.MemStats) uint64 { return m.HeapSys }},
+	{"heap_idle", "Bytes in idle (unused) spans.", "By", false, func(m *runtime.MemStats) uint64 { return m.HeapIdle }},
+	{"heap_inuse", "Bytes in in-use spans.", "By", false, func(m *runtime.MemStats) uint64 { return m.HeapInuse }},
+	{"heap_released", "Bytes of physical memory returned to the OS.", "By", false, func(m *runtime.MemStats) uint64 { return m.HeapReleased }},
+	{"heap_objects", "Number of allocated heap objects.", "1", false, func(m *runtime.MemStats) uint64 { return m.HeapObjects }},
+	{"stack_inuse", "Bytes in stack spans.", "By", false, func(m *runtime.MemStats) uint64 { return m.StackInuse }},
+	{"stack_sys", "Bytes of stack memory obtained from the OS.", "By", false, func(m *runtime.MemStats) uint64 { return m.StackSys }},
+	{"mspan_inuse", "Bytes of allocated mspan structures.", "By", false, func(m *runtime.MemStats) uint64 { return m.MSpanInuse }},
+	{"mspan_sys", "Bytes of memory obtained from the OS for mspan structures.", "By", false, func(m *runtime.MemStats) uint64 { return m.MSpanSys }},
+	{"mcache_inuse", "Bytes of allocated mcache structures.", "By", false, func(m *runtime.MemStats) uint64 { return m.MCacheInuse }},
+	{"mcache_sys", "Bytes of memory obtained from the OS for mcache structures.", "By", false, func(m *runtime.MemStats) uint64 { return m.MCacheSys }},
+	{"buck_hash_sys", "Bytes of memory in profiling bucket hash tables.", "By", false, func(m *runtime.MemStats) uint64 { return m.BuckHashSys }},
+	{"gc_sys", "Bytes of memory in garbage collection metadata.", "By", false, func(m *runtime.MemStats) uint64 { return m.GCSys }},
+	{"other_sys", "Bytes of memory in miscellaneous off-heap runtime allocations.", "By", false, func(m *runtime.MemStats) uint64 { return m.OtherSys }},
+	{"next_gc", "Target heap size of the next GC cycle.", "By", false, func(m *runtime.MemStats) uint64 { return m.NextGC }},
+	{"pause_total", "Cumulative nanoseconds in GC stop-the-world pauses.", "ns", true, func(m *runtime.MemStats) uint64 { return m.PauseTotalNs }},
+	{"num_gc", "Number of completed GC cycles.", "1", true, func(m *runtime.MemStats) uint64 { return uint64(m.NumGC) }},
+	{"num_forced_gc", "Number of GC cycles that were forced by the application.", "1", true, func(m *runtime.MemStats) uint64 { return uint64(m.NumForcedGC) }},
+}
+
+// buildMetrics converts the variables published by the expvar endpoint into
+// metrics. The "memstats" variable is mapped to the process.runtime.memstats.*
+// metrics and every other numeric variable, including the numeric values of
+// nested maps, is reported as a gauge named after its path in the document.
+func buildMetrics(vars map[string]json.RawMessage, prefix string, startTime, now time.Time) ([]*metricspb.Metric, []error) {
+	var metrics []*metricspb.Metric
+	var errs []error
+
+	nowTs := timestamppb.New(now)
+	if raw, ok := vars[memStatsVar]; ok {
+		memStats, err := buildMemStatsMetrics(raw, timestamppb.New(startTime), nowTs)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		metrics = append(metrics, memStats...)
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		if name != memStatsVar && name != cmdlineVar {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value, err := decodeVar(vars[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode variable %q: %w", name, err))
+			continue
+		}
+		metrics = appendVarMetrics(metrics, joinName(prefix, name), value, nowTs)
+	}
+
+	return metrics, errs
+}
+
+func buildMemStatsMetrics(raw json.RawMessage, start, now *timestamppb.Timestamp) ([]*metricspb.Metric, error) {
+	var memStats runtime.MemStats
+	if err := json.Unmarshal(raw, &memStats); err != nil {
+		return nil, fmt.Errorf("failed to decode memstats: %w", err)
+	}
+
+	metrics := make([]*metricspb.Metric, 0, len(memStatsMetrics)+1)
+	for _, m := range memStatsMetrics {
+		metricType := metricspb.MetricDescriptor_GAUGE_INT64
+		var startTimestamp *timestamppb.Timestamp
+		if m.cumulative {
+			metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+			startTimestamp = start
+		}
+		metrics = append(metrics, newMetric(memStatsPrefix+m.name, m.description, m.unit, metricType, startTimestamp, &metricspb.Point{
+			Timestamp: now,
+			Value:     &metricspb.Point_Int64Value{Int64Value: int64(m.value(&memStats))},
+		}))
+	}
+	metrics = append(metrics, newMetric(memStatsPrefix+"gc_cpu_fraction",
+		"Fraction of the available CPU time used by the GC since the program started.", "1",
+		metricspb.MetricDescriptor_GAUGE_DOUBLE, nil, &metricspb.Point{
+			Timestamp: now,
+			Value:     &metricspb.Point_DoubleValue{DoubleValue: memStats.GCCPUFraction},
+		}))
+	return metrics, nil
+}
+
+func decodeVar(raw json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	err := decoder.Decode(&value)
+	return value, err
+}
+
+// appendVarMetrics appends a gauge for every numeric value found in the given
+// variable. Strings, booleans and arrays are ignored.
+func appendVarMetrics(metrics []*metricspb.Metric, name string, value interface{}, now *timestamppb.Timestamp) []*metricspb.Metric {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return append(metrics, newMetric(name, "", "", metricspb.MetricDescriptor_GAUGE_INT64, nil, &metricspb.Point{
+				Timestamp: now,
+				Value:     &metricspb.Point_Int64Value{Int64Value: i},
+			}))
+		}
+		if f, err := v.Float64(); err == nil {
+			return append(metrics, newMetric(name, "", "", metricspb.MetricDescriptor_GAUGE_DOUBLE, nil, &metricspb.Point{
+				Timestamp: now,
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: f},
+			}))
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			metrics = appendVarMetrics(metrics, joinName(name, key), v[key], now)
+		}
+	}
+	return metrics
+}
+
+func joinName(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return strings.Join([]string{prefix, name}, ".")
+}
+
+func newMetric(
+	name string,
+	description string,
+	unit string,
+	metricType metricspb.MetricDescriptor_Type,
+	startTimestamp *timestamppb.Timestamp,
+	point *metricspb.Point,
+) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Type:        metricType,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				StartTimestamp: startTimestamp,
+				Points:         []*metricspb.Point{point},
+			},
+		},
+	}
+}
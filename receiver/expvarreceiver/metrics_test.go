@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	body, err := ioutil.ReadFile(path.Join(".", "testdata", "expvar.json"))
+	require.NoError(t, err)
+	var vars map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(body, &vars))
+
+	start := time.Unix(100, 0)
+	now := time.Unix(200, 0)
+	metrics, errs := buildMetrics(vars, "expvar", start, now)
+	require.Len(t, errs, 0)
+	require.Len(t, metrics, len(memStatsMetrics)+1+4)
+
+	byName := make(map[string]*metricspb.Metric)
+	for _, m := range metrics {
+		byName[m.GetMetricDescriptor().GetName()] = m
+	}
+
+	heapAlloc := byName["process.runtime.memstats.heap_alloc"]
+	require.NotNil(t, heapAlloc)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, heapAlloc.GetMetricDescriptor().GetType())
+	assert.Equal(t, "By", heapAlloc.GetMetricDescriptor().GetUnit())
+	assert.Nil(t, heapAlloc.GetTimeseries()[0].GetStartTimestamp())
+	assert.Equal(t, int64(1024), heapAlloc.GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+
+	numGC := byName["process.runtime.memstats.num_gc"]
+	require.NotNil(t, numGC)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, numGC.GetMetricDescriptor().GetType())
+	assert.Equal(t, timestamppb.New(start), numGC.GetTimeseries()[0].GetStartTimestamp())
+	assert.Equal(t, timestamppb.New(now), numGC.GetTimeseries()[0].GetPoints()[0].GetTimestamp())
+	assert.Equal(t, int64(2), numGC.GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+
+	gcCPUFraction := byName["process.runtime.memstats.gc_cpu_fraction"]
+	require.NotNil(t, gcCPUFraction)
+	assert.Equal(t, 0.001, gcCPUFraction.GetTimeseries()[0].GetPoints()[0].GetDoubleValue())
+
+	// User published variables are sorted and nested maps are flattened.
+	assert.Equal(t, []string{
+		"expvar.http.latency_p99",
+		"expvar.http.requests.200",
+		"expvar.http.requests.500",
+		"expvar.queue_depth",
+	}, []string{
+		metrics[len(metrics)-4].GetMetricDescriptor().GetName(),
+		metrics[len(metrics)-3].GetMetricDescriptor().GetName(),
+		metrics[len(metrics)-2].GetMetricDescriptor().GetName(),
+		metrics[len(metrics)-1].GetMetricDescriptor().GetName(),
+	})
+	assert.Equal(t, 0.25, byName["expvar.http.latency_p99"].GetTimeseries()[0].GetPoints()[0].GetDoubleValue())
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, byName["expvar.queue_depth"].GetMetricDescriptor().GetType())
+	assert.Equal(t, int64(7), byName["expvar.queue_depth"].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+}
+
+func TestBuildMetricsInvalidVars(t *testing.T) {
+	vars := map[string]json.RawMessage{
+		"memstats": json.RawMessage(`"invalid"`),
+		"broken":   json.RawMessage(`{`),
+		"valid":    json.RawMessage(`1`),
+	}
+
+	metrics, errs := buildMetrics(vars, "", time.Now(), time.Now())
+	assert.Len(t, errs, 2)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "valid", metrics[0].GetMetricDescriptor().GetName())
+}
@@ -0,0 +1,162 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "expvar"
+	transport  = "http"
+)
+
+var _ component.MetricsReceiver = (*expvarReceiver)(nil)
+
+// expvarReceiver implements the component.MetricsReceiver for Go expvar endpoints.
+type expvarReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       *http.Client
+	startTime    time.Time
+	cancel       context.CancelFunc
+}
+
+// newExpvarReceiver creates the expvar receiver with the given parameters.
+func newExpvarReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	client, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &expvarReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client:       client,
+	}
+	return r, nil
+}
+
+// Start begins scraping the configured expvar endpoint, right away and then
+// at each collection interval.
+func (r *expvarReceiver) Start(ctx context.Context, host component.Host) error {
+	r.startTime = time.Now()
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		r.scrapeAndLog(ctx)
+		for {
+			select {
+			case <-ticker.C:
+				r.scrapeAndLog(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the expvar receiver.
+func (r *expvarReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+// scrapeAndLog scrapes the endpoint once, logging the failures.
+func (r *expvarReceiver) scrapeAndLog(ctx context.Context) {
+	if err := r.scrape(ctx); err != nil {
+		r.logger.Error("Failed to scrape expvar endpoint",
+			zap.String("endpoint", r.config.Endpoint), zap.Error(err))
+	}
+}
+
+// scrape fetches the published variables once and sends the resulting
+// metrics to the next consumer.
+func (r *expvarReceiver) scrape(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	vars, err := r.fetchVars(ctx)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	metrics, errs := buildMetrics(vars, r.config.MetricPrefix, r.startTime, time.Now())
+	if len(errs) > 0 {
+		r.logger.Debug("Failed to convert some expvar variables", zap.Errors("errors", errs))
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: metrics,
+	}
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+func (r *expvarReceiver) fetchVars(ctx context.Context) (map[string]json.RawMessage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.config.Endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d from %s", resp.StatusCode, r.config.Endpoint)
+	}
+
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&vars); err != nil {
+		return nil, fmt.Errorf("failed to decode expvar response: %w", err)
+	}
+	return vars, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package expvarreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func newExpvarServer(t *testing.T) *httptest.Server {
+	body, err := ioutil.ReadFile(path.Join(".", "testdata", "expvar.json"))
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/vars" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestReceiverScrape(t *testing.T) {
+	server := newExpvarServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL + "/debug/vars"
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newExpvarReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*expvarReceiver)
+
+	require.NoError(t, r.scrape(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	names := make(map[string]bool)
+	for _, m := range ocmds[0].Metrics {
+		names[m.GetMetricDescriptor().GetName()] = true
+	}
+	assert.True(t, names["process.runtime.memstats.heap_alloc"])
+	assert.True(t, names["expvar.queue_depth"])
+	assert.True(t, names["expvar.http.requests.200"])
+	assert.False(t, names["expvar.version"])
+}
+
+func TestReceiverScrapeErrors(t *testing.T) {
+	server := newExpvarServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL + "/not/found"
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newExpvarReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*expvarReceiver)
+
+	assert.EqualError(t, r.scrape(context.Background()), "unexpected status code 404 from "+cfg.Endpoint)
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	server := newExpvarServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL + "/debug/vars"
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newExpvarReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestReceiverScrapesOnStart(t *testing.T) {
+	server := newExpvarServer(t)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL + "/debug/vars"
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newExpvarReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	// The first scrape doesn't wait for the default collection interval
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
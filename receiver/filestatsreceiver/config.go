@@ -0,0 +1,37 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the file stats receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the files are inspected.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Include is the list of glob patterns of the files to watch. See
+	// https://golang.org/pkg/path/filepath/#Match for the pattern syntax.
+	Include []string `mapstructure:"include"`
+
+	// Exclude is the list of glob patterns of the files to ignore among the
+	// ones matching Include.
+	Exclude []string `mapstructure:"exclude"`
+}
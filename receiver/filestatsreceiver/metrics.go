@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	filePathLabel = "file.path"
+	patternLabel  = "file.pattern"
+)
+
+// buildMetrics converts the collected file information into metrics. Files
+// matching several patterns are reported once.
+func buildMetrics(stats []patternStats, now time.Time) []*metricspb.Metric {
+	ts := timestamppb.New(now)
+
+	size := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "file.size",
+			Description: "Size of the file.",
+			Unit:        "By",
+			Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys:   []*metricspb.LabelKey{{Key: filePathLabel}},
+		},
+	}
+	age := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "file.mtime.age",
+			Description: "Time elapsed since the last modification of the file.",
+			Unit:        "s",
+			Type:        metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			LabelKeys:   []*metricspb.LabelKey{{Key: filePathLabel}},
+		},
+	}
+	count := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "file.count",
+			Description: "Number of files matching the pattern.",
+			Unit:        "1",
+			Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys:   []*metricspb.LabelKey{{Key: patternLabel}},
+		},
+	}
+
+	seen := make(map[string]bool)
+	for _, ps := range stats {
+		count.Timeseries = append(count.Timeseries, timeSeries(ps.pattern, &metricspb.Point{
+			Timestamp: ts,
+			Value:     &metricspb.Point_Int64Value{Int64Value: int64(len(ps.files))},
+		}))
+
+		for _, f := range ps.files {
+			if seen[f.path] {
+				continue
+			}
+			seen[f.path] = true
+
+			size.Timeseries = append(size.Timeseries, timeSeries(f.path, &metricspb.Point{
+				Timestamp: ts,
+				Value:     &metricspb.Point_Int64Value{Int64Value: f.size},
+			}))
+			age.Timeseries = append(age.Timeseries, timeSeries(f.path, &metricspb.Point{
+				Timestamp: ts,
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: now.Sub(f.modTime).Seconds()},
+			}))
+		}
+	}
+
+	metrics := []*metricspb.Metric{count}
+	if len(size.Timeseries) > 0 {
+		metrics = append(metrics, size, age)
+	}
+	return metrics
+}
+
+func timeSeries(labelValue string, point *metricspb.Point) *metricspb.TimeSeries {
+	return &metricspb.TimeSeries{
+		LabelValues: []*metricspb.LabelValue{{Value: labelValue, HasValue: true}},
+		Points:      []*metricspb.Point{point},
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "filestats"
+	transport  = "filesystem"
+)
+
+var _ component.MetricsReceiver = (*fileStatsReceiver)(nil)
+
+// fileStatsReceiver implements the component.MetricsReceiver for the
+// statistics of the files matching the configured patterns.
+type fileStatsReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	stat         statFunc
+	cancel       context.CancelFunc
+}
+
+// newFileStatsReceiver creates the file stats receiver with the given parameters.
+func newFileStatsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	if len(config.Include) == 0 {
+		return nil, fmt.Errorf("no include patterns configured for receiver %q", config.Name())
+	}
+	if err := validatePatterns(config.Include); err != nil {
+		return nil, err
+	}
+	if err := validatePatterns(config.Exclude); err != nil {
+		return nil, err
+	}
+
+	r := &fileStatsReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		stat:         os.Stat,
+	}
+	return r, nil
+}
+
+// Start begins collecting the statistics of the watched files.
+func (r *fileStatsReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect file statistics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the file stats receiver.
+func (r *fileStatsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *fileStatsReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	stats, err := collectStats(r.config.Include, r.config.Exclude, r.stat)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: buildMetrics(stats, time.Now()),
+	}
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
@@ -0,0 +1,77 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	createFiles(t, dir, 42, time.Hour, "a.csv")
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Include = []string{filepath.Join(dir, "*.csv")}
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newFileStatsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*fileStatsReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	metrics := ocmds[0].Metrics
+	require.Len(t, metrics, 3)
+	assert.Equal(t, int64(1), metrics[0].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+	assert.Equal(t, int64(42), metrics[1].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+	assert.InDelta(t, 3600, metrics[2].GetTimeseries()[0].GetPoints()[0].GetDoubleValue(), 60)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	cfg.Include = []string{filepath.Join(dir, "*.csv")}
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newFileStatsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
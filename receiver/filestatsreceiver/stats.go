@@ -0,0 +1,96 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// fileStats holds the information reported for a single file.
+type fileStats struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// patternStats holds the files matching a single include pattern.
+type patternStats struct {
+	pattern string
+	files   []fileStats
+}
+
+// statFunc returns the file information of the named file.
+type statFunc func(name string) (os.FileInfo, error)
+
+// validatePatterns reports an error if any of the patterns is malformed.
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// collectStats returns, for every include pattern, the regular files matching
+// it and none of the exclude patterns. Files that disappear between the glob
+// and the stat are ignored.
+func collectStats(include, exclude []string, stat statFunc) ([]patternStats, error) {
+	result := make([]patternStats, 0, len(include))
+	for _, pattern := range include {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		sort.Strings(matches)
+
+		ps := patternStats{pattern: pattern}
+		for _, match := range matches {
+			if excluded(match, exclude) {
+				continue
+			}
+			info, err := stat(match)
+			if err != nil {
+				if os.IsNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+			ps.files = append(ps.files, fileStats{
+				path:    match,
+				size:    info.Size(),
+				modTime: info.ModTime(),
+			})
+		}
+		result = append(result, ps)
+	}
+	return result, nil
+}
+
+func excluded(path string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
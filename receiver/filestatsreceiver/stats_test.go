@@ -0,0 +1,134 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestatsreceiver
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createFiles creates the named files in dir, each containing size bytes and
+// modified age ago.
+func createFiles(t *testing.T, dir string, size int, age time.Duration, names ...string) {
+	modTime := time.Now().Add(-age)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, ioutil.WriteFile(path, make([]byte, size), 0600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+}
+
+func TestCollectStats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	createFiles(t, dir, 10, time.Hour, "a.csv", "b.csv", "tmp-c.csv")
+	createFiles(t, dir, 20, time.Minute, "d.log")
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "e.csv"), 0700))
+
+	include := []string{filepath.Join(dir, "*.csv"), filepath.Join(dir, "*.log"), filepath.Join(dir, "*.txt")}
+	exclude := []string{filepath.Join(dir, "tmp-*")}
+	stats, err := collectStats(include, exclude, os.Stat)
+	require.NoError(t, err)
+	require.Len(t, stats, 3)
+
+	paths := func(ps patternStats) []string {
+		var result []string
+		for _, f := range ps.files {
+			result = append(result, filepath.Base(f.path))
+		}
+		return result
+	}
+	assert.Equal(t, include[0], stats[0].pattern)
+	assert.Equal(t, []string{"a.csv", "b.csv"}, paths(stats[0]))
+	assert.Equal(t, int64(10), stats[0].files[0].size)
+	assert.Equal(t, []string{"d.log"}, paths(stats[1]))
+	assert.Equal(t, int64(20), stats[1].files[0].size)
+	assert.Empty(t, stats[2].files)
+}
+
+func TestCollectStatsErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "filestats")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	createFiles(t, dir, 1, 0, "a.csv")
+	include := []string{filepath.Join(dir, "*.csv")}
+
+	stats, err := collectStats(include, nil, func(string) (os.FileInfo, error) {
+		return nil, os.ErrNotExist
+	})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Empty(t, stats[0].files)
+
+	_, err = collectStats(include, nil, func(string) (os.FileInfo, error) {
+		return nil, errors.New("permission denied")
+	})
+	assert.EqualError(t, err, "permission denied")
+}
+
+func TestBuildMetrics(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	stats := []patternStats{
+		{
+			pattern: "/data/*.csv",
+			files: []fileStats{
+				{path: "/data/a.csv", size: 100, modTime: now.Add(-90 * time.Second)},
+				{path: "/data/b.csv", size: 200, modTime: now.Add(-time.Hour)},
+			},
+		},
+		{
+			pattern: "/data/a.*",
+			files: []fileStats{
+				{path: "/data/a.csv", size: 100, modTime: now.Add(-90 * time.Second)},
+			},
+		},
+	}
+
+	metrics := buildMetrics(stats, now)
+	require.Len(t, metrics, 3)
+
+	values := func(m *metricspb.Metric) map[string]float64 {
+		result := make(map[string]float64)
+		for _, ts := range m.GetTimeseries() {
+			p := ts.GetPoints()[0]
+			result[ts.GetLabelValues()[0].GetValue()] = p.GetDoubleValue() + float64(p.GetInt64Value())
+		}
+		return result
+	}
+	assert.Equal(t, "file.count", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"/data/*.csv": 2, "/data/a.*": 1}, values(metrics[0]))
+	assert.Equal(t, "file.size", metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"/data/a.csv": 100, "/data/b.csv": 200}, values(metrics[1]))
+	assert.Equal(t, "file.mtime.age", metrics[2].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"/data/a.csv": 90, "/data/b.csv": 3600}, values(metrics[2]))
+}
+
+func TestBuildMetricsNoMatch(t *testing.T) {
+	metrics := buildMetrics([]patternStats{{pattern: "/data/*.csv"}}, time.Now())
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "file.count", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, int64(0), metrics[0].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+}
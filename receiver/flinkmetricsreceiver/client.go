@@ -0,0 +1,158 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flinkmetricsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	jobManagerMetricsPath  = "/jobmanager/metrics"
+	taskManagersPath       = "/taskmanagers"
+	taskManagerMetricsPath = "/taskmanagers/%s/metrics"
+	jobsOverviewPath       = "/jobs/overview"
+	jobMetricsPath         = "/jobs/%s/metrics"
+
+	jobStateRunning = "RUNNING"
+)
+
+// metricValue is an element of the responses of the metrics endpoints, Flink
+// reports all the values as strings.
+type metricValue struct {
+	ID    string `json:"id"`
+	Value string `json:"value"`
+}
+
+type taskManagersResponse struct {
+	TaskManagers []taskManager `json:"taskmanagers"`
+}
+
+type taskManager struct {
+	ID string `json:"id"`
+}
+
+type jobsOverviewResponse struct {
+	Jobs []job `json:"jobs"`
+}
+
+type job struct {
+	ID    string `json:"jid"`
+	Name  string `json:"name"`
+	State string `json:"state"`
+	// StartTime is the start time of the job in milliseconds since the epoch.
+	StartTime int64 `json:"start-time"`
+}
+
+// client retrieves the metrics of a Flink cluster.
+type client interface {
+	jobManagerMetrics(ctx context.Context, ids []string) (map[string]string, error)
+	taskManagers(ctx context.Context) ([]taskManager, error)
+	taskManagerMetrics(ctx context.Context, id string, ids []string) (map[string]string, error)
+	jobs(ctx context.Context) ([]job, error)
+	jobMetrics(ctx context.Context, id string, ids []string) (map[string]string, error)
+}
+
+type restClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+var _ client = (*restClient)(nil)
+
+func newRESTClient(config *Config) (*restClient, error) {
+	httpClient, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+	return &restClient{
+		client:   httpClient,
+		endpoint: strings.TrimSuffix(config.Endpoint, "/"),
+	}, nil
+}
+
+func (c *restClient) jobManagerMetrics(ctx context.Context, ids []string) (map[string]string, error) {
+	return c.getMetrics(ctx, jobManagerMetricsPath, ids)
+}
+
+func (c *restClient) taskManagers(ctx context.Context) ([]taskManager, error) {
+	var resp taskManagersResponse
+	if err := c.get(ctx, taskManagersPath, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.TaskManagers, nil
+}
+
+func (c *restClient) taskManagerMetrics(ctx context.Context, id string, ids []string) (map[string]string, error) {
+	return c.getMetrics(ctx, fmt.Sprintf(taskManagerMetricsPath, url.PathEscape(id)), ids)
+}
+
+func (c *restClient) jobs(ctx context.Context) ([]job, error) {
+	var resp jobsOverviewResponse
+	if err := c.get(ctx, jobsOverviewPath, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Jobs, nil
+}
+
+func (c *restClient) jobMetrics(ctx context.Context, id string, ids []string) (map[string]string, error) {
+	return c.getMetrics(ctx, fmt.Sprintf(jobMetricsPath, url.PathEscape(id)), ids)
+}
+
+// getMetrics returns the values of the metrics of the given ids, the
+// metrics unknown to Flink are omitted.
+func (c *restClient) getMetrics(ctx context.Context, path string, ids []string) (map[string]string, error) {
+	query := url.Values{"get": {strings.Join(ids, ",")}}
+	var values []metricValue
+	if err := c.get(ctx, path, query, &values); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(values))
+	for _, v := range values {
+		result[v.ID] = v.Value
+	}
+	return result, nil
+}
+
+func (c *restClient) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.endpoint + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flinkmetricsreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTaskManagerID = "172.18.0.3:36345-b9a592"
+	testJobID         = "5f1c8c5e4b3b0f2a7e6d9c8b7a6f5e4d"
+)
+
+// startServer starts a fake Flink REST API serving the responses of
+// testdata.
+func startServer(t *testing.T) string {
+	mux := http.NewServeMux()
+	serveFile := func(file string, metrics bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if metrics && r.URL.Query().Get("get") == "" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, err := ioutil.ReadFile(path.Join(".", "testdata", file))
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		}
+	}
+	mux.HandleFunc(jobManagerMetricsPath, serveFile("jobmanager_metrics.json", true))
+	mux.HandleFunc(taskManagersPath, serveFile("taskmanagers.json", false))
+	mux.HandleFunc(fmt.Sprintf(taskManagerMetricsPath, testTaskManagerID), serveFile("taskmanager_metrics.json", true))
+	mux.HandleFunc(jobsOverviewPath, serveFile("jobs_overview.json", false))
+	mux.HandleFunc(fmt.Sprintf(jobMetricsPath, testJobID), serveFile("job_metrics.json", true))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newTestClient(t *testing.T, endpoint string) *restClient {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint + "/"
+	c, err := newRESTClient(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+
+	values, err := c.jobManagerMetrics(context.Background(), metricIDs(jobManagerMetrics))
+	require.NoError(t, err)
+	assert.Equal(t, "0.0125", values["Status.JVM.CPU.Load"])
+	assert.Equal(t, "4", values["taskSlotsTotal"])
+
+	taskManagers, err := c.taskManagers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []taskManager{{ID: testTaskManagerID}}, taskManagers)
+
+	values, err = c.taskManagerMetrics(context.Background(), testTaskManagerID, metricIDs(taskManagerMetrics))
+	require.NoError(t, err)
+	assert.Equal(t, "64", values["Status.JVM.Threads.Count"])
+
+	jobs, err := c.jobs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []job{
+		{ID: testJobID, Name: "WordCount", State: "RUNNING", StartTime: 1600000000000},
+		{ID: "0a1b2c3d4e5f60718293a4b5c6d7e8f9", Name: "Backfill", State: "FINISHED", StartTime: 1599990000000},
+	}, jobs)
+
+	values, err = c.jobMetrics(context.Background(), testJobID, metricIDs(jobMetrics))
+	require.NoError(t, err)
+	assert.Equal(t, "120", values["numberOfCompletedCheckpoints"])
+}
+
+func TestClientErrors(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+	_, err := c.jobMetrics(context.Background(), "unknown", metricIDs(jobMetrics))
+	assert.EqualError(t, err, "request to /jobs/unknown/metrics failed: 404 Not Found")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer server.Close()
+	c = newTestClient(t, server.URL)
+	_, err = c.taskManagers(context.Background())
+	assert.EqualError(t, err, "failed to decode response of /taskmanagers: invalid character '<' looking for beginning of value")
+}
+
+func TestMetricIDs(t *testing.T) {
+	assert.Equal(t, []string{"a", "b"}, metricIDs([]*flinkMetric{{id: "a"}, {id: "b"}, {id: "a"}}))
+}
@@ -0,0 +1,261 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flinkmetricsreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	taskManagerIDLabel = "flink.taskmanager.id"
+	jobIDLabel         = "flink.job.id"
+	jobNameLabel       = "flink.job.name"
+)
+
+// label is a label of a flinkMetric.
+type label struct {
+	key   string
+	value string
+}
+
+// flinkMetric defines how a metric reported by Flink is converted.
+type flinkMetric struct {
+	id     string
+	name   string
+	unit   string
+	desc   string
+	labels []label
+	mdType metricspb.MetricDescriptor_Type
+}
+
+// jvmMetrics returns the JVM metrics of the job manager or of a task manager.
+func jvmMetrics(component string) []*flinkMetric {
+	prefix := "flink." + component + ".jvm."
+	return []*flinkMetric{
+		{
+			id:     "Status.JVM.CPU.Load",
+			name:   prefix + "cpu.load",
+			unit:   "1",
+			desc:   "Recent CPU usage of the JVM.",
+			mdType: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+		},
+		{
+			id:     "Status.JVM.Memory.Heap.Used",
+			name:   prefix + "memory.heap.used",
+			unit:   "By",
+			desc:   "Heap memory used by the JVM.",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			id:     "Status.JVM.Memory.Heap.Max",
+			name:   prefix + "memory.heap.max",
+			unit:   "By",
+			desc:   "Maximum heap memory of the JVM.",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			id:     "Status.JVM.Memory.NonHeap.Used",
+			name:   prefix + "memory.nonheap.used",
+			unit:   "By",
+			desc:   "Non-heap memory used by the JVM.",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			id:     "Status.JVM.Threads.Count",
+			name:   prefix + "threads.count",
+			unit:   "1",
+			desc:   "Number of live threads of the JVM.",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+	}
+}
+
+var jobManagerMetrics = append(jvmMetrics("jobmanager"), []*flinkMetric{
+	{
+		id:     "numRegisteredTaskManagers",
+		name:   "flink.jobmanager.taskmanagers.registered",
+		unit:   "1",
+		desc:   "Number of registered task managers.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "numRunningJobs",
+		name:   "flink.jobmanager.jobs.running",
+		unit:   "1",
+		desc:   "Number of running jobs.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "taskSlotsAvailable",
+		name:   "flink.jobmanager.slots.available",
+		unit:   "1",
+		desc:   "Number of available task slots.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "taskSlotsTotal",
+		name:   "flink.jobmanager.slots.total",
+		unit:   "1",
+		desc:   "Total number of task slots.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+}...)
+
+var taskManagerMetrics = jvmMetrics("taskmanager")
+
+var jobMetrics = []*flinkMetric{
+	{
+		id:     "uptime",
+		name:   "flink.job.uptime",
+		unit:   "ms",
+		desc:   "Time since the job started running without interruption.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "numRestarts",
+		name:   "flink.job.restart.count",
+		unit:   "1",
+		desc:   "Number of restarts of the job.",
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		id:     "numberOfCompletedCheckpoints",
+		name:   "flink.job.checkpoint.count",
+		unit:   "1",
+		desc:   "Number of checkpoints of the job.",
+		labels: []label{{"checkpoint", "completed"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		id:     "numberOfFailedCheckpoints",
+		name:   "flink.job.checkpoint.count",
+		unit:   "1",
+		desc:   "Number of checkpoints of the job.",
+		labels: []label{{"checkpoint", "failed"}},
+		mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	},
+	{
+		id:     "numberOfInProgressCheckpoints",
+		name:   "flink.job.checkpoint.in_progress",
+		unit:   "1",
+		desc:   "Number of checkpoints of the job in progress.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "lastCheckpointDuration",
+		name:   "flink.job.last_checkpoint.duration",
+		unit:   "ms",
+		desc:   "Duration of the last checkpoint of the job.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+	{
+		id:     "lastCheckpointSize",
+		name:   "flink.job.last_checkpoint.size",
+		unit:   "By",
+		desc:   "Size of the last checkpoint of the job.",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	},
+}
+
+// metricIDs returns the ids of the metrics, to be requested from Flink.
+func metricIDs(defs []*flinkMetric) []string {
+	ids := make([]string, 0, len(defs))
+	seen := make(map[string]bool, len(defs))
+	for _, def := range defs {
+		if !seen[def.id] {
+			seen[def.id] = true
+			ids = append(ids, def.id)
+		}
+	}
+	return ids
+}
+
+// metricsBuilder accumulates the time series of the metrics, merging the
+// values reported under the same metric name.
+type metricsBuilder struct {
+	now     *timestamppb.Timestamp
+	metrics []*metricspb.Metric
+	byName  map[string]*metricspb.Metric
+	errs    []error
+}
+
+func newMetricsBuilder(now time.Time) *metricsBuilder {
+	return &metricsBuilder{
+		now:    timestamppb.New(now),
+		byName: make(map[string]*metricspb.Metric),
+	}
+}
+
+// add converts the values into metrics, start being the start timestamp of
+// the cumulative ones. The metrics not reported by Flink, for instance
+// because of its version, are ignored.
+func (b *metricsBuilder) add(defs []*flinkMetric, values map[string]string, start *timestamppb.Timestamp, extraLabels ...label) {
+	for _, def := range defs {
+		raw, ok := values[def.id]
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			b.errs = append(b.errs, fmt.Errorf("metric %q: %w", def.id, err))
+			continue
+		}
+
+		labels := append(append([]label(nil), extraLabels...), def.labels...)
+		metric, ok := b.byName[def.name]
+		if !ok {
+			labelKeys := make([]*metricspb.LabelKey, 0, len(labels))
+			for _, l := range labels {
+				labelKeys = append(labelKeys, &metricspb.LabelKey{Key: l.key})
+			}
+			metric = &metricspb.Metric{
+				MetricDescriptor: &metricspb.MetricDescriptor{
+					Name:        def.name,
+					Description: def.desc,
+					Unit:        def.unit,
+					Type:        def.mdType,
+					LabelKeys:   labelKeys,
+				},
+			}
+			b.byName[def.name] = metric
+			b.metrics = append(b.metrics, metric)
+		}
+
+		labelValues := make([]*metricspb.LabelValue, 0, len(labels))
+		for _, l := range labels {
+			labelValues = append(labelValues, &metricspb.LabelValue{Value: l.value, HasValue: true})
+		}
+		point := &metricspb.Point{Timestamp: b.now}
+		switch def.mdType {
+		case metricspb.MetricDescriptor_GAUGE_DOUBLE, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+			point.Value = &metricspb.Point_DoubleValue{DoubleValue: value}
+		default:
+			point.Value = &metricspb.Point_Int64Value{Int64Value: int64(value)}
+		}
+		timeseries := &metricspb.TimeSeries{
+			LabelValues: labelValues,
+			Points:      []*metricspb.Point{point},
+		}
+		if def.mdType == metricspb.MetricDescriptor_CUMULATIVE_INT64 || def.mdType == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
+			timeseries.StartTimestamp = start
+		}
+		metric.Timeseries = append(metric.Timeseries, timeseries)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flinkmetricsreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	dataFormat = "flink"
+	transport  = "http"
+)
+
+var _ component.MetricsReceiver = (*flinkMetricsReceiver)(nil)
+
+// flinkMetricsReceiver implements the component.MetricsReceiver for Flink.
+type flinkMetricsReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       client
+	cancel       context.CancelFunc
+}
+
+// newFlinkMetricsReceiver creates the Flink metrics receiver with the given
+// parameters.
+func newFlinkMetricsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	c, err := newRESTClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &flinkMetricsReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client:       c,
+	}
+	return r, nil
+}
+
+// Start begins collecting the metrics of the Flink cluster.
+func (r *flinkMetricsReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect Flink metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the Flink metrics receiver.
+func (r *flinkMetricsReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *flinkMetricsReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	md, err := r.buildMetricsData(ctx)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+// buildMetricsData collects the metrics of the cluster. It fails if the job
+// manager cannot be queried, the failures to query the task managers or jobs
+// are logged and their metrics omitted.
+func (r *flinkMetricsReceiver) buildMetricsData(ctx context.Context) (consumerdata.MetricsData, error) {
+	b := newMetricsBuilder(time.Now())
+
+	values, err := r.client.jobManagerMetrics(ctx, metricIDs(jobManagerMetrics))
+	if err != nil {
+		return consumerdata.MetricsData{}, err
+	}
+	b.add(jobManagerMetrics, values, nil)
+
+	var errs []error
+	if taskManagers, err := r.client.taskManagers(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, tm := range taskManagers {
+			values, err := r.client.taskManagerMetrics(ctx, tm.ID, metricIDs(taskManagerMetrics))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("task manager %q: %w", tm.ID, err))
+				continue
+			}
+			b.add(taskManagerMetrics, values, nil, label{taskManagerIDLabel, tm.ID})
+		}
+	}
+
+	if jobs, err := r.client.jobs(ctx); err != nil {
+		errs = append(errs, err)
+	} else {
+		for _, j := range jobs {
+			if j.State != jobStateRunning {
+				continue
+			}
+			values, err := r.client.jobMetrics(ctx, j.ID, metricIDs(jobMetrics))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("job %q: %w", j.ID, err))
+				continue
+			}
+			start := timestamppb.New(time.Unix(0, j.StartTime*int64(time.Millisecond)))
+			b.add(jobMetrics, values, start, label{jobIDLabel, j.ID}, label{jobNameLabel, j.Name})
+		}
+	}
+
+	errs = append(errs, b.errs...)
+	if len(errs) > 0 {
+		r.logger.Warn("Failed to collect some Flink metrics", zap.Errors("errors", errs))
+	}
+
+	return consumerdata.MetricsData{Metrics: b.metrics}, nil
+}
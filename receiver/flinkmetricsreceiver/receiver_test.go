@@ -0,0 +1,118 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flinkmetricsreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t)
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newFlinkMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*flinkMetricsReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	metrics := make(map[string]int)
+	for _, metric := range ocmds[0].Metrics {
+		metrics[metric.MetricDescriptor.Name] = len(metric.Timeseries)
+	}
+	assert.Equal(t, map[string]int{
+		"flink.jobmanager.jvm.cpu.load":             1,
+		"flink.jobmanager.jvm.memory.heap.used":     1,
+		"flink.jobmanager.jvm.memory.heap.max":      1,
+		"flink.jobmanager.jvm.memory.nonheap.used":  1,
+		"flink.jobmanager.jvm.threads.count":        1,
+		"flink.jobmanager.taskmanagers.registered":  1,
+		"flink.jobmanager.jobs.running":             1,
+		"flink.jobmanager.slots.available":          1,
+		"flink.jobmanager.slots.total":              1,
+		"flink.taskmanager.jvm.cpu.load":            1,
+		"flink.taskmanager.jvm.memory.heap.used":    1,
+		"flink.taskmanager.jvm.memory.heap.max":     1,
+		"flink.taskmanager.jvm.memory.nonheap.used": 1,
+		"flink.taskmanager.jvm.threads.count":       1,
+		"flink.job.uptime":                          1,
+		"flink.job.restart.count":                   1,
+		"flink.job.checkpoint.count":                2,
+		"flink.job.checkpoint.in_progress":          1,
+		"flink.job.last_checkpoint.duration":        1,
+		"flink.job.last_checkpoint.size":            1,
+	}, metrics)
+
+	for _, metric := range ocmds[0].Metrics {
+		switch metric.MetricDescriptor.Name {
+		case "flink.taskmanager.jvm.cpu.load":
+			assert.Equal(t, taskManagerIDLabel, metric.MetricDescriptor.LabelKeys[0].Key)
+			assert.Equal(t, testTaskManagerID, metric.Timeseries[0].LabelValues[0].Value)
+			assert.Equal(t, 0.25, metric.Timeseries[0].Points[0].GetDoubleValue())
+		case "flink.job.restart.count":
+			require.Len(t, metric.MetricDescriptor.LabelKeys, 2)
+			assert.Equal(t, testJobID, metric.Timeseries[0].LabelValues[0].Value)
+			assert.Equal(t, "WordCount", metric.Timeseries[0].LabelValues[1].Value)
+			assert.Equal(t, int64(1600000000), metric.Timeseries[0].StartTimestamp.Seconds)
+			assert.Equal(t, int64(2), metric.Timeseries[0].Points[0].GetInt64Value())
+		}
+	}
+}
+
+func TestReceiverCollectError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newFlinkMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*flinkMetricsReceiver)
+
+	assert.EqualError(t, r.collect(context.Background()), "request to /jobmanager/metrics failed: 404 Not Found")
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newFlinkMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
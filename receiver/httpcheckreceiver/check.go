@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcheckreceiver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// target probes a single HTTP endpoint.
+type target struct {
+	endpoint string
+	method   string
+	client   *http.Client
+}
+
+// checkResult holds the outcome of a single probe.
+type checkResult struct {
+	endpoint   string
+	method     string
+	err        error
+	statusCode int
+	duration   time.Duration
+	// certNotAfter is the earliest expiry of the certificates presented by
+	// the server, it is zero if the connection didn't use TLS.
+	certNotAfter time.Time
+}
+
+// up reports whether the endpoint is considered available, i.e. it answered
+// with a non error status code.
+func (r *checkResult) up() bool {
+	return r.err == nil && r.statusCode < http.StatusBadRequest
+}
+
+func newTarget(config TargetConfig) (*target, error) {
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", config.Endpoint, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid endpoint %q: unsupported scheme %q", config.Endpoint, u.Scheme)
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = defaultTimeout
+	}
+	client, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+	// The probe reports the status of the endpoint itself, not of the one it
+	// redirects to.
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	method := strings.ToUpper(config.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	return &target{
+		endpoint: config.Endpoint,
+		method:   method,
+		client:   client,
+	}, nil
+}
+
+// check performs a single request against the target. Failing to reach the
+// endpoint is reported in the result rather than as an error.
+func (t *target) check(ctx context.Context) *checkResult {
+	result := &checkResult{
+		endpoint: t.endpoint,
+		method:   t.method,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, t.method, t.endpoint, nil)
+	if err != nil {
+		result.err = err
+		return result
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		result.duration = time.Since(start)
+		result.err = err
+		return result
+	}
+	// Read the body so the duration includes the transfer of the response.
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	result.duration = time.Since(start)
+	result.err = err
+	result.statusCode = resp.StatusCode
+
+	if resp.TLS != nil {
+		for _, cert := range resp.TLS.PeerCertificates {
+			if result.certNotAfter.IsZero() || cert.NotAfter.Before(result.certNotAfter) {
+				result.certNotAfter = cert.NotAfter
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcheckreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+func TestTargetCheck(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			assert.Equal(t, http.MethodHead, r.Method)
+			assert.Equal(t, "collector", r.Header.Get("x-probe"))
+			w.WriteHeader(http.StatusNoContent)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		config     TargetConfig
+		wantUp     bool
+		wantStatus int
+	}{
+		{
+			name: "ok",
+			config: TargetConfig{
+				HTTPClientSettings: confighttp.HTTPClientSettings{
+					Endpoint: srv.URL + "/ok",
+					Headers:  map[string]string{"x-probe": "collector"},
+				},
+				Method: "head",
+			},
+			wantUp:     true,
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "redirect not followed",
+			config: TargetConfig{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: srv.URL + "/redirect"},
+			},
+			wantUp:     true,
+			wantStatus: http.StatusFound,
+		},
+		{
+			name: "error status",
+			config: TargetConfig{
+				HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: srv.URL + "/unavailable"},
+			},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tgt, err := newTarget(tt.config)
+			require.NoError(t, err)
+
+			result := tgt.check(context.Background())
+			assert.NoError(t, result.err)
+			assert.Equal(t, tt.wantUp, result.up())
+			assert.Equal(t, tt.wantStatus, result.statusCode)
+			assert.True(t, result.duration > 0)
+			assert.True(t, result.certNotAfter.IsZero())
+		})
+	}
+}
+
+func TestTargetCheckUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	endpoint := srv.URL
+	srv.Close()
+
+	tgt, err := newTarget(TargetConfig{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: endpoint, Timeout: time.Second},
+	})
+	require.NoError(t, err)
+
+	result := tgt.check(context.Background())
+	assert.Error(t, result.err)
+	assert.False(t, result.up())
+	assert.Equal(t, 0, result.statusCode)
+}
+
+func TestTargetCheckTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	tgt, err := newTarget(TargetConfig{
+		HTTPClientSettings: confighttp.HTTPClientSettings{Endpoint: srv.URL},
+	})
+	require.NoError(t, err)
+	tgt.client.Transport = srv.Client().Transport
+
+	result := tgt.check(context.Background())
+	require.NoError(t, result.err)
+	assert.False(t, result.up())
+	assert.Equal(t, http.StatusNotFound, result.statusCode)
+	assert.Equal(t, srv.Certificate().NotAfter, result.certNotAfter)
+}
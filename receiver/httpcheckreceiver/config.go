@@ -0,0 +1,41 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcheckreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the HTTP check receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the targets are probed.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Targets is the list of endpoints to probe.
+	Targets []TargetConfig `mapstructure:"targets"`
+}
+
+// TargetConfig defines the configuration of a single probed endpoint.
+type TargetConfig struct {
+	confighttp.HTTPClientSettings `mapstructure:",squash"`
+
+	// Method is the HTTP method of the probe request. Defaults to GET.
+	Method string `mapstructure:"method"`
+}
@@ -0,0 +1,92 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcheckreceiver
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var labelKeys = []*metricspb.LabelKey{{Key: "http.url"}, {Key: "http.method"}}
+
+// buildMetrics converts the probe results into metrics.
+func buildMetrics(results []*checkResult, now time.Time) []*metricspb.Metric {
+	ts := timestamppb.New(now)
+
+	up := newMetric("httpcheck.up", "Whether the endpoint answered with a non error status code (1) or not (0).", "1", metricspb.MetricDescriptor_GAUGE_INT64)
+	duration := newMetric("httpcheck.duration", "Time taken by the probe request, including the transfer of the response.", "s", metricspb.MetricDescriptor_GAUGE_DOUBLE)
+	statusCode := newMetric("httpcheck.status_code", "Status code of the response.", "1", metricspb.MetricDescriptor_GAUGE_INT64)
+	certExpiry := newMetric("httpcheck.tls.cert_expiry", "Days left before the earliest expiry of the certificates presented by the endpoint.", "d", metricspb.MetricDescriptor_GAUGE_DOUBLE)
+
+	for _, r := range results {
+		labelValues := []*metricspb.LabelValue{
+			{Value: r.endpoint, HasValue: true},
+			{Value: r.method, HasValue: true},
+		}
+
+		upValue := int64(0)
+		if r.up() {
+			upValue = 1
+		}
+		addInt64Point(up, labelValues, upValue, ts)
+		addDoublePoint(duration, labelValues, r.duration.Seconds(), ts)
+		if r.statusCode != 0 {
+			addInt64Point(statusCode, labelValues, int64(r.statusCode), ts)
+		}
+		if !r.certNotAfter.IsZero() {
+			addDoublePoint(certExpiry, labelValues, r.certNotAfter.Sub(now).Hours()/24, ts)
+		}
+	}
+
+	metrics := make([]*metricspb.Metric, 0, 4)
+	for _, m := range []*metricspb.Metric{up, duration, statusCode, certExpiry} {
+		if len(m.Timeseries) > 0 {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+func newMetric(name, description, unit string, metricType metricspb.MetricDescriptor_Type) *metricspb.Metric {
+	return &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        name,
+			Description: description,
+			Unit:        unit,
+			Type:        metricType,
+			LabelKeys:   labelKeys,
+		},
+	}
+}
+
+func addInt64Point(m *metricspb.Metric, labelValues []*metricspb.LabelValue, value int64, ts *timestamppb.Timestamp) {
+	m.Timeseries = append(m.Timeseries, &metricspb.TimeSeries{
+		LabelValues: labelValues,
+		Points: []*metricspb.Point{
+			{Timestamp: ts, Value: &metricspb.Point_Int64Value{Int64Value: value}},
+		},
+	})
+}
+
+func addDoublePoint(m *metricspb.Metric, labelValues []*metricspb.LabelValue, value float64, ts *timestamppb.Timestamp) {
+	m.Timeseries = append(m.Timeseries, &metricspb.TimeSeries{
+		LabelValues: labelValues,
+		Points: []*metricspb.Point{
+			{Timestamp: ts, Value: &metricspb.Point_DoubleValue{DoubleValue: value}},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpcheckreceiver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	results := []*checkResult{
+		{
+			endpoint:     "https://example.com",
+			method:       "GET",
+			statusCode:   200,
+			duration:     250 * time.Millisecond,
+			certNotAfter: now.Add(36 * time.Hour),
+		},
+		{
+			endpoint: "http://localhost:8080",
+			method:   "HEAD",
+			err:      errors.New("connection refused"),
+			duration: time.Millisecond,
+		},
+	}
+
+	metrics := buildMetrics(results, now)
+	require.Len(t, metrics, 4)
+
+	values := func(m *metricspb.Metric) map[string]float64 {
+		result := make(map[string]float64)
+		for _, ts := range m.GetTimeseries() {
+			p := ts.GetPoints()[0]
+			key := ts.GetLabelValues()[0].GetValue() + " " + ts.GetLabelValues()[1].GetValue()
+			result[key] = p.GetDoubleValue() + float64(p.GetInt64Value())
+		}
+		return result
+	}
+
+	assert.Equal(t, "httpcheck.up", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"https://example.com GET": 1, "http://localhost:8080 HEAD": 0}, values(metrics[0]))
+	assert.Equal(t, "httpcheck.duration", metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"https://example.com GET": 0.25, "http://localhost:8080 HEAD": 0.001}, values(metrics[1]))
+	assert.Equal(t, "httpcheck.status_code", metrics[2].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"https://example.com GET": 200}, values(metrics[2]))
+	assert.Equal(t, "httpcheck.tls.cert_expiry", metrics[3].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"https://example.com GET": 1.5}, values(metrics[3]))
+}
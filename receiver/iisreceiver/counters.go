@@ -0,0 +1,103 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iisreceiver
+
+import (
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/windowsperfcountersreceiver"
+)
+
+const (
+	siteLabel  = "iis.site"
+	queueLabel = "iis.application_pool"
+)
+
+// perfCounters are the performance counters collected from IIS, per site for
+// the web service and per application pool for the request queues.
+var perfCounters = []windowsperfcountersreceiver.PerfCounterConfig{
+	{
+		Object:        "Web Service",
+		Instances:     []string{"*"},
+		InstanceLabel: siteLabel,
+		Counters: []windowsperfcountersreceiver.CounterConfig{
+			{
+				Name:        "Current Connections",
+				Metric:      "iis.connection.active",
+				Unit:        "1",
+				Description: "Number of active connections.",
+			},
+			{
+				Name:        "Connection Attempts/sec",
+				Metric:      "iis.connection.attempts",
+				Unit:        "1/s",
+				Description: "Rate of connection attempts.",
+			},
+			{
+				Name:        "Total Method Requests/sec",
+				Metric:      "iis.requests",
+				Unit:        "1/s",
+				Description: "Rate of HTTP requests.",
+			},
+			{
+				Name:        "Not Found Errors/sec",
+				Metric:      "iis.requests.not_found",
+				Unit:        "1/s",
+				Description: "Rate of requests that could not be satisfied because the document could not be found.",
+			},
+			{
+				Name:        "Bytes Received/sec",
+				Metric:      "iis.network.io.received",
+				Unit:        "By/s",
+				Description: "Rate of bytes received.",
+			},
+			{
+				Name:        "Bytes Sent/sec",
+				Metric:      "iis.network.io.sent",
+				Unit:        "By/s",
+				Description: "Rate of bytes sent.",
+			},
+			{
+				Name:        "Service Uptime",
+				Metric:      "iis.uptime",
+				Unit:        "s",
+				Description: "Time since the site started.",
+			},
+		},
+	},
+	{
+		Object:        "HTTP Service Request Queues",
+		Instances:     []string{"*"},
+		InstanceLabel: queueLabel,
+		Counters: []windowsperfcountersreceiver.CounterConfig{
+			{
+				Name:        "CurrentQueueSize",
+				Metric:      "iis.request.queue.count",
+				Unit:        "1",
+				Description: "Number of requests in the queue of the application pool.",
+			},
+			{
+				Name:        "MaxQueueItemAge",
+				Metric:      "iis.request.queue.age.max",
+				Unit:        "ms",
+				Description: "Age of the oldest request in the queue of the application pool.",
+			},
+			{
+				Name:        "RejectionRate",
+				Metric:      "iis.request.rejected",
+				Unit:        "1/s",
+				Description: "Rate of requests rejected by the queue of the application pool.",
+			},
+		},
+	},
+}
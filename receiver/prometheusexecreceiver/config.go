@@ -30,6 +30,106 @@ type Config struct {
 	ScrapeInterval time.Duration `mapstructure:"scrape_interval,omitempty"`
 	// Port is the port assigned to the Receiver, and to the {{port}} template variables
 	Port int `mapstructure:"port"`
+	// ScrapeConfig is the configuration of the scrapes of the subprocess' metrics endpoint
+	ScrapeConfig ScrapeConfig `mapstructure:",squash"`
 	// SubprocessConfig is the configuration needed for the subprocess
 	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+	// ConfigFiles maps the names of the files written to the {{tmpdir}} directory before starting the subprocess to their contents
+	ConfigFiles map[string]string `mapstructure:"config_files"`
+	// ResourceAttributes are the static attributes added to the resource of all the metrics scraped by the Receiver
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+	// ExternalLabels are the static labels added to all the metrics scraped by the Receiver
+	ExternalLabels map[string]string `mapstructure:"external_labels"`
+	// Restart is the configuration of the restarts of the subprocess after it exits
+	Restart RestartConfig `mapstructure:"restart"`
+	// Execs is the list of additional subprocesses managed by the Receiver, each scraped independently
+	Execs []ExecConfig `mapstructure:"execs"`
+}
+
+// ExecConfig is the config definition of each subprocess listed under execs
+type ExecConfig struct {
+	// Name is the name of the subprocess, used as the job name of its scrapes
+	Name string `mapstructure:"name"`
+	// ScrapeInterval is the time between each scrape of the subprocess, the Receiver's scrape_interval by default
+	ScrapeInterval time.Duration `mapstructure:"scrape_interval,omitempty"`
+	// Port is the port assigned to the subprocess, and to the {{port}} template variables
+	Port int `mapstructure:"port"`
+	// ScrapeConfig is the configuration of the scrapes of the subprocess' metrics endpoint
+	ScrapeConfig ScrapeConfig `mapstructure:",squash"`
+	// SubprocessConfig is the configuration needed for the subprocess
+	SubprocessConfig subprocessmanager.SubprocessConfig `mapstructure:",squash"`
+	// ConfigFiles maps the names of the files written to the {{tmpdir}} directory before starting the subprocess to their contents
+	ConfigFiles map[string]string `mapstructure:"config_files"`
+	// ResourceAttributes are the static attributes added to the resource of the metrics scraped from the subprocess, overriding the Receiver's ones
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+	// ExternalLabels are the static labels added to the metrics scraped from the subprocess, overriding the Receiver's ones
+	ExternalLabels map[string]string `mapstructure:"external_labels"`
+	// Restart is the configuration of the restarts of the subprocess after it exits, each setting defaulting to the Receiver's one
+	Restart RestartConfig `mapstructure:"restart"`
+}
+
+// RestartConfig is the config definition of the restarts of a subprocess after it exits
+type RestartConfig struct {
+	// Policy is either always (the default), restarting the subprocess whenever it exits, on_failure, only restarting it if it exits with an error, or never, for one-shot jobs
+	Policy string `mapstructure:"policy"`
+	// HealthyProcessTime is the time a subprocess needs to run to be considered healthy, resetting its crash count, 30 minutes by default
+	HealthyProcessTime time.Duration `mapstructure:"healthy_process_time"`
+	// HealthyCrashCount is the number of crashes of an unhealthy subprocess after which the delay before restarting it grows exponentially, 3 by default
+	HealthyCrashCount int `mapstructure:"healthy_crash_count"`
+	// InitialDelay is the delay before restarting a healthy subprocess, the base of the exponential backoff, 1 second by default
+	InitialDelay time.Duration `mapstructure:"initial_delay"`
+	// MaxDelay is the maximum delay before restarting a subprocess, unlimited by default
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+}
+
+// ScrapeConfig is the config definition of the scrapes of a subprocess' metrics endpoint
+type ScrapeConfig struct {
+	// MetricsPath is the path of the metrics endpoint, /metrics by default
+	MetricsPath string `mapstructure:"metrics_path"`
+	// Scheme is the scheme of the metrics endpoint, either http (the default) or https
+	Scheme string `mapstructure:"scheme"`
+	// TLSConfig is the TLS configuration used to scrape an https endpoint
+	TLSConfig TLSConfig `mapstructure:"tls_config"`
+	// BasicAuth is the HTTP basic authentication credentials sent with the scrapes
+	BasicAuth *BasicAuthConfig `mapstructure:"basic_auth"`
+	// BearerToken is the bearer token sent with the scrapes
+	BearerToken string `mapstructure:"bearer_token"`
+	// BearerTokenFile is the path to a file holding the bearer token sent with the scrapes
+	BearerTokenFile string `mapstructure:"bearer_token_file"`
+	// ReadinessProbe is the check delaying the scrapes until the subprocess is ready to serve its metrics
+	ReadinessProbe ReadinessProbeConfig `mapstructure:"readiness_probe"`
+}
+
+// ReadinessProbeConfig is the config definition of the check run before starting the scrapes of a subprocess
+type ReadinessProbeConfig struct {
+	// Type is the kind of check, either tcp (the port accepts connections) or http (the metrics endpoint responds with 200), disabled if empty
+	Type string `mapstructure:"type"`
+	// Timeout is the time after which the scrapes are started even if the subprocess isn't ready, 1 minute by default
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Interval is the time between each check, 1 second by default
+	Interval time.Duration `mapstructure:"interval"`
+}
+
+// TLSConfig is the config definition of the TLS connections to the metrics endpoint
+type TLSConfig struct {
+	// CAFile is the path to the CA cert that has signed the endpoint's TLS cert
+	CAFile string `mapstructure:"ca_file"`
+	// CertFile is the path to the client TLS cert, for endpoints requiring client authentication
+	CertFile string `mapstructure:"cert_file"`
+	// KeyFile is the path to the client TLS key, for endpoints requiring client authentication
+	KeyFile string `mapstructure:"key_file"`
+	// ServerName is the name used to verify the endpoint's TLS cert
+	ServerName string `mapstructure:"server_name"`
+	// InsecureSkipVerify disables the verification of the endpoint's TLS cert
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// BasicAuthConfig is the config definition of the HTTP basic authentication credentials
+type BasicAuthConfig struct {
+	// Username is the username sent with the scrapes
+	Username string `mapstructure:"username"`
+	// Password is the password sent with the scrapes
+	Password string `mapstructure:"password"`
+	// PasswordFile is the path to a file holding the password sent with the scrapes
+	PasswordFile string `mapstructure:"password_file"`
 }
@@ -27,6 +27,13 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
 )
 
+var (
+	// postgresUser is the uid and gid of the subprocess of prometheus_exec/test2
+	postgresUser = uint32(1000)
+	// inheritEnv is the inherit_env setting of prometheus_exec/test2
+	inheritEnv = false
+)
+
 var (
 	wantReceiver2 = &Config{
 		ReceiverSettings: configmodels.ReceiverSettings{
@@ -49,7 +56,23 @@ var (
 		ScrapeInterval: 90 * time.Second,
 		SubprocessConfig: subprocessmanager.SubprocessConfig{
 			Command: "postgres_exporter",
-			Env:     []subprocessmanager.EnvConfig{},
+			Env: []subprocessmanager.EnvConfig{
+				{Name: "DATA_SOURCE_PASS", Value: "${PGPASSWORD}"},
+			},
+			InheritEnv:   &inheritEnv,
+			GracePeriod:  30 * time.Second,
+			WorkingDir:   "/var/lib/postgres_exporter",
+			UID:          &postgresUser,
+			GID:          &postgresUser,
+			Nice:         10,
+			MaxMemoryMiB: 512,
+		},
+		Restart: RestartConfig{
+			Policy:             "on_failure",
+			HealthyProcessTime: 10 * time.Minute,
+			HealthyCrashCount:  5,
+			InitialDelay:       2 * time.Second,
+			MaxDelay:           time.Minute,
 		},
 	}
 
@@ -86,6 +109,72 @@ var (
 			Env:     []subprocessmanager.EnvConfig{},
 		},
 	}
+
+	wantReceiver6 = &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type("prometheus_exec"),
+			NameVal: "prometheus_exec/multi",
+		},
+		ScrapeInterval: 100 * time.Millisecond,
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Env: []subprocessmanager.EnvConfig{},
+		},
+		ResourceAttributes: map[string]string{"deployment.environment": "test"},
+		Execs: []ExecConfig{
+			{
+				Name: "one",
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
+					Env: []subprocessmanager.EnvConfig{
+						{
+							Name:  "SECONDARY_PORT",
+							Value: "{{port}}",
+						},
+					},
+				},
+				ExternalLabels: map[string]string{"exporter": "one"},
+			},
+			{
+				Name:           "two",
+				ScrapeInterval: 200 * time.Millisecond,
+				Port:           9998,
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go {{port}}",
+				},
+				ResourceAttributes: map[string]string{"deployment.environment": "staging"},
+			},
+		},
+	}
+
+	wantReceiver7 = &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type("prometheus_exec"),
+			NameVal: "prometheus_exec/https",
+		},
+		ScrapeInterval: 60 * time.Second,
+		Port:           9100,
+		ScrapeConfig: ScrapeConfig{
+			MetricsPath: "/node/metrics",
+			Scheme:      "https",
+			TLSConfig: TLSConfig{
+				CAFile:             "ca.pem",
+				InsecureSkipVerify: true,
+			},
+			BearerTokenFile: "/var/run/token",
+			ReadinessProbe: ReadinessProbeConfig{
+				Type:     "http",
+				Timeout:  2 * time.Minute,
+				Interval: 5 * time.Second,
+			},
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "node_exporter --web.config={{config_file:web.yml}}",
+			Env:     []subprocessmanager.EnvConfig{},
+		},
+		ConfigFiles: map[string]string{
+			"web.yml": "basic_auth_users:\n  prometheus: $2y$10$X0h1gDsPszWURQaxFh.zoubFi6DXncSjhoQNJgRrnGs7EsimhC7zG\n",
+		},
+	}
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -101,7 +190,7 @@ func TestLoadConfig(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, config)
 
-	assert.Equal(t, len(config.Receivers), 5)
+	assert.Equal(t, len(config.Receivers), 7)
 
 	receiver1 := config.Receivers[receiverType]
 	assert.Equal(t, factory.CreateDefaultConfig(), receiver1)
@@ -117,4 +206,10 @@ func TestLoadConfig(t *testing.T) {
 
 	receiver5 := config.Receivers["prometheus_exec/end_to_end_test/2"]
 	assert.Equal(t, wantReceiver5, receiver5)
+
+	receiver6 := config.Receivers["prometheus_exec/multi"]
+	assert.Equal(t, wantReceiver6, receiver6)
+
+	receiver7 := config.Receivers["prometheus_exec/https"]
+	assert.Equal(t, wantReceiver7, receiver7)
 }
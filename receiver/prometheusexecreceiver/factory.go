@@ -64,5 +64,8 @@ func createMetricsReceiver(
 	nextConsumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 	rCfg := cfg.(*Config)
+	if len(rCfg.Execs) > 0 {
+		return newMultiReceiver(params, rCfg, nextConsumer)
+	}
 	return new(params, rCfg, nextConsumer)
 }
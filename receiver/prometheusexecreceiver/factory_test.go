@@ -107,9 +107,22 @@ func TestCreateTraceAndMetricsReceiver(t *testing.T) {
 			Command: "mysqld_exporter",
 			Env:     []subprocessmanager.EnvConfig{},
 		},
-		port:               9104,
+		port: 9104,
+		restartConfig: RestartConfig{
+			Policy:             "always",
+			HealthyProcessTime: 30 * time.Minute,
+			HealthyCrashCount:  3,
+			InitialDelay:       time.Second,
+		},
 		prometheusReceiver: nil,
 	}
 
 	assert.Equal(t, wantPer, metricReceiver)
+
+	// Test CreateMetricsReceiver with a list of execs
+	receiver = config.Receivers["prometheus_exec/multi"]
+	metricReceiver, err = factory.CreateMetricsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, receiver, nil)
+	assert.Equal(t, nil, err)
+	assert.IsType(t, &multiReceiver{}, metricReceiver)
+	assert.Len(t, metricReceiver.(*multiReceiver).receivers, 2)
 }
@@ -4,9 +4,15 @@ go 1.14
 
 require (
 	github.com/kballard/go-shellquote v0.0.0-20180428030007-95032a82bc51
+	github.com/open-telemetry/opentelemetry-collector-contrib/extension/zpagescontribextension v0.0.0-00010101000000-000000000000
 	github.com/prometheus/common v0.11.1
 	github.com/prometheus/prometheus v1.8.2-0.20200626085723-c448ada63d83
 	github.com/stretchr/testify v1.6.1
+	go.opencensus.io v0.22.4
 	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
 	go.uber.org/zap v1.16.0
+	golang.org/x/sys v0.0.0-20200625212154-ddb9806d33ae
+	gopkg.in/yaml.v2 v2.3.0
 )
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/extension/zpagescontribextension => ./../../extension/zpagescontribextension
@@ -0,0 +1,145 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusexecreceiver/subprocessmanager"
+)
+
+func init() {
+	view.Register(
+		viewSubprocessRestarts,
+		viewSubprocessCrashes,
+		viewSubprocessUptime,
+		viewSubprocessExitCode,
+		viewSubprocessCPUTime,
+		viewSubprocessMemoryRSS,
+		viewSubprocessOpenFDs,
+	)
+}
+
+var (
+	tagReceiverKey, _ = tag.NewKey("receiver")
+
+	mSubprocessRestarts = stats.Int64("otelcol/prometheus_exec/subprocess_restarts", "Number of times the subprocess was restarted after exiting", "1")
+	mSubprocessCrashes  = stats.Int64("otelcol/prometheus_exec/subprocess_crashes", "Number of times the subprocess exited with an error", "1")
+	mSubprocessUptime   = stats.Float64("otelcol/prometheus_exec/subprocess_uptime", "Time the subprocess has been running, 0 while it is restarted", "s")
+	mSubprocessExitCode = stats.Int64("otelcol/prometheus_exec/subprocess_exit_code", "Exit code of the last subprocess exit, -1 if it has none", "1")
+	mSubprocessCPUTime  = stats.Float64("otelcol/prometheus_exec/subprocess_cpu_time", "User and system CPU time used by the subprocess, 0 while it is restarted", "s")
+	mSubprocessRSS      = stats.Int64("otelcol/prometheus_exec/subprocess_memory_rss", "Resident set size of the subprocess, 0 while it is restarted", "By")
+	mSubprocessOpenFDs  = stats.Int64("otelcol/prometheus_exec/subprocess_open_fds", "Number of file descriptors open by the subprocess, 0 while it is restarted", "1")
+)
+
+var viewSubprocessRestarts = &view.View{
+	Name:        mSubprocessRestarts.Name(),
+	Description: mSubprocessRestarts.Description(),
+	Measure:     mSubprocessRestarts,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewSubprocessCrashes = &view.View{
+	Name:        mSubprocessCrashes.Name(),
+	Description: mSubprocessCrashes.Description(),
+	Measure:     mSubprocessCrashes,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewSubprocessUptime = &view.View{
+	Name:        mSubprocessUptime.Name(),
+	Description: mSubprocessUptime.Description(),
+	Measure:     mSubprocessUptime,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewSubprocessExitCode = &view.View{
+	Name:        mSubprocessExitCode.Name(),
+	Description: mSubprocessExitCode.Description(),
+	Measure:     mSubprocessExitCode,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewSubprocessCPUTime = &view.View{
+	Name:        mSubprocessCPUTime.Name(),
+	Description: mSubprocessCPUTime.Description(),
+	Measure:     mSubprocessCPUTime,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewSubprocessMemoryRSS = &view.View{
+	Name:        mSubprocessRSS.Name(),
+	Description: mSubprocessRSS.Description(),
+	Measure:     mSubprocessRSS,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.LastValue(),
+}
+
+var viewSubprocessOpenFDs = &view.View{
+	Name:        mSubprocessOpenFDs.Name(),
+	Description: mSubprocessOpenFDs.Description(),
+	Measure:     mSubprocessOpenFDs,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.LastValue(),
+}
+
+func recordSubprocessRestart(receiver string) {
+	record(receiver, mSubprocessRestarts.M(1))
+}
+
+func recordSubprocessUptime(receiver string, uptime time.Duration) {
+	record(receiver, mSubprocessUptime.M(uptime.Seconds()))
+}
+
+func recordSubprocessUsage(receiver string, usage subprocessmanager.ResourceUsage) {
+	record(receiver, mSubprocessCPUTime.M(usage.CPUSeconds), mSubprocessRSS.M(usage.RSSBytes), mSubprocessOpenFDs.M(int64(usage.OpenFDs)))
+}
+
+// recordSubprocessExit records the exit code of a subprocess which exited on its own with the error returned by its run, counting it as a crash if the error isn't nil
+func recordSubprocessExit(receiver string, subprocessErr error) {
+	record(receiver, mSubprocessUptime.M(0), mSubprocessExitCode.M(int64(exitCode(subprocessErr))))
+	recordSubprocessUsage(receiver, subprocessmanager.ResourceUsage{})
+	if subprocessErr != nil {
+		record(receiver, mSubprocessCrashes.M(1))
+	}
+}
+
+// exitCode returns the exit code of a subprocess according to the error returned by its run, -1 if it didn't exit with a code (e.g. killed by a signal or not started)
+func exitCode(subprocessErr error) int {
+	if subprocessErr == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(subprocessErr, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+func record(receiver string, ms ...stats.Measurement) {
+	_ = stats.RecordWithTags(context.Background(), []tag.Mutator{tag.Upsert(tagReceiverKey, receiver)}, ms...)
+}
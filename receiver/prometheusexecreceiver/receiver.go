@@ -16,14 +16,22 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/rand"
 	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	configutil "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	sdconfig "github.com/prometheus/prometheus/discovery/config"
@@ -31,6 +39,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/receiver/prometheusreceiver"
 	"go.uber.org/zap"
 
@@ -40,20 +49,49 @@ import (
 const (
 	// template for port in strings
 	portTemplate string = "{{port}}"
-	// healthyProcessTime is the default time a process needs to stay alive to be considered healthy
-	healthyProcessTime time.Duration = 30 * time.Minute
-	// healthyCrashCount is the amount of times a process can crash (within the healthyProcessTime) before being considered unstable - it may be trying to find a port
-	healthyCrashCount int = 3
+	// template for the temporary directory of the subprocess in strings
+	tmpDirTemplate string = "{{tmpdir}}"
+	// defaultHealthyProcessTime is the default time a process needs to stay alive to be considered healthy
+	defaultHealthyProcessTime time.Duration = 30 * time.Minute
+	// defaultHealthyCrashCount is the default amount of times a process can crash (within the healthyProcessTime) before being considered unstable - it may be trying to find a port
+	defaultHealthyCrashCount int = 3
 	// delayMutiplier is the factor by which the delay scales
 	delayMultiplier float64 = 2.0
-	// initialDelay is the initial delay before a process is restarted
-	initialDelay time.Duration = 1 * time.Second
+	// defaultInitialDelay is the default initial delay before a process is restarted
+	defaultInitialDelay time.Duration = 1 * time.Second
+	// restart policy restarting the subprocess whenever it exits
+	restartPolicyAlways = "always"
+	// restart policy only restarting the subprocess if it exits with an error
+	restartPolicyOnFailure = "on_failure"
+	// restart policy never restarting the subprocess
+	restartPolicyNever = "never"
 	// default path to scrape metrics at endpoint
 	defaultMetricsPath = "/metrics"
+	// default scheme of the scrapes
+	defaultScheme = "http"
 	// defaul timeout for a scrape
 	defaultScrapeTimeout = 10 * time.Second
+	// readiness probe checking the port accepts connections
+	readinessProbeTCP = "tcp"
+	// readiness probe checking the metrics endpoint responds with 200
+	readinessProbeHTTP = "http"
+	// default time after which the scrapes are started even if the subprocess isn't ready
+	defaultReadinessTimeout = 1 * time.Minute
+	// default time between each readiness check
+	defaultReadinessInterval = 1 * time.Second
+	// time between each report of the subprocess uptime and resource usage
+	uptimeReportInterval = 10 * time.Second
+	// maxPortConflictRetries is the maximum number of times in a row a subprocess is restarted right away with a new port after failing to listen on the generated one
+	maxPortConflictRetries = 5
 )
 
+// configFileTemplate matches the {{config_file:NAME}} templates in strings, replaced by the path of the NAME config file
+var configFileTemplate = regexp.MustCompile(`\{\{config_file:([^}]*)\}\}`)
+
+// promReceiverStartMu serializes the starts of the underlying Prometheus receivers: each of them creates a scrape manager
+// registering itself in a global Prometheus metadata collector without any locking
+var promReceiverStartMu sync.Mutex
+
 type prometheusExecReceiver struct {
 	params   component.ReceiverCreateParams
 	config   *Config
@@ -65,12 +103,25 @@ type prometheusExecReceiver struct {
 	// Subprocess data
 	subprocessConfig *subprocessmanager.SubprocessConfig
 	port             int
+	// Directory holding the config files, created on start if the subprocess uses it
+	tmpDir string
+	// Restart settings, defaults applied
+	restartConfig RestartConfig
+	// Effective settings of the current subprocess, reported on the zPages
+	status subprocessStatus
 
 	// Underlying receiver data
 	prometheusReceiver component.MetricsReceiver
 
 	// Shutdown channel
 	shutdownCh chan struct{}
+	// Channel closed once the subprocess and the underlying receiver are stopped
+	doneCh chan struct{}
+}
+
+// multiReceiver manages the subprocesses listed under execs, each with its own prometheusExecReceiver
+type multiReceiver struct {
+	receivers []*prometheusExecReceiver
 }
 
 type runResult struct {
@@ -83,8 +134,29 @@ func new(params component.ReceiverCreateParams, config *Config, consumer consume
 	if config.SubprocessConfig.Command == "" {
 		return nil, fmt.Errorf("no command to execute entered in config file for %v", config.Name())
 	}
+	if err := config.SubprocessConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid subprocess configuration in config file for %v: %w", config.Name(), err)
+	}
 	subprocessConfig := getSubprocessConfig(config)
 	promReceiverConfig := getPromReceiverConfig(config)
+	if err := validateScrapeConfig(promReceiverConfig.PrometheusConfig.ScrapeConfigs[0]); err != nil {
+		return nil, fmt.Errorf("invalid scrape configuration in config file for %v: %w", config.Name(), err)
+	}
+	if err := validateReadinessProbe(&config.ScrapeConfig.ReadinessProbe); err != nil {
+		return nil, fmt.Errorf("invalid scrape configuration in config file for %v: %w", config.Name(), err)
+	}
+	if err := validateConfigFiles(config); err != nil {
+		return nil, fmt.Errorf("invalid config_files in config file for %v: %w", config.Name(), err)
+	}
+	if err := validateExternalLabels(config.ExternalLabels); err != nil {
+		return nil, fmt.Errorf("invalid external_labels in config file for %v: %w", config.Name(), err)
+	}
+	if err := validateRestartConfig(&config.Restart); err != nil {
+		return nil, fmt.Errorf("invalid restart configuration in config file for %v: %w", config.Name(), err)
+	}
+	if len(config.ResourceAttributes) > 0 {
+		consumer = &resourceAttributesConsumer{next: consumer, attributes: config.ResourceAttributes}
+	}
 
 	return &prometheusExecReceiver{
 		params:             params,
@@ -93,17 +165,164 @@ func new(params component.ReceiverCreateParams, config *Config, consumer consume
 		subprocessConfig:   subprocessConfig,
 		promReceiverConfig: promReceiverConfig,
 		port:               config.Port,
+		restartConfig:      getRestartConfig(config.Restart),
 	}, nil
 }
 
+// newMultiReceiver returns a multiReceiver running the subprocess of the top level exec, if any, and those listed under execs
+func newMultiReceiver(params component.ReceiverCreateParams, config *Config, consumer consumer.MetricsConsumer) (*multiReceiver, error) {
+	configs, err := getExecConfigs(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mr := &multiReceiver{}
+	for _, cfg := range configs {
+		receiver, err := new(params, cfg, consumer)
+		if err != nil {
+			return nil, err
+		}
+		mr.receivers = append(mr.receivers, receiver)
+	}
+	return mr, nil
+}
+
+// getExecConfigs returns the configs of the single subprocess receivers, whose names are the receiver's name followed by the exec's name
+func getExecConfigs(cfg *Config) ([]*Config, error) {
+	var configs []*Config
+	names := map[string]bool{}
+	ports := map[int]bool{}
+	if cfg.SubprocessConfig.Command != "" {
+		configs = append(configs, &Config{
+			ReceiverSettings:   cfg.ReceiverSettings,
+			ScrapeInterval:     cfg.ScrapeInterval,
+			Port:               cfg.Port,
+			ScrapeConfig:       cfg.ScrapeConfig,
+			SubprocessConfig:   cfg.SubprocessConfig,
+			ConfigFiles:        cfg.ConfigFiles,
+			ResourceAttributes: cfg.ResourceAttributes,
+			ExternalLabels:     cfg.ExternalLabels,
+			Restart:            cfg.Restart,
+		})
+		if cfg.Port != 0 {
+			ports[cfg.Port] = true
+		}
+	}
+
+	for i, exec := range cfg.Execs {
+		if exec.Name == "" {
+			return nil, fmt.Errorf("no name entered for exec %v in config file for %v", i, cfg.Name())
+		}
+		if names[exec.Name] {
+			return nil, fmt.Errorf("exec %q defined more than once in config file for %v", exec.Name, cfg.Name())
+		}
+		names[exec.Name] = true
+		if exec.Port != 0 {
+			if ports[exec.Port] {
+				return nil, fmt.Errorf("port %v assigned more than once in config file for %v", exec.Port, cfg.Name())
+			}
+			ports[exec.Port] = true
+		}
+
+		scrapeInterval := exec.ScrapeInterval
+		if scrapeInterval == 0 {
+			scrapeInterval = cfg.ScrapeInterval
+		}
+		configs = append(configs, &Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: cfg.Type(),
+				NameVal: cfg.Name() + "/" + exec.Name,
+			},
+			ScrapeInterval: scrapeInterval,
+			Port:           exec.Port,
+			ScrapeConfig:   exec.ScrapeConfig,
+			SubprocessConfig: subprocessmanager.SubprocessConfig{
+				Command:      exec.SubprocessConfig.Command,
+				Env:          exec.SubprocessConfig.Env,
+				InheritEnv:   exec.SubprocessConfig.InheritEnv,
+				GracePeriod:  exec.SubprocessConfig.GracePeriod,
+				WorkingDir:   exec.SubprocessConfig.WorkingDir,
+				UID:          exec.SubprocessConfig.UID,
+				GID:          exec.SubprocessConfig.GID,
+				Nice:         exec.SubprocessConfig.Nice,
+				MaxMemoryMiB: exec.SubprocessConfig.MaxMemoryMiB,
+			},
+			ConfigFiles:        exec.ConfigFiles,
+			ResourceAttributes: mergeMaps(cfg.ResourceAttributes, exec.ResourceAttributes),
+			ExternalLabels:     mergeMaps(cfg.ExternalLabels, exec.ExternalLabels),
+			Restart:            mergeRestartConfigs(cfg.Restart, exec.Restart),
+		})
+	}
+	return configs, nil
+}
+
+// mergeRestartConfigs returns the restart settings of the overrides, those not set being taken from the base
+func mergeRestartConfigs(base, overrides RestartConfig) RestartConfig {
+	if overrides.Policy == "" {
+		overrides.Policy = base.Policy
+	}
+	if overrides.HealthyProcessTime == 0 {
+		overrides.HealthyProcessTime = base.HealthyProcessTime
+	}
+	if overrides.HealthyCrashCount == 0 {
+		overrides.HealthyCrashCount = base.HealthyCrashCount
+	}
+	if overrides.InitialDelay == 0 {
+		overrides.InitialDelay = base.InitialDelay
+	}
+	if overrides.MaxDelay == 0 {
+		overrides.MaxDelay = base.MaxDelay
+	}
+	return overrides
+}
+
+// getRestartConfig returns the restart settings, the default values replacing those not set
+func getRestartConfig(cfg RestartConfig) RestartConfig {
+	if cfg.Policy == "" {
+		cfg.Policy = restartPolicyAlways
+	}
+	if cfg.HealthyProcessTime == 0 {
+		cfg.HealthyProcessTime = defaultHealthyProcessTime
+	}
+	if cfg.HealthyCrashCount == 0 {
+		cfg.HealthyCrashCount = defaultHealthyCrashCount
+	}
+	if cfg.InitialDelay == 0 {
+		cfg.InitialDelay = defaultInitialDelay
+	}
+	return cfg
+}
+
+// mergeMaps returns the entries of both maps, those of the overrides replacing those of the base, or nil if both are empty
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 // getPromReceiverConfig returns the Prometheus receiver config
 func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	scrapeConfig := &config.ScrapeConfig{}
 
 	scrapeConfig.ScrapeInterval = model.Duration(cfg.ScrapeInterval)
 	scrapeConfig.ScrapeTimeout = model.Duration(defaultScrapeTimeout)
-	scrapeConfig.Scheme = "http"
+	scrapeConfig.Scheme = defaultScheme
+	if cfg.ScrapeConfig.Scheme != "" {
+		scrapeConfig.Scheme = cfg.ScrapeConfig.Scheme
+	}
 	scrapeConfig.MetricsPath = defaultMetricsPath
+	if cfg.ScrapeConfig.MetricsPath != "" {
+		scrapeConfig.MetricsPath = cfg.ScrapeConfig.MetricsPath
+	}
+	scrapeConfig.HTTPClientConfig = getHTTPClientConfig(&cfg.ScrapeConfig)
 	scrapeConfig.JobName = extractName(cfg)
 	scrapeConfig.HonorLabels = false
 	scrapeConfig.HonorTimestamps = true
@@ -118,6 +337,14 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 			},
 		},
 	}
+	// The labels of the target group are added to all the scraped metrics
+	if len(cfg.ExternalLabels) > 0 {
+		labels := model.LabelSet{}
+		for name, value := range cfg.ExternalLabels {
+			labels[model.LabelName(name)] = model.LabelValue(value)
+		}
+		scrapeConfig.ServiceDiscoveryConfig.StaticConfigs[0].Labels = labels
+	}
 
 	receiverSettings := &configmodels.ReceiverSettings{
 		TypeVal: typeStr,
@@ -132,12 +359,122 @@ func getPromReceiverConfig(cfg *Config) *prometheusreceiver.Config {
 	}
 }
 
+// getHTTPClientConfig returns the Prometheus HTTP client config of the scrapes, holding their TLS settings and credentials
+func getHTTPClientConfig(cfg *ScrapeConfig) configutil.HTTPClientConfig {
+	httpConfig := configutil.HTTPClientConfig{
+		BearerToken:     configutil.Secret(cfg.BearerToken),
+		BearerTokenFile: cfg.BearerTokenFile,
+		TLSConfig: configutil.TLSConfig{
+			CAFile:             cfg.TLSConfig.CAFile,
+			CertFile:           cfg.TLSConfig.CertFile,
+			KeyFile:            cfg.TLSConfig.KeyFile,
+			ServerName:         cfg.TLSConfig.ServerName,
+			InsecureSkipVerify: cfg.TLSConfig.InsecureSkipVerify,
+		},
+	}
+
+	if cfg.BasicAuth != nil {
+		httpConfig.BasicAuth = &configutil.BasicAuth{
+			Username:     cfg.BasicAuth.Username,
+			Password:     configutil.Secret(cfg.BasicAuth.Password),
+			PasswordFile: cfg.BasicAuth.PasswordFile,
+		}
+	}
+
+	return httpConfig
+}
+
+// validateScrapeConfig makes sure the scheme is supported and at most one kind of credentials is configured
+func validateScrapeConfig(scrapeConfig *config.ScrapeConfig) error {
+	if scrapeConfig.Scheme != "http" && scrapeConfig.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", scrapeConfig.Scheme)
+	}
+	return scrapeConfig.HTTPClientConfig.Validate()
+}
+
+// validateReadinessProbe makes sure the readiness probe type is supported
+func validateReadinessProbe(probe *ReadinessProbeConfig) error {
+	switch probe.Type {
+	case "", readinessProbeTCP, readinessProbeHTTP:
+		return nil
+	}
+	return fmt.Errorf("unsupported readiness probe type %q", probe.Type)
+}
+
+// validateConfigFiles checks the config file names can't escape the temporary directory, and that the {{config_file:NAME}} templates reference defined files
+func validateConfigFiles(cfg *Config) error {
+	for name := range cfg.ConfigFiles {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+			return fmt.Errorf("invalid config file name %q", name)
+		}
+	}
+
+	values := []string{cfg.SubprocessConfig.Command}
+	for _, env := range cfg.SubprocessConfig.Env {
+		values = append(values, env.Value)
+	}
+	for _, content := range cfg.ConfigFiles {
+		values = append(values, content)
+	}
+	for _, value := range values {
+		for _, match := range configFileTemplate.FindAllStringSubmatch(value, -1) {
+			if _, ok := cfg.ConfigFiles[strings.ToLower(match[1])]; !ok {
+				return fmt.Errorf("undefined config file %q", match[1])
+			}
+		}
+	}
+	return nil
+}
+
+// validateRestartConfig checks the restart policy is supported and the restart settings aren't negative
+func validateRestartConfig(cfg *RestartConfig) error {
+	switch cfg.Policy {
+	case "", restartPolicyAlways, restartPolicyOnFailure, restartPolicyNever:
+	default:
+		return fmt.Errorf("unsupported restart policy %q", cfg.Policy)
+	}
+	if cfg.HealthyProcessTime < 0 || cfg.HealthyCrashCount < 0 || cfg.InitialDelay < 0 || cfg.MaxDelay < 0 {
+		return fmt.Errorf("healthy_process_time, healthy_crash_count, initial_delay and max_delay must not be negative")
+	}
+	return nil
+}
+
+// validateExternalLabels checks the external labels have valid Prometheus label names, not reserved for internal use
+func validateExternalLabels(labels map[string]string) error {
+	for name := range labels {
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+	return nil
+}
+
+// usesTmpDir returns whether the subprocess has config files or references the {{tmpdir}} template
+func usesTmpDir(cfg *Config) bool {
+	if len(cfg.ConfigFiles) > 0 || strings.Contains(cfg.SubprocessConfig.Command, tmpDirTemplate) {
+		return true
+	}
+	for _, env := range cfg.SubprocessConfig.Env {
+		if strings.Contains(env.Value, tmpDirTemplate) {
+			return true
+		}
+	}
+	return false
+}
+
 // getSubprocessConfig returns the subprocess config
 func getSubprocessConfig(cfg *Config) *subprocessmanager.SubprocessConfig {
 	subprocessConfig := &subprocessmanager.SubprocessConfig{}
 
 	subprocessConfig.Command = cfg.SubprocessConfig.Command
 	subprocessConfig.Env = cfg.SubprocessConfig.Env
+	subprocessConfig.InheritEnv = cfg.SubprocessConfig.InheritEnv
+	subprocessConfig.GracePeriod = cfg.SubprocessConfig.GracePeriod
+	subprocessConfig.WorkingDir = cfg.SubprocessConfig.WorkingDir
+	subprocessConfig.UID = cfg.SubprocessConfig.UID
+	subprocessConfig.GID = cfg.SubprocessConfig.GID
+	subprocessConfig.Nice = cfg.SubprocessConfig.Nice
+	subprocessConfig.MaxMemoryMiB = cfg.SubprocessConfig.MaxMemoryMiB
 
 	return subprocessConfig
 }
@@ -154,36 +491,59 @@ func extractName(cfg *Config) string {
 
 // Start creates the configs and calls the function that handles the prometheus_exec receiver
 func (per *prometheusExecReceiver) Start(ctx context.Context, host component.Host) error {
+	if usesTmpDir(per.config) {
+		tmpDir, err := ioutil.TempDir("", "prometheus_exec_")
+		if err != nil {
+			return fmt.Errorf("unable to create the temporary directory of %v: %w", per.config.Name(), err)
+		}
+		per.tmpDir = tmpDir
+	}
+
 	// Shutdown channel
 	per.shutdownCh = make(chan struct{})
+	per.doneCh = make(chan struct{})
 
-	go per.manageProcess(context.Background(), host)
+	unregisterStatus := per.status.register(per.config.Name())
+	go func() {
+		defer unregisterStatus()
+		per.manageProcess(context.Background(), host)
+	}()
 
 	return nil
 }
 
 // manageProcess is an infinite loop that handles starting and restarting Prometheus-receiver/subprocess pairs
 func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host component.Host) {
+	defer close(per.doneCh)
+	// Remove the config files once the subprocess is stopped for good
+	if per.tmpDir != "" {
+		defer os.RemoveAll(per.tmpDir)
+	}
 	var crashCount int
+	var portConflictRetries int
 
 	for {
 
-		receiver, err := per.createAndStartReceiver(ctx, host)
+		receiver, port, err := per.createReceiver(ctx)
 		if err != nil {
 			per.params.Logger.Error("createReceiver() error", zap.String("error", err.Error()))
 			return
 		}
 
-		elapsed := per.runProcess(ctx)
-
-		err = receiver.Shutdown(ctx)
+		result, started, err := per.runProcess(ctx, host, receiver, port)
 		if err != nil {
-			per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
+			per.params.Logger.Error("runProcess() error", zap.String("error", err.Error()))
 			return
 		}
 
-		crashCount = per.computeCrashCount(elapsed, crashCount)
-		per.computeDelayAndSleep(elapsed, crashCount)
+		// The receiver is only started once the subprocess is ready, it may not have been if the subprocess exited early
+		if started {
+			err = receiver.Shutdown(ctx)
+			if err != nil {
+				per.params.Logger.Error("could not stop receiver associated to process, killing it", zap.String("error", err.Error()))
+				return
+			}
+		}
 
 		// Exit loop if shutdown was signaled
 		select {
@@ -191,11 +551,36 @@ func (per *prometheusExecReceiver) manageProcess(ctx context.Context, host compo
 			return
 		default:
 		}
+
+		// Another process may have taken the generated port before the subprocess listened on it, which isn't a crash: the subprocess is restarted without delay on a new port, whatever the restart policy
+		if per.isPortConflict(result.subprocessErr) && portConflictRetries < maxPortConflictRetries {
+			portConflictRetries++
+			per.params.Logger.Info("Subprocess could not listen on its port, restarting it on a new port", zap.Int("port", port))
+			recordSubprocessRestart(per.config.Name())
+			continue
+		}
+		portConflictRetries = 0
+
+		if !per.shouldRestart(result.subprocessErr) {
+			per.params.Logger.Info("Subprocess exited, not restarting it according to the restart policy", zap.String("policy", per.restartConfig.Policy))
+			return
+		}
+
+		crashCount = per.computeCrashCount(result.elapsed, per.restartConfig.HealthyProcessTime, crashCount)
+		per.computeDelayAndSleep(result.elapsed, crashCount)
+
+		// Exit loop if shutdown was signaled while waiting to restart
+		select {
+		case <-per.shutdownCh:
+			return
+		default:
+			recordSubprocessRestart(per.config.Name())
+		}
 	}
 }
 
-// createAndStartReceiver will create the underlying Prometheus receiver and generate a random port if one is needed, then start it
-func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, host component.Host) (component.MetricsReceiver, error) {
+// createReceiver will create the underlying Prometheus receiver and generate a random port if one is needed, returning the port the subprocess is scraped on
+func (per *prometheusExecReceiver) createReceiver(ctx context.Context) (component.MetricsReceiver, int, error) {
 	currentPort := per.port
 
 	// Generate a port if none was specified
@@ -203,7 +588,7 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 		var err error
 		currentPort, err = generateRandomPort()
 		if err != nil {
-			return nil, fmt.Errorf("generateRandomPort() error - killing this single process/receiver: %w", err)
+			return nil, 0, fmt.Errorf("generateRandomPort() error - killing this single process/receiver: %w", err)
 		}
 
 		per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0].ServiceDiscoveryConfig.StaticConfigs[0].Targets = []model.LabelSet{
@@ -211,54 +596,198 @@ func (per *prometheusExecReceiver) createAndStartReceiver(ctx context.Context, h
 		}
 	}
 
-	// Create and start the underlying Prometheus receiver
+	// Create the underlying Prometheus receiver, started once the subprocess is ready
 	factory := prometheusreceiver.NewFactory()
 	receiver, err := factory.CreateMetricsReceiver(ctx, per.params, per.promReceiverConfig, per.consumer)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create Prometheus receiver - killing this single process/receiver: %w", err)
+		return nil, 0, fmt.Errorf("unable to create Prometheus receiver - killing this single process/receiver: %w", err)
 	}
 
-	per.subprocessConfig = per.fillPortPlaceholders(currentPort)
-
-	err = receiver.Start(ctx, host)
-	if err != nil {
-		return nil, fmt.Errorf("could not start receiver - killing this single process/receiver: %w", err)
+	per.subprocessConfig = per.fillPlaceholders(currentPort)
+	per.status.update(per.subprocessConfig.Command, currentPort, per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0])
+	if err := per.writeConfigFiles(currentPort); err != nil {
+		return nil, 0, fmt.Errorf("unable to write config files - killing this single process/receiver: %w", err)
 	}
 
-	return receiver, nil
+	return receiver, currentPort, nil
+}
+
+// isPortConflict returns whether the subprocess exited with the error returned by its run because its generated port was already in use, a configured port being kept
+func (per *prometheusExecReceiver) isPortConflict(subprocessErr error) bool {
+	return per.port == 0 && errors.Is(subprocessErr, subprocessmanager.ErrAddressInUse)
+}
+
+// shouldRestart returns whether the subprocess is restarted after exiting with the error returned by its run according to the restart policy
+func (per *prometheusExecReceiver) shouldRestart(subprocessErr error) bool {
+	switch per.restartConfig.Policy {
+	case restartPolicyNever:
+		return false
+	case restartPolicyOnFailure:
+		return subprocessErr != nil
+	default:
+		return true
+	}
 }
 
-// runProcess will run the process and return runtime, or handle a shutdown if one is triggered while the subprocess is running
-func (per *prometheusExecReceiver) runProcess(ctx context.Context) time.Duration {
+// runProcess will run the process and start the receiver once the process is ready, then return the result of the run and whether the receiver was started, or handle a shutdown if one is triggered while the subprocess is running
+func (per *prometheusExecReceiver) runProcess(ctx context.Context, host component.Host, receiver component.MetricsReceiver, port int) (runResult, bool, error) {
 	childCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	run := make(chan runResult, 1)
+	pids := make(chan int, 1)
+
+	go per.handleProcessResult(childCtx, run, pids)
+
+	ready := per.waitForReadiness(childCtx, port)
+	started := false
+	start := time.Now()
+	per.status.setStarted(start)
+	defer per.status.setStarted(time.Time{})
+	recordSubprocessUptime(per.config.Name(), 0)
+	uptimeTicker := time.NewTicker(uptimeReportInterval)
+	defer uptimeTicker.Stop()
+	// pid is the pid of the subprocess once it is started, 0 before
+	pid := 0
+	for {
+		select {
+		case pid = <-pids:
+			per.recordResourceUsage(pid)
+
+		case <-ready:
+			// Stop selecting the closed channel once the receiver is started
+			ready = nil
+			promReceiverStartMu.Lock()
+			err := receiver.Start(ctx, host)
+			promReceiverStartMu.Unlock()
+			if err != nil {
+				return runResult{}, false, fmt.Errorf("could not start receiver - killing this single process/receiver: %w", err)
+			}
+			started = true
+
+		case <-uptimeTicker.C:
+			recordSubprocessUptime(per.config.Name(), time.Since(start))
+			if pid != 0 {
+				per.recordResourceUsage(pid)
+			}
+
+		case result := <-run:
+			// Log the error from the subprocess without returning it since we want to restart the process if it exited
+			if result.subprocessErr != nil {
+				per.params.Logger.Info("Subprocess error", zap.String("error", result.subprocessErr.Error()))
+			}
+			recordSubprocessExit(per.config.Name(), result.subprocessErr)
+			return result, started, nil
+
+		case <-per.shutdownCh:
+			// Wait for the subprocess to exit, which takes at most its grace period
+			cancel()
+			<-run
+			return runResult{}, started, nil
+		}
+	}
+}
 
-	go per.handleProcessResult(childCtx, run)
+// waitForReadiness returns a channel closed once the subprocess is ready to be scraped according to the readiness probe, or once the probe timed out
+func (per *prometheusExecReceiver) waitForReadiness(ctx context.Context, port int) <-chan struct{} {
+	ready := make(chan struct{})
+	probe := per.config.ScrapeConfig.ReadinessProbe
+	if probe.Type == "" {
+		close(ready)
+		return ready
+	}
 
-	select {
-	case result := <-run:
-		// Log the error from the subprocess without returning it since we want to restart the process if it exited
-		if result.subprocessErr != nil {
-			per.params.Logger.Info("Subprocess error", zap.String("error", result.subprocessErr.Error()))
+	timeout := probe.Timeout
+	if timeout == 0 {
+		timeout = defaultReadinessTimeout
+	}
+	interval := probe.Interval
+	if interval == 0 {
+		interval = defaultReadinessInterval
+	}
+
+	go func() {
+		defer close(ready)
+
+		check, err := per.readinessCheck(probe.Type, port, interval)
+		if err != nil {
+			per.params.Logger.Error("could not create readiness probe, starting scrapes", zap.String("error", err.Error()))
+			return
 		}
-		cancel()
-		return result.elapsed
 
-	case <-per.shutdownCh:
-		cancel()
-		return 0
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for !check() {
+			select {
+			case <-ticker.C:
+			case <-timer.C:
+				per.params.Logger.Warn("Subprocess not ready after readiness probe timeout, starting scrapes", zap.String("timeout", timeout.String()))
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ready
+}
+
+// readinessCheck returns the function checking once whether the subprocess listening on port is ready, each check lasting at most timeout
+func (per *prometheusExecReceiver) readinessCheck(probeType string, port int, timeout time.Duration) (func() bool, error) {
+	address := fmt.Sprintf("localhost:%v", port)
+	if probeType == readinessProbeTCP {
+		return func() bool {
+			conn, err := net.DialTimeout("tcp", address, timeout)
+			if err != nil {
+				return false
+			}
+			conn.Close()
+			return true
+		}, nil
+	}
+
+	// Use the TLS settings and credentials of the scrapes to query the metrics endpoint
+	scrapeConfig := per.promReceiverConfig.PrometheusConfig.ScrapeConfigs[0]
+	client, err := configutil.NewClientFromConfig(scrapeConfig.HTTPClientConfig, "readiness_probe", true)
+	if err != nil {
+		return nil, err
 	}
+	client.Timeout = timeout
+	url := fmt.Sprintf("%v://%v%v", scrapeConfig.Scheme, address, scrapeConfig.MetricsPath)
+	return func() bool {
+		resp, err := client.Get(url)
+		if err != nil {
+			return false
+		}
+		resp.Body.Close()
+		return resp.StatusCode == http.StatusOK
+	}, nil
 }
 
-// handleProcessResult calls the process manager's run function and pipes the return value into the channel
-func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, run chan<- runResult) {
-	elapsed, subprocessErr := per.subprocessConfig.Run(childCtx, per.params.Logger)
+// handleProcessResult calls the process manager's run function and pipes the return value into the channel, the pid of the subprocess being sent to pids once it is started
+func (per *prometheusExecReceiver) handleProcessResult(childCtx context.Context, run chan<- runResult, pids chan<- int) {
+	elapsed, subprocessErr := per.subprocessConfig.RunWithPID(childCtx, per.params.Logger, pids)
 	run <- runResult{elapsed, subprocessErr}
 }
 
+// recordResourceUsage records the resource usage of the running subprocess, if supported on the current platform
+func (per *prometheusExecReceiver) recordResourceUsage(pid int) {
+	if !subprocessmanager.UsageSupported {
+		return
+	}
+	usage, err := subprocessmanager.ReadResourceUsage(pid)
+	if err != nil {
+		// The subprocess may have just exited, its usage is reset once its exit is handled
+		per.params.Logger.Debug("Could not read the resource usage of the subprocess", zap.Int("pid", pid), zap.Error(err))
+		return
+	}
+	recordSubprocessUsage(per.config.Name(), usage)
+}
+
 // computeDelayAndSleep will compute how long the process should delay before restarting and handle a shutdown while this goroutine waits
 func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, crashCount int) {
-	sleepTime := getDelay(elapsed, healthyProcessTime, crashCount, healthyCrashCount)
+	restart := per.restartConfig
+	sleepTime := getDelay(elapsed, restart.HealthyProcessTime, crashCount, restart.HealthyCrashCount, restart.InitialDelay, restart.MaxDelay)
 	per.params.Logger.Info("Subprocess start delay", zap.String("time until process restarts", sleepTime.String()))
 
 	select {
@@ -271,7 +800,7 @@ func (per *prometheusExecReceiver) computeDelayAndSleep(elapsed time.Duration, c
 }
 
 // computeCrashCount will compute crashCount according to runtime
-func (per *prometheusExecReceiver) computeCrashCount(elapsed time.Duration, crashCount int) int {
+func (per *prometheusExecReceiver) computeCrashCount(elapsed time.Duration, healthyProcessTime time.Duration, crashCount int) int {
 	if elapsed > healthyProcessTime {
 		return 1
 	}
@@ -280,21 +809,41 @@ func (per *prometheusExecReceiver) computeCrashCount(elapsed time.Duration, cras
 	return crashCount
 }
 
-// fillPortPlaceholders will check if any of the strings in the process data have the {{port}} placeholder, and replace it if necessary
-func (per *prometheusExecReceiver) fillPortPlaceholders(newPort int) *subprocessmanager.SubprocessConfig {
-	port := strconv.Itoa(newPort)
-
+// fillPlaceholders will check if any of the strings in the process data have the {{port}}, {{tmpdir}} or {{config_file:NAME}} placeholders, and replace them if necessary
+func (per *prometheusExecReceiver) fillPlaceholders(newPort int) *subprocessmanager.SubprocessConfig {
 	newConfig := *per.subprocessConfig
 
-	newConfig.Command = strings.ReplaceAll(per.config.SubprocessConfig.Command, portTemplate, port)
+	newConfig.Command = per.replacePlaceholders(per.config.SubprocessConfig.Command, newPort)
 
+	// Fill a new env, the templates of the config are used again by the next starts of the subprocess
+	newConfig.Env = make([]subprocessmanager.EnvConfig, len(per.config.SubprocessConfig.Env))
 	for i, env := range per.config.SubprocessConfig.Env {
-		newConfig.Env[i].Value = strings.ReplaceAll(env.Value, portTemplate, port)
+		newConfig.Env[i] = subprocessmanager.EnvConfig{Name: env.Name, Value: per.replacePlaceholders(env.Value, newPort)}
 	}
 
 	return &newConfig
 }
 
+// replacePlaceholders returns the string with its placeholders replaced by the port, the temporary directory and the paths of the config files
+func (per *prometheusExecReceiver) replacePlaceholders(value string, port int) string {
+	value = strings.ReplaceAll(value, portTemplate, strconv.Itoa(port))
+	value = strings.ReplaceAll(value, tmpDirTemplate, per.tmpDir)
+	return configFileTemplate.ReplaceAllStringFunc(value, func(match string) string {
+		// The config file names are lower-cased when the config is loaded
+		return filepath.Join(per.tmpDir, strings.ToLower(configFileTemplate.FindStringSubmatch(match)[1]))
+	})
+}
+
+// writeConfigFiles writes the config files to the temporary directory, after filling their placeholders, before each start of the subprocess since the port may change
+func (per *prometheusExecReceiver) writeConfigFiles(port int) error {
+	for name, content := range per.config.ConfigFiles {
+		if err := ioutil.WriteFile(filepath.Join(per.tmpDir, name), []byte(per.replacePlaceholders(content, port)), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // generateRandomPort will generate a random available port
 func generateRandomPort() (int, error) {
 	listener, err := net.Listen("tcp", ":0")
@@ -305,19 +854,73 @@ func generateRandomPort() (int, error) {
 	return listener.Addr().(*net.TCPAddr).Port, nil
 }
 
-// getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm
-func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int) time.Duration {
+// getDelay will compute the delay for a given process according to its crash count and time alive using an exponential backoff algorithm, capped by maxDelay unless 0
+func getDelay(elapsed time.Duration, healthyProcessDuration time.Duration, crashCount int, healthyCrashCount int, initialDelay time.Duration, maxDelay time.Duration) time.Duration {
 	// Return the initialDelay if the process is healthy (lasted longer than health duration) or has less or equal the allowed amount of crashes
 	if elapsed > healthyProcessDuration || crashCount <= healthyCrashCount {
 		return initialDelay
 	}
 
 	// Return initialDelay times 2 to the power of crashCount-healthyCrashCount (to offset for the allowed crashes) added to a random number
-	return initialDelay * time.Duration(math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64()))
+	factor := math.Pow(delayMultiplier, float64(crashCount-healthyCrashCount)+rand.Float64())
+	// Compare the delays as floats, the delay overflowing after many crashes
+	if maxDelay > 0 && float64(initialDelay)*factor > float64(maxDelay) {
+		return maxDelay
+	}
+	return initialDelay * time.Duration(factor)
 }
 
-// Shutdown stops the underlying Prometheus receiver.
+// Shutdown stops the subprocess and the underlying Prometheus receiver, waiting for the subprocess to exit gracefully unless the context is done first.
 func (per *prometheusExecReceiver) Shutdown(ctx context.Context) error {
 	close(per.shutdownCh)
+	select {
+	case <-per.doneCh:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+// Start starts the subprocesses and their underlying Prometheus receivers, each managed by its own loop
+func (mr *multiReceiver) Start(ctx context.Context, host component.Host) error {
+	for _, receiver := range mr.receivers {
+		if err := receiver.Start(ctx, host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown stops all the subprocesses and their underlying Prometheus receivers.
+func (mr *multiReceiver) Shutdown(ctx context.Context) error {
+	for _, receiver := range mr.receivers {
+		if err := receiver.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// resourceAttributesConsumer adds static attributes to the resources of the metrics passed to the next consumer
+type resourceAttributesConsumer struct {
+	next       consumer.MetricsConsumer
+	attributes map[string]string
+}
+
+// ConsumeMetrics adds the attributes to the resources of the metrics, overriding those set by the Prometheus receiver, then passes them to the next consumer
+func (c *resourceAttributesConsumer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		resource := rm.Resource()
+		if resource.IsNil() {
+			resource.InitEmpty()
+		}
+		for k, v := range c.attributes {
+			resource.Attributes().UpsertString(k, v)
+		}
+	}
+	return c.next.ConsumeMetrics(ctx, md)
+}
@@ -16,16 +16,30 @@ package prometheusexecreceiver
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
 	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	configutil "github.com/prometheus/common/config"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	sdconfig "github.com/prometheus/prometheus/discovery/config"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -68,12 +82,190 @@ func assertErrorWhenExecKeyMissing(t *testing.T, errorReceiverConfig configmodel
 	assert.Error(t, err, "new() didn't return an error")
 }
 
+// TestInvalidSubprocessConfig makes sure new() returns an error for invalid subprocess settings
+func TestInvalidSubprocessConfig(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/test",
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "mysqld_exporter",
+			Nice:    -30,
+		},
+	}
+	_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+	assert.EqualError(t, err, "invalid subprocess configuration in config file for prometheus_exec/test: nice must be between -20 and 19")
+}
+
+// TestInvalidScrapeConfig makes sure new() returns an error for unsupported schemes and conflicting credentials
+func TestInvalidScrapeConfig(t *testing.T) {
+	invalidScrapeConfigTests := []struct {
+		name         string
+		scrapeConfig ScrapeConfig
+		wantErr      string
+	}{
+		{
+			name:         "unsupported scheme",
+			scrapeConfig: ScrapeConfig{Scheme: "ftp"},
+			wantErr:      `invalid scrape configuration in config file for prometheus_exec/test: unsupported scheme "ftp"`,
+		},
+		{
+			name: "basic auth and bearer token",
+			scrapeConfig: ScrapeConfig{
+				BasicAuth:   &BasicAuthConfig{Username: "user"},
+				BearerToken: "token",
+			},
+			wantErr: "invalid scrape configuration in config file for prometheus_exec/test: at most one of basic_auth, bearer_token & bearer_token_file must be configured",
+		},
+		{
+			name:         "unsupported readiness probe",
+			scrapeConfig: ScrapeConfig{ReadinessProbe: ReadinessProbeConfig{Type: "exec"}},
+			wantErr:      `invalid scrape configuration in config file for prometheus_exec/test: unsupported readiness probe type "exec"`,
+		},
+	}
+
+	for _, test := range invalidScrapeConfigTests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/test",
+				},
+				ScrapeConfig: test.scrapeConfig,
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+				},
+			}
+			_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+// TestInvalidConfigFiles makes sure new() returns an error for config file names escaping the temporary directory and references to undefined config files
+func TestInvalidConfigFiles(t *testing.T) {
+	invalidConfigFilesTests := []struct {
+		name        string
+		command     string
+		configFiles map[string]string
+		wantErr     string
+	}{
+		{
+			name:        "path in name",
+			command:     "mysqld_exporter",
+			configFiles: map[string]string{"../my.cnf": "[client]"},
+			wantErr:     `invalid config_files in config file for prometheus_exec/test: invalid config file name "../my.cnf"`,
+		},
+		{
+			name:    "undefined config file",
+			command: "mysqld_exporter --config.my-cnf={{config_file:my.cnf}}",
+			wantErr: `invalid config_files in config file for prometheus_exec/test: undefined config file "my.cnf"`,
+		},
+		{
+			name:        "undefined config file in content",
+			command:     "mysqld_exporter --config.my-cnf={{config_file:my.cnf}}",
+			configFiles: map[string]string{"my.cnf": "!include {{config_file:client.cnf}}"},
+			wantErr:     `invalid config_files in config file for prometheus_exec/test: undefined config file "client.cnf"`,
+		},
+	}
+
+	for _, test := range invalidConfigFilesTests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/test",
+				},
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: test.command,
+				},
+				ConfigFiles: test.configFiles,
+			}
+			_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
+}
+
+// TestWriteConfigFiles makes sure the config files are written to the temporary directory with their placeholders filled
+func TestWriteConfigFiles(t *testing.T) {
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/test",
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "blackbox_exporter --config.file={{config_file:blackbox.yml}}",
+		},
+		ConfigFiles: map[string]string{
+			"blackbox.yml": "modules:\n  self:\n    prober: http\n    http:\n      headers:\n        Host: localhost:{{port}}\n",
+			"ca.pem":       "{{tmpdir}}",
+		},
+	}
+	assert.True(t, usesTmpDir(cfg))
+
+	receiver, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+	require.NoError(t, err)
+	receiver.tmpDir, err = ioutil.TempDir("", "prometheus_exec_test")
+	require.NoError(t, err)
+	defer os.RemoveAll(receiver.tmpDir)
+
+	require.NoError(t, receiver.writeConfigFiles(9115))
+
+	content, err := ioutil.ReadFile(filepath.Join(receiver.tmpDir, "blackbox.yml"))
+	require.NoError(t, err)
+	assert.Equal(t, "modules:\n  self:\n    prober: http\n    http:\n      headers:\n        Host: localhost:9115\n", string(content))
+	content, err = ioutil.ReadFile(filepath.Join(receiver.tmpDir, "ca.pem"))
+	require.NoError(t, err)
+	assert.Equal(t, receiver.tmpDir, string(content))
+
+	assert.False(t, usesTmpDir(&Config{SubprocessConfig: subprocessmanager.SubprocessConfig{Command: "mysqld_exporter --web.listen-address=:{{port}}"}}))
+}
+
 // TestEndToEnd loads the test config and completes an 2e2 test where Prometheus metrics are scrapped twice from `test_prometheus_exporter.go`
 func TestEndToEnd(t *testing.T) {
 	receiverConfig := loadConfigAssertNoError(t, "prometheus_exec/end_to_end_test/2")
 
 	// e2e test with port undefined by user
 	endToEndScrapeTest(t, receiverConfig, "end-to-end port not defined")
+
+	// The application exits with code 1 after each scrape and is restarted
+	name := receiverConfig.Name()
+	assert.GreaterOrEqual(t, viewValue(t, viewSubprocessRestarts, name), float64(1))
+	assert.GreaterOrEqual(t, viewValue(t, viewSubprocessCrashes, name), float64(1))
+	assert.Equal(t, float64(1), viewValue(t, viewSubprocessExitCode, name))
+}
+
+// viewValue returns the sum or last value of the view for the receiver, 0 if it has none
+func viewValue(t *testing.T, v *view.View, receiver string) float64 {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		if len(row.Tags) != 1 || row.Tags[0].Value != receiver {
+			continue
+		}
+		switch data := row.Data.(type) {
+		case *view.SumData:
+			return data.Value
+		case *view.LastValueData:
+			return data.Value
+		}
+	}
+	return 0
+}
+
+// TestExitCode makes sure the exit codes of the subprocesses are extracted from the errors of their runs
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, exitCode(nil))
+	assert.Equal(t, -1, exitCode(errors.New("process could not start")))
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	require.Error(t, err)
+	assert.Equal(t, 3, exitCode(fmt.Errorf("%w", err)))
 }
 
 // endToEndScrapeTest creates a receiver that invokes `go run test_prometheus_exporter.go` and waits until it has scraped the /metrics endpoint twice - the application will crash between each scrape
@@ -104,6 +296,382 @@ func endToEndScrapeTest(t *testing.T, receiverConfig configmodels.Receiver, test
 	assertTwoUniqueValuesScraped(t, metrics)
 }
 
+// TestMultiEndToEnd loads the test config and makes sure the metrics of both subprocesses listed under execs are scraped
+func TestMultiEndToEnd(t *testing.T) {
+	receiverConfig := loadConfigAssertNoError(t, "prometheus_exec/multi")
+
+	sink := &exportertest.SinkMetricsExporter{}
+	wrapper, err := newMultiReceiver(component.ReceiverCreateParams{Logger: zap.NewNop()}, receiverConfig.(*Config), sink)
+	require.NoError(t, err, "newMultiReceiver() returned an error")
+	require.Len(t, wrapper.receivers, 2)
+
+	ctx := context.Background()
+	err = wrapper.Start(ctx, componenttest.NewNopHost())
+	assert.NoError(t, err, "Start() returned an error")
+	defer func() { assert.NoError(t, wrapper.Shutdown(ctx)) }()
+
+	const waitFor = 20 * time.Second
+	const tick = 100 * time.Millisecond
+	require.Eventuallyf(t, func() bool {
+		jobs := map[string]bool{}
+		for _, metrics := range sink.AllMetrics() {
+			for _, md := range internaldata.MetricsToOC(metrics) {
+				jobs[md.Node.GetServiceInfo().GetName()] = true
+			}
+		}
+		return jobs["multi/one"] && jobs["multi/two"]
+	}, waitFor, tick, "Both subprocesses not scraped after %v", waitFor)
+
+	// The resource attributes of the receiver are overridden by those of the exec, and the external labels are only added to the exec's metrics
+	wantEnvironments := map[string]string{"multi/one": "test", "multi/two": "staging"}
+	for _, metrics := range sink.AllMetrics() {
+		for _, md := range internaldata.MetricsToOC(metrics) {
+			job := md.Node.GetServiceInfo().GetName()
+			assert.Equal(t, wantEnvironments[job], md.Resource.GetLabels()["deployment.environment"], "unexpected environment of %v", job)
+			for _, metric := range md.Metrics {
+				hasExporterLabel := false
+				for _, key := range metric.GetMetricDescriptor().GetLabelKeys() {
+					hasExporterLabel = hasExporterLabel || key.GetKey() == "exporter"
+				}
+				assert.Equal(t, job == "multi/one", hasExporterLabel, "unexpected labels of %v in %v", metric.GetMetricDescriptor().GetName(), job)
+			}
+		}
+	}
+}
+
+// TestInvalidExternalLabels makes sure new() returns an error for external labels which aren't valid Prometheus label names
+func TestInvalidExternalLabels(t *testing.T) {
+	for _, name := range []string{"service.name", "__address__", "1st"} {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/test",
+				},
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+				},
+				ExternalLabels: map[string]string{name: "value"},
+			}
+			_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+			assert.EqualError(t, err, fmt.Sprintf("invalid external_labels in config file for prometheus_exec/test: invalid label name %q", name))
+		})
+	}
+}
+
+// TestResourceAttributesConsumer makes sure the resource attributes are added to the metrics passed to the next consumer, overriding the existing ones
+func TestResourceAttributesConsumer(t *testing.T) {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(2)
+	md.ResourceMetrics().At(0).Resource().InitEmpty()
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("service.name", "mysql")
+	md.ResourceMetrics().At(0).Resource().Attributes().InsertString("deployment.environment", "dev")
+
+	sink := &exportertest.SinkMetricsExporter{}
+	c := &resourceAttributesConsumer{next: sink, attributes: map[string]string{"deployment.environment": "prod"}}
+	require.NoError(t, c.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	rms := sink.AllMetrics()[0].ResourceMetrics()
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"service.name":           pdata.NewAttributeValueString("mysql"),
+		"deployment.environment": pdata.NewAttributeValueString("prod"),
+	}).Sort(), rms.At(0).Resource().Attributes().Sort())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		"deployment.environment": pdata.NewAttributeValueString("prod"),
+	}), rms.At(1).Resource().Attributes())
+}
+
+// TestWaitForReadiness makes sure the readiness channel is only closed once the subprocess is ready, or once the probe timed out
+func TestWaitForReadiness(t *testing.T) {
+	var ready int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/custom/metrics" || atomic.LoadInt32(&ready) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	_, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+	closedPort, err := generateRandomPort()
+	require.NoError(t, err)
+
+	waitForReadinessTests := []struct {
+		name      string
+		probe     ReadinessProbeConfig
+		port      int
+		wantReady bool
+	}{
+		{
+			name:      "no probe",
+			port:      closedPort,
+			wantReady: true,
+		},
+		{
+			name:      "tcp probe, port open",
+			probe:     ReadinessProbeConfig{Type: "tcp", Interval: 10 * time.Millisecond},
+			port:      port,
+			wantReady: true,
+		},
+		{
+			name:      "tcp probe, port closed",
+			probe:     ReadinessProbeConfig{Type: "tcp", Interval: 10 * time.Millisecond},
+			port:      closedPort,
+			wantReady: false,
+		},
+		{
+			name:      "http probe, endpoint not ready",
+			probe:     ReadinessProbeConfig{Type: "http", Interval: 10 * time.Millisecond},
+			port:      port,
+			wantReady: false,
+		},
+	}
+
+	for _, test := range waitForReadinessTests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/test",
+				},
+				ScrapeConfig: ScrapeConfig{
+					MetricsPath:    "/custom/metrics",
+					ReadinessProbe: test.probe,
+				},
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+				},
+			}
+			per, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ready := per.waitForReadiness(ctx, test.port)
+			select {
+			case <-ready:
+				assert.True(t, test.wantReady, "subprocess reported ready")
+			case <-time.After(100 * time.Millisecond):
+				assert.False(t, test.wantReady, "subprocess not reported ready")
+			}
+		})
+	}
+
+	// The http probe succeeds once the endpoint responds with 200
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/test",
+		},
+		ScrapeConfig: ScrapeConfig{
+			MetricsPath:    "/custom/metrics",
+			ReadinessProbe: ReadinessProbeConfig{Type: "http", Interval: 10 * time.Millisecond, Timeout: 10 * time.Second},
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "mysqld_exporter",
+		},
+	}
+	per, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+	require.NoError(t, err)
+	readyCh := per.waitForReadiness(context.Background(), port)
+	atomic.StoreInt32(&ready, 1)
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		t.Error("subprocess not reported ready once the endpoint responded with 200")
+	}
+
+	// The channel is closed once the probe timed out
+	cfg.ScrapeConfig.ReadinessProbe = ReadinessProbeConfig{Type: "tcp", Interval: 10 * time.Millisecond, Timeout: 50 * time.Millisecond}
+	readyCh = per.waitForReadiness(context.Background(), closedPort)
+	select {
+	case <-readyCh:
+	case <-time.After(5 * time.Second):
+		t.Error("subprocess not reported ready after the probe timed out")
+	}
+}
+
+func TestGetExecConfigs(t *testing.T) {
+	execConfigsTests := []struct {
+		name    string
+		config  *Config
+		want    []*Config
+		wantErr string
+	}{
+		{
+			name: "top level and listed execs",
+			config: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/exporters",
+				},
+				ScrapeInterval: 60 * time.Second,
+				Port:           9104,
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+				},
+				ResourceAttributes: map[string]string{"deployment.environment": "prod", "service.namespace": "db"},
+				Restart:            RestartConfig{Policy: "on_failure", MaxDelay: time.Minute},
+				Execs: []ExecConfig{
+					{
+						Name: "apache",
+						SubprocessConfig: subprocessmanager.SubprocessConfig{
+							Command: "apache_exporter --port:{{port}}",
+							Env: []subprocessmanager.EnvConfig{
+								{
+									Name:  "SECONDARY_PORT",
+									Value: "{{port}}",
+								},
+							},
+						},
+						ResourceAttributes: map[string]string{"service.namespace": "web"},
+						ExternalLabels:     map[string]string{"exporter": "apache"},
+					},
+					{
+						Name:           "postgres",
+						ScrapeInterval: 90 * time.Second,
+						Port:           9187,
+						SubprocessConfig: subprocessmanager.SubprocessConfig{
+							Command:     "postgres_exporter",
+							GracePeriod: 10 * time.Second,
+						},
+						Restart: RestartConfig{Policy: "never", InitialDelay: 5 * time.Second},
+					},
+				},
+			},
+			want: []*Config{
+				{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						TypeVal: "prometheus_exec",
+						NameVal: "prometheus_exec/exporters",
+					},
+					ScrapeInterval: 60 * time.Second,
+					Port:           9104,
+					SubprocessConfig: subprocessmanager.SubprocessConfig{
+						Command: "mysqld_exporter",
+					},
+					ResourceAttributes: map[string]string{"deployment.environment": "prod", "service.namespace": "db"},
+					Restart:            RestartConfig{Policy: "on_failure", MaxDelay: time.Minute},
+				},
+				{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						TypeVal: "prometheus_exec",
+						NameVal: "prometheus_exec/exporters/apache",
+					},
+					ScrapeInterval: 60 * time.Second,
+					SubprocessConfig: subprocessmanager.SubprocessConfig{
+						Command: "apache_exporter --port:{{port}}",
+						Env: []subprocessmanager.EnvConfig{
+							{
+								Name:  "SECONDARY_PORT",
+								Value: "{{port}}",
+							},
+						},
+					},
+					ResourceAttributes: map[string]string{"deployment.environment": "prod", "service.namespace": "web"},
+					ExternalLabels:     map[string]string{"exporter": "apache"},
+					Restart:            RestartConfig{Policy: "on_failure", MaxDelay: time.Minute},
+				},
+				{
+					ReceiverSettings: configmodels.ReceiverSettings{
+						TypeVal: "prometheus_exec",
+						NameVal: "prometheus_exec/exporters/postgres",
+					},
+					ScrapeInterval: 90 * time.Second,
+					Port:           9187,
+					SubprocessConfig: subprocessmanager.SubprocessConfig{
+						Command:     "postgres_exporter",
+						GracePeriod: 10 * time.Second,
+					},
+					ResourceAttributes: map[string]string{"deployment.environment": "prod", "service.namespace": "db"},
+					Restart:            RestartConfig{Policy: "never", InitialDelay: 5 * time.Second, MaxDelay: time.Minute},
+				},
+			},
+		},
+		{
+			name: "missing name",
+			config: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec",
+				},
+				Execs: []ExecConfig{
+					{
+						SubprocessConfig: subprocessmanager.SubprocessConfig{
+							Command: "apache_exporter",
+						},
+					},
+				},
+			},
+			wantErr: "no name entered for exec 0 in config file for prometheus_exec",
+		},
+		{
+			name: "duplicate name",
+			config: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec",
+				},
+				Execs: []ExecConfig{
+					{
+						Name: "apache",
+					},
+					{
+						Name: "apache",
+					},
+				},
+			},
+			wantErr: `exec "apache" defined more than once in config file for prometheus_exec`,
+		},
+		{
+			name: "duplicate port",
+			config: &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec",
+				},
+				Port: 9117,
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "apache_exporter",
+				},
+				Execs: []ExecConfig{
+					{
+						Name: "apache",
+						Port: 9117,
+					},
+				},
+			},
+			wantErr: "port 9117 assigned more than once in config file for prometheus_exec",
+		},
+	}
+
+	for _, test := range execConfigsTests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := getExecConfigs(test.config)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	// An exec without command fails like a receiver without exec key
+	_, err := newMultiReceiver(component.ReceiverCreateParams{Logger: zap.NewNop()}, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec",
+		},
+		Execs: []ExecConfig{{Name: "apache"}},
+	}, nil)
+	assert.EqualError(t, err, "no command to execute entered in config file for prometheus_exec/apache")
+}
+
 // assertTwoUniqueValuesScraped iterates over the found metrics and returns true if it finds at least 2 unique metrics, meaning the endpoint
 // was successfully scraped twice AND the subprocess being handled was stopped and restarted
 func assertTwoUniqueValuesScraped(t *testing.T, metricsSlice []pdata.Metrics) {
@@ -283,6 +851,73 @@ func TestConfigBuilderFunctions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:       "https config",
+			customName: "prometheus_exec/node",
+			config: &Config{
+				ScrapeInterval: 30 * time.Second,
+				Port:           9100,
+				ScrapeConfig: ScrapeConfig{
+					MetricsPath: "/node/metrics",
+					Scheme:      "https",
+					TLSConfig: TLSConfig{
+						CAFile:     "ca.pem",
+						ServerName: "node",
+					},
+					BasicAuth: &BasicAuthConfig{
+						Username: "user",
+						Password: "secret",
+					},
+				},
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "node_exporter",
+					Env:     []subprocessmanager.EnvConfig{},
+				},
+			},
+			wantReceiverConfig: &prometheusreceiver.Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/node",
+				},
+				PrometheusConfig: &config.Config{
+					ScrapeConfigs: []*config.ScrapeConfig{
+						{
+							ScrapeInterval:  model.Duration(30 * time.Second),
+							ScrapeTimeout:   model.Duration(10 * time.Second),
+							Scheme:          "https",
+							MetricsPath:     "/node/metrics",
+							JobName:         "node",
+							HonorLabels:     false,
+							HonorTimestamps: true,
+							HTTPClientConfig: configutil.HTTPClientConfig{
+								BasicAuth: &configutil.BasicAuth{
+									Username: "user",
+									Password: "secret",
+								},
+								TLSConfig: configutil.TLSConfig{
+									CAFile:     "ca.pem",
+									ServerName: "node",
+								},
+							},
+							ServiceDiscoveryConfig: sdconfig.ServiceDiscoveryConfig{
+								StaticConfigs: []*targetgroup.Group{
+									{
+										Targets: []model.LabelSet{
+											{model.AddressLabel: model.LabelValue("localhost:9100")},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			wantSubprocessConfig: &subprocessmanager.SubprocessConfig{
+				Command: "node_exporter",
+				Env:     []subprocessmanager.EnvConfig{},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, test := range configTests {
@@ -357,8 +992,8 @@ func TestExtractName(t *testing.T) {
 	}
 }
 
-func TestFillPortPlaceholders(t *testing.T) {
-	fillPortPlaceholdersTests := []struct {
+func TestFillPlaceholders(t *testing.T) {
+	fillPlaceholdersTests := []struct {
 		name    string
 		wrapper *prometheusExecReceiver
 		newPort int
@@ -497,17 +1132,73 @@ func TestFillPortPlaceholders(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "temporary directory and config files",
+			wrapper: &prometheusExecReceiver{
+				tmpDir: filepath.Join("tmp", "prometheus_exec_1"),
+				config: &Config{
+					SubprocessConfig: subprocessmanager.SubprocessConfig{
+						Command: "node_exporter --web.config={{config_file:Web.yml}} --collector.textfile.directory={{tmpdir}}",
+						Env: []subprocessmanager.EnvConfig{
+							{
+								Name:  "CONFIG",
+								Value: "{{config_file:web.yml}}",
+							},
+						},
+					},
+					ConfigFiles: map[string]string{"web.yml": "tls_server_config: {}"},
+				},
+				subprocessConfig: &subprocessmanager.SubprocessConfig{
+					Env: []subprocessmanager.EnvConfig{
+						{
+							Name: "CONFIG",
+						},
+					},
+				},
+			},
+			newPort: 9100,
+			want: &subprocessmanager.SubprocessConfig{
+				Command: "node_exporter --web.config=" + filepath.Join("tmp", "prometheus_exec_1", "web.yml") + " --collector.textfile.directory=" + filepath.Join("tmp", "prometheus_exec_1"),
+				Env: []subprocessmanager.EnvConfig{
+					{
+						Name:  "CONFIG",
+						Value: filepath.Join("tmp", "prometheus_exec_1", "web.yml"),
+					},
+				},
+			},
+		},
 	}
 
-	for _, test := range fillPortPlaceholdersTests {
+	for _, test := range fillPlaceholdersTests {
 		t.Run(test.name, func(t *testing.T) {
-			got := test.wrapper.fillPortPlaceholders(test.newPort)
+			got := test.wrapper.fillPlaceholders(test.newPort)
 			assert.Equal(t, test.want.Command, got.Command)
 			assert.Equal(t, test.want.Env, got.Env)
 		})
 	}
 }
 
+// TestFillPlaceholdersNewPort makes sure the templates are kept when the placeholders are filled, for a restart on a new port
+func TestFillPlaceholdersNewPort(t *testing.T) {
+	cfg := &Config{
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "exporter --port={{port}}",
+			Env:     []subprocessmanager.EnvConfig{{Name: "PORT", Value: "{{port}}"}},
+		},
+	}
+	per := &prometheusExecReceiver{config: cfg, subprocessConfig: getSubprocessConfig(cfg)}
+
+	first := per.fillPlaceholders(1111)
+	assert.Equal(t, "exporter --port=1111", first.Command)
+	assert.Equal(t, []subprocessmanager.EnvConfig{{Name: "PORT", Value: "1111"}}, first.Env)
+
+	second := per.fillPlaceholders(2222)
+	assert.Equal(t, "exporter --port=2222", second.Command)
+	assert.Equal(t, []subprocessmanager.EnvConfig{{Name: "PORT", Value: "2222"}}, second.Env)
+	assert.Equal(t, []subprocessmanager.EnvConfig{{Name: "PORT", Value: "1111"}}, first.Env)
+	assert.Equal(t, "{{port}}", cfg.SubprocessConfig.Env[0].Value)
+}
+
 func TestGetDelayAndComputeCrashCount(t *testing.T) {
 	var (
 		getDelayAndComputeCrashCountTests = []struct {
@@ -576,7 +1267,7 @@ func TestGetDelayAndComputeCrashCount(t *testing.T) {
 	// getDelay() test
 	t.Run("GetDelay test", func(t *testing.T) {
 		for _, test := range getDelayAndComputeCrashCountTests {
-			got := getDelay(test.elapsed, test.healthyProcessTime, test.crashCount, test.healthyCrashCount)
+			got := getDelay(test.elapsed, test.healthyProcessTime, test.crashCount, test.healthyCrashCount, defaultInitialDelay, 0)
 
 			if test.wantDelay > 0 {
 				assert.Equalf(t, test.wantDelay, got, "getDelay() '%v', got = %v, want %v", test.name, got, test.wantDelay)
@@ -595,8 +1286,172 @@ func TestGetDelayAndComputeCrashCount(t *testing.T) {
 
 	for _, test := range getDelayAndComputeCrashCountTests {
 		t.Run(test.name, func(t *testing.T) {
-			got := per.computeCrashCount(test.elapsed, test.crashCount)
+			got := per.computeCrashCount(test.elapsed, test.healthyProcessTime, test.crashCount)
 			assert.Equal(t, test.wantCrashCount, got)
 		})
 	}
+
+	// The delay is capped by the max delay, even once it overflows
+	assert.Equal(t, 2*time.Second, getDelay(15*time.Second, 30*time.Minute, 10, 3, defaultInitialDelay, 2*time.Second))
+	assert.Equal(t, time.Minute, getDelay(15*time.Second, 30*time.Minute, 100, 3, defaultInitialDelay, time.Minute))
+	assert.Equal(t, 500*time.Millisecond, getDelay(15*time.Second, 30*time.Minute, 1, 3, 500*time.Millisecond, time.Minute))
+}
+
+// TestShouldRestart makes sure the subprocesses are restarted according to the restart policies
+func TestShouldRestart(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	shouldRestartTests := []struct {
+		policy        string
+		subprocessErr error
+		want          bool
+	}{
+		{policy: restartPolicyAlways, want: true},
+		{policy: restartPolicyAlways, subprocessErr: exitErr, want: true},
+		{policy: restartPolicyOnFailure, want: false},
+		{policy: restartPolicyOnFailure, subprocessErr: exitErr, want: true},
+		{policy: restartPolicyNever, want: false},
+		{policy: restartPolicyNever, subprocessErr: exitErr, want: false},
+	}
+
+	for _, test := range shouldRestartTests {
+		per := &prometheusExecReceiver{restartConfig: getRestartConfig(RestartConfig{Policy: test.policy})}
+		assert.Equal(t, test.want, per.shouldRestart(test.subprocessErr), "policy %v, error %v", test.policy, test.subprocessErr)
+	}
+}
+
+// TestRestartPolicyNever makes sure a one-shot subprocess isn't restarted once it exited
+func TestRestartPolicyNever(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/one_shot",
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "sh -c 'exit 0'",
+		},
+		Restart: RestartConfig{Policy: restartPolicyNever},
+	}
+	receiver, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, &exportertest.SinkMetricsExporter{})
+	require.NoError(t, err)
+
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	select {
+	case <-receiver.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("manageProcess() didn't return once the subprocess exited")
+	}
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+	assert.Equal(t, float64(0), viewValue(t, viewSubprocessRestarts, cfg.Name()))
+	assert.Equal(t, float64(0), viewValue(t, viewSubprocessExitCode, cfg.Name()))
+}
+
+// TestResourceUsage makes sure the resource usage of the running subprocess is recorded, and reset once it exited
+func TestResourceUsage(t *testing.T) {
+	if !subprocessmanager.UsageSupported {
+		t.Skip("the resource usage is not supported on " + runtime.GOOS)
+	}
+
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/resource_usage",
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: "sleep 1",
+		},
+		Restart: RestartConfig{Policy: restartPolicyNever},
+	}
+	receiver, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, &exportertest.SinkMetricsExporter{})
+	require.NoError(t, err)
+
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Eventually(t, func() bool {
+		return viewValue(t, viewSubprocessMemoryRSS, cfg.Name()) > 0
+	}, 5*time.Second, 10*time.Millisecond, "the resident set size of the subprocess wasn't recorded")
+	assert.Greater(t, viewValue(t, viewSubprocessOpenFDs, cfg.Name()), float64(0))
+
+	select {
+	case <-receiver.doneCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("manageProcess() didn't return once the subprocess exited")
+	}
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+	assert.Equal(t, float64(0), viewValue(t, viewSubprocessMemoryRSS, cfg.Name()))
+	assert.Equal(t, float64(0), viewValue(t, viewSubprocessOpenFDs, cfg.Name()))
+	assert.Equal(t, float64(0), viewValue(t, viewSubprocessCPUTime, cfg.Name()))
+}
+
+// TestPortConflict makes sure a subprocess failing to listen on its generated port is restarted right away on a new port, without counting it as a crash, a limited number of times in a row
+func TestPortConflict(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+
+	cfg := &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "prometheus_exec",
+			NameVal: "prometheus_exec/port_conflict",
+		},
+		SubprocessConfig: subprocessmanager.SubprocessConfig{
+			Command: `sh -c "echo 'listen tcp :{{port}}: bind: address already in use' >&2; exit 1"`,
+		},
+		// The subprocess is only restarted because of the port conflicts
+		Restart: RestartConfig{Policy: restartPolicyNever},
+	}
+	receiver, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, &exportertest.SinkMetricsExporter{})
+	require.NoError(t, err)
+
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	select {
+	case <-receiver.doneCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("manageProcess() didn't return once the port conflict retries were exhausted")
+	}
+	assert.NoError(t, receiver.Shutdown(context.Background()))
+	assert.Equal(t, float64(maxPortConflictRetries), viewValue(t, viewSubprocessRestarts, cfg.Name()))
+
+	// A configured port isn't changed, the conflict is handled as any other failure
+	receiver.port = 9104
+	assert.False(t, receiver.isPortConflict(fmt.Errorf("exit status 1: %w", subprocessmanager.ErrAddressInUse)))
+}
+
+// TestInvalidRestartConfig makes sure new() returns an error for unsupported restart policies and negative restart settings
+func TestInvalidRestartConfig(t *testing.T) {
+	invalidRestartConfigTests := []struct {
+		name    string
+		restart RestartConfig
+		wantErr string
+	}{
+		{
+			name:    "unsupported policy",
+			restart: RestartConfig{Policy: "sometimes"},
+			wantErr: `invalid restart configuration in config file for prometheus_exec/test: unsupported restart policy "sometimes"`,
+		},
+		{
+			name:    "negative delay",
+			restart: RestartConfig{InitialDelay: -time.Second},
+			wantErr: "invalid restart configuration in config file for prometheus_exec/test: healthy_process_time, healthy_crash_count, initial_delay and max_delay must not be negative",
+		},
+	}
+
+	for _, test := range invalidRestartConfigTests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := &Config{
+				ReceiverSettings: configmodels.ReceiverSettings{
+					TypeVal: "prometheus_exec",
+					NameVal: "prometheus_exec/test",
+				},
+				SubprocessConfig: subprocessmanager.SubprocessConfig{
+					Command: "mysqld_exporter",
+				},
+				Restart: test.restart,
+			}
+			_, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, nil)
+			assert.EqualError(t, err, test.wantErr)
+		})
+	}
 }
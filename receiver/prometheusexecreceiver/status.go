@@ -0,0 +1,82 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/config"
+	"gopkg.in/yaml.v2"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/zpagescontribextension"
+)
+
+// statusKind is the kind of component the status is reported for on the /debug/statusz page of the zpages_contrib extension
+const statusKind = "receiver"
+
+// subprocessStatus holds the effective settings of the subprocess currently run, reported on the /debug/statusz page so that operators can check what is actually run and scraped
+type subprocessStatus struct {
+	mu           sync.Mutex
+	command      string
+	port         int
+	scrapeConfig string
+	// started is the time the subprocess was started, zero if it isn't running
+	started time.Time
+}
+
+// register adds the status to the /debug/statusz page, until the returned function is called
+func (s *subprocessStatus) register(name string) func() {
+	return zpagescontribextension.RegisterStatus(statusKind, name, s.entries)
+}
+
+// update sets the command with its placeholders filled, the port and the scrape config of the subprocess about to be started
+func (s *subprocessStatus) update(command string, port int, scrapeConfig *config.ScrapeConfig) {
+	// The secrets of the scrape config are marshaled as <secret>
+	out, err := yaml.Marshal(scrapeConfig)
+	if err != nil {
+		out = []byte(err.Error())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.command = command
+	s.port = port
+	s.scrapeConfig = string(out)
+}
+
+// setStarted sets the time the subprocess was started, zero once it exited
+func (s *subprocessStatus) setStarted(started time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.started = started
+}
+
+func (s *subprocessStatus) entries() []zpagescontribextension.StatusEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := "not running"
+	if !s.started.IsZero() {
+		state = "running since " + s.started.Format(time.RFC3339)
+	}
+	return []zpagescontribextension.StatusEntry{
+		{Name: "Command", Value: s.command},
+		{Name: "Port", Value: strconv.Itoa(s.port)},
+		{Name: "State", Value: state},
+		{Name: "Scrape config", Value: s.scrapeConfig},
+	}
+}
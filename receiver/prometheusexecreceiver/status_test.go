@@ -0,0 +1,56 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package prometheusexecreceiver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+// TestSubprocessStatus makes sure the effective settings of the subprocess are reported once its receiver is created
+func TestSubprocessStatus(t *testing.T) {
+	receiverConfig := loadConfigAssertNoError(t, "prometheus_exec/end_to_end_test/2")
+	per, err := new(component.ReceiverCreateParams{Logger: zap.NewNop()}, receiverConfig.(*Config), &exportertest.SinkMetricsExporter{})
+	require.NoError(t, err)
+
+	entries := per.status.entries()
+	require.Len(t, entries, 4)
+	assert.Equal(t, "not running", entries[2].Value)
+
+	_, port, err := per.createReceiver(context.Background())
+	require.NoError(t, err)
+
+	entries = per.status.entries()
+	assert.Equal(t, "Command", entries[0].Name)
+	assert.Equal(t, fmt.Sprintf("go run ./testdata/end_to_end_metrics_test/test_prometheus_exporter.go %v", port), entries[0].Value)
+	assert.Equal(t, "Port", entries[1].Name)
+	assert.Equal(t, fmt.Sprint(port), entries[1].Value)
+	assert.Equal(t, "Scrape config", entries[3].Name)
+	assert.Contains(t, entries[3].Value, "job_name: end_to_end_test/2")
+	assert.Contains(t, entries[3].Value, fmt.Sprintf("localhost:%v", port))
+
+	started := time.Date(2020, 9, 15, 12, 0, 0, 0, time.UTC)
+	per.status.setStarted(started)
+	assert.Equal(t, "State", per.status.entries()[2].Name)
+	assert.Equal(t, "running since 2020-09-15T12:00:00Z", per.status.entries()[2].Value)
+}
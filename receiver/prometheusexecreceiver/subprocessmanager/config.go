@@ -14,18 +14,53 @@
 
 package subprocessmanager
 
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
 // SubprocessConfig is the config definition for the subprocess manager
 type SubprocessConfig struct {
-	// Command is the command to be run (binary + flags, separated by commas)
+	// Command is the command to be run (binary + flags, separated by commas), ${VAR} references to the Collector's environment variables being expanded in each argument
 	Command string `mapstructure:"exec"`
 	// Env is a list of env variables to pass to a specific command
 	Env []EnvConfig `mapstructure:"env"`
+	// InheritEnv is whether the subprocess inherits the environment variables of the Collector, true by default
+	InheritEnv *bool `mapstructure:"inherit_env"`
+	// GracePeriod is the time the subprocess is given to exit after being sent SIGTERM, before being killed
+	GracePeriod time.Duration `mapstructure:"grace_period"`
+	// WorkingDir is the working directory of the subprocess, the Collector's by default
+	WorkingDir string `mapstructure:"working_directory"`
+	// UID is the user id the subprocess runs as, the Collector's by default (not supported on Windows)
+	UID *uint32 `mapstructure:"uid"`
+	// GID is the group id the subprocess runs as, the Collector's by default (not supported on Windows)
+	GID *uint32 `mapstructure:"gid"`
+	// Nice is the niceness of the subprocess, from -20 (highest priority) to 19 (lowest priority), the Collector's by default (Linux only)
+	Nice int `mapstructure:"nice"`
+	// MaxMemoryMiB is the maximum size in MiB of the virtual memory of the subprocess, unlimited by default (Linux only)
+	MaxMemoryMiB uint64 `mapstructure:"max_memory_mib"`
+}
+
+// Validate checks the subprocess settings are valid and supported on the current platform
+func (proc *SubprocessConfig) Validate() error {
+	if (proc.UID != nil || proc.GID != nil) && !credentialSupported {
+		return fmt.Errorf("uid and gid are not supported on %v", runtime.GOOS)
+	}
+	if proc.Nice < -20 || proc.Nice > 19 {
+		return errors.New("nice must be between -20 and 19")
+	}
+	if (proc.Nice != 0 || proc.MaxMemoryMiB != 0) && !limitsSupported {
+		return fmt.Errorf("nice and max_memory_mib are not supported on %v", runtime.GOOS)
+	}
+	return nil
 }
 
 // EnvConfig is the config definition of each key-value pair for environment variables
 type EnvConfig struct {
 	// Name is the name of the environment variable
 	Name string `mapstructure:"name"`
-	// Value is the value of the variable
+	// Value is the value of the variable, ${VAR} references to the Collector's environment variables being expanded
 	Value string `mapstructure:"value"`
 }
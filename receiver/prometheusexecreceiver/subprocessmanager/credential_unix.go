@@ -0,0 +1,42 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+// +build !windows
+
+package subprocessmanager
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+const credentialSupported = true
+
+// setCredential makes the subprocess run as the configured user and group, the Collector's ones being used for those not configured
+func setCredential(cmd *exec.Cmd, proc *SubprocessConfig) {
+	if proc.UID == nil && proc.GID == nil {
+		return
+	}
+	credential := &syscall.Credential{Uid: uint32(os.Getuid()), Gid: uint32(os.Getgid())}
+	if proc.UID != nil {
+		credential.Uid = *proc.UID
+	}
+	if proc.GID != nil {
+		credential.Gid = *proc.GID
+	}
+	// The supplementary groups of the Collector are not passed on to the subprocess
+	cmd.SysProcAttr = &syscall.SysProcAttr{Credential: credential}
+}
@@ -12,16 +12,13 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package awsecscontainermetrics
+//go:build windows
+// +build windows
 
-import (
-	"testing"
+package subprocessmanager
 
-	"github.com/stretchr/testify/require"
-)
+import "os/exec"
 
-func TestGenerateDummyMetrics(t *testing.T) {
-	data := GenerateDummyMetrics()
+const credentialSupported = false
 
-	require.NotNil(t, data)
-}
+func setCredential(*exec.Cmd, *SubprocessConfig) {}
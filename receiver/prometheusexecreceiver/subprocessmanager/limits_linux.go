@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package subprocessmanager
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const limitsSupported = true
+
+// startProcess starts the subprocess with the configured niceness
+func startProcess(cmd *exec.Cmd, proc *SubprocessConfig) error {
+	if proc.Nice == 0 {
+		return cmd.Start()
+	}
+
+	// The niceness is per thread on Linux and inherited by the processes the thread forks, set it on a thread
+	// dedicated to the start, which is terminated once the goroutine returns as it isn't unlocked
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := unix.Setpriority(unix.PRIO_PROCESS, 0, proc.Nice); err != nil {
+			errCh <- fmt.Errorf("could not set the niceness: %w", err)
+			return
+		}
+		errCh <- cmd.Start()
+	}()
+	return <-errCh
+}
+
+// applyLimits sets the maximum memory of the started subprocess
+func applyLimits(pid int, proc *SubprocessConfig) error {
+	if proc.MaxMemoryMiB == 0 {
+		return nil
+	}
+	limit := proc.MaxMemoryMiB * 1024 * 1024
+	if err := prlimit(pid, unix.RLIMIT_AS, &unix.Rlimit{Cur: limit, Max: limit}); err != nil {
+		return fmt.Errorf("could not set the maximum memory: %w", err)
+	}
+	return nil
+}
+
+// prlimit sets the resource limit of another process, as setrlimit only applies to the Collector itself
+func prlimit(pid int, resource int, limit *unix.Rlimit) error {
+	_, _, errno := unix.RawSyscall6(unix.SYS_PRLIMIT64, uintptr(pid), uintptr(resource), uintptr(unsafe.Pointer(limit)), 0, 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
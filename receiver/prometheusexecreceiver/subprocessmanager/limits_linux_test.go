@@ -0,0 +1,58 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package subprocessmanager
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRunLimits(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subprocessmanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The niceness is the 19th field of /proc/self/stat, that of the cut process inheriting it from sh. The memory limit is
+	// only set once the subprocess is started, leave it the time to be set before reading it
+	process := &SubprocessConfig{
+		Command:      `sh -c "cut -d ' ' -f 19 /proc/self/stat > limits; sleep 0.5; ulimit -v >> limits"`,
+		WorkingDir:   dir,
+		Nice:         5,
+		MaxMemoryMiB: 256,
+	}
+	if _, err := process.Run(context.Background(), zap.NewNop()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	limits, err := ioutil.ReadFile(filepath.Join(dir, "limits"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// ulimit -v reports the limit in KiB
+	if got, want := strings.Fields(string(limits)), []string{"5", "262144"}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("Run() limits = %v, want %v", got, want)
+	}
+}
@@ -17,19 +17,64 @@ package subprocessmanager
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/kballard/go-shellquote"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
+const (
+	// defaultGracePeriod is the default time the subprocess is given to exit after being sent SIGTERM
+	defaultGracePeriod = 5 * time.Second
+	// outputDrainTimeout is the time the outputs of the subprocess are still read once it exited, its own children may keep them open
+	outputDrainTimeout = 1 * time.Second
+)
+
+// envVarPattern matches the ${VAR} references to the Collector's environment variables
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// addressInUsePattern matches the output lines of the subprocesses which couldn't listen on an address already in use (Unix and Windows messages)
+var addressInUsePattern = regexp.MustCompile(`(?i)address already in use|eaddrinuse|only one usage of each socket address`)
+
+// ErrAddressInUse is matched, with errors.Is, by the errors of the runs of the subprocesses which exited with an error after reporting an address already in use
+var ErrAddressInUse = errors.New("address already in use")
+
+// addressInUseError wraps the error of a subprocess which reported an address already in use before exiting
+type addressInUseError struct {
+	err error
+}
+
+func (e *addressInUseError) Error() string {
+	return fmt.Sprintf("%v (%v)", e.err, ErrAddressInUse)
+}
+
+func (e *addressInUseError) Unwrap() error {
+	return e.err
+}
+
+func (e *addressInUseError) Is(target error) bool {
+	return target == ErrAddressInUse
+}
+
 // Run will start the process and keep track of running time
 func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time.Duration, error) {
+	return proc.RunWithPID(ctx, logger, nil)
+}
+
+// RunWithPID runs the process as Run does, sending its pid to pids once it is started unless pids is nil, the channel must be able to buffer it
+func (proc *SubprocessConfig) RunWithPID(ctx context.Context, logger *zap.Logger, pids chan<- int) (time.Duration, error) {
 
 	var argsSlice []string
 
@@ -38,39 +83,90 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 	if err != nil {
 		return 0, fmt.Errorf("could not parse command, error: %w", err)
 	}
+	// Expand the environment variables once the command is split, so that their values are never split into several arguments
+	for i, arg := range args {
+		args[i] = expandEnv(arg)
+	}
 	// Separate the executable from the flags for the Command object
 	if len(args) > 1 {
 		argsSlice = args[1:]
 	}
 
-	// Create the command object and attach current os environment + environment variables defined by the user
+	// Create the command object and attach current os environment (unless not inherited) + environment variables defined by the user
 	childProcess := exec.Command(args[0], argsSlice...)
-	childProcess.Env = append(os.Environ(), formatEnvSlice(&proc.Env)...)
+	childProcess.Env = environment(proc)
+	childProcess.Dir = proc.WorkingDir
+	setCredential(childProcess, proc)
 
-	// Handle the subprocess standard and error outputs in goroutines
-	stdoutReader, stdoutErr := childProcess.StdoutPipe()
+	// Get the subprocess standard and error outputs, read in goroutines once the process is started. The pipes are
+	// created here rather than by the Command object, whose Wait closes them, so that the last lines aren't lost
+	stdoutReader, stdoutWriter, stdoutErr := os.Pipe()
 	if stdoutErr != nil {
 		return 0, fmt.Errorf("could not get the command's stdout pipe, err: %w", stdoutErr)
 	}
-	go proc.pipeSubprocessOutput(bufio.NewReader(stdoutReader), logger, true)
+	defer stdoutReader.Close()
 
-	stderrReader, stderrErr := childProcess.StderrPipe()
+	stderrReader, stderrWriter, stderrErr := os.Pipe()
 	if stderrErr != nil {
+		stdoutWriter.Close()
 		return 0, fmt.Errorf("could not get the command's stderr pipe, err: %w", stderrErr)
 	}
-	go proc.pipeSubprocessOutput(bufio.NewReader(stderrReader), logger, false)
+	defer stderrReader.Close()
+	childProcess.Stdout = stdoutWriter
+	childProcess.Stderr = stderrWriter
 
 	// Start and stop timer (elapsed) right before and after executing the command
 	processErrCh := make(chan error, 1)
 	start := time.Now()
 
-	errProcess := childProcess.Start()
+	errProcess := startProcess(childProcess, proc)
+	// The subprocess has its own copies of the write ends, the outputs are closed once it and its children exit
+	stdoutWriter.Close()
+	stderrWriter.Close()
 	if errProcess != nil {
 		return 0, fmt.Errorf("process could not start: %w", errProcess)
 	}
 
+	// The memory limit can only be set once the subprocess is started, it is killed if it can't be
+	if err := applyLimits(childProcess.Process.Pid, proc); err != nil {
+		_ = childProcess.Process.Kill()
+		_ = childProcess.Wait()
+		return time.Since(start), fmt.Errorf("could not apply the limits of the subprocess: %w", err)
+	}
+	if pids != nil {
+		pids <- childProcess.Process.Pid
+	}
+
+	// Identify the subprocess in each of its output lines
+	processLogger := logger.With(zap.String("process", filepath.Base(args[0])), zap.Int("pid", childProcess.Process.Pid))
+	outputDone := make(chan struct{})
+	var outputWg sync.WaitGroup
+	var addressInUse int32
+	for _, output := range []struct {
+		reader   *os.File
+		isStdout bool
+	}{{stdoutReader, true}, {stderrReader, false}} {
+		outputWg.Add(1)
+		go func(reader *os.File, isStdout bool) {
+			defer outputWg.Done()
+			if pipeSubprocessOutput(bufio.NewReader(reader), processLogger, isStdout) {
+				atomic.StoreInt32(&addressInUse, 1)
+			}
+		}(output.reader, output.isStdout)
+	}
+	go func() {
+		outputWg.Wait()
+		close(outputDone)
+	}()
+
 	go func() {
-		processErrCh <- childProcess.Wait()
+		err := childProcess.Wait()
+		// Read the lines written right before the exit, unless the outputs are kept open by the children of the subprocess
+		select {
+		case <-outputDone:
+		case <-time.After(outputDrainTimeout):
+		}
+		processErrCh <- err
 	}()
 
 	// Handle normal process exiting or parent logic triggering a shutdown
@@ -79,6 +175,9 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 		elapsed := time.Since(start)
 
 		if errProcess != nil {
+			if atomic.LoadInt32(&addressInUse) == 1 {
+				return elapsed, &addressInUseError{err: errProcess}
+			}
 			return elapsed, fmt.Errorf("%w", errProcess)
 		}
 		return elapsed, nil
@@ -86,16 +185,44 @@ func (proc *SubprocessConfig) Run(ctx context.Context, logger *zap.Logger) (time
 	case <-ctx.Done():
 		elapsed := time.Since(start)
 
-		err := childProcess.Process.Kill()
+		// Ask the subprocess to exit so that it can flush its state, and only kill it if it's still running after the grace period
+		err := childProcess.Process.Signal(syscall.SIGTERM)
 		if err != nil {
-			return elapsed, fmt.Errorf("couldn't kill subprocess: %w", errProcess)
+			// Signals other than kill are not supported on Windows
+			return elapsed, killProcess(childProcess, processErrCh)
+		}
+
+		gracePeriod := proc.GracePeriod
+		if gracePeriod == 0 {
+			gracePeriod = defaultGracePeriod
+		}
+		timer := time.NewTimer(gracePeriod)
+		defer timer.Stop()
+
+		select {
+		case <-processErrCh:
+			return elapsed, nil
+
+		case <-timer.C:
+			processLogger.Info("Subprocess still running after the grace period, killing it", zap.String("grace period", gracePeriod.String()))
+			return elapsed, killProcess(childProcess, processErrCh)
 		}
-		return elapsed, nil
 	}
 }
 
-// Log every line of the subprocesse's output using zap, until pipe is closed (EOF)
-func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger *zap.Logger, isStdout bool) {
+// killProcess kills the subprocess and waits for it to exit
+func killProcess(childProcess *exec.Cmd, processErrCh <-chan error) error {
+	err := childProcess.Process.Kill()
+	if err != nil {
+		return fmt.Errorf("couldn't kill subprocess: %w", err)
+	}
+	<-processErrCh
+	return nil
+}
+
+// Log every line of the subprocesse's output using zap, until pipe is closed (EOF), returning whether a line reported an address already in use
+func pipeSubprocessOutput(reader *bufio.Reader, logger *zap.Logger, isStdout bool) bool {
+	addressInUse := false
 	for {
 		line, err := reader.ReadString('\n')
 		if err != nil && err != io.EOF {
@@ -105,10 +232,11 @@ func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger
 
 		line = strings.TrimSpace(line)
 		if line != "" && line != "\n" {
-			if isStdout {
-				logger.Info("subprocess output line", zap.String("output", line))
-			} else {
-				logger.Error("subprocess output line", zap.String("output", line))
+			if ce := logger.Check(detectSeverity(line, isStdout), "subprocess output line"); ce != nil {
+				ce.Write(zap.String("output", line))
+			}
+			if addressInUsePattern.MatchString(line) {
+				addressInUse = true
 			}
 		}
 
@@ -117,6 +245,41 @@ func (proc *SubprocessConfig) pipeSubprocessOutput(reader *bufio.Reader, logger
 			break
 		}
 	}
+	return addressInUse
+}
+
+var (
+	// severityFieldRegexp matches the level fields of logfmt and JSON lines, e.g. level=warn or "level":"warn"
+	severityFieldRegexp = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)"?\s*[=:]\s*"?([a-z]+)`)
+	// severityPrefixRegexp matches the levels starting plain text lines, e.g. WARN ... or [warning] ...
+	severityPrefixRegexp = regexp.MustCompile(`(?i)^\W*(trace|debug|info|warn|warning|error|err|fatal|panic|critical|crit)\b`)
+)
+
+// detectSeverity returns the level a subprocess output line is logged at, detected from the line if it holds a
+// known level, otherwise info for the standard output and error for the error output
+func detectSeverity(line string, isStdout bool) zapcore.Level {
+	match := severityFieldRegexp.FindStringSubmatch(line)
+	if match == nil {
+		match = severityPrefixRegexp.FindStringSubmatch(line)
+	}
+	if match != nil {
+		switch strings.ToLower(match[1]) {
+		case "trace", "debug":
+			return zapcore.DebugLevel
+		case "info":
+			return zapcore.InfoLevel
+		case "warn", "warning":
+			return zapcore.WarnLevel
+		// Fatal and panic lines are logged as errors, the collector must not exit because of its subprocesses
+		case "error", "err", "fatal", "panic", "critical", "crit":
+			return zapcore.ErrorLevel
+		}
+	}
+
+	if isStdout {
+		return zapcore.InfoLevel
+	}
+	return zapcore.ErrorLevel
 }
 
 // formatEnvSlice will loop over the key-value pairs and format the slice correctly for use by the Command object ("name=value")
@@ -127,8 +290,25 @@ func formatEnvSlice(envs *[]EnvConfig) []string {
 
 	envSlice := make([]string, len(*envs))
 	for i, env := range *envs {
-		envSlice[i] = fmt.Sprintf("%v=%v", env.Name, env.Value)
+		envSlice[i] = fmt.Sprintf("%v=%v", env.Name, expandEnv(env.Value))
 	}
 
 	return envSlice
 }
+
+// environment returns the environment of the subprocess, the Collector's environment if inherited followed by the variables defined by the user
+func environment(proc *SubprocessConfig) []string {
+	// A nil environment would make the subprocess inherit the Collector's one
+	env := []string{}
+	if proc.InheritEnv == nil || *proc.InheritEnv {
+		env = os.Environ()
+	}
+	return append(env, formatEnvSlice(&proc.Env)...)
+}
+
+// expandEnv replaces the ${VAR} references with the values of the Collector's environment variables, unset variables being replaced by an empty string
+func expandEnv(s string) string {
+	return envVarPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return os.Getenv(ref[2 : len(ref)-1])
+	})
+}
@@ -15,12 +15,22 @@
 package subprocessmanager
 
 import (
+	"bufio"
 	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestFormatEnvSlice(t *testing.T) {
@@ -148,3 +158,417 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectSeverity(t *testing.T) {
+	var detectSeverityTests = []struct {
+		name     string
+		line     string
+		isStdout bool
+		want     zapcore.Level
+	}{
+		{
+			name:     "plain stdout line",
+			line:     "Listening on :9100",
+			isStdout: true,
+			want:     zapcore.InfoLevel,
+		},
+		{
+			name:     "plain stderr line",
+			line:     "Listening on :9100",
+			isStdout: false,
+			want:     zapcore.ErrorLevel,
+		},
+		{
+			name:     "logfmt info line on stderr",
+			line:     `level=info ts=2020-09-15T19:39:38.000Z caller=node_exporter.go:177 msg="Starting node_exporter"`,
+			isStdout: false,
+			want:     zapcore.InfoLevel,
+		},
+		{
+			name:     "logfmt quoted warning line",
+			line:     `time="2020-09-15T19:39:38Z" level="warning" msg="Collector failed"`,
+			isStdout: true,
+			want:     zapcore.WarnLevel,
+		},
+		{
+			name:     "json debug line",
+			line:     `{"lvl":"debug","msg":"scraping"}`,
+			isStdout: false,
+			want:     zapcore.DebugLevel,
+		},
+		{
+			name:     "bracketed prefix",
+			line:     "[ERROR] could not connect to the database",
+			isStdout: true,
+			want:     zapcore.ErrorLevel,
+		},
+		{
+			name:     "fatal prefix",
+			line:     "FATAL: password authentication failed",
+			isStdout: true,
+			want:     zapcore.ErrorLevel,
+		},
+		{
+			name:     "unknown level",
+			line:     "level=verbose msg=hello",
+			isStdout: true,
+			want:     zapcore.InfoLevel,
+		},
+	}
+
+	for _, test := range detectSeverityTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := detectSeverity(test.line, test.isStdout); got != test.want {
+				t.Errorf("detectSeverity() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPipeSubprocessOutput(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core).With(zap.String("process", "exporter"), zap.Int("pid", 42))
+
+	output := "level=debug msg=first\n\nsecond line\nWARN third"
+	if pipeSubprocessOutput(bufio.NewReader(strings.NewReader(output)), logger, false) {
+		t.Errorf("pipeSubprocessOutput() reported an address already in use")
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("pipeSubprocessOutput() logged %v lines, want 3", len(entries))
+	}
+
+	wantLevels := []zapcore.Level{zapcore.DebugLevel, zapcore.ErrorLevel, zapcore.WarnLevel}
+	wantOutputs := []string{"level=debug msg=first", "second line", "WARN third"}
+	for i, entry := range entries {
+		if entry.Level != wantLevels[i] {
+			t.Errorf("pipeSubprocessOutput() line %v level = %v, want %v", i, entry.Level, wantLevels[i])
+		}
+		fields := entry.ContextMap()
+		if fields["output"] != wantOutputs[i] {
+			t.Errorf("pipeSubprocessOutput() line %v output = %v, want %v", i, fields["output"], wantOutputs[i])
+		}
+		if fields["process"] != "exporter" || fields["pid"] != int64(42) {
+			t.Errorf("pipeSubprocessOutput() line %v fields = %v, want process and pid", i, fields)
+		}
+	}
+}
+
+func TestPipeSubprocessOutputAddressInUse(t *testing.T) {
+	var addressInUseTests = []struct {
+		name   string
+		output string
+		want   bool
+	}{
+		{
+			name:   "unix",
+			output: "starting\nlisten tcp :9104: bind: address already in use\n",
+			want:   true,
+		},
+		{
+			name:   "node",
+			output: "Error: listen EADDRINUSE: :::9104",
+			want:   true,
+		},
+		{
+			name:   "windows",
+			output: "listen tcp :9104: bind: Only one usage of each socket address (protocol/network address/port) is normally permitted.",
+			want:   true,
+		},
+		{
+			name:   "other error",
+			output: "level=error msg=\"connection refused\"",
+			want:   false,
+		},
+	}
+
+	for _, test := range addressInUseTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := pipeSubprocessOutput(bufio.NewReader(strings.NewReader(test.output)), zap.NewNop(), false); got != test.want {
+				t.Errorf("pipeSubprocessOutput() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunAddressInUse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+
+	var addressInUseTests = []struct {
+		name             string
+		command          string
+		wantErr          bool
+		wantAddressInUse bool
+	}{
+		{
+			name:             "address in use",
+			command:          `sh -c "echo 'listen tcp :9104: bind: address already in use' >&2; exit 1"`,
+			wantErr:          true,
+			wantAddressInUse: true,
+		},
+		{
+			name:    "other error",
+			command: `sh -c "echo 'connection refused' >&2; exit 1"`,
+			wantErr: true,
+		},
+		{
+			name:    "successful exit",
+			command: `sh -c "echo 'address already in use, using another one'; exit 0"`,
+		},
+	}
+
+	for _, test := range addressInUseTests {
+		t.Run(test.name, func(t *testing.T) {
+			process := &SubprocessConfig{Command: test.command}
+			_, err := process.Run(context.Background(), zap.NewNop())
+			if (err != nil) != test.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, test.wantErr)
+			}
+			if got := errors.Is(err, ErrAddressInUse); got != test.wantAddressInUse {
+				t.Errorf("Run() error = %v, address in use = %v, want %v", err, got, test.wantAddressInUse)
+			}
+		})
+	}
+}
+
+func TestRunGracefulShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("SIGTERM is not supported on Windows")
+	}
+
+	var gracefulShutdownTests = []struct {
+		name        string
+		process     *SubprocessConfig
+		wantAtMost  time.Duration
+		wantAtLeast time.Duration
+	}{
+		{
+			name: "process exiting on SIGTERM",
+			process: &SubprocessConfig{
+				Command:     "sleep 10",
+				GracePeriod: 5 * time.Second,
+			},
+			wantAtMost: 2 * time.Second,
+		},
+		{
+			name: "process ignoring SIGTERM",
+			process: &SubprocessConfig{
+				Command:     "sh -c \"trap '' TERM; sleep 3\"",
+				GracePeriod: 200 * time.Millisecond,
+			},
+			wantAtLeast: 200 * time.Millisecond,
+			wantAtMost:  2 * time.Second,
+		},
+	}
+
+	for _, test := range gracefulShutdownTests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+			go func() {
+				_, err := test.process.Run(ctx, zap.NewNop())
+				errCh <- err
+			}()
+
+			// Leave the time to the process to start before stopping it
+			time.Sleep(200 * time.Millisecond)
+			start := time.Now()
+			cancel()
+
+			select {
+			case err := <-errCh:
+				if err != nil {
+					t.Errorf("Run() error = %v", err)
+				}
+				if stopped := time.Since(start); stopped < test.wantAtLeast || stopped > test.wantAtMost {
+					t.Errorf("Run() stopped after %v, want between %v and %v", stopped, test.wantAtLeast, test.wantAtMost)
+				}
+			case <-time.After(5 * time.Second):
+				t.Errorf("Run() didn't return after the context was done")
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	uid := uint32(65534)
+	var validateTests = []struct {
+		name    string
+		process *SubprocessConfig
+		wantErr bool
+	}{
+		{
+			name:    "no settings",
+			process: &SubprocessConfig{Command: "node_exporter"},
+		},
+		{
+			name:    "nice too low",
+			process: &SubprocessConfig{Command: "node_exporter", Nice: -21},
+			wantErr: true,
+		},
+		{
+			name:    "nice too high",
+			process: &SubprocessConfig{Command: "node_exporter", Nice: 20},
+			wantErr: true,
+		},
+		{
+			name:    "uid",
+			process: &SubprocessConfig{Command: "node_exporter", UID: &uid},
+			wantErr: !credentialSupported,
+		},
+		{
+			name:    "limits",
+			process: &SubprocessConfig{Command: "node_exporter", Nice: 10, MaxMemoryMiB: 512},
+			wantErr: !limitsSupported,
+		},
+	}
+
+	for _, test := range validateTests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.process.Validate()
+			if (err != nil) != test.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunWorkingDirAndCredential(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+
+	dir, err := ioutil.TempDir("", "subprocessmanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// Let the subprocess write its output whatever the user it runs as
+	if err := os.Chmod(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+
+	process := &SubprocessConfig{
+		Command:    `sh -c "id -u > user"`,
+		WorkingDir: dir,
+	}
+	wantUser := strconv.Itoa(os.Getuid())
+	// Only root can run the subprocess as another user
+	if os.Getuid() == 0 {
+		uid, gid := uint32(65534), uint32(65534)
+		process.UID, process.GID = &uid, &gid
+		wantUser = "65534"
+	}
+
+	if _, err := process.Run(context.Background(), zap.NewNop()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	user, err := ioutil.ReadFile(filepath.Join(dir, "user"))
+	if err != nil {
+		t.Fatalf("Run() didn't run in the working directory: %v", err)
+	}
+	if got := strings.TrimSpace(string(user)); got != wantUser {
+		t.Errorf("Run() ran as user %v, want %v", got, wantUser)
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("SUBPROCESSMANAGER_TEST_TOKEN", "s3cret")
+	defer os.Unsetenv("SUBPROCESSMANAGER_TEST_TOKEN")
+
+	var expandEnvTests = []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "no reference",
+			value: "user:password@(url:port)/dbname",
+			want:  "user:password@(url:port)/dbname",
+		},
+		{
+			name:  "set variable",
+			value: "--token=${SUBPROCESSMANAGER_TEST_TOKEN}",
+			want:  "--token=s3cret",
+		},
+		{
+			name:  "unset variable",
+			value: "${SUBPROCESSMANAGER_TEST_UNSET}:${SUBPROCESSMANAGER_TEST_TOKEN}",
+			want:  ":s3cret",
+		},
+		{
+			name:  "unbraced references are kept",
+			value: "$SUBPROCESSMANAGER_TEST_TOKEN $1 ${}",
+			want:  "$SUBPROCESSMANAGER_TEST_TOKEN $1 ${}",
+		},
+	}
+
+	for _, test := range expandEnvTests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := expandEnv(test.value); got != test.want {
+				t.Errorf("expandEnv() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRunInheritEnv(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test relies on sh")
+	}
+
+	os.Setenv("SUBPROCESSMANAGER_TEST_TOKEN", "s3cret")
+	defer os.Unsetenv("SUBPROCESSMANAGER_TEST_TOKEN")
+
+	inherit, noInherit := true, false
+	var inheritEnvTests = []struct {
+		name       string
+		inheritEnv *bool
+		want       string
+	}{
+		{
+			name: "default",
+			want: "s3cret s3cret-token s3cret",
+		},
+		{
+			name:       "inherited",
+			inheritEnv: &inherit,
+			want:       "s3cret s3cret-token s3cret",
+		},
+		{
+			name:       "not inherited",
+			inheritEnv: &noInherit,
+			want:       "s3cret s3cret-token",
+		},
+	}
+
+	for _, test := range inheritEnvTests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "subprocessmanager")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			process := &SubprocessConfig{
+				Command:    `sh -c "echo ${SUBPROCESSMANAGER_TEST_TOKEN} $TOKEN $SUBPROCESSMANAGER_TEST_TOKEN > env"`,
+				Env:        []EnvConfig{{Name: "TOKEN", Value: "${SUBPROCESSMANAGER_TEST_TOKEN}-token"}},
+				InheritEnv: test.inheritEnv,
+				WorkingDir: dir,
+			}
+			if _, err := process.Run(context.Background(), zap.NewNop()); err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			env, err := ioutil.ReadFile(filepath.Join(dir, "env"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(env)); got != test.want {
+				t.Errorf("Run() got = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subprocessmanager
+
+// ResourceUsage is the resource usage of a subprocess
+type ResourceUsage struct {
+	// CPUSeconds is the user and system CPU time used by the subprocess, in seconds
+	CPUSeconds float64
+	// RSSBytes is the resident set size of the subprocess, in bytes
+	RSSBytes int64
+	// OpenFDs is the number of file descriptors open by the subprocess
+	OpenFDs int
+}
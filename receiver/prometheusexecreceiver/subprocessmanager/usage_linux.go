@@ -0,0 +1,86 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package subprocessmanager
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UsageSupported is whether the resource usage of the subprocesses can be read on the current platform
+const UsageSupported = true
+
+// userHZ is the number of clock ticks per second of the CPU times of /proc/<pid>/stat, fixed to 100 on all the Linux platforms supported by Go
+const userHZ = 100
+
+// ReadResourceUsage reads the resource usage of the process from /proc, the usage of its own children not being included
+func ReadResourceUsage(pid int) (ResourceUsage, error) {
+	stat, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	usage, err := parseStat(string(stat))
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("could not parse /proc/%d/stat: %w", pid, err)
+	}
+
+	fds, err := os.Open(fmt.Sprintf("/proc/%d/fd", pid))
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	defer fds.Close()
+	names, err := fds.Readdirnames(-1)
+	if err != nil {
+		return ResourceUsage{}, err
+	}
+	usage.OpenFDs = len(names)
+	return usage, nil
+}
+
+// parseStat parses the CPU times and the resident set size of the content of /proc/<pid>/stat
+func parseStat(stat string) (ResourceUsage, error) {
+	// The command name, the second field, is in parentheses and can contain spaces and parentheses itself
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return ResourceUsage{}, fmt.Errorf("no command name in %q", stat)
+	}
+	// fields[0] is the state, the third field of the line: utime, stime and rss are the 14th, 15th and 24th fields
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 22 {
+		return ResourceUsage{}, fmt.Errorf("only %d fields after the command name", len(fields))
+	}
+	utime, err := strconv.ParseUint(fields[11], 10, 64)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("invalid utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[12], 10, 64)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("invalid stime: %w", err)
+	}
+	rss, err := strconv.ParseInt(fields[21], 10, 64)
+	if err != nil {
+		return ResourceUsage{}, fmt.Errorf("invalid rss: %w", err)
+	}
+	return ResourceUsage{
+		CPUSeconds: float64(utime+stime) / userHZ,
+		RSSBytes:   rss * int64(os.Getpagesize()),
+	}, nil
+}
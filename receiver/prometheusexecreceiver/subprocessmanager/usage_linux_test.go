@@ -0,0 +1,113 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+// +build linux
+
+package subprocessmanager
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestParseStat(t *testing.T) {
+	// The command name of the process is "my (weird) exporter"
+	stat := "4242 (my (weird) exporter) S 1 4242 4242 0 -1 4194560 1391 0 0 0 250 50 0 0 20 0 9 0 2885004 1210421248 3000 18446744073709551615 1 1 0 0 0 0 0 0 2143420159 0 0 0 17 3 0 0 0 0 0"
+	got, err := parseStat(stat)
+	if err != nil {
+		t.Fatalf("parseStat() error = %v", err)
+	}
+	want := ResourceUsage{CPUSeconds: 3, RSSBytes: 3000 * int64(os.Getpagesize())}
+	if got != want {
+		t.Errorf("parseStat() = %+v, want %+v", got, want)
+	}
+
+	invalidCPUTime := "4242 (exporter) S 1 4242 4242 0 -1 4194560 1391 0 0 0 x 50 0 0 20 0 9 0 2885004 1210421248 3000"
+	for _, invalid := range []string{"", "4242 (exporter) S 1 4242", invalidCPUTime} {
+		if _, err := parseStat(invalid); err == nil {
+			t.Errorf("parseStat(%q) error = nil, want an error", invalid)
+		}
+	}
+}
+
+func TestReadResourceUsage(t *testing.T) {
+	before, err := ReadResourceUsage(os.Getpid())
+	if err != nil {
+		t.Fatalf("ReadResourceUsage() error = %v", err)
+	}
+	if before.RSSBytes <= 0 || before.OpenFDs <= 0 {
+		t.Errorf("ReadResourceUsage() = %+v, want a positive resident set size and open file descriptors", before)
+	}
+
+	f, err := ioutil.TempFile("", "subprocessmanager")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	after, err := ReadResourceUsage(os.Getpid())
+	if err != nil {
+		t.Fatalf("ReadResourceUsage() error = %v", err)
+	}
+	if after.OpenFDs <= before.OpenFDs {
+		t.Errorf("ReadResourceUsage() open file descriptors = %v after opening a file, want more than %v", after.OpenFDs, before.OpenFDs)
+	}
+	if after.CPUSeconds < before.CPUSeconds {
+		t.Errorf("ReadResourceUsage() CPU time = %v, want at least %v", after.CPUSeconds, before.CPUSeconds)
+	}
+}
+
+func TestRunWithPID(t *testing.T) {
+	process := &SubprocessConfig{Command: "sleep 2"}
+	pids := make(chan int, 1)
+	ran := make(chan error, 1)
+	go func() {
+		_, err := process.RunWithPID(context.Background(), zap.NewNop(), pids)
+		ran <- err
+	}()
+
+	select {
+	case pid := <-pids:
+		// The pid is sent right after the fork, the resident set size is only positive once the process is running
+		deadline := time.Now().Add(time.Second)
+		for {
+			usage, err := ReadResourceUsage(pid)
+			if err != nil {
+				t.Fatalf("ReadResourceUsage() of the subprocess error = %v", err)
+			}
+			if usage.RSSBytes > 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("ReadResourceUsage() of the subprocess = %+v, want a positive resident set size", usage)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunWithPID() didn't send the pid of the subprocess")
+	}
+	if err := <-ran; err != nil {
+		t.Errorf("RunWithPID() error = %v", err)
+	}
+
+	if _, err := ReadResourceUsage(-1); err == nil {
+		t.Error("ReadResourceUsage() of a missing process error = nil, want an error")
+	}
+}
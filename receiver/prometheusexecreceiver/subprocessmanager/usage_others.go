@@ -0,0 +1,31 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package subprocessmanager
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// UsageSupported is whether the resource usage of the subprocesses can be read on the current platform
+const UsageSupported = false
+
+// ReadResourceUsage is only supported on Linux
+func ReadResourceUsage(int) (ResourceUsage, error) {
+	return ResourceUsage{}, fmt.Errorf("resource usage is not supported on %v", runtime.GOOS)
+}
@@ -47,6 +47,8 @@ func server() {
 			log.Fatal(err)
 		}
 		http.ServeFile(w, r, file.Name())
+		// Flush the response before the deferred exit, which would otherwise drop it
+		w.(http.Flusher).Flush()
 		return
 	})
 
@@ -0,0 +1,39 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"go.opentelemetry.io/collector/config/configmodels"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
+)
+
+// Config defines configuration for the Pulsar receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	pulsarexporter.ClientConfig `mapstructure:",squash"`
+
+	// The name of the topic to consume the traces from (default "otlp_spans")
+	TracesTopic string `mapstructure:"traces_topic"`
+	// The name of the topic to consume the metrics from (default "otlp_metrics")
+	MetricsTopic string `mapstructure:"metrics_topic"`
+	// The name of the topic to consume the logs from (default "otlp_logs")
+	LogsTopic string `mapstructure:"logs_topic"`
+	// The name of the shared subscription of the receivers, the messages
+	// being balanced between the receivers using the same subscription
+	// (default "otel-collector")
+	Subscription string `mapstructure:"subscription"`
+}
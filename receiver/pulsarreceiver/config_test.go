@@ -0,0 +1,71 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 2)
+
+	r0 := cfg.Receivers["pulsar"]
+	assert.Equal(t, factory.CreateDefaultConfig(), r0)
+
+	r1 := cfg.Receivers["pulsar/custom"]
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "pulsar/custom",
+		},
+		ClientConfig: pulsarexporter.ClientConfig{
+			URL: "pulsar+ssl://pulsar.example.com:6651",
+			Authentication: pulsarexporter.Authentication{
+				TLS: &pulsarexporter.TLSAuthentication{
+					CertFile: "/etc/pulsar/client.pem",
+					KeyFile:  "/etc/pulsar/client-key.pem",
+				},
+			},
+			TLS: pulsarexporter.TLSSettings{
+				CAFile: "/etc/pulsar/ca.pem",
+			},
+		},
+		TracesTopic:  "persistent://tenant/otel/spans",
+		MetricsTopic: "persistent://tenant/otel/metrics",
+		LogsTopic:    "persistent://tenant/otel/logs",
+		Subscription: "collectors",
+	}, r1)
+}
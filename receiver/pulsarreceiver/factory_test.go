@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func TestCreateReceivers(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	tr, err := createTraceReceiver(context.Background(), params, createDefaultConfig(), new(exportertest.SinkTraceExporter))
+	require.NoError(t, err)
+	assert.NotNil(t, tr)
+
+	mr, err := createMetricsReceiver(context.Background(), params, createDefaultConfig(), new(exportertest.SinkMetricsExporter))
+	require.NoError(t, err)
+	assert.NotNil(t, mr)
+
+	lr, err := createLogsReceiver(context.Background(), params, createDefaultConfig(), new(exportertest.SinkLogsExporter))
+	require.NoError(t, err)
+	assert.NotNil(t, lr)
+
+	tr, err = createTraceReceiver(context.Background(), params, createDefaultConfig(), nil)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+	assert.Nil(t, tr)
+}
+
+func TestCreateReceiverInvalidConfig(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Subscription = ""
+	tr, err := createTraceReceiver(context.Background(), params, cfg, new(exportertest.SinkTraceExporter))
+	assert.EqualError(t, err, "`subscription` not specified")
+	assert.Nil(t, tr)
+
+	cfg = createDefaultConfig().(*Config)
+	cfg.MetricsTopic = ""
+	mr, err := createMetricsReceiver(context.Background(), params, cfg, new(exportertest.SinkMetricsExporter))
+	assert.EqualError(t, err, "topic not specified")
+	assert.Nil(t, mr)
+}
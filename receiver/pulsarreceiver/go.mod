@@ -0,0 +1,14 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/pulsarreceiver
+
+go 1.14
+
+require (
+	github.com/apache/pulsar-client-go v0.2.0
+	github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
+	go.uber.org/zap v1.16.0
+	google.golang.org/protobuf v1.25.0
+)
+
+replace github.com/open-telemetry/opentelemetry-collector-contrib/exporter/pulsarexporter => ../../exporter/pulsarexporter
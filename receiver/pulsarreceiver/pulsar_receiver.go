@@ -0,0 +1,226 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+)
+
+const (
+	transport = "pulsar"
+	encoding  = "otlp_proto"
+)
+
+// messageHandler passes the payload of a message to the next consumer, the
+// payloads which cannot be unmarshalled being reported as permanent errors.
+type messageHandler func(ctx context.Context, payload []byte) error
+
+// pulsarConsumer consumes the messages of a signal from its Pulsar topic.
+type pulsarConsumer struct {
+	name         string
+	client       pulsar.Client
+	topic        string
+	subscription string
+	handle       messageHandler
+	logger       *zap.Logger
+
+	consumer pulsar.Consumer
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.Receiver = (*pulsarConsumer)(nil)
+
+// newPulsarConsumer creates the Pulsar client, the consumer being only
+// created on start as it connects to the brokers.
+func newPulsarConsumer(config *Config, topic string, logger *zap.Logger) (*pulsarConsumer, error) {
+	if topic == "" {
+		return nil, errors.New("topic not specified")
+	}
+	if config.Subscription == "" {
+		return nil, errors.New("`subscription` not specified")
+	}
+
+	opts, err := config.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	client, err := pulsar.NewClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &pulsarConsumer{
+		name:         config.Name(),
+		client:       client,
+		topic:        topic,
+		subscription: config.Subscription,
+		logger:       logger,
+	}, nil
+}
+
+func newTraceReceiver(config *Config, logger *zap.Logger, nextConsumer consumer.TraceConsumer) (component.TraceReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	c, err := newPulsarConsumer(config, config.TracesTopic, logger)
+	if err != nil {
+		return nil, err
+	}
+	c.handle = func(ctx context.Context, payload []byte) error {
+		ctx = obsreport.StartTraceDataReceiveOp(ctx, c.name, transport)
+		td, err := unmarshalTraces(payload)
+		if err != nil {
+			obsreport.EndTraceDataReceiveOp(ctx, encoding, 0, err)
+			return consumererror.Permanent(err)
+		}
+		err = nextConsumer.ConsumeTraces(ctx, td)
+		obsreport.EndTraceDataReceiveOp(ctx, encoding, td.SpanCount(), err)
+		return err
+	}
+	return c, nil
+}
+
+func newMetricsReceiver(config *Config, logger *zap.Logger, nextConsumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	c, err := newPulsarConsumer(config, config.MetricsTopic, logger)
+	if err != nil {
+		return nil, err
+	}
+	c.handle = func(ctx context.Context, payload []byte) error {
+		ctx = obsreport.StartMetricsReceiveOp(ctx, c.name, transport)
+		md, err := unmarshalMetrics(payload)
+		if err != nil {
+			obsreport.EndMetricsReceiveOp(ctx, encoding, 0, 0, err)
+			return consumererror.Permanent(err)
+		}
+		numTimeSeries, numPoints := md.MetricAndDataPointCount()
+		err = nextConsumer.ConsumeMetrics(ctx, md)
+		obsreport.EndMetricsReceiveOp(ctx, encoding, numPoints, numTimeSeries, err)
+		return err
+	}
+	return c, nil
+}
+
+func newLogsReceiver(config *Config, logger *zap.Logger, nextConsumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	c, err := newPulsarConsumer(config, config.LogsTopic, logger)
+	if err != nil {
+		return nil, err
+	}
+	c.handle = func(ctx context.Context, payload []byte) error {
+		ld, err := unmarshalLogs(payload)
+		if err != nil {
+			return consumererror.Permanent(err)
+		}
+		return nextConsumer.ConsumeLogs(ctx, ld)
+	}
+	return c, nil
+}
+
+// Start subscribes to the topic, the messages being balanced between the
+// consumers of the shared subscription.
+func (c *pulsarConsumer) Start(context.Context, component.Host) error {
+	err := componenterror.ErrAlreadyStarted
+	c.startOnce.Do(func() {
+		c.consumer, err = c.client.Subscribe(pulsar.ConsumerOptions{
+			Topic:            c.topic,
+			SubscriptionName: c.subscription,
+			Type:             pulsar.Shared,
+		})
+		if err != nil {
+			err = fmt.Errorf("failed to subscribe to topic %q: %w", c.topic, err)
+			return
+		}
+
+		var ctx context.Context
+		ctx, c.cancel = context.WithCancel(context.Background())
+		c.done = make(chan struct{})
+		go func() {
+			defer close(c.done)
+			c.consume(ctx, c.consumer)
+		}()
+	})
+	return err
+}
+
+// Shutdown stops consuming, the messages being handled are not acknowledged
+// and are therefore redelivered to the other consumers of the subscription.
+func (c *pulsarConsumer) Shutdown(context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	c.stopOnce.Do(func() {
+		err = nil
+		if c.cancel != nil {
+			c.cancel()
+			<-c.done
+		}
+		if c.consumer != nil {
+			c.consumer.Close()
+		}
+		c.client.Close()
+	})
+	return err
+}
+
+func (c *pulsarConsumer) consume(ctx context.Context, consumer pulsar.Consumer) {
+	ctx = obsreport.ReceiverContext(ctx, c.name, transport, c.name)
+	for {
+		select {
+		case msg, ok := <-consumer.Chan():
+			if !ok {
+				return
+			}
+			c.handleMessage(ctx, consumer, msg.Message)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleMessage acknowledges the messages passed to the next consumer, and
+// the ones which cannot be unmarshalled as they would fail again. The others
+// are negatively acknowledged, to be redelivered later.
+func (c *pulsarConsumer) handleMessage(ctx context.Context, consumer pulsar.Consumer, msg pulsar.Message) {
+	err := c.handle(ctx, msg.Payload())
+	switch {
+	case err == nil:
+		consumer.Ack(msg)
+	case consumererror.IsPermanent(err):
+		c.logger.Error("Dropping invalid Pulsar message",
+			zap.String("topic", msg.Topic()), zap.Error(err))
+		consumer.Ack(msg)
+	default:
+		c.logger.Error("Failed to consume Pulsar message, it will be redelivered",
+			zap.String("topic", msg.Topic()), zap.Error(err))
+		consumer.Nack(msg)
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/apache/pulsar-client-go/pulsar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+// fakeMessage is a message of the topic of the tests.
+type fakeMessage struct {
+	pulsar.Message
+	payload []byte
+}
+
+func (m *fakeMessage) Topic() string {
+	return "otlp"
+}
+
+func (m *fakeMessage) Payload() []byte {
+	return m.payload
+}
+
+// fakeConsumer delivers the messages sent to its channel and records their
+// acknowledgements.
+type fakeConsumer struct {
+	pulsar.Consumer
+	ch     chan pulsar.ConsumerMessage
+	acked  chan pulsar.Message
+	nacked chan pulsar.Message
+}
+
+func newFakeConsumer() *fakeConsumer {
+	return &fakeConsumer{
+		ch:     make(chan pulsar.ConsumerMessage, 10),
+		acked:  make(chan pulsar.Message, 10),
+		nacked: make(chan pulsar.Message, 10),
+	}
+}
+
+func (c *fakeConsumer) send(payload []byte) *fakeMessage {
+	msg := &fakeMessage{payload: payload}
+	c.ch <- pulsar.ConsumerMessage{Consumer: c, Message: msg}
+	return msg
+}
+
+func (c *fakeConsumer) Chan() <-chan pulsar.ConsumerMessage {
+	return c.ch
+}
+
+func (c *fakeConsumer) Ack(msg pulsar.Message) {
+	c.acked <- msg
+}
+
+func (c *fakeConsumer) Nack(msg pulsar.Message) {
+	c.nacked <- msg
+}
+
+func TestReceiverConsumeTraces(t *testing.T) {
+	sink := new(exportertest.SinkTraceExporter)
+	r, err := newTraceReceiver(createDefaultConfig().(*Config), zap.NewNop(), sink)
+	require.NoError(t, err)
+	c := r.(*pulsarConsumer)
+
+	consumer := newFakeConsumer()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.consume(ctx, consumer)
+	}()
+
+	td := testTraces()
+	msg := consumer.send(tracesRequest(t, td))
+	assert.Equal(t, msg, <-consumer.acked)
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, td, sink.AllTraces()[0])
+
+	// Invalid messages are dropped, they would never be unmarshalled.
+	msg = consumer.send([]byte{0xff})
+	assert.Equal(t, msg, <-consumer.acked)
+	assert.Len(t, sink.AllTraces(), 1)
+
+	// The next consumer failures are redelivered.
+	sink.SetConsumeTraceError(errors.New("pipeline unavailable"))
+	msg = consumer.send(tracesRequest(t, td))
+	assert.Equal(t, msg, <-consumer.nacked)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("consume did not stop")
+	}
+}
+
+func TestReceiverConsumeMetrics(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newMetricsReceiver(createDefaultConfig().(*Config), zap.NewNop(), sink)
+	require.NoError(t, err)
+	c := r.(*pulsarConsumer)
+
+	consumer := newFakeConsumer()
+	md := testMetrics()
+	c.handleMessage(context.Background(), consumer, &fakeMessage{payload: marshalRequest(t, pdata.MetricsToOtlp(md)[0])})
+	require.Len(t, consumer.acked, 1)
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, md, sink.AllMetrics()[0])
+}
+
+func TestReceiverConsumeLogs(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	r, err := newLogsReceiver(createDefaultConfig().(*Config), zap.NewNop(), sink)
+	require.NoError(t, err)
+	c := r.(*pulsarConsumer)
+
+	consumer := newFakeConsumer()
+	ld := testLogs()
+	c.handleMessage(context.Background(), consumer, &fakeMessage{payload: marshalRequest(t, (*ld.InternalRep().Orig)[0])})
+	require.Len(t, consumer.acked, 1)
+	require.Len(t, sink.AllLogs(), 1)
+	assert.Equal(t, ld, sink.AllLogs()[0])
+}
+
+func TestReceiverShutdownWithoutStart(t *testing.T) {
+	r, err := newTraceReceiver(createDefaultConfig().(*Config), zap.NewNop(), new(exportertest.SinkTraceExporter))
+	require.NoError(t, err)
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
+}
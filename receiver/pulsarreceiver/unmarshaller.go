@@ -0,0 +1,102 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// The number of the repeated resource spans, metrics and logs field in the
+// OTLP export requests, their only field.
+const resourceFieldNumber = 1
+
+// splitRequest returns the serialized resources of an OTLP export request,
+// the resources being unmarshalled by the OTLP types internal to the
+// Collector.
+func splitRequest(request []byte) ([][]byte, error) {
+	var resources [][]byte
+	for len(request) > 0 {
+		num, typ, n := protowire.ConsumeTag(request)
+		if n < 0 {
+			return nil, fmt.Errorf("invalid OTLP request: %w", protowire.ParseError(n))
+		}
+		request = request[n:]
+
+		if num != resourceFieldNumber || typ != protowire.BytesType {
+			// Skip the unknown fields, as the protobuf unmarshallers do.
+			n = protowire.ConsumeFieldValue(num, typ, request)
+		} else {
+			var resource []byte
+			resource, n = protowire.ConsumeBytes(request)
+			resources = append(resources, resource)
+		}
+		if n < 0 {
+			return nil, fmt.Errorf("invalid OTLP request: %w", protowire.ParseError(n))
+		}
+		request = request[n:]
+	}
+	return resources, nil
+}
+
+// unmarshalTraces deserializes an OTLP ExportTraceServiceRequest.
+func unmarshalTraces(request []byte) (pdata.Traces, error) {
+	resources, err := splitRequest(request)
+	if err != nil {
+		return pdata.NewTraces(), err
+	}
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(len(resources))
+	for i, rs := range pdata.TracesToOtlp(td) {
+		if err := rs.Unmarshal(resources[i]); err != nil {
+			return pdata.NewTraces(), fmt.Errorf("invalid OTLP resource spans: %w", err)
+		}
+	}
+	return td, nil
+}
+
+// unmarshalMetrics deserializes an OTLP ExportMetricsServiceRequest.
+func unmarshalMetrics(request []byte) (pdata.Metrics, error) {
+	resources, err := splitRequest(request)
+	if err != nil {
+		return pdata.NewMetrics(), err
+	}
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(len(resources))
+	for i, rm := range pdata.MetricsToOtlp(md) {
+		if err := rm.Unmarshal(resources[i]); err != nil {
+			return pdata.NewMetrics(), fmt.Errorf("invalid OTLP resource metrics: %w", err)
+		}
+	}
+	return md, nil
+}
+
+// unmarshalLogs deserializes an OTLP ExportLogsServiceRequest.
+func unmarshalLogs(request []byte) (pdata.Logs, error) {
+	resources, err := splitRequest(request)
+	if err != nil {
+		return pdata.NewLogs(), err
+	}
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(len(resources))
+	for i, rl := range *ld.InternalRep().Orig {
+		if err := rl.Unmarshal(resources[i]); err != nil {
+			return pdata.NewLogs(), fmt.Errorf("invalid OTLP resource logs: %w", err)
+		}
+	}
+	return ld, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pulsarreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func testTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	td.ResourceSpans().Resize(2)
+	for i, service := range []string{"frontend", "backend"} {
+		rs := td.ResourceSpans().At(i)
+		rs.Resource().InitEmpty()
+		rs.Resource().Attributes().InsertString("service.name", service)
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(1)
+		spans.At(0).SetName("GET /api/" + service)
+		spans.At(0).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+		spans.At(0).SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+	return td
+}
+
+func testMetrics() pdata.Metrics {
+	md := pdata.NewMetrics()
+	md.ResourceMetrics().Resize(1)
+	rm := md.ResourceMetrics().At(0)
+	rm.Resource().InitEmpty()
+	rm.Resource().Attributes().InsertString("service.name", "frontend")
+	rm.InstrumentationLibraryMetrics().Resize(1)
+	metrics := rm.InstrumentationLibraryMetrics().At(0).Metrics()
+	metrics.Resize(1)
+	metrics.At(0).SetName("requests")
+	metrics.At(0).SetDataType(pdata.MetricDataTypeIntSum)
+	metrics.At(0).IntSum().InitEmpty()
+	metrics.At(0).IntSum().DataPoints().Resize(1)
+	metrics.At(0).IntSum().DataPoints().At(0).SetValue(42)
+	return md
+}
+
+func testLogs() pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("service.name", "frontend")
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(1)
+	logs.At(0).SetName("access")
+	logs.At(0).Body().SetStringVal("GET /api/orders 200")
+	return ld
+}
+
+type resourceMarshaller interface {
+	Marshal() ([]byte, error)
+}
+
+// marshalRequest serializes the resources as an OTLP export request.
+func marshalRequest(t *testing.T, resources ...resourceMarshaller) []byte {
+	var request []byte
+	for _, resource := range resources {
+		bts, err := resource.Marshal()
+		require.NoError(t, err)
+		request = protowire.AppendTag(request, resourceFieldNumber, protowire.BytesType)
+		request = protowire.AppendBytes(request, bts)
+	}
+	return request
+}
+
+func tracesRequest(t *testing.T, td pdata.Traces) []byte {
+	var resources []resourceMarshaller
+	for _, rs := range pdata.TracesToOtlp(td) {
+		resources = append(resources, rs)
+	}
+	return marshalRequest(t, resources...)
+}
+
+func TestUnmarshalTraces(t *testing.T) {
+	td := testTraces()
+	got, err := unmarshalTraces(tracesRequest(t, td))
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestUnmarshalMetrics(t *testing.T) {
+	md := testMetrics()
+	got, err := unmarshalMetrics(marshalRequest(t, pdata.MetricsToOtlp(md)[0]))
+	require.NoError(t, err)
+	assert.Equal(t, md, got)
+}
+
+func TestUnmarshalLogs(t *testing.T) {
+	ld := testLogs()
+	got, err := unmarshalLogs(marshalRequest(t, (*ld.InternalRep().Orig)[0]))
+	require.NoError(t, err)
+	assert.Equal(t, ld, got)
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	td := testTraces()
+	request := protowire.AppendTag(nil, 2, protowire.VarintType)
+	request = protowire.AppendVarint(request, 42)
+	request = append(request, tracesRequest(t, td)...)
+
+	got, err := unmarshalTraces(request)
+	require.NoError(t, err)
+	assert.Equal(t, td, got)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	_, err := unmarshalTraces([]byte{0x0a, 0x10, 0x01})
+	assert.Error(t, err)
+
+	_, err = unmarshalMetrics([]byte{0x0a, 0x02, 0xff, 0xff})
+	assert.Error(t, err)
+
+	_, err = unmarshalLogs([]byte{0xff})
+	assert.Error(t, err)
+}
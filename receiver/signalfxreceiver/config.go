@@ -15,6 +15,8 @@
 package signalfxreceiver
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/confighttp"
 	"go.opentelemetry.io/collector/config/configmodels"
 
@@ -27,4 +29,30 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// Validation configures the validation of the received datapoints.
+	Validation ValidationConfig `mapstructure:"validation"`
+}
+
+// ValidationConfig defines the checks of the received datapoints, the
+// datapoints failing them being rejected instead of being passed to the next
+// consumer.
+type ValidationConfig struct {
+	// MaxFutureSkew is the duration after the time of reception beyond which
+	// the timestamp of a datapoint is rejected. Disabled if 0.
+	MaxFutureSkew time.Duration `mapstructure:"max_future_skew"`
+
+	// Dimensions enables the rejection of the datapoints whose dimension keys
+	// or values are not accepted by SignalFx.
+	Dimensions bool `mapstructure:"dimensions"`
+
+	// QuarantineExporter is the name of a logs exporter, used by a logs
+	// pipeline, to which the rejected datapoints are sent, along with the
+	// reason of their rejection. The rejected datapoints are dropped if empty.
+	QuarantineExporter string `mapstructure:"quarantine_exporter"`
+}
+
+// enabled returns whether any of the checks is enabled.
+func (vCfg *ValidationConfig) enabled() bool {
+	return vCfg.MaxFutureSkew > 0 || vCfg.Dimensions
 }
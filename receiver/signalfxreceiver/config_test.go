@@ -17,6 +17,7 @@ package signalfxreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -60,6 +61,11 @@ func TestLoadConfig(t *testing.T) {
 			AccessTokenPassthroughConfig: splunk.AccessTokenPassthroughConfig{
 				AccessTokenPassthrough: true,
 			},
+			Validation: ValidationConfig{
+				MaxFutureSkew:      10 * time.Minute,
+				Dimensions:         true,
+				QuarantineExporter: "exampleexporter",
+			},
 		})
 
 	r2 := cfg.Receivers["signalfx/tls"].(*Config)
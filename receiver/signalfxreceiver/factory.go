@@ -16,9 +16,11 @@ package signalfxreceiver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strconv"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/confighttp"
@@ -42,7 +44,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -74,12 +77,16 @@ func extractPortFromEndpoint(endpoint string) (int, error) {
 	return int(port), nil
 }
 
-// verify that the configured port is not 0
+// verify that the configured port is not 0 and that the quarantined
+// datapoints are validated
 func (rCfg *Config) validate() error {
 	_, err := extractPortFromEndpoint(rCfg.Endpoint)
 	if err != nil {
 		return err
 	}
+	if rCfg.Validation.QuarantineExporter != "" && !rCfg.Validation.enabled() {
+		return errors.New("validation.quarantine_exporter requires max_future_skew or dimensions to be set")
+	}
 	return nil
 }
 
@@ -91,6 +98,44 @@ func createMetricsReceiver(
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	r, err := createReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.registerMetricsConsumer(consumer)
+
+	return r, nil
+}
+
+// createLogsReceiver creates a logs receiver, receiving the SignalFx events,
+// based on provided config.
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	r, err := createReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.registerLogsConsumer(consumer)
+
+	return r, nil
+}
+
+// createReceiver returns the receiver of the config, creating it if it was
+// not created yet for the other data type.
+func createReceiver(params component.ReceiverCreateParams, cfg configmodels.Receiver) (*sfxReceiver, error) {
 	rCfg := cfg.(*Config)
 
 	err := rCfg.validate()
@@ -98,5 +143,24 @@ func createMetricsReceiver(
 		return nil, err
 	}
 
-	return New(params.Logger, *rCfg, consumer)
+	receiversMu.Lock()
+	defer receiversMu.Unlock()
+	r, ok := receivers[rCfg]
+	if !ok {
+		r, err = newReceiver(params.Logger, *rCfg)
+		if err != nil {
+			return nil, err
+		}
+		receivers[rCfg] = r
+	}
+	return r, nil
 }
+
+// This is the map of already created SignalFx receivers for particular
+// configurations. The factory is asked the metrics and logs receivers
+// separately but they must be the same, serving the datapoints and the
+// events on the same endpoint.
+var (
+	receiversMu sync.Mutex
+	receivers   = map[*Config]*sfxReceiver{}
+)
@@ -75,3 +75,37 @@ func TestCreateLargePort(t *testing.T) {
 	assert.Error(t, err, "port number must be between 1 and 65535")
 	assert.Nil(t, tReceiver)
 }
+
+func TestCreateQuarantineWithoutValidation(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Validation.QuarantineExporter = "file/quarantine"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	tReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.EqualError(t, err, "validation.quarantine_exporter requires max_future_skew or dimensions to be set")
+	assert.Nil(t, tReceiver)
+}
+
+func TestCreateMetricsAndLogsReceiversShareReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:1" // Endpoint is required, not going to be used here.
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.NoError(t, err)
+	lReceiver, err := factory.CreateLogsReceiver(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+	assert.NoError(t, err)
+	assert.Same(t, mReceiver, lReceiver)
+}
+
+func TestCreateLogsReceiverNilConsumer(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	lReceiver, err := factory.CreateLogsReceiver(context.Background(), params, cfg, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+	assert.Nil(t, lReceiver)
+}
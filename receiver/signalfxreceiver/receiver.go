@@ -63,6 +63,7 @@ const (
 
 var (
 	errNilNextConsumer = errors.New("nil nextConsumer")
+	errNoConsumers     = errors.New("neither metrics nor logs consumer is set")
 	errEmptyEndpoint   = errors.New("empty endpoint")
 
 	okRespBody               = initJSONResponse(responseOK)
@@ -75,19 +76,25 @@ var (
 	errNextConsumerRespBody  = initJSONResponse(responseErrNextConsumer)
 )
 
-// sfxReceiver implements the component.MetricsReceiver for SignalFx metric protocol.
+// sfxReceiver implements the component.MetricsReceiver and
+// component.LogsReceiver for SignalFx metric protocol, the datapoints being
+// received on "/v2/datapoint" and the events on "/v2/event".
 type sfxReceiver struct {
 	sync.Mutex
 	logger       *zap.Logger
 	config       *Config
 	nextConsumer consumer.MetricsConsumer
+	logsConsumer consumer.LogsConsumer
 	server       *http.Server
+	validator    *datapointValidator
+	quarantine   consumer.LogsConsumer
 
 	startOnce sync.Once
 	stopOnce  sync.Once
 }
 
 var _ component.MetricsReceiver = (*sfxReceiver)(nil)
+var _ component.LogsReceiver = (*sfxReceiver)(nil)
 
 // New creates the SignalFx receiver with the given configuration.
 func New(
@@ -100,17 +107,39 @@ func New(
 		return nil, errNilNextConsumer
 	}
 
+	r, err := newReceiver(logger, config)
+	if err != nil {
+		return nil, err
+	}
+	r.registerMetricsConsumer(nextConsumer)
+
+	return r, nil
+}
+
+// newReceiver creates the SignalFx receiver with the given configuration, the
+// consumers of the datapoints and of the events being registered afterwards.
+func newReceiver(logger *zap.Logger, config Config) (*sfxReceiver, error) {
 	if config.Endpoint == "" {
 		return nil, errEmptyEndpoint
 	}
 
-	r := &sfxReceiver{
-		logger:       logger,
-		config:       &config,
-		nextConsumer: nextConsumer,
-	}
+	return &sfxReceiver{
+		logger:    logger,
+		config:    &config,
+		validator: newDatapointValidator(config.Validation),
+	}, nil
+}
 
-	return r, nil
+func (r *sfxReceiver) registerMetricsConsumer(nextConsumer consumer.MetricsConsumer) {
+	r.Lock()
+	defer r.Unlock()
+	r.nextConsumer = nextConsumer
+}
+
+func (r *sfxReceiver) registerLogsConsumer(logsConsumer consumer.LogsConsumer) {
+	r.Lock()
+	defer r.Unlock()
+	r.logsConsumer = logsConsumer
 }
 
 // StartMetricsReception tells the receiver to start its processing.
@@ -120,10 +149,21 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 	r.Lock()
 	defer r.Unlock()
 
+	if r.nextConsumer == nil && r.logsConsumer == nil {
+		return errNoConsumers
+	}
+
 	err := componenterror.ErrAlreadyStarted
 	r.startOnce.Do(func() {
 		err = nil
 
+		if name := r.config.Validation.QuarantineExporter; name != "" {
+			r.quarantine, err = findQuarantineExporter(host, name, r.config.Name())
+			if err != nil {
+				return
+			}
+		}
+
 		var ln net.Listener
 		// set up the listener
 		ln, err = r.config.HTTPServerSettings.ToListener()
@@ -133,7 +173,12 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 		}
 
 		mx := mux.NewRouter()
-		mx.HandleFunc("/v2/datapoint", r.handleReq)
+		if r.nextConsumer != nil {
+			mx.HandleFunc("/v2/datapoint", r.handleReq)
+		}
+		if r.logsConsumer != nil {
+			mx.HandleFunc("/v2/event", r.handleEventReq)
+		}
 
 		r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -173,44 +218,25 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
 
-	if req.Method != http.MethodPost {
-		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return
-	}
-
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
+	body, ok := r.readBody(ctx, resp, req)
+	if !ok {
 		return
 	}
 
-	encoding := req.Header.Get(httpContentEncodingHeader)
-	if encoding != "" && encoding != gzipEncoding {
-		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
+	msg := &sfxpb.DataPointUploadMessage{}
+	if err := msg.Unmarshal(body); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
-	bodyReader := req.Body
-	if encoding == gzipEncoding {
-		var err error
-		bodyReader, err = gzip.NewReader(bodyReader)
-		if err != nil {
-			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return
+	if r.validator != nil {
+		var rejected []rejectedDatapoint
+		msg.Datapoints, rejected = r.validator.filter(msg.Datapoints, time.Now())
+		if len(rejected) > 0 {
+			r.quarantineDatapoints(ctx, rejected)
 		}
 	}
 
-	body, err := ioutil.ReadAll(bodyReader)
-	if err != nil {
-		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
-		return
-	}
-
-	msg := &sfxpb.DataPointUploadMessage{}
-	if err = msg.Unmarshal(body); err != nil {
-		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
-		return
-	}
-
 	if len(msg.Datapoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
@@ -231,7 +257,7 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 		}
 	}
 
-	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	err := r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
@@ -247,6 +273,103 @@ func (r *sfxReceiver) handleReq(resp http.ResponseWriter, req *http.Request) {
 	resp.Write(okRespBody)
 }
 
+func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+
+	body, ok := r.readBody(ctx, resp, req)
+	if !ok {
+		return
+	}
+
+	msg := &sfxpb.EventUploadMessage{}
+	if err := msg.Unmarshal(body); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	if len(msg.Events) == 0 {
+		resp.Write(okRespBody)
+		return
+	}
+
+	ld := signalFxV2EventsToLogRecords(msg.Events)
+
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			ld.ResourceLogs().At(0).Resource().Attributes().InsertString(splunk.SFxAccessTokenLabel, accessToken)
+		}
+	}
+
+	if err := r.logsConsumer.ConsumeLogs(ctx, ld); err != nil {
+		r.failRequest(ctx, resp, http.StatusInternalServerError, errNextConsumerRespBody, err)
+		return
+	}
+
+	resp.WriteHeader(http.StatusAccepted)
+	resp.Write(okRespBody)
+}
+
+// readBody returns the uncompressed body of a request, failing the request if
+// its method or headers are not supported or its body cannot be read.
+func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, bool) {
+	if req.Method != http.MethodPost {
+		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
+		return nil, false
+	}
+
+	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
+		return nil, false
+	}
+
+	encoding := req.Header.Get(httpContentEncodingHeader)
+	if encoding != "" && encoding != gzipEncoding {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
+		return nil, false
+	}
+
+	bodyReader := req.Body
+	if encoding == gzipEncoding {
+		var err error
+		bodyReader, err = gzip.NewReader(bodyReader)
+		if err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
+			return nil, false
+		}
+	}
+
+	body, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
+		return nil, false
+	}
+	return body, true
+}
+
+// quarantineDatapoints sends the rejected datapoints to the quarantine
+// exporter, if any, or drops them.
+func (r *sfxReceiver) quarantineDatapoints(ctx context.Context, rejected []rejectedDatapoint) {
+	if r.quarantine == nil {
+		r.logger.Debug(
+			"SignalFx datapoints rejected by the validation",
+			zap.Int("count", len(rejected)),
+			zap.String("first_rejection", rejected[0].message),
+			zap.String("receiver", r.config.Name()))
+		return
+	}
+	if err := r.quarantine.ConsumeLogs(ctx, rejectedToLogs(rejected, r.config.Name())); err != nil {
+		r.logger.Warn(
+			"Error sending the rejected SignalFx datapoints to the quarantine exporter",
+			zap.Int("count", len(rejected)),
+			zap.Error(err),
+			zap.String("receiver", r.config.Name()))
+	}
+}
+
 func (r *sfxReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
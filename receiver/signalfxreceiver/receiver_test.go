@@ -39,6 +39,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -50,6 +51,7 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/signalfxexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
 )
 
 func Test_signalfxeceiver_New(t *testing.T) {
@@ -331,6 +333,169 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_EventEndToEnd(t *testing.T) {
+	port := testutil.GetAvailablePort(t)
+	addr := fmt.Sprintf("localhost:%d", port)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = addr
+	sink := new(exportertest.SinkLogsExporter)
+	r, err := NewFactory().CreateLogsReceiver(
+		context.Background(),
+		component.ReceiverCreateParams{Logger: zap.NewNop()},
+		cfg,
+		sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	runtime.Gosched()
+	defer r.Shutdown(context.Background())
+
+	expCfg := &signalfxexporter.Config{
+		IngestURL:   "http://" + addr + "/v2/datapoint",
+		APIURL:      "http://localhost",
+		AccessToken: "access_token",
+	}
+	exp, err := signalfxexporter.NewFactory().CreateLogsExporter(
+		context.Background(),
+		component.ExporterCreateParams{Logger: zap.NewNop()},
+		expCfg)
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, testutil.WaitForPort(t, port))
+	defer exp.Shutdown(context.Background())
+
+	ld := signalFxV2EventsToLogRecords([]*sfxpb.Event{
+		{
+			EventType:  "deployment",
+			Timestamp:  1574092046011,
+			Dimensions: []*sfxpb.Dimension{{Key: "service", Value: "checkout"}},
+			Properties: []*sfxpb.Property{{Key: "version", Value: &sfxpb.PropertyValue{StrValue: strPtr("1.2.3")}}},
+		},
+	})
+	require.NoError(t, exp.ConsumeLogs(context.Background(), ld))
+
+	lds := sink.AllLogs()
+	require.Len(t, lds, 1)
+	assert.Equal(t, ld, lds[0])
+
+	// The datapoints are not received without a metrics pipeline.
+	resp, err := http.Post("http://"+addr+"/v2/datapoint", "application/x-protobuf", bytes.NewReader(nil))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func Test_sfxReceiver_handleEventReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+	config.AccessTokenPassthrough = true
+
+	msgBytes, err := (&sfxpb.EventUploadMessage{
+		Events: []*sfxpb.Event{
+			{EventType: "deployment", Timestamp: time.Now().Unix() * 1e3},
+		},
+	}).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		consumeErr     error
+		assertResponse func(t *testing.T, status int, body string)
+		wantToken      string
+	}{
+		{
+			name: "incorrect_method",
+			req:  httptest.NewRequest("PUT", "http://localhost", nil),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseInvalidMethod, body)
+			},
+		},
+		{
+			name: "bad_data_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte{1, 2, 3, 4}))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
+		{
+			name: "empty_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(nil))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusOK, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "msg_accepted",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				req.Header.Set("x-sf-token", "myToken")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+			wantToken: "myToken",
+		},
+		{
+			name: "next_consumer_error",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			consumeErr: errors.New("consumer failed"),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusInternalServerError, status)
+				assert.Equal(t, responseErrNextConsumer, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkLogsExporter)
+			sink.SetConsumeLogError(tt.consumeErr)
+			r, err := newReceiver(zap.NewNop(), *config)
+			require.NoError(t, err)
+			r.registerLogsConsumer(sink)
+
+			w := httptest.NewRecorder()
+			r.handleEventReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+
+			tt.assertResponse(t, resp.StatusCode, bodyStr)
+
+			if tt.wantToken != "" {
+				lds := sink.AllLogs()
+				require.Len(t, lds, 1)
+				token, ok := lds[0].ResourceLogs().At(0).Resource().Attributes().Get(splunk.SFxAccessTokenLabel)
+				require.True(t, ok)
+				assert.Equal(t, tt.wantToken, token.StringVal())
+			}
+		})
+	}
+}
+
 func Test_sfxReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := createDefaultConfig().(*Config)
@@ -513,6 +678,68 @@ func Test_sfxReceiver_AccessTokenPassthrough(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_Quarantine(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.Validation = ValidationConfig{
+		Dimensions:         true,
+		QuarantineExporter: "file/quarantine",
+	}
+
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := New(zap.NewNop(), *config, sink)
+	require.NoError(t, err)
+
+	quarantine := new(exportertest.SinkLogsExporter)
+	host := newLogsExportersHost(map[configmodels.Exporter]component.Exporter{
+		&configmodels.ExporterSettings{NameVal: "file/quarantine"}: quarantine,
+	})
+	require.NoError(t, rcv.Start(context.Background(), host))
+	defer rcv.Shutdown(context.Background())
+
+	currentTime := time.Now().Unix() * 1e3
+	sFxMsg := buildSFxMsg(currentTime, 13, 3)
+	sFxMsg.Datapoints = append(sFxMsg.Datapoints, &sfxpb.DataPoint{
+		Metric:     "rejected",
+		Timestamp:  currentTime,
+		Value:      sfxpb.Datum{IntValue: int64Ptr(1)},
+		MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+		Dimensions: []*sfxpb.Dimension{{Key: "host name", Value: "web-1"}},
+	})
+	msgBytes, err := sFxMsg.Marshal()
+	require.NoError(t, err)
+	req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(msgBytes))
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	w := httptest.NewRecorder()
+	rcv.(*sfxReceiver).handleReq(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.Equal(t, 1, sink.MetricsCount(), "the valid datapoint should be passed to the next consumer")
+	_, numPoints := sink.AllMetrics()[0].MetricAndDataPointCount()
+	assert.Equal(t, 1, numPoints)
+	require.Equal(t, 1, quarantine.LogRecordsCount(), "the rejected datapoint should be quarantined")
+	lr := quarantine.AllLogs()[0].ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, "rejected", lr.Body().StringVal())
+	reason, ok := lr.Attributes().Get(rejectedReasonAttribute)
+	require.True(t, ok)
+	assert.Equal(t, reasonInvalidDimensionKey, reason.StringVal())
+}
+
+func Test_sfxReceiver_QuarantineExporterNotFound(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = testutil.GetAvailableLocalAddress(t)
+	config.Validation = ValidationConfig{
+		MaxFutureSkew:      time.Minute,
+		QuarantineExporter: "file/quarantine",
+	}
+
+	rcv, err := New(zap.NewNop(), *config, new(exportertest.SinkMetricsExporter))
+	require.NoError(t, err)
+	err = rcv.Start(context.Background(), componenttest.NewNopHost())
+	assert.EqualError(t, err, `quarantine exporter "file/quarantine" of receiver "signalfx" is not used by a logs pipeline`)
+}
+
 func buildSFxMsg(time int64, value int64, dimensions uint) *sfxpb.DataPointUploadMessage {
 	return &sfxpb.DataPointUploadMessage{
 		Datapoints: []*sfxpb.DataPoint{
@@ -0,0 +1,89 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+// signalFxV2EventsToLogRecords converts SignalFx events to log records, as
+// expected by the SignalFx exporter: the type of an event is the name of its
+// log record, its dimensions the attributes, along with the
+// "com.splunk.signalfx.event_category" attribute holding its category, and
+// its properties the "com.splunk.signalfx.event_properties" map attribute.
+func signalFxV2EventsToLogRecords(events []*sfxpb.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	rl := ld.ResourceLogs().At(0)
+	rl.Resource().InitEmpty()
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(events))
+
+	for i, event := range events {
+		lr := logs.At(i)
+		lr.SetName(event.EventType)
+		// SignalFx timestamps are in milliseconds.
+		lr.SetTimestamp(pdata.TimestampUnixNano(event.Timestamp * 1e6))
+
+		attrs := lr.Attributes()
+		attrs.InitEmptyWithCapacity(len(event.Dimensions) + 2)
+		for _, dim := range event.Dimensions {
+			attrs.InsertString(dim.Key, dim.Value)
+		}
+
+		// The events without category are user defined ones.
+		category := sfxpb.EventCategory_USER_DEFINED
+		if event.Category != nil {
+			category = *event.Category
+		}
+		attrs.InsertInt(splunk.SFxEventCategoryKey, int64(category))
+
+		if len(event.Properties) > 0 {
+			properties := pdata.NewAttributeMap()
+			properties.InitEmptyWithCapacity(len(event.Properties))
+			for _, prop := range event.Properties {
+				if v, ok := propertyValue(prop.Value); ok {
+					properties.Insert(prop.Key, v)
+				}
+			}
+			propertiesVal := pdata.NewAttributeValueNull()
+			propertiesVal.SetMapVal(properties)
+			attrs.Insert(splunk.SFxEventPropertiesKey, propertiesVal)
+		}
+	}
+
+	return ld
+}
+
+func propertyValue(v *sfxpb.PropertyValue) (pdata.AttributeValue, bool) {
+	switch {
+	case v == nil:
+		return pdata.AttributeValue{}, false
+	case v.StrValue != nil:
+		return pdata.NewAttributeValueString(*v.StrValue), true
+	case v.IntValue != nil:
+		return pdata.NewAttributeValueInt(*v.IntValue), true
+	case v.DoubleValue != nil:
+		return pdata.NewAttributeValueDouble(*v.DoubleValue), true
+	case v.BoolValue != nil:
+		return pdata.NewAttributeValueBool(*v.BoolValue), true
+	default:
+		return pdata.AttributeValue{}, false
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+func Test_signalFxV2EventsToLogRecords(t *testing.T) {
+	alert := sfxpb.EventCategory_ALERT
+	boolTrue := true
+	events := []*sfxpb.Event{
+		{
+			EventType: "cpu high",
+			Category:  &alert,
+			Timestamp: 1574092046011,
+			Dimensions: []*sfxpb.Dimension{
+				{Key: "host", Value: "host-1"},
+			},
+			Properties: []*sfxpb.Property{
+				{Key: "str", Value: &sfxpb.PropertyValue{StrValue: strPtr("high")}},
+				{Key: "int", Value: &sfxpb.PropertyValue{IntValue: int64Ptr(13)}},
+				{Key: "double", Value: &sfxpb.PropertyValue{DoubleValue: float64Ptr(13.13)}},
+				{Key: "bool", Value: &sfxpb.PropertyValue{BoolValue: &boolTrue}},
+				{Key: "empty", Value: &sfxpb.PropertyValue{}},
+				{Key: "nil"},
+			},
+		},
+		{
+			EventType: "deployment",
+		},
+	}
+
+	ld := signalFxV2EventsToLogRecords(events)
+	require.Equal(t, 2, ld.LogRecordCount())
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+
+	lr := logs.At(0)
+	assert.Equal(t, "cpu high", lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(1574092046011000000), lr.Timestamp())
+	wantAttrs := pdata.NewAttributeMap()
+	wantAttrs.InsertString("host", "host-1")
+	wantAttrs.InsertInt(splunk.SFxEventCategoryKey, int64(sfxpb.EventCategory_ALERT))
+	wantProperties := pdata.NewAttributeMap()
+	wantProperties.InsertString("str", "high")
+	wantProperties.InsertInt("int", 13)
+	wantProperties.InsertDouble("double", 13.13)
+	wantProperties.InsertBool("bool", true)
+	wantPropertiesVal := pdata.NewAttributeValueNull()
+	wantPropertiesVal.SetMapVal(wantProperties)
+	wantAttrs.Insert(splunk.SFxEventPropertiesKey, wantPropertiesVal)
+	assert.Equal(t, wantAttrs.Sort(), lr.Attributes().Sort())
+
+	lr = logs.At(1)
+	assert.Equal(t, "deployment", lr.Name())
+	category, ok := lr.Attributes().Get(splunk.SFxEventCategoryKey)
+	require.True(t, ok)
+	assert.Equal(t, int64(sfxpb.EventCategory_USER_DEFINED), category.IntVal())
+	_, ok = lr.Attributes().Get(splunk.SFxEventPropertiesKey)
+	assert.False(t, ok)
+}
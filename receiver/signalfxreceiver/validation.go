@@ -0,0 +1,182 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// The reasons of the rejection of the datapoints.
+const (
+	reasonFutureTimestamp       = "future_timestamp"
+	reasonInvalidDimensionKey   = "invalid_dimension_key"
+	reasonInvalidDimensionValue = "invalid_dimension_value"
+)
+
+// The attributes of the log records of the rejected datapoints.
+const (
+	rejectedReasonAttribute     = "reason"
+	rejectedMessageAttribute    = "message"
+	rejectedMetricAttribute     = "metric"
+	rejectedMetricTypeAttribute = "metric_type"
+	rejectedValueAttribute      = "value"
+	rejectedDimensionsAttribute = "dimensions"
+)
+
+// The limits of the dimensions accepted by SignalFx, see
+// https://docs.signalfx.com/en/latest/metrics-metadata/metrics-metadata.html#criteria-for-metric-and-dimension-names-and-values.
+const (
+	maxDimensionKeyLength   = 128
+	maxDimensionValueLength = 256
+)
+
+var dimensionKeyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]*$`)
+
+// rejectedDatapoint is a datapoint failing the validation.
+type rejectedDatapoint struct {
+	datapoint *sfxpb.DataPoint
+	reason    string
+	message   string
+}
+
+// datapointValidator checks the received datapoints.
+type datapointValidator struct {
+	maxFutureSkew time.Duration
+	dimensions    bool
+}
+
+// newDatapointValidator returns the validator of the given configuration, or
+// nil if no check is enabled.
+func newDatapointValidator(cfg ValidationConfig) *datapointValidator {
+	if !cfg.enabled() {
+		return nil
+	}
+	return &datapointValidator{
+		maxFutureSkew: cfg.MaxFutureSkew,
+		dimensions:    cfg.Dimensions,
+	}
+}
+
+// filter splits the datapoints received at the given time into the valid and
+// the rejected ones. The valid datapoints are moved to the front of the slice,
+// which is truncated to them.
+func (v *datapointValidator) filter(datapoints []*sfxpb.DataPoint, now time.Time) ([]*sfxpb.DataPoint, []rejectedDatapoint) {
+	var rejected []rejectedDatapoint
+	valid := datapoints[:0]
+	for _, dp := range datapoints {
+		if dp == nil {
+			valid = append(valid, dp)
+			continue
+		}
+		if reason, message := v.check(dp, now); reason != "" {
+			rejected = append(rejected, rejectedDatapoint{datapoint: dp, reason: reason, message: message})
+			continue
+		}
+		valid = append(valid, dp)
+	}
+	return valid, rejected
+}
+
+// check returns the reason and a description of the rejection of the
+// datapoint, or an empty reason if the datapoint is valid.
+func (v *datapointValidator) check(dp *sfxpb.DataPoint, now time.Time) (string, string) {
+	if v.maxFutureSkew > 0 && dp.Timestamp != 0 {
+		limit := now.Add(v.maxFutureSkew)
+		if ts := time.Unix(0, dp.Timestamp*int64(time.Millisecond)); ts.After(limit) {
+			return reasonFutureTimestamp, fmt.Sprintf("timestamp %s is more than %s after the time of reception", ts.UTC().Format(time.RFC3339Nano), v.maxFutureSkew)
+		}
+	}
+	if v.dimensions {
+		for _, dim := range dp.Dimensions {
+			if dim == nil {
+				continue
+			}
+			if len(dim.Key) > maxDimensionKeyLength || !dimensionKeyPattern.MatchString(dim.Key) {
+				return reasonInvalidDimensionKey, fmt.Sprintf("dimension key %q must start with a letter, contain only letters, digits, '_' and '-', and be at most %d characters long", dim.Key, maxDimensionKeyLength)
+			}
+			if len(dim.Value) > maxDimensionValueLength {
+				return reasonInvalidDimensionValue, fmt.Sprintf("value of dimension %q is longer than %d characters", dim.Key, maxDimensionValueLength)
+			}
+		}
+	}
+	return "", ""
+}
+
+// findQuarantineExporter returns the logs exporter of the given name.
+func findQuarantineExporter(host component.Host, name string, receiver string) (consumer.LogsConsumer, error) {
+	for cfg, exporter := range host.GetExporters()[configmodels.LogsDataType] {
+		if cfg.Name() != name {
+			continue
+		}
+		if e, ok := exporter.(consumer.LogsConsumer); ok {
+			return e, nil
+		}
+		return nil, fmt.Errorf("quarantine exporter %q of receiver %q is not a logs exporter", name, receiver)
+	}
+	return nil, fmt.Errorf("quarantine exporter %q of receiver %q is not used by a logs pipeline", name, receiver)
+}
+
+// rejectedToLogs converts the rejected datapoints to logs, one log record per
+// datapoint whose body is the metric name and whose attributes hold the
+// reason of the rejection and the content of the datapoint.
+func rejectedToLogs(rejected []rejectedDatapoint, receiver string) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	rl.Resource().InitEmpty()
+	rl.Resource().Attributes().InsertString("receiver", receiver)
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	logs.Resize(len(rejected))
+	for i, r := range rejected {
+		dp := r.datapoint
+		lr := logs.At(i)
+		lr.SetName("signalfx.datapoint.rejected")
+		lr.SetTimestamp(pdata.TimestampUnixNano(dp.Timestamp * int64(time.Millisecond)))
+		lr.Body().InitEmpty()
+		lr.Body().SetStringVal(dp.Metric)
+
+		attrs := lr.Attributes()
+		attrs.InsertString(rejectedReasonAttribute, r.reason)
+		attrs.InsertString(rejectedMessageAttribute, r.message)
+		attrs.InsertString(rejectedMetricAttribute, dp.Metric)
+		attrs.InsertString(rejectedMetricTypeAttribute, dp.GetMetricType().String())
+		switch {
+		case dp.Value.IntValue != nil:
+			attrs.InsertInt(rejectedValueAttribute, *dp.Value.IntValue)
+		case dp.Value.DoubleValue != nil:
+			attrs.InsertDouble(rejectedValueAttribute, *dp.Value.DoubleValue)
+		case dp.Value.StrValue != nil:
+			attrs.InsertString(rejectedValueAttribute, *dp.Value.StrValue)
+		}
+		dims := pdata.NewAttributeValueMap()
+		for _, dim := range dp.Dimensions {
+			if dim != nil {
+				dims.MapVal().InsertString(dim.Key, dim.Value)
+			}
+		}
+		attrs.Insert(rejectedDimensionsAttribute, dims)
+	}
+	return ld
+}
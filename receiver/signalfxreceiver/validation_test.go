@@ -0,0 +1,215 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+)
+
+func TestNewDatapointValidator(t *testing.T) {
+	assert.Nil(t, newDatapointValidator(ValidationConfig{}))
+	assert.Nil(t, newDatapointValidator(ValidationConfig{QuarantineExporter: "file"}))
+	assert.NotNil(t, newDatapointValidator(ValidationConfig{MaxFutureSkew: time.Minute}))
+	assert.NotNil(t, newDatapointValidator(ValidationConfig{Dimensions: true}))
+}
+
+func TestDatapointValidatorCheck(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	v := &datapointValidator{maxFutureSkew: time.Minute, dimensions: true}
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+
+	tests := []struct {
+		name       string
+		timestamp  int64
+		dimensions []*sfxpb.Dimension
+		wantReason string
+	}{
+		{
+			name:       "valid",
+			timestamp:  nowMs + int64(time.Minute/time.Millisecond),
+			dimensions: []*sfxpb.Dimension{{Key: "host-name_1", Value: "web-1"}, nil},
+		},
+		{
+			name: "no_timestamp",
+		},
+		{
+			name:       "future_timestamp",
+			timestamp:  nowMs + int64(time.Minute/time.Millisecond) + 1,
+			wantReason: reasonFutureTimestamp,
+		},
+		{
+			name:       "key_with_space",
+			dimensions: []*sfxpb.Dimension{{Key: "host name", Value: "web-1"}},
+			wantReason: reasonInvalidDimensionKey,
+		},
+		{
+			name:       "key_starting_with_digit",
+			dimensions: []*sfxpb.Dimension{{Key: "1host", Value: "web-1"}},
+			wantReason: reasonInvalidDimensionKey,
+		},
+		{
+			name:       "key_too_long",
+			dimensions: []*sfxpb.Dimension{{Key: strings.Repeat("k", maxDimensionKeyLength+1), Value: "v"}},
+			wantReason: reasonInvalidDimensionKey,
+		},
+		{
+			name:       "value_too_long",
+			dimensions: []*sfxpb.Dimension{{Key: "k", Value: strings.Repeat("v", maxDimensionValueLength+1)}},
+			wantReason: reasonInvalidDimensionValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp := &sfxpb.DataPoint{Metric: "m", Timestamp: tt.timestamp, Dimensions: tt.dimensions}
+			reason, message := v.check(dp, now)
+			assert.Equal(t, tt.wantReason, reason)
+			if tt.wantReason == "" {
+				assert.Empty(t, message)
+			} else {
+				assert.NotEmpty(t, message)
+			}
+		})
+	}
+}
+
+func TestDatapointValidatorChecksDisabled(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	dp := &sfxpb.DataPoint{
+		Metric:     "m",
+		Timestamp:  now.Add(time.Hour).UnixNano() / int64(time.Millisecond),
+		Dimensions: []*sfxpb.Dimension{{Key: "host name", Value: "web-1"}},
+	}
+
+	reason, _ := (&datapointValidator{dimensions: true}).check(dp, now)
+	assert.Equal(t, reasonInvalidDimensionKey, reason, "the timestamp should not be checked")
+	reason, _ = (&datapointValidator{maxFutureSkew: time.Minute}).check(dp, now)
+	assert.Equal(t, reasonFutureTimestamp, reason, "the dimensions should not be checked")
+}
+
+func TestDatapointValidatorFilter(t *testing.T) {
+	v := &datapointValidator{dimensions: true}
+	valid1 := &sfxpb.DataPoint{Metric: "valid1"}
+	invalid := &sfxpb.DataPoint{Metric: "invalid", Dimensions: []*sfxpb.Dimension{{Key: "a b"}}}
+	valid2 := &sfxpb.DataPoint{Metric: "valid2"}
+
+	valid, rejected := v.filter([]*sfxpb.DataPoint{valid1, invalid, nil, valid2}, time.Now())
+	assert.Equal(t, []*sfxpb.DataPoint{valid1, nil, valid2}, valid)
+	require.Len(t, rejected, 1)
+	assert.Equal(t, invalid, rejected[0].datapoint)
+	assert.Equal(t, reasonInvalidDimensionKey, rejected[0].reason)
+}
+
+func TestRejectedToLogs(t *testing.T) {
+	rejected := []rejectedDatapoint{
+		{
+			datapoint: &sfxpb.DataPoint{
+				Metric:     "cpu.utilization",
+				Timestamp:  1600000000123,
+				Value:      sfxpb.Datum{DoubleValue: float64Ptr(12.5)},
+				MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+				Dimensions: []*sfxpb.Dimension{{Key: "host name", Value: "web-1"}},
+			},
+			reason:  reasonInvalidDimensionKey,
+			message: "invalid key",
+		},
+		{
+			datapoint: &sfxpb.DataPoint{
+				Metric:     "requests",
+				Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+				MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+			},
+			reason:  reasonFutureTimestamp,
+			message: "future",
+		},
+	}
+
+	ld := rejectedToLogs(rejected, "signalfx/test")
+	require.Equal(t, 2, ld.LogRecordCount())
+	rl := ld.ResourceLogs().At(0)
+	receiver, ok := rl.Resource().Attributes().Get("receiver")
+	require.True(t, ok)
+	assert.Equal(t, "signalfx/test", receiver.StringVal())
+
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	lr := logs.At(0)
+	assert.Equal(t, "cpu.utilization", lr.Body().StringVal())
+	assert.Equal(t, pdata.TimestampUnixNano(1600000000123000000), lr.Timestamp())
+	attrs := lr.Attributes()
+	assertStringAttribute(t, attrs, rejectedReasonAttribute, reasonInvalidDimensionKey)
+	assertStringAttribute(t, attrs, rejectedMessageAttribute, "invalid key")
+	assertStringAttribute(t, attrs, rejectedMetricAttribute, "cpu.utilization")
+	assertStringAttribute(t, attrs, rejectedMetricTypeAttribute, "GAUGE")
+	value, ok := attrs.Get(rejectedValueAttribute)
+	require.True(t, ok)
+	assert.Equal(t, 12.5, value.DoubleVal())
+	dims, ok := attrs.Get(rejectedDimensionsAttribute)
+	require.True(t, ok)
+	assertStringAttribute(t, dims.MapVal(), "host name", "web-1")
+
+	value, ok = logs.At(1).Attributes().Get(rejectedValueAttribute)
+	require.True(t, ok)
+	assert.Equal(t, int64(3), value.IntVal())
+}
+
+type exportersHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+func newLogsExportersHost(exporters map[configmodels.Exporter]component.Exporter) component.Host {
+	return &exportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{configmodels.LogsDataType: exporters},
+	}
+}
+
+func TestFindQuarantineExporter(t *testing.T) {
+	exp := new(exportertest.SinkLogsExporter)
+	host := newLogsExportersHost(map[configmodels.Exporter]component.Exporter{
+		&configmodels.ExporterSettings{NameVal: "file/quarantine"}: exp,
+		&configmodels.ExporterSettings{NameVal: "traces"}:          new(exportertest.SinkTraceExporter),
+	})
+
+	got, err := findQuarantineExporter(host, "file/quarantine", "signalfx")
+	require.NoError(t, err)
+	assert.Same(t, exp, got)
+
+	_, err = findQuarantineExporter(host, "traces", "signalfx")
+	assert.EqualError(t, err, `quarantine exporter "traces" of receiver "signalfx" is not a logs exporter`)
+
+	_, err = findQuarantineExporter(host, "missing", "signalfx")
+	assert.EqualError(t, err, `quarantine exporter "missing" of receiver "signalfx" is not used by a logs pipeline`)
+}
+
+func assertStringAttribute(t *testing.T, attrs pdata.AttributeMap, key string, want string) {
+	v, ok := attrs.Get(key)
+	require.True(t, ok, key)
+	assert.Equal(t, want, v.StringVal(), key)
+}
@@ -0,0 +1,140 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skywalkingreceiver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/SkyAPM/go2sky/reporter/grpc/common"
+	agentv3 "github.com/SkyAPM/go2sky/reporter/grpc/language-agent"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const (
+	receiverTransport = "grpc"
+	receiverFormat    = "skywalking"
+)
+
+var _ component.TraceReceiver = (*skywalkingReceiver)(nil)
+
+// skywalkingReceiver implements the component.TraceReceiver for the
+// SkyWalking trace segment report service.
+type skywalkingReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.TraceConsumer
+
+	server *grpc.Server
+	done   chan struct{}
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// newSkyWalkingReceiver creates the SkyWalking receiver with the given
+// parameters.
+func newSkyWalkingReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	r := &skywalkingReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}
+	return r, nil
+}
+
+// Start starts the gRPC server of the trace segment report service.
+func (r *skywalkingReceiver) Start(_ context.Context, host component.Host) error {
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		var opts []grpc.ServerOption
+		opts, err = r.config.GRPCServerSettings.ToServerOption()
+		if err != nil {
+			return
+		}
+
+		var ln net.Listener
+		ln, err = r.config.GRPCServerSettings.ToListener()
+		if err != nil {
+			return
+		}
+
+		r.server = grpc.NewServer(opts...)
+		agentv3.RegisterTraceSegmentReportServiceServer(r.server, r)
+		r.done = make(chan struct{})
+		go func() {
+			defer close(r.done)
+			if err := r.server.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+				host.ReportFatalError(err)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops the gRPC server, closing the active streams.
+func (r *skywalkingReceiver) Shutdown(context.Context) error {
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.server != nil {
+			r.server.Stop()
+			<-r.done
+		}
+	})
+	return err
+}
+
+// Collect implements agentv3.TraceSegmentReportServiceServer. The agents
+// stream one segment per message, each segment holding the spans of a trace
+// executed by a single thread of a service instance.
+func (r *skywalkingReceiver) Collect(stream agentv3.TraceSegmentReportService_CollectServer) error {
+	receiverCtx := obsreport.ReceiverContext(stream.Context(), r.config.Name(), receiverTransport, "")
+	for {
+		segment, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&common.Commands{})
+		}
+		if err != nil {
+			return err
+		}
+
+		ctx := obsreport.StartTraceDataReceiveOp(
+			receiverCtx, r.config.Name(), receiverTransport, obsreport.WithLongLivedCtx())
+		td := segmentToTraces(segment)
+		err = r.nextConsumer.ConsumeTraces(ctx, td)
+		obsreport.EndTraceDataReceiveOp(ctx, receiverFormat, td.SpanCount(), err)
+		if err != nil {
+			// The segment is dropped, the agents do not retry it anyway.
+			r.logger.Error("Failed to consume SkyWalking segment", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skywalkingreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	agentv3 "github.com/SkyAPM/go2sky/reporter/grpc/language-agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = addr
+	sink := new(exportertest.SinkTraceExporter)
+	r, err := newSkyWalkingReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer r.Shutdown(context.Background())
+	assert.Equal(t, componenterror.ErrAlreadyStarted, r.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream, err := agentv3.NewTraceSegmentReportServiceClient(conn).Collect(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(testSegment()))
+	require.NoError(t, stream.Send(&agentv3.SegmentObject{
+		TraceId:        "1c6ba1d9-a29c-4c5f-9a1e-79e8df3e4cfe",
+		TraceSegmentId: "segment",
+		Service:        "frontend",
+		Spans:          []*agentv3.SpanObject{{ParentSpanId: -1}},
+	}))
+	_, err = stream.CloseAndRecv()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.SpansCount() == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	traces := sink.AllTraces()
+	require.Len(t, traces, 2)
+	serviceName, ok := traces[1].ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	require.True(t, ok)
+	assert.Equal(t, "frontend", serviceName.StringVal())
+
+	assert.NoError(t, r.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, r.Shutdown(context.Background()))
+}
+
+func TestReceiverStartError(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = "localhost:invalid"
+	r, err := newSkyWalkingReceiver(zap.NewNop(), cfg, new(exportertest.SinkTraceExporter))
+	require.NoError(t, err)
+
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,192 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skywalkingreceiver
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"hash/fnv"
+	"net"
+	"strconv"
+	"strings"
+
+	agentv3 "github.com/SkyAPM/go2sky/reporter/grpc/language-agent"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+const (
+	segmentIDAttribute   = "skywalking.segment.id"
+	spanIDAttribute      = "skywalking.span.id"
+	spanLayerAttribute   = "skywalking.span.layer"
+	componentIDAttribute = "skywalking.component.id"
+
+	// The key of the log data holding the name of the event, as set by the
+	// agents for errors and exceptions.
+	logEventKey     = "event"
+	defaultLogEvent = "log"
+)
+
+// segmentToTraces converts the spans of a SkyWalking segment into spans of
+// the resource of the service instance that reported it.
+func segmentToTraces(segment *agentv3.SegmentObject) pdata.Traces {
+	td := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	rss.Resize(1)
+	rs := rss.At(0)
+	rs.Resource().InitEmpty()
+	attrs := rs.Resource().Attributes()
+	if segment.GetService() != "" {
+		attrs.InsertString(conventions.AttributeServiceName, segment.GetService())
+	}
+	if segment.GetServiceInstance() != "" {
+		attrs.InsertString(conventions.AttributeServiceInstance, segment.GetServiceInstance())
+	}
+	rs.InstrumentationLibrarySpans().Resize(1)
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+
+	traceID := toTraceID(segment.GetTraceId())
+	spans.Resize(len(segment.GetSpans()))
+	for i, swSpan := range segment.GetSpans() {
+		fillSpan(spans.At(i), traceID, segment.GetTraceSegmentId(), swSpan)
+	}
+	return td
+}
+
+func fillSpan(span pdata.Span, traceID pdata.TraceID, segmentID string, swSpan *agentv3.SpanObject) {
+	span.SetTraceID(traceID)
+	span.SetSpanID(toSpanID(segmentID, swSpan.GetSpanId()))
+	span.SetName(swSpan.GetOperationName())
+	span.SetKind(spanKind(swSpan))
+	span.SetStartTime(msToUnixNano(swSpan.GetStartTime()))
+	span.SetEndTime(msToUnixNano(swSpan.GetEndTime()))
+
+	// The first span of a segment has no parent in the segment, it is
+	// instead referenced to the span of the parent segment, another thread
+	// or process, that started it. The other references, such as the ones
+	// of batch consumers, are reported as links.
+	refs := swSpan.GetRefs()
+	if swSpan.GetParentSpanId() >= 0 {
+		span.SetParentSpanID(toSpanID(segmentID, swSpan.GetParentSpanId()))
+	} else if len(refs) > 0 {
+		span.SetParentSpanID(toSpanID(refs[0].GetParentTraceSegmentId(), refs[0].GetParentSpanId()))
+		refs = refs[1:]
+	}
+	links := span.Links()
+	links.Resize(len(refs))
+	for i, ref := range refs {
+		link := links.At(i)
+		link.SetTraceID(toTraceID(ref.GetTraceId()))
+		link.SetSpanID(toSpanID(ref.GetParentTraceSegmentId(), ref.GetParentSpanId()))
+	}
+
+	if swSpan.GetIsError() {
+		span.Status().InitEmpty()
+		span.Status().SetCode(pdata.StatusCodeUnknownError)
+	}
+
+	attrs := span.Attributes()
+	attrs.InsertString(segmentIDAttribute, segmentID)
+	attrs.InsertInt(spanIDAttribute, int64(swSpan.GetSpanId()))
+	if swSpan.GetSpanLayer() != agentv3.SpanLayer_Unknown {
+		attrs.InsertString(spanLayerAttribute, swSpan.GetSpanLayer().String())
+	}
+	if swSpan.GetComponentId() != 0 {
+		attrs.InsertInt(componentIDAttribute, int64(swSpan.GetComponentId()))
+	}
+	fillPeer(attrs, swSpan.GetPeer())
+	for _, tag := range swSpan.GetTags() {
+		attrs.UpsertString(tag.GetKey(), tag.GetValue())
+	}
+
+	events := span.Events()
+	events.Resize(len(swSpan.GetLogs()))
+	for i, log := range swSpan.GetLogs() {
+		event := events.At(i)
+		event.SetTimestamp(msToUnixNano(log.GetTime()))
+		event.SetName(defaultLogEvent)
+		for _, data := range log.GetData() {
+			if data.GetKey() == logEventKey {
+				event.SetName(data.GetValue())
+			}
+			event.Attributes().UpsertString(data.GetKey(), data.GetValue())
+		}
+	}
+}
+
+// spanKind maps the type of a SkyWalking span to a span kind, the entry and
+// exit spans of the message queue layer being consumers and producers.
+func spanKind(swSpan *agentv3.SpanObject) pdata.SpanKind {
+	mq := swSpan.GetSpanLayer() == agentv3.SpanLayer_MQ
+	switch swSpan.GetSpanType() {
+	case agentv3.SpanType_Entry:
+		if mq {
+			return pdata.SpanKindCONSUMER
+		}
+		return pdata.SpanKindSERVER
+	case agentv3.SpanType_Exit:
+		if mq {
+			return pdata.SpanKindPRODUCER
+		}
+		return pdata.SpanKindCLIENT
+	default:
+		return pdata.SpanKindINTERNAL
+	}
+}
+
+// fillPeer sets the net.peer attributes from the address of the remote
+// side of an exit span, usually reported as host:port.
+func fillPeer(attrs pdata.AttributeMap, peer string) {
+	if peer == "" {
+		return
+	}
+	host, port, err := net.SplitHostPort(peer)
+	if err != nil {
+		attrs.InsertString(conventions.AttributeNetPeerName, peer)
+		return
+	}
+	attrs.InsertString(conventions.AttributeNetPeerName, host)
+	if p, err := strconv.ParseInt(port, 10, 64); err == nil {
+		attrs.InsertInt(conventions.AttributeNetPeerPort, p)
+	}
+}
+
+// toTraceID converts a SkyWalking trace id to a trace id. The UUIDs used by
+// most agents are kept as is, the other ids, such as the
+// <uuid>.<thread>.<sequence> ids of the Java agent, are hashed.
+func toTraceID(id string) pdata.TraceID {
+	if b, err := hex.DecodeString(strings.ReplaceAll(id, "-", "")); err == nil && len(b) == 16 {
+		return pdata.NewTraceID(b)
+	}
+	h := fnv.New128a()
+	h.Write([]byte(id))
+	return pdata.NewTraceID(h.Sum(nil))
+}
+
+// toSpanID derives a span id from the id of a segment and the id of a span,
+// which is only unique within its segment. The same id is derived for the
+// references to the span from the other segments.
+func toSpanID(segmentID string, spanID int32) pdata.SpanID {
+	h := fnv.New64a()
+	h.Write([]byte(segmentID))
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(spanID))
+	h.Write(b[:])
+	return pdata.NewSpanID(h.Sum(nil))
+}
+
+func msToUnixNano(ms int64) pdata.TimestampUnixNano {
+	return pdata.TimestampUnixNano(uint64(ms) * 1e6)
+}
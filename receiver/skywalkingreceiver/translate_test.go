@@ -0,0 +1,203 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package skywalkingreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SkyAPM/go2sky/reporter/grpc/common"
+	agentv3 "github.com/SkyAPM/go2sky/reporter/grpc/language-agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+var testStartTime = time.Date(2020, 9, 15, 12, 0, 0, 0, time.UTC)
+
+func ms(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func testSegment() *agentv3.SegmentObject {
+	return &agentv3.SegmentObject{
+		TraceId:         "1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.55.16001712000000001",
+		TraceSegmentId:  "1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.56.16001712000010000",
+		Service:         "orders",
+		ServiceInstance: "orders-7d9c@10.0.0.5",
+		Spans: []*agentv3.SpanObject{
+			{
+				SpanId:        0,
+				ParentSpanId:  -1,
+				StartTime:     ms(testStartTime),
+				EndTime:       ms(testStartTime.Add(250 * time.Millisecond)),
+				OperationName: "/api/orders",
+				SpanType:      agentv3.SpanType_Entry,
+				SpanLayer:     agentv3.SpanLayer_Http,
+				ComponentId:   1,
+				IsError:       true,
+				Refs: []*agentv3.SegmentReference{
+					{
+						RefType:              agentv3.RefType_CrossProcess,
+						TraceId:              "1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.55.16001712000000001",
+						ParentTraceSegmentId: "parent-segment",
+						ParentSpanId:         2,
+						ParentService:        "frontend",
+					},
+				},
+				Tags: []*common.KeyStringValuePair{
+					{Key: "url", Value: "http://orders/api/orders"},
+					{Key: "status_code", Value: "500"},
+				},
+				Logs: []*agentv3.Log{
+					{
+						Time: ms(testStartTime.Add(200 * time.Millisecond)),
+						Data: []*common.KeyStringValuePair{
+							{Key: "event", Value: "error"},
+							{Key: "error.kind", Value: "java.lang.IllegalStateException"},
+						},
+					},
+				},
+			},
+			{
+				SpanId:        1,
+				ParentSpanId:  0,
+				StartTime:     ms(testStartTime.Add(10 * time.Millisecond)),
+				EndTime:       ms(testStartTime.Add(20 * time.Millisecond)),
+				OperationName: "Mysql/JDBI/PreparedStatement/execute",
+				Peer:          "mysql:3306",
+				SpanType:      agentv3.SpanType_Exit,
+				SpanLayer:     agentv3.SpanLayer_Database,
+				Tags: []*common.KeyStringValuePair{
+					{Key: "db.statement", Value: "SELECT * FROM orders"},
+				},
+			},
+			{
+				SpanId:        2,
+				ParentSpanId:  0,
+				OperationName: "Kafka/orders/Producer",
+				Peer:          "kafka",
+				SpanType:      agentv3.SpanType_Exit,
+				SpanLayer:     agentv3.SpanLayer_MQ,
+			},
+		},
+	}
+}
+
+func TestSegmentToTraces(t *testing.T) {
+	segment := testSegment()
+	td := segmentToTraces(segment)
+	require.Equal(t, 3, td.SpanCount())
+
+	rs := td.ResourceSpans().At(0)
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		conventions.AttributeServiceName:     pdata.NewAttributeValueString("orders"),
+		conventions.AttributeServiceInstance: pdata.NewAttributeValueString("orders-7d9c@10.0.0.5"),
+	}).Sort(), rs.Resource().Attributes().Sort())
+
+	spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+	entry, db, mq := spans.At(0), spans.At(1), spans.At(2)
+
+	traceID := toTraceID(segment.TraceId)
+	for i := 0; i < spans.Len(); i++ {
+		assert.Equal(t, traceID, spans.At(i).TraceID())
+	}
+
+	assert.Equal(t, "/api/orders", entry.Name())
+	assert.Equal(t, pdata.SpanKindSERVER, entry.Kind())
+	assert.Equal(t, toSpanID(segment.TraceSegmentId, 0), entry.SpanID())
+	assert.Equal(t, toSpanID("parent-segment", 2), entry.ParentSpanID())
+	assert.Equal(t, pdata.TimestampUnixNano(testStartTime.UnixNano()), entry.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(testStartTime.Add(250*time.Millisecond).UnixNano()), entry.EndTime())
+	assert.Equal(t, pdata.StatusCodeUnknownError, entry.Status().Code())
+	assert.Equal(t, 0, entry.Links().Len())
+	assert.Equal(t, pdata.NewAttributeMap().InitFromMap(map[string]pdata.AttributeValue{
+		segmentIDAttribute:   pdata.NewAttributeValueString(segment.TraceSegmentId),
+		spanIDAttribute:      pdata.NewAttributeValueInt(0),
+		spanLayerAttribute:   pdata.NewAttributeValueString("Http"),
+		componentIDAttribute: pdata.NewAttributeValueInt(1),
+		"url":                pdata.NewAttributeValueString("http://orders/api/orders"),
+		"status_code":        pdata.NewAttributeValueString("500"),
+	}).Sort(), entry.Attributes().Sort())
+	require.Equal(t, 1, entry.Events().Len())
+	event := entry.Events().At(0)
+	assert.Equal(t, "error", event.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(testStartTime.Add(200*time.Millisecond).UnixNano()), event.Timestamp())
+	kind, ok := event.Attributes().Get("error.kind")
+	require.True(t, ok)
+	assert.Equal(t, "java.lang.IllegalStateException", kind.StringVal())
+
+	assert.Equal(t, pdata.SpanKindCLIENT, db.Kind())
+	assert.Equal(t, entry.SpanID(), db.ParentSpanID())
+	assert.True(t, db.Status().IsNil())
+	peerName, ok := db.Attributes().Get(conventions.AttributeNetPeerName)
+	require.True(t, ok)
+	assert.Equal(t, "mysql", peerName.StringVal())
+	peerPort, ok := db.Attributes().Get(conventions.AttributeNetPeerPort)
+	require.True(t, ok)
+	assert.EqualValues(t, 3306, peerPort.IntVal())
+
+	assert.Equal(t, pdata.SpanKindPRODUCER, mq.Kind())
+	peerName, ok = mq.Attributes().Get(conventions.AttributeNetPeerName)
+	require.True(t, ok)
+	assert.Equal(t, "kafka", peerName.StringVal())
+	_, ok = mq.Attributes().Get(conventions.AttributeNetPeerPort)
+	assert.False(t, ok)
+}
+
+func TestSegmentToTracesLinks(t *testing.T) {
+	// A batch consumer references the segments of all the messages it
+	// consumed.
+	segment := &agentv3.SegmentObject{
+		TraceId:        "a",
+		TraceSegmentId: "consumer",
+		Spans: []*agentv3.SpanObject{
+			{
+				ParentSpanId: -1,
+				SpanType:     agentv3.SpanType_Entry,
+				SpanLayer:    agentv3.SpanLayer_MQ,
+				Refs: []*agentv3.SegmentReference{
+					{TraceId: "a", ParentTraceSegmentId: "producer-a", ParentSpanId: 1},
+					{TraceId: "b", ParentTraceSegmentId: "producer-b", ParentSpanId: 3},
+				},
+			},
+		},
+	}
+	span := segmentToTraces(segment).ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, pdata.SpanKindCONSUMER, span.Kind())
+	assert.Equal(t, toSpanID("producer-a", 1), span.ParentSpanID())
+	require.Equal(t, 1, span.Links().Len())
+	assert.Equal(t, toTraceID("b"), span.Links().At(0).TraceID())
+	assert.Equal(t, toSpanID("producer-b", 3), span.Links().At(0).SpanID())
+}
+
+func TestToTraceID(t *testing.T) {
+	assert.Equal(t, "1c6ba1d9a29c4c5f9a1e79e8df3e4cfe", toTraceID("1c6ba1d9-a29c-4c5f-9a1e-79e8df3e4cfe").String())
+	assert.Equal(t, "1c6ba1d9a29c4c5f9a1e79e8df3e4cfe", toTraceID("1c6ba1d9a29c4c5f9a1e79e8df3e4cfe").String())
+
+	javaID := toTraceID("1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.55.16001712000000001")
+	assert.Len(t, javaID.Bytes(), 16)
+	assert.Equal(t, javaID, toTraceID("1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.55.16001712000000001"))
+	assert.NotEqual(t, javaID, toTraceID("1c6ba1d9a29c4c5f9a1e79e8df3e4cfe.55.16001712000000002"))
+}
+
+func TestToSpanID(t *testing.T) {
+	id := toSpanID("segment", 1)
+	assert.Len(t, id.Bytes(), 8)
+	assert.Equal(t, id, toSpanID("segment", 1))
+	assert.NotEqual(t, id, toSpanID("segment", 2))
+	assert.NotEqual(t, id, toSpanID("other", 1))
+}
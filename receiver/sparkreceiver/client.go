@@ -0,0 +1,167 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	applicationsPath = "/api/v1/applications"
+	executorsPath    = "/api/v1/applications/%s/executors"
+	jobsPath         = "/api/v1/applications/%s/jobs"
+	stagesPath       = "/api/v1/applications/%s/stages"
+
+	statusRunning = "running"
+	statusActive  = "active"
+)
+
+type application struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Attempts []attempt `json:"attempts"`
+}
+
+type attempt struct {
+	// StartTimeEpoch is the start time of the attempt in milliseconds since
+	// the epoch.
+	StartTimeEpoch int64 `json:"startTimeEpoch"`
+	Completed      bool  `json:"completed"`
+}
+
+type executor struct {
+	ID             string  `json:"id"`
+	MemoryUsed     float64 `json:"memoryUsed"`
+	MaxMemory      float64 `json:"maxMemory"`
+	DiskUsed       float64 `json:"diskUsed"`
+	ActiveTasks    float64 `json:"activeTasks"`
+	CompletedTasks float64 `json:"completedTasks"`
+	FailedTasks    float64 `json:"failedTasks"`
+	TotalGCTime    float64 `json:"totalGCTime"`
+}
+
+type job struct {
+	JobID             int64   `json:"jobId"`
+	Status            string  `json:"status"`
+	NumActiveTasks    float64 `json:"numActiveTasks"`
+	NumCompletedTasks float64 `json:"numCompletedTasks"`
+	NumFailedTasks    float64 `json:"numFailedTasks"`
+	NumActiveStages   float64 `json:"numActiveStages"`
+}
+
+type stage struct {
+	StageID           int64   `json:"stageId"`
+	AttemptID         int64   `json:"attemptId"`
+	Name              string  `json:"name"`
+	Status            string  `json:"status"`
+	NumActiveTasks    float64 `json:"numActiveTasks"`
+	NumCompleteTasks  float64 `json:"numCompleteTasks"`
+	NumFailedTasks    float64 `json:"numFailedTasks"`
+	InputBytes        float64 `json:"inputBytes"`
+	OutputBytes       float64 `json:"outputBytes"`
+	ShuffleReadBytes  float64 `json:"shuffleReadBytes"`
+	ShuffleWriteBytes float64 `json:"shuffleWriteBytes"`
+	ExecutorRunTime   float64 `json:"executorRunTime"`
+}
+
+// client retrieves the state of the Spark applications.
+type client interface {
+	applications(ctx context.Context) ([]application, error)
+	executors(ctx context.Context, appID string) ([]executor, error)
+	runningJobs(ctx context.Context, appID string) ([]job, error)
+	activeStages(ctx context.Context, appID string) ([]stage, error)
+}
+
+type restClient struct {
+	client   *http.Client
+	endpoint string
+}
+
+var _ client = (*restClient)(nil)
+
+func newRESTClient(config *Config) (*restClient, error) {
+	httpClient, err := config.HTTPClientSettings.ToClient()
+	if err != nil {
+		return nil, err
+	}
+	return &restClient{
+		client:   httpClient,
+		endpoint: strings.TrimSuffix(config.Endpoint, "/"),
+	}, nil
+}
+
+// applications returns the running applications.
+func (c *restClient) applications(ctx context.Context) ([]application, error) {
+	var apps []application
+	if err := c.get(ctx, applicationsPath, url.Values{"status": {statusRunning}}, &apps); err != nil {
+		return nil, err
+	}
+	return apps, nil
+}
+
+func (c *restClient) executors(ctx context.Context, appID string) ([]executor, error) {
+	var executors []executor
+	if err := c.get(ctx, fmt.Sprintf(executorsPath, url.PathEscape(appID)), nil, &executors); err != nil {
+		return nil, err
+	}
+	return executors, nil
+}
+
+func (c *restClient) runningJobs(ctx context.Context, appID string) ([]job, error) {
+	var jobs []job
+	if err := c.get(ctx, fmt.Sprintf(jobsPath, url.PathEscape(appID)), url.Values{"status": {statusRunning}}, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (c *restClient) activeStages(ctx context.Context, appID string) ([]stage, error) {
+	var stages []stage
+	if err := c.get(ctx, fmt.Sprintf(stagesPath, url.PathEscape(appID)), url.Values{"status": {statusActive}}, &stages); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+func (c *restClient) get(ctx context.Context, path string, query url.Values, v interface{}) error {
+	u := c.endpoint + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response of %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkreceiver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testApplicationID = "app-20200915120000-0001"
+
+// startServer starts a fake Spark monitoring REST API serving the responses
+// of testdata.
+func startServer(t *testing.T) string {
+	mux := http.NewServeMux()
+	serveFile := func(file string, status string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("status") != status {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			body, err := ioutil.ReadFile(path.Join(".", "testdata", file))
+			require.NoError(t, err)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(body)
+		}
+	}
+	mux.HandleFunc(applicationsPath, serveFile("applications.json", statusRunning))
+	mux.HandleFunc(fmt.Sprintf(executorsPath, testApplicationID), serveFile("executors.json", ""))
+	mux.HandleFunc(fmt.Sprintf(jobsPath, testApplicationID), serveFile("jobs.json", statusRunning))
+	mux.HandleFunc(fmt.Sprintf(stagesPath, testApplicationID), serveFile("stages.json", statusActive))
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func newTestClient(t *testing.T, endpoint string) *restClient {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = endpoint + "/"
+	c, err := newRESTClient(cfg)
+	require.NoError(t, err)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+
+	apps, err := c.applications(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []application{{
+		ID:       testApplicationID,
+		Name:     "WordCount",
+		Attempts: []attempt{{StartTimeEpoch: 1600171200000}},
+	}}, apps)
+
+	executors, err := c.executors(context.Background(), testApplicationID)
+	require.NoError(t, err)
+	require.Len(t, executors, 2)
+	assert.Equal(t, executor{
+		ID:             "1",
+		MemoryUsed:     1048576,
+		MaxMemory:      2101975449,
+		DiskUsed:       2048,
+		ActiveTasks:    3,
+		CompletedTasks: 42,
+		FailedTasks:    1,
+		TotalGCTime:    1250,
+	}, executors[1])
+
+	jobs, err := c.runningJobs(context.Background(), testApplicationID)
+	require.NoError(t, err)
+	assert.Equal(t, []job{{
+		JobID:             3,
+		Status:            "RUNNING",
+		NumActiveTasks:    3,
+		NumCompletedTasks: 18,
+		NumFailedTasks:    1,
+		NumActiveStages:   1,
+	}}, jobs)
+
+	stages, err := c.activeStages(context.Background(), testApplicationID)
+	require.NoError(t, err)
+	assert.Equal(t, []stage{{
+		StageID:          7,
+		Name:             "collect at WordCount.scala:42",
+		Status:           "ACTIVE",
+		NumActiveTasks:   3,
+		NumCompleteTasks: 6,
+		NumFailedTasks:   1,
+		OutputBytes:      4096,
+		ShuffleReadBytes: 1048576,
+		ExecutorRunTime:  54000,
+	}}, stages)
+}
+
+func TestClientErrors(t *testing.T) {
+	c := newTestClient(t, startServer(t))
+	_, err := c.executors(context.Background(), "unknown")
+	assert.EqualError(t, err, "request to /api/v1/applications/unknown/executors failed: 404 Not Found")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html>"))
+	}))
+	defer server.Close()
+	c = newTestClient(t, server.URL)
+	_, err = c.applications(context.Background())
+	assert.EqualError(t, err, "failed to decode response of /api/v1/applications: invalid character '<' looking for beginning of value")
+}
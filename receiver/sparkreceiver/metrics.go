@@ -0,0 +1,268 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkreceiver
+
+import (
+	"strconv"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	applicationIDLabel   = "spark.application.id"
+	applicationNameLabel = "spark.application.name"
+	executorIDLabel      = "spark.executor.id"
+	jobIDLabel           = "spark.job.id"
+	stageIDLabel         = "spark.stage.id"
+	stageAttemptIDLabel  = "spark.stage.attempt_id"
+	stageNameLabel       = "spark.stage.name"
+	stateLabel           = "state"
+	directionLabel       = "direction"
+
+	stateCompleted = "completed"
+	stateFailed    = "failed"
+	directionRead  = "read"
+	directionWrite = "write"
+)
+
+var (
+	executorLabelKeys = []string{applicationIDLabel, applicationNameLabel, executorIDLabel}
+	jobLabelKeys      = []string{applicationIDLabel, applicationNameLabel, jobIDLabel}
+	stageLabelKeys    = []string{applicationIDLabel, applicationNameLabel, stageIDLabel, stageAttemptIDLabel, stageNameLabel}
+)
+
+// withLabel returns a copy of the label keys with an extra key.
+func withLabel(keys []string, key string) []string {
+	return append(append([]string(nil), keys...), key)
+}
+
+// metricDef describes a metric reported with a time series per executor,
+// job or stage of an application.
+type metricDef struct {
+	name      string
+	unit      string
+	desc      string
+	labelKeys []string
+	mdType    metricspb.MetricDescriptor_Type
+}
+
+var (
+	executorMemoryUsageMetric = &metricDef{
+		name:      "spark.executor.memory.usage",
+		unit:      "By",
+		desc:      "Storage memory used by the executor.",
+		labelKeys: executorLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	executorMemoryMaxMetric = &metricDef{
+		name:      "spark.executor.memory.max",
+		unit:      "By",
+		desc:      "Storage memory available to the executor.",
+		labelKeys: executorLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	executorDiskUsageMetric = &metricDef{
+		name:      "spark.executor.disk.usage",
+		unit:      "By",
+		desc:      "Disk space used by the executor for RDD storage.",
+		labelKeys: executorLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	executorTasksActiveMetric = &metricDef{
+		name:      "spark.executor.tasks.active",
+		unit:      "1",
+		desc:      "Number of tasks running on the executor.",
+		labelKeys: executorLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	executorTasksMetric = &metricDef{
+		name:      "spark.executor.tasks.count",
+		unit:      "1",
+		desc:      "Number of tasks run by the executor, by final state.",
+		labelKeys: withLabel(executorLabelKeys, stateLabel),
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	executorGCTimeMetric = &metricDef{
+		name:      "spark.executor.gc.time",
+		unit:      "ms",
+		desc:      "Time spent by the executor in garbage collection.",
+		labelKeys: executorLabelKeys,
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+
+	jobTasksActiveMetric = &metricDef{
+		name:      "spark.job.tasks.active",
+		unit:      "1",
+		desc:      "Number of running tasks of the job.",
+		labelKeys: jobLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	jobTasksMetric = &metricDef{
+		name:      "spark.job.tasks.count",
+		unit:      "1",
+		desc:      "Number of tasks of the job, by final state.",
+		labelKeys: withLabel(jobLabelKeys, stateLabel),
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	jobStagesActiveMetric = &metricDef{
+		name:      "spark.job.stages.active",
+		unit:      "1",
+		desc:      "Number of active stages of the job.",
+		labelKeys: jobLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+
+	stageTasksActiveMetric = &metricDef{
+		name:      "spark.stage.tasks.active",
+		unit:      "1",
+		desc:      "Number of running tasks of the stage.",
+		labelKeys: stageLabelKeys,
+		mdType:    metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+	stageTasksMetric = &metricDef{
+		name:      "spark.stage.tasks.count",
+		unit:      "1",
+		desc:      "Number of tasks of the stage, by final state.",
+		labelKeys: withLabel(stageLabelKeys, stateLabel),
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	stageIOMetric = &metricDef{
+		name:      "spark.stage.io",
+		unit:      "By",
+		desc:      "Bytes read from and written to storage by the stage.",
+		labelKeys: withLabel(stageLabelKeys, directionLabel),
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	stageShuffleIOMetric = &metricDef{
+		name:      "spark.stage.shuffle.io",
+		unit:      "By",
+		desc:      "Bytes read and written by the stage during shuffles.",
+		labelKeys: withLabel(stageLabelKeys, directionLabel),
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+	stageExecutorRunTimeMetric = &metricDef{
+		name:      "spark.stage.executor.run_time",
+		unit:      "ms",
+		desc:      "Time spent by the executors running the tasks of the stage.",
+		labelKeys: stageLabelKeys,
+		mdType:    metricspb.MetricDescriptor_CUMULATIVE_INT64,
+	}
+)
+
+// metricsBuilder accumulates the time series of the metrics of the
+// applications.
+type metricsBuilder struct {
+	now     *timestamppb.Timestamp
+	metrics []*metricspb.Metric
+	byDef   map[*metricDef]*metricspb.Metric
+}
+
+func newMetricsBuilder(now time.Time) *metricsBuilder {
+	return &metricsBuilder{
+		now:   timestamppb.New(now),
+		byDef: make(map[*metricDef]*metricspb.Metric),
+	}
+}
+
+func (b *metricsBuilder) addExecutor(app *application, e *executor) {
+	start := applicationStart(app)
+	labels := []string{app.ID, app.Name, e.ID}
+	b.add(executorMemoryUsageMetric, nil, e.MemoryUsed, labels...)
+	b.add(executorMemoryMaxMetric, nil, e.MaxMemory, labels...)
+	b.add(executorDiskUsageMetric, nil, e.DiskUsed, labels...)
+	b.add(executorTasksActiveMetric, nil, e.ActiveTasks, labels...)
+	b.add(executorTasksMetric, start, e.CompletedTasks, append(labels, stateCompleted)...)
+	b.add(executorTasksMetric, start, e.FailedTasks, append(labels, stateFailed)...)
+	b.add(executorGCTimeMetric, start, e.TotalGCTime, labels...)
+}
+
+func (b *metricsBuilder) addJob(app *application, j *job) {
+	start := applicationStart(app)
+	labels := []string{app.ID, app.Name, strconv.FormatInt(j.JobID, 10)}
+	b.add(jobTasksActiveMetric, nil, j.NumActiveTasks, labels...)
+	b.add(jobTasksMetric, start, j.NumCompletedTasks, append(labels, stateCompleted)...)
+	b.add(jobTasksMetric, start, j.NumFailedTasks, append(labels, stateFailed)...)
+	b.add(jobStagesActiveMetric, nil, j.NumActiveStages, labels...)
+}
+
+func (b *metricsBuilder) addStage(app *application, s *stage) {
+	start := applicationStart(app)
+	labels := []string{
+		app.ID,
+		app.Name,
+		strconv.FormatInt(s.StageID, 10),
+		strconv.FormatInt(s.AttemptID, 10),
+		s.Name,
+	}
+	b.add(stageTasksActiveMetric, nil, s.NumActiveTasks, labels...)
+	b.add(stageTasksMetric, start, s.NumCompleteTasks, append(labels, stateCompleted)...)
+	b.add(stageTasksMetric, start, s.NumFailedTasks, append(labels, stateFailed)...)
+	b.add(stageIOMetric, start, s.InputBytes, append(labels, directionRead)...)
+	b.add(stageIOMetric, start, s.OutputBytes, append(labels, directionWrite)...)
+	b.add(stageShuffleIOMetric, start, s.ShuffleReadBytes, append(labels, directionRead)...)
+	b.add(stageShuffleIOMetric, start, s.ShuffleWriteBytes, append(labels, directionWrite)...)
+	b.add(stageExecutorRunTimeMetric, start, s.ExecutorRunTime, labels...)
+}
+
+// add appends a time series to the metric, start being its start timestamp
+// if the metric is cumulative. The label values are in the order of the keys
+// of the metric.
+func (b *metricsBuilder) add(def *metricDef, start *timestamppb.Timestamp, value float64, labelValues ...string) {
+	metric, ok := b.byDef[def]
+	if !ok {
+		labelKeys := make([]*metricspb.LabelKey, 0, len(def.labelKeys))
+		for _, key := range def.labelKeys {
+			labelKeys = append(labelKeys, &metricspb.LabelKey{Key: key})
+		}
+		metric = &metricspb.Metric{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:        def.name,
+				Description: def.desc,
+				Unit:        def.unit,
+				Type:        def.mdType,
+				LabelKeys:   labelKeys,
+			},
+		}
+		b.byDef[def] = metric
+		b.metrics = append(b.metrics, metric)
+	}
+
+	values := make([]*metricspb.LabelValue, 0, len(labelValues))
+	for _, v := range labelValues {
+		values = append(values, &metricspb.LabelValue{Value: v, HasValue: true})
+	}
+	timeseries := &metricspb.TimeSeries{
+		LabelValues: values,
+		Points: []*metricspb.Point{{
+			Timestamp: b.now,
+			Value:     &metricspb.Point_Int64Value{Int64Value: int64(value)},
+		}},
+	}
+	if def.mdType == metricspb.MetricDescriptor_CUMULATIVE_INT64 {
+		timeseries.StartTimestamp = start
+	}
+	metric.Timeseries = append(metric.Timeseries, timeseries)
+}
+
+// applicationStart returns the start time of the current attempt of the
+// application, the attempts being listed from the most recent one.
+func applicationStart(app *application) *timestamppb.Timestamp {
+	if len(app.Attempts) == 0 || app.Attempts[0].StartTimeEpoch == 0 {
+		return nil
+	}
+	return timestamppb.New(time.Unix(0, app.Attempts[0].StartTimeEpoch*int64(time.Millisecond)))
+}
@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "spark"
+	transport  = "http"
+)
+
+var _ component.MetricsReceiver = (*sparkReceiver)(nil)
+
+// sparkReceiver implements the component.MetricsReceiver for Spark.
+type sparkReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	client       client
+	cancel       context.CancelFunc
+}
+
+// newSparkReceiver creates the Spark receiver with the given parameters.
+func newSparkReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	c, err := newRESTClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &sparkReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		client:       c,
+	}
+	return r, nil
+}
+
+// Start begins collecting the metrics of the Spark applications.
+func (r *sparkReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect Spark metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the Spark receiver.
+func (r *sparkReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+func (r *sparkReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	md, err := r.buildMetricsData(ctx)
+	if err != nil {
+		obsreport.EndMetricsReceiveOp(ctx, dataFormat, 0, 0, err)
+		return err
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+// buildMetricsData collects the metrics of the running applications. It
+// fails if the applications cannot be listed, the failures to query the
+// executors, jobs or stages of an application are logged and their metrics
+// omitted.
+func (r *sparkReceiver) buildMetricsData(ctx context.Context) (consumerdata.MetricsData, error) {
+	apps, err := r.client.applications(ctx)
+	if err != nil {
+		return consumerdata.MetricsData{}, err
+	}
+
+	b := newMetricsBuilder(time.Now())
+	var errs []error
+	for i := range apps {
+		app := &apps[i]
+
+		if executors, err := r.client.executors(ctx, app.ID); err != nil {
+			errs = append(errs, fmt.Errorf("executors of application %q: %w", app.ID, err))
+		} else {
+			for j := range executors {
+				b.addExecutor(app, &executors[j])
+			}
+		}
+
+		if jobs, err := r.client.runningJobs(ctx, app.ID); err != nil {
+			errs = append(errs, fmt.Errorf("jobs of application %q: %w", app.ID, err))
+		} else {
+			for j := range jobs {
+				b.addJob(app, &jobs[j])
+			}
+		}
+
+		if stages, err := r.client.activeStages(ctx, app.ID); err != nil {
+			errs = append(errs, fmt.Errorf("stages of application %q: %w", app.ID, err))
+		} else {
+			for j := range stages {
+				b.addStage(app, &stages[j])
+			}
+		}
+	}
+	if len(errs) > 0 {
+		r.logger.Warn("Failed to collect some Spark metrics", zap.Errors("errors", errs))
+	}
+
+	return consumerdata.MetricsData{Metrics: b.metrics}, nil
+}
@@ -0,0 +1,159 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparkreceiver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t)
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newSparkReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*sparkReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	metrics := make(map[string]int)
+	for _, metric := range ocmds[0].Metrics {
+		metrics[metric.MetricDescriptor.Name] = len(metric.Timeseries)
+	}
+	assert.Equal(t, map[string]int{
+		"spark.executor.memory.usage":   2,
+		"spark.executor.memory.max":     2,
+		"spark.executor.disk.usage":     2,
+		"spark.executor.tasks.active":   2,
+		"spark.executor.tasks.count":    4,
+		"spark.executor.gc.time":        2,
+		"spark.job.tasks.active":        1,
+		"spark.job.tasks.count":         2,
+		"spark.job.stages.active":       1,
+		"spark.stage.tasks.active":      1,
+		"spark.stage.tasks.count":       2,
+		"spark.stage.io":                2,
+		"spark.stage.shuffle.io":        2,
+		"spark.stage.executor.run_time": 1,
+	}, metrics)
+
+	for _, metric := range ocmds[0].Metrics {
+		switch metric.MetricDescriptor.Name {
+		case "spark.executor.tasks.count":
+			require.Len(t, metric.MetricDescriptor.LabelKeys, 4)
+			ts := metric.Timeseries[2]
+			assert.Equal(t, map[string]string{
+				applicationIDLabel:   testApplicationID,
+				applicationNameLabel: "WordCount",
+				executorIDLabel:      "1",
+				stateLabel:           stateCompleted,
+			}, labels(metric, ts))
+			assert.Equal(t, int64(1600171200), ts.StartTimestamp.Seconds)
+			assert.Equal(t, int64(42), ts.Points[0].GetInt64Value())
+		case "spark.job.stages.active":
+			ts := metric.Timeseries[0]
+			assert.Equal(t, "3", labels(metric, ts)[jobIDLabel])
+			assert.Nil(t, ts.StartTimestamp)
+			assert.Equal(t, int64(1), ts.Points[0].GetInt64Value())
+		case "spark.stage.shuffle.io":
+			ts := metric.Timeseries[0]
+			assert.Equal(t, map[string]string{
+				applicationIDLabel:   testApplicationID,
+				applicationNameLabel: "WordCount",
+				stageIDLabel:         "7",
+				stageAttemptIDLabel:  "0",
+				stageNameLabel:       "collect at WordCount.scala:42",
+				directionLabel:       directionRead,
+			}, labels(metric, ts))
+			assert.Equal(t, int64(1048576), ts.Points[0].GetInt64Value())
+		}
+	}
+}
+
+// labels returns the labels of a time series of the metric by key.
+func labels(metric *metricspb.Metric, ts *metricspb.TimeSeries) map[string]string {
+	labels := make(map[string]string, len(ts.LabelValues))
+	for i, key := range metric.MetricDescriptor.LabelKeys {
+		labels[key.Key] = ts.LabelValues[i].Value
+	}
+	return labels
+}
+
+func TestReceiverCollectPartialError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(applicationsPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": "app-1", "name": "Unreachable"}]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newSparkReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*sparkReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+	require.Len(t, sink.AllMetrics(), 1)
+	assert.Equal(t, 0, sink.AllMetrics()[0].MetricCount())
+}
+
+func TestReceiverCollectError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = server.URL
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newSparkReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*sparkReceiver)
+
+	assert.EqualError(t, r.collect(context.Background()), "request to /api/v1/applications failed: 404 Not Found")
+	assert.Len(t, sink.AllMetrics(), 0)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = startServer(t)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newSparkReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
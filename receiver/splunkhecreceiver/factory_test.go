@@ -48,6 +48,20 @@ func TestCreateReceiver(t *testing.T) {
 	assert.Nil(t, tReceiver)
 }
 
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:1" // Endpoint is required, not going to be used here.
+
+	lReceiver, err := createLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, exportertest.NewNopLogsExporter())
+	assert.NoError(t, err)
+	assert.NotNil(t, lReceiver)
+
+	cfg.Endpoint = "localhost:abr"
+	lReceiver, err = createLogsReceiver(context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()}, cfg, exportertest.NewNopLogsExporter())
+	assert.Error(t, err)
+	assert.Nil(t, lReceiver)
+}
+
 func TestFactoryType(t *testing.T) {
 	assert.Equal(t, configmodels.Type("splunk_hec"), NewFactory().Type())
 }
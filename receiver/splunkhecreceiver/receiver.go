@@ -0,0 +1,307 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+const (
+	defaultServerTimeout = 20 * time.Second
+
+	// The endpoints of the HEC events, the first one being the legacy path
+	// still used by many clients.
+	eventPath       = "/services/collector"
+	eventPathSuffix = "/services/collector/event"
+	// rawPath is the endpoint of the raw data, each line being an event.
+	rawPath = "/services/collector/raw"
+	// maxLineSize is the maximum size of a line sent to the raw endpoint.
+	maxLineSize = 1024 * 1024
+
+	responseOK                = `{"text":"Success","code":0}`
+	responseInvalidMethod     = `{"text":"Only \"POST\" method is supported","code":6}`
+	responseInvalidEncoding   = `{"text":"\"Content-Encoding\" must be \"gzip\" or empty","code":6}`
+	responseErrGzipReader     = `{"text":"Error on gzip body","code":6}`
+	responseErrUnmarshalBody  = `{"text":"Invalid data format","code":6}`
+	responseErrEventRequired  = `{"text":"Event field is required","code":12}`
+	responseErrEventBlank     = `{"text":"Event field cannot be blank","code":13}`
+	responseErrNextConsumer   = `{"text":"Internal Server Error","code":8}`
+	responseNoData            = `{"text":"No data","code":5}`
+	gzipEncoding              = "gzip"
+	httpContentEncodingHeader = "Content-Encoding"
+	httpAuthorizationHeader   = "Authorization"
+	queryHost                 = "host"
+	querySource               = "source"
+	querySourcetype           = "sourcetype"
+	queryIndex                = "index"
+)
+
+var (
+	errNilNextConsumer = errors.New("nil nextConsumer")
+	errEmptyEndpoint   = errors.New("empty endpoint")
+)
+
+// splunkReceiver implements the component.LogsReceiver for the Splunk HEC
+// event and raw endpoints.
+type splunkReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.LogsConsumer
+	server       *http.Server
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.LogsReceiver = (*splunkReceiver)(nil)
+
+// New creates the Splunk HEC receiver with the given configuration.
+func New(
+	logger *zap.Logger,
+	config Config,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	if config.Endpoint == "" {
+		return nil, errEmptyEndpoint
+	}
+
+	r := &splunkReceiver{
+		logger:       logger,
+		config:       &config,
+		nextConsumer: nextConsumer,
+	}
+
+	return r, nil
+}
+
+// Start tells the receiver to start its processing.
+// By convention the consumer of the received data is set when the receiver
+// instance is created.
+func (r *splunkReceiver) Start(_ context.Context, host component.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		err = nil
+
+		var ln net.Listener
+		// set up the listener
+		ln, err = r.config.HTTPServerSettings.ToListener()
+		if err != nil {
+			err = fmt.Errorf("failed to bind to address %s: %w", r.config.Endpoint, err)
+			return
+		}
+
+		mx := http.NewServeMux()
+		mx.HandleFunc(eventPath, r.handleEvents)
+		mx.HandleFunc(eventPathSuffix, r.handleEvents)
+		mx.HandleFunc(rawPath, r.handleRaw)
+
+		r.server = r.config.HTTPServerSettings.ToServer(mx)
+		r.server.ReadHeaderTimeout = defaultServerTimeout
+		r.server.WriteTimeout = defaultServerTimeout
+
+		go func() {
+			if errHTTP := r.server.Serve(ln); errHTTP != http.ErrServerClosed {
+				host.ReportFatalError(errHTTP)
+			}
+		}()
+	})
+
+	return err
+}
+
+// Shutdown tells the receiver that should stop reception,
+// giving it a chance to perform any necessary clean-up.
+func (r *splunkReceiver) Shutdown(context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = r.server.Close()
+	})
+	return err
+}
+
+// handleEvents handles the HEC events, a stream of JSON objects optionally
+// separated by whitespace.
+func (r *splunkReceiver) handleEvents(resp http.ResponseWriter, req *http.Request) {
+	body, ok := r.openBody(resp, req)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	var events []*splunkEvent
+	for dec.More() {
+		var event splunkEvent
+		if err := dec.Decode(&event); err != nil {
+			r.failRequest(resp, http.StatusBadRequest, responseErrUnmarshalBody, err)
+			return
+		}
+		if event.Event == nil {
+			r.failRequest(resp, http.StatusBadRequest, responseErrEventRequired, nil)
+			return
+		}
+		if s, isString := event.Event.(string); isString && s == "" {
+			r.failRequest(resp, http.StatusBadRequest, responseErrEventBlank, nil)
+			return
+		}
+		events = append(events, &event)
+	}
+	if len(events) == 0 {
+		r.failRequest(resp, http.StatusBadRequest, responseNoData, nil)
+		return
+	}
+
+	r.consumeLogs(resp, req, eventsToLogs(events, r.accessToken(req), time.Now()))
+}
+
+// handleRaw handles the raw data, each non-empty line being an event whose
+// metadata is given by the query parameters.
+func (r *splunkReceiver) handleRaw(resp http.ResponseWriter, req *http.Request) {
+	body, ok := r.openBody(resp, req)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	var lines []string
+	sc := bufio.NewScanner(body)
+	sc.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		r.failRequest(resp, http.StatusBadRequest, responseErrUnmarshalBody, err)
+		return
+	}
+	if len(lines) == 0 {
+		r.failRequest(resp, http.StatusBadRequest, responseNoData, nil)
+		return
+	}
+
+	query := req.URL.Query()
+	res := eventResource{
+		host:       query.Get(queryHost),
+		source:     query.Get(querySource),
+		sourcetype: query.Get(querySourcetype),
+		index:      query.Get(queryIndex),
+	}
+	r.consumeLogs(resp, req, rawToLogs(lines, res, r.accessToken(req), time.Now()))
+}
+
+// openBody checks the method and the encoding of the request and returns its
+// decompressed body.
+func (r *splunkReceiver) openBody(resp http.ResponseWriter, req *http.Request) (io.ReadCloser, bool) {
+	if req.Method != http.MethodPost {
+		r.failRequest(resp, http.StatusBadRequest, responseInvalidMethod, nil)
+		return nil, false
+	}
+
+	encoding := req.Header.Get(httpContentEncodingHeader)
+	switch encoding {
+	case "":
+		return req.Body, true
+	case gzipEncoding:
+		gz, err := gzip.NewReader(req.Body)
+		if err != nil {
+			r.failRequest(resp, http.StatusBadRequest, responseErrGzipReader, err)
+			return nil, false
+		}
+		return gz, true
+	default:
+		r.failRequest(resp, http.StatusUnsupportedMediaType, responseInvalidEncoding, nil)
+		return nil, false
+	}
+}
+
+func (r *splunkReceiver) consumeLogs(resp http.ResponseWriter, req *http.Request, ld pdata.Logs) {
+	if err := r.nextConsumer.ConsumeLogs(req.Context(), ld); err != nil {
+		r.failRequest(resp, http.StatusInternalServerError, responseErrNextConsumer, err)
+		return
+	}
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	resp.Write([]byte(responseOK))
+}
+
+// accessToken returns the HEC token of the request if the token is passed
+// through, an empty string otherwise.
+func (r *splunkReceiver) accessToken(req *http.Request) string {
+	if !r.config.AccessTokenPassthrough {
+		return ""
+	}
+	auth := req.Header.Get(httpAuthorizationHeader)
+	if !strings.HasPrefix(auth, splunk.HECTokenHeader+" ") {
+		return ""
+	}
+	return strings.TrimSpace(auth[len(splunk.HECTokenHeader)+1:])
+}
+
+func (r *splunkReceiver) failRequest(
+	resp http.ResponseWriter,
+	httpStatusCode int,
+	jsonResponse string,
+	err error,
+) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(httpStatusCode)
+	if _, writeErr := resp.Write([]byte(jsonResponse)); writeErr != nil {
+		r.logger.Warn(
+			"Error writing HTTP response message",
+			zap.Error(writeErr),
+			zap.String("receiver", r.config.Name()))
+	}
+
+	r.logger.Debug(
+		"Splunk HEC receiver request failed",
+		zap.Int("http_status_code", httpStatusCode),
+		zap.String("msg", jsonResponse),
+		zap.Error(err), // It handles nil error
+		zap.String("receiver", r.config.Name()))
+}
@@ -0,0 +1,292 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func newTestReceiver(t *testing.T, sink *exportertest.SinkLogsExporter, accessTokenPassthrough bool) *splunkReceiver {
+	cfg := createDefaultConfig().(*Config)
+	cfg.AccessTokenPassthrough = accessTokenPassthrough
+	r, err := New(zap.NewNop(), *cfg, sink)
+	require.NoError(t, err)
+	return r.(*splunkReceiver)
+}
+
+func TestNew(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+
+	_, err := New(zap.NewNop(), *cfg, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+
+	cfg.Endpoint = ""
+	_, err = New(zap.NewNop(), *cfg, exportertest.NewNopLogsExporter())
+	assert.Equal(t, errEmptyEndpoint, err)
+}
+
+func TestEventsFieldsPassedThrough(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	r := newTestReceiver(t, sink, true)
+
+	body := `{"time":1600000000.5,"host":"web-1","sourcetype":"access","event":"GET /","fields":{"region":"us-east","status":200,"tags":["a","b"]}}
+{"host":"web-1","sourcetype":"access","event":{"path":"/health"}}`
+	req := httptest.NewRequest(http.MethodPost, "/services/collector", strings.NewReader(body))
+	req.Header.Set("Authorization", "Splunk abc-123")
+	resp := httptest.NewRecorder()
+	r.handleEvents(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, responseOK, resp.Body.String())
+	got := sink.AllLogs()
+	require.Len(t, got, 1)
+	require.Equal(t, 1, got[0].ResourceLogs().Len(), "the events of the same resource should share their ResourceLogs")
+	rl := got[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		hostnameLabel:                 "web-1",
+		sourcetypeLabel:               "access",
+		"com.splunk.hec.access_token": "abc-123",
+	}, attributesToMap(rl.Resource().Attributes()))
+
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "GET /", logs.At(0).Body().StringVal())
+	assert.Equal(t, pdata.TimestampUnixNano(1600000000500000000), logs.At(0).Timestamp())
+	assert.Equal(t, map[string]interface{}{
+		"region": "us-east",
+		"status": int64(200),
+		"tags":   []interface{}{"a", "b"},
+	}, attributesToMap(logs.At(0).Attributes()))
+	assert.Equal(t, map[string]interface{}{"path": "/health"}, attributesToMap(logs.At(1).Body().MapVal()))
+	assert.NotZero(t, logs.At(1).Timestamp())
+}
+
+func TestEventsEndpointGzip(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	r := newTestReceiver(t, sink, false)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(`{"event":"compressed"}`))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/services/collector/event", &buf)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("Authorization", "Splunk abc-123")
+	resp := httptest.NewRecorder()
+	r.handleEvents(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	require.Equal(t, 1, sink.LogRecordsCount())
+	rl := sink.AllLogs()[0].ResourceLogs().At(0)
+	assert.Equal(t, 0, rl.Resource().Attributes().Len(), "the token should not be passed through")
+	assert.Equal(t, "compressed", rl.InstrumentationLibraryLogs().At(0).Logs().At(0).Body().StringVal())
+}
+
+func TestEventsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		encoding string
+		body     string
+		wantCode int
+		wantResp string
+	}{
+		{
+			name:     "invalid_method",
+			method:   http.MethodGet,
+			wantCode: http.StatusBadRequest,
+			wantResp: responseInvalidMethod,
+		},
+		{
+			name:     "invalid_encoding",
+			method:   http.MethodPost,
+			encoding: "deflate",
+			wantCode: http.StatusUnsupportedMediaType,
+			wantResp: responseInvalidEncoding,
+		},
+		{
+			name:     "invalid_gzip",
+			method:   http.MethodPost,
+			encoding: "gzip",
+			body:     "not gzip",
+			wantCode: http.StatusBadRequest,
+			wantResp: responseErrGzipReader,
+		},
+		{
+			name:     "invalid_json",
+			method:   http.MethodPost,
+			body:     `{"event":`,
+			wantCode: http.StatusBadRequest,
+			wantResp: responseErrUnmarshalBody,
+		},
+		{
+			name:     "missing_event",
+			method:   http.MethodPost,
+			body:     `{"host":"web-1"}`,
+			wantCode: http.StatusBadRequest,
+			wantResp: responseErrEventRequired,
+		},
+		{
+			name:     "blank_event",
+			method:   http.MethodPost,
+			body:     `{"event":""}`,
+			wantCode: http.StatusBadRequest,
+			wantResp: responseErrEventBlank,
+		},
+		{
+			name:     "no_data",
+			method:   http.MethodPost,
+			wantCode: http.StatusBadRequest,
+			wantResp: responseNoData,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkLogsExporter)
+			r := newTestReceiver(t, sink, false)
+
+			req := httptest.NewRequest(tt.method, "/services/collector", strings.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			resp := httptest.NewRecorder()
+			r.handleEvents(resp, req)
+
+			assert.Equal(t, tt.wantCode, resp.Code)
+			assert.JSONEq(t, tt.wantResp, resp.Body.String())
+			assert.Equal(t, 0, sink.LogRecordsCount())
+		})
+	}
+}
+
+func TestRawEndpoint(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	r := newTestReceiver(t, sink, false)
+
+	body := "first line\r\n\nsecond line\n"
+	req := httptest.NewRequest(http.MethodPost, "/services/collector/raw?host=web-1&source=app.log&sourcetype=syslog&index=main&channel=ch", strings.NewReader(body))
+	resp := httptest.NewRecorder()
+	r.handleRaw(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	got := sink.AllLogs()
+	require.Len(t, got, 1)
+	rl := got[0].ResourceLogs().At(0)
+	assert.Equal(t, map[string]interface{}{
+		hostnameLabel:   "web-1",
+		sourceLabel:     "app.log",
+		sourcetypeLabel: "syslog",
+		indexLabel:      "main",
+	}, attributesToMap(rl.Resource().Attributes()))
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+	require.Equal(t, 2, logs.Len())
+	assert.Equal(t, "first line", logs.At(0).Body().StringVal())
+	assert.Equal(t, "second line", logs.At(1).Body().StringVal())
+}
+
+func TestRawEndpointNoData(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	r := newTestReceiver(t, sink, false)
+
+	req := httptest.NewRequest(http.MethodPost, "/services/collector/raw", strings.NewReader("\n \n"))
+	resp := httptest.NewRecorder()
+	r.handleRaw(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, responseNoData, resp.Body.String())
+}
+
+func TestNextConsumerError(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	sink.SetConsumeLogError(errors.New("consumer error"))
+	r := newTestReceiver(t, sink, false)
+
+	req := httptest.NewRequest(http.MethodPost, "/services/collector/raw", strings.NewReader("line"))
+	resp := httptest.NewRecorder()
+	r.handleRaw(resp, req)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.JSONEq(t, responseErrNextConsumer, resp.Body.String())
+}
+
+func TestStartShutdown(t *testing.T) {
+	sink := new(exportertest.SinkLogsExporter)
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	rcv, err := New(zap.NewNop(), *cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, rcv.Shutdown(context.Background()))
+	}()
+
+	for _, path := range []string{"/services/collector", "/services/collector/event", "/services/collector/raw"} {
+		resp, err := http.Post("http://"+cfg.Endpoint+path, "application/json", strings.NewReader(`{"event":"hello"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+	assert.Equal(t, 3, sink.LogRecordsCount())
+}
+
+func attributesToMap(attrs pdata.AttributeMap) map[string]interface{} {
+	m := make(map[string]interface{}, attrs.Len())
+	attrs.ForEach(func(k string, v pdata.AttributeValue) {
+		m[k] = attributeValueToInterface(v)
+	})
+	return m
+}
+
+func attributeValueToInterface(v pdata.AttributeValue) interface{} {
+	switch v.Type() {
+	case pdata.AttributeValueSTRING:
+		return v.StringVal()
+	case pdata.AttributeValueINT:
+		return v.IntVal()
+	case pdata.AttributeValueDOUBLE:
+		return v.DoubleVal()
+	case pdata.AttributeValueBOOL:
+		return v.BoolVal()
+	case pdata.AttributeValueMAP:
+		return attributesToMap(v.MapVal())
+	case pdata.AttributeValueARRAY:
+		arr := v.ArrayVal()
+		values := make([]interface{}, arr.Len())
+		for i := 0; i < arr.Len(); i++ {
+			values[i] = attributeValueToInterface(arr.At(i))
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
+)
+
+const (
+	// hostnameLabel is the resource attribute of the host field, the same one
+	// the Splunk HEC exporter reads the host from.
+	hostnameLabel = "host.hostname"
+	// sourceLabel is the resource attribute of the source field.
+	sourceLabel = "com.splunk.source"
+	// sourcetypeLabel is the resource attribute of the sourcetype field.
+	sourcetypeLabel = "com.splunk.sourcetype"
+	// indexLabel is the resource attribute of the index field.
+	indexLabel = "com.splunk.index"
+)
+
+// splunkEvent is an event in the Splunk HEC JSON format, see
+// https://docs.splunk.com/Documentation/Splunk/8.0.5/Data/FormateventsforHTTPEventCollector.
+type splunkEvent struct {
+	Time       interface{}            `json:"time,omitempty"`       // epoch time, as a number or a string
+	Host       string                 `json:"host,omitempty"`       // hostname
+	Source     string                 `json:"source,omitempty"`     // optional description of the source of the event
+	SourceType string                 `json:"sourcetype,omitempty"` // optional name of a Splunk parsing configuration
+	Index      string                 `json:"index,omitempty"`      // optional name of the Splunk index to store the event in
+	Event      interface{}            `json:"event"`                // payload of the event
+	Fields     map[string]interface{} `json:"fields,omitempty"`     // indexed fields
+}
+
+// eventResource identifies the resource of the events, the events sharing
+// the same metadata being reported under the same ResourceLogs.
+type eventResource struct {
+	host       string
+	source     string
+	sourcetype string
+	index      string
+}
+
+// logsBuilder groups the log records by resource.
+type logsBuilder struct {
+	logs        pdata.Logs
+	accessToken string
+	resources   map[eventResource]pdata.LogSlice
+}
+
+func newLogsBuilder(accessToken string) *logsBuilder {
+	return &logsBuilder{
+		logs:        pdata.NewLogs(),
+		accessToken: accessToken,
+		resources:   map[eventResource]pdata.LogSlice{},
+	}
+}
+
+// appendRecord returns a new log record of the given resource.
+func (b *logsBuilder) appendRecord(res eventResource) pdata.LogRecord {
+	logs, ok := b.resources[res]
+	if !ok {
+		rls := b.logs.ResourceLogs()
+		rls.Resize(rls.Len() + 1)
+		rl := rls.At(rls.Len() - 1)
+		rl.Resource().InitEmpty()
+		fillResource(rl.Resource().Attributes(), res, b.accessToken)
+		rl.InstrumentationLibraryLogs().Resize(1)
+		logs = rl.InstrumentationLibraryLogs().At(0).Logs()
+		b.resources[res] = logs
+	}
+	logs.Resize(logs.Len() + 1)
+	return logs.At(logs.Len() - 1)
+}
+
+func fillResource(attrs pdata.AttributeMap, res eventResource, accessToken string) {
+	if res.host != "" {
+		attrs.InsertString(hostnameLabel, res.host)
+	}
+	if res.source != "" {
+		attrs.InsertString(sourceLabel, res.source)
+	}
+	if res.sourcetype != "" {
+		attrs.InsertString(sourcetypeLabel, res.sourcetype)
+	}
+	if res.index != "" {
+		attrs.InsertString(indexLabel, res.index)
+	}
+	if accessToken != "" {
+		attrs.InsertString(splunk.HECTokenLabel, accessToken)
+	}
+}
+
+// eventsToLogs converts the HEC events to logs, the fields of each event
+// being kept as attributes of its log record. The events without a time are
+// reported at the time they were received.
+func eventsToLogs(events []*splunkEvent, accessToken string, received time.Time) pdata.Logs {
+	b := newLogsBuilder(accessToken)
+	for _, event := range events {
+		lr := b.appendRecord(eventResource{
+			host:       event.Host,
+			source:     event.Source,
+			sourcetype: event.SourceType,
+			index:      event.Index,
+		})
+		lr.SetTimestamp(eventTimestamp(event.Time, received))
+		if event.Event != nil {
+			toAttributeValue(event.Event).CopyTo(lr.Body())
+		}
+		attrs := lr.Attributes()
+		for k, v := range event.Fields {
+			attrs.Insert(k, toAttributeValue(v))
+		}
+	}
+	return b.logs
+}
+
+// rawToLogs converts the lines sent to the raw endpoint to logs, one log
+// record per line. The metadata of the lines is given by the query parameters
+// of the request.
+func rawToLogs(lines []string, res eventResource, accessToken string, received time.Time) pdata.Logs {
+	b := newLogsBuilder(accessToken)
+	for _, line := range lines {
+		lr := b.appendRecord(res)
+		lr.SetTimestamp(pdata.TimestampUnixNano(received.UnixNano()))
+		lr.Body().InitEmpty()
+		lr.Body().SetStringVal(line)
+	}
+	return b.logs
+}
+
+// eventTimestamp parses the time of an event, in seconds since the epoch with
+// an optional fractional part.
+func eventTimestamp(t interface{}, received time.Time) pdata.TimestampUnixNano {
+	var secs float64
+	switch v := t.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return pdata.TimestampUnixNano(received.UnixNano())
+		}
+		secs = f
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return pdata.TimestampUnixNano(received.UnixNano())
+		}
+		secs = f
+	default:
+		return pdata.TimestampUnixNano(received.UnixNano())
+	}
+	whole, frac := math.Modf(secs)
+	// Round to the millisecond, the precision of the HEC timestamps, to not
+	// report the float64 rounding errors.
+	return pdata.TimestampUnixNano(time.Unix(int64(whole), int64(math.Round(frac*1e3))*int64(time.Millisecond)).UnixNano())
+}
+
+// toAttributeValue converts a value decoded from JSON, with numbers decoded
+// as json.Number, to an attribute value.
+func toAttributeValue(v interface{}) pdata.AttributeValue {
+	switch val := v.(type) {
+	case string:
+		return pdata.NewAttributeValueString(val)
+	case bool:
+		return pdata.NewAttributeValueBool(val)
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return pdata.NewAttributeValueInt(i)
+		}
+		f, err := val.Float64()
+		if err != nil {
+			return pdata.NewAttributeValueString(val.String())
+		}
+		return pdata.NewAttributeValueDouble(f)
+	case map[string]interface{}:
+		av := pdata.NewAttributeValueMap()
+		m := av.MapVal()
+		for k, e := range val {
+			m.Insert(k, toAttributeValue(e))
+		}
+		return av
+	case []interface{}:
+		av := pdata.NewAttributeValueArray()
+		arr := av.ArrayVal()
+		for _, e := range val {
+			arr.Append(toAttributeValue(e))
+		}
+		return av
+	default:
+		return pdata.NewAttributeValueNull()
+	}
+}
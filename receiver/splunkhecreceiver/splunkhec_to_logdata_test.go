@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunkhecreceiver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestEventsToLogsGroupsByResource(t *testing.T) {
+	events := []*splunkEvent{
+		{Host: "web-1", Index: "main", Event: "one"},
+		{Host: "web-2", Index: "main", Event: "two"},
+		{Host: "web-1", Index: "main", Event: "three"},
+	}
+	ld := eventsToLogs(events, "", time.Unix(0, 0))
+
+	assert.Equal(t, 3, ld.LogRecordCount())
+	assert.Equal(t, 2, ld.ResourceLogs().Len())
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+	assert.Equal(t, 2, logs.Len())
+	assert.Equal(t, "one", logs.At(0).Body().StringVal())
+	assert.Equal(t, "three", logs.At(1).Body().StringVal())
+}
+
+func TestEventTimestamp(t *testing.T) {
+	received := time.Unix(1700000000, 0)
+	tests := []struct {
+		name string
+		time interface{}
+		want pdata.TimestampUnixNano
+	}{
+		{name: "number", time: json.Number("1600000000.123"), want: 1600000000123000000},
+		{name: "integer", time: json.Number("1600000000"), want: 1600000000000000000},
+		{name: "string", time: "1600000000.5", want: 1600000000500000000},
+		{name: "missing", time: nil, want: pdata.TimestampUnixNano(received.UnixNano())},
+		{name: "invalid", time: "yesterday", want: pdata.TimestampUnixNano(received.UnixNano())},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventTimestamp(tt.time, received))
+		})
+	}
+}
+
+func TestToAttributeValue(t *testing.T) {
+	assert.Equal(t, pdata.NewAttributeValueString("v"), toAttributeValue("v"))
+	assert.Equal(t, pdata.NewAttributeValueBool(true), toAttributeValue(true))
+	assert.Equal(t, pdata.NewAttributeValueInt(42), toAttributeValue(json.Number("42")))
+	assert.Equal(t, pdata.NewAttributeValueDouble(4.2), toAttributeValue(json.Number("4.2")))
+	assert.Equal(t, pdata.AttributeValueNULL, toAttributeValue(nil).Type())
+	assert.Equal(t, map[string]interface{}{"k": []interface{}{"a", int64(1)}},
+		attributesToMap(toAttributeValue(map[string]interface{}{"k": []interface{}{"a", json.Number("1")}}).MapVal()))
+}
@@ -0,0 +1,107 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// stringMap holds the values of a row by column name.
+type stringMap map[string]string
+
+// dbClient runs queries against a database.
+type dbClient interface {
+	queryRows(ctx context.Context, query string) ([]stringMap, error)
+	close() error
+}
+
+// openClientFunc opens a client for the given driver and data source.
+type openClientFunc func(driver, dataSource string) (dbClient, error)
+
+type sqlClient struct {
+	db *sql.DB
+}
+
+var _ dbClient = (*sqlClient)(nil)
+
+func openSQLClient(driver, dataSource string) (dbClient, error) {
+	db, err := sql.Open(driver, dataSource)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlClient{db: db}, nil
+}
+
+// queryRows runs the query and returns its rows with all their values
+// converted to strings, NULL values being converted to empty strings.
+func (c *sqlClient) queryRows(ctx context.Context, query string) ([]stringMap, error) {
+	rows, err := c.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []stringMap
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err = rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(stringMap, len(columns))
+		for i, column := range columns {
+			row[column] = valueToString(values[i])
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+func (c *sqlClient) close() error {
+	return c.db.Close()
+}
+
+func valueToString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
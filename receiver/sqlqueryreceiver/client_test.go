@@ -0,0 +1,125 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a database/sql driver answering every query with the same
+// rows, or with err if set.
+type fakeDriver struct {
+	columns []string
+	rows    [][]driver.Value
+	err     error
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{driver: d}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+}
+
+var _ driver.QueryerContext = (*fakeConn)(nil)
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *fakeConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	if c.driver.err != nil {
+		return nil, c.driver.err
+	}
+	return &fakeRows{columns: c.driver.columns, rows: c.driver.rows}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+func TestSQLClientQueryRows(t *testing.T) {
+	ts := time.Date(2020, 9, 15, 10, 30, 0, 0, time.UTC)
+	sql.Register("fake-rows", &fakeDriver{
+		columns: []string{"name", "count", "ratio", "enabled", "updated", "comment"},
+		rows: [][]driver.Value{
+			{[]byte("a"), int64(1), 0.5, true, ts, nil},
+			{"b", int64(-2), 1.25, false, ts, "note"},
+		},
+	})
+
+	client, err := openSQLClient("fake-rows", "")
+	require.NoError(t, err)
+	defer client.close()
+
+	rows, err := client.queryRows(context.Background(), "select")
+	require.NoError(t, err)
+	assert.Equal(t, []stringMap{
+		{"name": "a", "count": "1", "ratio": "0.5", "enabled": "true", "updated": "2020-09-15T10:30:00Z", "comment": ""},
+		{"name": "b", "count": "-2", "ratio": "1.25", "enabled": "false", "updated": "2020-09-15T10:30:00Z", "comment": "note"},
+	}, rows)
+}
+
+func TestSQLClientQueryRowsError(t *testing.T) {
+	sql.Register("fake-error", &fakeDriver{err: errors.New("relation does not exist")})
+
+	client, err := openSQLClient("fake-error", "")
+	require.NoError(t, err)
+	defer client.close()
+
+	_, err = client.queryRows(context.Background(), "select")
+	assert.EqualError(t, err, "relation does not exist")
+}
+
+func TestOpenSQLClientUnknownDriver(t *testing.T) {
+	_, err := openSQLClient("unknown", "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the SQL query receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the queries are run.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// Driver is the name of the database driver, one of "postgres", "mysql"
+	// or "sqlserver".
+	Driver string `mapstructure:"driver"`
+
+	// DataSource is the driver specific connection string.
+	DataSource string `mapstructure:"datasource"`
+
+	// Queries is the list of queries to run.
+	Queries []Query `mapstructure:"queries"`
+}
+
+// Query defines a SQL query and how the rows it returns are converted.
+type Query struct {
+	// SQL is the query to run.
+	SQL string `mapstructure:"sql"`
+
+	// Metrics defines the metrics built from each row, they are only reported
+	// by metrics receivers.
+	Metrics []MetricConfig `mapstructure:"metrics"`
+
+	// Logs defines the log records built from each row, they are only
+	// reported by logs receivers.
+	Logs []LogsConfig `mapstructure:"logs"`
+}
+
+// MetricConfig defines a metric built from the rows of a query, each row
+// being a time series of the metric.
+type MetricConfig struct {
+	// MetricName is the name of the metric.
+	MetricName string `mapstructure:"metric_name"`
+
+	// ValueColumn is the column holding the value of the metric.
+	ValueColumn string `mapstructure:"value_column"`
+
+	// AttributeColumns are the columns whose values are used as labels of
+	// the metric, the column names are used as label keys.
+	AttributeColumns []string `mapstructure:"attribute_columns"`
+
+	// ValueType is the type of the value, either "int" or "double".
+	// Defaults to "int".
+	ValueType string `mapstructure:"value_type"`
+
+	// DataType is the type of the metric, either "gauge" or "sum". Sums are
+	// reported as cumulative metrics starting when the receiver started.
+	// Defaults to "gauge".
+	DataType string `mapstructure:"data_type"`
+
+	// Unit is the unit of the metric.
+	Unit string `mapstructure:"unit"`
+
+	// Description is the description of the metric.
+	Description string `mapstructure:"description"`
+}
+
+// LogsConfig defines the log records built from the rows of a query, each
+// row being a log record.
+type LogsConfig struct {
+	// BodyColumn is the column holding the body of the log records.
+	BodyColumn string `mapstructure:"body_column"`
+
+	// AttributeColumns are the columns whose values are used as attributes of
+	// the log records, the column names are used as attribute keys.
+	AttributeColumns []string `mapstructure:"attribute_columns"`
+}
@@ -0,0 +1,91 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 2)
+
+	r0 := cfg.Receivers["sqlquery"]
+	assert.Equal(t, factory.CreateDefaultConfig(), r0)
+
+	r1 := cfg.Receivers["sqlquery/custom"]
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "sqlquery/custom",
+		},
+		CollectionInterval: 30 * time.Second,
+		Driver:             "postgres",
+		DataSource:         "host=localhost port=5432 user=otel password=otel sslmode=disable",
+		Queries: []Query{
+			{
+				SQL: "select count(*) as count, status from orders group by status",
+				Metrics: []MetricConfig{
+					{
+						MetricName:       "orders.count",
+						ValueColumn:      "count",
+						AttributeColumns: []string{"status"},
+						Unit:             "1",
+						Description:      "Number of orders by status.",
+					},
+				},
+			},
+			{
+				SQL: "select sum(amount) as total from payments",
+				Metrics: []MetricConfig{
+					{
+						MetricName:  "payments.total",
+						ValueColumn: "total",
+						ValueType:   "double",
+						DataType:    "sum",
+					},
+				},
+			},
+			{
+				SQL: "select message, level from audit_events where created_at > now() - interval '30 seconds'",
+				Logs: []LogsConfig{
+					{
+						BodyColumn:       "message",
+						AttributeColumns: []string{"level"},
+					},
+				},
+			},
+		},
+	}, r1)
+}
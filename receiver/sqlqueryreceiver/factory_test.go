@@ -0,0 +1,146 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestCreateDefaultConfig(t *testing.T) {
+	cfg := createDefaultConfig()
+	assert.NotNil(t, cfg, "failed to create default config")
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+}
+
+func testConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Driver = "mysql"
+	cfg.DataSource = "user:password@tcp(localhost:3306)/shop"
+	cfg.Queries = []Query{
+		{
+			SQL:     "select count(*) as count from orders",
+			Metrics: []MetricConfig{{MetricName: "orders.count", ValueColumn: "count"}},
+		},
+	}
+	return cfg
+}
+
+func TestCreateReceivers(t *testing.T) {
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+
+	mr, err := createMetricsReceiver(context.Background(), params, testConfig(), exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.NotNil(t, mr)
+
+	mr, err = createMetricsReceiver(context.Background(), params, testConfig(), nil)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+	assert.Nil(t, mr)
+
+	lr, err := createLogsReceiver(context.Background(), params, testConfig(), exportertest.NewNopLogsExporter())
+	require.NoError(t, err)
+	require.NotNil(t, lr)
+
+	lr, err = createLogsReceiver(context.Background(), params, testConfig(), nil)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+	assert.Nil(t, lr)
+}
+
+func TestCreateReceiversInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:    "unsupported driver",
+			modify:  func(cfg *Config) { cfg.Driver = "oracle" },
+			wantErr: `unsupported driver "oracle" for receiver "sqlquery"`,
+		},
+		{
+			name:    "no datasource",
+			modify:  func(cfg *Config) { cfg.DataSource = "" },
+			wantErr: `no datasource configured for receiver "sqlquery"`,
+		},
+		{
+			name:    "no queries",
+			modify:  func(cfg *Config) { cfg.Queries = nil },
+			wantErr: `no queries configured for receiver "sqlquery"`,
+		},
+		{
+			name:    "non-positive collection interval",
+			modify:  func(cfg *Config) { cfg.CollectionInterval = 0 },
+			wantErr: `collection_interval must be positive for receiver "sqlquery"`,
+		},
+		{
+			name:    "no sql",
+			modify:  func(cfg *Config) { cfg.Queries[0].SQL = "" },
+			wantErr: `invalid query 0 of receiver "sqlquery": sql must be set`,
+		},
+		{
+			name:    "no metrics or logs",
+			modify:  func(cfg *Config) { cfg.Queries[0].Metrics = nil },
+			wantErr: `invalid query 0 of receiver "sqlquery": at least one of metrics or logs must be set`,
+		},
+		{
+			name:    "no metric name",
+			modify:  func(cfg *Config) { cfg.Queries[0].Metrics[0].MetricName = "" },
+			wantErr: `invalid query 0 of receiver "sqlquery": metric_name must be set`,
+		},
+		{
+			name:    "no value column",
+			modify:  func(cfg *Config) { cfg.Queries[0].Metrics[0].ValueColumn = "" },
+			wantErr: `invalid query 0 of receiver "sqlquery": metric "orders.count": value_column must be set`,
+		},
+		{
+			name:    "unsupported value type",
+			modify:  func(cfg *Config) { cfg.Queries[0].Metrics[0].ValueType = "string" },
+			wantErr: `invalid query 0 of receiver "sqlquery": metric "orders.count": unsupported value_type "string"`,
+		},
+		{
+			name:    "unsupported data type",
+			modify:  func(cfg *Config) { cfg.Queries[0].Metrics[0].DataType = "histogram" },
+			wantErr: `invalid query 0 of receiver "sqlquery": metric "orders.count": unsupported data_type "histogram"`,
+		},
+		{
+			name:    "no body column",
+			modify:  func(cfg *Config) { cfg.Queries[0].Logs = []LogsConfig{{}} },
+			wantErr: `invalid query 0 of receiver "sqlquery": logs: body_column must be set`,
+		},
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig()
+			tt.modify(cfg)
+
+			mr, err := createMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, mr)
+
+			lr, err := createLogsReceiver(context.Background(), params, cfg, exportertest.NewNopLogsExporter())
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, lr)
+		})
+	}
+}
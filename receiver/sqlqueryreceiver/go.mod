@@ -0,0 +1,14 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sqlqueryreceiver
+
+go 1.14
+
+require (
+	github.com/census-instrumentation/opencensus-proto v0.3.0
+	github.com/denisenkom/go-mssqldb v0.0.0-20200910202707-1e08a3fab204
+	github.com/go-sql-driver/mysql v1.5.0
+	github.com/lib/pq v1.8.0
+	github.com/stretchr/testify v1.6.1
+	go.opentelemetry.io/collector v0.10.1-0.20200915193938-b3a5ceaefa96
+	go.uber.org/zap v1.16.0
+	google.golang.org/protobuf v1.25.0
+)
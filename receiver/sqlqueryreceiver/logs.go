@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+// appendLogRecords converts the rows of a query into log records appended to
+// logs, each row being a log record. Rows which can't be converted are
+// skipped and reported in the returned errors.
+func appendLogRecords(logs pdata.LogSlice, config LogsConfig, rows []stringMap, now time.Time) []error {
+	var errs []error
+	valid := make([]stringMap, 0, len(rows))
+	for _, row := range rows {
+		if err := checkColumns(row, config.BodyColumn, config.AttributeColumns...); err != nil {
+			errs = append(errs, fmt.Errorf("logs: %w", err))
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	offset := logs.Len()
+	logs.Resize(offset + len(valid))
+	for i, row := range valid {
+		lr := logs.At(offset + i)
+		lr.SetTimestamp(pdata.TimestampUnixNano(now.UnixNano()))
+		lr.Body().InitEmpty()
+		lr.Body().SetStringVal(row[config.BodyColumn])
+
+		attrs := lr.Attributes()
+		for _, column := range config.AttributeColumns {
+			attrs.InsertString(column, row[column])
+		}
+	}
+	return errs
+}
+
+// checkColumns reports an error if any of the columns is missing from row.
+func checkColumns(row stringMap, column string, columns ...string) error {
+	for _, c := range append([]string{column}, columns...) {
+		if _, ok := row[c]; !ok {
+			return fmt.Errorf("column %q not found in the query results", c)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+func TestAppendLogRecords(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	rows := []stringMap{
+		{"message": "user created", "level": "info"},
+		{"message": "login failed"},
+		{"message": "user deleted", "level": "warn"},
+	}
+
+	ld := pdata.NewLogs()
+	ld.ResourceLogs().Resize(1)
+	ld.ResourceLogs().At(0).InstrumentationLibraryLogs().Resize(1)
+	logs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs()
+
+	errs := appendLogRecords(logs, LogsConfig{
+		BodyColumn:       "message",
+		AttributeColumns: []string{"level"},
+	}, rows, now)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `logs: column "level" not found in the query results`)
+
+	errs = appendLogRecords(logs, LogsConfig{BodyColumn: "message"}, rows[1:2], now)
+	assert.Empty(t, errs)
+
+	require.Equal(t, 3, logs.Len())
+	bodies := make([]string, 0, logs.Len())
+	for i := 0; i < logs.Len(); i++ {
+		lr := logs.At(i)
+		assert.Equal(t, pdata.TimestampUnixNano(now.UnixNano()), lr.Timestamp())
+		bodies = append(bodies, lr.Body().StringVal())
+	}
+	assert.Equal(t, []string{"user created", "user deleted", "login failed"}, bodies)
+
+	level, ok := logs.At(1).Attributes().Get("level")
+	require.True(t, ok)
+	assert.Equal(t, "warn", level.StringVal())
+	assert.Equal(t, 0, logs.At(2).Attributes().Len())
+}
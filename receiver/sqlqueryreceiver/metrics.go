@@ -0,0 +1,107 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// buildMetric converts the rows of a query into a metric, each row being a
+// time series. Rows which can't be converted are skipped and reported in the
+// returned errors.
+func buildMetric(config MetricConfig, rows []stringMap, start, now time.Time) (*metricspb.Metric, []error) {
+	isDouble := config.ValueType == valueTypeDouble
+	isSum := config.DataType == dataTypeSum
+
+	var metricType metricspb.MetricDescriptor_Type
+	switch {
+	case isSum && isDouble:
+		metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
+	case isSum:
+		metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+	case isDouble:
+		metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+	default:
+		metricType = metricspb.MetricDescriptor_GAUGE_INT64
+	}
+
+	labelKeys := make([]*metricspb.LabelKey, 0, len(config.AttributeColumns))
+	for _, column := range config.AttributeColumns {
+		labelKeys = append(labelKeys, &metricspb.LabelKey{Key: column})
+	}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        config.MetricName,
+			Description: config.Description,
+			Unit:        config.Unit,
+			Type:        metricType,
+			LabelKeys:   labelKeys,
+		},
+	}
+
+	ts := timestamppb.New(now)
+	var errs []error
+	for _, row := range rows {
+		timeseries, err := buildTimeSeries(config, row, isDouble, ts)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("metric %q: %w", config.MetricName, err))
+			continue
+		}
+		if isSum {
+			timeseries.StartTimestamp = timestamppb.New(start)
+		}
+		metric.Timeseries = append(metric.Timeseries, timeseries)
+	}
+	return metric, errs
+}
+
+func buildTimeSeries(config MetricConfig, row stringMap, isDouble bool, ts *timestamppb.Timestamp) (*metricspb.TimeSeries, error) {
+	if err := checkColumns(row, config.ValueColumn, config.AttributeColumns...); err != nil {
+		return nil, err
+	}
+
+	labelValues := make([]*metricspb.LabelValue, 0, len(config.AttributeColumns))
+	for _, column := range config.AttributeColumns {
+		labelValues = append(labelValues, &metricspb.LabelValue{Value: row[column], HasValue: true})
+	}
+
+	raw := row[config.ValueColumn]
+
+	point := &metricspb.Point{Timestamp: ts}
+	if isDouble {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of column %q: %w", config.ValueColumn, err)
+		}
+		point.Value = &metricspb.Point_DoubleValue{DoubleValue: value}
+	} else {
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value of column %q: %w", config.ValueColumn, err)
+		}
+		point.Value = &metricspb.Point_Int64Value{Int64Value: value}
+	}
+
+	return &metricspb.TimeSeries{
+		LabelValues: labelValues,
+		Points:      []*metricspb.Point{point},
+	}, nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBuildMetric(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	now := start.Add(time.Minute)
+	rows := []stringMap{
+		{"count": "3", "total": "12.5", "status": "paid"},
+		{"count": "1", "total": "4", "status": "refunded"},
+	}
+
+	metric, errs := buildMetric(MetricConfig{
+		MetricName:       "orders.count",
+		ValueColumn:      "count",
+		AttributeColumns: []string{"status"},
+		Unit:             "1",
+		Description:      "Number of orders by status.",
+	}, rows, start, now)
+	assert.Empty(t, errs)
+	assert.Equal(t, &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "orders.count",
+			Description: "Number of orders by status.",
+			Unit:        "1",
+			Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys:   []*metricspb.LabelKey{{Key: "status"}},
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				LabelValues: []*metricspb.LabelValue{{Value: "paid", HasValue: true}},
+				Points: []*metricspb.Point{
+					{Timestamp: timestamppb.New(now), Value: &metricspb.Point_Int64Value{Int64Value: 3}},
+				},
+			},
+			{
+				LabelValues: []*metricspb.LabelValue{{Value: "refunded", HasValue: true}},
+				Points: []*metricspb.Point{
+					{Timestamp: timestamppb.New(now), Value: &metricspb.Point_Int64Value{Int64Value: 1}},
+				},
+			},
+		},
+	}, metric)
+
+	metric, errs = buildMetric(MetricConfig{
+		MetricName:  "orders.total",
+		ValueColumn: "total",
+		ValueType:   valueTypeDouble,
+		DataType:    dataTypeSum,
+	}, rows, start, now)
+	assert.Empty(t, errs)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, metric.GetMetricDescriptor().GetType())
+	require.Len(t, metric.Timeseries, 2)
+	assert.Equal(t, timestamppb.New(start), metric.Timeseries[0].StartTimestamp)
+	assert.Equal(t, 12.5, metric.Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, 4.0, metric.Timeseries[1].Points[0].GetDoubleValue())
+}
+
+func TestBuildMetricErrors(t *testing.T) {
+	rows := []stringMap{
+		{"count": "3", "status": "paid"},
+		{"count": "", "status": "pending"},
+		{"count": "2"},
+	}
+
+	metric, errs := buildMetric(MetricConfig{
+		MetricName:       "orders.count",
+		ValueColumn:      "count",
+		AttributeColumns: []string{"status"},
+	}, rows, time.Now(), time.Now())
+	require.Len(t, metric.Timeseries, 1)
+	assert.Equal(t, "paid", metric.Timeseries[0].LabelValues[0].Value)
+	require.Len(t, errs, 2)
+	assert.EqualError(t, errs[0], `metric "orders.count": failed to parse value of column "count": strconv.ParseInt: parsing "": invalid syntax`)
+	assert.EqualError(t, errs[1], `metric "orders.count": column "status" not found in the query results`)
+}
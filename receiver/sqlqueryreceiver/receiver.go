@@ -0,0 +1,214 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "sqlquery"
+	transport  = "sql"
+)
+
+var (
+	_ component.MetricsReceiver = (*sqlQueryReceiver)(nil)
+	_ component.LogsReceiver    = (*sqlQueryReceiver)(nil)
+)
+
+// sqlQueryReceiver implements the component.MetricsReceiver and the
+// component.LogsReceiver for SQL queries. Only one of metricsConsumer and
+// logsConsumer is set, depending on the pipeline the receiver was created
+// for.
+type sqlQueryReceiver struct {
+	logger          *zap.Logger
+	config          *Config
+	metricsConsumer consumer.MetricsConsumer
+	logsConsumer    consumer.LogsConsumer
+	openClient      openClientFunc
+	client          dbClient
+	startTime       time.Time
+	cancel          context.CancelFunc
+}
+
+// newMetricsReceiver creates the SQL query receiver of a metrics pipeline.
+func newMetricsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	r := &sqlQueryReceiver{
+		logger:          logger,
+		config:          config,
+		metricsConsumer: nextConsumer,
+		openClient:      openSQLClient,
+	}
+	return r, nil
+}
+
+// newLogsReceiver creates the SQL query receiver of a logs pipeline.
+func newLogsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	r := &sqlQueryReceiver{
+		logger:       logger,
+		config:       config,
+		logsConsumer: nextConsumer,
+		openClient:   openSQLClient,
+	}
+	return r, nil
+}
+
+// Start opens the database and begins running the queries.
+func (r *sqlQueryReceiver) Start(ctx context.Context, host component.Host) error {
+	client, err := r.openClient(r.config.Driver, r.config.DataSource)
+	if err != nil {
+		return err
+	}
+	r.client = client
+	r.startTime = time.Now()
+
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect the results of the SQL queries", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the SQL query receiver and closes the database.
+func (r *sqlQueryReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	if r.client != nil {
+		return r.client.close()
+	}
+	return nil
+}
+
+func (r *sqlQueryReceiver) collect(ctx context.Context) error {
+	if r.metricsConsumer != nil {
+		return r.collectMetrics(ctx)
+	}
+	return r.collectLogs(ctx)
+}
+
+func (r *sqlQueryReceiver) collectMetrics(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	now := time.Now()
+	md := consumerdata.MetricsData{}
+	var errs []error
+	for _, query := range r.config.Queries {
+		if len(query.Metrics) == 0 {
+			continue
+		}
+		rows, err := r.client.queryRows(ctx, query.SQL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, metricConfig := range query.Metrics {
+			metric, metricErrs := buildMetric(metricConfig, rows, r.startTime, now)
+			errs = append(errs, metricErrs...)
+			if len(metric.Timeseries) > 0 {
+				md.Metrics = append(md.Metrics, metric)
+			}
+		}
+	}
+
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	if len(md.Metrics) > 0 {
+		if err := r.metricsConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	err := componenterror.CombineErrors(errs)
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
+
+func (r *sqlQueryReceiver) collectLogs(ctx context.Context) error {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+	rl.Resource().InitEmpty()
+	rl.InstrumentationLibraryLogs().Resize(1)
+	logs := rl.InstrumentationLibraryLogs().At(0).Logs()
+
+	now := time.Now()
+	var errs []error
+	for _, query := range r.config.Queries {
+		if len(query.Logs) == 0 {
+			continue
+		}
+		rows, err := r.client.queryRows(ctx, query.SQL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, logsConfig := range query.Logs {
+			errs = append(errs, appendLogRecords(logs, logsConfig, rows, now)...)
+		}
+	}
+
+	if logs.Len() > 0 {
+		if err := r.logsConsumer.ConsumeLogs(ctx, ld); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
@@ -0,0 +1,138 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+// fakeClient answers each query with the rows registered for it.
+type fakeClient struct {
+	rows   map[string][]stringMap
+	closed bool
+}
+
+func (c *fakeClient) queryRows(_ context.Context, query string) ([]stringMap, error) {
+	rows, ok := c.rows[query]
+	if !ok {
+		return nil, errors.New("unexpected query: " + query)
+	}
+	return rows, nil
+}
+
+func (c *fakeClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func fakeOpenClient(client *fakeClient) openClientFunc {
+	return func(string, string) (dbClient, error) {
+		return client, nil
+	}
+}
+
+func receiverTestConfig() *Config {
+	cfg := testConfig()
+	cfg.Queries = []Query{
+		{
+			SQL: "select count",
+			Metrics: []MetricConfig{
+				{MetricName: "orders.count", ValueColumn: "count", AttributeColumns: []string{"status"}},
+			},
+		},
+		{
+			SQL:  "select events",
+			Logs: []LogsConfig{{BodyColumn: "message"}},
+		},
+		{
+			SQL:     "select missing",
+			Metrics: []MetricConfig{{MetricName: "missing", ValueColumn: "value"}},
+		},
+	}
+	return cfg
+}
+
+func TestMetricsReceiverCollect(t *testing.T) {
+	client := &fakeClient{rows: map[string][]stringMap{
+		"select count": {{"count": "3", "status": "paid"}, {"count": "1", "status": "refunded"}},
+	}}
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newMetricsReceiver(zap.NewNop(), receiverTestConfig(), sink)
+	require.NoError(t, err)
+	r := rcv.(*sqlQueryReceiver)
+	r.openClient = fakeOpenClient(client)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	assert.EqualError(t, r.collect(context.Background()), "unexpected query: select missing")
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+	require.Len(t, ocmds[0].Metrics, 1)
+	assert.Equal(t, "orders.count", ocmds[0].Metrics[0].GetMetricDescriptor().GetName())
+	assert.Len(t, ocmds[0].Metrics[0].GetTimeseries(), 2)
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.True(t, client.closed)
+}
+
+func TestLogsReceiverCollect(t *testing.T) {
+	client := &fakeClient{rows: map[string][]stringMap{
+		"select events": {{"message": "user created"}, {"message": "user deleted"}},
+	}}
+	sink := new(exportertest.SinkLogsExporter)
+	rcv, err := newLogsReceiver(zap.NewNop(), receiverTestConfig(), sink)
+	require.NoError(t, err)
+	r := rcv.(*sqlQueryReceiver)
+	r.openClient = fakeOpenClient(client)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, r.collect(context.Background()))
+	assert.Equal(t, 2, sink.LogRecordsCount())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.True(t, client.closed)
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	client := &fakeClient{rows: map[string][]stringMap{
+		"select count":   {{"count": "3", "status": "paid"}},
+		"select missing": {{"value": "1"}},
+	}}
+	cfg := receiverTestConfig()
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*sqlQueryReceiver)
+	r.openClient = fakeOpenClient(client)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,94 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"fmt"
+)
+
+const (
+	valueTypeInt    = "int"
+	valueTypeDouble = "double"
+
+	dataTypeGauge = "gauge"
+	dataTypeSum   = "sum"
+)
+
+// validateConfig checks the configuration of the receiver.
+func validateConfig(config *Config) error {
+	if !contains(supportedDrivers, config.Driver) {
+		return fmt.Errorf("unsupported driver %q for receiver %q", config.Driver, config.Name())
+	}
+	if config.DataSource == "" {
+		return fmt.Errorf("no datasource configured for receiver %q", config.Name())
+	}
+	if len(config.Queries) == 0 {
+		return fmt.Errorf("no queries configured for receiver %q", config.Name())
+	}
+	if config.CollectionInterval <= 0 {
+		return fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	for i, query := range config.Queries {
+		if err := validateQuery(query); err != nil {
+			return fmt.Errorf("invalid query %d of receiver %q: %w", i, config.Name(), err)
+		}
+	}
+	return nil
+}
+
+func validateQuery(query Query) error {
+	if query.SQL == "" {
+		return fmt.Errorf("sql must be set")
+	}
+	if len(query.Metrics) == 0 && len(query.Logs) == 0 {
+		return fmt.Errorf("at least one of metrics or logs must be set")
+	}
+
+	for _, metric := range query.Metrics {
+		if metric.MetricName == "" {
+			return fmt.Errorf("metric_name must be set")
+		}
+		if metric.ValueColumn == "" {
+			return fmt.Errorf("metric %q: value_column must be set", metric.MetricName)
+		}
+		switch metric.ValueType {
+		case "", valueTypeInt, valueTypeDouble:
+		default:
+			return fmt.Errorf("metric %q: unsupported value_type %q", metric.MetricName, metric.ValueType)
+		}
+		switch metric.DataType {
+		case "", dataTypeGauge, dataTypeSum:
+		default:
+			return fmt.Errorf("metric %q: unsupported data_type %q", metric.MetricName, metric.DataType)
+		}
+	}
+
+	for _, logs := range query.Logs {
+		if logs.BodyColumn == "" {
+			return fmt.Errorf("logs: body_column must be set")
+		}
+	}
+	return nil
+}
+
+func contains(slice []string, element string) bool {
+	for _, val := range slice {
+		if val == element {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// clientVersion is the identification string sent to the servers.
+	clientVersion = "SSH-2.0-OpenTelemetryCollector"
+
+	// maxPreambleLines is the number of lines a server may send before its
+	// identification string, see https://tools.ietf.org/html/rfc4253#section-4.2.
+	maxPreambleLines = 32
+)
+
+var errNoIdentification = errors.New("server did not send an SSH identification string")
+
+// target probes a single SSH server.
+type target struct {
+	endpoint string
+	host     string
+	port     string
+}
+
+// checkResult holds the outcome of a single probe.
+type checkResult struct {
+	target        *target
+	err           error
+	duration      time.Duration
+	serverVersion string
+}
+
+func newTarget(config TargetConfig) (*target, error) {
+	host, port, err := net.SplitHostPort(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", config.Endpoint, err)
+	}
+	return &target{
+		endpoint: config.Endpoint,
+		host:     host,
+		port:     port,
+	}, nil
+}
+
+// check connects to the target and performs the protocol version exchange
+// that starts every SSH handshake, without going on with the key exchange or
+// authentication. Failing to do so is reported in the result rather than as
+// an error.
+func (t *target) check(ctx context.Context, timeout time.Duration) *checkResult {
+	result := &checkResult{
+		target: t,
+	}
+
+	start := time.Now()
+	result.serverVersion, result.err = t.exchangeVersions(ctx, timeout)
+	result.duration = time.Since(start)
+	return result
+}
+
+func (t *target) exchangeVersions(ctx context.Context, timeout time.Duration) (string, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", t.endpoint)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err = fmt.Fprintf(conn, "%s\r\n", clientVersion); err != nil {
+		return "", err
+	}
+
+	reader := bufio.NewReader(conn)
+	for i := 0; i < maxPreambleLines; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if !strings.HasPrefix(line, "SSH-") {
+			continue
+		}
+		// Servers supporting both protocol versions identify as 1.99.
+		if !strings.HasPrefix(line, "SSH-2.0-") && !strings.HasPrefix(line, "SSH-1.99-") {
+			return line, fmt.Errorf("unsupported SSH protocol version: %s", line)
+		}
+		return line, nil
+	}
+	return "", errNoIdentification
+}
@@ -0,0 +1,128 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+// startServer starts a fake SSH server answering every connection with the
+// given lines after reading the client identification string.
+func startServer(t *testing.T, lines ...string) net.Listener {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				clientLine, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil || clientLine != clientVersion+"\r\n" {
+					return
+				}
+				for _, line := range lines {
+					if _, err := conn.Write([]byte(line + "\r\n")); err != nil {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+	return ln
+}
+
+func TestTargetCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       []string
+		wantVersion string
+		wantErr     string
+	}{
+		{
+			name:        "ssh 2.0",
+			lines:       []string{"SSH-2.0-OpenSSH_8.2p1 Ubuntu-4ubuntu0.1"},
+			wantVersion: "SSH-2.0-OpenSSH_8.2p1 Ubuntu-4ubuntu0.1",
+		},
+		{
+			name:        "ssh 1.99 with preamble",
+			lines:       []string{"Authorized access only", "SSH-1.99-Cisco-1.25"},
+			wantVersion: "SSH-1.99-Cisco-1.25",
+		},
+		{
+			name:        "ssh 1.5",
+			lines:       []string{"SSH-1.5-OldServer"},
+			wantVersion: "SSH-1.5-OldServer",
+			wantErr:     "unsupported SSH protocol version: SSH-1.5-OldServer",
+		},
+		{
+			name:    "not ssh",
+			lines:   []string{"220 smtp.example.com ESMTP"},
+			wantErr: "EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln := startServer(t, tt.lines...)
+			defer ln.Close()
+
+			tgt, err := newTarget(TargetConfig{Endpoint: ln.Addr().String()})
+			require.NoError(t, err)
+
+			result := tgt.check(context.Background(), time.Second)
+			if tt.wantErr != "" {
+				assert.EqualError(t, result.err, tt.wantErr)
+			} else {
+				assert.NoError(t, result.err)
+			}
+			assert.Equal(t, tt.wantVersion, result.serverVersion)
+			assert.True(t, result.duration > 0)
+		})
+	}
+}
+
+func TestTargetCheckTimeout(t *testing.T) {
+	// The listener accepts the connection but never answers.
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	tgt, err := newTarget(TargetConfig{Endpoint: ln.Addr().String()})
+	require.NoError(t, err)
+
+	result := tgt.check(context.Background(), 50*time.Millisecond)
+	require.Error(t, result.err)
+	netErr, ok := result.err.(net.Error)
+	require.True(t, ok)
+	assert.True(t, netErr.Timeout())
+}
+
+func TestTargetCheckUnreachable(t *testing.T) {
+	tgt, err := newTarget(TargetConfig{Endpoint: testutil.GetAvailableLocalAddress(t)})
+	require.NoError(t, err)
+
+	result := tgt.check(context.Background(), time.Second)
+	assert.Error(t, result.err)
+}
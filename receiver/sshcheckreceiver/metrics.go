@@ -0,0 +1,74 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var labelKeys = []*metricspb.LabelKey{{Key: "net.peer.name"}, {Key: "net.peer.port"}}
+
+// buildMetrics converts the probe results into metrics.
+func buildMetrics(results []*checkResult, now time.Time) []*metricspb.Metric {
+	ts := timestamppb.New(now)
+
+	up := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "sshcheck.up",
+			Description: "Whether the server answered with a valid SSH identification string (1) or not (0).",
+			Unit:        "1",
+			Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+			LabelKeys:   labelKeys,
+		},
+	}
+	duration := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:        "sshcheck.duration",
+			Description: "Time taken to connect to the server and receive its identification string, or to fail to.",
+			Unit:        "s",
+			Type:        metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			LabelKeys:   labelKeys,
+		},
+	}
+
+	for _, r := range results {
+		labelValues := []*metricspb.LabelValue{
+			{Value: r.target.host, HasValue: true},
+			{Value: r.target.port, HasValue: true},
+		}
+
+		upValue := int64(0)
+		if r.err == nil {
+			upValue = 1
+		}
+		up.Timeseries = append(up.Timeseries, &metricspb.TimeSeries{
+			LabelValues: labelValues,
+			Points: []*metricspb.Point{
+				{Timestamp: ts, Value: &metricspb.Point_Int64Value{Int64Value: upValue}},
+			},
+		})
+		duration.Timeseries = append(duration.Timeseries, &metricspb.TimeSeries{
+			LabelValues: labelValues,
+			Points: []*metricspb.Point{
+				{Timestamp: ts, Value: &metricspb.Point_DoubleValue{DoubleValue: r.duration.Seconds()}},
+			},
+		})
+	}
+
+	return []*metricspb.Metric{up, duration}
+}
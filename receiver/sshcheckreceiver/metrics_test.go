@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	results := []*checkResult{
+		{
+			target:   &target{endpoint: "bastion:22", host: "bastion", port: "22"},
+			duration: 20 * time.Millisecond,
+		},
+		{
+			target:   &target{endpoint: "git:2222", host: "git", port: "2222"},
+			err:      errors.New("invalid SSH identification string"),
+			duration: time.Millisecond,
+		},
+	}
+
+	metrics := buildMetrics(results, time.Now())
+	require.Len(t, metrics, 2)
+
+	values := func(m *metricspb.Metric) map[string]float64 {
+		result := make(map[string]float64)
+		for _, ts := range m.GetTimeseries() {
+			p := ts.GetPoints()[0]
+			key := ts.GetLabelValues()[0].GetValue() + ":" + ts.GetLabelValues()[1].GetValue()
+			result[key] = p.GetDoubleValue() + float64(p.GetInt64Value())
+		}
+		return result
+	}
+
+	assert.Equal(t, "sshcheck.up", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"bastion:22": 1, "git:2222": 0}, values(metrics[0]))
+	assert.Equal(t, "sshcheck.duration", metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"bastion:22": 0.02, "git:2222": 0.001}, values(metrics[1]))
+}
@@ -0,0 +1,145 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+const (
+	dataFormat = "sshcheck"
+	transport  = "tcp"
+)
+
+var _ component.MetricsReceiver = (*sshCheckReceiver)(nil)
+
+// sshCheckReceiver implements the component.MetricsReceiver for SSH
+// availability checks.
+type sshCheckReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	targets      []*target
+	cancel       context.CancelFunc
+}
+
+// newSSHCheckReceiver creates the SSH check receiver with the given parameters.
+func newSSHCheckReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+
+	if config.CollectionInterval <= 0 {
+		return nil, fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("no targets configured for receiver %q", config.Name())
+	}
+
+	targets := make([]*target, 0, len(config.Targets))
+	for _, targetConfig := range config.Targets {
+		t, err := newTarget(targetConfig)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	r := &sshCheckReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+		targets:      targets,
+	}
+	return r, nil
+}
+
+// Start begins probing the configured targets.
+func (r *sshCheckReceiver) Start(ctx context.Context, host component.Host) error {
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), typeStr, transport, r.config.Name()))
+	go func() {
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to report SSH check metrics", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the SSH check receiver.
+func (r *sshCheckReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return nil
+}
+
+// collect probes all the targets concurrently so an unresponsive server
+// doesn't delay the checks of the others.
+func (r *sshCheckReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	results := make([]*checkResult, len(r.targets))
+	var wg sync.WaitGroup
+	for i, t := range r.targets {
+		wg.Add(1)
+		go func(i int, t *target) {
+			defer wg.Done()
+			results[i] = t.check(ctx, r.config.Timeout)
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if result.err != nil {
+			r.logger.Debug("SSH check failed",
+				zap.String("endpoint", result.target.endpoint),
+				zap.Error(result.err))
+		}
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: buildMetrics(results, time.Now()),
+	}
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	err := r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sshcheckreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestReceiverCollect(t *testing.T) {
+	ln := startServer(t, "SSH-2.0-OpenSSH_8.2p1")
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Targets = []TargetConfig{{Endpoint: ln.Addr().String()}}
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := newSSHCheckReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*sshCheckReceiver)
+
+	require.NoError(t, r.collect(context.Background()))
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+	require.Len(t, ocmds[0].Metrics, 2)
+	assert.Equal(t, int64(1), ocmds[0].Metrics[0].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	ln := startServer(t, "SSH-2.0-OpenSSH_8.2p1")
+	defer ln.Close()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = 10 * time.Millisecond
+	cfg.Targets = []TargetConfig{{Endpoint: ln.Addr().String()}}
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := newSSHCheckReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
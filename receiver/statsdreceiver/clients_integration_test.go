@@ -0,0 +1,328 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build integration
+// +build integration
+
+package statsdreceiver
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/testing/container"
+)
+
+// The conformance tests run StatsD and DogStatsD client libraries in Docker
+// against the receiver, to catch the differences between the lines the
+// clients actually send and the protocol as the receiver understands it. The
+// clients run in the network namespace of the host to reach the receiver
+// listening on the loopback interface, which requires a local Docker daemon on
+// Linux.
+//
+// Each client sends the same metrics under its own conformance.<client>
+// prefix, the other metrics such as the telemetry of the clients being
+// ignored. No sample rate is used so that the aggregated values are exact.
+
+const pythonClient = `
+import os
+
+import statsd
+from datadog import DogStatsd
+
+host, port = os.environ["STATSD_HOST"], int(os.environ["STATSD_PORT"])
+
+c = statsd.StatsClient(host, port, prefix="conformance.python_statsd")
+c.incr("counter", 3)
+c.gauge("gauge", 42)
+c.timing("timer", 320)
+
+d = DogStatsd(host=host, port=port, namespace="conformance.python_datadog", constant_tags=["env:prod"])
+d.increment("counter", 3)
+d.gauge("gauge", 42)
+d.timing("timer", 320)
+d.histogram("histogram", 320)
+d.distribution("distribution", 320)
+`
+
+const goClient = `
+package main
+
+import (
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/DataDog/datadog-go/statsd"
+)
+
+func main() {
+	addr := net.JoinHostPort(os.Getenv("STATSD_HOST"), os.Getenv("STATSD_PORT"))
+	c, err := statsd.New(addr,
+		statsd.WithNamespace("conformance.datadog_go."),
+		statsd.WithTags([]string{"env:prod"}),
+		statsd.WithoutTelemetry())
+	if err != nil {
+		log.Fatal(err)
+	}
+	checks := []error{
+		c.Count("counter", 3, nil, 1),
+		c.Gauge("gauge", 42, nil, 1),
+		c.Timing("timer", 320*time.Millisecond, nil, 1),
+		c.Histogram("histogram", 320, nil, 1),
+		c.Distribution("distribution", 320, nil, 1),
+		c.Close(),
+	}
+	for _, err := range checks {
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+`
+
+const javaStatsDClient = `
+import com.timgroup.statsd.NonBlockingStatsDClient;
+import com.timgroup.statsd.StatsDClient;
+
+public class Client {
+    public static void main(String[] args) {
+        StatsDClient c = new NonBlockingStatsDClient("conformance.java_statsd",
+            System.getenv("STATSD_HOST"), Integer.parseInt(System.getenv("STATSD_PORT")));
+        c.count("counter", 3);
+        c.gauge("gauge", 42);
+        c.recordExecutionTime("timer", 320);
+        c.stop();
+    }
+}
+`
+
+const javaDogStatsDClient = `
+import com.timgroup.statsd.NonBlockingStatsDClient;
+import com.timgroup.statsd.StatsDClient;
+
+public class Client {
+    public static void main(String[] args) {
+        StatsDClient c = new NonBlockingStatsDClient("conformance.java_dogstatsd",
+            System.getenv("STATSD_HOST"), Integer.parseInt(System.getenv("STATSD_PORT")), "env:prod");
+        c.count("counter", 3);
+        c.gauge("gauge", 42);
+        c.recordExecutionTime("timer", 320);
+        c.histogram("histogram", 320);
+        c.recordDistributionValue("distribution", 320);
+        c.stop();
+    }
+}
+`
+
+// javaCmd resolves the class path of the client library with a minimal
+// project, the two Java libraries sharing the com.timgroup.statsd package.
+const javaCmd = `set -e
+mkdir /tmp/client && cd /tmp/client
+printf '%s' "$CLIENT_SOURCE" > Client.java
+cat > pom.xml <<EOF
+<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>client</groupId>
+  <artifactId>client</artifactId>
+  <version>1</version>
+  <dependencies>
+    <dependency>
+      <groupId>$GROUP_ID</groupId>
+      <artifactId>$ARTIFACT_ID</artifactId>
+      <version>$VERSION</version>
+    </dependency>
+  </dependencies>
+</project>
+EOF
+mvn -q dependency:build-classpath -Dmdep.outputFile=classpath.txt
+java -cp "$(cat classpath.txt)" Client.java`
+
+type expectedMetric struct {
+	name   string
+	labels map[string]string
+	// value is the value of the counters and gauges, the sum of the
+	// distributions.
+	value float64
+	// count is the number of observations of the distributions.
+	count int64
+}
+
+func statsdMetrics(prefix string) []expectedMetric {
+	return []expectedMetric{
+		{name: prefix + ".counter", value: 3},
+		{name: prefix + ".gauge", value: 42},
+		{name: prefix + ".timer", value: 320, count: 1},
+	}
+}
+
+func dogStatsDMetrics(prefix string) []expectedMetric {
+	labels := map[string]string{"env": "prod"}
+	return []expectedMetric{
+		{name: prefix + ".counter", labels: labels, value: 3},
+		{name: prefix + ".gauge", labels: labels, value: 42},
+		{name: prefix + ".timer", labels: labels, value: 320, count: 1},
+		{name: prefix + ".histogram", labels: labels, value: 320, count: 1},
+		{name: prefix + ".distribution", labels: labels, value: 320, count: 1},
+	}
+}
+
+func TestClientsIntegration(t *testing.T) {
+	tests := []struct {
+		name     string
+		image    string
+		source   string
+		env      []string
+		cmd      string
+		expected []expectedMetric
+	}{
+		{
+			name:   "python",
+			image:  "docker.io/library/python:3.8-slim",
+			source: pythonClient,
+			cmd: `set -e
+pip install -q statsd==3.3.0 datadog==0.39.0
+printf '%s' "$CLIENT_SOURCE" > /tmp/client.py
+python /tmp/client.py`,
+			expected: append(statsdMetrics("conformance.python_statsd"), dogStatsDMetrics("conformance.python_datadog")...),
+		},
+		{
+			name:   "go",
+			image:  "docker.io/library/golang:1.15",
+			source: goClient,
+			cmd: `set -e
+mkdir /tmp/client && cd /tmp/client
+printf '%s' "$CLIENT_SOURCE" > main.go
+go mod init client
+go get github.com/DataDog/datadog-go@v3.7.2+incompatible
+go run .`,
+			expected: dogStatsDMetrics("conformance.datadog_go"),
+		},
+		{
+			name:     "java_statsd",
+			image:    "docker.io/library/maven:3.6-openjdk-11",
+			source:   javaStatsDClient,
+			env:      []string{"GROUP_ID=com.timgroup", "ARTIFACT_ID=java-statsd-client", "VERSION=3.1.0"},
+			cmd:      javaCmd,
+			expected: statsdMetrics("conformance.java_statsd"),
+		},
+		{
+			name:     "java_dogstatsd",
+			image:    "docker.io/library/maven:3.6-openjdk-11",
+			source:   javaDogStatsDClient,
+			env:      []string{"GROUP_ID=com.datadoghq", "ARTIFACT_ID=java-dogstatsd-client", "VERSION=2.10.5"},
+			cmd:      javaCmd,
+			expected: dogStatsDMetrics("conformance.java_dogstatsd"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := testutil.GetAvailableLocalAddress(t)
+			host, port, err := net.SplitHostPort(addr)
+			require.NoError(t, err)
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.NetAddr.Endpoint = addr
+			cfg.AggregationInterval = 100 * time.Millisecond
+			sink := new(exportertest.SinkMetricsExporter)
+			rcv, err := New(zaptest.NewLogger(t), *cfg, sink)
+			require.NoError(t, err)
+			require.NoError(t, rcv.Start(context.Background(), componenttest.NewNopHost()))
+			defer rcv.Shutdown(context.Background())
+
+			d := container.New(t)
+			c := d.StartImage(tt.image,
+				container.WithHostNetwork(),
+				container.WithEnv("STATSD_HOST="+host, "STATSD_PORT="+port, "CLIENT_SOURCE="+tt.source),
+				container.WithEnv(tt.env...),
+				container.WithCmd("sh", "-c", tt.cmd))
+			exitCode, output := d.Wait(c, 10*time.Minute)
+			require.Equalf(t, int64(0), exitCode, "client failed:\n%s", output)
+
+			var actual map[string]expectedMetric
+			assert.Eventually(t, func() bool {
+				actual = receivedMetrics(t, sink)
+				for _, expected := range tt.expected {
+					if _, ok := actual[expected.name]; !ok {
+						return false
+					}
+				}
+				return true
+			}, 10*time.Second, 100*time.Millisecond, "missing metrics")
+
+			for _, expected := range tt.expected {
+				assert.Equal(t, expected, actual[expected.name], expected.name)
+			}
+		})
+	}
+}
+
+// receivedMetrics sums the conformance metrics received by the sink over the
+// aggregation intervals, the gauges keeping their last value.
+func receivedMetrics(t *testing.T, sink *exportertest.SinkMetricsExporter) map[string]expectedMetric {
+	received := map[string]expectedMetric{}
+	for _, md := range sink.AllMetrics() {
+		for _, ocmd := range internaldata.MetricsToOC(md) {
+			for _, metric := range ocmd.Metrics {
+				descriptor := metric.GetMetricDescriptor()
+				if !strings.HasPrefix(descriptor.GetName(), "conformance.") {
+					continue
+				}
+				for _, ts := range metric.GetTimeseries() {
+					m := received[descriptor.GetName()]
+					m.name = descriptor.GetName()
+					m.labels = nil
+					for i, key := range descriptor.GetLabelKeys() {
+						if m.labels == nil {
+							m.labels = map[string]string{}
+						}
+						m.labels[key.GetKey()] = ts.GetLabelValues()[i].GetValue()
+					}
+					for _, point := range ts.GetPoints() {
+						switch descriptor.GetType() {
+						case metricspb.MetricDescriptor_GAUGE_INT64:
+							m.value = float64(point.GetInt64Value())
+						case metricspb.MetricDescriptor_GAUGE_DOUBLE:
+							m.value = point.GetDoubleValue()
+						case metricspb.MetricDescriptor_CUMULATIVE_INT64:
+							m.value += float64(point.GetInt64Value())
+						case metricspb.MetricDescriptor_CUMULATIVE_DOUBLE:
+							m.value += point.GetDoubleValue()
+						case metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION:
+							m.value += point.GetDistributionValue().GetSum()
+							m.count += point.GetDistributionValue().GetCount()
+						default:
+							t.Errorf("unexpected type %v of metric %v", descriptor.GetType(), descriptor.GetName())
+						}
+					}
+					received[m.name] = m
+				}
+			}
+		}
+	}
+	return received
+}
@@ -15,12 +15,112 @@
 package statsdreceiver
 
 import (
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
 // Config defines configuration for StatsD receiver.
 type Config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
 	NetAddr                       confignet.NetAddr `mapstructure:",squash"`
+
+	// AggregationInterval is the interval at which the metrics aggregated
+	// from the received StatsD messages are sent to the next consumer.
+	AggregationInterval time.Duration `mapstructure:"aggregation_interval"`
+
+	// DistributionObserver defines how timer, histogram and DogStatsD
+	// distribution metrics are aggregated, either "histogram" or "summary".
+	DistributionObserver protocol.ObserverType `mapstructure:"distribution_observer"`
+
+	// Histogram defines the bucket bounds of the metrics aggregated by the
+	// "histogram" observer, by metric name prefix.
+	Histogram protocol.HistogramConfig `mapstructure:"histogram"`
+
+	// MaxPacketSize is the maximum size of the UDP packets, larger packets
+	// are truncated to their last complete line. With the TCP transport it is
+	// the maximum size of the lines, larger lines are dropped.
+	MaxPacketSize int `mapstructure:"max_packet_size"`
+
+	// ReadBufferSize is the size of the operating system receive buffer of
+	// the UDP socket, the system default is used if not set. It is ignored if
+	// the transport being used is TCP.
+	ReadBufferSize int `mapstructure:"read_buffer_size"`
+
+	// UDPReaders is the number of UDP sockets bound to the endpoint with
+	// SO_REUSEPORT, each read by its own goroutine. One socket is used if
+	// not set. It is ignored if the transport being used is TCP.
+	UDPReaders int `mapstructure:"udp_readers"`
+
+	// TCPIdleTimeout is the timeout for idle TCP connections, it is ignored
+	// if the transport being used is UDP.
+	TCPIdleTimeout time.Duration `mapstructure:"tcp_idle_timeout"`
+
+	// TCPMaxConnections is the maximum number of concurrent TCP connections,
+	// zero means no limit. It is ignored if the transport being used is UDP.
+	TCPMaxConnections int `mapstructure:"tcp_max_connections"`
+
+	// ResourceAttributes lists the tags reported as resource attributes
+	// instead of metric labels.
+	ResourceAttributes []ResourceAttributeConfig `mapstructure:"resource_attributes"`
+
+	// Filter drops the metrics by name when they are parsed, before they are
+	// aggregated.
+	Filter protocol.FilterConfig `mapstructure:"filter"`
+
+	// Mappings rename the metrics and extract labels from their names, the
+	// first mapping matching the name of a metric being applied.
+	Mappings []protocol.MappingConfig `mapstructure:"mappings"`
+
+	// EnableSourceAddress adds the IP address of the client that sent the
+	// metrics as the net.peer.ip resource attribute.
+	EnableSourceAddress bool `mapstructure:"enable_source_address"`
+
+	// Exemplars attaches the trace context recently received from each
+	// client as exemplars of its aggregated counters and histograms.
+	Exemplars ExemplarsConfig `mapstructure:"exemplars"`
+
+	// Persistence saves the metrics aggregated since the last interval on
+	// shutdown instead of reporting them, and restores them on start.
+	Persistence PersistenceConfig `mapstructure:"persistence"`
+}
+
+// PersistenceConfig defines where the aggregation state is saved across
+// restarts.
+type PersistenceConfig struct {
+	// Directory is the directory of the file holding the aggregation state
+	// of the receiver. The state is not saved if not set.
+	Directory string `mapstructure:"directory"`
+}
+
+// ExemplarsConfig defines the source of the trace context attached as
+// exemplars.
+type ExemplarsConfig struct {
+	// TraceContextExporter is the name of the statsd_trace_context exporter
+	// recording the spans received by the collector from each client. No
+	// exemplar is attached if not set.
+	TraceContextExporter string `mapstructure:"trace_context_exporter"`
+}
+
+// ResourceAttributeConfig defines a tag promoted to a resource attribute.
+type ResourceAttributeConfig struct {
+	// Tag is the key of the StatsD tag.
+	Tag string `mapstructure:"tag"`
+
+	// Attribute is the name of the resource attribute, the tag key is used
+	// if not set.
+	Attribute string `mapstructure:"attribute"`
+}
+
+// TraceContextExporterConfig defines configuration for the
+// statsd_trace_context exporter.
+type TraceContextExporterConfig struct {
+	configmodels.ExporterSettings `mapstructure:",squash"`
+
+	// MaxAge is the duration the trace context of a client is kept after
+	// its last span was received.
+	MaxAge time.Duration `mapstructure:"max_age"`
 }
@@ -17,6 +17,7 @@ package statsdreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/config/configtest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -32,6 +35,7 @@ func TestLoadConfig(t *testing.T) {
 
 	factory := NewFactory()
 	factories.Receivers[configmodels.Type(typeStr)] = factory
+	factories.Exporters[configmodels.Type(traceContextTypeStr)] = NewTraceContextExporterFactory()
 	cfg, err := configtest.LoadConfigFile(
 		t, path.Join(".", "testdata", "config.yaml"), factories,
 	)
@@ -54,5 +58,54 @@ func TestLoadConfig(t *testing.T) {
 			Endpoint:  "localhost:12345",
 			Transport: "custom_transport",
 		},
+		AggregationInterval:  70 * time.Second,
+		DistributionObserver: protocol.SummaryObserver,
+		Histogram: protocol.HistogramConfig{
+			Bounds: []float64{5, 10, 50},
+			Overrides: []protocol.HistogramOverrideConfig{
+				{Prefix: "checkout.", Bounds: []float64{0.1, 0.25, 0.5}},
+			},
+		},
+		MaxPacketSize:       8192,
+		ReadBufferSize:      1048576,
+		UDPReaders:          4,
+		TCPIdleTimeout:      45 * time.Second,
+		TCPMaxConnections:   100,
+		EnableSourceAddress: true,
+		Exemplars:           ExemplarsConfig{TraceContextExporter: "statsd_trace_context/custom"},
+		Persistence:         PersistenceConfig{Directory: "/var/lib/otelcol/statsd"},
+		ResourceAttributes: []ResourceAttributeConfig{
+			{Tag: "host", Attribute: "host.name"},
+			{Tag: "env"},
+		},
+		Filter: protocol.FilterConfig{
+			Allow: []string{"api", "jobs"},
+			Deny:  []string{"api.*.debug"},
+		},
+		Mappings: []protocol.MappingConfig{
+			{
+				Match:  "api.*.*.count",
+				Name:   "api_requests",
+				Labels: map[string]string{"service": "$1", "code": "$2"},
+			},
+			{
+				Match:     `jobs\.(\w+)\.duration`,
+				MatchType: protocol.RegexMatch,
+				Name:      "job_duration",
+				Labels:    map[string]string{"job": "$1"},
+			},
+		},
 	}, r1)
+
+	e0 := cfg.Exporters["statsd_trace_context"]
+	assert.Equal(t, createDefaultTraceContextConfig(), e0)
+
+	e1 := cfg.Exporters["statsd_trace_context/custom"]
+	assert.Equal(t, &TraceContextExporterConfig{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(traceContextTypeStr),
+			NameVal: "statsd_trace_context/custom",
+		},
+		MaxAge: time.Minute,
+	}, e1)
 }
@@ -0,0 +1,142 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+)
+
+// The filtered labels of the exemplars holding the trace context.
+const (
+	exemplarTraceIDLabel = "trace_id"
+	exemplarSpanIDLabel  = "span_id"
+)
+
+// findTraceContextExporter returns the statsd_trace_context exporter of the
+// given name, which must be used by a traces pipeline.
+func findTraceContextExporter(host component.Host, name string, receiver string) (*traceContextExporter, error) {
+	for cfg, exporter := range host.GetExporters()[configmodels.TracesDataType] {
+		if cfg.Name() != name {
+			continue
+		}
+		if e, ok := exporter.(*traceContextExporter); ok {
+			return e, nil
+		}
+		return nil, fmt.Errorf("exporter %q of the exemplars of receiver %q is not a %s exporter", name, receiver, traceContextTypeStr)
+	}
+	return nil, fmt.Errorf("exporter %q of the exemplars of receiver %q is not used by a traces pipeline", name, receiver)
+}
+
+// addExemplars attaches the trace context received since the given time from
+// the client of each resource as an exemplar of the points of its counters
+// and histograms. The value of the exemplars is the value of the counters and
+// the mean of the histograms.
+func addExemplars(md pdata.Metrics, contexts *traceContextExporter, since time.Time) {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() || rm.Resource().IsNil() {
+			continue
+		}
+		ip, ok := rm.Resource().Attributes().Get(protocol.SourceAddressLabel)
+		if !ok {
+			continue
+		}
+		tc, ok := contexts.get(ip.StringVal(), since)
+		if !ok {
+			continue
+		}
+
+		ilms := rm.InstrumentationLibraryMetrics()
+		for j := 0; j < ilms.Len(); j++ {
+			ilm := ilms.At(j)
+			if ilm.IsNil() {
+				continue
+			}
+			metrics := ilm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				addMetricExemplars(metrics.At(k), tc)
+			}
+		}
+	}
+}
+
+func addMetricExemplars(metric pdata.Metric, tc traceContext) {
+	if metric.IsNil() {
+		return
+	}
+	timestamp := pdata.TimestampUnixNano(tc.received.UnixNano())
+	switch metric.DataType() {
+	case pdata.MetricDataTypeIntSum:
+		dps := metric.IntSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			dp.Exemplars().Append(newIntExemplar(dp.Value(), timestamp, tc))
+		}
+	case pdata.MetricDataTypeDoubleSum:
+		dps := metric.DoubleSum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			dp.Exemplars().Append(newDoubleExemplar(dp.Value(), timestamp, tc))
+		}
+	case pdata.MetricDataTypeIntHistogram:
+		dps := metric.IntHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.Count() > 0 {
+				dp.Exemplars().Append(newIntExemplar(dp.Sum()/int64(dp.Count()), timestamp, tc))
+			}
+		}
+	case pdata.MetricDataTypeDoubleHistogram:
+		dps := metric.DoubleHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			if dp.Count() > 0 {
+				dp.Exemplars().Append(newDoubleExemplar(dp.Sum()/float64(dp.Count()), timestamp, tc))
+			}
+		}
+	}
+}
+
+func newIntExemplar(value int64, timestamp pdata.TimestampUnixNano, tc traceContext) pdata.IntExemplar {
+	exemplar := pdata.NewIntExemplar()
+	exemplar.InitEmpty()
+	exemplar.SetValue(value)
+	exemplar.SetTimestamp(timestamp)
+	setTraceContextLabels(exemplar.FilteredLabels(), tc)
+	return exemplar
+}
+
+func newDoubleExemplar(value float64, timestamp pdata.TimestampUnixNano, tc traceContext) pdata.DoubleExemplar {
+	exemplar := pdata.NewDoubleExemplar()
+	exemplar.InitEmpty()
+	exemplar.SetValue(value)
+	exemplar.SetTimestamp(timestamp)
+	setTraceContextLabels(exemplar.FilteredLabels(), tc)
+	return exemplar
+}
+
+func setTraceContextLabels(labels pdata.StringMap, tc traceContext) {
+	labels.InitEmptyWithCapacity(2)
+	labels.Insert(exemplarTraceIDLabel, tc.traceID)
+	labels.Insert(exemplarSpanIDLabel, tc.spanID)
+}
@@ -0,0 +1,145 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+)
+
+type exportersHost struct {
+	component.Host
+	exporters map[configmodels.DataType]map[configmodels.Exporter]component.Exporter
+}
+
+func (h *exportersHost) GetExporters() map[configmodels.DataType]map[configmodels.Exporter]component.Exporter {
+	return h.exporters
+}
+
+func newExportersHost(exporters map[configmodels.Exporter]component.Exporter) component.Host {
+	return &exportersHost{
+		Host:      componenttest.NewNopHost(),
+		exporters: map[configmodels.DataType]map[configmodels.Exporter]component.Exporter{configmodels.TracesDataType: exporters},
+	}
+}
+
+func TestFindTraceContextExporter(t *testing.T) {
+	exp := newTraceContextExporter(0)
+	host := newExportersHost(map[configmodels.Exporter]component.Exporter{
+		createDefaultTraceContextConfig():                exp,
+		&configmodels.ExporterSettings{NameVal: "other"}: exportertest.NewNopTraceExporter(),
+	})
+
+	got, err := findTraceContextExporter(host, traceContextTypeStr, "statsd")
+	require.NoError(t, err)
+	assert.Same(t, exp, got)
+
+	_, err = findTraceContextExporter(host, "other", "statsd")
+	assert.Equal(t, errors.New(`exporter "other" of the exemplars of receiver "statsd" is not a statsd_trace_context exporter`), err)
+
+	_, err = findTraceContextExporter(host, "missing", "statsd")
+	assert.Equal(t, errors.New(`exporter "missing" of the exemplars of receiver "statsd" is not used by a traces pipeline`), err)
+}
+
+func TestAddExemplars(t *testing.T) {
+	now := time.Unix(1000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	contexts := newTraceContextExporter(0)
+	contexts.contexts["10.0.0.1"] = traceContext{traceID: "t1", spanID: "s1", received: now}
+	contexts.contexts["10.0.0.2"] = traceContext{traceID: "t2", spanID: "s2", received: now.Add(-time.Minute)}
+
+	counter := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: "counter", Type: metricspb.MetricDescriptor_CUMULATIVE_INT64},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{Value: &metricspb.Point_Int64Value{Int64Value: 42}}},
+		}},
+	}
+	histogram := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: "histogram", Type: metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{Value: &metricspb.Point_DistributionValue{DistributionValue: &metricspb.DistributionValue{
+				Count:         4,
+				Sum:           10,
+				BucketOptions: &metricspb.DistributionValue_BucketOptions{Type: &metricspb.DistributionValue_BucketOptions_Explicit_{Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{Bounds: []float64{5}}}},
+				Buckets:       []*metricspb.DistributionValue_Bucket{{Count: 4}, {Count: 0}},
+			}}}},
+		}},
+	}
+	gauge := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{Name: "gauge", Type: metricspb.MetricDescriptor_GAUGE_INT64},
+		Timeseries: []*metricspb.TimeSeries{{
+			Points: []*metricspb.Point{{Value: &metricspb.Point_Int64Value{Int64Value: 1}}},
+		}},
+	}
+	withAddress := func(ip string) *resourcepb.Resource {
+		return &resourcepb.Resource{Labels: map[string]string{protocol.SourceAddressLabel: ip}}
+	}
+	md := internaldata.OCSliceToMetrics([]consumerdata.MetricsData{
+		{Resource: withAddress("10.0.0.1"), Metrics: []*metricspb.Metric{counter, histogram, gauge}},
+		{Resource: withAddress("10.0.0.2"), Metrics: []*metricspb.Metric{counter}},
+		{Metrics: []*metricspb.Metric{counter}},
+	})
+
+	addExemplars(md, contexts, now.Add(-time.Second))
+
+	rms := md.ResourceMetrics()
+	require.Equal(t, 3, rms.Len())
+	metrics := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	exemplars := metrics.At(0).IntSum().DataPoints().At(0).Exemplars()
+	require.Equal(t, 1, exemplars.Len())
+	assert.Equal(t, int64(42), exemplars.At(0).Value())
+	assert.Equal(t, pdata.TimestampUnixNano(now.UnixNano()), exemplars.At(0).Timestamp())
+	assert.Equal(t, map[string]string{"trace_id": "t1", "span_id": "s1"}, labelsOf(exemplars.At(0).FilteredLabels()))
+
+	histogramExemplars := metrics.At(1).DoubleHistogram().DataPoints().At(0).Exemplars()
+	require.Equal(t, 1, histogramExemplars.Len())
+	assert.Equal(t, 2.5, histogramExemplars.At(0).Value())
+	assert.Equal(t, map[string]string{"trace_id": "t1", "span_id": "s1"}, labelsOf(histogramExemplars.At(0).FilteredLabels()))
+
+	assert.Equal(t, 0, metrics.At(2).IntGauge().DataPoints().At(0).Exemplars().Len())
+
+	// The trace context of 10.0.0.2 was received before the interval, the
+	// last resource has no source address.
+	for i := 1; i < rms.Len(); i++ {
+		assert.Equal(t, 0, rms.At(i).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints().At(0).Exemplars().Len())
+	}
+}
+
+func labelsOf(m pdata.StringMap) map[string]string {
+	labels := make(map[string]string, m.Len())
+	m.ForEach(func(k string, v pdata.StringValue) {
+		labels[k] = v.Value()
+	})
+	return labels
+}
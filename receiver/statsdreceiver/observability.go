@@ -0,0 +1,102 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+func init() {
+	view.Register(
+		viewPacketsReceived,
+		viewLinesParsed,
+		viewParseErrors,
+		viewMetricPointsDropped,
+	)
+}
+
+var (
+	tagReceiverKey, _ = tag.NewKey("receiver")
+	tagReasonKey, _   = tag.NewKey("reason")
+
+	mPacketsReceived     = stats.Int64("otelcol/statsd/packets_received", "Number of UDP packets received", "1")
+	mLinesParsed         = stats.Int64("otelcol/statsd/lines_parsed", "Number of StatsD lines successfully parsed", "1")
+	mParseErrors         = stats.Int64("otelcol/statsd/parse_errors", "Number of StatsD lines which could not be parsed", "1")
+	mMetricPointsDropped = stats.Int64("otelcol/statsd/metric_points_dropped", "Number of aggregated metric points refused by the next consumer", "1")
+)
+
+// The reasons of the parse errors not returned by the parser, in addition to
+// the protocol.ParseErrorReason values.
+const (
+	reasonPacketTruncated = "packet_truncated"
+	reasonOther           = "other"
+)
+
+var viewPacketsReceived = &view.View{
+	Name:        mPacketsReceived.Name(),
+	Description: mPacketsReceived.Description(),
+	Measure:     mPacketsReceived,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewLinesParsed = &view.View{
+	Name:        mLinesParsed.Name(),
+	Description: mLinesParsed.Description(),
+	Measure:     mLinesParsed,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+var viewParseErrors = &view.View{
+	Name:        mParseErrors.Name(),
+	Description: mParseErrors.Description(),
+	Measure:     mParseErrors,
+	TagKeys:     []tag.Key{tagReceiverKey, tagReasonKey},
+	Aggregation: view.Sum(),
+}
+
+var viewMetricPointsDropped = &view.View{
+	Name:        mMetricPointsDropped.Name(),
+	Description: mMetricPointsDropped.Description(),
+	Measure:     mMetricPointsDropped,
+	TagKeys:     []tag.Key{tagReceiverKey},
+	Aggregation: view.Sum(),
+}
+
+func recordPacketReceived(receiver string) {
+	record(receiver, nil, mPacketsReceived.M(1))
+}
+
+func recordLinesParsed(receiver string, n int) {
+	record(receiver, nil, mLinesParsed.M(int64(n)))
+}
+
+func recordParseError(receiver string, reason string) {
+	record(receiver, []tag.Mutator{tag.Upsert(tagReasonKey, reason)}, mParseErrors.M(1))
+}
+
+func recordMetricPointsDropped(receiver string, n int) {
+	record(receiver, nil, mMetricPointsDropped.M(int64(n)))
+}
+
+func record(receiver string, mutators []tag.Mutator, m stats.Measurement) {
+	mutators = append(mutators, tag.Upsert(tagReceiverKey, receiver))
+	_ = stats.RecordWithTags(context.Background(), mutators, m)
+}
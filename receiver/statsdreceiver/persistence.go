@@ -0,0 +1,109 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+)
+
+// stateFile returns the path of the file holding the aggregation state of
+// the receiver, or "" if the state is not persisted.
+func stateFile(config *Config) string {
+	if config.Persistence.Directory == "" {
+		return ""
+	}
+	// The name of the receiver, e.g. statsd/custom, is unique in the
+	// configuration.
+	name := strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(config.Name())
+	return filepath.Join(config.Persistence.Directory, name+".json")
+}
+
+// restoreState restores the aggregation state saved on the last shutdown, if
+// any, and removes it so that it is not restored twice. It returns whether a
+// state was restored. The state lost to an invalid file is only logged, not to
+// prevent the receiver from starting.
+func (r *statsdReceiver) restoreState() bool {
+	path := stateFile(r.config)
+	parser, ok := r.parser.(protocol.StatefulParser)
+	if path == "" || !ok {
+		return false
+	}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return false
+	}
+	if err != nil {
+		r.logger.Warn("Failed to open the aggregation state", zap.String("path", path), zap.Error(err))
+		return false
+	}
+	err = parser.RestoreState(f)
+	f.Close()
+	if err != nil {
+		r.logger.Warn("Failed to restore the aggregation state", zap.String("path", path), zap.Error(err))
+	}
+	if removeErr := os.Remove(path); removeErr != nil {
+		r.logger.Warn("Failed to remove the aggregation state", zap.String("path", path), zap.Error(removeErr))
+	}
+	return err == nil
+}
+
+// saveState saves the aggregation state, returning whether it was saved. The
+// state is written to a temporary file renamed once complete, so that a
+// partial state is never restored.
+func (r *statsdReceiver) saveState() bool {
+	path := stateFile(r.config)
+	parser, ok := r.parser.(protocol.StatefulParser)
+	if path == "" || !ok {
+		return false
+	}
+
+	err := os.MkdirAll(filepath.Dir(path), 0700)
+	if err == nil {
+		err = writeState(path, parser)
+	}
+	if err != nil {
+		r.logger.Warn("Failed to save the aggregation state, flushing it", zap.String("path", path), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+func writeState(path string, parser protocol.StatefulParser) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if err = parser.SaveState(f); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,342 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ObserverType defines how the observations of distribution metrics are
+// aggregated during an interval.
+type ObserverType string
+
+const (
+	// HistogramObserver aggregates observations into an explicit bucket histogram.
+	HistogramObserver ObserverType = "histogram"
+	// SummaryObserver aggregates observations into quantile gauges, plus
+	// cumulative count and sum metrics.
+	SummaryObserver ObserverType = "summary"
+
+	quantileLabelKey = "quantile"
+)
+
+var (
+	defaultHistogramBounds = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+	summaryQuantiles       = []float64{0.5, 0.9, 0.95, 0.99}
+)
+
+// HistogramConfig defines the bucket bounds of the histograms aggregated from
+// the timer, histogram and distribution metrics.
+type HistogramConfig struct {
+	// Bounds are the upper bounds of the buckets of the metrics not matching
+	// any override, in increasing order. The default bounds are used if not
+	// set.
+	Bounds []float64 `mapstructure:"bounds"`
+
+	// Overrides define the bounds of the metrics whose name starts with a
+	// prefix, the first override matching the name of a metric being
+	// applied.
+	Overrides []HistogramOverrideConfig `mapstructure:"overrides"`
+}
+
+// HistogramOverrideConfig defines the bucket bounds of the metrics whose name
+// starts with a prefix.
+type HistogramOverrideConfig struct {
+	// Prefix is matched against the start of the metric names.
+	Prefix string `mapstructure:"prefix"`
+
+	// Bounds are the upper bounds of the buckets, in increasing order.
+	Bounds []float64 `mapstructure:"bounds"`
+}
+
+// HistogramBounds selects the bucket bounds of the histograms by metric
+// name.
+type HistogramBounds struct {
+	bounds    []float64
+	overrides []HistogramOverrideConfig
+}
+
+// NewHistogramBounds validates the bounds of the histogram configuration.
+func NewHistogramBounds(config HistogramConfig) (*HistogramBounds, error) {
+	h := &HistogramBounds{
+		bounds:    defaultHistogramBounds,
+		overrides: config.Overrides,
+	}
+	if len(config.Bounds) > 0 {
+		if err := validateBounds(config.Bounds); err != nil {
+			return nil, err
+		}
+		h.bounds = config.Bounds
+	}
+	for i, override := range config.Overrides {
+		if override.Prefix == "" {
+			return nil, fmt.Errorf("override %d: prefix must be set", i)
+		}
+		if len(override.Bounds) == 0 {
+			return nil, fmt.Errorf("override %d: bounds must be set", i)
+		}
+		if err := validateBounds(override.Bounds); err != nil {
+			return nil, fmt.Errorf("override %d: %w", i, err)
+		}
+	}
+	return h, nil
+}
+
+func validateBounds(bounds []float64) error {
+	for i := 1; i < len(bounds); i++ {
+		// Also rejects the NaN bounds.
+		if !(bounds[i] > bounds[i-1]) {
+			return fmt.Errorf("bounds must be strictly increasing: %v", bounds)
+		}
+	}
+	return nil
+}
+
+// forName returns the bucket bounds of the histograms of a metric, the
+// default bounds if h is nil.
+func (h *HistogramBounds) forName(name string) []float64 {
+	if h == nil {
+		return defaultHistogramBounds
+	}
+	for _, override := range h.overrides {
+		if strings.HasPrefix(name, override.Prefix) {
+			return override.Bounds
+		}
+	}
+	return h.bounds
+}
+
+// distributionAggregation holds the observations of a distribution series
+// for the current aggregation interval.
+type distributionAggregation struct {
+	observerType ObserverType
+	count        float64
+	sum          float64
+
+	// Only used by HistogramObserver.
+	bounds       []float64
+	bucketCounts []float64
+
+	// Only used by SummaryObserver.
+	observations []observation
+}
+
+type observation struct {
+	value  float64
+	weight float64
+}
+
+func newDistributionAggregation(observerType ObserverType, bounds []float64) *distributionAggregation {
+	d := &distributionAggregation{
+		observerType: observerType,
+	}
+	if observerType == HistogramObserver {
+		d.bounds = bounds
+		d.bucketCounts = make([]float64, len(d.bounds)+1)
+	}
+	return d
+}
+
+// observe records a value, weighted by the inverse of its sample rate.
+func (d *distributionAggregation) observe(value float64, sampleRate float64) {
+	weight := 1.0
+	if sampleRate > 0 && sampleRate < 1 {
+		weight = 1 / sampleRate
+	}
+
+	d.count += weight
+	d.sum += value * weight
+
+	switch d.observerType {
+	case HistogramObserver:
+		// The buckets include their lower bound as in OpenCensus and OTLP: a
+		// value equal to a bound goes to the bucket starting at this bound.
+		i := sort.Search(len(d.bounds), func(i int) bool { return d.bounds[i] > value })
+		d.bucketCounts[i] += weight
+	case SummaryObserver:
+		d.observations = append(d.observations, observation{value: value, weight: weight})
+	}
+}
+
+func (d *distributionAggregation) buildMetrics(
+	name string,
+	labelKeys []*metricspb.LabelKey,
+	labelValues []*metricspb.LabelValue,
+	start, now *timestamppb.Timestamp,
+) []*metricspb.Metric {
+	if d.observerType == SummaryObserver {
+		return d.buildSummaryMetrics(name, labelKeys, labelValues, start, now)
+	}
+
+	// The cumulative counts are rounded rather than each bucket, so that the
+	// buckets of the sampled values sum to the count.
+	buckets := make([]*metricspb.DistributionValue_Bucket, len(d.bucketCounts))
+	var cumulative float64
+	var count int64
+	for i, bucketCount := range d.bucketCounts {
+		cumulative += bucketCount
+		rounded := round(cumulative)
+		buckets[i] = &metricspb.DistributionValue_Bucket{
+			Count: rounded - count,
+		}
+		count = rounded
+	}
+
+	return []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      name,
+				Type:      metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION,
+				LabelKeys: labelKeys,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					StartTimestamp: start,
+					LabelValues:    labelValues,
+					Points: []*metricspb.Point{
+						{
+							Timestamp: now,
+							Value: &metricspb.Point_DistributionValue{
+								DistributionValue: &metricspb.DistributionValue{
+									Count: count,
+									Sum:   d.sum,
+									BucketOptions: &metricspb.DistributionValue_BucketOptions{
+										Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+											Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+												Bounds: d.bounds,
+											},
+										},
+									},
+									Buckets: buckets,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// buildSummaryMetrics reports the quantiles as a gauge with an additional
+// "quantile" label, since summaries are not supported by the internal metrics
+// representation of the Collector.
+func (d *distributionAggregation) buildSummaryMetrics(
+	name string,
+	labelKeys []*metricspb.LabelKey,
+	labelValues []*metricspb.LabelValue,
+	start, now *timestamppb.Timestamp,
+) []*metricspb.Metric {
+	sort.Slice(d.observations, func(i, j int) bool {
+		return d.observations[i].value < d.observations[j].value
+	})
+
+	quantileLabelKeys := make([]*metricspb.LabelKey, 0, len(labelKeys)+1)
+	quantileLabelKeys = append(quantileLabelKeys, labelKeys...)
+	quantileLabelKeys = append(quantileLabelKeys, &metricspb.LabelKey{Key: quantileLabelKey})
+
+	timeseries := make([]*metricspb.TimeSeries, 0, len(summaryQuantiles))
+	for _, q := range summaryQuantiles {
+		quantileLabelValues := make([]*metricspb.LabelValue, 0, len(labelValues)+1)
+		quantileLabelValues = append(quantileLabelValues, labelValues...)
+		quantileLabelValues = append(quantileLabelValues, &metricspb.LabelValue{
+			Value:    strconv.FormatFloat(q, 'f', -1, 64),
+			HasValue: true,
+		})
+		timeseries = append(timeseries, &metricspb.TimeSeries{
+			LabelValues: quantileLabelValues,
+			Points: []*metricspb.Point{
+				{
+					Timestamp: now,
+					Value:     &metricspb.Point_DoubleValue{DoubleValue: d.quantile(q)},
+				},
+			},
+		})
+	}
+
+	return []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      name,
+				Type:      metricspb.MetricDescriptor_GAUGE_DOUBLE,
+				LabelKeys: quantileLabelKeys,
+			},
+			Timeseries: timeseries,
+		},
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      name + ".count",
+				Type:      metricspb.MetricDescriptor_CUMULATIVE_INT64,
+				LabelKeys: labelKeys,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					StartTimestamp: start,
+					LabelValues:    labelValues,
+					Points: []*metricspb.Point{
+						{
+							Timestamp: now,
+							Value:     &metricspb.Point_Int64Value{Int64Value: round(d.count)},
+						},
+					},
+				},
+			},
+		},
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      name + ".sum",
+				Type:      metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+				LabelKeys: labelKeys,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					StartTimestamp: start,
+					LabelValues:    labelValues,
+					Points: []*metricspb.Point{
+						{
+							Timestamp: now,
+							Value:     &metricspb.Point_DoubleValue{DoubleValue: d.sum},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// quantile returns the smallest observed value whose cumulative weight is at
+// least q of the total weight. The observations must be sorted.
+func (d *distributionAggregation) quantile(q float64) float64 {
+	target := q * d.count
+	var cumulative float64
+	for _, o := range d.observations {
+		cumulative += o.weight
+		if cumulative >= target {
+			return o.value
+		}
+	}
+	return d.observations[len(d.observations)-1].value
+}
+
+func round(f float64) int64 {
+	return int64(math.Round(f))
+}
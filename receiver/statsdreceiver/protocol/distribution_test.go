@@ -0,0 +1,265 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"strconv"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_StatsDParser_AggregateDistributionAsHistogram(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 0
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	p := &StatsDParser{}
+	for _, line := range []string{
+		"test.distribution:1|d|#key:value",
+		"test.distribution:7.5|d|#key:value",
+		"test.distribution:20000|d|@0.5|#key:value",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	buckets := make([]*metricspb.DistributionValue_Bucket, len(defaultHistogramBounds)+1)
+	for i := range buckets {
+		buckets[i] = &metricspb.DistributionValue_Bucket{}
+	}
+	// 1 is the lower bound of the second bucket
+	buckets[1].Count = 1
+	buckets[3].Count = 1
+	buckets[len(buckets)-1].Count = 2
+
+	assert.Equal(t, []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      "test.distribution",
+				Type:      metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION,
+				LabelKeys: []*metricspb.LabelKey{{Key: "key"}},
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					StartTimestamp: &timestamppb.Timestamp{},
+					LabelValues:    []*metricspb.LabelValue{{Value: "value", HasValue: true}},
+					Points: []*metricspb.Point{
+						{
+							Timestamp: &timestamppb.Timestamp{},
+							Value: &metricspb.Point_DistributionValue{
+								DistributionValue: &metricspb.DistributionValue{
+									Count: 4,
+									Sum:   40008.5,
+									BucketOptions: &metricspb.DistributionValue_BucketOptions{
+										Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+											Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+												Bounds: defaultHistogramBounds,
+											},
+										},
+									},
+									Buckets: buckets,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, p.GetMetrics())
+}
+
+func Test_StatsDParser_AggregateDistributionAsSummary(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 0
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	p := &StatsDParser{DistributionObserver: SummaryObserver}
+	for i := 100; i > 0; i-- {
+		require.NoError(t, p.Aggregate("test.distribution:"+strconv.Itoa(i)+"|d", nil))
+	}
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 3)
+
+	quantiles := metrics[0]
+	assert.Equal(t, "test.distribution", quantiles.GetMetricDescriptor().GetName())
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, quantiles.GetMetricDescriptor().GetType())
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "quantile"}}, quantiles.GetMetricDescriptor().GetLabelKeys())
+	require.Len(t, quantiles.GetTimeseries(), len(summaryQuantiles))
+	expected := map[string]float64{"0.5": 50, "0.9": 90, "0.95": 95, "0.99": 99}
+	for _, ts := range quantiles.GetTimeseries() {
+		q := ts.GetLabelValues()[0].GetValue()
+		assert.Equal(t, expected[q], ts.GetPoints()[0].GetDoubleValue(), "quantile %s", q)
+	}
+
+	count := metrics[1]
+	assert.Equal(t, "test.distribution.count", count.GetMetricDescriptor().GetName())
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, count.GetMetricDescriptor().GetType())
+	assert.Equal(t, int64(100), count.GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+
+	sum := metrics[2]
+	assert.Equal(t, "test.distribution.sum", sum.GetMetricDescriptor().GetName())
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DOUBLE, sum.GetMetricDescriptor().GetType())
+	assert.Equal(t, float64(5050), sum.GetTimeseries()[0].GetPoints()[0].GetDoubleValue())
+}
+
+func TestNewHistogramBoundsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config HistogramConfig
+		err    string
+	}{
+		{
+			name:   "decreasing bounds",
+			config: HistogramConfig{Bounds: []float64{1, 5, 5}},
+			err:    "bounds must be strictly increasing: [1 5 5]",
+		},
+		{
+			name:   "override without prefix",
+			config: HistogramConfig{Overrides: []HistogramOverrideConfig{{Bounds: []float64{1}}}},
+			err:    "override 0: prefix must be set",
+		},
+		{
+			name:   "override without bounds",
+			config: HistogramConfig{Overrides: []HistogramOverrideConfig{{Prefix: "api."}}},
+			err:    "override 0: bounds must be set",
+		},
+		{
+			name: "invalid override bounds",
+			config: HistogramConfig{Overrides: []HistogramOverrideConfig{
+				{Prefix: "api.", Bounds: []float64{1}},
+				{Prefix: "db.", Bounds: []float64{2, 1}},
+			}},
+			err: "override 1: bounds must be strictly increasing: [2 1]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHistogramBounds(tt.config)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func Test_StatsDParser_AggregateTimersWithHistogramBounds(t *testing.T) {
+	histogramBounds, err := NewHistogramBounds(HistogramConfig{
+		Bounds: []float64{10, 100},
+		Overrides: []HistogramOverrideConfig{
+			{Prefix: "checkout.", Bounds: []float64{250, 500, 1000}},
+			{Prefix: "checkout.payment.", Bounds: []float64{1}},
+		},
+	})
+	require.NoError(t, err)
+
+	p := &StatsDParser{HistogramBounds: histogramBounds}
+	for _, line := range []string{
+		"api.latency:42|ms",
+		"api.latency:10|ms",
+		"checkout.payment.latency:300|ms|@0.5",
+		"checkout.payment.latency:2000|ms",
+		"checkout.payment.latency:1000|ms",
+		"queue.size:7|h",
+		"queue.size:100|h",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 3)
+	tests := []struct {
+		name         string
+		bounds       []float64
+		bucketCounts []int64
+		count        int64
+	}{
+		{
+			name:         "api.latency",
+			bounds:       []float64{10, 100},
+			bucketCounts: []int64{0, 2, 0},
+			count:        2,
+		},
+		{
+			// The first matching override applies.
+			name:         "checkout.payment.latency",
+			bounds:       []float64{250, 500, 1000},
+			bucketCounts: []int64{0, 2, 0, 2},
+			count:        4,
+		},
+		{
+			// A value equal to a bound is in the bucket starting at the bound.
+			name:         "queue.size",
+			bounds:       []float64{10, 100},
+			bucketCounts: []int64{1, 0, 1},
+			count:        2,
+		},
+	}
+	for i, tt := range tests {
+		metric := metrics[i]
+		assert.Equal(t, tt.name, metric.GetMetricDescriptor().GetName())
+		assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION, metric.GetMetricDescriptor().GetType())
+		distribution := metric.GetTimeseries()[0].GetPoints()[0].GetDistributionValue()
+		assert.Equal(t, tt.bounds, distribution.GetBucketOptions().GetExplicit().GetBounds(), tt.name)
+		bucketCounts := make([]int64, 0, len(distribution.GetBuckets()))
+		for _, bucket := range distribution.GetBuckets() {
+			bucketCounts = append(bucketCounts, bucket.GetCount())
+		}
+		assert.Equal(t, tt.bucketCounts, bucketCounts, tt.name)
+		assert.Equal(t, tt.count, distribution.GetCount(), tt.name)
+	}
+}
+
+func Test_StatsDParser_AggregateSampledTimersBucketsSumToCount(t *testing.T) {
+	histogramBounds, err := NewHistogramBounds(HistogramConfig{Bounds: []float64{10, 100}})
+	require.NoError(t, err)
+
+	p := &StatsDParser{HistogramBounds: histogramBounds}
+	for _, line := range []string{
+		"api.latency:5|ms|@0.4",
+		"api.latency:50|ms|@0.4",
+		"api.latency:500|ms|@0.4",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 1)
+	distribution := metrics[0].GetTimeseries()[0].GetPoints()[0].GetDistributionValue()
+	// Each bucket holds 2.5 observations, the cumulative counts 2.5, 5 and
+	// 7.5 are rounded to 3, 5 and 8.
+	bucketCounts := make([]int64, 0, len(distribution.GetBuckets()))
+	var sum int64
+	for _, bucket := range distribution.GetBuckets() {
+		bucketCounts = append(bucketCounts, bucket.GetCount())
+		sum += bucket.GetCount()
+	}
+	assert.Equal(t, []int64{3, 2, 3}, bucketCounts)
+	assert.Equal(t, int64(8), distribution.GetCount())
+	assert.Equal(t, distribution.GetCount(), sum)
+}
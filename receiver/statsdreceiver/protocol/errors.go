@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+)
+
+// ParseErrorReason classifies the StatsD lines which can't be parsed.
+type ParseErrorReason string
+
+const (
+	// ReasonInvalidFormat is used for the lines not in the
+	// <name>:<value>|<type> format.
+	ReasonInvalidFormat ParseErrorReason = "invalid_format"
+	// ReasonEmptyName is used for the lines without a metric name.
+	ReasonEmptyName ParseErrorReason = "empty_name"
+	// ReasonEmptyValue is used for the lines without a metric value.
+	ReasonEmptyValue ParseErrorReason = "empty_value"
+	// ReasonInvalidValue is used for the lines whose value isn't a number.
+	ReasonInvalidValue ParseErrorReason = "invalid_value"
+	// ReasonUnsupportedType is used for the lines of an unsupported metric
+	// type.
+	ReasonUnsupportedType ParseErrorReason = "unsupported_type"
+	// ReasonInvalidSampleRate is used for the lines whose sample rate isn't a
+	// number.
+	ReasonInvalidSampleRate ParseErrorReason = "invalid_sample_rate"
+	// ReasonInvalidTag is used for the lines with a tag not in the key:value
+	// format.
+	ReasonInvalidTag ParseErrorReason = "invalid_tag"
+	// ReasonInvalidTimestamp is used for the lines whose timestamp isn't a
+	// positive number of seconds.
+	ReasonInvalidTimestamp ParseErrorReason = "invalid_timestamp"
+	// ReasonUnrecognizedPart is used for the lines with an unknown part.
+	ReasonUnrecognizedPart ParseErrorReason = "unrecognized_part"
+)
+
+// ParseError is the error returned by the StatsDParser for the lines which
+// can't be parsed.
+type ParseError struct {
+	// Reason is the reason the line can't be parsed.
+	Reason ParseErrorReason
+
+	msg string
+}
+
+var _ error = (*ParseError)(nil)
+
+func newParseError(reason ParseErrorReason, format string, args ...interface{}) *ParseError {
+	return &ParseError{Reason: reason, msg: fmt.Sprintf(format, args...)}
+}
+
+func (e *ParseError) Error() string {
+	return e.msg
+}
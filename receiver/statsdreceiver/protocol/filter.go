@@ -0,0 +1,86 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FilterConfig defines the metrics dropped when they are parsed, before being
+// aggregated, by name. The patterns are globs whose "*" matches a single
+// non-empty dot-separated component of the name, which match the metric
+// names they match and the names they are a dot-separated prefix of.
+type FilterConfig struct {
+	// Allow lists the patterns of the metrics kept, all the metrics being
+	// kept if empty.
+	Allow []string `mapstructure:"allow"`
+
+	// Deny lists the patterns of the metrics dropped, even if allowed.
+	Deny []string `mapstructure:"deny"`
+}
+
+// Filter drops the metrics not allowed or denied by their name, before the
+// mappings are applied.
+type Filter struct {
+	allow *regexp.Regexp
+	deny  *regexp.Regexp
+}
+
+// NewFilter compiles the patterns of the filter.
+func NewFilter(config FilterConfig) (*Filter, error) {
+	allow, err := compileFilterPatterns("allow", config.Allow)
+	if err != nil {
+		return nil, err
+	}
+	deny, err := compileFilterPatterns("deny", config.Deny)
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{allow: allow, deny: deny}, nil
+}
+
+// compileFilterPatterns compiles the patterns into a single regex matching
+// the names matched by any of them, nil if there is no pattern.
+func compileFilterPatterns(list string, patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	exprs := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		if pattern == "" {
+			return nil, fmt.Errorf("%s %d: pattern must be set", list, i)
+		}
+		parts := strings.Split(pattern, "*")
+		for j, part := range parts {
+			parts[j] = regexp.QuoteMeta(part)
+		}
+		exprs[i] = strings.Join(parts, "[^.]+")
+	}
+	return regexp.Compile(`^(?:` + strings.Join(exprs, "|") + `)(?:\.|$)`)
+}
+
+// keep reports whether the metric of the given name is kept.
+func (f *Filter) keep(name string) bool {
+	if f == nil {
+		return true
+	}
+	if f.allow != nil && !f.allow.MatchString(name) {
+		return false
+	}
+	return f.deny == nil || !f.deny.MatchString(name)
+}
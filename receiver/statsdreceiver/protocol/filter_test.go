@@ -0,0 +1,102 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFilterErrors(t *testing.T) {
+	f, err := NewFilter(FilterConfig{Allow: []string{"api"}, Deny: []string{"api.debug", ""}})
+	assert.EqualError(t, err, "deny 1: pattern must be set")
+	assert.Nil(t, f)
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		config FilterConfig
+		keep   []string
+		drop   []string
+	}{
+		{
+			name: "no patterns",
+			keep: []string{"api.requests", "internal.gc.pause"},
+		},
+		{
+			name:   "deny prefix",
+			config: FilterConfig{Deny: []string{"internal", "*.debug"}},
+			keep:   []string{"api.requests", "internals.count", "debug.count", "api.debugging"},
+			drop:   []string{"internal", "internal.gc.pause", "api.debug", "api.debug.count"},
+		},
+		{
+			name:   "allow prefix",
+			config: FilterConfig{Allow: []string{"api.*.count", "jobs"}},
+			keep:   []string{"api.payments.count", "api.payments.count.total", "jobs.backup.duration"},
+			drop:   []string{"api.count", "api.payments.counter", "api..count", "internal.jobs"},
+		},
+		{
+			name:   "deny overrides allow",
+			config: FilterConfig{Allow: []string{"api"}, Deny: []string{"api.health"}},
+			keep:   []string{"api.requests"},
+			drop:   []string{"api.health.count", "jobs.count"},
+		},
+		{
+			name:   "literal patterns",
+			config: FilterConfig{Deny: []string{"a+b.c"}},
+			keep:   []string{"aab.c", "a+bxc"},
+			drop:   []string{"a+b.c"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.config)
+			require.NoError(t, err)
+			for _, name := range tt.keep {
+				assert.True(t, f.keep(name), name)
+			}
+			for _, name := range tt.drop {
+				assert.False(t, f.keep(name), name)
+			}
+		})
+	}
+}
+
+func TestNilFilter(t *testing.T) {
+	var f *Filter
+	assert.True(t, f.keep("api.requests"))
+}
+
+func TestStatsDParserFilter(t *testing.T) {
+	f, err := NewFilter(FilterConfig{Deny: []string{"internal"}})
+	require.NoError(t, err)
+	m, err := NewMapper([]MappingConfig{{Match: "api.*", Name: "internal.$1"}})
+	require.NoError(t, err)
+
+	p := &StatsDParser{Filter: f, Mapper: m}
+	require.NoError(t, p.Aggregate("internal.gc.pause:12|ms", nil))
+	require.NoError(t, p.Aggregate("internal,host=h1:1|c", nil))
+	require.NoError(t, p.Aggregate("internal.invalid:abc|c", nil))
+	// The names are filtered before the mappings are applied.
+	require.NoError(t, p.Aggregate("api.requests:1|c", nil))
+	assert.Equal(t, errEmptyMetricName, p.Aggregate(":1|c", nil))
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "internal.requests", metrics[0].MetricDescriptor.Name)
+}
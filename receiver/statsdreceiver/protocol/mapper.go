@@ -0,0 +1,156 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// MatchType is the type of the patterns of the mappings.
+type MatchType string
+
+const (
+	// GlobMatch patterns match the dot-separated components of the metric
+	// names, each "*" matching a single non-empty component.
+	GlobMatch MatchType = "glob"
+	// RegexMatch patterns are regular expressions matching the whole metric
+	// name.
+	RegexMatch MatchType = "regex"
+)
+
+// MappingConfig defines a rule renaming the metrics matching a pattern and
+// extracting labels from their name, in the manner of the Prometheus
+// statsd_exporter.
+type MappingConfig struct {
+	// Match is the pattern matched against the metric names.
+	Match string `mapstructure:"match"`
+
+	// MatchType is the type of the pattern, "glob" (the default) or "regex".
+	MatchType MatchType `mapstructure:"match_type"`
+
+	// Name is the new name of the matching metrics. It can reference the
+	// components matched by the wildcards of a glob or the groups of a regex,
+	// e.g. "$1" or "${name}".
+	Name string `mapstructure:"name"`
+
+	// Labels are the labels added to the matching metrics, their values can
+	// reference the matched components or groups like Name. They override
+	// the tags of the same key.
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+// Mapper renames the metrics according to the first mapping matching their
+// name. The metrics not matching any mapping are left unchanged.
+type Mapper struct {
+	mappings []*mapping
+}
+
+type mapping struct {
+	regex     *regexp.Regexp
+	name      string
+	labelKeys []string
+	labels    map[string]string
+}
+
+// NewMapper compiles the mappings.
+func NewMapper(configs []MappingConfig) (*Mapper, error) {
+	m := &Mapper{}
+	for i, cfg := range configs {
+		if cfg.Match == "" {
+			return nil, fmt.Errorf("mapping %d: match must be set", i)
+		}
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("mapping %d: name must be set", i)
+		}
+
+		var expr string
+		switch cfg.MatchType {
+		case "", GlobMatch:
+			expr = globToRegex(cfg.Match)
+		case RegexMatch:
+			expr = "^(?:" + cfg.Match + ")$"
+		default:
+			return nil, fmt.Errorf("mapping %d: unsupported match_type %q", i, cfg.MatchType)
+		}
+		regex, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("mapping %d: invalid match %q: %w", i, cfg.Match, err)
+		}
+
+		labelKeys := make([]string, 0, len(cfg.Labels))
+		for key := range cfg.Labels {
+			labelKeys = append(labelKeys, key)
+		}
+		sort.Strings(labelKeys)
+
+		m.mappings = append(m.mappings, &mapping{
+			regex:     regex,
+			name:      cfg.Name,
+			labelKeys: labelKeys,
+			labels:    cfg.Labels,
+		})
+	}
+	return m, nil
+}
+
+func globToRegex(glob string) string {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return "^" + strings.Join(parts, "([^.]+)") + "$"
+}
+
+// apply renames the metric and adds the labels of the first matching
+// mapping.
+func (m *Mapper) apply(metric *statsDMetric) {
+	if m == nil {
+		return
+	}
+
+	for _, mapping := range m.mappings {
+		match := mapping.regex.FindStringSubmatchIndex(metric.name)
+		if match == nil {
+			continue
+		}
+
+		name := string(mapping.regex.ExpandString(nil, mapping.name, metric.name, match))
+		for _, key := range mapping.labelKeys {
+			value := string(mapping.regex.ExpandString(nil, mapping.labels[key], metric.name, match))
+			metric.setLabel(key, value)
+		}
+		metric.name = name
+		sort.Sort(byLabelKey(*metric))
+		return
+	}
+}
+
+// setLabel sets the value of the label, adding it if needed.
+func (m *statsDMetric) setLabel(key, value string) {
+	labelValue := &metricspb.LabelValue{Value: value, HasValue: true}
+	for i, labelKey := range m.labelKeys {
+		if labelKey.Key == key {
+			m.labelValues[i] = labelValue
+			return
+		}
+	}
+	m.labelKeys = append(m.labelKeys, &metricspb.LabelKey{Key: key})
+	m.labelValues = append(m.labelValues, labelValue)
+}
@@ -0,0 +1,180 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"sort"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMapperErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  MappingConfig
+		wantErr string
+	}{
+		{
+			name:    "no match",
+			config:  MappingConfig{Name: "requests"},
+			wantErr: "mapping 0: match must be set",
+		},
+		{
+			name:    "no name",
+			config:  MappingConfig{Match: "api.*"},
+			wantErr: "mapping 0: name must be set",
+		},
+		{
+			name:    "unsupported match type",
+			config:  MappingConfig{Match: "api.*", MatchType: "prefix", Name: "requests"},
+			wantErr: `mapping 0: unsupported match_type "prefix"`,
+		},
+		{
+			name:    "invalid regex",
+			config:  MappingConfig{Match: "api.(", MatchType: RegexMatch, Name: "requests"},
+			wantErr: "mapping 0: invalid match \"api.(\": error parsing regexp: missing closing ): `^(?:api.()$`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMapper([]MappingConfig{tt.config})
+			assert.EqualError(t, err, tt.wantErr)
+			assert.Nil(t, m)
+		})
+	}
+}
+
+func TestMapper(t *testing.T) {
+	m, err := NewMapper([]MappingConfig{
+		{
+			Match: "api.*.*.count",
+			Name:  "api_requests",
+			Labels: map[string]string{
+				"service": "$1",
+				"code":    "$2",
+			},
+		},
+		{
+			Match:     `jobs\.(?P<job>[a-z]+)\.duration`,
+			MatchType: RegexMatch,
+			Name:      "job_${job}_duration",
+			Labels: map[string]string{
+				"env": "production",
+			},
+		},
+		{
+			Match: "api.*.*.*",
+			Name:  "never_applied",
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		input      string
+		wantName   string
+		wantLabels map[string]string
+	}{
+		{
+			name:     "glob",
+			input:    "api.payments.200.count:1|c",
+			wantName: "api_requests",
+			wantLabels: map[string]string{
+				"code":    "200",
+				"service": "payments",
+			},
+		},
+		{
+			name:     "glob with tags",
+			input:    "api.payments.500.count:1|c|#host:h1,code:override",
+			wantName: "api_requests",
+			wantLabels: map[string]string{
+				"code":    "500",
+				"host":    "h1",
+				"service": "payments",
+			},
+		},
+		{
+			name:       "glob components are not empty",
+			input:      "api..200.count:1|c",
+			wantName:   "api..200.count",
+			wantLabels: map[string]string{},
+		},
+		{
+			name:     "regex",
+			input:    "jobs.backup.duration:12|g",
+			wantName: "job_backup_duration",
+			wantLabels: map[string]string{
+				"env": "production",
+			},
+		},
+		{
+			name:       "regex matches the whole name",
+			input:      "batch.jobs.backup.duration:12|g",
+			wantName:   "batch.jobs.backup.duration",
+			wantLabels: map[string]string{},
+		},
+		{
+			name:       "no match",
+			input:      "test.metric:1|c|#key:value",
+			wantName:   "test.metric",
+			wantLabels: map[string]string{"key": "value"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric, err := parseMessageToMetric(tt.input)
+			require.NoError(t, err)
+
+			m.apply(metric)
+			assert.Equal(t, tt.wantName, metric.name)
+			labels := make(map[string]string)
+			var keys []string
+			for i, key := range metric.labelKeys {
+				keys = append(keys, key.Key)
+				labels[key.Key] = metric.labelValues[i].Value
+			}
+			assert.Equal(t, tt.wantLabels, labels)
+			assert.True(t, sort.StringsAreSorted(keys))
+		})
+	}
+}
+
+func TestStatsDParserMapper(t *testing.T) {
+	m, err := NewMapper([]MappingConfig{
+		{Match: "api.*.*.count", Name: "api_requests", Labels: map[string]string{"service": "$1", "code": "$2"}},
+	})
+	require.NoError(t, err)
+
+	p := &StatsDParser{Mapper: m}
+	require.NoError(t, p.Aggregate("api.payments.200.count:1|c", nil))
+	require.NoError(t, p.Aggregate("api.payments.200.count:2|c", nil))
+	require.NoError(t, p.Aggregate("api.orders.200.count:4|c", nil))
+	assert.Equal(t, errEmptyMetricValue, p.Aggregate("api.orders.200.count:|c", nil))
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 2)
+	for _, metric := range metrics {
+		assert.Equal(t, "api_requests", metric.MetricDescriptor.Name)
+		assert.Equal(t, []*metricspb.LabelKey{{Key: "code"}, {Key: "service"}}, metric.MetricDescriptor.LabelKeys)
+	}
+	assert.Equal(t, "payments", metrics[0].Timeseries[0].LabelValues[1].Value)
+	assert.Equal(t, int64(3), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "orders", metrics[1].Timeseries[0].LabelValues[1].Value)
+	assert.Equal(t, int64(4), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+}
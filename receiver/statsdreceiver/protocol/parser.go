@@ -15,10 +15,34 @@
 package protocol
 
 import (
+	"io"
+	"net"
+
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 )
 
-// Parser is something that can map input StatsD strings to OTLP Metric representations.
+// Parser is something that can aggregate input StatsD strings and map the
+// aggregated values to OpenCensus Metric representations.
 type Parser interface {
-	Parse(in string) (*metricspb.Metric, error)
+	// Aggregate parses a single StatsD line, sent by the client at addr, and
+	// merges it into the current aggregation state of the parser.
+	Aggregate(line string, addr net.Addr) error
+
+	// GetMetrics returns the metrics aggregated since the previous call and
+	// resets the aggregation state.
+	GetMetrics() []*metricspb.Metric
+}
+
+// StatefulParser is a Parser whose aggregation state can be saved, so that
+// the metrics aggregated before a restart are reported with those aggregated
+// after it.
+type StatefulParser interface {
+	Parser
+
+	// SaveState writes the current aggregation state.
+	SaveState(w io.Writer) error
+
+	// RestoreState replaces the aggregation state by the one written by
+	// SaveState.
+	RestoreState(r io.Reader) error
 }
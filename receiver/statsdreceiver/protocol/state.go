@@ -0,0 +1,195 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// stateVersion is the version of the format of the saved aggregation state,
+// increased whenever the format changes.
+const stateVersion = 1
+
+var _ StatefulParser = (*StatsDParser)(nil)
+
+// savedState is the aggregation state of a StatsDParser as saved by
+// SaveState.
+type savedState struct {
+	Version       int           `json:"version"`
+	IntervalStart int64         `json:"interval_start"`
+	Metrics       []savedMetric `json:"metrics"`
+	Gauges        []savedMetric `json:"gauges,omitempty"`
+}
+
+type savedMetric struct {
+	Name           string             `json:"name"`
+	Type           string             `json:"type"`
+	Timestamp      int64              `json:"timestamp,omitempty"`
+	LabelKeys      []string           `json:"label_keys,omitempty"`
+	LabelValues    []string           `json:"label_values,omitempty"`
+	IsDouble       bool               `json:"is_double,omitempty"`
+	IntValue       int64              `json:"int_value,omitempty"`
+	ScaledIntValue float64            `json:"scaled_int_value,omitempty"`
+	DoubleValue    float64            `json:"double_value,omitempty"`
+	Distribution   *savedDistribution `json:"distribution,omitempty"`
+}
+
+type savedDistribution struct {
+	Observer     ObserverType `json:"observer"`
+	Count        float64      `json:"count"`
+	Sum          float64      `json:"sum"`
+	Bounds       []float64    `json:"bounds,omitempty"`
+	BucketCounts []float64    `json:"bucket_counts,omitempty"`
+	Observations [][2]float64 `json:"observations,omitempty"`
+}
+
+// SaveState writes the metrics aggregated during the current interval, and
+// the last values of the gauges, so that they can be restored by
+// RestoreState after a restart instead of being reported early.
+func (p *StatsDParser) SaveState(w io.Writer) error {
+	state := savedState{
+		Version:       stateVersion,
+		IntervalStart: p.intervalStart,
+		Metrics:       make([]savedMetric, 0, len(p.order)),
+	}
+	for _, description := range p.order {
+		state.Metrics = append(state.Metrics, p.metrics[description].save())
+	}
+	for _, gauge := range p.gauges {
+		state.Gauges = append(state.Gauges, gauge.save())
+	}
+	return json.NewEncoder(w).Encode(&state)
+}
+
+// RestoreState replaces the aggregation state of the parser by the state
+// written by SaveState, the aggregation interval continuing from the saved
+// start.
+func (p *StatsDParser) RestoreState(r io.Reader) error {
+	var state savedState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("invalid aggregation state: %w", err)
+	}
+	if state.Version != stateVersion {
+		return fmt.Errorf("unsupported aggregation state version %d", state.Version)
+	}
+
+	metrics := make(map[statsDMetricDescription]*aggregatedMetric, len(state.Metrics))
+	order := make([]statsDMetricDescription, 0, len(state.Metrics))
+	for _, saved := range state.Metrics {
+		description, aggregated, err := saved.restore()
+		if err != nil {
+			return err
+		}
+		if _, ok := metrics[description]; !ok {
+			order = append(order, description)
+		}
+		metrics[description] = aggregated
+	}
+	var gauges map[statsDMetricDescription]*aggregatedMetric
+	if len(state.Gauges) > 0 {
+		gauges = make(map[statsDMetricDescription]*aggregatedMetric, len(state.Gauges))
+	}
+	for _, saved := range state.Gauges {
+		description, aggregated, err := saved.restore()
+		if err != nil {
+			return err
+		}
+		gauges[description.series()] = aggregated
+	}
+
+	p.metrics = metrics
+	p.order = order
+	p.intervalStart = state.IntervalStart
+	p.gauges = gauges
+	return nil
+}
+
+func (a *aggregatedMetric) save() savedMetric {
+	saved := savedMetric{
+		Name:           a.name,
+		Type:           a.statsdMetricType,
+		Timestamp:      a.timestamp,
+		IsDouble:       a.isDouble,
+		IntValue:       a.intValue,
+		ScaledIntValue: a.scaledIntValue,
+		DoubleValue:    a.doubleValue,
+	}
+	for i, key := range a.labelKeys {
+		saved.LabelKeys = append(saved.LabelKeys, key.Key)
+		saved.LabelValues = append(saved.LabelValues, a.labelValues[i].Value)
+	}
+	if d := a.distribution; d != nil {
+		saved.Distribution = &savedDistribution{
+			Observer:     d.observerType,
+			Count:        d.count,
+			Sum:          d.sum,
+			Bounds:       d.bounds,
+			BucketCounts: d.bucketCounts,
+		}
+		for _, o := range d.observations {
+			saved.Distribution.Observations = append(saved.Distribution.Observations, [2]float64{o.value, o.weight})
+		}
+	}
+	return saved
+}
+
+func (s *savedMetric) restore() (statsDMetricDescription, *aggregatedMetric, error) {
+	if s.Name == "" || !contains(getSupportedTypes(), s.Type) || len(s.LabelKeys) != len(s.LabelValues) {
+		return statsDMetricDescription{}, nil, fmt.Errorf("invalid aggregated metric %q of type %q", s.Name, s.Type)
+	}
+	metric := statsDMetric{
+		name:             s.Name,
+		statsdMetricType: s.Type,
+		timestamp:        s.Timestamp,
+	}
+	for i, key := range s.LabelKeys {
+		metric.labelKeys = append(metric.labelKeys, &metricspb.LabelKey{Key: key})
+		metric.labelValues = append(metric.labelValues, &metricspb.LabelValue{Value: s.LabelValues[i], HasValue: true})
+	}
+	aggregated := &aggregatedMetric{
+		name:             metric.name,
+		statsdMetricType: metric.statsdMetricType,
+		timestamp:        metric.timestamp,
+		labelKeys:        metric.labelKeys,
+		labelValues:      metric.labelValues,
+		isDouble:         s.IsDouble,
+		intValue:         s.IntValue,
+		scaledIntValue:   s.ScaledIntValue,
+		doubleValue:      s.DoubleValue,
+	}
+	if d := s.Distribution; d != nil {
+		switch {
+		case d.Observer == HistogramObserver && len(d.BucketCounts) == len(d.Bounds)+1:
+		case d.Observer == SummaryObserver:
+		default:
+			return statsDMetricDescription{}, nil, fmt.Errorf("invalid distribution of the aggregated metric %q", s.Name)
+		}
+		aggregated.distribution = &distributionAggregation{
+			observerType: d.Observer,
+			count:        d.Count,
+			sum:          d.Sum,
+			bounds:       d.Bounds,
+			bucketCounts: d.BucketCounts,
+		}
+		for _, o := range d.Observations {
+			aggregated.distribution.observations = append(aggregated.distribution.observations, observation{value: o[0], weight: o[1]})
+		}
+	}
+	return metric.description(), aggregated, nil
+}
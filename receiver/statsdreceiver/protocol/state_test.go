@@ -0,0 +1,117 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StatsDParser_SaveState(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	now := int64(1000)
+	timeNowFunc = func() int64 {
+		return now
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	lines := []string{
+		"test.counter:42|c|@0.5|#env:prod,host:a",
+		"test.gauge:7.5|g",
+		"test.timer:320|ms",
+		"test.histogram:12|h|#env:prod",
+		"test.timestamped:3|c|T900",
+	}
+	newParsers := []func() *StatsDParser{
+		func() *StatsDParser { return &StatsDParser{} },
+		func() *StatsDParser { return &StatsDParser{DistributionObserver: SummaryObserver} },
+	}
+	for _, newParser := range newParsers {
+		// The gauge is flushed once, so that its last value is saved too.
+		saved := newParser()
+		require.NoError(t, saved.Aggregate("test.previous:10|g", nil))
+		saved.GetMetrics()
+		for _, line := range lines {
+			require.NoError(t, saved.Aggregate(line, nil))
+		}
+		expected := newParser()
+		require.NoError(t, expected.Aggregate("test.previous:10|g", nil))
+		expected.GetMetrics()
+		now = 1000
+		for _, line := range lines {
+			require.NoError(t, expected.Aggregate(line, nil))
+		}
+
+		var state bytes.Buffer
+		require.NoError(t, saved.SaveState(&state))
+		restored := newParser()
+		require.NoError(t, restored.RestoreState(&state))
+
+		// The aggregation goes on after the restart.
+		now = 1060
+		require.NoError(t, restored.Aggregate("test.counter:1|c|#host:a,env:prod", nil))
+		require.NoError(t, restored.Aggregate("test.previous:+1|g", nil))
+		require.NoError(t, expected.Aggregate("test.counter:1|c|#host:a,env:prod", nil))
+		require.NoError(t, expected.Aggregate("test.previous:+1|g", nil))
+		assert.Equal(t, expected.GetMetrics(), restored.GetMetrics())
+		now = 1000
+	}
+}
+
+func Test_StatsDParser_RestoreStateInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		state string
+	}{
+		{
+			name:  "not json",
+			state: "test.counter:42|c",
+		},
+		{
+			name:  "unsupported version",
+			state: `{"version":2,"interval_start":1000,"metrics":[]}`,
+		},
+		{
+			name:  "unsupported type",
+			state: `{"version":1,"interval_start":1000,"metrics":[{"name":"test.set","type":"s"}]}`,
+		},
+		{
+			name:  "missing label values",
+			state: `{"version":1,"interval_start":1000,"metrics":[{"name":"test.counter","type":"c","label_keys":["env"]}]}`,
+		},
+		{
+			name: "invalid histogram",
+			state: `{"version":1,"interval_start":1000,"metrics":[{"name":"test.timer","type":"ms",` +
+				`"distribution":{"observer":"histogram","count":1,"sum":1,"bounds":[1,2],"bucket_counts":[1]}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &StatsDParser{}
+			require.NoError(t, p.Aggregate("test.counter:42|c", nil))
+			assert.Error(t, p.RestoreState(strings.NewReader(tt.state)))
+			// The aggregation state is left unchanged.
+			assert.Len(t, p.GetMetrics(), 1)
+		})
+	}
+}
@@ -15,8 +15,8 @@
 package protocol
 
 import (
-	"errors"
-	"fmt"
+	"net"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,45 +25,234 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// SourceAddressLabel is the label holding the IP address of the client that
+// sent the metric.
+const SourceAddressLabel = "net.peer.ip"
+
 var (
-	errEmptyMetricName  = errors.New("empty metric name")
-	errEmptyMetricValue = errors.New("empty metric value")
+	errEmptyMetricName  = newParseError(ReasonEmptyName, "empty metric name")
+	errEmptyMetricValue = newParseError(ReasonEmptyValue, "empty metric value")
 )
 
 func getSupportedTypes() []string {
-	return []string{"c", "g"}
+	return []string{"c", "g", "d", "ms", "h"}
+}
+
+// StatsDParser supports the Aggregate method for parsing StatsD messages with
+// Tags and the GetMetrics method for retrieving the aggregated metrics.
+//
+// Counters are summed, after being scaled by their sample rate, gauges keep
+// their last value, adjusted by the values sent with a sign, and the
+// observations of timers, histograms and DogStatsD
+// distributions are aggregated according to DistributionObserver until
+// GetMetrics is called, which starts a new aggregation interval. StatsDParser
+// is not safe for concurrent use.
+type StatsDParser struct {
+	// DistributionObserver selects how timer ("ms"), histogram ("h") and
+	// distribution ("d") metrics are aggregated. Defaults to
+	// HistogramObserver.
+	DistributionObserver ObserverType
+
+	// HistogramBounds selects the bucket bounds of the metrics aggregated by
+	// HistogramObserver. The default bounds are used if nil.
+	HistogramBounds *HistogramBounds
+
+	// Filter drops the metrics by name when they are parsed, before the
+	// mappings are applied. All the metrics are kept if nil.
+	Filter *Filter
+
+	// Mapper renames the metrics and extracts labels from their names before
+	// they are aggregated. No mapping is applied if nil.
+	Mapper *Mapper
+
+	// EnableSourceAddress adds the IP address of the client as the
+	// SourceAddressLabel label of the metrics, overriding the tag of the same
+	// key if any.
+	EnableSourceAddress bool
+
+	metrics       map[statsDMetricDescription]*aggregatedMetric
+	order         []statsDMetricDescription
+	intervalStart int64
+
+	// gauges holds the last value of the gauges reported by GetMetrics,
+	// adjusted by the deltas received in the following intervals. They are
+	// keyed by series, whatever the timestamp of the values, and dropped
+	// after gaugeMaxIdleIntervals intervals without being reported.
+	gauges map[statsDMetricDescription]*aggregatedMetric
 }
 
-// StatsDParser supports the Parse method for parsing StatsD messages with Tags.
-type StatsDParser struct{}
+// gaugeMaxIdleIntervals is the number of aggregation intervals after which
+// the last value of a gauge which was not reported again is forgotten, the
+// deltas received later being applied to zero.
+const gaugeMaxIdleIntervals = 10
 
 type statsDMetric struct {
 	name             string
 	value            string
 	statsdMetricType string
-	metricType       metricspb.MetricDescriptor_Type
 	sampleRate       float64
+	timestamp        int64
+	// gaugeDelta is set for the gauge values sent with a sign, which adjust
+	// the value of the gauge instead of setting it.
+	gaugeDelta  bool
+	labelKeys   []*metricspb.LabelKey
+	labelValues []*metricspb.LabelValue
+}
+
+// statsDMetricDescription identifies a single aggregated time series.
+type statsDMetricDescription struct {
+	name             string
+	statsdMetricType string
+	labels           string
+	timestamp        int64
+}
+
+// aggregatedMetric holds the state of a single time series for the current
+// aggregation interval.
+type aggregatedMetric struct {
+	name             string
+	statsdMetricType string
+	timestamp        int64
 	labelKeys        []*metricspb.LabelKey
 	labelValues      []*metricspb.LabelValue
+	isDouble         bool
+	intValue         int64
+	doubleValue      float64
+	// scaledIntValue is the sum of the integer counter values scaled by their
+	// sample rate, only rounded when the metric is built.
+	scaledIntValue float64
+	distribution   *distributionAggregation
+	// idleIntervals is the number of intervals since the last value of a
+	// gauge was reported.
+	idleIntervals int
 }
 
 var timeNowFunc = func() int64 {
 	return time.Now().Unix()
 }
 
-// Parse returns an OTLP metric representation of the input StatsD string.
-func (p *StatsDParser) Parse(line string) (*metricspb.Metric, error) {
+// Aggregate parses the input StatsD string and merges it into the metrics
+// aggregated during the current interval, addr being the address of the
+// client that sent it. The lines of the metrics dropped by the filter are
+// ignored without error.
+func (p *StatsDParser) Aggregate(line string, addr net.Addr) error {
 	parsedMetric, err := parseMessageToMetric(line)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	if !p.Filter.keep(parsedMetric.name) {
+		return nil
 	}
 
-	metricPoint, err := buildPoint(parsedMetric)
+	intValue, doubleValue, isDouble, err := parseValue(parsedMetric.value)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	p.Mapper.apply(parsedMetric)
+
+	if p.EnableSourceAddress {
+		if ip := addrIP(addr); ip != "" {
+			parsedMetric.setLabel(SourceAddressLabel, ip)
+			sort.Sort(byLabelKey(*parsedMetric))
+		}
+	}
+
+	if p.metrics == nil {
+		p.reset()
+	}
+
+	description := parsedMetric.description()
+	aggregated, ok := p.metrics[description]
+	if !ok {
+		aggregated = &aggregatedMetric{
+			name:             parsedMetric.name,
+			statsdMetricType: parsedMetric.statsdMetricType,
+			timestamp:        parsedMetric.timestamp,
+			labelKeys:        parsedMetric.labelKeys,
+			labelValues:      parsedMetric.labelValues,
+		}
+		p.metrics[description] = aggregated
+		p.order = append(p.order, description)
+	}
+
+	switch parsedMetric.statsdMetricType {
+	case "c":
+		aggregated.addCounterValue(intValue, doubleValue, isDouble, parsedMetric.sampleRate)
+	case "g":
+		if !parsedMetric.gaugeDelta {
+			aggregated.setGaugeValue(intValue, doubleValue, isDouble)
+			break
+		}
+		if last, found := p.gauges[description.series()]; !ok && found {
+			aggregated.setGaugeValue(last.intValue, last.doubleValue, last.isDouble)
+		}
+		// The sample rate of the gauges is ignored.
+		aggregated.addCounterValue(intValue, doubleValue, isDouble, 1)
+	case "d", "ms", "h":
+		if aggregated.distribution == nil {
+			aggregated.distribution = newDistributionAggregation(
+				p.distributionObserver(), p.HistogramBounds.forName(parsedMetric.name))
+		}
+		if !isDouble {
+			doubleValue = float64(intValue)
+		}
+		aggregated.distribution.observe(doubleValue, parsedMetric.sampleRate)
+	}
+
+	return nil
+}
+
+// GetMetrics returns the metrics aggregated since the previous call, in the
+// order they were first received, and starts a new aggregation interval.
+func (p *StatsDParser) GetMetrics() []*metricspb.Metric {
+	if len(p.order) == 0 {
+		return nil
+	}
+
+	start := &timestamppb.Timestamp{
+		Seconds: p.intervalStart,
+	}
+	now := &timestamppb.Timestamp{
+		Seconds: timeNowFunc(),
+	}
+
+	if p.gauges == nil {
+		p.gauges = make(map[statsDMetricDescription]*aggregatedMetric)
+	}
+	for _, gauge := range p.gauges {
+		gauge.idleIntervals++
+	}
+	metrics := make([]*metricspb.Metric, 0, len(p.order))
+	for _, description := range p.order {
+		aggregated := p.metrics[description]
+		if aggregated.statsdMetricType == "g" {
+			p.gauges[description.series()] = aggregated
+		}
+		metrics = append(metrics, aggregated.buildMetrics(start, now)...)
+	}
+	for series, gauge := range p.gauges {
+		if gauge.idleIntervals > gaugeMaxIdleIntervals {
+			delete(p.gauges, series)
+		}
 	}
 
-	return buildMetric(parsedMetric, metricPoint), nil
+	p.reset()
+	return metrics
+}
+
+func (p *StatsDParser) distributionObserver() ObserverType {
+	if p.DistributionObserver == "" {
+		return HistogramObserver
+	}
+	return p.DistributionObserver
+}
+
+func (p *StatsDParser) reset() {
+	p.metrics = make(map[statsDMetricDescription]*aggregatedMetric)
+	p.order = nil
+	p.intervalStart = timeNowFunc()
 }
 
 func parseMessageToMetric(line string) (*statsDMetric, error) {
@@ -71,15 +260,18 @@ func parseMessageToMetric(line string) (*statsDMetric, error) {
 
 	parts := strings.Split(line, "|")
 	if len(parts) < 2 {
-		return nil, fmt.Errorf("invalid message format: %s", line)
+		return nil, newParseError(ReasonInvalidFormat, "invalid message format: %s", line)
 	}
 
 	separatorIndex := strings.IndexByte(parts[0], ':')
 	if separatorIndex < 0 {
-		return nil, fmt.Errorf("invalid <name>:<value> format: %s", parts[0])
+		return nil, newParseError(ReasonInvalidFormat, "invalid <name>:<value> format: %s", parts[0])
 	}
 
 	result.name = parts[0][0:separatorIndex]
+	if err := result.parseInfluxTags(); err != nil {
+		return nil, err
+	}
 	if result.name == "" {
 		return nil, errEmptyMetricName
 	}
@@ -90,48 +282,93 @@ func parseMessageToMetric(line string) (*statsDMetric, error) {
 
 	result.statsdMetricType = parts[1]
 	if !contains(getSupportedTypes(), result.statsdMetricType) {
-		return nil, fmt.Errorf("unsupported metric type: %s", result.statsdMetricType)
+		return nil, newParseError(ReasonUnsupportedType, "unsupported metric type: %s", result.statsdMetricType)
 	}
+	result.gaugeDelta = result.statsdMetricType == "g" && (result.value[0] == '+' || result.value[0] == '-')
 
 	additionalParts := parts[2:]
 	for _, part := range additionalParts {
-		// TODO: Sample rate doesn't currently have a place to go in the protocol
 		if strings.HasPrefix(part, "@") {
 			sampleRateStr := strings.TrimPrefix(part, "@")
 
 			f, err := strconv.ParseFloat(sampleRateStr, 64)
 			if err != nil {
-				return nil, fmt.Errorf("parse sample rate: %s", sampleRateStr)
+				return nil, newParseError(ReasonInvalidSampleRate, "parse sample rate: %s", sampleRateStr)
 			}
 
 			result.sampleRate = f
+		} else if strings.HasPrefix(part, "T") {
+			timestampStr := strings.TrimPrefix(part, "T")
+
+			timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
+			if err != nil || timestamp <= 0 {
+				return nil, newParseError(ReasonInvalidTimestamp, "parse timestamp: %s", timestampStr)
+			}
+
+			result.timestamp = timestamp
 		} else if strings.HasPrefix(part, "#") {
 			tagsStr := strings.TrimPrefix(part, "#")
 
 			tagSets := strings.Split(tagsStr, ",")
 
-			result.labelKeys = make([]*metricspb.LabelKey, 0, len(tagSets))
-			result.labelValues = make([]*metricspb.LabelValue, 0, len(tagSets))
-
 			for _, tagSet := range tagSets {
 				tagParts := strings.Split(tagSet, ":")
 				if len(tagParts) != 2 {
-					return nil, fmt.Errorf("invalid tag format: %s", tagParts)
+					return nil, newParseError(ReasonInvalidTag, "invalid tag format: %s", tagParts)
 				}
-				result.labelKeys = append(result.labelKeys, &metricspb.LabelKey{Key: tagParts[0]})
-				result.labelValues = append(result.labelValues, &metricspb.LabelValue{
-					Value:    tagParts[1],
-					HasValue: true,
-				})
+				// The DogStatsD tags override the InfluxDB ones of the same key.
+				result.setLabel(tagParts[0], tagParts[1])
 			}
 		} else {
-			return nil, fmt.Errorf("unrecognized message part: %s", part)
+			return nil, newParseError(ReasonUnrecognizedPart, "unrecognized message part: %s", part)
 		}
 	}
 
+	// Sort the tags so the same series is always aggregated under the same
+	// description, regardless of the order sent by the client.
+	sort.Sort(byLabelKey(*result))
+
 	return result, nil
 }
 
+// parseInfluxTags extracts the InfluxDB style tags embedded in the name, as
+// in <name>,<tag1-key>=<tag1-value>,<tag2-k/v>, which are sent by the
+// Telegraf compatible clients.
+func (m *statsDMetric) parseInfluxTags() error {
+	separatorIndex := strings.IndexByte(m.name, ',')
+	if separatorIndex < 0 {
+		return nil
+	}
+
+	tagSets := strings.Split(m.name[separatorIndex+1:], ",")
+	m.name = m.name[:separatorIndex]
+	for _, tagSet := range tagSets {
+		tagParts := strings.Split(tagSet, "=")
+		if len(tagParts) != 2 || tagParts[0] == "" {
+			return newParseError(ReasonInvalidTag, "invalid tag format: %s", tagParts)
+		}
+		m.setLabel(tagParts[0], tagParts[1])
+	}
+	return nil
+}
+
+// addrIP returns the IP address of addr, or "" if it has none.
+func addrIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case nil:
+		return ""
+	case *net.UDPAddr:
+		return a.IP.String()
+	case *net.TCPAddr:
+		return a.IP.String()
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return ""
+	}
+	return host
+}
+
 func contains(slice []string, element string) bool {
 	for _, val := range slice {
 		if val == element {
@@ -141,77 +378,145 @@ func contains(slice []string, element string) bool {
 	return false
 }
 
-func buildMetric(metric *statsDMetric, point *metricspb.Point) *metricspb.Metric {
-	return &metricspb.Metric{
-		MetricDescriptor: &metricspb.MetricDescriptor{
-			Name:      metric.name,
-			Type:      metric.metricType,
-			LabelKeys: metric.labelKeys,
-		},
-		Timeseries: []*metricspb.TimeSeries{
-			{
-				LabelValues: metric.labelValues,
-				Points: []*metricspb.Point{
-					point,
-				},
-			},
-		},
+// byLabelKey sorts the labels of a statsDMetric by key.
+type byLabelKey statsDMetric
+
+func (b byLabelKey) Len() int           { return len(b.labelKeys) }
+func (b byLabelKey) Less(i, j int) bool { return b.labelKeys[i].Key < b.labelKeys[j].Key }
+func (b byLabelKey) Swap(i, j int) {
+	b.labelKeys[i], b.labelKeys[j] = b.labelKeys[j], b.labelKeys[i]
+	b.labelValues[i], b.labelValues[j] = b.labelValues[j], b.labelValues[i]
+}
+
+// series returns the description of the time series regardless of the
+// timestamp of its values.
+func (d statsDMetricDescription) series() statsDMetricDescription {
+	d.timestamp = 0
+	return d
+}
+
+func (m *statsDMetric) description() statsDMetricDescription {
+	var labels strings.Builder
+	for i, key := range m.labelKeys {
+		labels.WriteString(key.Key)
+		labels.WriteByte(0)
+		labels.WriteString(m.labelValues[i].Value)
+		labels.WriteByte(0)
+	}
+	return statsDMetricDescription{
+		name:             m.name,
+		statsdMetricType: m.statsdMetricType,
+		labels:           labels.String(),
+		timestamp:        m.timestamp,
 	}
 }
 
-func buildPoint(parsedMetric *statsDMetric) (*metricspb.Point, error) {
-	now := &timestamppb.Timestamp{
-		Seconds: timeNowFunc(),
+func parseValue(value string) (int64, float64, bool, error) {
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err == nil {
+		return i, 0, false, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, 0, false, newParseError(ReasonInvalidValue, "parse metric value string: %s", value)
 	}
+	return 0, f, true, nil
+}
 
-	switch parsedMetric.statsdMetricType {
-	case "c":
-		return buildGaugeOrCounterPoint(parsedMetric, now, func(parsedMetric *statsDMetric, isDouble bool) {
-			if isDouble {
-				parsedMetric.metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
-				return
-			}
-			parsedMetric.metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
-		})
-	case "g":
-		return buildGaugeOrCounterPoint(parsedMetric, now, func(parsedMetric *statsDMetric, isDouble bool) {
-			if isDouble {
-				parsedMetric.metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
-				return
-			}
-			parsedMetric.metricType = metricspb.MetricDescriptor_GAUGE_INT64
-		})
+func (a *aggregatedMetric) addCounterValue(intValue int64, doubleValue float64, isDouble bool, sampleRate float64) {
+	sampled := sampleRate > 0 && sampleRate < 1
+	if sampled && isDouble {
+		doubleValue /= sampleRate
+	}
+
+	// Once a double value is received the whole series is reported as double.
+	if isDouble && !a.isDouble {
+		a.isDouble = true
+		a.doubleValue = float64(a.intValue) + a.scaledIntValue
 	}
 
-	return nil, fmt.Errorf("unhandled metric type: %s", parsedMetric.statsdMetricType)
+	switch {
+	case isDouble:
+		a.doubleValue += doubleValue
+	case a.isDouble && sampled:
+		a.doubleValue += float64(intValue) / sampleRate
+	case a.isDouble:
+		a.doubleValue += float64(intValue)
+	case sampled:
+		a.scaledIntValue += float64(intValue) / sampleRate
+	default:
+		a.intValue += intValue
+	}
 }
 
-func buildGaugeOrCounterPoint(parsedMetric *statsDMetric, now *timestamppb.Timestamp,
-	metricTypeSetter func(parsedMetric *statsDMetric, isDouble bool)) (*metricspb.Point, error) {
-	var point *metricspb.Point
-	var isDouble bool
+func (a *aggregatedMetric) setGaugeValue(intValue int64, doubleValue float64, isDouble bool) {
+	a.isDouble = isDouble
+	a.intValue = intValue
+	a.doubleValue = doubleValue
+}
 
-	i, err := strconv.ParseInt(parsedMetric.value, 10, 64)
-	if err != nil {
-		f, err := strconv.ParseFloat(parsedMetric.value, 64)
-		if err != nil {
-			return nil, fmt.Errorf("parse metric value string: %s", parsedMetric.value)
-		}
-		point = &metricspb.Point{
-			Timestamp: now,
-			Value: &metricspb.Point_DoubleValue{
-				DoubleValue: f,
-			},
+func (a *aggregatedMetric) buildMetrics(start, now *timestamppb.Timestamp) []*metricspb.Metric {
+	if a.timestamp != 0 {
+		// The series sent with a timestamp are reported at it, the start of
+		// the interval being moved back to the timestamp if it is older.
+		now = &timestamppb.Timestamp{
+			Seconds: a.timestamp,
+		}
+		if a.timestamp < start.Seconds {
+			start = now
+		}
+	}
+
+	if a.distribution != nil {
+		return a.distribution.buildMetrics(a.name, a.labelKeys, a.labelValues, start, now)
+	}
+
+	point := &metricspb.Point{
+		Timestamp: now,
+	}
+	if a.isDouble {
+		point.Value = &metricspb.Point_DoubleValue{
+			DoubleValue: a.doubleValue,
 		}
-		isDouble = true
 	} else {
-		point = &metricspb.Point{
-			Timestamp: now,
-			Value: &metricspb.Point_Int64Value{
-				Int64Value: i,
-			},
+		point.Value = &metricspb.Point_Int64Value{
+			Int64Value: a.intValue + round(a.scaledIntValue),
+		}
+	}
+
+	timeseries := &metricspb.TimeSeries{
+		LabelValues: a.labelValues,
+		Points: []*metricspb.Point{
+			point,
+		},
+	}
+
+	var metricType metricspb.MetricDescriptor_Type
+	switch a.statsdMetricType {
+	case "c":
+		metricType = metricspb.MetricDescriptor_CUMULATIVE_INT64
+		if a.isDouble {
+			metricType = metricspb.MetricDescriptor_CUMULATIVE_DOUBLE
 		}
+		// Counters restart from zero at the beginning of each interval.
+		timeseries.StartTimestamp = start
+	case "g":
+		metricType = metricspb.MetricDescriptor_GAUGE_INT64
+		if a.isDouble {
+			metricType = metricspb.MetricDescriptor_GAUGE_DOUBLE
+		}
+	}
+
+	return []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      a.name,
+				Type:      metricType,
+				LabelKeys: a.labelKeys,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				timeseries,
+			},
+		},
 	}
-	metricTypeSetter(parsedMetric, isDouble)
-	return point, nil
 }
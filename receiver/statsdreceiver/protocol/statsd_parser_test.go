@@ -15,15 +15,17 @@
 package protocol
 
 import (
-	"errors"
+	"fmt"
+	"net"
 	"testing"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-func Test_StatsDParser_Parse(t *testing.T) {
+func Test_StatsDParser_Aggregate(t *testing.T) {
 	prevTimeNowFunc := timeNowFunc
 	timeNowFunc = func() int64 {
 		return 0
@@ -38,27 +40,27 @@ func Test_StatsDParser_Parse(t *testing.T) {
 		name       string
 		input      string
 		wantMetric *metricspb.Metric
-		err        error
+		err        *ParseError
 	}{
 		{
 			name:  "empty input string",
 			input: "",
-			err:   errors.New("invalid message format: "),
+			err:   newParseError(ReasonInvalidFormat, "invalid message format: "),
 		},
 		{
 			name:  "missing metric value",
 			input: "test.metric|c",
-			err:   errors.New("invalid <name>:<value> format: test.metric"),
+			err:   newParseError(ReasonInvalidFormat, "invalid <name>:<value> format: test.metric"),
 		},
 		{
 			name:  "empty metric name",
 			input: ":42|c",
-			err:   errors.New("empty metric name"),
+			err:   newParseError(ReasonEmptyName, "empty metric name"),
 		},
 		{
 			name:  "empty metric value",
 			input: "test.metric:|c",
-			err:   errors.New("empty metric value"),
+			err:   newParseError(ReasonEmptyValue, "empty metric value"),
 		},
 		{
 			name:  "integer counter",
@@ -95,12 +97,12 @@ func Test_StatsDParser_Parse(t *testing.T) {
 		{
 			name:  "invalid metric value",
 			input: "test.metric:42.abc|c",
-			err:   errors.New("parse metric value string: 42.abc"),
+			err:   newParseError(ReasonInvalidValue, "parse metric value string: 42.abc"),
 		},
 		{
 			name:  "unhandled metric type",
 			input: "test.metric:42|unhandled_type",
-			err:   errors.New("unsupported metric type: unhandled_type"),
+			err:   newParseError(ReasonUnsupportedType, "unsupported metric type: unhandled_type"),
 		},
 		{
 			name:  "counter metric with sample rate and tags",
@@ -123,7 +125,7 @@ func Test_StatsDParser_Parse(t *testing.T) {
 						Seconds: 0,
 					},
 					Value: &metricspb.Point_Int64Value{
-						Int64Value: 42,
+						Int64Value: 420,
 					},
 				}),
 		},
@@ -180,17 +182,125 @@ func Test_StatsDParser_Parse(t *testing.T) {
 		{
 			name:  "invalid sample rate value",
 			input: "test.metric:42|c|@1.0a",
-			err:   errors.New("parse sample rate: 1.0a"),
+			err:   newParseError(ReasonInvalidSampleRate, "parse sample rate: 1.0a"),
 		},
 		{
 			name:  "invalid tag format",
 			input: "test.metric:42|c|#key1",
-			err:   errors.New("invalid tag format: [key1]"),
+			err:   newParseError(ReasonInvalidTag, "invalid tag format: [key1]"),
+		},
+		{
+			name:  "counter metric with influxdb tags",
+			input: "test.metric,key2=value2,key1=value1:42|c",
+			wantMetric: testMetric("test.metric",
+				metricspb.MetricDescriptor_CUMULATIVE_INT64,
+				[]*metricspb.LabelKey{
+					{
+						Key: "key1",
+					},
+					{
+						Key: "key2",
+					},
+				},
+				[]*metricspb.LabelValue{
+					{
+						Value:    "value1",
+						HasValue: true,
+					},
+					{
+						Value:    "value2",
+						HasValue: true,
+					},
+				},
+				&metricspb.Point{
+					Timestamp: &timestamppb.Timestamp{
+						Seconds: 0,
+					},
+					Value: &metricspb.Point_Int64Value{
+						Int64Value: 42,
+					},
+				}),
+		},
+		{
+			name:  "influxdb tags overridden by dogstatsd tags",
+			input: "test.gauge,key=influx,host=a:42|g|#key:value",
+			wantMetric: testMetric("test.gauge",
+				metricspb.MetricDescriptor_GAUGE_INT64,
+				[]*metricspb.LabelKey{
+					{
+						Key: "host",
+					},
+					{
+						Key: "key",
+					},
+				},
+				[]*metricspb.LabelValue{
+					{
+						Value:    "a",
+						HasValue: true,
+					},
+					{
+						Value:    "value",
+						HasValue: true,
+					},
+				},
+				&metricspb.Point{
+					Timestamp: &timestamppb.Timestamp{
+						Seconds: 0,
+					},
+					Value: &metricspb.Point_Int64Value{
+						Int64Value: 42,
+					},
+				}),
+		},
+		{
+			name:  "invalid influxdb tag format",
+			input: "test.metric,key1:42|c",
+			err:   newParseError(ReasonInvalidTag, "invalid tag format: [key1]"),
+		},
+		{
+			name:  "empty metric name with influxdb tags",
+			input: ",key=value:42|c",
+			err:   newParseError(ReasonEmptyName, "empty metric name"),
+		},
+		{
+			name:  "counter metric with timestamp",
+			input: "test.metric:42|c|#key:value|T1600000000",
+			wantMetric: &metricspb.Metric{
+				MetricDescriptor: &metricspb.MetricDescriptor{
+					Name:      "test.metric",
+					Type:      metricspb.MetricDescriptor_CUMULATIVE_INT64,
+					LabelKeys: []*metricspb.LabelKey{{Key: "key"}},
+				},
+				Timeseries: []*metricspb.TimeSeries{
+					{
+						// The interval starts at 0 in the test.
+						StartTimestamp: &timestamppb.Timestamp{},
+						LabelValues:    []*metricspb.LabelValue{{Value: "value", HasValue: true}},
+						Points: []*metricspb.Point{
+							{
+								Timestamp: &timestamppb.Timestamp{Seconds: 1600000000},
+								Value:     &metricspb.Point_Int64Value{Int64Value: 42},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "invalid timestamp",
+			input: "test.metric:42|c|T16e8",
+			err:   newParseError(ReasonInvalidTimestamp, "parse timestamp: 16e8"),
+		},
+		{
+			name:  "negative timestamp",
+			input: "test.metric:42|c|T-1",
+			err:   newParseError(ReasonInvalidTimestamp, "parse timestamp: -1"),
 		},
 		{
 			name:  "unrecognized message part",
 			input: "test.metric:42|c|$extra",
-			err:   errors.New("unrecognized message part: $extra"),
+			err:   newParseError(ReasonUnrecognizedPart, "unrecognized message part: $extra"),
 		},
 	}
 
@@ -198,23 +308,279 @@ func Test_StatsDParser_Parse(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			p := &StatsDParser{}
 
-			got, err := p.Parse(tt.input)
+			err := p.Aggregate(tt.input, nil)
 
 			if tt.err != nil {
-				assert.Equal(t, err, tt.err)
+				assert.Equal(t, tt.err, err)
+				assert.Nil(t, p.GetMetrics())
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, got, tt.wantMetric)
+				assert.Equal(t, []*metricspb.Metric{tt.wantMetric}, p.GetMetrics())
 			}
 		})
 	}
 }
 
+func Test_StatsDParser_AggregateMultipleLines(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 0
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	p := &StatsDParser{}
+	for _, line := range []string{
+		"test.counter:42|c|#key1:value1,key2:value2",
+		"test.gauge:1|g",
+		"test.counter:8|c|#key2:value2,key1:value1",
+		"test.gauge:2.5|g",
+		"test.counter:1|c|@0.5",
+		"test.counter:0.5|c|@0.5",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	labelKeys := []*metricspb.LabelKey{
+		{
+			Key: "key1",
+		},
+		{
+			Key: "key2",
+		},
+	}
+	labelValues := []*metricspb.LabelValue{
+		{
+			Value:    "value1",
+			HasValue: true,
+		},
+		{
+			Value:    "value2",
+			HasValue: true,
+		},
+	}
+	assert.Equal(t, []*metricspb.Metric{
+		testMetric("test.counter",
+			metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labelKeys,
+			labelValues,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_Int64Value{
+					Int64Value: 50,
+				},
+			}),
+		testMetric("test.gauge",
+			metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			nil,
+			nil,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_DoubleValue{
+					DoubleValue: 2.5,
+				},
+			}),
+		testMetric("test.counter",
+			metricspb.MetricDescriptor_CUMULATIVE_DOUBLE,
+			nil,
+			nil,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_DoubleValue{
+					DoubleValue: 3,
+				},
+			}),
+	}, p.GetMetrics())
+
+	// A new aggregation interval starts after the metrics are retrieved.
+	assert.Nil(t, p.GetMetrics())
+}
+
+func Test_StatsDParser_AggregateSampledCounter(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 0
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	// Each value stands for 3.33 values, the sum is only rounded once.
+	p := &StatsDParser{}
+	for _, line := range []string{
+		"test.counter:1|c|@0.3",
+		"test.counter:1|c|@0.3",
+		"test.counter:1|c|@0.3",
+		"test.counter:2|c",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	assert.Equal(t, []*metricspb.Metric{
+		testMetric("test.counter",
+			metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			nil,
+			nil,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_Int64Value{
+					Int64Value: 12,
+				},
+			}),
+	}, p.GetMetrics())
+}
+
+func Test_StatsDParser_SourceAddress(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 0
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	p := &StatsDParser{EnableSourceAddress: true}
+	udpAddr := &net.UDPAddr{IP: net.ParseIP("10.0.0.1"), Port: 51234}
+	tcpAddr := &net.TCPAddr{IP: net.ParseIP("10.0.0.2"), Port: 51234}
+	require.NoError(t, p.Aggregate("test.counter:1|c|#zone:a", udpAddr))
+	require.NoError(t, p.Aggregate("test.counter:2|c|#zone:a", udpAddr))
+	require.NoError(t, p.Aggregate("test.counter:4|c|#zone:a,net.peer.ip:1.2.3.4", tcpAddr))
+	require.NoError(t, p.Aggregate("test.gauge:1|g", nil))
+
+	labelKeys := []*metricspb.LabelKey{
+		{
+			Key: SourceAddressLabel,
+		},
+		{
+			Key: "zone",
+		},
+	}
+	labelValues := func(ip string) []*metricspb.LabelValue {
+		return []*metricspb.LabelValue{
+			{
+				Value:    ip,
+				HasValue: true,
+			},
+			{
+				Value:    "a",
+				HasValue: true,
+			},
+		}
+	}
+	assert.Equal(t, []*metricspb.Metric{
+		testMetric("test.counter",
+			metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labelKeys,
+			labelValues("10.0.0.1"),
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_Int64Value{
+					Int64Value: 3,
+				},
+			}),
+		testMetric("test.counter",
+			metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			labelKeys,
+			labelValues("10.0.0.2"),
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_Int64Value{
+					Int64Value: 4,
+				},
+			}),
+		testMetric("test.gauge",
+			metricspb.MetricDescriptor_GAUGE_INT64,
+			nil,
+			nil,
+			&metricspb.Point{
+				Timestamp: &timestamppb.Timestamp{
+					Seconds: 0,
+				},
+				Value: &metricspb.Point_Int64Value{
+					Int64Value: 1,
+				},
+			}),
+	}, p.GetMetrics())
+}
+
+func Test_StatsDParser_AggregateTimestamps(t *testing.T) {
+	prevTimeNowFunc := timeNowFunc
+	timeNowFunc = func() int64 {
+		return 1600000060
+	}
+	t.Cleanup(
+		func() {
+			timeNowFunc = prevTimeNowFunc
+		},
+	)
+
+	p := &StatsDParser{}
+	for _, line := range []string{
+		"test.counter:1|c|T1600000000",
+		"test.counter:2|c|T1600000000",
+		"test.counter:4|c|T1600000010",
+		"test.counter:8|c",
+		"test.distribution:5|ms|T1600000000",
+	} {
+		require.NoError(t, p.Aggregate(line, nil))
+	}
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 4)
+	tests := []struct {
+		start int64
+		time  int64
+		value int64
+	}{
+		// The buffered series are aggregated by timestamp.
+		{start: 1600000000, time: 1600000000, value: 3},
+		{start: 1600000010, time: 1600000010, value: 4},
+		{start: 1600000060, time: 1600000060, value: 8},
+	}
+	for i, tt := range tests {
+		ts := metrics[i].GetTimeseries()[0]
+		assert.Equal(t, tt.start, ts.GetStartTimestamp().GetSeconds(), "metric %d", i)
+		assert.Equal(t, tt.time, ts.GetPoints()[0].GetTimestamp().GetSeconds(), "metric %d", i)
+		assert.Equal(t, tt.value, ts.GetPoints()[0].GetInt64Value(), "metric %d", i)
+	}
+
+	ts := metrics[3].GetTimeseries()[0]
+	assert.Equal(t, int64(1600000000), ts.GetStartTimestamp().GetSeconds())
+	assert.Equal(t, int64(1600000000), ts.GetPoints()[0].GetTimestamp().GetSeconds())
+	assert.Equal(t, int64(1), ts.GetPoints()[0].GetDistributionValue().GetCount())
+}
+
 func testMetric(metricName string,
 	metricType metricspb.MetricDescriptor_Type,
 	lableKeys []*metricspb.LabelKey,
 	labelValues []*metricspb.LabelValue,
 	point *metricspb.Point) *metricspb.Metric {
+	var startTimestamp *timestamppb.Timestamp
+	if metricType == metricspb.MetricDescriptor_CUMULATIVE_INT64 ||
+		metricType == metricspb.MetricDescriptor_CUMULATIVE_DOUBLE {
+		startTimestamp = &timestamppb.Timestamp{
+			Seconds: 0,
+		}
+	}
 	return &metricspb.Metric{
 		MetricDescriptor: &metricspb.MetricDescriptor{
 			Name:      metricName,
@@ -223,7 +589,8 @@ func testMetric(metricName string,
 		},
 		Timeseries: []*metricspb.TimeSeries{
 			{
-				LabelValues: labelValues,
+				StartTimestamp: startTimestamp,
+				LabelValues:    labelValues,
 				Points: []*metricspb.Point{
 					point,
 				},
@@ -231,3 +598,62 @@ func testMetric(metricName string,
 		},
 	}
 }
+
+func Test_StatsDParser_GaugeDeltas(t *testing.T) {
+	p := &StatsDParser{}
+	require.NoError(t, p.Aggregate("queue.size:+5|g", nil))
+	require.NoError(t, p.Aggregate("queue.size:-2|g", nil))
+	require.NoError(t, p.Aggregate("workers:10|g", nil))
+	require.NoError(t, p.Aggregate("workers:+2|g", nil))
+	require.NoError(t, p.Aggregate("workers:-1|g|#pool:a", nil))
+
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 3)
+	assert.Equal(t, "queue.size", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, int64(3), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, int64(12), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	// Each series is adjusted on its own.
+	assert.Equal(t, int64(-1), metrics[2].Timeseries[0].Points[0].GetInt64Value())
+
+	// The deltas adjust the value reported in the previous interval.
+	require.NoError(t, p.Aggregate("queue.size:-0.5|g", nil))
+	require.NoError(t, p.Aggregate("workers:4|g", nil))
+	require.NoError(t, p.Aggregate("workers:-1|g", nil))
+	metrics = p.GetMetrics()
+	require.Len(t, metrics, 2)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, metrics[0].MetricDescriptor.Type)
+	assert.Equal(t, 2.5, metrics[0].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, int64(3), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+
+	// The gauges which did not change are not reported again.
+	require.NoError(t, p.Aggregate("workers:+1|g", nil))
+	metrics = p.GetMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, int64(4), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+}
+
+func Test_StatsDParser_TimestampedGauges(t *testing.T) {
+	p := &StatsDParser{}
+	require.NoError(t, p.Aggregate("idle:1|g", nil))
+	for i := 0; i < 3*gaugeMaxIdleIntervals; i++ {
+		require.NoError(t, p.Aggregate(fmt.Sprintf("queue.size:%d|g|T%d", 10+i, 1600000000+i), nil))
+		require.NoError(t, p.Aggregate(fmt.Sprintf("workers:+1|g|T%d", 1600000000+i), nil))
+		metrics := p.GetMetrics()
+		// The deltas adjust the last value whatever its timestamp.
+		assert.Equal(t, int64(i+1), metrics[len(metrics)-1].Timeseries[0].Points[0].GetInt64Value())
+
+		// A single last value is kept per series, the idle gauge being
+		// dropped after gaugeMaxIdleIntervals intervals.
+		wantGauges := 2
+		if i <= gaugeMaxIdleIntervals {
+			wantGauges = 3
+		}
+		assert.Len(t, p.gauges, wantGauges)
+	}
+
+	// The delta of a forgotten gauge applies to zero.
+	require.NoError(t, p.Aggregate("idle:+2|g", nil))
+	metrics := p.GetMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, int64(2), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+}
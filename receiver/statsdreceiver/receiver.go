@@ -19,16 +19,21 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
 )
 
+// transferChanSize is the size of the buffer of lines per UDP reader.
+const transferChanSize = 1024
+
 var _ component.MetricsReceiver = (*statsdReceiver)(nil)
 
 // statsdReceiver implements the component.MetricsReceiver for StatsD protocol.
@@ -40,8 +45,25 @@ type statsdReceiver struct {
 	server       transport.Server
 	reporter     transport.Reporter
 	parser       protocol.Parser
+	grouper      *resourceGrouper
 	nextConsumer consumer.MetricsConsumer
 
+	// traceContext is the source of the exemplars, set on start if
+	// configured.
+	traceContext *traceContextExporter
+
+	cancel         context.CancelFunc
+	serverDone     chan struct{}
+	aggregatorDone chan struct{}
+
+	// Only accessed by the aggregation goroutine.
+	numReceivedMessages int
+	numInvalidMessages  int
+	intervalStart       time.Time
+	// restored is set when the aggregation state saved on the last shutdown
+	// is restored, so that it is flushed even if no line is received.
+	restored bool
+
 	startOnce sync.Once
 	stopOnce  sync.Once
 }
@@ -60,6 +82,25 @@ func New(
 		config.NetAddr.Endpoint = "localhost:8125"
 	}
 
+	if config.AggregationInterval <= 0 {
+		config.AggregationInterval = defaultAggregationInterval
+	}
+
+	for _, attribute := range config.ResourceAttributes {
+		if attribute.Tag == "" {
+			return nil, fmt.Errorf("empty tag in resource attributes for receiver %q", config.Name())
+		}
+	}
+
+	if config.Exemplars.TraceContextExporter != "" && !config.EnableSourceAddress {
+		return nil, fmt.Errorf("exemplars require enable_source_address for receiver %q", config.Name())
+	}
+
+	parser, err := buildParser(config)
+	if err != nil {
+		return nil, err
+	}
+
 	server, err := buildTransportServer(config)
 	if err != nil {
 		return nil, err
@@ -70,36 +111,120 @@ func New(
 		config:       &config,
 		nextConsumer: nextConsumer,
 		server:       server,
-		reporter:     newReporter(config.Name(), logger),
-		parser:       &protocol.StatsDParser{},
+		reporter:     newReporter(config.Name(), transportName(config.NetAddr.Transport), logger),
+		parser:       parser,
+		grouper:      newResourceGrouper(resourceAttributes(config)),
 	}
 	return r, nil
 }
 
+func buildParser(config Config) (protocol.Parser, error) {
+	observer := protocol.ObserverType(strings.ToLower(string(config.DistributionObserver)))
+	switch observer {
+	case "", protocol.HistogramObserver, protocol.SummaryObserver:
+	default:
+		return nil, fmt.Errorf("unsupported distribution observer %q for receiver %q", config.DistributionObserver, config.Name())
+	}
+
+	mapper, err := protocol.NewMapper(config.Mappings)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mappings for receiver %q: %w", config.Name(), err)
+	}
+
+	histogramBounds, err := protocol.NewHistogramBounds(config.Histogram)
+	if err != nil {
+		return nil, fmt.Errorf("invalid histogram for receiver %q: %w", config.Name(), err)
+	}
+
+	filter, err := protocol.NewFilter(config.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter for receiver %q: %w", config.Name(), err)
+	}
+
+	return &protocol.StatsDParser{
+		DistributionObserver: observer,
+		HistogramBounds:      histogramBounds,
+		Filter:               filter,
+		Mapper:               mapper,
+		EnableSourceAddress:  config.EnableSourceAddress,
+	}, nil
+}
+
+// resourceAttributes returns the tags promoted to resource attributes,
+// including the label of the source address if enabled.
+func resourceAttributes(config Config) []ResourceAttributeConfig {
+	if !config.EnableSourceAddress {
+		return config.ResourceAttributes
+	}
+	return append(
+		append([]ResourceAttributeConfig(nil), config.ResourceAttributes...),
+		ResourceAttributeConfig{Tag: protocol.SourceAddressLabel},
+	)
+}
+
+// transportName returns the transport reported with the receive operations,
+// "udp" if not set.
+func transportName(configured string) string {
+	if configured == "" {
+		return "udp"
+	}
+	return strings.ToLower(configured)
+}
+
 func buildTransportServer(config Config) (transport.Server, error) {
-	// TODO: Add TCP/unix socket transport implementations
+	// TODO: Add unix socket transport implementation
 	switch strings.ToLower(config.NetAddr.Transport) {
 	case "", "udp":
-		return transport.NewUDPServer(config.NetAddr.Endpoint)
+		return transport.NewUDPServer(config.NetAddr.Endpoint, config.MaxPacketSize, config.ReadBufferSize, config.UDPReaders)
+	case "tcp":
+		return transport.NewTCPServer(config.NetAddr.Endpoint, config.MaxPacketSize, config.TCPIdleTimeout, config.TCPMaxConnections)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.NetAddr.Transport, config.Name())
 }
 
-// StartMetricsReception starts a UDP server that can process StatsD messages.
+// udpReaders returns the number of goroutines reading the UDP sockets.
+func udpReaders(config *Config) int {
+	if config.UDPReaders > 1 {
+		return config.UDPReaders
+	}
+	return 1
+}
+
+// StartMetricsReception starts a UDP or TCP server that can process StatsD messages.
 func (r *statsdReceiver) Start(_ context.Context, host component.Host) error {
 	r.Lock()
 	defer r.Unlock()
 
 	err := componenterror.ErrAlreadyStarted
 	r.startOnce.Do(func() {
+		if name := r.config.Exemplars.TraceContextExporter; name != "" {
+			r.traceContext, err = findTraceContextExporter(host, name, r.config.Name())
+			if err != nil {
+				return
+			}
+		}
 		err = nil
+		r.restored = r.restoreState()
+
+		var ctx context.Context
+		ctx, r.cancel = context.WithCancel(context.Background())
+		r.serverDone = make(chan struct{})
+		r.aggregatorDone = make(chan struct{})
+		// The lines of all the readers are handed off to the aggregation
+		// goroutine, the buffer absorbing their bursts.
+		transferChan := make(chan transport.Metric, transferChanSize*udpReaders(r.config))
+
 		go func() {
-			err = r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter)
-			if err != nil {
+			defer close(r.serverDone)
+			if err := r.server.ListenAndServe(r.reporter, transferChan); err != nil {
 				host.ReportFatalError(err)
 			}
 		}()
+		go func() {
+			defer close(r.aggregatorDone)
+			r.aggregate(ctx, transferChan)
+		}()
 	})
 
 	return err
@@ -113,6 +238,77 @@ func (r *statsdReceiver) Shutdown(context.Context) error {
 	var err = componenterror.ErrAlreadyStopped
 	r.stopOnce.Do(func() {
 		err = r.server.Close()
+		if r.cancel != nil {
+			// Wait for the server to stop producing lines before flushing, or
+			// saving, whatever was aggregated since the last interval.
+			<-r.serverDone
+			r.cancel()
+			<-r.aggregatorDone
+		}
 	})
 	return err
 }
+
+// aggregate feeds the lines received by the server to the parser and flushes
+// the aggregated metrics to the next consumer every AggregationInterval.
+func (r *statsdReceiver) aggregate(ctx context.Context, transferChan <-chan transport.Metric) {
+	ticker := time.NewTicker(r.config.AggregationInterval)
+	defer ticker.Stop()
+	r.intervalStart = timeNow()
+
+	for {
+		select {
+		case metric := <-transferChan:
+			r.aggregateLine(metric)
+		case <-ticker.C:
+			r.flush()
+		case <-ctx.Done():
+			for {
+				select {
+				case metric := <-transferChan:
+					r.aggregateLine(metric)
+				default:
+					if !r.saveState() {
+						r.flush()
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *statsdReceiver) aggregateLine(metric transport.Metric) {
+	r.numReceivedMessages++
+	if err := r.parser.Aggregate(metric.Raw, metric.Addr); err != nil {
+		r.numInvalidMessages++
+		r.reporter.OnTranslationError(context.Background(), err)
+	}
+}
+
+func (r *statsdReceiver) flush() {
+	since := r.intervalStart
+	r.intervalStart = timeNow()
+	if r.numReceivedMessages == 0 && !r.restored {
+		return
+	}
+	r.restored = false
+
+	ctx := r.reporter.OnDataReceived(context.Background())
+	var (
+		err       error
+		numPoints int
+	)
+	if metrics := r.parser.GetMetrics(); len(metrics) > 0 {
+		md := internaldata.OCSliceToMetrics(r.grouper.group(metrics))
+		if r.traceContext != nil {
+			addExemplars(md, r.traceContext, since)
+		}
+		_, numPoints = md.MetricAndDataPointCount()
+		err = r.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	r.reporter.OnMetricsProcessed(ctx, r.numReceivedMessages, r.numInvalidMessages, numPoints, err)
+
+	r.numReceivedMessages = 0
+	r.numInvalidMessages = 0
+}
@@ -17,15 +17,22 @@ package statsdreceiver
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -33,6 +40,7 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport/client"
 )
@@ -85,6 +93,78 @@ func Test_statsdreceiver_New(t *testing.T) {
 			},
 			wantErr: errors.New("unsupported transport \"unknown\" for receiver \"statsd\""),
 		},
+		{
+			name: "unsupported distribution observer",
+			args: args{
+				config: Config{
+					ReceiverSettings:     defaultConfig.ReceiverSettings,
+					NetAddr:              defaultConfig.NetAddr,
+					DistributionObserver: "unknown",
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: errors.New("unsupported distribution observer \"unknown\" for receiver \"statsd\""),
+		},
+		{
+			name: "empty resource attribute tag",
+			args: args{
+				config: Config{
+					ReceiverSettings:   defaultConfig.ReceiverSettings,
+					NetAddr:            defaultConfig.NetAddr,
+					ResourceAttributes: []ResourceAttributeConfig{{Attribute: "host.name"}},
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: errors.New("empty tag in resource attributes for receiver \"statsd\""),
+		},
+		{
+			name: "invalid mapping",
+			args: args{
+				config: Config{
+					ReceiverSettings: defaultConfig.ReceiverSettings,
+					NetAddr:          defaultConfig.NetAddr,
+					Mappings:         []protocol.MappingConfig{{Match: "api.*"}},
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: fmt.Errorf("invalid mappings for receiver \"statsd\": %w", errors.New("mapping 0: name must be set")),
+		},
+		{
+			name: "invalid histogram",
+			args: args{
+				config: Config{
+					ReceiverSettings: defaultConfig.ReceiverSettings,
+					NetAddr:          defaultConfig.NetAddr,
+					Histogram:        protocol.HistogramConfig{Bounds: []float64{10, 5}},
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: fmt.Errorf("invalid histogram for receiver \"statsd\": %w", errors.New("bounds must be strictly increasing: [10 5]")),
+		},
+		{
+			name: "invalid filter",
+			args: args{
+				config: Config{
+					ReceiverSettings: defaultConfig.ReceiverSettings,
+					NetAddr:          defaultConfig.NetAddr,
+					Filter:           protocol.FilterConfig{Allow: []string{""}},
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: fmt.Errorf("invalid filter for receiver \"statsd\": %w", errors.New("allow 0: pattern must be set")),
+		},
+		{
+			name: "exemplars without source address",
+			args: args{
+				config: Config{
+					ReceiverSettings: defaultConfig.ReceiverSettings,
+					NetAddr:          defaultConfig.NetAddr,
+					Exemplars:        ExemplarsConfig{TraceContextExporter: "statsd_trace_context"},
+				},
+				nextConsumer: exportertest.NewNopMetricsExporter(),
+			},
+			wantErr: errors.New("exemplars require enable_source_address for receiver \"statsd\""),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,11 +203,38 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 				return c
 			},
 		},
+		{
+			name: "tcp",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.NetAddr.Transport = "tcp"
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.StatsD {
+				c, err := client.NewStatsD(client.TCP, host, port)
+				require.NoError(t, err)
+				return c
+			},
+		},
+		{
+			name: "source_address",
+			configFn: func() *Config {
+				cfg := createDefaultConfig().(*Config)
+				cfg.EnableSourceAddress = true
+				return cfg
+			},
+			clientFn: func(t *testing.T) *client.StatsD {
+				c, err := client.NewStatsD(client.UDP, host, port)
+				require.NoError(t, err)
+				return c
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cfg := tt.configFn()
 			cfg.NetAddr.Endpoint = addr
+			cfg.AggregationInterval = 10 * time.Millisecond
 			sink := new(exportertest.SinkMetricsExporter)
 			rcv, err := New(zap.NewNop(), *cfg, sink)
 			require.NoError(t, err)
@@ -157,6 +264,10 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 			require.Len(t, mdd, 1)
 			ocmd := internaldata.MetricsToOC(mdd[0])
 			require.Len(t, ocmd, 1)
+			if cfg.EnableSourceAddress {
+				ip := ocmd[0].Resource.GetLabels()[protocol.SourceAddressLabel]
+				assert.True(t, net.ParseIP(ip).IsLoopback(), "unexpected source address %q", ip)
+			}
 			require.Len(t, ocmd[0].Metrics, 1)
 			metric := ocmd[0].Metrics[0]
 			assert.Equal(t, statsdMetric.Name, metric.GetMetricDescriptor().GetName())
@@ -168,3 +279,124 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func Test_statsdreceiver_Exemplars(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+
+	// The clock is frozen so that the trace context is received during the
+	// aggregation interval.
+	now := time.Unix(1000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = addr
+	cfg.AggregationInterval = 10 * time.Millisecond
+	cfg.EnableSourceAddress = true
+	cfg.Exemplars.TraceContextExporter = traceContextTypeStr
+	rcv, err := New(zap.NewNop(), *cfg, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.Equal(t,
+		errors.New(`exporter "statsd_trace_context" of the exemplars of receiver "statsd" is not used by a traces pipeline`),
+		rcv.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, rcv.Shutdown(context.Background()))
+
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err = New(zap.NewNop(), *cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*statsdReceiver)
+
+	mr := transport.NewMockReporter(1)
+	r.reporter = mr
+
+	exp := newTraceContextExporter(0)
+	require.NoError(t, r.Start(context.Background(), newExportersHost(map[configmodels.Exporter]component.Exporter{
+		createDefaultTraceContextConfig(): exp,
+	})))
+	defer r.Shutdown(context.Background())
+	require.NoError(t, exp.ConsumeTraces(context.Background(), testTraces("127.0.0.1")))
+
+	statsdClient, err := client.NewStatsD(client.UDP, host, port)
+	require.NoError(t, err)
+	require.NoError(t, statsdClient.SendMetric(client.Metric{Name: "test.metric", Value: "42", Type: "c"}))
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	mdd := sink.AllMetrics()
+	require.Len(t, mdd, 1)
+	rms := mdd[0].ResourceMetrics()
+	require.Equal(t, 1, rms.Len())
+	dps := rms.At(0).InstrumentationLibraryMetrics().At(0).Metrics().At(0).IntSum().DataPoints()
+	require.Equal(t, 1, dps.Len())
+	exemplars := dps.At(0).Exemplars()
+	require.Equal(t, 1, exemplars.Len())
+	assert.Equal(t, int64(42), exemplars.At(0).Value())
+	assert.Equal(t, map[string]string{
+		"trace_id": "0102030405060708090a0b0c0d0e0f00",
+		"span_id":  "0102030405060700",
+	}, labelsOf(exemplars.At(0).FilteredLabels()))
+}
+
+func Test_statsdreceiver_Persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statsd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.NameVal = "statsd/persisted"
+	cfg.Persistence.Directory = filepath.Join(dir, "state")
+
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := New(zap.NewNop(), *cfg, sink)
+	require.NoError(t, err)
+	r := rcv.(*statsdReceiver)
+	assert.False(t, r.restoreState(), "no state was saved")
+	require.NoError(t, r.parser.Aggregate("test.metric:42|c", nil))
+	require.True(t, r.saveState())
+	assert.FileExists(t, filepath.Join(dir, "state", "statsd_persisted.json"))
+	require.NoError(t, r.server.Close())
+
+	rcv, err = New(zap.NewNop(), *cfg, sink)
+	require.NoError(t, err)
+	r = rcv.(*statsdReceiver)
+	defer r.server.Close()
+	r.restored = r.restoreState()
+	require.True(t, r.restored)
+	_, err = os.Stat(filepath.Join(dir, "state", "statsd_persisted.json"))
+	assert.True(t, os.IsNotExist(err), "the state should be removed once restored")
+
+	// The restored metrics are flushed even if no line is received.
+	r.flush()
+	mdd := sink.AllMetrics()
+	require.Len(t, mdd, 1)
+	ocmd := internaldata.MetricsToOC(mdd[0])
+	require.Len(t, ocmd, 1)
+	metrics := ocmd[0].Metrics
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "test.metric", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, int64(42), metrics[0].GetTimeseries()[0].GetPoints()[0].GetInt64Value())
+}
+
+func Test_statsdreceiver_PersistenceInvalidState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statsd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "statsd.json")
+	require.NoError(t, ioutil.WriteFile(path, []byte("not a state"), 0600))
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = testutil.GetAvailableLocalAddress(t)
+	cfg.Persistence.Directory = dir
+	rcv, err := New(zap.NewNop(), *cfg, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	r := rcv.(*statsdReceiver)
+	defer r.server.Close()
+	assert.False(t, r.restoreState())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "the invalid state should be removed")
+}
@@ -16,11 +16,13 @@ package statsdreceiver
 
 import (
 	"context"
+	"errors"
 
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
 )
 
@@ -28,6 +30,7 @@ import (
 // observability per Collector metric observability package.
 type reporter struct {
 	name          string
+	transport     string
 	spanName      string
 	logger        *zap.Logger
 	sugaredLogger *zap.SugaredLogger // Used for generic debug logging
@@ -35,9 +38,12 @@ type reporter struct {
 
 var _ (transport.Reporter) = (*reporter)(nil)
 
-func newReporter(receiverName string, logger *zap.Logger) transport.Reporter {
+// newReporter creates the reporter of a receiver, transportType being the
+// transport of its server, reported with the receive operations.
+func newReporter(receiverName string, transportType string, logger *zap.Logger) transport.Reporter {
 	return &reporter{
 		name:          receiverName,
+		transport:     transportType,
 		spanName:      receiverName + ".receiver",
 		logger:        logger,
 		sugaredLogger: logger.Sugar(),
@@ -49,8 +55,13 @@ func newReporter(receiverName string, logger *zap.Logger) transport.Reporter {
 // reporter instance. The caller code should include a call to end the
 // returned span.
 func (r *reporter) OnDataReceived(ctx context.Context) context.Context {
-	ctx = obsreport.ReceiverContext(ctx, r.name, "tcp", r.name)
-	return obsreport.StartMetricsReceiveOp(ctx, r.name, "tcp")
+	ctx = obsreport.ReceiverContext(ctx, r.name, r.transport, r.name)
+	return obsreport.StartMetricsReceiveOp(ctx, r.name, r.transport)
+}
+
+// OnPacketReceived is called for each UDP packet received from a client.
+func (r *reporter) OnPacketReceived(context.Context) {
+	recordPacketReceived(r.name)
 }
 
 // OnTranslationError is used to report a translation error from original
@@ -61,6 +72,8 @@ func (r *reporter) OnTranslationError(ctx context.Context, err error) {
 		return
 	}
 
+	recordParseError(r.name, errorReason(err))
+
 	r.logger.Debug(
 		"StatsD translation error",
 		zap.String("receiver", r.name),
@@ -83,9 +96,14 @@ func (r *reporter) OnMetricsProcessed(
 	ctx context.Context,
 	numReceivedMessages int,
 	numInvalidMessages int,
+	numMetricPoints int,
 	err error,
 ) {
+	recordLinesParsed(r.name, numReceivedMessages-numInvalidMessages)
+
 	if err != nil {
+		recordMetricPointsDropped(r.name, numMetricPoints)
+
 		r.logger.Debug(
 			"StatsD receiver failed to push metrics into pipeline",
 			zap.String("receiver", r.name),
@@ -100,7 +118,20 @@ func (r *reporter) OnMetricsProcessed(
 		})
 	}
 
-	obsreport.EndMetricsReceiveOp(ctx, "statsd", numReceivedMessages, numReceivedMessages, err)
+	obsreport.EndMetricsReceiveOp(ctx, "statsd", numMetricPoints, numMetricPoints, err)
+}
+
+// errorReason returns the reason reported for a translation error.
+func errorReason(err error) string {
+	var parseErr *protocol.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		return string(parseErr.Reason)
+	case errors.Is(err, transport.ErrPacketTruncated):
+		return reasonPacketTruncated
+	default:
+		return reasonOther
+	}
 }
 
 func (r *reporter) OnDebugf(template string, args ...interface{}) {
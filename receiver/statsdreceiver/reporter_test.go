@@ -17,11 +17,18 @@ package statsdreceiver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
 	"go.opentelemetry.io/collector/obsreport/obsreporttest"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
 )
 
 func TestReporterObservability(t *testing.T) {
@@ -30,18 +37,59 @@ func TestReporterObservability(t *testing.T) {
 	defer doneFn()
 
 	const receiverName = "fake_statsd_receiver"
-	reporter := newReporter(receiverName, zap.NewNop())
+	reporter := newReporter(receiverName, "udp", zap.NewNop())
 
 	ctx := reporter.OnDataReceived(context.Background())
 
-	reporter.OnMetricsProcessed(ctx, 17, 13, nil)
+	reporter.OnMetricsProcessed(ctx, 17, 13, 4, nil)
 
-	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 17, 0)
+	// The accepted points are the aggregated points, not the lines.
+	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "udp", 4, 0)
 
 	// Below just exercise the error paths.
 	err = errors.New("fake error for tests")
 	reporter.OnTranslationError(ctx, err)
-	reporter.OnMetricsProcessed(ctx, 10, 10, err)
+	reporter.OnMetricsProcessed(ctx, 10, 0, 10, err)
+
+	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "udp", 4, 10)
+}
+
+func TestReporterSelfMetrics(t *testing.T) {
+	const receiverName = "statsd/self_metrics"
+	reporter := newReporter(receiverName, "udp", zap.NewNop())
+	ctx := reporter.OnDataReceived(context.Background())
 
-	obsreporttest.CheckReceiverMetricsViews(t, receiverName, "tcp", 17, 10)
+	reporter.OnPacketReceived(ctx)
+	reporter.OnPacketReceived(ctx)
+	reporter.OnTranslationError(ctx, &protocol.ParseError{Reason: protocol.ReasonInvalidValue})
+	reporter.OnTranslationError(ctx, &protocol.ParseError{Reason: protocol.ReasonInvalidValue})
+	reporter.OnTranslationError(ctx, fmt.Errorf("%w: larger than the max packet size", transport.ErrPacketTruncated))
+	reporter.OnTranslationError(ctx, errors.New("unexpected"))
+	reporter.OnMetricsProcessed(ctx, 10, 2, 5, nil)
+	reporter.OnMetricsProcessed(ctx, 4, 0, 3, errors.New("consumer failed"))
+
+	assert.Equal(t, int64(2), viewSum(t, viewPacketsReceived, receiverName, ""))
+	assert.Equal(t, int64(12), viewSum(t, viewLinesParsed, receiverName, ""))
+	assert.Equal(t, int64(2), viewSum(t, viewParseErrors, receiverName, string(protocol.ReasonInvalidValue)))
+	assert.Equal(t, int64(1), viewSum(t, viewParseErrors, receiverName, reasonPacketTruncated))
+	assert.Equal(t, int64(1), viewSum(t, viewParseErrors, receiverName, reasonOther))
+	assert.Equal(t, int64(3), viewSum(t, viewMetricPointsDropped, receiverName, ""))
+}
+
+// viewSum returns the value of the view for the receiver, and for the reason
+// if not empty.
+func viewSum(t *testing.T, v *view.View, receiver, reason string) int64 {
+	rows, err := view.RetrieveData(v.Name)
+	require.NoError(t, err)
+	for _, row := range rows {
+		tags := make(map[tag.Key]string, len(row.Tags))
+		for _, tg := range row.Tags {
+			tags[tg.Key] = tg.Value
+		}
+		if tags[tagReceiverKey] != receiver || (reason != "" && tags[tagReasonKey] != reason) {
+			continue
+		}
+		return int64(row.Data.(*view.SumData).Value)
+	}
+	return 0
 }
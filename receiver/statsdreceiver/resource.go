@@ -0,0 +1,158 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+)
+
+// resourceGrouper moves the labels of the promoted tags to the resource and
+// groups the metrics by the resulting resources.
+type resourceGrouper struct {
+	// attributes maps the promoted tag keys to the resource attribute names.
+	attributes map[string]string
+}
+
+func newResourceGrouper(configs []ResourceAttributeConfig) *resourceGrouper {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	g := &resourceGrouper{attributes: make(map[string]string, len(configs))}
+	for _, cfg := range configs {
+		attribute := cfg.Attribute
+		if attribute == "" {
+			attribute = cfg.Tag
+		}
+		g.attributes[cfg.Tag] = attribute
+	}
+	return g
+}
+
+// resourceGroup holds the metrics of a single resource, keyed by the index of
+// the source metric.
+type resourceGroup struct {
+	labels  map[string]string
+	metrics []*metricspb.Metric
+	byIndex map[int]*metricspb.Metric
+}
+
+// group splits the metrics by resource, the groups and the metrics of each
+// group keeping the order of the input metrics. A nil grouper returns the
+// metrics unchanged.
+func (g *resourceGrouper) group(metrics []*metricspb.Metric) []consumerdata.MetricsData {
+	if g == nil {
+		return []consumerdata.MetricsData{{Metrics: metrics}}
+	}
+
+	var groups []*resourceGroup
+	byResource := make(map[string]*resourceGroup)
+	for i, metric := range metrics {
+		keys := metric.GetMetricDescriptor().GetLabelKeys()
+		var (
+			promoted  []int
+			remaining []*metricspb.LabelKey
+		)
+		for j, key := range keys {
+			if _, ok := g.attributes[key.Key]; ok {
+				promoted = append(promoted, j)
+			} else {
+				remaining = append(remaining, key)
+			}
+		}
+
+		for _, ts := range metric.Timeseries {
+			labels := make(map[string]string, len(promoted))
+			var values []*metricspb.LabelValue
+			for j, value := range ts.LabelValues {
+				if !contains(promoted, j) {
+					values = append(values, value)
+				} else if value.HasValue {
+					labels[g.attributes[keys[j].Key]] = value.Value
+				}
+			}
+
+			id := resourceID(labels)
+			group, ok := byResource[id]
+			if !ok {
+				group = &resourceGroup{labels: labels, byIndex: make(map[int]*metricspb.Metric)}
+				byResource[id] = group
+				groups = append(groups, group)
+			}
+			out, ok := group.byIndex[i]
+			if !ok {
+				out = &metricspb.Metric{
+					MetricDescriptor: &metricspb.MetricDescriptor{
+						Name:        metric.MetricDescriptor.Name,
+						Description: metric.MetricDescriptor.Description,
+						Unit:        metric.MetricDescriptor.Unit,
+						Type:        metric.MetricDescriptor.Type,
+						LabelKeys:   remaining,
+					},
+				}
+				group.byIndex[i] = out
+				group.metrics = append(group.metrics, out)
+			}
+			out.Timeseries = append(out.Timeseries, &metricspb.TimeSeries{
+				StartTimestamp: ts.StartTimestamp,
+				LabelValues:    values,
+				Points:         ts.Points,
+			})
+		}
+	}
+
+	mds := make([]consumerdata.MetricsData, 0, len(groups))
+	for _, group := range groups {
+		md := consumerdata.MetricsData{Metrics: group.metrics}
+		if len(group.labels) > 0 {
+			md.Resource = &resourcepb.Resource{Labels: group.labels}
+		}
+		mds = append(mds, md)
+	}
+	return mds
+}
+
+// resourceID returns a key identifying the resource labels, independently of
+// the order of the tags in the StatsD messages.
+func resourceID(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var id strings.Builder
+	for _, key := range keys {
+		id.WriteString(key)
+		id.WriteByte(0)
+		id.WriteString(labels[key])
+		id.WriteByte(0)
+	}
+	return id.String()
+}
+
+func contains(indexes []int, index int) bool {
+	for _, i := range indexes {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
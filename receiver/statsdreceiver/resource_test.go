@@ -0,0 +1,110 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+)
+
+func testMetric(name string, keys []string, values ...[]string) *metricspb.Metric {
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: name,
+			Type: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+	}
+	for _, key := range keys {
+		metric.MetricDescriptor.LabelKeys = append(metric.MetricDescriptor.LabelKeys, &metricspb.LabelKey{Key: key})
+	}
+	for _, tsValues := range values {
+		ts := &metricspb.TimeSeries{
+			Points: []*metricspb.Point{{Value: &metricspb.Point_Int64Value{Int64Value: 1}}},
+		}
+		for _, value := range tsValues {
+			ts.LabelValues = append(ts.LabelValues, &metricspb.LabelValue{Value: value, HasValue: true})
+		}
+		metric.Timeseries = append(metric.Timeseries, ts)
+	}
+	return metric
+}
+
+func TestResourceGrouper(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []ResourceAttributeConfig
+		metrics []*metricspb.Metric
+		want    []consumerdata.MetricsData
+	}{
+		{
+			name: "no resource attributes",
+			metrics: []*metricspb.Metric{
+				testMetric("a", []string{"host"}, []string{"h1"}),
+			},
+			want: []consumerdata.MetricsData{
+				{
+					Metrics: []*metricspb.Metric{
+						testMetric("a", []string{"host"}, []string{"h1"}),
+					},
+				},
+			},
+		},
+		{
+			name: "promoted tags",
+			configs: []ResourceAttributeConfig{
+				{Tag: "host", Attribute: "host.name"},
+				{Tag: "env"},
+			},
+			metrics: []*metricspb.Metric{
+				testMetric("a", []string{"env", "host", "path"}, []string{"prod", "h1", "/"}),
+				testMetric("b", []string{"host"}, []string{"h2"}),
+				testMetric("c", []string{"path"}, []string{"/"}),
+				testMetric("d", []string{"host", "quantile"}, []string{"h2", "0.5"}, []string{"h2", "0.9"}),
+				testMetric("e", []string{"host", "env", "path"}, []string{"h1", "prod", "/index"}),
+			},
+			want: []consumerdata.MetricsData{
+				{
+					Resource: &resourcepb.Resource{Labels: map[string]string{"env": "prod", "host.name": "h1"}},
+					Metrics: []*metricspb.Metric{
+						testMetric("a", []string{"path"}, []string{"/"}),
+						testMetric("e", []string{"path"}, []string{"/index"}),
+					},
+				},
+				{
+					Resource: &resourcepb.Resource{Labels: map[string]string{"host.name": "h2"}},
+					Metrics: []*metricspb.Metric{
+						testMetric("b", nil, nil),
+						testMetric("d", []string{"quantile"}, []string{"0.5"}, []string{"0.9"}),
+					},
+				},
+				{
+					Metrics: []*metricspb.Metric{
+						testMetric("c", []string{"path"}, []string{"/"}),
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newResourceGrouper(tt.configs)
+			assert.Equal(t, tt.want, g.group(tt.metrics))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// The value of "type" key in configuration of the trace context
+	// exporter.
+	traceContextTypeStr = "statsd_trace_context"
+
+	defaultTraceContextMaxAge = 5 * time.Minute
+
+	// podIPAttribute is the resource attribute holding the IP address of the
+	// pod that sent the spans, as set by the k8s_tagger processor.
+	podIPAttribute = "k8s.pod.ip"
+)
+
+var timeNow = time.Now
+
+// NewTraceContextExporterFactory creates a factory for the
+// statsd_trace_context exporter, which records the trace context of the spans
+// received from each client for the exemplars of the StatsD receiver.
+func NewTraceContextExporterFactory() component.ExporterFactory {
+	return exporterhelper.NewFactory(
+		traceContextTypeStr,
+		createDefaultTraceContextConfig,
+		exporterhelper.WithTraces(createTraceContextExporter),
+	)
+}
+
+func createDefaultTraceContextConfig() configmodels.Exporter {
+	return &TraceContextExporterConfig{
+		ExporterSettings: configmodels.ExporterSettings{
+			TypeVal: configmodels.Type(traceContextTypeStr),
+			NameVal: traceContextTypeStr,
+		},
+		MaxAge: defaultTraceContextMaxAge,
+	}
+}
+
+func createTraceContextExporter(
+	_ context.Context,
+	_ component.ExporterCreateParams,
+	cfg configmodels.Exporter,
+) (component.TraceExporter, error) {
+	c := cfg.(*TraceContextExporterConfig)
+	return newTraceContextExporter(c.MaxAge), nil
+}
+
+// traceContext is the context of a span received from a client.
+type traceContext struct {
+	traceID  string
+	spanID   string
+	received time.Time
+}
+
+// traceContextExporter keeps the trace context of the last span received
+// from each client address.
+type traceContextExporter struct {
+	maxAge time.Duration
+
+	mu        sync.Mutex
+	contexts  map[string]traceContext
+	lastPrune time.Time
+}
+
+var _ component.TraceExporter = (*traceContextExporter)(nil)
+
+func newTraceContextExporter(maxAge time.Duration) *traceContextExporter {
+	if maxAge <= 0 {
+		maxAge = defaultTraceContextMaxAge
+	}
+	return &traceContextExporter{
+		maxAge:    maxAge,
+		contexts:  make(map[string]traceContext),
+		lastPrune: timeNow(),
+	}
+}
+
+func (e *traceContextExporter) Start(context.Context, component.Host) error {
+	return nil
+}
+
+func (e *traceContextExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// ConsumeTraces records the last span of each resource. The client address is
+// the k8s.pod.ip resource attribute if set, the address of the client that
+// sent the spans to the collector otherwise.
+func (e *traceContextExporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	now := timeNow()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		ip := clientIP(ctx, rs.Resource())
+		if ip == "" {
+			continue
+		}
+		if span, ok := lastSpan(rs); ok {
+			e.contexts[ip] = traceContext{
+				traceID:  span.TraceID().String(),
+				spanID:   span.SpanID().String(),
+				received: now,
+			}
+		}
+	}
+
+	e.prune(now)
+	return nil
+}
+
+// get returns the trace context of the last span received from the client
+// since the given time.
+func (e *traceContextExporter) get(ip string, since time.Time) (traceContext, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tc, ok := e.contexts[ip]
+	if !ok || tc.received.Before(since) {
+		return traceContext{}, false
+	}
+	return tc, true
+}
+
+// prune removes the trace contexts older than maxAge, at most once per
+// maxAge.
+func (e *traceContextExporter) prune(now time.Time) {
+	if now.Sub(e.lastPrune) < e.maxAge {
+		return
+	}
+	for ip, tc := range e.contexts {
+		if now.Sub(tc.received) >= e.maxAge {
+			delete(e.contexts, ip)
+		}
+	}
+	e.lastPrune = now
+}
+
+func clientIP(ctx context.Context, resource pdata.Resource) string {
+	if !resource.IsNil() {
+		if ip, ok := resource.Attributes().Get(podIPAttribute); ok && ip.StringVal() != "" {
+			return ip.StringVal()
+		}
+	}
+	if c, ok := client.FromContext(ctx); ok {
+		return c.IP
+	}
+	return ""
+}
+
+// lastSpan returns the last span of the resource with a trace and span ID.
+func lastSpan(rs pdata.ResourceSpans) (pdata.Span, bool) {
+	ilss := rs.InstrumentationLibrarySpans()
+	for i := ilss.Len() - 1; i >= 0; i-- {
+		ils := ilss.At(i)
+		if ils.IsNil() {
+			continue
+		}
+		spans := ils.Spans()
+		for j := spans.Len() - 1; j >= 0; j-- {
+			span := spans.At(j)
+			if !span.IsNil() && len(span.TraceID()) > 0 && len(span.SpanID()) > 0 {
+				return span, true
+			}
+		}
+	}
+	return pdata.Span{}, false
+}
@@ -0,0 +1,104 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsdreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configcheck"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.uber.org/zap"
+)
+
+// testTraces returns traces holding a single span per resource, the resources
+// having the given k8s.pod.ip attributes if not empty.
+func testTraces(ips ...string) pdata.Traces {
+	td := pdata.NewTraces()
+	rss := td.ResourceSpans()
+	rss.Resize(len(ips))
+	for i, ip := range ips {
+		rs := rss.At(i)
+		rs.Resource().InitEmpty()
+		if ip != "" {
+			rs.Resource().Attributes().InsertString(podIPAttribute, ip)
+		}
+		rs.InstrumentationLibrarySpans().Resize(1)
+		spans := rs.InstrumentationLibrarySpans().At(0).Spans()
+		spans.Resize(1)
+		spans.At(0).SetTraceID(pdata.NewTraceID([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, byte(i)}))
+		spans.At(0).SetSpanID(pdata.NewSpanID([]byte{1, 2, 3, 4, 5, 6, 7, byte(i)}))
+	}
+	return td
+}
+
+func TestCreateTraceContextExporter(t *testing.T) {
+	factory := NewTraceContextExporterFactory()
+	cfg := factory.CreateDefaultConfig()
+	assert.NoError(t, configcheck.ValidateConfig(cfg))
+
+	params := component.ExporterCreateParams{Logger: zap.NewNop()}
+	exp, err := factory.CreateTraceExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.IsType(t, &traceContextExporter{}, exp)
+	assert.Equal(t, defaultTraceContextMaxAge, exp.(*traceContextExporter).maxAge)
+}
+
+func TestTraceContextExporter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	e := newTraceContextExporter(time.Minute)
+
+	ctx := client.NewContext(context.Background(), &client.Client{IP: "10.0.0.3"})
+	require.NoError(t, e.ConsumeTraces(ctx, testTraces("10.0.0.1", "10.0.0.2", "")))
+	require.NoError(t, e.ConsumeTraces(context.Background(), testTraces("")))
+
+	tc, ok := e.get("10.0.0.1", now)
+	require.True(t, ok)
+	assert.Equal(t, traceContext{
+		traceID:  "0102030405060708090a0b0c0d0e0f00",
+		spanID:   "0102030405060700",
+		received: now,
+	}, tc)
+
+	tc, ok = e.get("10.0.0.2", now)
+	require.True(t, ok)
+	assert.Equal(t, "0102030405060701", tc.spanID)
+
+	// The spans without pod IP are associated with the sending client.
+	tc, ok = e.get("10.0.0.3", now)
+	require.True(t, ok)
+	assert.Equal(t, "0102030405060702", tc.spanID)
+
+	_, ok = e.get("10.0.0.1", now.Add(time.Second))
+	assert.False(t, ok, "trace context received before the interval")
+	_, ok = e.get("10.0.0.4", now)
+	assert.False(t, ok)
+
+	now = now.Add(30 * time.Second)
+	require.NoError(t, e.ConsumeTraces(context.Background(), testTraces("10.0.0.1")))
+	now = now.Add(30 * time.Second)
+	require.NoError(t, e.ConsumeTraces(context.Background(), testTraces()))
+	assert.Len(t, e.contexts, 1, "trace contexts older than max_age are pruned")
+	_, ok = e.contexts["10.0.0.1"]
+	assert.True(t, ok)
+}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"strconv"
 )
 
 // StatsD defines the properties of a StatsD connection.
@@ -58,13 +59,15 @@ func (s *StatsD) connect(transport Transport) error {
 		cl.Close()
 	}
 
-	address := fmt.Sprintf("%s:%d", s.Host, s.Port)
+	address := net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
 
 	var err error
 	switch transport {
 	case TCP:
-		// TODO: implement TCP support
-		return fmt.Errorf("TCP unsupported")
+		s.Conn, err = net.Dial("tcp", address)
+		if err != nil {
+			return err
+		}
 	case UDP:
 		var udpAddr *net.UDPAddr
 		udpAddr, err = net.ResolveUDPAddr("udp", address)
@@ -92,9 +95,10 @@ func (s *StatsD) Disconnect() error {
 	return err
 }
 
-// SendMetric sends the input metric to the StatsD connection.
+// SendMetric sends the input metric to the StatsD connection. Each metric
+// is terminated by a newline so it can be delimited over a TCP stream.
 func (s *StatsD) SendMetric(metric Metric) error {
-	_, err := fmt.Fprint(s.Conn, metric.String())
+	_, err := fmt.Fprintln(s.Conn, metric.String())
 	if err != nil {
 		return err
 	}
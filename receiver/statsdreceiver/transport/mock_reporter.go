@@ -38,6 +38,9 @@ func (m *MockReporter) OnDataReceived(ctx context.Context) context.Context {
 	return ctx
 }
 
+func (m *MockReporter) OnPacketReceived(ctx context.Context) {
+}
+
 func (m *MockReporter) OnTranslationError(ctx context.Context, err error) {
 }
 
@@ -45,6 +48,7 @@ func (m *MockReporter) OnMetricsProcessed(
 	ctx context.Context,
 	numReceivedMessages int,
 	numInvalidMessages int,
+	numMetricPoints int,
 	err error,
 ) {
 	m.wgMetricsProcessed.Done()
@@ -0,0 +1,31 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package transport
+
+import (
+	"errors"
+	"net"
+)
+
+const reusePortSupported = false
+
+// listenPacketReusePort returns an error on the operating systems without
+// SO_REUSEPORT.
+func listenPacketReusePort(string, string) (net.PacketConn, error) {
+	return nil, errors.New("multiple UDP readers require SO_REUSEPORT, which is not supported on this operating system")
+}
@@ -17,30 +17,40 @@ package transport
 import (
 	"context"
 	"errors"
-
-	"go.opentelemetry.io/collector/consumer"
-
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+	"net"
 )
 
 var (
 	errNilListenAndServeParameters = errors.New("no parameter of ListenAndServe can be nil")
+
+	// ErrPacketTruncated is wrapped by the errors reported for the UDP packets
+	// larger than the max packet size, whose last lines are dropped, and for
+	// the TCP lines larger than it or without newline, which are dropped.
+	ErrPacketTruncated = errors.New("packet truncated")
 )
 
+// Metric is a StatsD line received by a Server.
+type Metric struct {
+	// Raw is the line, without the trailing newline.
+	Raw string
+
+	// Addr is the address of the client that sent the line.
+	Addr net.Addr
+}
+
 // Server abstracts the type of transport being used and offer an
 // interface to handle serving clients over that transport.
 type Server interface {
 	// ListenAndServe is a blocking call that starts to listen for client messages
-	// on the specific transport, and prepares the message to be processed by
-	// the Parser and passed to the next consumer.
+	// on the specific transport, and sends each received line to transferChan
+	// so it can be aggregated by the receiver.
 	ListenAndServe(
-		p protocol.Parser,
-		mc consumer.MetricsConsumer,
 		r Reporter,
+		transferChan chan<- Metric,
 	) error
 
 	// Close stops any running ListenAndServe, however, it waits for any
-	// data already received to be parsed and sent to the next consumer.
+	// data already received to be sent to transferChan.
 	Close() error
 }
 
@@ -53,6 +63,9 @@ type Reporter interface {
 	// returned span.
 	OnDataReceived(ctx context.Context) context.Context
 
+	// OnPacketReceived is called for each UDP packet received from a client.
+	OnPacketReceived(ctx context.Context)
+
 	// OnTranslationError is used to report a translation error from original
 	// format to the internal format of the Collector. The context
 	// passed to it should be the ones returned by OnDataReceived.
@@ -60,12 +73,14 @@ type Reporter interface {
 
 	// OnMetricsProcessed is called when the received data is passed to next
 	// consumer on the pipeline. The context passed to it should be the
-	// one returned by OnDataReceived. The error should be error returned by
+	// one returned by OnDataReceived. numMetricPoints is the number of points
+	// of the aggregated metrics. The error should be error returned by
 	// the next consumer - the reporter is expected to handle nil error too.
 	OnMetricsProcessed(
 		ctx context.Context,
 		numReceivedMessages int,
 		numInvalidMessages int,
+		numMetricPoints int,
 		err error)
 
 	// OnDebugf allows less structured reporting for debugging scenarios.
@@ -20,14 +20,12 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/testutil"
-	"go.opentelemetry.io/collector/translator/internaldata"
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport/client"
 )
 
@@ -40,12 +38,21 @@ func Test_Server_ListenAndServe(t *testing.T) {
 		{
 			name: "udp",
 			buildServerFn: func(addr string) (Server, error) {
-				return NewUDPServer(addr)
+				return NewUDPServer(addr, 0, 0, 0)
 			},
 			buildClientFn: func(host string, port int) (*client.StatsD, error) {
 				return client.NewStatsD(client.UDP, host, port)
 			},
 		},
+		{
+			name: "tcp",
+			buildServerFn: func(addr string) (Server, error) {
+				return NewTCPServer(addr, 0, time.Second, 0)
+			},
+			buildClientFn: func(host string, port int) (*client.StatsD, error) {
+				return client.NewStatsD(client.TCP, host, port)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -59,16 +66,14 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			port, err := strconv.Atoi(portStr)
 			require.NoError(t, err)
 
-			mc := new(exportertest.SinkMetricsExporter)
-			p := &protocol.StatsDParser{}
-			require.NoError(t, err)
-			mr := NewMockReporter(1)
+			mr := NewMockReporter(0)
+			transferChan := make(chan Metric, 10)
 
 			wgListenAndServe := sync.WaitGroup{}
 			wgListenAndServe.Add(1)
 			go func() {
 				defer wgListenAndServe.Done()
-				assert.Error(t, srv.ListenAndServe(p, mc, mr))
+				assert.Error(t, srv.ListenAndServe(mr, transferChan))
 			}()
 
 			runtime.Gosched()
@@ -88,23 +93,17 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			err = gc.Disconnect()
 			assert.NoError(t, err)
 
-			mr.WaitAllOnMetricsProcessedCalls()
+			metric := <-transferChan
+			assert.Equal(t, "test.metric:42|c", metric.Raw)
+			require.NotNil(t, metric.Addr)
+			peerHost, _, err := net.SplitHostPort(metric.Addr.String())
+			require.NoError(t, err)
+			assert.True(t, net.ParseIP(peerHost).IsLoopback(), "unexpected client address %s", metric.Addr)
 
 			err = srv.Close()
 			assert.NoError(t, err)
 
 			wgListenAndServe.Wait()
-
-			mdd := mc.AllMetrics()
-			require.Len(t, mdd, 1)
-			ocmd := internaldata.MetricsToOC(mdd[0])
-			require.Len(t, ocmd, 1)
-			require.Len(t, ocmd[0].Metrics, 1)
-			metric := ocmd[0].Metrics[0]
-			assert.Equal(t, "test.metric", metric.GetMetricDescriptor().GetName())
-
-			// require.Equal(t, 1, len(mc.md))
-			// assert.Equal(t, "test.metric", mc.md[0].Metrics[0].GetMetricDescriptor().GetName())
 		})
 	}
 }
@@ -0,0 +1,254 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TCPIdleTimeoutDefault is the default timeout for idle TCP connections.
+	TCPIdleTimeoutDefault = 30 * time.Second
+)
+
+type tcpServer struct {
+	ln             net.Listener
+	wg             sync.WaitGroup
+	maxLineSize    int
+	idleTimeout    time.Duration
+	maxConnections int
+	reporter       Reporter
+
+	connsMtx sync.Mutex
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+var _ (Server) = (*tcpServer)(nil)
+
+// NewTCPServer creates a transport.Server using TCP as its transport. Each
+// connection carries newline-delimited StatsD lines and is closed once idle
+// for idleTimeout. The lines larger than maxLineSize, or not terminated by a
+// newline when the connection is closed, are dropped. A maxLineSize of zero
+// uses UDPMaxPacketSizeDefault. Connections beyond maxConnections are
+// rejected, a maxConnections of zero doesn't limit the number of connections.
+func NewTCPServer(
+	addr string,
+	maxLineSize int,
+	idleTimeout time.Duration,
+	maxConnections int,
+) (Server, error) {
+	if maxLineSize < 0 {
+		return nil, fmt.Errorf("invalid max line size: %d", maxLineSize)
+	}
+
+	if maxLineSize == 0 {
+		maxLineSize = UDPMaxPacketSizeDefault
+	}
+
+	if idleTimeout < 0 {
+		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = TCPIdleTimeoutDefault
+	}
+
+	if maxConnections < 0 {
+		return nil, fmt.Errorf("invalid max connections: %d", maxConnections)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	t := tcpServer{
+		ln:             ln,
+		maxLineSize:    maxLineSize,
+		idleTimeout:    idleTimeout,
+		maxConnections: maxConnections,
+		conns:          make(map[net.Conn]struct{}),
+	}
+	return &t, nil
+}
+
+func (t *tcpServer) ListenAndServe(
+	reporter Reporter,
+	transferChan chan<- Metric,
+) error {
+	if reporter == nil || transferChan == nil {
+		return errNilListenAndServeParameters
+	}
+
+	t.reporter = reporter
+	var err error
+	for {
+		conn, acceptErr := t.ln.Accept()
+		if acceptErr == nil {
+			if !t.trackConnection(conn) {
+				t.reporter.OnDebugf(
+					"TCP Transport (%s) - rejected connection from %s: connection limit (%d) reached",
+					t.ln.Addr(),
+					conn.RemoteAddr(),
+					t.maxConnections)
+				conn.Close()
+				continue
+			}
+			go func(c net.Conn) {
+				defer t.wg.Done()
+				defer t.untrackConnection(c)
+				t.handleConnection(c, transferChan)
+			}(conn)
+			continue
+		}
+
+		if netErr, ok := acceptErr.(net.Error); ok {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - Accept (temporary=%v) net.Error: %v",
+				t.ln.Addr(),
+				netErr.Temporary(),
+				netErr)
+			if netErr.Temporary() {
+				continue
+			}
+		}
+
+		err = acceptErr
+		break
+	}
+
+	t.reporter.OnDebugf(
+		"TCP Transport (%s) exiting Accept loop error: %v",
+		t.ln.Addr(),
+		err)
+
+	return err
+}
+
+// Close stops accepting connections, closes the active ones and waits for
+// the lines already read from them to be sent to the transfer channel.
+func (t *tcpServer) Close() error {
+	err := t.ln.Close()
+
+	t.connsMtx.Lock()
+	t.closed = true
+	for conn := range t.conns {
+		conn.Close()
+	}
+	t.connsMtx.Unlock()
+
+	t.wg.Wait()
+	return err
+}
+
+// trackConnection registers conn as active, it returns false if the server
+// is closed or the connection limit is reached.
+func (t *tcpServer) trackConnection(conn net.Conn) bool {
+	t.connsMtx.Lock()
+	defer t.connsMtx.Unlock()
+
+	if t.closed || (t.maxConnections > 0 && len(t.conns) >= t.maxConnections) {
+		return false
+	}
+	t.conns[conn] = struct{}{}
+	t.wg.Add(1)
+	return true
+}
+
+func (t *tcpServer) untrackConnection(conn net.Conn) {
+	t.connsMtx.Lock()
+	delete(t.conns, conn)
+	t.connsMtx.Unlock()
+}
+
+func (t *tcpServer) handleConnection(
+	conn net.Conn,
+	transferChan chan<- Metric,
+) {
+	defer conn.Close()
+	// The extra byte holds the newline of the lines of maxLineSize.
+	reader := bufio.NewReaderSize(conn, t.maxLineSize+1)
+	discarding := false
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(t.idleTimeout)); err != nil {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - conn.SetReadDeadline error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		// reader.ReadSlice call below will block until either:
+		//
+		// * a '\n' char is read
+		// * the buffer is full, the line being larger than maxLineSize
+		// * the connection is closed (either by client or server)
+		// * an idle timeout happens (see call to conn.SetReadDeadline above)
+		//
+		// Notice that it is possible for the function to return with error at
+		// the same time that it returns data (typically the error is io.EOF in
+		// this case), that data is a partial line.
+		data, err := reader.ReadSlice((byte)('\n'))
+		line := strings.TrimSpace(string(data))
+		switch {
+		case err == bufio.ErrBufferFull:
+			// The rest of the line is discarded up to its newline.
+			if !discarding {
+				t.dropLine(fmt.Sprintf("larger than the max line size (%d bytes)", t.maxLineSize))
+				discarding = true
+			}
+			continue
+		case discarding:
+			discarding = false
+		case err == nil && len(data) > t.maxLineSize+1:
+			// The buffer is never smaller than 16 bytes.
+			t.dropLine(fmt.Sprintf("larger than the max line size (%d bytes)", t.maxLineSize))
+		case err == nil && line != "":
+			transferChan <- Metric{Raw: line, Addr: conn.RemoteAddr()}
+		case line != "":
+			// A partial line is not parsed as a valid but wrong metric.
+			t.dropLine("not terminated by a newline")
+		}
+
+		if err == nil {
+			continue
+		}
+
+		if err != io.EOF {
+			t.reporter.OnDebugf(
+				"TCP Transport (%s) - connection from %s error: %v",
+				t.ln.Addr(),
+				conn.RemoteAddr(),
+				err)
+		}
+		return
+	}
+}
+
+// dropLine reports a line that is not sent to the transfer channel.
+func (t *tcpServer) dropLine(reason string) {
+	t.reporter.OnTranslationError(context.Background(), fmt.Errorf(
+		"%w: TCP line %s, dropped",
+		ErrPacketTruncated,
+		reason))
+}
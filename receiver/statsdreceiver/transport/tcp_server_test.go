@@ -0,0 +1,125 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func Test_NewTCPServer_InvalidParameters(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+
+	_, err := NewTCPServer(addr, -1, time.Second, 0)
+	assert.EqualError(t, err, "invalid max line size: -1")
+
+	_, err = NewTCPServer(addr, 0, -time.Second, 0)
+	assert.EqualError(t, err, "invalid idle timeout: -1s")
+
+	_, err = NewTCPServer(addr, 0, time.Second, -1)
+	assert.EqualError(t, err, "invalid max connections: -1")
+}
+
+func Test_TCPServer_ConnectionHandling(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewTCPServer(addr, 0, 100*time.Millisecond, 1)
+	require.NoError(t, err)
+
+	transferChan := make(chan Metric, 10)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, srv.ListenAndServe(NewMockReporter(0), transferChan))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Several lines in a single write, the last one without a newline is
+	// dropped once the connection is closed.
+	_, err = conn.Write([]byte("a:1|c\nb:2|g\n\nc:3"))
+	require.NoError(t, err)
+	assert.Equal(t, "a:1|c", (<-transferChan).Raw)
+	assert.Equal(t, "b:2|g", (<-transferChan).Raw)
+
+	// The connection limit is reached, so the second connection is closed by
+	// the server.
+	rejected, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer rejected.Close()
+	require.NoError(t, rejected.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = bufio.NewReader(rejected).ReadByte()
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err), "rejected connection was not closed")
+
+	// The first connection is closed after being idle.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = bufio.NewReader(conn).ReadByte()
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err), "idle connection was not closed")
+
+	assert.NoError(t, srv.Close())
+	wg.Wait()
+	assert.Empty(t, transferChan)
+}
+
+func Test_TCPServer_MaxLineSize(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewTCPServer(addr, 32, time.Second, 0)
+	require.NoError(t, err)
+
+	transferChan := make(chan Metric, 10)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, srv.ListenAndServe(NewMockReporter(0), transferChan))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	// A line of exactly the max line size is kept, the larger lines are
+	// dropped up to their newline, even when written in several parts.
+	line := "a:" + strings.Repeat("1", 28) + "|c"
+	_, err = conn.Write([]byte(line + "\nb:" + strings.Repeat("2", 100)))
+	require.NoError(t, err)
+	assert.Equal(t, line, (<-transferChan).Raw)
+	_, err = conn.Write([]byte(strings.Repeat("2", 100) + "|c\nc:3|c\nd:" + strings.Repeat("4", 30) + "|c\ne:5|c\nf:6"))
+	require.NoError(t, err)
+	assert.Equal(t, "c:3|c", (<-transferChan).Raw)
+	assert.Equal(t, "e:5|c", (<-transferChan).Raw)
+
+	// The partial line is dropped when the connection is closed.
+	require.NoError(t, conn.Close())
+	assert.NoError(t, srv.Close())
+	wg.Wait()
+	assert.Empty(t, transferChan)
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
@@ -17,60 +17,135 @@ package transport
 import (
 	"bytes"
 	"context"
-	"io"
+	"fmt"
 	"net"
-	"strings"
-
-	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
-	"go.opentelemetry.io/collector/consumer"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
-	"go.opentelemetry.io/collector/translator/internaldata"
+)
 
-	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+const (
+	// UDPMaxPacketSizeDefault is the default maximum size of the UDP packets,
+	// it is the maximum size for an UDP packet body (assuming ipv6).
+	UDPMaxPacketSizeDefault = 65527
 )
 
 type udpServer struct {
-	packetConn net.PacketConn
-	reporter   Reporter
+	packetConns   []net.PacketConn
+	maxPacketSize int
+	reporter      Reporter
 }
 
 var _ (Server) = (*udpServer)(nil)
 
-// NewUDPServer creates a transport.Server using UDP as its transport.
-func NewUDPServer(addr string) (Server, error) {
-	packetConn, err := net.ListenPacket("udp", addr)
-	if err != nil {
-		return nil, err
+// NewUDPServer creates a transport.Server using UDP as its transport. Packets
+// larger than maxPacketSize are truncated to their last complete line, a
+// maxPacketSize of zero uses UDPMaxPacketSizeDefault. If readBufferSize is
+// positive it sets the size of the operating system receive buffer of the
+// socket, a larger buffer avoids dropping packets during bursts.
+//
+// If readers is larger than one, as many sockets are bound to addr with
+// SO_REUSEPORT, each read by its own goroutine, so that the kernel spreads
+// the packets of the clients over several CPUs. A readers of zero uses a
+// single socket.
+func NewUDPServer(addr string, maxPacketSize int, readBufferSize int, readers int) (Server, error) {
+	if maxPacketSize < 0 {
+		return nil, fmt.Errorf("invalid max packet size: %d", maxPacketSize)
+	}
+
+	if maxPacketSize == 0 {
+		maxPacketSize = UDPMaxPacketSizeDefault
+	}
+
+	if readBufferSize < 0 {
+		return nil, fmt.Errorf("invalid read buffer size: %d", readBufferSize)
+	}
+
+	if readers < 0 {
+		return nil, fmt.Errorf("invalid number of readers: %d", readers)
+	}
+
+	if readers == 0 {
+		readers = 1
 	}
 
 	u := udpServer{
-		packetConn: packetConn,
+		maxPacketSize: maxPacketSize,
+	}
+	for i := 0; i < readers; i++ {
+		packetConn, err := u.listen(addr, readers)
+		if err != nil {
+			u.Close()
+			return nil, err
+		}
+		u.packetConns = append(u.packetConns, packetConn)
+
+		if readBufferSize > 0 {
+			if err = packetConn.(*net.UDPConn).SetReadBuffer(readBufferSize); err != nil {
+				u.Close()
+				return nil, err
+			}
+		}
 	}
 	return &u, nil
 }
 
+// listen binds a new socket, the sockets after the first one being bound to
+// the address of the first one in case addr has an ephemeral port.
+func (u *udpServer) listen(addr string, readers int) (net.PacketConn, error) {
+	if readers == 1 {
+		return net.ListenPacket("udp", addr)
+	}
+	if len(u.packetConns) > 0 {
+		addr = u.packetConns[0].LocalAddr().String()
+	}
+	return listenPacketReusePort("udp", addr)
+}
+
 func (u *udpServer) ListenAndServe(
-	parser protocol.Parser,
-	nextConsumer consumer.MetricsConsumer,
 	reporter Reporter,
+	transferChan chan<- Metric,
 ) error {
-	if parser == nil || nextConsumer == nil || reporter == nil {
+	if reporter == nil || transferChan == nil {
 		return errNilListenAndServeParameters
 	}
 
 	u.reporter = reporter
 
-	buf := make([]byte, 65527) // max size for udp packet body (assuming ipv6)
+	errs := make(chan error, len(u.packetConns))
+	for _, packetConn := range u.packetConns {
+		go func(packetConn net.PacketConn) {
+			errs <- u.read(packetConn, transferChan)
+		}(packetConn)
+	}
+
+	// Each reader only stops on a permanent error, such as the closing of
+	// its socket, the first error is returned once all of them stopped.
+	var err error
+	for range u.packetConns {
+		if readErr := <-errs; err == nil {
+			err = readErr
+		}
+	}
+	return err
+}
+
+// read reads the packets of packetConn until a permanent error. The buffer
+// is reused for all the packets, only the lines sent to transferChan are
+// allocated.
+func (u *udpServer) read(packetConn net.PacketConn, transferChan chan<- Metric) error {
+	// The extra byte allows detecting the packets larger than maxPacketSize,
+	// the operating system silently truncates packets to the buffer size.
+	buf := make([]byte, u.maxPacketSize+1)
 	for {
-		n, _, err := u.packetConn.ReadFrom(buf)
+		n, addr, err := packetConn.ReadFrom(buf)
+		if n > u.maxPacketSize {
+			n = u.truncatePacket(buf[:n])
+		}
 		if n > 0 {
-			bufCopy := make([]byte, n)
-			copy(bufCopy, buf)
-			u.handlePacket(parser, nextConsumer, bufCopy)
+			u.reporter.OnPacketReceived(context.Background())
+			u.handlePacket(buf[:n], addr, transferChan)
 		}
 		if err != nil {
 			u.reporter.OnDebugf("UDP Transport (%s) - ReadFrom error: %v",
-				u.packetConn.LocalAddr(),
+				packetConn.LocalAddr(),
 				err)
 			if netErr, ok := err.(net.Error); ok {
 				if netErr.Temporary() {
@@ -83,43 +158,45 @@ func (u *udpServer) ListenAndServe(
 }
 
 func (u *udpServer) Close() error {
-	return u.packetConn.Close()
+	var err error
+	for _, packetConn := range u.packetConns {
+		if closeErr := packetConn.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// truncatePacket reports a packet larger than maxPacketSize and returns the
+// size of its complete lines within maxPacketSize, so a partial line is not
+// parsed as a valid but wrong metric.
+func (u *udpServer) truncatePacket(data []byte) int {
+	n := bytes.LastIndexByte(data[:u.maxPacketSize], '\n') + 1
+	u.reporter.OnTranslationError(context.Background(), fmt.Errorf(
+		"%w: larger than the max packet size (%d bytes), truncated to %d bytes",
+		ErrPacketTruncated,
+		u.maxPacketSize,
+		n))
+	return n
 }
 
+// handlePacket sends each of the newline-delimited lines of the packet to
+// transferChan.
 func (u *udpServer) handlePacket(
-	p protocol.Parser,
-	nextConsumer consumer.MetricsConsumer,
 	data []byte,
+	addr net.Addr,
+	transferChan chan<- Metric,
 ) {
-	ctx := u.reporter.OnDataReceived(context.Background())
-	var numReceivedMessages, numInvalidMessages int
-	var metrics []*metricspb.Metric
-	buf := bytes.NewBuffer(data)
-	for {
-		bytes, err := buf.ReadBytes((byte)('\n'))
-		if err == io.EOF {
-			if len(bytes) == 0 {
-				// Completed without errors.
-				break
-			}
+	for len(data) > 0 {
+		var line []byte
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line, data = data[:i], data[i+1:]
+		} else {
+			line, data = data, nil
 		}
-		line := strings.TrimSpace(string(bytes))
-		if line != "" {
-			numReceivedMessages++
-			metric, err := p.Parse(line)
-			if err != nil {
-				numInvalidMessages++
-				u.reporter.OnTranslationError(ctx, err)
-				continue
-			}
-
-			metrics = append(metrics, metric)
+		// The line is copied out of the read buffer by the conversion.
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			transferChan <- Metric{Raw: string(line), Addr: addr}
 		}
 	}
-
-	md := consumerdata.MetricsData{
-		Metrics: metrics,
-	}
-	err := nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
-	u.reporter.OnMetricsProcessed(ctx, numReceivedMessages, numInvalidMessages, err)
 }
@@ -0,0 +1,217 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+)
+
+func Test_NewUDPServer_InvalidParameters(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+
+	_, err := NewUDPServer(addr, -1, 0, 0)
+	assert.EqualError(t, err, "invalid max packet size: -1")
+
+	_, err = NewUDPServer(addr, 0, -1, 0)
+	assert.EqualError(t, err, "invalid read buffer size: -1")
+
+	_, err = NewUDPServer(addr, 0, 0, -1)
+	assert.EqualError(t, err, "invalid number of readers: -1")
+}
+
+func Test_UDPServer_Packets(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewUDPServer(addr, 32, 1<<20, 0)
+	require.NoError(t, err)
+
+	transferChan := make(chan Metric, 10)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, srv.ListenAndServe(NewMockReporter(0), transferChan))
+	}()
+
+	conn, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Multiple lines in a single packet, with a trailing line without newline.
+	_, err = conn.Write([]byte("a:1|c\nb:2|g\r\n\nc:3|c"))
+	require.NoError(t, err)
+	assert.Equal(t, "a:1|c", (<-transferChan).Raw)
+	assert.Equal(t, "b:2|g", (<-transferChan).Raw)
+	assert.Equal(t, "c:3|c", (<-transferChan).Raw)
+
+	// A packet larger than the max packet size is truncated to its last
+	// complete line.
+	_, err = conn.Write([]byte("d:4|c\ne:5|c\nf:1234567890123456789|c\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "d:4|c", (<-transferChan).Raw)
+	assert.Equal(t, "e:5|c", (<-transferChan).Raw)
+
+	// A packet of exactly the max packet size is not truncated.
+	line := "g:" + strings.Repeat("1", 27) + "|c"
+	_, err = conn.Write([]byte(line))
+	require.NoError(t, err)
+	assert.Equal(t, line, (<-transferChan).Raw)
+
+	assert.NoError(t, srv.Close())
+	wg.Wait()
+	assert.Empty(t, transferChan)
+}
+
+func Test_UDPServer_MultipleReaders(t *testing.T) {
+	if !reusePortSupported {
+		t.Skip("SO_REUSEPORT is not supported on this operating system")
+	}
+
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewUDPServer(addr, 0, 0, 4)
+	require.NoError(t, err)
+	require.Len(t, srv.(*udpServer).packetConns, 4)
+
+	transferChan := make(chan Metric, 100)
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.Error(t, srv.ListenAndServe(NewMockReporter(0), transferChan))
+	}()
+
+	// The kernel balances the packets by client address, all the lines are
+	// received whichever socket reads them.
+	const numClients = 8
+	for i := 0; i < numClients; i++ {
+		conn, err := net.Dial("udp", addr)
+		require.NoError(t, err)
+		_, err = conn.Write([]byte("a:1|c\nb:2|g"))
+		require.NoError(t, err)
+		require.NoError(t, conn.Close())
+	}
+	lines := map[string]int{}
+	for i := 0; i < 2*numClients; i++ {
+		lines[(<-transferChan).Raw]++
+	}
+	assert.Equal(t, map[string]int{"a:1|c": numClients, "b:2|g": numClients}, lines)
+
+	// Closing the server stops all the readers.
+	assert.NoError(t, srv.Close())
+	wg.Wait()
+}
+
+// BenchmarkUDPServer measures the packets per second going through the
+// readers and the single goroutine aggregating their lines, as done by the
+// receiver, and the share of the packets dropped on the way.
+func BenchmarkUDPServer(b *testing.B) {
+	for _, readers := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("readers=%d", readers), func(b *testing.B) {
+			if readers > 1 && !reusePortSupported {
+				b.Skip("SO_REUSEPORT is not supported on this operating system")
+			}
+			benchmarkUDPServer(b, readers)
+		})
+	}
+}
+
+func benchmarkUDPServer(b *testing.B, readers int) {
+	const numClients = 8
+	packet := []byte("api.requests:1|c|#route:checkout\napi.latency:12.5|ms|#route:checkout")
+	const linesPerPacket = 2
+
+	srv, err := NewUDPServer("localhost:0", 0, 8<<20, readers)
+	require.NoError(b, err)
+	addr := srv.(*udpServer).packetConns[0].LocalAddr().String()
+
+	transferChan := make(chan Metric, 1024*readers)
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		srv.ListenAndServe(NewMockReporter(0), transferChan)
+		close(transferChan)
+	}()
+	var lines int64
+	aggregated := make(chan struct{})
+	go func() {
+		defer close(aggregated)
+		p := &protocol.StatsDParser{}
+		for metric := range transferChan {
+			p.Aggregate(metric.Raw, metric.Addr)
+			atomic.AddInt64(&lines, 1)
+		}
+	}()
+
+	// Each client has its own source port, for the kernel to spread them
+	// over the sockets.
+	conns := make([]net.Conn, numClients)
+	for i := range conns {
+		conns[i], err = net.Dial("udp", addr)
+		require.NoError(b, err)
+		defer conns[i].Close()
+	}
+
+	b.ResetTimer()
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for i, conn := range conns {
+		wg.Add(1)
+		go func(i int, conn net.Conn) {
+			defer wg.Done()
+			for j := i; j < b.N; j += numClients {
+				conn.Write(packet)
+			}
+		}(i, conn)
+	}
+	wg.Wait()
+
+	// Wait for the packets in flight, the dropped ones never arriving.
+	want := int64(b.N * linesPerPacket)
+	last, lastChange := int64(-1), time.Now()
+	for {
+		n := atomic.LoadInt64(&lines)
+		if n == want || time.Since(lastChange) > 200*time.Millisecond {
+			break
+		}
+		if n != last {
+			last, lastChange = n, time.Now()
+		}
+		time.Sleep(time.Millisecond)
+	}
+	received := atomic.LoadInt64(&lines) / linesPerPacket
+	elapsed := time.Since(start)
+	if received < int64(b.N) {
+		// Don't count the time spent waiting for the dropped packets.
+		elapsed -= time.Since(lastChange)
+	}
+	b.StopTimer()
+
+	require.NoError(b, srv.Close())
+	<-served
+	<-aggregated
+	b.ReportMetric(float64(received)/elapsed.Seconds(), "packets/s")
+	b.ReportMetric(100*float64(int64(b.N)-received)/float64(b.N), "%dropped")
+}
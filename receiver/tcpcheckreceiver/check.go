@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcpcheckreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// target probes a single TCP endpoint.
+type target struct {
+	endpoint string
+	host     string
+	port     string
+}
+
+// checkResult holds the outcome of a single probe.
+type checkResult struct {
+	target   *target
+	err      error
+	duration time.Duration
+}
+
+func newTarget(config TargetConfig) (*target, error) {
+	host, port, err := net.SplitHostPort(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", config.Endpoint, err)
+	}
+	return &target{
+		endpoint: config.Endpoint,
+		host:     host,
+		port:     port,
+	}, nil
+}
+
+// check opens a connection to the target and closes it right away. Failing to
+// connect is reported in the result rather than as an error.
+func (t *target) check(ctx context.Context, timeout time.Duration) *checkResult {
+	result := &checkResult{
+		target: t,
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", t.endpoint)
+	result.duration = time.Since(start)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	conn.Close()
+	return result
+}
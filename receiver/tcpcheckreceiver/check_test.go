@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcpcheckreceiver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/testutil"
+)
+
+func TestTargetCheck(t *testing.T) {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	tgt, err := newTarget(TargetConfig{Endpoint: ln.Addr().String()})
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", tgt.host)
+
+	result := tgt.check(context.Background(), time.Second)
+	assert.NoError(t, result.err)
+	assert.True(t, result.duration > 0)
+}
+
+func TestTargetCheckUnreachable(t *testing.T) {
+	tgt, err := newTarget(TargetConfig{Endpoint: testutil.GetAvailableLocalAddress(t)})
+	require.NoError(t, err)
+
+	result := tgt.check(context.Background(), time.Second)
+	assert.Error(t, result.err)
+}
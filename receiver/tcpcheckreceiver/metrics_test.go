@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tcpcheckreceiver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	results := []*checkResult{
+		{
+			target:   &target{endpoint: "db:5432", host: "db", port: "5432"},
+			duration: 20 * time.Millisecond,
+		},
+		{
+			target:   &target{endpoint: "cache:6379", host: "cache", port: "6379"},
+			err:      errors.New("connection refused"),
+			duration: time.Millisecond,
+		},
+	}
+
+	metrics := buildMetrics(results, time.Now())
+	require.Len(t, metrics, 2)
+
+	values := func(m *metricspb.Metric) map[string]float64 {
+		result := make(map[string]float64)
+		for _, ts := range m.GetTimeseries() {
+			p := ts.GetPoints()[0]
+			key := ts.GetLabelValues()[0].GetValue() + ":" + ts.GetLabelValues()[1].GetValue()
+			result[key] = p.GetDoubleValue() + float64(p.GetInt64Value())
+		}
+		return result
+	}
+
+	assert.Equal(t, "tcpcheck.up", metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"db:5432": 1, "cache:6379": 0}, values(metrics[0]))
+	assert.Equal(t, "tcpcheck.duration", metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, map[string]float64{"db:5432": 0.02, "cache:6379": 0.001}, values(metrics[1]))
+}
@@ -0,0 +1,63 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for the Windows performance counters receiver.
+type Config struct {
+	configmodels.ReceiverSettings `mapstructure:",squash"`
+
+	// CollectionInterval is the interval at which the counters are collected.
+	CollectionInterval time.Duration `mapstructure:"collection_interval"`
+
+	// PerfCounters is the list of the performance objects to collect.
+	PerfCounters []PerfCounterConfig `mapstructure:"perfcounters"`
+}
+
+// PerfCounterConfig defines the counters collected from a performance object.
+type PerfCounterConfig struct {
+	// Object is the English name of the performance object, e.g. "Memory".
+	Object string `mapstructure:"object"`
+
+	// Instances are the instances of the object to collect, "*" collecting
+	// all of them. Objects without instances must leave it empty.
+	Instances []string `mapstructure:"instances"`
+
+	// InstanceLabel is the name of the label holding the instance of the
+	// object. Defaults to "instance".
+	InstanceLabel string `mapstructure:"instance_label"`
+
+	// Counters are the counters of the object to collect.
+	Counters []CounterConfig `mapstructure:"counters"`
+}
+
+// CounterConfig defines how a counter is reported.
+type CounterConfig struct {
+	// Name is the English name of the counter, e.g. "Committed Bytes".
+	Name string `mapstructure:"name"`
+
+	// Metric is the name of the metric, it defaults to the object and counter
+	// names, e.g. "memory.committed_bytes".
+	Metric string `mapstructure:"metric"`
+
+	// Unit and Description are the unit and description of the metric.
+	Unit        string `mapstructure:"unit"`
+	Description string `mapstructure:"description"`
+}
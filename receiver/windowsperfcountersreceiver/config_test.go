@@ -0,0 +1,127 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtest"
+)
+
+func TestLoadConfig(t *testing.T) {
+	factories, err := componenttest.ExampleComponents()
+	assert.Nil(t, err)
+
+	factory := NewFactory()
+	factories.Receivers[configmodels.Type(typeStr)] = factory
+	cfg, err := configtest.LoadConfigFile(
+		t, path.Join(".", "testdata", "config.yaml"), factories,
+	)
+
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+
+	assert.Equal(t, len(cfg.Receivers), 2)
+
+	r0 := cfg.Receivers["windowsperfcounters"]
+	assert.Equal(t, factory.CreateDefaultConfig(), r0)
+
+	r1 := cfg.Receivers["windowsperfcounters/custom"]
+	assert.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: configmodels.Type(typeStr),
+			NameVal: "windowsperfcounters/custom",
+		},
+		CollectionInterval: 30 * time.Second,
+		PerfCounters: []PerfCounterConfig{
+			{
+				Object: "Memory",
+				Counters: []CounterConfig{
+					{Name: "Committed Bytes", Metric: "memory.committed", Unit: "By", Description: "Committed virtual memory."},
+				},
+			},
+			{
+				Object:        "Processor",
+				Instances:     []string{"*"},
+				InstanceLabel: "cpu",
+				Counters:      []CounterConfig{{Name: "% Processor Time"}},
+			},
+		},
+	}, r1)
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name         string
+		perfCounters []PerfCounterConfig
+		wantErr      string
+	}{
+		{
+			name:    "no perfcounters",
+			wantErr: `no performance counters configured for receiver "windowsperfcounters"`,
+		},
+		{
+			name:         "no object",
+			perfCounters: []PerfCounterConfig{{Counters: []CounterConfig{{Name: "Committed Bytes"}}}},
+			wantErr:      `invalid performance counter 0 of receiver "windowsperfcounters": object must be set`,
+		},
+		{
+			name:         "no counters",
+			perfCounters: []PerfCounterConfig{{Object: "Memory"}},
+			wantErr:      `invalid performance counter 0 of receiver "windowsperfcounters": object "Memory": counters must be set`,
+		},
+		{
+			name:         "empty instance",
+			perfCounters: []PerfCounterConfig{{Object: "Processor", Instances: []string{""}, Counters: []CounterConfig{{Name: "% Processor Time"}}}},
+			wantErr:      `invalid performance counter 0 of receiver "windowsperfcounters": object "Processor": empty instance`,
+		},
+		{
+			name:         "no counter name",
+			perfCounters: []PerfCounterConfig{{Object: "Memory", Counters: []CounterConfig{{Metric: "memory.committed"}}}},
+			wantErr:      `invalid performance counter 0 of receiver "windowsperfcounters": object "Memory": counter name must be set`,
+		},
+		{
+			name:         "valid",
+			perfCounters: []PerfCounterConfig{{Object: "Memory", Counters: []CounterConfig{{Name: "Committed Bytes"}}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.PerfCounters = tt.perfCounters
+			err := validateConfig(cfg)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfigCollectionInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		cfg := createDefaultConfig().(*Config)
+		cfg.CollectionInterval = interval
+		cfg.PerfCounters = []PerfCounterConfig{{Object: "Memory", Counters: []CounterConfig{{Name: "Committed Bytes"}}}}
+		assert.EqualError(t, validateConfig(cfg), `collection_interval must be positive for receiver "windowsperfcounters"`)
+	}
+}
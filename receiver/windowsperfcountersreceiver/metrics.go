@@ -0,0 +1,103 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"strings"
+	"unicode"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/windows/pdh"
+)
+
+const defaultInstanceLabel = "instance"
+
+// counterMetric is a metric reported from the values of a counter, for each
+// of the configured instances of its object.
+type counterMetric struct {
+	descriptor *metricspb.MetricDescriptor
+	scrapers   []pdh.PerfCounterScraper
+	// instances are the configured instances of the scrapers.
+	instances []string
+}
+
+func newCounterMetric(pc PerfCounterConfig, counter CounterConfig) *counterMetric {
+	name := counter.Metric
+	if name == "" {
+		name = defaultMetricName(pc.Object, counter.Name)
+	}
+
+	descriptor := &metricspb.MetricDescriptor{
+		Name:        name,
+		Description: counter.Description,
+		Unit:        counter.Unit,
+		Type:        metricspb.MetricDescriptor_GAUGE_DOUBLE,
+	}
+	if len(pc.Instances) > 0 {
+		label := pc.InstanceLabel
+		if label == "" {
+			label = defaultInstanceLabel
+		}
+		descriptor.LabelKeys = []*metricspb.LabelKey{{Key: label}}
+	}
+	return &counterMetric{descriptor: descriptor}
+}
+
+// defaultMetricName builds the name of a metric from the object and counter
+// names, e.g. "Memory" and "% Committed Bytes In Use" become
+// "memory.committed_bytes_in_use".
+func defaultMetricName(object, counter string) string {
+	return sanitize(object) + "." + sanitize(counter)
+}
+
+func sanitize(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, "_")
+}
+
+// buildMetric returns the metric from the values scraped from each instance,
+// nil if no value was scraped.
+func (m *counterMetric) buildMetric(values [][]pdh.CounterValue, now *timestamppb.Timestamp) *metricspb.Metric {
+	var timeseries []*metricspb.TimeSeries
+	for i, instanceValues := range values {
+		for _, value := range instanceValues {
+			ts := &metricspb.TimeSeries{
+				Points: []*metricspb.Point{
+					{Timestamp: now, Value: &metricspb.Point_DoubleValue{DoubleValue: value.Value}},
+				},
+			}
+			if len(m.descriptor.LabelKeys) > 0 {
+				instance := value.InstanceName
+				if instance == "" {
+					instance = m.instances[i]
+				}
+				ts.LabelValues = []*metricspb.LabelValue{{Value: instance, HasValue: true}}
+			}
+			timeseries = append(timeseries, ts)
+		}
+	}
+
+	if len(timeseries) == 0 {
+		return nil
+	}
+	return &metricspb.Metric{
+		MetricDescriptor: m.descriptor,
+		Timeseries:       timeseries,
+	}
+}
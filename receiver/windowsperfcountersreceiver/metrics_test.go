@@ -0,0 +1,64 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/windows/pdh"
+)
+
+func TestDefaultMetricName(t *testing.T) {
+	assert.Equal(t, "memory.committed_bytes_in_use", defaultMetricName("Memory", "% Committed Bytes In Use"))
+	assert.Equal(t, "web_service.bytes_sent_sec", defaultMetricName("Web Service", "Bytes Sent/sec"))
+}
+
+func TestCounterMetric(t *testing.T) {
+	now := timestamppb.Now()
+
+	m := newCounterMetric(PerfCounterConfig{Object: "Memory"}, CounterConfig{Name: "Committed Bytes", Unit: "By"})
+	assert.Equal(t, "memory.committed_bytes", m.descriptor.Name)
+	assert.Equal(t, "By", m.descriptor.Unit)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, m.descriptor.Type)
+	assert.Nil(t, m.descriptor.LabelKeys)
+	assert.Nil(t, m.buildMetric([][]pdh.CounterValue{nil}, now))
+
+	metric := m.buildMetric([][]pdh.CounterValue{{{Value: 42}}}, now)
+	require.NotNil(t, metric)
+	require.Len(t, metric.Timeseries, 1)
+	assert.Nil(t, metric.Timeseries[0].LabelValues)
+	assert.Equal(t, float64(42), metric.Timeseries[0].Points[0].GetDoubleValue())
+
+	m = newCounterMetric(
+		PerfCounterConfig{Object: "Processor", Instances: []string{"*", "1"}},
+		CounterConfig{Name: "% Processor Time", Metric: "cpu.time"},
+	)
+	m.instances = []string{"*", "1"}
+	assert.Equal(t, "cpu.time", m.descriptor.Name)
+	assert.Equal(t, []*metricspb.LabelKey{{Key: "instance"}}, m.descriptor.LabelKeys)
+
+	metric = m.buildMetric([][]pdh.CounterValue{{{InstanceName: "0", Value: 1}, {InstanceName: "1", Value: 2}}, {{Value: 3}}}, now)
+	require.NotNil(t, metric)
+	require.Len(t, metric.Timeseries, 3)
+	assert.Equal(t, "0", metric.Timeseries[0].LabelValues[0].Value)
+	assert.Equal(t, "1", metric.Timeseries[1].LabelValues[0].Value)
+	assert.Equal(t, "1", metric.Timeseries[2].LabelValues[0].Value)
+	assert.Equal(t, float64(3), metric.Timeseries[2].Points[0].GetDoubleValue())
+}
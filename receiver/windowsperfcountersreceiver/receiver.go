@@ -0,0 +1,189 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/windows/pdh"
+)
+
+const (
+	dataFormat = "perfcounters"
+	transport  = "pdh"
+)
+
+// newPerfCounter creates the performance counters, it is replaced in tests.
+var newPerfCounter = pdh.NewPerfCounter
+
+var _ component.MetricsReceiver = (*perfCountersReceiver)(nil)
+
+// perfCountersReceiver implements the component.MetricsReceiver for Windows
+// performance counters.
+type perfCountersReceiver struct {
+	logger       *zap.Logger
+	config       *Config
+	nextConsumer consumer.MetricsConsumer
+	metrics      []*counterMetric
+	cancel       context.CancelFunc
+	done         chan struct{}
+}
+
+// NewMetricsReceiver creates a receiver collecting the performance counters
+// of the configuration. It is exported so that receivers monitoring a
+// specific workload can be built from a preset configuration, the type of
+// the configuration being used to report the receiver observability.
+func NewMetricsReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.MetricsConsumer,
+) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if err := validateConfig(config); err != nil {
+		return nil, err
+	}
+
+	r := &perfCountersReceiver{
+		logger:       logger,
+		config:       config,
+		nextConsumer: nextConsumer,
+	}
+	return r, nil
+}
+
+// Start creates the performance counters and begins collecting them.
+func (r *perfCountersReceiver) Start(ctx context.Context, host component.Host) error {
+	if err := r.createCounters(); err != nil {
+		return err
+	}
+
+	ctx, r.cancel = context.WithCancel(obsreport.ReceiverContext(context.Background(), string(r.config.Type()), transport, r.config.Name()))
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.config.CollectionInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := r.collect(ctx); err != nil {
+					r.logger.Error("Failed to collect performance counters", zap.Error(err))
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the receiver and releases the performance counters.
+func (r *perfCountersReceiver) Shutdown(context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+		<-r.done
+	}
+	return r.closeCounters()
+}
+
+func (r *perfCountersReceiver) createCounters() error {
+	for _, pc := range r.config.PerfCounters {
+		instances := pc.Instances
+		if len(instances) == 0 {
+			instances = []string{""}
+		}
+
+		for _, counter := range pc.Counters {
+			metric := newCounterMetric(pc, counter)
+			r.metrics = append(r.metrics, metric)
+			for _, instance := range instances {
+				path := pdh.CounterPath(pc.Object, instance, counter.Name)
+				scraper, err := newPerfCounter(path)
+				if err != nil {
+					r.closeCounters()
+					return fmt.Errorf("failed to create performance counter %q for receiver %q: %w", path, r.config.Name(), err)
+				}
+				metric.scrapers = append(metric.scrapers, scraper)
+				metric.instances = append(metric.instances, instance)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *perfCountersReceiver) closeCounters() error {
+	var errs []error
+	for _, metric := range r.metrics {
+		for _, scraper := range metric.scrapers {
+			if err := scraper.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	r.metrics = nil
+	return componenterror.CombineErrors(errs)
+}
+
+func (r *perfCountersReceiver) collect(ctx context.Context) error {
+	ctx = obsreport.StartMetricsReceiveOp(ctx, dataFormat, transport)
+
+	now := timestamppb.Now()
+	var (
+		metrics []*metricspb.Metric
+		errs    []error
+	)
+	for _, metric := range r.metrics {
+		values := make([][]pdh.CounterValue, len(metric.scrapers))
+		for i, scraper := range metric.scrapers {
+			var err error
+			if values[i], err = scraper.ScrapeData(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to scrape %q: %w", scraper.Path(), err))
+			}
+		}
+		if m := metric.buildMetric(values, now); m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	// The counters that could be scraped are reported regardless of the
+	// failures of the others.
+	scrapeErr := componenterror.CombineErrors(errs)
+
+	md := consumerdata.MetricsData{Metrics: metrics}
+	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
+	var err error
+	if len(metrics) > 0 {
+		err = r.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	}
+	if err == nil {
+		err = scrapeErr
+	}
+	obsreport.EndMetricsReceiveOp(ctx, dataFormat, numPoints, numTimeSeries, err)
+	return err
+}
@@ -0,0 +1,163 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/windows/pdh"
+)
+
+type fakePerfCounter struct {
+	path   string
+	values []pdh.CounterValue
+	err    error
+	closed bool
+}
+
+func (c *fakePerfCounter) Path() string {
+	return c.path
+}
+
+func (c *fakePerfCounter) ScrapeData() ([]pdh.CounterValue, error) {
+	return c.values, c.err
+}
+
+func (c *fakePerfCounter) Close() error {
+	c.closed = true
+	return nil
+}
+
+// useFakePerfCounters replaces the performance counters by fakes for the
+// duration of the test, the counters of unknown paths failing to be created.
+func useFakePerfCounters(t *testing.T, counters ...*fakePerfCounter) {
+	byPath := make(map[string]*fakePerfCounter)
+	for _, c := range counters {
+		byPath[c.path] = c
+	}
+	newPerfCounter = func(path string) (pdh.PerfCounterScraper, error) {
+		if c, ok := byPath[path]; ok {
+			return c, nil
+		}
+		return nil, errors.New("counter not found")
+	}
+	t.Cleanup(func() { newPerfCounter = pdh.NewPerfCounter })
+}
+
+func newTestConfig() *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.PerfCounters = []PerfCounterConfig{
+		{
+			Object:   "Memory",
+			Counters: []CounterConfig{{Name: "Committed Bytes"}, {Name: "Available Bytes"}},
+		},
+		{
+			Object:        "Processor",
+			Instances:     []string{"*"},
+			InstanceLabel: "cpu",
+			Counters:      []CounterConfig{{Name: "% Processor Time", Metric: "cpu.time"}},
+		},
+	}
+	return cfg
+}
+
+func TestReceiverCollect(t *testing.T) {
+	committed := &fakePerfCounter{path: `\Memory\Committed Bytes`, values: []pdh.CounterValue{{Value: 1024}}}
+	available := &fakePerfCounter{path: `\Memory\Available Bytes`, err: errors.New("no data")}
+	processor := &fakePerfCounter{path: `\Processor(*)\% Processor Time`, values: []pdh.CounterValue{{InstanceName: "0", Value: 12.5}, {InstanceName: "1", Value: 50}}}
+	useFakePerfCounters(t, committed, available, processor)
+
+	sink := new(exportertest.SinkMetricsExporter)
+	rcv, err := NewMetricsReceiver(zap.NewNop(), newTestConfig(), sink)
+	require.NoError(t, err)
+	r := rcv.(*perfCountersReceiver)
+	require.NoError(t, r.createCounters())
+
+	err = r.collect(context.Background())
+	assert.EqualError(t, err, `failed to scrape "\\Memory\\Available Bytes": no data`)
+
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	ocmds := internaldata.MetricsToOC(mds[0])
+	require.Len(t, ocmds, 1)
+
+	metrics := ocmds[0].Metrics
+	require.Len(t, metrics, 2)
+	assert.Equal(t, "memory.committed_bytes", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, float64(1024), metrics[0].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, "cpu.time", metrics[1].MetricDescriptor.Name)
+	assert.Equal(t, "cpu", metrics[1].MetricDescriptor.LabelKeys[0].Key)
+	require.Len(t, metrics[1].Timeseries, 2)
+	assert.Equal(t, "1", metrics[1].Timeseries[1].LabelValues[0].Value)
+	assert.Equal(t, float64(50), metrics[1].Timeseries[1].Points[0].GetDoubleValue())
+
+	require.NoError(t, r.Shutdown(context.Background()))
+	assert.True(t, committed.closed)
+	assert.True(t, processor.closed)
+}
+
+func TestReceiverStartError(t *testing.T) {
+	committed := &fakePerfCounter{path: `\Memory\Committed Bytes`}
+	useFakePerfCounters(t, committed)
+
+	r, err := NewMetricsReceiver(zap.NewNop(), newTestConfig(), exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+
+	err = r.Start(context.Background(), componenttest.NewNopHost())
+	assert.EqualError(t, err, `failed to create performance counter "\\Memory\\Available Bytes" for receiver "windowsperfcounters": counter not found`)
+	assert.True(t, committed.closed)
+	assert.NoError(t, r.Shutdown(context.Background()))
+}
+
+func TestReceiverStartUnsupported(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("performance counters are supported on Windows")
+	}
+
+	r, err := NewMetricsReceiver(zap.NewNop(), newTestConfig(), exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	assert.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
+
+func TestReceiverStartAndShutdown(t *testing.T) {
+	useFakePerfCounters(t,
+		&fakePerfCounter{path: `\Memory\Committed Bytes`, values: []pdh.CounterValue{{Value: 1024}}},
+		&fakePerfCounter{path: `\Memory\Available Bytes`, values: []pdh.CounterValue{{Value: 2048}}},
+		&fakePerfCounter{path: `\Processor(*)\% Processor Time`, values: []pdh.CounterValue{{InstanceName: "0", Value: 12.5}}},
+	)
+
+	cfg := newTestConfig()
+	cfg.CollectionInterval = 10 * time.Millisecond
+	sink := new(exportertest.SinkMetricsExporter)
+	r, err := NewMetricsReceiver(zap.NewNop(), cfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return len(sink.AllMetrics()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, r.Shutdown(context.Background()))
+}
@@ -0,0 +1,57 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package windowsperfcountersreceiver
+
+import (
+	"fmt"
+)
+
+// validateConfig checks the configuration of the receiver.
+func validateConfig(config *Config) error {
+	if config.CollectionInterval <= 0 {
+		return fmt.Errorf("collection_interval must be positive for receiver %q", config.Name())
+	}
+
+	if len(config.PerfCounters) == 0 {
+		return fmt.Errorf("no performance counters configured for receiver %q", config.Name())
+	}
+
+	for i, pc := range config.PerfCounters {
+		if err := validatePerfCounter(pc); err != nil {
+			return fmt.Errorf("invalid performance counter %d of receiver %q: %w", i, config.Name(), err)
+		}
+	}
+	return nil
+}
+
+func validatePerfCounter(pc PerfCounterConfig) error {
+	if pc.Object == "" {
+		return fmt.Errorf("object must be set")
+	}
+	if len(pc.Counters) == 0 {
+		return fmt.Errorf("object %q: counters must be set", pc.Object)
+	}
+	for _, instance := range pc.Instances {
+		if instance == "" {
+			return fmt.Errorf("object %q: empty instance", pc.Object)
+		}
+	}
+	for _, counter := range pc.Counters {
+		if counter.Name == "" {
+			return fmt.Errorf("object %q: counter name must be set", pc.Object)
+		}
+	}
+	return nil
+}